@@ -19,7 +19,6 @@ import (
 	"net"
 	"time"
 
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	"k8s.io/klog"
 
@@ -28,9 +27,13 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/cniserver"
 	_ "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/ipam"
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/egress"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/externalip"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/multicluster"
 	"github.com/vmware-tanzu/antrea/pkg/agent/controller/networkpolicy"
 	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
 	"github.com/vmware-tanzu/antrea/pkg/agent/controller/traceflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/flowrecords"
@@ -50,6 +53,7 @@ import (
 	ofconfig "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
 	"github.com/vmware-tanzu/antrea/pkg/signals"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
 	"github.com/vmware-tanzu/antrea/pkg/version"
 )
 
@@ -94,7 +98,10 @@ func run(o *Options) error {
 	ovsBridgeMgmtAddr := ofconfig.GetMgmtAddress(o.config.OVSRunDir, o.config.OVSBridge)
 	ofClient := openflow.NewClient(o.config.OVSBridge, ovsBridgeMgmtAddr,
 		features.DefaultFeatureGate.Enabled(features.AntreaProxy),
-		features.DefaultFeatureGate.Enabled(features.AntreaPolicy))
+		features.DefaultFeatureGate.Enabled(features.AntreaPolicy),
+		features.DefaultFeatureGate.Enabled(features.Egress),
+		features.DefaultFeatureGate.Enabled(features.FlowExporter),
+		agent.OVSMetersAreSupported(o.config.OVSDatapathType))
 
 	// statsCollector collects stats and reports to the antrea-controller periodically. For now it's only used for
 	// NetworkPolicy stats.
@@ -110,7 +117,10 @@ func run(o *Options) error {
 		TrafficEncapMode:  encapMode,
 		EnableIPSecTunnel: o.config.EnableIPSecTunnel}
 
-	routeClient, err := route.NewClient(serviceCIDRNet, encapMode)
+	routeClient, err := route.NewClient(serviceCIDRNet, encapMode,
+		features.DefaultFeatureGate.Enabled(features.AntreaProxyNodePort),
+		features.DefaultFeatureGate.Enabled(features.AntreaProxyHostNetwork),
+		features.DefaultFeatureGate.Enabled(features.AntreaProxyLoadBalancerDSR))
 	if err != nil {
 		return fmt.Errorf("error creating route client: %v", err)
 	}
@@ -173,6 +183,47 @@ func run(o *Options) error {
 		nodeConfig.Name,
 		podUpdates,
 		features.DefaultFeatureGate.Enabled(features.AntreaPolicy))
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		// Register the handler of the packets sent to the controller by
+		// the rules with the Reject action.
+		ofClient.RegisterPacketInHandler(uint8(openflow.PacketInReasonNP), "networkpolicy", networkPolicyController)
+	}
+
+	var egressController *egress.Controller
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		egressController = egress.NewEgressController(
+			ofClient,
+			routeClient,
+			ifaceStore,
+			nodeConfig.Name,
+			informerFactory,
+			crdInformerFactory.Core().V1alpha2().Egresses())
+	}
+
+	var externalIPController *externalip.Controller
+	if features.DefaultFeatureGate.Enabled(features.ServiceExternalIP) {
+		externalIPController, err = externalip.NewExternalIPController(
+			nodeConfig.Name,
+			nodeConfig.NodeIPAddr.IP,
+			informerFactory,
+			crdInformerFactory.Core().V1alpha2().ExternalIPPools())
+		if err != nil {
+			return fmt.Errorf("error creating external IP controller: %v", err)
+		}
+	}
+
+	var multiclusterController *multicluster.Controller
+	if features.DefaultFeatureGate.Enabled(features.Multicluster) {
+		multiclusterController = multicluster.NewMulticlusterController(
+			nodeConfig,
+			networkConfig,
+			env.GetPodNamespace(),
+			ofClient,
+			ovsBridgeClient,
+			ifaceStore,
+			informerFactory,
+			crdInformerFactory.Multicluster().V1alpha1().ClusterInfos())
+	}
 
 	isChaining := false
 	if networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
@@ -180,7 +231,11 @@ func run(o *Options) error {
 	}
 	var proxier proxy.Proxier
 	if features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
-		proxier = proxy.New(nodeConfig.Name, informerFactory, ofClient)
+		proxier = proxy.New(nodeConfig.Name, informerFactory, ofClient, routeClient,
+			features.DefaultFeatureGate.Enabled(features.AntreaProxyNodePort),
+			features.DefaultFeatureGate.Enabled(features.EndpointSlice),
+			features.DefaultFeatureGate.Enabled(features.AntreaProxyHostNetwork),
+			features.DefaultFeatureGate.Enabled(features.AntreaProxyLoadBalancerDSR))
 	}
 	cniServer := cniserver.New(
 		o.config.CNISocket,
@@ -220,12 +275,26 @@ func run(o *Options) error {
 
 	go nodeRouteController.Run(stopCh)
 
+	go routeClient.Run(stopCh)
+
 	go networkPolicyController.Run(stopCh)
 
 	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
 		go statsCollector.Run(stopCh)
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		go egressController.Run(stopCh)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.ServiceExternalIP) {
+		go externalIPController.Run(stopCh)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.Multicluster) {
+		go multiclusterController.Run(stopCh)
+	}
+
 	if features.DefaultFeatureGate.Enabled(features.Traceflow) {
 		go traceflowController.Run(stopCh)
 	}
@@ -258,7 +327,16 @@ func run(o *Options) error {
 	}
 	go apiServer.Run(stopCh)
 
-	if features.DefaultFeatureGate.Enabled(features.Traceflow) {
+	var denyConnStore *connections.DenyConnectionStore
+	if features.DefaultFeatureGate.Enabled(features.FlowExporter) {
+		// Register the handler of the packets sent to the controller by
+		// the flows dropping packets, to record the denied connections.
+		denyConnStore = connections.NewDenyConnectionStore(ifaceStore, networkPolicyController)
+		ofClient.RegisterPacketInHandler(uint8(openflow.PacketInReasonNP), "denyconnections", denyConnStore)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.Traceflow) || features.DefaultFeatureGate.Enabled(features.AntreaPolicy) ||
+		features.DefaultFeatureGate.Enabled(features.FlowExporter) {
 		go ofClient.StartPacketInHandler(stopCh)
 	}
 
@@ -269,14 +347,26 @@ func run(o *Options) error {
 			ifaceStore,
 			serviceCIDRNet,
 			proxier,
+			networkPolicyController,
 			o.pollInterval)
+		var connEnded <-chan flowexporter.ConnectionKey
+		if o.config.EnableFlowConntrackEvents {
+			connEnded, err = connStore.EnableConnTrackEvents()
+			if err != nil {
+				return fmt.Errorf("error when enabling conntrack events: %v", err)
+			}
+		}
 		pollDone := make(chan struct{})
 		go connStore.Run(stopCh, pollDone)
 
 		flowExporter := exporter.NewFlowExporter(
 			flowrecords.NewFlowRecords(connStore),
+			denyConnStore,
 			o.config.FlowExportFrequency)
-		go wait.Until(func() { flowExporter.Export(o.flowCollector, stopCh, pollDone) }, 0, stopCh)
+		for _, sink := range o.flowExportSinks {
+			flowExporter.AddSink(sink.sink, sink.bufferSize)
+		}
+		go flowExporter.Export(stopCh, pollDone, connEnded)
 	}
 
 	<-stopCh
@@ -92,6 +92,9 @@ type AgentConfig struct {
 	// is given, we consider tcp as default.
 	// Defaults to "".
 	FlowCollectorAddr string `yaml:"flowCollectorAddr,omitempty"`
+	// Provide the sinks the flow exporter exports flow records to, in addition to the flow collector given by
+	// FlowCollectorAddr. At least one of them must be provided when the flow exporter is enabled.
+	FlowExportSinks []FlowExportSinkConfig `yaml:"flowExportSinks,omitempty"`
 	// Provide flow poll interval in format "0s". This determines how often flow exporter dumps connections in conntrack module.
 	// Flow poll interval should be greater than or equal to 1s(one second).
 	// Defaults to "5s". Follow the time units of duration.
@@ -101,4 +104,39 @@ type AgentConfig struct {
 	// Flow export frequency should be greater than or equal to 1.
 	// Defaults to "12".
 	FlowExportFrequency uint `yaml:"flowExportFrequency,omitempty"`
+	// Enable subscribing to the NEW, UPDATE and DESTROY conntrack events to maintain the connections of the flow
+	// exporter incrementally, instead of adding and deleting them by polling. Polling only refreshes the counters of
+	// the connections, and the flow records of the connections are exported as soon as they are destroyed in conntrack.
+	// It's only supported with the OVS system datapath on Linux.
+	// Defaults to false.
+	EnableFlowConntrackEvents bool `yaml:"enableFlowConntrackEvents,omitempty"`
+}
+
+// FlowExportSinkConfig is the configuration of a sink the flow exporter exports flow records to.
+type FlowExportSinkConfig struct {
+	// Type of the sink: "ipfix", "json" or "kafka". The "json" sink writes newline-delimited JSON documents, and the
+	// "kafka" sink produces JSON documents to a Kafka topic.
+	Type string `yaml:"type"`
+	// Transport of the sink. It is "tcp", "udp" or "tls" for "ipfix", and defaults to "tcp". It is "file", "tcp",
+	// "udp" or "unix" for "json", and defaults to "file". It is "tcp" or "tls" for "kafka", and defaults to "tcp".
+	Transport string `yaml:"transport,omitempty"`
+	// Address of the sink. It is <IP>:<port> of the collector for "ipfix". It is the path of the file or of the Unix
+	// socket, or <IP>:<port> of the TCP or UDP socket for "json". It is a comma-separated list of <host>:<port> of the
+	// bootstrap brokers for "kafka".
+	Address string `yaml:"address"`
+	// Topic the flow records are produced to. It must be provided for "kafka".
+	Topic string `yaml:"topic,omitempty"`
+	// Path of the CA certificate used to verify the certificate of the server over TLS. If it is not provided, the
+	// CA certificates of the host are used.
+	CACertFile string `yaml:"caCertFile,omitempty"`
+	// Paths of the client certificate and key presented to the server over TLS. Both or none of them must be provided.
+	ClientCertFile string `yaml:"clientCertFile,omitempty"`
+	ClientKeyFile  string `yaml:"clientKeyFile,omitempty"`
+	// Name used to verify the certificate of the server over TLS. Defaults to the host of the address.
+	ServerName string `yaml:"serverName,omitempty"`
+	// Skip the verification of the certificate of the server over TLS. It should only be used for testing.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty"`
+	// Number of flow records buffered for the sink while it is unavailable. The flow records exceeding it are dropped.
+	// Defaults to 10000.
+	BufferSize int `yaml:"bufferSize,omitempty"`
 }
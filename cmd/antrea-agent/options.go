@@ -15,9 +15,12 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"net"
+	"runtime"
 	"strings"
 	"time"
 
@@ -25,6 +28,8 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
 	"github.com/vmware-tanzu/antrea/pkg/apis"
 	"github.com/vmware-tanzu/antrea/pkg/cni"
 	"github.com/vmware-tanzu/antrea/pkg/features"
@@ -48,10 +53,18 @@ type Options struct {
 	config *AgentConfig
 	// IPFIX flow collector
 	flowCollector net.Addr
+	// Sinks the flow exporter exports flow records to
+	flowExportSinks []flowExportSink
 	// Flow exporter poll interval
 	pollInterval time.Duration
 }
 
+// flowExportSink is a sink the flow exporter exports flow records to, with the number of flow records buffered for it.
+type flowExportSink struct {
+	sink       flowexporter.Sink
+	bufferSize int
+}
+
 func newOptions() *Options {
 	return &Options{
 		config: new(AgentConfig),
@@ -82,7 +95,7 @@ func (o *Options) validate(args []string) error {
 		return fmt.Errorf("no positional arguments are supported")
 	}
 	// Validate service CIDR configuration
-	_, _, err := net.ParseCIDR(o.config.ServiceCIDR)
+	_, serviceCIDR, err := net.ParseCIDR(o.config.ServiceCIDR)
 	if err != nil {
 		return fmt.Errorf("service CIDR %s is invalid", o.config.ServiceCIDR)
 	}
@@ -108,6 +121,65 @@ func (o *Options) validate(args []string) error {
 			return fmt.Errorf("IPSec tunnel may only be enabled on %s mode", config.TrafficEncapModeEncap)
 		}
 	}
+	if features.DefaultFeatureGate.Enabled(features.Egress) && !encapMode.SupportsEncap() {
+		return fmt.Errorf("Egress requires the tunnel and is not supported in %s mode", o.config.TrafficEncapMode)
+	}
+	if features.DefaultFeatureGate.Enabled(features.AntreaProxyNodePort) {
+		if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+			return fmt.Errorf("AntreaProxyNodePort requires AntreaProxy to be enabled")
+		}
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("AntreaProxyNodePort is not supported on Windows")
+		}
+		if encapMode.IsNetworkPolicyOnly() {
+			return fmt.Errorf("AntreaProxyNodePort is not supported in %s mode", o.config.TrafficEncapMode)
+		}
+	}
+	if features.DefaultFeatureGate.Enabled(features.AntreaProxyHostNetwork) {
+		if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+			return fmt.Errorf("AntreaProxyHostNetwork requires AntreaProxy to be enabled")
+		}
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("AntreaProxyHostNetwork is not supported on Windows")
+		}
+		// The ClusterIP traffic from the host network relies on the tunnel to reach remote Endpoints.
+		if encapMode != config.TrafficEncapModeEncap {
+			return fmt.Errorf("AntreaProxyHostNetwork is not supported in %s mode", o.config.TrafficEncapMode)
+		}
+		if serviceCIDR.IP.To4() == nil {
+			return fmt.Errorf("AntreaProxyHostNetwork only supports an IPv4 Service CIDR")
+		}
+	}
+	if features.DefaultFeatureGate.Enabled(features.AntreaProxyLoadBalancerDSR) {
+		if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+			return fmt.Errorf("AntreaProxyLoadBalancerDSR requires AntreaProxy to be enabled")
+		}
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("AntreaProxyLoadBalancerDSR is not supported on Windows")
+		}
+		// The DSR traffic relies on the Geneve options to carry the ingress IPs and the selected Endpoints.
+		if encapMode != config.TrafficEncapModeEncap {
+			return fmt.Errorf("AntreaProxyLoadBalancerDSR is not supported in %s mode", o.config.TrafficEncapMode)
+		}
+		if o.config.TunnelType != ovsconfig.GeneveTunnel {
+			return fmt.Errorf("AntreaProxyLoadBalancerDSR requires the %s tunnel type", ovsconfig.GeneveTunnel)
+		}
+	}
+	if features.DefaultFeatureGate.Enabled(features.ServiceExternalIP) && runtime.GOOS == "windows" {
+		return fmt.Errorf("ServiceExternalIP is not supported on Windows")
+	}
+	if features.DefaultFeatureGate.Enabled(features.Multicluster) {
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("Multicluster is not supported on Windows")
+		}
+		// The traffic to the other clusters is sent to the gateway Node over the tunnel.
+		if encapMode != config.TrafficEncapModeEncap {
+			return fmt.Errorf("Multicluster is not supported in %s mode", o.config.TrafficEncapMode)
+		}
+	}
+	if features.DefaultFeatureGate.Enabled(features.EndpointSlice) && !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+		return fmt.Errorf("EndpointSlice requires AntreaProxy to be enabled")
+	}
 	if err := o.validateFlowExporterConfig(); err != nil {
 		return fmt.Errorf("Failed to validate flow exporter config: %v", err)
 	}
@@ -173,9 +245,9 @@ func (o *Options) setDefaults() {
 
 func (o *Options) validateFlowExporterConfig() error {
 	if features.DefaultFeatureGate.Enabled(features.FlowExporter) {
-		if o.config.FlowCollectorAddr == "" {
-			return fmt.Errorf("IPFIX flow collector address should be provided")
-		} else {
+		if o.config.FlowCollectorAddr == "" && len(o.config.FlowExportSinks) == 0 {
+			return fmt.Errorf("IPFIX flow collector address or flow export sinks should be provided")
+		} else if o.config.FlowCollectorAddr != "" {
 			// Check if it is TCP or UDP
 			strSlice := strings.Split(o.config.FlowCollectorAddr, ":")
 			var proto string
@@ -208,6 +280,22 @@ func (o *Options) validateFlowExporterConfig() error {
 					return fmt.Errorf("IPFIX flow collector over TCP proto cannot be resolved: %v", err)
 				}
 			}
+			o.flowExportSinks = append(o.flowExportSinks, flowExportSink{
+				sink:       exporter.NewIPFIXSink(o.flowCollector, nil),
+				bufferSize: exporter.DefaultSinkBufferSize,
+			})
+		}
+		for i, sinkConfig := range o.config.FlowExportSinks {
+			sink, err := newFlowExportSink(sinkConfig)
+			if err != nil {
+				return fmt.Errorf("flow export sink %d is invalid: %v", i, err)
+			}
+			if sinkConfig.BufferSize < 0 {
+				return fmt.Errorf("flow export sink %d is invalid: buffer size should not be negative", i)
+			} else if sinkConfig.BufferSize == 0 {
+				sinkConfig.BufferSize = exporter.DefaultSinkBufferSize
+			}
+			o.flowExportSinks = append(o.flowExportSinks, flowExportSink{sink: sink, bufferSize: sinkConfig.BufferSize})
 		}
 		if o.config.FlowPollInterval != "" {
 			var err error
@@ -219,6 +307,99 @@ func (o *Options) validateFlowExporterConfig() error {
 				return fmt.Errorf("FlowPollInterval should be greater than or equal to one second")
 			}
 		}
+		if o.config.EnableFlowConntrackEvents && o.config.OVSDatapathType != ovsconfig.OVSDatapathSystem {
+			return fmt.Errorf("conntrack events are only supported with the OVS system datapath")
+		}
 	}
 	return nil
 }
+
+// newFlowExportSink validates the configuration of the flow export sink and returns the sink.
+func newFlowExportSink(sinkConfig FlowExportSinkConfig) (flowexporter.Sink, error) {
+	if sinkConfig.Address == "" {
+		return nil, fmt.Errorf("address should be provided")
+	}
+	var tlsConfig *tls.Config
+	if sinkConfig.Transport == "tls" {
+		var err error
+		if tlsConfig, err = newFlowExportTLSConfig(sinkConfig); err != nil {
+			return nil, err
+		}
+	}
+	switch sinkConfig.Type {
+	case "ipfix":
+		var addr net.Addr
+		var err error
+		switch sinkConfig.Transport {
+		case "", "tcp", "tls":
+			addr, err = net.ResolveTCPAddr("tcp", sinkConfig.Address)
+		case "udp":
+			addr, err = net.ResolveUDPAddr("udp", sinkConfig.Address)
+		default:
+			return nil, fmt.Errorf("IPFIX over %s transport is not supported", sinkConfig.Transport)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("IPFIX collector address cannot be resolved: %v", err)
+		}
+		return exporter.NewIPFIXSink(addr, tlsConfig), nil
+	case "json":
+		network := sinkConfig.Transport
+		switch network {
+		case "":
+			network = exporter.JSONFileNetwork
+		case exporter.JSONFileNetwork, "unix":
+		case "tcp", "udp":
+			if _, _, err := net.SplitHostPort(sinkConfig.Address); err != nil {
+				return nil, fmt.Errorf("JSON socket address is given in invalid format: %v", err)
+			}
+		default:
+			return nil, fmt.Errorf("JSON over %s transport is not supported", network)
+		}
+		return exporter.NewJSONSink(network, sinkConfig.Address), nil
+	case "kafka":
+		if sinkConfig.Transport != "" && sinkConfig.Transport != "tcp" && sinkConfig.Transport != "tls" {
+			return nil, fmt.Errorf("Kafka over %s transport is not supported", sinkConfig.Transport)
+		}
+		if sinkConfig.Topic == "" {
+			return nil, fmt.Errorf("Kafka topic should be provided")
+		}
+		brokers := strings.Split(sinkConfig.Address, ",")
+		for i := range brokers {
+			brokers[i] = strings.TrimSpace(brokers[i])
+			if _, _, err := net.SplitHostPort(brokers[i]); err != nil {
+				return nil, fmt.Errorf("Kafka broker address is given in invalid format: %v", err)
+			}
+		}
+		return exporter.NewKafkaSink(brokers, sinkConfig.Topic, tlsConfig), nil
+	default:
+		return nil, fmt.Errorf("sink type %q is not supported", sinkConfig.Type)
+	}
+}
+
+func newFlowExportTLSConfig(sinkConfig FlowExportSinkConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         sinkConfig.ServerName,
+		InsecureSkipVerify: sinkConfig.InsecureSkipVerify,
+	}
+	if sinkConfig.CACertFile != "" {
+		caCert, err := ioutil.ReadFile(sinkConfig.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading CA certificate: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid certificate found in CA certificate file %s", sinkConfig.CACertFile)
+		}
+	}
+	if (sinkConfig.ClientCertFile == "") != (sinkConfig.ClientKeyFile == "") {
+		return nil, fmt.Errorf("both or none of client certificate and key should be provided")
+	}
+	if sinkConfig.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(sinkConfig.ClientCertFile, sinkConfig.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error when loading client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -5,8 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
 	"github.com/vmware-tanzu/antrea/pkg/features"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
 )
 
 func TestOptions_validateFlowExporterConfig(t *testing.T) {
@@ -51,3 +54,111 @@ func TestOptions_validateFlowExporterConfig(t *testing.T) {
 	}
 
 }
+
+func TestOptions_validateFlowExportSinks(t *testing.T) {
+	features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{"FlowExporter": true})
+	testcases := []struct {
+		name      string
+		collector string
+		sinks     []FlowExportSinkConfig
+		expSinks  []string
+		expError  bool
+	}{
+		{
+			name:      "collector and sinks",
+			collector: "192.168.1.100:2002:udp",
+			sinks: []FlowExportSinkConfig{
+				{Type: "ipfix", Transport: "tls", Address: "192.168.1.101:4739", InsecureSkipVerify: true},
+				{Type: "json", Address: "/var/log/antrea/flows.json", BufferSize: 100},
+				{Type: "json", Transport: "tcp", Address: "192.168.1.102:5000"},
+				{Type: "kafka", Address: "kafka-0:9092, kafka-1:9092", Topic: "flows"},
+			},
+			expSinks: []string{
+				"IPFIX collector 192.168.1.100:2002:udp",
+				"IPFIX collector 192.168.1.101:4739:tls",
+				"JSON file /var/log/antrea/flows.json",
+				"JSON tcp 192.168.1.102:5000",
+				"Kafka topic flows on kafka-0:9092,kafka-1:9092",
+			},
+		},
+		{
+			name:     "no collector or sink",
+			expError: true,
+		},
+		{
+			name:     "unsupported sink type",
+			sinks:    []FlowExportSinkConfig{{Type: "syslog", Address: "192.168.1.101:514"}},
+			expError: true,
+		},
+		{
+			name:     "unsupported IPFIX transport",
+			sinks:    []FlowExportSinkConfig{{Type: "ipfix", Transport: "sctp", Address: "192.168.1.101:4739"}},
+			expError: true,
+		},
+		{
+			name:     "invalid JSON socket address",
+			sinks:    []FlowExportSinkConfig{{Type: "json", Transport: "udp", Address: "192.168.1.101"}},
+			expError: true,
+		},
+		{
+			name:     "missing Kafka topic",
+			sinks:    []FlowExportSinkConfig{{Type: "kafka", Address: "kafka-0:9092"}},
+			expError: true,
+		},
+		{
+			name:     "missing client key",
+			sinks:    []FlowExportSinkConfig{{Type: "kafka", Transport: "tls", Address: "kafka-0:9093", Topic: "flows", ClientCertFile: "/etc/antrea/tls.crt"}},
+			expError: true,
+		},
+		{
+			name:     "negative buffer size",
+			sinks:    []FlowExportSinkConfig{{Type: "json", Address: "/var/log/antrea/flows.json", BufferSize: -1}},
+			expError: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			testOptions := &Options{
+				config: &AgentConfig{
+					FlowCollectorAddr: tc.collector,
+					FlowExportSinks:   tc.sinks,
+				},
+			}
+			err := testOptions.validateFlowExporterConfig()
+			if tc.expError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var sinks []string
+			for _, sink := range testOptions.flowExportSinks {
+				sinks = append(sinks, sink.sink.String())
+			}
+			assert.Equal(t, tc.expSinks, sinks)
+			assert.Equal(t, 100, testOptions.flowExportSinks[2].bufferSize)
+			assert.Equal(t, exporter.DefaultSinkBufferSize, testOptions.flowExportSinks[3].bufferSize)
+		})
+	}
+}
+
+func TestOptions_validateFlowConntrackEvents(t *testing.T) {
+	features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{"FlowExporter": true})
+	for _, tc := range []struct {
+		datapathType string
+		expError     bool
+	}{
+		{datapathType: ovsconfig.OVSDatapathSystem, expError: false},
+		{datapathType: ovsconfig.OVSDatapathNetdev, expError: true},
+	} {
+		testOptions := &Options{config: new(AgentConfig)}
+		testOptions.config.FlowCollectorAddr = "192.168.1.100:2002"
+		testOptions.config.OVSDatapathType = tc.datapathType
+		testOptions.config.EnableFlowConntrackEvents = true
+		err := testOptions.validateFlowExporterConfig()
+		if tc.expError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -39,4 +39,16 @@ type ControllerConfig struct {
 	// antrea-controller container.
 	// Defaults to true.
 	SelfSignedCert bool `yaml:"selfSignedCert,omitempty"`
+	// ClusterID is the unique ID of this cluster in the multicluster set. It's
+	// required when the Multicluster feature is enabled.
+	ClusterID string `yaml:"clusterID,omitempty"`
+	// LeaderClusterKubeconfig is the path of the kubeconfig file used to access
+	// the leader cluster of the multicluster set, which stores the
+	// ServiceImports and ClusterInfos of all the clusters.
+	// Defaults to "", which means this cluster is the leader cluster.
+	LeaderClusterKubeconfig string `yaml:"leaderClusterKubeconfig,omitempty"`
+	// LeaderClusterNamespace is the Namespace of the leader cluster which stores
+	// the ServiceImports and ClusterInfos of all the clusters.
+	// Defaults to "antrea-multicluster".
+	LeaderClusterNamespace string `yaml:"leaderClusterNamespace,omitempty"`
 }
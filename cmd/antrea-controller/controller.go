@@ -35,7 +35,9 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/openapi"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/storage"
 	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	"github.com/vmware-tanzu/antrea/pkg/controller/externalippool"
 	"github.com/vmware-tanzu/antrea/pkg/controller/metrics"
+	"github.com/vmware-tanzu/antrea/pkg/controller/multicluster"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
 	"github.com/vmware-tanzu/antrea/pkg/controller/querier"
@@ -46,6 +48,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/log"
 	"github.com/vmware-tanzu/antrea/pkg/monitor"
 	"github.com/vmware-tanzu/antrea/pkg/signals"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
 	"github.com/vmware-tanzu/antrea/pkg/version"
 )
 
@@ -73,6 +76,7 @@ var allowedPaths = []string{
 	"/validate/tier",
 	"/validate/acnp",
 	"/validate/anp",
+	"/validate/acg",
 }
 
 // run starts Antrea Controller with the given options and waits for termination signal.
@@ -89,12 +93,14 @@ func run(o *Options) error {
 	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, informerDefaultResync)
 	podInformer := informerFactory.Core().V1().Pods()
 	namespaceInformer := informerFactory.Core().V1().Namespaces()
+	serviceInformer := informerFactory.Core().V1().Services()
 	networkPolicyInformer := informerFactory.Networking().V1().NetworkPolicies()
 	nodeInformer := informerFactory.Core().V1().Nodes()
 	cnpInformer := crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
 	externalEntityInformer := crdInformerFactory.Core().V1alpha1().ExternalEntities()
 	anpInformer := crdInformerFactory.Security().V1alpha1().NetworkPolicies()
 	tierInformer := crdInformerFactory.Security().V1alpha1().Tiers()
+	cgInformer := crdInformerFactory.Core().V1alpha2().ClusterGroups()
 	traceflowInformer := crdInformerFactory.Ops().V1alpha1().Traceflows()
 
 	// Create Antrea object storage.
@@ -106,11 +112,13 @@ func run(o *Options) error {
 		crdClient,
 		podInformer,
 		namespaceInformer,
+		serviceInformer,
 		externalEntityInformer,
 		networkPolicyInformer,
 		cnpInformer,
 		anpInformer,
 		tierInformer,
+		cgInformer,
 		addressGroupStore,
 		appliedToGroupStore,
 		networkPolicyStore)
@@ -119,6 +127,11 @@ func run(o *Options) error {
 
 	controllerQuerier := querier.NewControllerQuerier(networkPolicyController, o.config.APIPort)
 
+	var networkPolicyStatusController *networkpolicy.StatusController
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		networkPolicyStatusController = networkpolicy.NewStatusController(crdClient, networkPolicyStore, cnpInformer, anpInformer)
+	}
+
 	controllerMonitor := monitor.NewControllerMonitor(crdClient, nodeInformer, controllerQuerier)
 
 	var traceflowController *traceflow.Controller
@@ -126,6 +139,43 @@ func run(o *Options) error {
 		traceflowController = traceflow.NewTraceflowController(crdClient, podInformer, traceflowInformer)
 	}
 
+	var externalIPPoolController *externalippool.Controller
+	if features.DefaultFeatureGate.Enabled(features.ServiceExternalIP) {
+		externalIPPoolController = externalippool.NewExternalIPPoolController(client, crdClient,
+			serviceInformer,
+			crdInformerFactory.Core().V1alpha2().ExternalIPPools())
+	}
+
+	var multiclusterController *multicluster.Controller
+	var leaderCRDInformerFactory crdinformers.SharedInformerFactory
+	if features.DefaultFeatureGate.Enabled(features.Multicluster) {
+		leaderCRDClient := crdClient
+		if o.config.LeaderClusterKubeconfig != "" {
+			leaderClientConnection := o.config.ClientConnection
+			leaderClientConnection.Kubeconfig = o.config.LeaderClusterKubeconfig
+			_, _, leaderCRDClient, err = k8s.CreateClients(leaderClientConnection)
+			if err != nil {
+				return fmt.Errorf("error creating K8s clients for the leader cluster: %v", err)
+			}
+		}
+		// The member clusters may only be allowed to access the leader Namespace of the leader cluster.
+		leaderCRDInformerFactory = crdinformers.NewSharedInformerFactoryWithOptions(leaderCRDClient, informerDefaultResync,
+			crdinformers.WithNamespace(o.config.LeaderClusterNamespace))
+		multiclusterController = multicluster.NewMulticlusterController(o.config.ClusterID,
+			env.GetPodNamespace(),
+			o.config.LeaderClusterNamespace,
+			client,
+			crdClient,
+			leaderCRDClient,
+			nodeInformer,
+			serviceInformer,
+			informerFactory.Core().V1().Endpoints(),
+			crdInformerFactory.Multicluster().V1alpha1().ServiceExports(),
+			crdInformerFactory.Multicluster().V1alpha1().ClusterInfos(),
+			leaderCRDInformerFactory.Multicluster().V1alpha1().ServiceImports(),
+			leaderCRDInformerFactory.Multicluster().V1alpha1().ClusterInfos())
+	}
+
 	// statsAggregator takes stats summaries from antrea-agents, aggregates them, and serves the Stats APIs with the
 	// aggregated data. For now it's only used for NetworkPolicy stats.
 	var statsAggregator *stats.Aggregator
@@ -144,6 +194,7 @@ func run(o *Options) error {
 		controllerQuerier,
 		endpointQuerier,
 		networkPolicyController,
+		networkPolicyStatusController,
 		statsAggregator,
 		o.config.EnablePrometheusMetrics)
 	if err != nil {
@@ -173,6 +224,10 @@ func run(o *Options) error {
 
 	go networkPolicyController.Run(stopCh)
 
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		go networkPolicyStatusController.Run(stopCh)
+	}
+
 	go apiServer.Run(stopCh)
 
 	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
@@ -187,6 +242,15 @@ func run(o *Options) error {
 		go traceflowController.Run(stopCh)
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.ServiceExternalIP) {
+		go externalIPPoolController.Run(stopCh)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.Multicluster) {
+		leaderCRDInformerFactory.Start(stopCh)
+		go multiclusterController.Run(stopCh)
+	}
+
 	<-stopCh
 	klog.Info("Stopping Antrea controller")
 	return nil
@@ -203,6 +267,7 @@ func createAPIServerConfig(kubeconfig string,
 	controllerQuerier querier.ControllerQuerier,
 	endpointQuerier networkpolicy.EndpointQuerier,
 	npController *networkpolicy.NetworkPolicyController,
+	npStatusController *networkpolicy.StatusController,
 	statsAggregator *stats.Aggregator,
 	enableMetrics bool) (*apiserver.Config, error) {
 	secureServing := genericoptions.NewSecureServingOptions().WithLoopback()
@@ -255,5 +320,6 @@ func createAPIServerConfig(kubeconfig string,
 		statsAggregator,
 		controllerQuerier,
 		endpointQuerier,
-		npController), nil
+		npController,
+		npStatusController), nil
 }
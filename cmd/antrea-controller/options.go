@@ -16,6 +16,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 
 	"github.com/spf13/pflag"
@@ -23,8 +24,11 @@ import (
 
 	"github.com/vmware-tanzu/antrea/pkg/apis"
 	"github.com/vmware-tanzu/antrea/pkg/features"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
 )
 
+const defaultLeaderClusterNamespace = "antrea-multicluster"
+
 type Options struct {
 	// The path of configuration file.
 	configFile string
@@ -61,6 +65,15 @@ func (o *Options) validate(args []string) error {
 	if len(args) != 0 {
 		return errors.New("no positional arguments are supported")
 	}
+	if features.DefaultFeatureGate.Enabled(features.Multicluster) {
+		if o.config.ClusterID == "" {
+			return errors.New("clusterID is required when Multicluster is enabled")
+		}
+		// The ClusterInfos of the remote clusters are copied to the Antrea Namespace.
+		if o.config.LeaderClusterKubeconfig == "" && o.config.LeaderClusterNamespace == env.GetPodNamespace() {
+			return fmt.Errorf("leaderClusterNamespace must not be the Antrea Namespace %s", env.GetPodNamespace())
+		}
+	}
 	return nil
 }
 
@@ -84,4 +97,7 @@ func (o *Options) setDefaults() {
 	if o.config.APIPort == 0 {
 		o.config.APIPort = apis.AntreaControllerAPIPort
 	}
+	if o.config.LeaderClusterNamespace == "" {
+		o.config.LeaderClusterNamespace = defaultLeaderClusterNamespace
+	}
 }
@@ -0,0 +1,33 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+type FlowAggregatorConfig struct {
+	// Provide the address on which the flow aggregator collects the IPFIX flow records from the Flow Exporters, as
+	// string with format [<IP>]:<port>[:<proto>], where proto is tcp or udp. It must match the flowCollectorAddr of
+	// the Antrea Agents. If no L4 transport proto is given, we consider tcp as default.
+	// Defaults to ":4739:tcp".
+	CollectorAddr string `yaml:"collectorAddr,omitempty"`
+	// Provide the address of the external flow collector to which the aggregated flow records are exported, as
+	// string with format <IP>:<port>[:<proto>], where proto is tcp or udp. If no L4 transport proto is given, we
+	// consider tcp as default.
+	// Defaults to "".
+	ExternalFlowCollectorAddr string `yaml:"externalFlowCollectorAddr,omitempty"`
+	// Provide flow export interval as a duration string. This determines how often the flow aggregator exports the
+	// aggregated flow records to the external flow collector. It should be the same as the flow export interval of
+	// the Flow Exporters, which is flowPollInterval * flowExportFrequency.
+	// Defaults to "60s". Follow the time units of duration.
+	FlowExportInterval string `yaml:"flowExportInterval,omitempty"`
+}
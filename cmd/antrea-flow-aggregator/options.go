@@ -0,0 +1,145 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultCollectorAddr      = ":4739:tcp"
+	defaultFlowExportInterval = time.Minute
+)
+
+type Options struct {
+	// The path of configuration file.
+	configFile string
+	// The configuration object
+	config *FlowAggregatorConfig
+	// The address to collect the flow records from the Flow Exporters.
+	collector net.Addr
+	// The address of the external flow collector.
+	externalFlowCollector net.Addr
+	// The interval to export the aggregated flow records.
+	exportInterval time.Duration
+}
+
+func newOptions() *Options {
+	return &Options{
+		config: new(FlowAggregatorConfig),
+	}
+}
+
+// addFlags adds flags to fs and binds them to options.
+func (o *Options) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.configFile, "config", o.configFile, "The path to the configuration file")
+}
+
+// complete completes all the required options.
+func (o *Options) complete(args []string) error {
+	if len(o.configFile) > 0 {
+		c, err := o.loadConfigFromFile(o.configFile)
+		if err != nil {
+			return err
+		}
+		o.config = c
+	}
+	o.setDefaults()
+	return nil
+}
+
+// validate validates all the required options.
+func (o *Options) validate(args []string) error {
+	if len(args) != 0 {
+		return errors.New("no positional arguments are supported")
+	}
+	var err error
+	if o.collector, err = parseFlowCollectorAddr(o.config.CollectorAddr); err != nil {
+		return fmt.Errorf("collectorAddr is invalid: %v", err)
+	}
+	if o.config.ExternalFlowCollectorAddr == "" {
+		return errors.New("IPFIX external flow collector address should be provided")
+	}
+	if o.externalFlowCollector, err = parseFlowCollectorAddr(o.config.ExternalFlowCollectorAddr); err != nil {
+		return fmt.Errorf("externalFlowCollectorAddr is invalid: %v", err)
+	}
+	if o.config.FlowExportInterval != "" {
+		o.exportInterval, err = time.ParseDuration(o.config.FlowExportInterval)
+		if err != nil {
+			return fmt.Errorf("FlowExportInterval is not provided in right format: %v", err)
+		}
+		if o.exportInterval < time.Second {
+			return errors.New("FlowExportInterval should be greater than or equal to one second")
+		}
+	}
+	return nil
+}
+
+// parseFlowCollectorAddr parses an address with format [<IP>]:<port>[:<proto>],
+// where proto is tcp or udp and defaults to tcp.
+func parseFlowCollectorAddr(addr string) (net.Addr, error) {
+	strSlice := strings.Split(addr, ":")
+	var proto string
+	if len(strSlice) == 2 {
+		proto = "tcp"
+	} else if len(strSlice) == 3 {
+		if (strSlice[2] != "udp") && (strSlice[2] != "tcp") {
+			return nil, fmt.Errorf("IPFIX over %s proto is not supported", strSlice[2])
+		}
+		proto = strSlice[2]
+	} else {
+		return nil, fmt.Errorf("address %s is given in invalid format", addr)
+	}
+
+	hostPortAddr := strSlice[0] + ":" + strSlice[1]
+	if _, _, err := net.SplitHostPort(hostPortAddr); err != nil {
+		return nil, fmt.Errorf("address %s is given in invalid format: %v", addr, err)
+	}
+	if proto == "udp" {
+		return net.ResolveUDPAddr("udp", hostPortAddr)
+	}
+	return net.ResolveTCPAddr("tcp", hostPortAddr)
+}
+
+func (o *Options) loadConfigFromFile(file string) (*FlowAggregatorConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var c FlowAggregatorConfig
+	err = yaml.UnmarshalStrict(data, &c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (o *Options) setDefaults() {
+	if o.config.CollectorAddr == "" {
+		o.config.CollectorAddr = defaultCollectorAddr
+	}
+	if o.config.FlowExportInterval == "" {
+		o.exportInterval = defaultFlowExportInterval
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	testcases := []struct {
+		name                     string
+		config                   FlowAggregatorConfig
+		expectedErr              bool
+		expectedCollector        string
+		expectedCollectorNetwork string
+		expectedExternalNetwork  string
+		expectedExportInterval   time.Duration
+	}{
+		{
+			name:                     "default",
+			config:                   FlowAggregatorConfig{ExternalFlowCollectorAddr: "192.168.1.100:4739"},
+			expectedCollector:        ":4739",
+			expectedCollectorNetwork: "tcp",
+			expectedExternalNetwork:  "tcp",
+			expectedExportInterval:   time.Minute,
+		},
+		{
+			name: "udp",
+			config: FlowAggregatorConfig{
+				CollectorAddr:             "0.0.0.0:4739:udp",
+				ExternalFlowCollectorAddr: "192.168.1.100:4739:udp",
+				FlowExportInterval:        "30s",
+			},
+			expectedCollector:        "0.0.0.0:4739",
+			expectedCollectorNetwork: "udp",
+			expectedExternalNetwork:  "udp",
+			expectedExportInterval:   30 * time.Second,
+		},
+		{
+			name:        "missing external flow collector",
+			config:      FlowAggregatorConfig{},
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported proto",
+			config:      FlowAggregatorConfig{ExternalFlowCollectorAddr: "192.168.1.100:4739:sctp"},
+			expectedErr: true,
+		},
+		{
+			name: "invalid export interval",
+			config: FlowAggregatorConfig{
+				ExternalFlowCollectorAddr: "192.168.1.100:4739",
+				FlowExportInterval:        "100ms",
+			},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.config
+			o := &Options{config: &config}
+			assert.NoError(t, o.complete(nil))
+			err := o.validate(nil)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCollector, o.collector.String())
+			assert.Equal(t, tc.expectedCollectorNetwork, o.collector.Network())
+			assert.Equal(t, tc.expectedExternalNetwork, o.externalFlowCollector.Network())
+			assert.Equal(t, tc.expectedExportInterval, o.exportInterval)
+		})
+	}
+}
@@ -285,7 +285,7 @@ func (i *Initializer) initOpenFlowPipeline() error {
 
 	// Set up flow entries for gateway interface, including classifier, skip spoof guard check,
 	// L3 forwarding and L2 forwarding
-	if err := i.ofClient.InstallGatewayFlows(gateway.IP, gateway.MAC, gatewayOFPort); err != nil {
+	if err := i.ofClient.InstallGatewayFlows(gateway.IPs, gateway.MAC, gatewayOFPort); err != nil {
 		klog.Errorf("Failed to setup openflow entries for gateway: %v", err)
 		return err
 	}
@@ -455,7 +455,7 @@ func (i *Initializer) configureGatewayInterface(gatewayIface *interfacestore.Int
 	if i.networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
 		// Assign IP to gw as required by SpoofGuard.
 		i.nodeConfig.GatewayConfig.IP = i.nodeConfig.NodeIPAddr.IP
-		gatewayIface.IP = i.nodeConfig.NodeIPAddr.IP
+		gatewayIface.IPs = []net.IP{i.nodeConfig.NodeIPAddr.IP}
 		// No need to assign local CIDR to gw0 because local CIDR is not managed by Antrea
 		return nil
 	}
@@ -476,7 +476,18 @@ func (i *Initializer) configureGatewayInterface(gatewayIface *interfacestore.Int
 
 	i.nodeConfig.GatewayConfig.LinkIndex = gwLinkIdx
 	i.nodeConfig.GatewayConfig.IP = gwIP.IP
-	gatewayIface.IP = gwIP.IP
+	gatewayIface.IPs = []net.IP{gwIP.IP}
+
+	// Configure host gateway IPv6 address using the first address of node IPv6 localSubnet.
+	if localIPv6Subnet := i.nodeConfig.PodIPv6CIDR; localIPv6Subnet != nil {
+		subnetID := localIPv6Subnet.IP.Mask(localIPv6Subnet.Mask)
+		gwIPv6 := &net.IPNet{IP: ip.NextIP(subnetID), Mask: localIPv6Subnet.Mask}
+		if err := util.ConfigureLinkAddress(gwLinkIdx, gwIPv6); err != nil {
+			return err
+		}
+		i.nodeConfig.GatewayConfig.IPv6 = gwIPv6.IP
+		gatewayIface.IPs = append(gatewayIface.IPs, gwIPv6.IP)
+	}
 	return nil
 }
 
@@ -591,6 +602,15 @@ func (i *Initializer) initNodeLocalConfig() error {
 		NodeMTU:         mtu,
 		UplinkNetConfig: new(config.AdapterNetConfig)}
 
+	// The IPv6 address of a dual-stack Node is used to route IPv6 Pod traffic in noEncap mode.
+	if ipv6Addr := noderoute.GetNodeIPv6Addr(node); ipv6Addr != nil {
+		if localIPv6Addr, _, err := util.GetIPNetDeviceFromIP(ipv6Addr); err != nil {
+			klog.Warningf("Failed to get local IPv6 IPNet: %v", err)
+		} else {
+			i.nodeConfig.NodeIPv6Addr = localIPv6Addr
+		}
+	}
+
 	if i.networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
 		return nil
 	}
@@ -601,12 +621,27 @@ func (i *Initializer) initNodeLocalConfig() error {
 			"for kube-controller-manager and --cluster-cidr specifies a sufficient CIDR range", nodeName)
 		return fmt.Errorf("CIDR string is empty for node %s", nodeName)
 	}
-	_, localSubnet, err := net.ParseCIDR(node.Spec.PodCIDR)
-	if err != nil {
-		klog.Errorf("Failed to parse subnet from CIDR string %s: %v", node.Spec.PodCIDR, err)
-		return err
+	// Spec.PodCIDRs contains both the IPv4 and the IPv6 Pod CIDRs of a dual-stack Node. Spec.PodCIDR is always the
+	// first one of Spec.PodCIDRs.
+	podCIDRs := node.Spec.PodCIDRs
+	if len(podCIDRs) == 0 {
+		podCIDRs = []string{node.Spec.PodCIDR}
+	}
+	for _, podCIDR := range podCIDRs {
+		_, localSubnet, err := net.ParseCIDR(podCIDR)
+		if err != nil {
+			klog.Errorf("Failed to parse subnet from CIDR string %s: %v", podCIDR, err)
+			return err
+		}
+		if localSubnet.IP.To4() != nil {
+			i.nodeConfig.PodCIDR = localSubnet
+		} else {
+			i.nodeConfig.PodIPv6CIDR = localSubnet
+		}
+	}
+	if i.nodeConfig.PodCIDR == nil {
+		return fmt.Errorf("no IPv4 CIDR is allocated to node %s, IPv6 single-stack is not supported", nodeName)
 	}
-	i.nodeConfig.PodCIDR = localSubnet
 	return nil
 }
 
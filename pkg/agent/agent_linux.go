@@ -17,7 +17,14 @@
 package agent
 
 import (
+	"bytes"
+	"fmt"
 	"net"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
 )
 
 // setupExternalConnectivity returns immediately on Linux. The corresponding functions are provided in routeClient.
@@ -45,3 +52,24 @@ func (i *Initializer) initHostNetworkFlows() error {
 func (i *Initializer) getTunnelPortLocalIP() net.IP {
 	return nil
 }
+
+// OVSMetersAreSupported returns whether OpenFlow meters can be used with the provided OVS datapath type. Meters are
+// always supported by the userspace datapath. With the kernel datapath, meters require Linux 4.15 or later, and they
+// don't work correctly before Linux 4.18 because of a kernel bug.
+func OVSMetersAreSupported(ovsDatapathType string) bool {
+	if ovsDatapathType == ovsconfig.OVSDatapathNetdev {
+		return true
+	}
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		klog.Errorf("Failed to get kernel version, assuming OVS meters are not supported: %v", err)
+		return false
+	}
+	release := string(bytes.TrimRight(uname.Release[:], "\x00"))
+	var major, minor int
+	if _, err := fmt.Sscanf(release, "%d.%d", &major, &minor); err != nil {
+		klog.Errorf("Failed to parse kernel version %s, assuming OVS meters are not supported: %v", release, err)
+		return false
+	}
+	return major > 4 || (major == 4 && minor >= 18)
+}
@@ -195,6 +195,11 @@ func (i *Initializer) initHostNetworkFlows() error {
 	return nil
 }
 
+// OVSMetersAreSupported returns false as OpenFlow meters are not supported by OVS on Windows.
+func OVSMetersAreSupported(ovsDatapathType string) bool {
+	return false
+}
+
 // getTunnelLocalIP returns local_ip of tunnel port
 func (i *Initializer) getTunnelPortLocalIP() net.IP {
 	return i.nodeConfig.NodeIPAddr.IP
@@ -30,6 +30,16 @@ type IPAMConfig struct {
 	Type    string `json:"type,omitempty"`
 	Subnet  string `json:"subnet,omitempty"`
 	Gateway string `json:"gateway,omitempty"`
+	// Ranges is used for the additional IPv6 subnet of a dual-stack Node. The IPAM plugin allocates
+	// an IP from Subnet and an IP from each RangeSet.
+	Ranges []RangeSet `json:"ranges,omitempty"`
+}
+
+type RangeSet []Range
+
+type Range struct {
+	Subnet  string `json:"subnet"`
+	Gateway string `json:"gateway,omitempty"`
 }
 
 type IPAMDriver interface {
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 
 	cnitypes "github.com/containernetworking/cni/pkg/types"
 	"github.com/containernetworking/cni/pkg/types/current"
@@ -32,6 +33,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/k8s"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
 )
 
 type vethPair struct {
@@ -112,19 +114,44 @@ func findContainerIPConfig(ips []*current.IPConfig) (*current.IPConfig, error) {
 	return nil, fmt.Errorf("failed to find a valid IP address")
 }
 
-func parseContainerIP(ips []*current.IPConfig) (net.IP, error) {
-	ipc, err := findContainerIPConfig(ips)
-	if err == nil {
-		return ipc.Address.IP, nil
+// parseContainerIPs returns all the IP addresses of the container, including
+// both the IPv4 and the IPv6 address if the Pod is dual-stack.
+func parseContainerIPs(ipcs []*current.IPConfig) ([]net.IP, error) {
+	var ips []net.IP
+	for _, ipc := range ipcs {
+		if ipc.Version == "4" || ipc.Version == "6" {
+			ips = append(ips, ipc.Address.IP)
+		}
+	}
+	if len(ips) > 0 {
+		return ips, nil
 	}
 	return nil, fmt.Errorf("failed to find a valid IP address")
 }
 
+func getContainerIPsString(ips []net.IP) string {
+	var containerIPs []string
+	for _, ipAddr := range ips {
+		containerIPs = append(containerIPs, ipAddr.String())
+	}
+	return strings.Join(containerIPs, ",")
+}
+
+func parseContainerIPsString(ipStr string) []net.IP {
+	var ips []net.IP
+	for _, s := range strings.Split(ipStr, ",") {
+		if ipAddr := net.ParseIP(s); ipAddr != nil {
+			ips = append(ips, ipAddr)
+		}
+	}
+	return ips
+}
+
 func buildContainerConfig(
 	interfaceName, containerID, podName, podNamespace string,
 	containerIface *current.Interface,
 	ips []*current.IPConfig) *interfacestore.InterfaceConfig {
-	containerIP, err := parseContainerIP(ips)
+	containerIPs, err := parseContainerIPs(ips)
 	if err != nil {
 		klog.Errorf("Failed to find container %s IP", containerID)
 	}
@@ -136,7 +163,7 @@ func buildContainerConfig(
 		podName,
 		podNamespace,
 		containerMAC,
-		containerIP)
+		containerIPs)
 }
 
 // BuildOVSPortExternalIDs parses OVS port external_ids from InterfaceConfig.
@@ -145,7 +172,7 @@ func BuildOVSPortExternalIDs(containerConfig *interfacestore.InterfaceConfig) ma
 	externalIDs := make(map[string]interface{})
 	externalIDs[ovsExternalIDMAC] = containerConfig.MAC.String()
 	externalIDs[ovsExternalIDContainerID] = containerConfig.ContainerID
-	externalIDs[ovsExternalIDIP] = containerConfig.IP.String()
+	externalIDs[ovsExternalIDIP] = getContainerIPsString(containerConfig.IPs)
 	externalIDs[ovsExternalIDPodName] = containerConfig.PodName
 	externalIDs[ovsExternalIDPodNamespace] = containerConfig.PodNamespace
 	return externalIDs
@@ -166,7 +193,7 @@ func ParseOVSPortInterfaceConfig(portData *ovsconfig.OVSPortData, portConfig *in
 		klog.V(2).Infof("OVS port %s has no %s in external_ids", portData.Name, ovsExternalIDContainerID)
 		return nil
 	}
-	containerIP := net.ParseIP(portData.ExternalIDs[ovsExternalIDIP])
+	containerIPs := parseContainerIPsString(portData.ExternalIDs[ovsExternalIDIP])
 	containerMAC, err := net.ParseMAC(portData.ExternalIDs[ovsExternalIDMAC])
 	if err != nil {
 		klog.Errorf("Failed to parse MAC address from OVS external config %s: %v",
@@ -181,7 +208,7 @@ func ParseOVSPortInterfaceConfig(portData *ovsconfig.OVSPortData, portConfig *in
 		podName,
 		podNamespace,
 		containerMAC,
-		containerIP)
+		containerIPs)
 	interfaceConfig.OVSPortConfig = portConfig
 	return interfaceConfig
 }
@@ -361,14 +388,22 @@ func (pc *podConfigurator) validateOVSInterfaceConfig(containerID string, contai
 		}
 
 		for _, ipc := range ips {
-			if ipc.Version == "4" {
-				if containerConfig.IP.Equal(ipc.Address.IP) {
-					return nil
+			if ipc.Version != "4" && ipc.Version != "6" {
+				continue
+			}
+			found := false
+			for _, ipAddr := range containerConfig.IPs {
+				if ipAddr.Equal(ipc.Address.IP) {
+					found = true
+					break
 				}
 			}
+			if !found {
+				return fmt.Errorf("interface IPs %s do not match container %s IP %s",
+					getContainerIPsString(containerConfig.IPs), containerID, ipc.Address.IP.String())
+			}
 		}
-		return fmt.Errorf("interface IP %s does not match container %s IP",
-			containerConfig.IP.String(), containerID)
+		return nil
 	} else {
 		return fmt.Errorf("container %s interface not found from local cache", containerID)
 	}
@@ -420,7 +455,7 @@ func (pc *podConfigurator) reconcile(pods []corev1.Pod) error {
 			klog.V(4).Infof("Syncing interface %s for Pod %s", containerConfig.InterfaceName, namespacedName)
 			if err := pc.ofClient.InstallPodFlows(
 				containerConfig.InterfaceName,
-				containerConfig.IP,
+				containerConfig.IPs,
 				containerConfig.MAC,
 				pc.gatewayMAC,
 				uint32(containerConfig.OFPort),
@@ -486,7 +521,7 @@ func (pc *podConfigurator) connectInterfaceToOVS(
 	}
 
 	klog.V(2).Infof("Setting up Openflow entries for container %s", containerID)
-	err = pc.ofClient.InstallPodFlows(ovsPortName, containerConfig.IP, containerConfig.MAC, pc.gatewayMAC, uint32(ofPort))
+	err = pc.ofClient.InstallPodFlows(ovsPortName, containerConfig.IPs, containerConfig.MAC, pc.gatewayMAC, uint32(ofPort))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add Openflow entries for container %s: %v", containerID, err)
 	}
@@ -552,7 +587,7 @@ func (pc *podConfigurator) disconnectInterceptedInterface(podName, podNamespace,
 		return nil
 	}
 	if err := pc.routeClient.UnMigrateRoutesFromGw(&net.IPNet{
-		IP:   containerConfig.IP,
+		IP:   ip.GetIPv4Addr(containerConfig.IPs),
 		Mask: net.CIDRMask(32, 32),
 	}, ""); err != nil {
 		return fmt.Errorf("connectInterceptedInterface failed to migrate: %w", err)
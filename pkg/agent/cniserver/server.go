@@ -138,9 +138,14 @@ type CNIConfig struct {
 //   * updates the IP configuration for each assigned IP address: this includes computing the
 //     gateway (if missing) based on the subnet and setting the interface pointer to the container
 //     interface
-//   * if there is no default route, add one using the provided default gateway
-func updateResultIfaceConfig(result *current.Result, defaultV4Gateway net.IP) {
+//   * if there is no default route, add one using the provided default gateway. The IPv6 default
+//     route is added only if an IPv6 address is assigned and defaultV6Gateway is provided
+func updateResultIfaceConfig(result *current.Result, defaultV4Gateway, defaultV6Gateway net.IP) {
+	hasIPv6 := false
 	for _, ipc := range result.IPs {
+		if ipc.Version == "6" {
+			hasIPv6 = true
+		}
 		// result.Interfaces[0] is host interface, and result.Interfaces[1] is container interface
 		ipc.Interface = current.Int(1)
 		if ipc.Gateway == nil {
@@ -150,22 +155,25 @@ func updateResultIfaceConfig(result *current.Result, defaultV4Gateway net.IP) {
 		}
 	}
 
-	foundDefaultRoute := false
-	defaultRouteDst := "0.0.0.0/0"
-	if result.Routes != nil {
-		for _, rt := range result.Routes {
-			if rt.Dst.String() == defaultRouteDst {
-				foundDefaultRoute = true
-				break
-			}
-		}
-	} else {
+	if result.Routes == nil {
 		result.Routes = []*cnitypes.Route{}
 	}
-	if !foundDefaultRoute {
-		_, defaultRouteDstNet, _ := net.ParseCIDR(defaultRouteDst)
-		result.Routes = append(result.Routes, &cnitypes.Route{Dst: *defaultRouteDstNet, GW: defaultV4Gateway})
+	addDefaultRoute(result, "0.0.0.0/0", defaultV4Gateway)
+	if hasIPv6 && defaultV6Gateway != nil {
+		addDefaultRoute(result, "::/0", defaultV6Gateway)
+	}
+}
+
+// addDefaultRoute adds a default route with the provided destination and gateway to the result
+// if there is no such route.
+func addDefaultRoute(result *current.Result, defaultRouteDst string, gateway net.IP) {
+	for _, rt := range result.Routes {
+		if rt.Dst.String() == defaultRouteDst {
+			return
+		}
 	}
+	_, defaultRouteDstNet, _ := net.ParseCIDR(defaultRouteDst)
+	result.Routes = append(result.Routes, &cnitypes.Route{Dst: *defaultRouteDstNet, GW: gateway})
 }
 
 func (s *CNIServer) loadNetworkConfig(request *cnipb.CniCmdRequest) (*CNIConfig, error) {
@@ -221,6 +229,12 @@ func (s *CNIServer) checkRequestMessage(request *cnipb.CniCmdRequest) (*CNIConfi
 func (s *CNIServer) updateLocalIPAMSubnet(cniConfig *CNIConfig) {
 	cniConfig.NetworkConfig.IPAM.Gateway = s.nodeConfig.GatewayConfig.IP.String()
 	cniConfig.NetworkConfig.IPAM.Subnet = s.nodeConfig.PodCIDR.String()
+	if s.nodeConfig.PodIPv6CIDR != nil {
+		// The IPAM plugin allocates an IP from Subnet and an IP from each RangeSet of Ranges.
+		cniConfig.NetworkConfig.IPAM.Ranges = []ipam.RangeSet{
+			{{Subnet: s.nodeConfig.PodIPv6CIDR.String(), Gateway: s.nodeConfig.GatewayConfig.IPv6.String()}},
+		}
+	}
 	cniConfig.NetworkConfiguration, _ = json.Marshal(cniConfig.NetworkConfig)
 }
 
@@ -400,7 +414,7 @@ func (s *CNIServer) CmdAdd(ctx context.Context, request *cnipb.CniCmdRequest) (*
 	result.IPs = ipamResult.IPs
 	result.Routes = ipamResult.Routes
 	// Ensure interface gateway setting and mapping relations between result.Interfaces and result.IPs
-	updateResultIfaceConfig(result, s.nodeConfig.GatewayConfig.IP)
+	updateResultIfaceConfig(result, s.nodeConfig.GatewayConfig.IP, s.nodeConfig.GatewayConfig.IPv6)
 	// Setup pod interfaces and connect to ovs bridge
 	podName := string(cniConfig.K8S_POD_NAME)
 	podNamespace := string(cniConfig.K8S_POD_NAMESPACE)
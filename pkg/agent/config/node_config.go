@@ -33,6 +33,19 @@ const (
 	BridgeOFPort = 0xfffffffe
 )
 
+var (
+	// VirtualNodePortIP is a link-local IP used as the destination of NodePort
+	// traffic which is DNAT'd by iptables and forwarded to OVS via the host
+	// gateway, so that AntreaProxy can load-balance it in the Service tables.
+	VirtualNodePortIP = net.ParseIP("169.254.169.110")
+	// VirtualServiceIP is a link-local IP used as the next hop of the route to
+	// the Service CIDR on the host gateway. It's also used as the source IP of
+	// the Service traffic which is sent to OVS from the host gateway and
+	// load-balanced to an Endpoint reached via the host gateway again, e.g. a
+	// hostNetwork Endpoint, so that the reply traffic can be sent back to OVS.
+	VirtualServiceIP = net.ParseIP("169.254.169.253")
+)
+
 const (
 	VXLANOverhead  = 50
 	GeneveOverhead = 50
@@ -46,13 +59,28 @@ type GatewayConfig struct {
 	// Name is the name of host gateway, e.g. antrea-gw0.
 	Name string
 	IP   net.IP
+	// IPv6 is the IPv6 address of host gateway. It's nil if the Node has no
+	// IPv6 Pod CIDR.
+	IPv6 net.IP
 	MAC  net.HardwareAddr
 	// LinkIndex is the link index of host gateway.
 	LinkIndex int
 }
 
 func (g *GatewayConfig) String() string {
-	return fmt.Sprintf("Name %s: IP %s, MAC %s", g.Name, g.IP, g.MAC)
+	return fmt.Sprintf("Name %s: IP %s, IPv6 %s, MAC %s", g.Name, g.IP, g.IPv6, g.MAC)
+}
+
+// IPs returns all the IP addresses of host gateway.
+func (g *GatewayConfig) IPs() []net.IP {
+	var ips []net.IP
+	if g.IP != nil {
+		ips = append(ips, g.IP)
+	}
+	if g.IPv6 != nil {
+		ips = append(ips, g.IPv6)
+	}
+	return ips
 }
 
 type AdapterNetConfig struct {
@@ -77,8 +105,14 @@ type NodeConfig struct {
 	// The CIDR block to allocate Pod IPs out of.
 	// It's nil for the networkPolicyOnly trafficEncapMode which doesn't do IPAM.
 	PodCIDR *net.IPNet
+	// The IPv6 CIDR block to allocate Pod IPs out of, next to PodCIDR. It's
+	// nil if the Node is not assigned an IPv6 Pod CIDR.
+	PodIPv6CIDR *net.IPNet
 	// The Node's IP used in Kubernetes. It has the network mask information.
 	NodeIPAddr *net.IPNet
+	// The Node's IPv6 address used in Kubernetes. It has the network mask
+	// information. It's nil if the Node has no IPv6 address.
+	NodeIPv6Addr *net.IPNet
 	// Set either via defaultMTU config in antrea.yaml or auto discovered.
 	// Auto discovery will use MTU value of the Node's primary interface.
 	// For Encap and Hybrid mode, Node MTU will be adjusted to account for encap header.
@@ -90,8 +124,20 @@ type NodeConfig struct {
 }
 
 func (n *NodeConfig) String() string {
-	return fmt.Sprintf("NodeName: %s, OVSBridge: %s, PodCIDR: %s, NodeIP: %s, Gateway: %s",
-		n.Name, n.OVSBridge, n.PodCIDR, n.NodeIPAddr, n.GatewayConfig)
+	return fmt.Sprintf("NodeName: %s, OVSBridge: %s, PodCIDR: %s, PodIPv6CIDR: %s, NodeIP: %s, NodeIPv6: %s, Gateway: %s",
+		n.Name, n.OVSBridge, n.PodCIDR, n.PodIPv6CIDR, n.NodeIPAddr, n.NodeIPv6Addr, n.GatewayConfig)
+}
+
+// PodCIDRs returns all the Pod CIDRs of the Node.
+func (n *NodeConfig) PodCIDRs() []*net.IPNet {
+	var cidrs []*net.IPNet
+	if n.PodCIDR != nil {
+		cidrs = append(cidrs, n.PodCIDR)
+	}
+	if n.PodIPv6CIDR != nil {
+		cidrs = append(cidrs, n.PodIPv6CIDR)
+	}
+	return cidrs
 }
 
 // User provided network configuration parameters.
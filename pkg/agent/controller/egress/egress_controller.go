@@ -0,0 +1,708 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/route"
+	"github.com/vmware-tanzu/antrea/pkg/agent/types"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
+	egressv1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	egressinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha2"
+	egresslisters "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha2"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+const (
+	controllerName = "AntreaAgentEgressController"
+	// Interval of reprocessing every Egress. It's needed to detect the
+	// Egress IPs which are configured on or removed from this Node.
+	egressResyncPeriod = 60 * time.Second
+	// How long to wait before retrying the processing of an Egress change.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+	// maxEgressIPMark is the maximum number of Egress IPs that can be
+	// configured on a Node, limited by the bits of packet mark reserved
+	// for them.
+	maxEgressIPMark = types.SNATIPMarkMask
+)
+
+// egressState keeps the realized state of an Egress on this Node.
+type egressState struct {
+	egressIP net.IP
+	// mark is the ID of the Egress IP if it's configured on this Node,
+	// otherwise it's 0.
+	mark uint32
+	// localPods maps the keys of the local Pods to which the Egress is
+	// applied to the OVS ports of the Pods.
+	localPods map[string]uint32
+	// remotePodIPs is the set of the IPs of the remote Pods to which the
+	// Egress is applied. It's only populated when the Egress IP is on this
+	// Node.
+	remotePodIPs sets.String
+}
+
+// egressIPState keeps the state of an Egress IP configured on this Node,
+// which might be shared by multiple Egresses.
+type egressIPState struct {
+	mark     uint32
+	egresses sets.String
+}
+
+// Controller is responsible for realizing Egresses on this Node. For the local
+// Pods to which an Egress is applied, it installs the flows to mark the traffic
+// to the external network with the ID of the Egress IP if the Egress IP is on
+// this Node, so the traffic will be SNAT'd by the iptables rule installed for
+// the Egress IP, otherwise it installs the flows to tunnel the traffic to the
+// Node which owns the Egress IP. For the remote Pods to which an Egress with an
+// Egress IP on this Node is applied, it installs the flows to mark the traffic
+// tunnelled from them.
+// If a Pod is selected by multiple Egresses, only the Egress with the smallest
+// name is applied to it.
+type Controller struct {
+	ofClient              openflow.Client
+	routeClient           route.Interface
+	ifaceStore            interfacestore.InterfaceStore
+	nodeName              string
+	egressLister          egresslisters.EgressLister
+	egressListerSynced    cache.InformerSynced
+	podLister             corelisters.PodLister
+	podListerSynced       cache.InformerSynced
+	namespaceLister       corelisters.NamespaceLister
+	namespaceListerSynced cache.InformerSynced
+	queue                 workqueue.RateLimitingInterface
+	// isLocalIP returns whether the provided IP is configured on this Node.
+	isLocalIP func(ip net.IP) bool
+
+	// The following fields are only accessed by the single worker, so they
+	// are not protected by a lock.
+	egressStates   map[string]*egressState
+	egressIPStates map[string]*egressIPState
+	// localPodOwners and remotePodOwners map the keys of the local Pods and
+	// the IPs of the remote Pods to the names of the Egresses whose flows are
+	// currently installed for them.
+	localPodOwners  map[string]string
+	remotePodOwners map[string]string
+	usedMarks       sets.Int
+}
+
+// NewEgressController instantiates a new Controller object which will process
+// Egress, Pod and Namespace events and realize the Egresses on this Node.
+func NewEgressController(
+	ofClient openflow.Client,
+	routeClient route.Interface,
+	ifaceStore interfacestore.InterfaceStore,
+	nodeName string,
+	informerFactory informers.SharedInformerFactory,
+	egressInformer egressinformers.EgressInformer) *Controller {
+	podInformer := informerFactory.Core().V1().Pods()
+	namespaceInformer := informerFactory.Core().V1().Namespaces()
+	c := &Controller{
+		ofClient:              ofClient,
+		routeClient:           routeClient,
+		ifaceStore:            ifaceStore,
+		nodeName:              nodeName,
+		egressLister:          egressInformer.Lister(),
+		egressListerSynced:    egressInformer.Informer().HasSynced,
+		podLister:             podInformer.Lister(),
+		podListerSynced:       podInformer.Informer().HasSynced,
+		namespaceLister:       namespaceInformer.Lister(),
+		namespaceListerSynced: namespaceInformer.Informer().HasSynced,
+		queue:                 workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egress"),
+		isLocalIP:             isLocalIP,
+		egressStates:          map[string]*egressState{},
+		egressIPStates:        map[string]*egressIPState{},
+		localPodOwners:        map[string]string{},
+		remotePodOwners:       map[string]string{},
+		usedMarks:             sets.NewInt(),
+	}
+	egressInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addEgress,
+			UpdateFunc: c.updateEgress,
+			DeleteFunc: c.deleteEgress,
+		},
+		egressResyncPeriod,
+	)
+	podInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addPod,
+			UpdateFunc: c.updatePod,
+			DeleteFunc: c.deletePod,
+		},
+	)
+	namespaceInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addNamespace,
+			UpdateFunc: c.updateNamespace,
+			DeleteFunc: c.deleteNamespace,
+		},
+	)
+	return c
+}
+
+func isLocalIP(ip net.IP) bool {
+	_, _, err := util.GetIPNetDeviceFromIP(ip)
+	return err == nil
+}
+
+// enqueueAllEgresses adds all Egresses to the work queue. As the selection of
+// an Egress might affect the Pods to which other Egresses are applied, all
+// Egresses are processed when any Egress changes.
+func (c *Controller) enqueueAllEgresses() {
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Egresses: %v", err)
+		return
+	}
+	for _, egress := range egresses {
+		c.queue.Add(egress.Name)
+	}
+}
+
+func (c *Controller) addEgress(obj interface{}) {
+	egress := obj.(*egressv1alpha2.Egress)
+	klog.V(2).Infof("Processing Egress %s ADD event", egress.Name)
+	c.queue.Add(egress.Name)
+	c.enqueueAllEgresses()
+}
+
+func (c *Controller) updateEgress(_, cur interface{}) {
+	egress := cur.(*egressv1alpha2.Egress)
+	klog.V(2).Infof("Processing Egress %s UPDATE event", egress.Name)
+	c.enqueueAllEgresses()
+}
+
+func (c *Controller) deleteEgress(old interface{}) {
+	egress, ok := old.(*egressv1alpha2.Egress)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Egress, invalid type: %v", old)
+			return
+		}
+		egress, ok = tombstone.Obj.(*egressv1alpha2.Egress)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Egress, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing Egress %s DELETE event", egress.Name)
+	c.queue.Add(egress.Name)
+	c.enqueueAllEgresses()
+}
+
+// enqueueEgressesForPod adds the Egresses which select the provided Pod to the
+// work queue.
+func (c *Controller) enqueueEgressesForPod(pod *corev1.Pod) {
+	namespace, err := c.namespaceLister.Get(pod.Namespace)
+	if err != nil {
+		// The Namespace might have been deleted, nothing to do as
+		// the Pod will be deleted as well.
+		klog.V(2).Infof("Failed to get Namespace %s of Pod %s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Egresses: %v", err)
+		return
+	}
+	for _, egress := range egresses {
+		if egressSelectsPod(egress, pod, namespace) {
+			c.queue.Add(egress.Name)
+		}
+	}
+}
+
+func (c *Controller) addPod(obj interface{}) {
+	pod := obj.(*corev1.Pod)
+	if pod.Spec.HostNetwork || pod.Status.PodIP == "" {
+		return
+	}
+	c.enqueueEgressesForPod(pod)
+}
+
+func (c *Controller) updatePod(old, cur interface{}) {
+	oldPod := old.(*corev1.Pod)
+	curPod := cur.(*corev1.Pod)
+	if curPod.Spec.HostNetwork {
+		return
+	}
+	// Only the labels, the IP and the phase of a Pod affect the Egresses
+	// applied to it.
+	if labels.Equals(oldPod.Labels, curPod.Labels) &&
+		oldPod.Status.PodIP == curPod.Status.PodIP &&
+		isPodTerminated(oldPod) == isPodTerminated(curPod) {
+		return
+	}
+	c.enqueueEgressesForPod(oldPod)
+	c.enqueueEgressesForPod(curPod)
+}
+
+func (c *Controller) deletePod(old interface{}) {
+	pod, ok := old.(*corev1.Pod)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Pod, invalid type: %v", old)
+			return
+		}
+		pod, ok = tombstone.Obj.(*corev1.Pod)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Pod, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	if pod.Spec.HostNetwork {
+		return
+	}
+	c.enqueueEgressesForPod(pod)
+}
+
+// enqueueEgressesForNamespace adds the Egresses whose NamespaceSelector matches
+// the provided labels of a Namespace to the work queue.
+func (c *Controller) enqueueEgressesForNamespace(namespaceLabels labels.Set) {
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Egresses: %v", err)
+		return
+	}
+	for _, egress := range egresses {
+		if egress.Spec.AppliedTo.NamespaceSelector == nil {
+			continue
+		}
+		if selectorMatches(egress.Spec.AppliedTo.NamespaceSelector, namespaceLabels) {
+			c.queue.Add(egress.Name)
+		}
+	}
+}
+
+func (c *Controller) addNamespace(obj interface{}) {
+	namespace := obj.(*corev1.Namespace)
+	c.enqueueEgressesForNamespace(namespace.Labels)
+}
+
+func (c *Controller) updateNamespace(old, cur interface{}) {
+	oldNamespace := old.(*corev1.Namespace)
+	curNamespace := cur.(*corev1.Namespace)
+	if labels.Equals(oldNamespace.Labels, curNamespace.Labels) {
+		return
+	}
+	c.enqueueEgressesForNamespace(oldNamespace.Labels)
+	c.enqueueEgressesForNamespace(curNamespace.Labels)
+}
+
+func (c *Controller) deleteNamespace(old interface{}) {
+	namespace, ok := old.(*corev1.Namespace)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Namespace, invalid type: %v", old)
+			return
+		}
+		namespace, ok = tombstone.Obj.(*corev1.Namespace)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Namespace, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	c.enqueueEgressesForNamespace(namespace.Labels)
+}
+
+// Run will create a worker (go routine) which will process the Egress events
+// from the workqueue. Only one worker is used, as the realized state of the
+// Egresses is shared.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", controllerName)
+	defer klog.Infof("Shutting down %s", controllerName)
+
+	klog.Infof("Waiting for caches to sync for %s", controllerName)
+	if !cache.WaitForCacheSync(stopCh, c.egressListerSynced, c.podListerSynced, c.namespaceListerSynced) {
+		klog.Errorf("Unable to sync caches for %s", controllerName)
+		return
+	}
+	klog.Infof("Caches are synced for %s", controllerName)
+
+	go wait.Until(c.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+// worker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(obj)
+
+	// We expect strings (Egress name) to come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd
+		// go into a loop of attempting to process a work item that is invalid.
+		c.queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := c.syncEgress(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again until
+		// another change happens.
+		c.queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Error syncing Egress %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+func (c *Controller) syncEgress(egressName string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing Egress for %s. (%v)", egressName, time.Since(startTime))
+	}()
+
+	egress, err := c.egressLister.Get(egressName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return c.uninstallEgress(egressName)
+		}
+		return err
+	}
+	egressIP := net.ParseIP(egress.Spec.EgressIP)
+	if egressIP == nil || egressIP.To4() == nil {
+		// Retrying doesn't help, just remove the realized state.
+		klog.Errorf("Invalid IPv4 Egress IP %s of Egress %s", egress.Spec.EgressIP, egressName)
+		return c.uninstallEgress(egressName)
+	}
+	isLocal := c.isLocalIP(egressIP)
+
+	state, exists := c.egressStates[egressName]
+	if exists && (!state.egressIP.Equal(egressIP) || (state.mark != 0) != isLocal) {
+		// The Egress IP or its location has changed, all the realized
+		// flows need to be re-installed.
+		if err := c.uninstallEgress(egressName); err != nil {
+			return err
+		}
+		exists = false
+	}
+	if !exists {
+		state = &egressState{
+			egressIP:     egressIP,
+			localPods:    map[string]uint32{},
+			remotePodIPs: sets.NewString(),
+		}
+		if isLocal {
+			mark, err := c.acquireEgressIPMark(egressName, egressIP)
+			if err != nil {
+				return err
+			}
+			state.mark = mark
+		}
+		c.egressStates[egressName] = state
+	}
+
+	desiredLocalPods, desiredRemotePodIPs, err := c.getAppliedPods(egress, state.mark != 0)
+	if err != nil {
+		return err
+	}
+
+	for podKey, ofPort := range desiredLocalPods {
+		if curOFPort, ok := state.localPods[podKey]; ok && curOFPort == ofPort && c.localPodOwners[podKey] == egressName {
+			continue
+		}
+		if err := c.ofClient.InstallPodSNATFlows(ofPort, egressIP, state.mark); err != nil {
+			return fmt.Errorf("error installing SNAT flows for Pod %s: %v", podKey, err)
+		}
+		state.localPods[podKey] = ofPort
+		c.localPodOwners[podKey] = egressName
+	}
+	for podKey, ofPort := range state.localPods {
+		if _, ok := desiredLocalPods[podKey]; ok {
+			continue
+		}
+		// The flows might have been overridden by another Egress which
+		// is applied to the Pod now.
+		if c.localPodOwners[podKey] == egressName {
+			if err := c.ofClient.UninstallPodSNATFlows(ofPort); err != nil {
+				return fmt.Errorf("error uninstalling SNAT flows for Pod %s: %v", podKey, err)
+			}
+			delete(c.localPodOwners, podKey)
+		}
+		delete(state.localPods, podKey)
+	}
+
+	for podIP := range desiredRemotePodIPs {
+		if state.remotePodIPs.Has(podIP) && c.remotePodOwners[podIP] == egressName {
+			continue
+		}
+		if err := c.ofClient.InstallRemotePodSNATFlows(net.ParseIP(podIP), state.mark); err != nil {
+			return fmt.Errorf("error installing SNAT flows for remote Pod IP %s: %v", podIP, err)
+		}
+		state.remotePodIPs.Insert(podIP)
+		c.remotePodOwners[podIP] = egressName
+	}
+	for podIP := range state.remotePodIPs.Difference(desiredRemotePodIPs) {
+		if c.remotePodOwners[podIP] == egressName {
+			if err := c.ofClient.UninstallRemotePodSNATFlows(net.ParseIP(podIP)); err != nil {
+				return fmt.Errorf("error uninstalling SNAT flows for remote Pod IP %s: %v", podIP, err)
+			}
+			delete(c.remotePodOwners, podIP)
+		}
+		state.remotePodIPs.Delete(podIP)
+	}
+	return nil
+}
+
+// uninstallEgress removes all the flows and the Egress IP mark realized for the
+// provided Egress.
+func (c *Controller) uninstallEgress(egressName string) error {
+	state, exists := c.egressStates[egressName]
+	if !exists {
+		return nil
+	}
+	for podKey, ofPort := range state.localPods {
+		if c.localPodOwners[podKey] == egressName {
+			if err := c.ofClient.UninstallPodSNATFlows(ofPort); err != nil {
+				return fmt.Errorf("error uninstalling SNAT flows for Pod %s: %v", podKey, err)
+			}
+			delete(c.localPodOwners, podKey)
+		}
+		delete(state.localPods, podKey)
+	}
+	for podIP := range state.remotePodIPs {
+		if c.remotePodOwners[podIP] == egressName {
+			if err := c.ofClient.UninstallRemotePodSNATFlows(net.ParseIP(podIP)); err != nil {
+				return fmt.Errorf("error uninstalling SNAT flows for remote Pod IP %s: %v", podIP, err)
+			}
+			delete(c.remotePodOwners, podIP)
+		}
+		state.remotePodIPs.Delete(podIP)
+	}
+	if state.mark != 0 {
+		if err := c.releaseEgressIPMark(egressName, state.egressIP); err != nil {
+			return err
+		}
+	}
+	delete(c.egressStates, egressName)
+	return nil
+}
+
+// acquireEgressIPMark returns the mark of the provided Egress IP on this Node,
+// allocating a mark and installing the SNAT rule for the Egress IP if it's not
+// used by any Egress yet.
+func (c *Controller) acquireEgressIPMark(egressName string, egressIP net.IP) (uint32, error) {
+	ipState, exists := c.egressIPStates[egressIP.String()]
+	if !exists {
+		mark, err := c.allocateMark()
+		if err != nil {
+			return 0, err
+		}
+		if err := c.routeClient.AddSNATRule(egressIP, mark); err != nil {
+			return 0, fmt.Errorf("error adding SNAT rule for Egress IP %s: %v", egressIP, err)
+		}
+		c.usedMarks.Insert(int(mark))
+		ipState = &egressIPState{mark: mark, egresses: sets.NewString()}
+		c.egressIPStates[egressIP.String()] = ipState
+	}
+	ipState.egresses.Insert(egressName)
+	return ipState.mark, nil
+}
+
+// releaseEgressIPMark releases the mark of the provided Egress IP used by the
+// Egress, and removes the SNAT rule for the Egress IP if it's not used by any
+// Egress.
+func (c *Controller) releaseEgressIPMark(egressName string, egressIP net.IP) error {
+	ipState, exists := c.egressIPStates[egressIP.String()]
+	if !exists {
+		return nil
+	}
+	ipState.egresses.Delete(egressName)
+	if ipState.egresses.Len() > 0 {
+		return nil
+	}
+	if err := c.routeClient.DeleteSNATRule(ipState.mark); err != nil {
+		ipState.egresses.Insert(egressName)
+		return fmt.Errorf("error deleting SNAT rule for Egress IP %s: %v", egressIP, err)
+	}
+	c.usedMarks.Delete(int(ipState.mark))
+	delete(c.egressIPStates, egressIP.String())
+	return nil
+}
+
+func (c *Controller) allocateMark() (uint32, error) {
+	for mark := 1; mark <= maxEgressIPMark; mark++ {
+		if !c.usedMarks.Has(mark) {
+			return uint32(mark), nil
+		}
+	}
+	return 0, fmt.Errorf("no mark is available for Egress IP, the number of Egress IPs on this Node exceeds %d", maxEgressIPMark)
+}
+
+// getAppliedPods returns the OVS ports of the local Pods and the IPs of the
+// remote Pods to which the provided Egress is applied. The remote Pods are
+// only needed when the Egress IP is on this Node.
+func (c *Controller) getAppliedPods(egress *egressv1alpha2.Egress, includeRemotePods bool) (map[string]uint32, sets.String, error) {
+	pods, err := c.selectPods(egress)
+	if err != nil {
+		return nil, nil, err
+	}
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+	localPods := map[string]uint32{}
+	remotePodIPs := sets.NewString()
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork || pod.Status.PodIP == "" || isPodTerminated(pod) {
+			continue
+		}
+		if pod.Spec.NodeName != c.nodeName && !includeRemotePods {
+			continue
+		}
+		if !c.isEgressEffectiveForPod(egress, egresses, pod) {
+			continue
+		}
+		if pod.Spec.NodeName == c.nodeName {
+			podKey := k8s.NamespacedName(pod.Namespace, pod.Name)
+			ifaces := c.ifaceStore.GetContainerInterfacesByPod(pod.Name, pod.Namespace)
+			if len(ifaces) == 0 {
+				// The CNI server has not processed the Pod yet. The
+				// Egress will be processed again when the Pod's IP
+				// is reported.
+				klog.V(2).Infof("Interface of Pod %s not found", podKey)
+				continue
+			}
+			localPods[podKey] = uint32(ifaces[0].OFPort)
+		} else {
+			remotePodIPs.Insert(pod.Status.PodIP)
+		}
+	}
+	return localPods, remotePodIPs, nil
+}
+
+// selectPods returns the Pods selected by the AppliedTo of the provided Egress.
+func (c *Controller) selectPods(egress *egressv1alpha2.Egress) ([]*corev1.Pod, error) {
+	appliedTo := egress.Spec.AppliedTo
+	if appliedTo.PodSelector == nil && appliedTo.NamespaceSelector == nil {
+		return nil, nil
+	}
+	podSelector := labels.Everything()
+	if appliedTo.PodSelector != nil {
+		var err error
+		podSelector, err = metav1.LabelSelectorAsSelector(appliedTo.PodSelector)
+		if err != nil {
+			// The selector is validated by the CRD schema, retrying
+			// doesn't help.
+			klog.Errorf("Invalid PodSelector of Egress %s: %v", egress.Name, err)
+			return nil, nil
+		}
+	}
+	if appliedTo.NamespaceSelector == nil {
+		return c.podLister.List(podSelector)
+	}
+	nsSelector, err := metav1.LabelSelectorAsSelector(appliedTo.NamespaceSelector)
+	if err != nil {
+		klog.Errorf("Invalid NamespaceSelector of Egress %s: %v", egress.Name, err)
+		return nil, nil
+	}
+	namespaces, err := c.namespaceLister.List(nsSelector)
+	if err != nil {
+		return nil, err
+	}
+	var pods []*corev1.Pod
+	for _, ns := range namespaces {
+		nsPods, err := c.podLister.Pods(ns.Name).List(podSelector)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, nsPods...)
+	}
+	return pods, nil
+}
+
+// isEgressEffectiveForPod returns whether the provided Egress is the one
+// applied to the Pod, i.e. no other Egress with a smaller name selects the Pod.
+func (c *Controller) isEgressEffectiveForPod(egress *egressv1alpha2.Egress, egresses []*egressv1alpha2.Egress, pod *corev1.Pod) bool {
+	var namespace *corev1.Namespace
+	for _, other := range egresses {
+		if other.Name >= egress.Name {
+			continue
+		}
+		if namespace == nil {
+			var err error
+			namespace, err = c.namespaceLister.Get(pod.Namespace)
+			if err != nil {
+				return false
+			}
+		}
+		if egressSelectsPod(other, pod, namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// egressSelectsPod returns whether the AppliedTo of the provided Egress selects
+// the Pod, which is in the provided Namespace.
+func egressSelectsPod(egress *egressv1alpha2.Egress, pod *corev1.Pod, namespace *corev1.Namespace) bool {
+	appliedTo := egress.Spec.AppliedTo
+	if appliedTo.PodSelector == nil && appliedTo.NamespaceSelector == nil {
+		return false
+	}
+	if appliedTo.NamespaceSelector != nil && !selectorMatches(appliedTo.NamespaceSelector, namespace.Labels) {
+		return false
+	}
+	if appliedTo.PodSelector != nil && !selectorMatches(appliedTo.PodSelector, pod.Labels) {
+		return false
+	}
+	return true
+}
+
+func selectorMatches(labelSelector *metav1.LabelSelector, labelSet labels.Set) bool {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labelSet)
+}
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
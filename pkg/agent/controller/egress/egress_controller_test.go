@@ -0,0 +1,205 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	routetest "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
+	egressv1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+const (
+	fakeNode       = "node1"
+	fakeRemoteNode = "node2"
+	fakeLocalIP    = "1.1.1.1"
+	fakeRemoteIP   = "1.1.1.2"
+	fakeNamespace  = "ns1"
+)
+
+var (
+	webLabels = map[string]string{"app": "web"}
+	dbLabels  = map[string]string{"app": "db"}
+)
+
+type fakeController struct {
+	*Controller
+	mockOFClient    *openflowtest.MockClient
+	mockRouteClient *routetest.MockInterface
+	egressStore     cache.Store
+	podStore        cache.Store
+	namespaceStore  cache.Store
+}
+
+func newFakeController(t *testing.T) *fakeController {
+	controller := gomock.NewController(t)
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockRouteClient := routetest.NewMockInterface(controller)
+
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(fakeversioned.NewSimpleClientset(), 0)
+	egressInformer := crdInformerFactory.Core().V1alpha2().Egresses()
+	ifaceStore := interfacestore.NewInterfaceStore()
+	c := NewEgressController(mockOFClient, mockRouteClient, ifaceStore, fakeNode, informerFactory, egressInformer)
+	c.isLocalIP = func(ip net.IP) bool {
+		return ip.String() == fakeLocalIP
+	}
+	namespaceStore := informerFactory.Core().V1().Namespaces().Informer().GetStore()
+	namespaceStore.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fakeNamespace}})
+	return &fakeController{
+		Controller:      c,
+		mockOFClient:    mockOFClient,
+		mockRouteClient: mockRouteClient,
+		egressStore:     egressInformer.Informer().GetStore(),
+		podStore:        informerFactory.Core().V1().Pods().Informer().GetStore(),
+		namespaceStore:  namespaceStore,
+	}
+}
+
+func (c *fakeController) addLocalPod(pod *corev1.Pod, ofPort int32) {
+	c.podStore.Add(pod)
+	containerConfig := interfacestore.NewContainerInterface(pod.Name, pod.Name, pod.Name, pod.Namespace, nil, []net.IP{net.ParseIP(pod.Status.PodIP)})
+	containerConfig.OVSPortConfig = &interfacestore.OVSPortConfig{OFPort: ofPort}
+	c.ifaceStore.AddInterface(containerConfig)
+}
+
+func newEgress(name, egressIP string, podLabels map[string]string) *egressv1alpha2.Egress {
+	return &egressv1alpha2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: egressv1alpha2.EgressSpec{
+			AppliedTo: egressv1alpha2.AppliedTo{
+				PodSelector: &metav1.LabelSelector{MatchLabels: podLabels},
+			},
+			EgressIP: egressIP,
+		},
+	}
+}
+
+func newPod(name, nodeName, podIP string, podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: fakeNamespace, Labels: podLabels},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{PodIP: podIP},
+	}
+}
+
+func TestSyncEgressWithLocalEgressIP(t *testing.T) {
+	c := newFakeController(t)
+	egress := newEgress("egressA", fakeLocalIP, webLabels)
+	c.egressStore.Add(egress)
+	c.addLocalPod(newPod("pod1", fakeNode, "10.10.0.2", webLabels), 10)
+	c.addLocalPod(newPod("pod2", fakeNode, "10.10.0.3", dbLabels), 11)
+	c.podStore.Add(newPod("pod3", fakeRemoteNode, "10.10.1.2", webLabels))
+
+	egressIP := net.ParseIP(fakeLocalIP)
+	c.mockRouteClient.EXPECT().AddSNATRule(egressIP, uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(10), egressIP, uint32(1))
+	c.mockOFClient.EXPECT().InstallRemotePodSNATFlows(net.ParseIP("10.10.1.2"), uint32(1))
+	require.NoError(t, c.syncEgress(egress.Name))
+
+	// Syncing an unchanged Egress should not install the flows again.
+	require.NoError(t, c.syncEgress(egress.Name))
+
+	// The remote Pod doesn't match the Egress anymore.
+	c.podStore.Update(newPod("pod3", fakeRemoteNode, "10.10.1.2", dbLabels))
+	c.mockOFClient.EXPECT().UninstallRemotePodSNATFlows(net.ParseIP("10.10.1.2"))
+	require.NoError(t, c.syncEgress(egress.Name))
+
+	c.egressStore.Delete(egress)
+	c.mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(10))
+	c.mockRouteClient.EXPECT().DeleteSNATRule(uint32(1))
+	require.NoError(t, c.syncEgress(egress.Name))
+	assert.Empty(t, c.egressStates)
+	assert.Empty(t, c.egressIPStates)
+	assert.Empty(t, c.localPodOwners)
+	assert.Empty(t, c.remotePodOwners)
+}
+
+func TestSyncEgressWithRemoteEgressIP(t *testing.T) {
+	c := newFakeController(t)
+	egress := newEgress("egressA", fakeRemoteIP, webLabels)
+	c.egressStore.Add(egress)
+	c.addLocalPod(newPod("pod1", fakeNode, "10.10.0.2", webLabels), 10)
+	c.podStore.Add(newPod("pod3", fakeRemoteNode, "10.10.1.2", webLabels))
+
+	// The traffic of the local Pod should be tunnelled to the Node owning
+	// the Egress IP, and no flows are needed for the remote Pod.
+	egressIP := net.ParseIP(fakeRemoteIP)
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(10), egressIP, uint32(0))
+	require.NoError(t, c.syncEgress(egress.Name))
+
+	// The Egress IP is moved to this Node.
+	updatedEgress := newEgress("egressA", fakeLocalIP, webLabels)
+	c.egressStore.Update(updatedEgress)
+	newEgressIP := net.ParseIP(fakeLocalIP)
+	c.mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(10))
+	c.mockRouteClient.EXPECT().AddSNATRule(newEgressIP, uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(10), newEgressIP, uint32(1))
+	c.mockOFClient.EXPECT().InstallRemotePodSNATFlows(net.ParseIP("10.10.1.2"), uint32(1))
+	require.NoError(t, c.syncEgress(egress.Name))
+}
+
+func TestSyncEgressWithConflictingEgresses(t *testing.T) {
+	c := newFakeController(t)
+	egressA := newEgress("egressA", fakeLocalIP, webLabels)
+	egressB := newEgress("egressB", fakeLocalIP, nil)
+	egressB.Spec.AppliedTo.PodSelector = &metav1.LabelSelector{}
+	c.egressStore.Add(egressA)
+	c.egressStore.Add(egressB)
+	c.addLocalPod(newPod("pod1", fakeNode, "10.10.0.2", webLabels), 10)
+	c.addLocalPod(newPod("pod2", fakeNode, "10.10.0.3", dbLabels), 11)
+
+	// Both Egresses share the same Egress IP, so only one SNAT rule is
+	// added. pod1 is selected by both Egresses and egressA takes effect.
+	egressIP := net.ParseIP(fakeLocalIP)
+	c.mockRouteClient.EXPECT().AddSNATRule(egressIP, uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(10), egressIP, uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(11), egressIP, uint32(1))
+	require.NoError(t, c.syncEgress(egressA.Name))
+	require.NoError(t, c.syncEgress(egressB.Name))
+	assert.Equal(t, "egressA", c.localPodOwners["ns1/pod1"])
+	assert.Equal(t, "egressB", c.localPodOwners["ns1/pod2"])
+
+	// Deleting egressB should only remove the flows of pod2 and keep the
+	// SNAT rule which is still used by egressA.
+	c.egressStore.Delete(egressB)
+	c.mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(11))
+	require.NoError(t, c.syncEgress(egressB.Name))
+	assert.Len(t, c.egressIPStates, 1)
+}
+
+func TestSyncEgressWithInvalidEgressIP(t *testing.T) {
+	c := newFakeController(t)
+	egress := newEgress("egressA", "1.1.1", webLabels)
+	c.egressStore.Add(egress)
+	c.addLocalPod(newPod("pod1", fakeNode, "10.10.0.2", webLabels), 10)
+
+	require.NoError(t, c.syncEgress(egress.Name))
+	assert.Empty(t, c.egressStates)
+}
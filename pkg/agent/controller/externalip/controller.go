@@ -0,0 +1,503 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalip
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	corev1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	coreinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha2"
+	corelistersv1alpha2 "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha2"
+	iputil "github.com/vmware-tanzu/antrea/pkg/util/ip"
+)
+
+const (
+	controllerName = "AntreaAgentExternalIPController"
+	// How long to wait before retrying the processing of a Service change.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+)
+
+// ipAssigner assigns IPs to this Node and announces them to the external
+// network.
+type ipAssigner interface {
+	// AssignIP assigns the IP to this Node and announces it. It's called
+	// again for an assigned IP to announce it again.
+	AssignIP(ip net.IP) error
+	// UnassignIP removes the IP from this Node.
+	UnassignIP(ip net.IP) error
+	// AssignedIPs returns the IPs assigned to this Node.
+	AssignedIPs() (sets.String, error)
+}
+
+// Controller is responsible for announcing the ingress IPs allocated from
+// ExternalIPPools to LoadBalancer Services. For each ingress IP, one Node is
+// selected among the ready Nodes selected by the ExternalIPPool, and the agent
+// of that Node assigns the IP to the Node and announces it with gratuitous ARP.
+// When the Node becomes not ready or is deleted, another Node is selected and
+// takes over the IP.
+type Controller struct {
+	nodeName                   string
+	ipAssigner                 ipAssigner
+	serviceLister              corelisters.ServiceLister
+	serviceListerSynced        cache.InformerSynced
+	nodeLister                 corelisters.NodeLister
+	nodeListerSynced           cache.InformerSynced
+	externalIPPoolLister       corelistersv1alpha2.ExternalIPPoolLister
+	externalIPPoolListerSynced cache.InformerSynced
+	queue                      workqueue.RateLimitingInterface
+
+	// assignedIPs maps the keys of the Services to the IPs assigned to this
+	// Node for them. It's only accessed by the single worker.
+	assignedIPs map[string]sets.String
+}
+
+// NewExternalIPController instantiates a new Controller object which will
+// process Service, Node and ExternalIPPool events and announce the external IPs
+// of the Services which this Node is selected for. nodeTransportIP is the IP of
+// the Node, whose interface is used to announce the external IPs.
+func NewExternalIPController(
+	nodeName string,
+	nodeTransportIP net.IP,
+	informerFactory informers.SharedInformerFactory,
+	externalIPPoolInformer coreinformers.ExternalIPPoolInformer) (*Controller, error) {
+	assigner, err := newIPAssigner(nodeTransportIP)
+	if err != nil {
+		return nil, fmt.Errorf("error creating IP assigner: %v", err)
+	}
+	return newController(nodeName, assigner, informerFactory, externalIPPoolInformer), nil
+}
+
+func newController(
+	nodeName string,
+	assigner ipAssigner,
+	informerFactory informers.SharedInformerFactory,
+	externalIPPoolInformer coreinformers.ExternalIPPoolInformer) *Controller {
+	serviceInformer := informerFactory.Core().V1().Services()
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	c := &Controller{
+		nodeName:                   nodeName,
+		ipAssigner:                 assigner,
+		serviceLister:              serviceInformer.Lister(),
+		serviceListerSynced:        serviceInformer.Informer().HasSynced,
+		nodeLister:                 nodeInformer.Lister(),
+		nodeListerSynced:           nodeInformer.Informer().HasSynced,
+		externalIPPoolLister:       externalIPPoolInformer.Lister(),
+		externalIPPoolListerSynced: externalIPPoolInformer.Informer().HasSynced,
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "externalIP"),
+		assignedIPs:                map[string]sets.String{},
+	}
+	serviceInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addService,
+			UpdateFunc: c.updateService,
+			DeleteFunc: c.deleteService,
+		},
+	)
+	nodeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addNode,
+			UpdateFunc: c.updateNode,
+			DeleteFunc: c.deleteNode,
+		},
+	)
+	externalIPPoolInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addExternalIPPool,
+			UpdateFunc: c.updateExternalIPPool,
+			DeleteFunc: c.deleteExternalIPPool,
+		},
+	)
+	return c
+}
+
+// requestsExternalIP returns whether the Service is annotated to request an IP
+// from an ExternalIPPool.
+func requestsExternalIP(svc *corev1.Service) bool {
+	_, exists := svc.Annotations[corev1alpha2.ServiceExternalIPPoolAnnotationKey]
+	return exists
+}
+
+func (c *Controller) enqueueService(svc *corev1.Service) {
+	key, err := cache.MetaNamespaceKeyFunc(svc)
+	if err != nil {
+		klog.Errorf("Failed to get key of Service %s/%s: %v", svc.Namespace, svc.Name, err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueServices adds the Services which request IPs from the ExternalIPPool
+// to the work queue. All the Services which request IPs are added if poolName
+// is empty.
+func (c *Controller) enqueueServices(poolName string) {
+	services, err := c.serviceLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Services: %v", err)
+		return
+	}
+	for _, svc := range services {
+		if !requestsExternalIP(svc) {
+			continue
+		}
+		if poolName == "" || svc.Annotations[corev1alpha2.ServiceExternalIPPoolAnnotationKey] == poolName {
+			c.enqueueService(svc)
+		}
+	}
+}
+
+func (c *Controller) addService(obj interface{}) {
+	svc := obj.(*corev1.Service)
+	if !requestsExternalIP(svc) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s ADD event", svc.Namespace, svc.Name)
+	c.enqueueService(svc)
+}
+
+func (c *Controller) updateService(old, cur interface{}) {
+	oldSvc := old.(*corev1.Service)
+	curSvc := cur.(*corev1.Service)
+	if !requestsExternalIP(oldSvc) && !requestsExternalIP(curSvc) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s UPDATE event", curSvc.Namespace, curSvc.Name)
+	c.enqueueService(curSvc)
+}
+
+func (c *Controller) deleteService(old interface{}) {
+	svc, ok := old.(*corev1.Service)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Service, invalid type: %v", old)
+			return
+		}
+		svc, ok = tombstone.Obj.(*corev1.Service)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Service, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	if !requestsExternalIP(svc) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s DELETE event", svc.Namespace, svc.Name)
+	c.enqueueService(svc)
+}
+
+func (c *Controller) addNode(obj interface{}) {
+	node := obj.(*corev1.Node)
+	klog.V(2).Infof("Processing Node %s ADD event", node.Name)
+	c.enqueueServices("")
+}
+
+func (c *Controller) updateNode(old, cur interface{}) {
+	oldNode := old.(*corev1.Node)
+	curNode := cur.(*corev1.Node)
+	// Only the labels and the readiness of a Node affect the selection of
+	// the Nodes announcing the IPs.
+	if labels.Equals(oldNode.Labels, curNode.Labels) && isNodeReady(oldNode) == isNodeReady(curNode) {
+		return
+	}
+	klog.V(2).Infof("Processing Node %s UPDATE event", curNode.Name)
+	c.enqueueServices("")
+}
+
+func (c *Controller) deleteNode(old interface{}) {
+	node, ok := old.(*corev1.Node)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Node, invalid type: %v", old)
+			return
+		}
+		node, ok = tombstone.Obj.(*corev1.Node)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Node, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing Node %s DELETE event", node.Name)
+	c.enqueueServices("")
+}
+
+func (c *Controller) addExternalIPPool(obj interface{}) {
+	pool := obj.(*corev1alpha2.ExternalIPPool)
+	klog.V(2).Infof("Processing ExternalIPPool %s ADD event", pool.Name)
+	c.enqueueServices(pool.Name)
+}
+
+func (c *Controller) updateExternalIPPool(_, cur interface{}) {
+	pool := cur.(*corev1alpha2.ExternalIPPool)
+	klog.V(2).Infof("Processing ExternalIPPool %s UPDATE event", pool.Name)
+	c.enqueueServices(pool.Name)
+}
+
+func (c *Controller) deleteExternalIPPool(old interface{}) {
+	pool, ok := old.(*corev1alpha2.ExternalIPPool)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting ExternalIPPool, invalid type: %v", old)
+			return
+		}
+		pool, ok = tombstone.Obj.(*corev1alpha2.ExternalIPPool)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting ExternalIPPool, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing ExternalIPPool %s DELETE event", pool.Name)
+	c.enqueueServices(pool.Name)
+}
+
+// Run will create a worker (go routine) which will process the Service events
+// from the workqueue, after removing the stale IPs assigned to this Node. Only
+// one worker is used, as the assigned IPs are shared.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", controllerName)
+	defer klog.Infof("Shutting down %s", controllerName)
+
+	klog.Infof("Waiting for caches to sync for %s", controllerName)
+	if !cache.WaitForCacheSync(stopCh, c.serviceListerSynced, c.nodeListerSynced, c.externalIPPoolListerSynced) {
+		klog.Errorf("Unable to sync caches for %s", controllerName)
+		return
+	}
+	klog.Infof("Caches are synced for %s", controllerName)
+
+	if err := c.removeStaleIPs(); err != nil {
+		klog.Errorf("Failed to remove stale external IPs: %v", err)
+	}
+
+	go wait.Until(c.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+// removeStaleIPs removes the IPs assigned to this Node which it is no longer
+// selected for, e.g. because the Services were deleted while the agent was
+// down.
+func (c *Controller) removeStaleIPs() error {
+	assignedIPs, err := c.ipAssigner.AssignedIPs()
+	if err != nil {
+		return err
+	}
+	services, err := c.serviceLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	desiredIPs := sets.NewString()
+	for _, svc := range services {
+		desiredIPs.Insert(c.localIPs(svc).UnsortedList()...)
+	}
+	for ip := range assignedIPs.Difference(desiredIPs) {
+		if err := c.ipAssigner.UnassignIP(net.ParseIP(ip)); err != nil {
+			return err
+		}
+		klog.Infof("Removed stale external IP %s", ip)
+	}
+	return nil
+}
+
+// worker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(obj)
+
+	// We expect strings (Service key) to come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd
+		// go into a loop of attempting to process a work item that is invalid.
+		c.queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := c.syncService(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again until
+		// another change happens.
+		c.queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Error syncing external IPs of Service %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+func (c *Controller) syncService(key string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing external IPs of Service %s. (%v)", key, time.Since(startTime))
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	desiredIPs := sets.NewString()
+	svc, err := c.serviceLister.Services(namespace).Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		desiredIPs = c.localIPs(svc)
+	}
+
+	assignedIPs, exists := c.assignedIPs[key]
+	if !exists {
+		assignedIPs = sets.NewString()
+		c.assignedIPs[key] = assignedIPs
+	}
+	for ip := range assignedIPs.Difference(desiredIPs) {
+		if err := c.ipAssigner.UnassignIP(net.ParseIP(ip)); err != nil {
+			return fmt.Errorf("error removing external IP %s: %v", ip, err)
+		}
+		assignedIPs.Delete(ip)
+		klog.Infof("Removed external IP %s of Service %s from this Node", ip, key)
+	}
+	for ip := range desiredIPs.Difference(assignedIPs) {
+		if err := c.ipAssigner.AssignIP(net.ParseIP(ip)); err != nil {
+			return fmt.Errorf("error assigning external IP %s: %v", ip, err)
+		}
+		assignedIPs.Insert(ip)
+		klog.Infof("Assigned external IP %s of Service %s to this Node", ip, key)
+	}
+	if len(assignedIPs) == 0 {
+		delete(c.assignedIPs, key)
+	}
+	return nil
+}
+
+// localIPs returns the ingress IPs of the Service which were allocated from
+// the requested ExternalIPPool and which this Node is selected to announce.
+func (c *Controller) localIPs(svc *corev1.Service) sets.String {
+	ips := sets.NewString()
+	poolName := svc.Annotations[corev1alpha2.ServiceExternalIPPoolAnnotationKey]
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || poolName == "" || len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return ips
+	}
+	pool, err := c.externalIPPoolLister.Get(poolName)
+	if err != nil {
+		return ips
+	}
+	nodes, err := c.eligibleNodes(pool)
+	if err != nil {
+		klog.Errorf("Failed to get eligible Nodes of ExternalIPPool %s: %v", poolName, err)
+		return ips
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		ip := net.ParseIP(ingress.IP)
+		if ip == nil || !poolContains(pool, ip) {
+			continue
+		}
+		if selectNode(ip.String(), nodes) == c.nodeName {
+			ips.Insert(ip.String())
+		}
+	}
+	return ips
+}
+
+// eligibleNodes returns the names of the ready Nodes selected by the
+// ExternalIPPool.
+func (c *Controller) eligibleNodes(pool *corev1alpha2.ExternalIPPool) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&pool.Spec.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.nodeLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	var nodeNames []string
+	for _, node := range nodes {
+		if isNodeReady(node) {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+	return nodeNames, nil
+}
+
+// poolContains returns whether the IP is in the IP ranges of the
+// ExternalIPPool.
+func poolContains(pool *corev1alpha2.ExternalIPPool, ip net.IP) bool {
+	if ip.To4() == nil {
+		return false
+	}
+	i := iputil.IPv4ToUint32(ip)
+	for _, r := range pool.Spec.IPRanges {
+		first, last, err := iputil.ParseIPv4Range(r.CIDR, r.Start, r.End)
+		if err == nil && i >= first && i <= last {
+			return true
+		}
+	}
+	return false
+}
+
+// selectNode selects the Node which announces the IP among the provided Nodes
+// with rendezvous hashing, i.e. the Node with the highest hash of its name and
+// the IP is selected. All the agents make the same choice independently, and
+// an IP is only moved when the Node announcing it is no longer eligible, or a
+// Node with a higher hash becomes eligible.
+func selectNode(ip string, nodes []string) string {
+	var selected string
+	var maxHash uint32
+	for _, node := range nodes {
+		h := fnv.New32a()
+		h.Write([]byte(node))
+		h.Write([]byte(ip))
+		sum := h.Sum32()
+		if selected == "" || sum > maxHash || (sum == maxHash && node < selected) {
+			selected = node
+			maxHash = sum
+		}
+	}
+	return selected
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
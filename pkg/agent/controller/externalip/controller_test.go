@@ -0,0 +1,175 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalip
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	corev1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+const localNodeName = "node1"
+
+type fakeIPAssigner struct {
+	ips sets.String
+}
+
+func (a *fakeIPAssigner) AssignIP(ip net.IP) error {
+	a.ips.Insert(ip.String())
+	return nil
+}
+
+func (a *fakeIPAssigner) UnassignIP(ip net.IP) error {
+	a.ips.Delete(ip.String())
+	return nil
+}
+
+func (a *fakeIPAssigner) AssignedIPs() (sets.String, error) {
+	return sets.NewString(a.ips.UnsortedList()...), nil
+}
+
+type externalIPController struct {
+	*Controller
+	assigner        *fakeIPAssigner
+	informerFactory informers.SharedInformerFactory
+}
+
+func newExternalIPController(objects []runtime.Object, crdObjects []runtime.Object, assignedIPs ...string) *externalIPController {
+	client := fake.NewSimpleClientset(objects...)
+	crdClient := fakeversioned.NewSimpleClientset(crdObjects...)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	assigner := &fakeIPAssigner{ips: sets.NewString(assignedIPs...)}
+	c := newController(localNodeName, assigner, informerFactory, crdInformerFactory.Core().V1alpha2().ExternalIPPools())
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	crdInformerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	crdInformerFactory.WaitForCacheSync(stopCh)
+	return &externalIPController{c, assigner, informerFactory}
+}
+
+func newNode(name string, ready bool, labels map[string]string) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newService(name, poolName string, ingressIPs ...string) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        name,
+			Annotations: map[string]string{corev1alpha2.ServiceExternalIPPoolAnnotationKey: poolName},
+		},
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+	for _, ip := range ingressIPs {
+		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+	}
+	return svc
+}
+
+// ipSelectingNode returns an IP in 10.10.10.0/24 for which the expected Node is
+// selected among the provided Nodes.
+func ipSelectingNode(t *testing.T, expectedNode string, nodes []string) string {
+	for i := 0; i < 256; i++ {
+		ip := fmt.Sprintf("10.10.10.%d", i)
+		if selectNode(ip, nodes) == expectedNode {
+			return ip
+		}
+	}
+	t.Fatalf("No IP selects Node %s", expectedNode)
+	return ""
+}
+
+func TestSelectNode(t *testing.T) {
+	nodes := []string{"node1", "node2", "node3"}
+	for i := 0; i < 256; i++ {
+		ip := fmt.Sprintf("10.10.10.%d", i)
+		selected := selectNode(ip, nodes)
+		// The selection doesn't depend on the order of the Nodes.
+		assert.Equal(t, selected, selectNode(ip, []string{"node3", "node2", "node1"}))
+		// The IP isn't moved when another Node is removed.
+		var remaining []string
+		for _, node := range nodes {
+			if node != selected {
+				remaining = append(remaining, node)
+			}
+		}
+		assert.Equal(t, selected, selectNode(ip, append([]string{selected}, remaining[1:]...)))
+	}
+	assert.Equal(t, "", selectNode("10.10.10.1", nil))
+}
+
+func TestSyncService(t *testing.T) {
+	pool := &corev1alpha2.ExternalIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec: corev1alpha2.ExternalIPPoolSpec{
+			IPRanges:     []corev1alpha2.IPRange{{CIDR: "10.10.10.0/24"}},
+			NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"external-ip": "true"}},
+		},
+	}
+	eligibleLabels := map[string]string{"external-ip": "true"}
+	localIP := ipSelectingNode(t, "node1", []string{"node1", "node2"})
+	remoteIP := ipSelectingNode(t, "node2", []string{"node1", "node2"})
+	svc1 := newService("svc1", "pool1", localIP, remoteIP, "10.10.20.1")
+	svc2 := newService("svc2", "pool2", localIP)
+	node1 := newNode("node1", true, eligibleLabels)
+	node2 := newNode("node2", true, eligibleLabels)
+	// node3 isn't selected by the pool.
+	node3 := newNode("node3", true, nil)
+	c := newExternalIPController([]runtime.Object{svc1, svc2, node1, node2, node3}, []runtime.Object{pool}, "10.10.30.1")
+
+	require.NoError(t, c.removeStaleIPs())
+	assert.Equal(t, sets.NewString(), c.assigner.ips)
+	require.NoError(t, c.syncService("default/svc1"))
+	require.NoError(t, c.syncService("default/svc2"))
+	// Only the IP in the pool which selects this Node is assigned.
+	assert.Equal(t, sets.NewString(localIP), c.assigner.ips)
+
+	// This Node takes over the IP when the Node announcing it becomes not
+	// ready.
+	c.informerFactory.Core().V1().Nodes().Informer().GetIndexer().Update(newNode("node2", false, eligibleLabels))
+	require.NoError(t, c.syncService("default/svc1"))
+	assert.Equal(t, sets.NewString(localIP, remoteIP), c.assigner.ips)
+
+	// The IPs are removed when this Node is not selected by the pool.
+	c.informerFactory.Core().V1().Nodes().Informer().GetIndexer().Update(newNode("node1", true, nil))
+	require.NoError(t, c.syncService("default/svc1"))
+	assert.Equal(t, sets.NewString(), c.assigner.ips)
+	_, exists := c.assignedIPs["default/svc1"]
+	assert.False(t, exists)
+}
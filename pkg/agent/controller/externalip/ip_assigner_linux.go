@@ -0,0 +1,113 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/arping"
+)
+
+// dummyDeviceName is the name of the dummy device which the external IPs are
+// assigned to. Assigning the IPs to a dummy device makes the Node reply to the
+// ARP requests for them on the transport interface, while not affecting the
+// routing of the transport interface.
+const dummyDeviceName = "antrea-ext0"
+
+type linuxIPAssigner struct {
+	// transportInterface is the interface over which the IPs are announced.
+	transportInterface *net.Interface
+	dummyDevice        netlink.Link
+}
+
+func newIPAssigner(nodeTransportIP net.IP) (ipAssigner, error) {
+	_, transportInterface, err := util.GetIPNetDeviceFromIP(nodeTransportIP)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the interface of IP %s: %v", nodeTransportIP, err)
+	}
+	dummyDevice, err := ensureDummyDevice()
+	if err != nil {
+		return nil, fmt.Errorf("error creating dummy device %s: %v", dummyDeviceName, err)
+	}
+	return &linuxIPAssigner{
+		transportInterface: transportInterface,
+		dummyDevice:        dummyDevice,
+	}, nil
+}
+
+// ensureDummyDevice creates the dummy device if it doesn't exist and brings it
+// up.
+func ensureDummyDevice() (netlink.Link, error) {
+	link, err := netlink.LinkByName(dummyDeviceName)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); !ok {
+			return nil, err
+		}
+		link = &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: dummyDeviceName}}
+		if err := netlink.LinkAdd(link); err != nil {
+			return nil, err
+		}
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (a *linuxIPAssigner) AssignIP(ip net.IP) error {
+	addr := &netlink.Addr{IPNet: &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}}
+	if err := netlink.AddrReplace(a.dummyDevice, addr); err != nil {
+		return fmt.Errorf("error adding IP %s to device %s: %v", ip, dummyDeviceName, err)
+	}
+	// Update the ARP caches of the hosts in the same network, which might
+	// still map the IP to the MAC address of the Node previously announcing
+	// it.
+	if err := arping.GratuitousARPOverIface(ip, a.transportInterface); err != nil {
+		return fmt.Errorf("error sending gratuitous ARP for IP %s over interface %s: %v", ip, a.transportInterface.Name, err)
+	}
+	return nil
+}
+
+func (a *linuxIPAssigner) UnassignIP(ip net.IP) error {
+	assignedIPs, err := a.AssignedIPs()
+	if err != nil {
+		return err
+	}
+	if !assignedIPs.Has(ip.String()) {
+		return nil
+	}
+	addr := &netlink.Addr{IPNet: &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}}
+	if err := netlink.AddrDel(a.dummyDevice, addr); err != nil {
+		return fmt.Errorf("error removing IP %s from device %s: %v", ip, dummyDeviceName, err)
+	}
+	return nil
+}
+
+func (a *linuxIPAssigner) AssignedIPs() (sets.String, error) {
+	addrs, err := netlink.AddrList(a.dummyDevice, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, fmt.Errorf("error listing the IPs of device %s: %v", dummyDeviceName, err)
+	}
+	ips := sets.NewString()
+	for _, addr := range addrs {
+		ips.Insert(addr.IP.String())
+	}
+	return ips, nil
+}
@@ -0,0 +1,470 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+	mcinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/multicluster/v1alpha1"
+	mclisters "github.com/vmware-tanzu/antrea/pkg/client/listers/multicluster/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/multicluster"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+)
+
+const (
+	controllerName = "AntreaAgentMulticlusterController"
+	// How long to wait before retrying the processing of a cluster change.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+)
+
+// clusterConnection is the connection installed to a remote cluster.
+type clusterConnection struct {
+	// isGateway is true if this Node is the gateway Node of the cluster.
+	isGateway bool
+	// tunnelPeerIP is the IP of the gateway Node of the remote cluster if
+	// this Node is the gateway, otherwise the IP of the local gateway Node.
+	tunnelPeerIP net.IP
+	podCIDRs     []*net.IPNet
+}
+
+func (c *clusterConnection) equals(other *clusterConnection) bool {
+	if c.isGateway != other.isGateway || !c.tunnelPeerIP.Equal(other.tunnelPeerIP) || len(c.podCIDRs) != len(other.podCIDRs) {
+		return false
+	}
+	for i := range c.podCIDRs {
+		if c.podCIDRs[i].String() != other.podCIDRs[i].String() {
+			return false
+		}
+	}
+	return true
+}
+
+// Controller is responsible for connecting the Pods of this Node to the Pods
+// of the remote clusters, described by the ClusterInfos which the Antrea
+// Controller copies from the leader cluster. The traffic to the remote Pod
+// CIDRs is sent to the gateway Node of the local cluster over the default
+// tunnel, and the gateway Node forwards it over a tunnel to the gateway Node of
+// each remote cluster, which forwards it to the destination Node as the traffic
+// received from any other Node.
+type Controller struct {
+	nodeName                string
+	namespace               string
+	nodeConfig              *config.NodeConfig
+	networkConfig           *config.NetworkConfig
+	ofClient                openflow.Client
+	ovsBridgeClient         ovsconfig.OVSBridgeClient
+	interfaceStore          interfacestore.InterfaceStore
+	nodeLister              corelisters.NodeLister
+	nodeListerSynced        cache.InformerSynced
+	clusterInfoLister       mclisters.ClusterInfoLister
+	clusterInfoListerSynced cache.InformerSynced
+	queue                   workqueue.RateLimitingInterface
+
+	// installedClusters maps the IDs of the remote clusters to the
+	// connections installed to them. It's only accessed by the single worker.
+	installedClusters map[string]*clusterConnection
+}
+
+// NewMulticlusterController instantiates a new Controller object which will
+// process the ClusterInfos in the Namespace and the Node events.
+func NewMulticlusterController(
+	nodeConfig *config.NodeConfig,
+	networkConfig *config.NetworkConfig,
+	namespace string,
+	ofClient openflow.Client,
+	ovsBridgeClient ovsconfig.OVSBridgeClient,
+	interfaceStore interfacestore.InterfaceStore,
+	informerFactory informers.SharedInformerFactory,
+	clusterInfoInformer mcinformers.ClusterInfoInformer) *Controller {
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	c := &Controller{
+		nodeName:                nodeConfig.Name,
+		namespace:               namespace,
+		nodeConfig:              nodeConfig,
+		networkConfig:           networkConfig,
+		ofClient:                ofClient,
+		ovsBridgeClient:         ovsBridgeClient,
+		interfaceStore:          interfaceStore,
+		nodeLister:              nodeInformer.Lister(),
+		nodeListerSynced:        nodeInformer.Informer().HasSynced,
+		clusterInfoLister:       clusterInfoInformer.Lister(),
+		clusterInfoListerSynced: clusterInfoInformer.Informer().HasSynced,
+		queue:                   workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "multicluster"),
+		installedClusters:       map[string]*clusterConnection{},
+	}
+	nodeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addNode,
+			UpdateFunc: c.updateNode,
+			DeleteFunc: c.deleteNode,
+		},
+	)
+	clusterInfoInformer.Informer().AddEventHandler(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				info, ok := getClusterInfo(obj)
+				return ok && info.Namespace == namespace
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.enqueueClusterInfo,
+				UpdateFunc: c.updateClusterInfo,
+				DeleteFunc: c.enqueueClusterInfo,
+			},
+		},
+	)
+	return c
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// enqueueAllClusters adds all the remote clusters to the queue, as the gateway
+// Node of the local cluster might change.
+func (c *Controller) enqueueAllClusters() {
+	infos, err := c.clusterInfoLister.ClusterInfos(c.namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing ClusterInfos: %v", err)
+		return
+	}
+	for _, info := range infos {
+		c.queue.Add(info.Name)
+	}
+}
+
+func (c *Controller) addNode(obj interface{}) {
+	node := obj.(*corev1.Node)
+	if !multicluster.GatewayNodeSelector.Matches(labels.Set(node.Labels)) {
+		return
+	}
+	klog.V(2).Infof("Processing Node %s ADD event", node.Name)
+	c.enqueueAllClusters()
+}
+
+func (c *Controller) updateNode(old, cur interface{}) {
+	oldNode := old.(*corev1.Node)
+	curNode := cur.(*corev1.Node)
+	oldMatches := multicluster.GatewayNodeSelector.Matches(labels.Set(oldNode.Labels))
+	curMatches := multicluster.GatewayNodeSelector.Matches(labels.Set(curNode.Labels))
+	if !oldMatches && !curMatches {
+		return
+	}
+	if oldMatches == curMatches &&
+		isNodeReady(oldNode) == isNodeReady(curNode) &&
+		apiequality.Semantic.DeepEqual(oldNode.Status.Addresses, curNode.Status.Addresses) {
+		return
+	}
+	klog.V(2).Infof("Processing Node %s UPDATE event", curNode.Name)
+	c.enqueueAllClusters()
+}
+
+func (c *Controller) deleteNode(old interface{}) {
+	node, ok := old.(*corev1.Node)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Node, invalid type: %v", old)
+			return
+		}
+		node, ok = tombstone.Obj.(*corev1.Node)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Node, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	if !multicluster.GatewayNodeSelector.Matches(labels.Set(node.Labels)) {
+		return
+	}
+	klog.V(2).Infof("Processing Node %s DELETE event", node.Name)
+	c.enqueueAllClusters()
+}
+
+func getClusterInfo(obj interface{}) (*mcv1alpha1.ClusterInfo, bool) {
+	info, ok := obj.(*mcv1alpha1.ClusterInfo)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return nil, false
+		}
+		info, ok = tombstone.Obj.(*mcv1alpha1.ClusterInfo)
+		if !ok {
+			return nil, false
+		}
+	}
+	return info, true
+}
+
+func (c *Controller) enqueueClusterInfo(obj interface{}) {
+	info, ok := getClusterInfo(obj)
+	if !ok {
+		klog.Errorf("Error decoding ClusterInfo, invalid type: %v", obj)
+		return
+	}
+	klog.V(2).Infof("Processing ClusterInfo %s event", info.Name)
+	c.queue.Add(info.Name)
+}
+
+func (c *Controller) updateClusterInfo(old, cur interface{}) {
+	oldInfo := old.(*mcv1alpha1.ClusterInfo)
+	curInfo := cur.(*mcv1alpha1.ClusterInfo)
+	if apiequality.Semantic.DeepEqual(oldInfo.Spec, curInfo.Spec) {
+		return
+	}
+	c.enqueueClusterInfo(curInfo)
+}
+
+// Run will create a worker (go routine) which will process the remote clusters
+// from the workqueue, after removing the tunnel ports to the clusters which
+// no longer exist.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", controllerName)
+	defer klog.Infof("Shutting down %s", controllerName)
+
+	klog.Infof("Waiting for caches to sync for %s", controllerName)
+	if !cache.WaitForCacheSync(stopCh, c.nodeListerSynced, c.clusterInfoListerSynced) {
+		klog.Errorf("Unable to sync caches for %s", controllerName)
+		return
+	}
+	klog.Infof("Caches are synced for %s", controllerName)
+
+	c.removeStaleTunnelPorts()
+
+	go wait.Until(c.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+// removeStaleTunnelPorts removes the tunnel ports to the remote clusters whose
+// ClusterInfos no longer exist. The ports to the existing clusters are kept
+// and reused if their configurations are still valid.
+func (c *Controller) removeStaleTunnelPorts() {
+	for _, interfaceConfig := range c.interfaceStore.GetInterfacesByType(interfacestore.TunnelInterface) {
+		if interfaceConfig.ClusterID == "" {
+			continue
+		}
+		_, err := c.clusterInfoLister.ClusterInfos(c.namespace).Get(interfaceConfig.ClusterID)
+		if err == nil || !apierrors.IsNotFound(err) {
+			continue
+		}
+		if err := c.deleteClusterTunnelPort(interfaceConfig); err != nil {
+			klog.Errorf("Failed to delete stale tunnel port for cluster %s: %v", interfaceConfig.ClusterID, err)
+		}
+	}
+}
+
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(obj)
+
+	// We expect strings (cluster IDs) to come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd
+		// go into a loop of attempting to process a work item that is invalid.
+		c.queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := c.syncCluster(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again until
+		// another change happens.
+		c.queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Error syncing cluster %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+// desiredConnection returns the connection which should be installed to the
+// remote cluster, or nil if the remote cluster can't be connected.
+func (c *Controller) desiredConnection(clusterID string) (*clusterConnection, error) {
+	info, err := c.clusterInfoLister.ClusterInfos(c.namespace).Get(clusterID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	remoteGatewayIP := net.ParseIP(info.Spec.GatewayIP)
+	if remoteGatewayIP == nil {
+		klog.Infof("Remote cluster %s has no available gateway", clusterID)
+		return nil, nil
+	}
+	var podCIDRs []*net.IPNet
+	for _, podCIDR := range info.Spec.PodCIDRs {
+		_, ipNet, err := net.ParseCIDR(podCIDR)
+		if err != nil {
+			klog.Errorf("Invalid Pod CIDR %s of remote cluster %s", podCIDR, clusterID)
+			continue
+		}
+		podCIDRs = append(podCIDRs, ipNet)
+	}
+
+	nodes, err := c.nodeLister.List(multicluster.GatewayNodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	gatewayNode := multicluster.SelectGatewayNode(nodes)
+	if gatewayNode == nil {
+		klog.Infof("Local cluster has no available gateway")
+		return nil, nil
+	}
+	if gatewayNode.Name == c.nodeName {
+		return &clusterConnection{isGateway: true, tunnelPeerIP: remoteGatewayIP, podCIDRs: podCIDRs}, nil
+	}
+	return &clusterConnection{isGateway: false, tunnelPeerIP: multicluster.GetGatewayIP(gatewayNode), podCIDRs: podCIDRs}, nil
+}
+
+func (c *Controller) syncCluster(clusterID string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing cluster %s. (%v)", clusterID, time.Since(startTime))
+	}()
+
+	desired, err := c.desiredConnection(clusterID)
+	if err != nil {
+		return err
+	}
+	installed, exists := c.installedClusters[clusterID]
+	if exists && desired != nil && installed.equals(desired) {
+		return nil
+	}
+	if exists {
+		if err := c.ofClient.UninstallMulticlusterFlows(clusterID); err != nil {
+			return fmt.Errorf("failed to uninstall flows to cluster %s: %v", clusterID, err)
+		}
+		delete(c.installedClusters, clusterID)
+		klog.Infof("Removed connection to cluster %s", clusterID)
+	}
+	if desired == nil || !desired.isGateway {
+		// This Node doesn't need the tunnel port anymore.
+		if interfaceConfig, ok := c.interfaceStore.GetClusterTunnelInterface(clusterID); ok {
+			if err := c.deleteClusterTunnelPort(interfaceConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if desired == nil {
+		return nil
+	}
+
+	if desired.isGateway {
+		tunOFPort, err := c.createClusterTunnelPort(clusterID, desired.tunnelPeerIP)
+		if err != nil {
+			return err
+		}
+		err = c.ofClient.InstallMulticlusterGatewayFlows(clusterID, c.nodeConfig.GatewayConfig.MAC, desired.podCIDRs, uint32(tunOFPort))
+		if err != nil {
+			return fmt.Errorf("failed to install flows to cluster %s: %v", clusterID, err)
+		}
+	} else {
+		err := c.ofClient.InstallMulticlusterNodeFlows(clusterID, c.nodeConfig.GatewayConfig.MAC, desired.podCIDRs, desired.tunnelPeerIP, config.DefaultTunOFPort)
+		if err != nil {
+			return fmt.Errorf("failed to install flows to cluster %s: %v", clusterID, err)
+		}
+	}
+	c.installedClusters[clusterID] = desired
+	klog.Infof("Installed connection to cluster %s, gateway: %v, tunnel peer: %s, Pod CIDRs: %v",
+		clusterID, desired.isGateway, desired.tunnelPeerIP, desired.podCIDRs)
+	return nil
+}
+
+// createClusterTunnelPort creates the tunnel port to the gateway Node of the
+// remote cluster if it doesn't exist, and returns the ofport number. The
+// existing tunnel port is re-created if its configuration has changed.
+func (c *Controller) createClusterTunnelPort(clusterID string, remoteIP net.IP) (int32, error) {
+	interfaceConfig, ok := c.interfaceStore.GetClusterTunnelInterface(clusterID)
+	if ok && (!interfaceConfig.RemoteIP.Equal(remoteIP) || interfaceConfig.TunnelInterfaceConfig.Type != c.networkConfig.TunnelType) {
+		if err := c.deleteClusterTunnelPort(interfaceConfig); err != nil {
+			return 0, err
+		}
+		ok = false
+	}
+	if ok && interfaceConfig.OFPort != 0 {
+		return interfaceConfig.OFPort, nil
+	}
+	if !ok {
+		portName := util.GenerateClusterTunnelInterfaceName(clusterID)
+		ovsExternalIDs := map[string]interface{}{noderoute.OVSExternalIDClusterID: clusterID}
+		portUUID, err := c.ovsBridgeClient.CreateTunnelPortExt(
+			portName,
+			c.networkConfig.TunnelType,
+			0, // ofPortRequest - let OVS allocate OFPort number.
+			false,
+			"",
+			remoteIP.String(),
+			"",
+			ovsExternalIDs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create tunnel port for cluster %s: %v", clusterID, err)
+		}
+		klog.Infof("Created tunnel port %s for cluster %s", portName, clusterID)
+
+		interfaceConfig = interfacestore.NewClusterTunnelInterface(portName, c.networkConfig.TunnelType, clusterID, remoteIP)
+		interfaceConfig.OVSPortConfig = &interfacestore.OVSPortConfig{PortUUID: portUUID}
+		c.interfaceStore.AddInterface(interfaceConfig)
+	}
+
+	// GetOFPort will wait for up to 1 second for OVSDB to report the OFPort number.
+	ofPort, err := c.ovsBridgeClient.GetOFPort(interfaceConfig.InterfaceName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get of_port of tunnel port for cluster %s: %v", clusterID, err)
+	}
+	interfaceConfig.OFPort = ofPort
+	return ofPort, nil
+}
+
+func (c *Controller) deleteClusterTunnelPort(interfaceConfig *interfacestore.InterfaceConfig) error {
+	if err := c.ovsBridgeClient.DeletePort(interfaceConfig.PortUUID); err != nil {
+		return fmt.Errorf("failed to delete tunnel port %s for cluster %s: %v", interfaceConfig.InterfaceName, interfaceConfig.ClusterID, err)
+	}
+	c.interfaceStore.DeleteInterface(interfaceConfig)
+	klog.Infof("Deleted tunnel port %s for cluster %s", interfaceConfig.InterfaceName, interfaceConfig.ClusterID)
+	return nil
+}
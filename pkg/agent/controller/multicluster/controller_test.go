@@ -0,0 +1,162 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	oftest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	ovsconfigtest "github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig/testing"
+)
+
+const (
+	localNodeName = "node1"
+	testNamespace = "kube-system"
+)
+
+var gatewayMAC, _ = net.ParseMAC("00:00:00:00:00:01")
+
+type multiclusterController struct {
+	*Controller
+	informerFactory    informers.SharedInformerFactory
+	crdInformerFactory crdinformers.SharedInformerFactory
+	ofClient           *oftest.MockClient
+	ovsClient          *ovsconfigtest.MockOVSBridgeClient
+}
+
+func newMulticlusterController(t *testing.T, objects []runtime.Object, crdObjects []runtime.Object) *multiclusterController {
+	ctrl := gomock.NewController(t)
+	client := fake.NewSimpleClientset(objects...)
+	crdClient := fakeversioned.NewSimpleClientset(crdObjects...)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	ofClient := oftest.NewMockClient(ctrl)
+	ovsClient := ovsconfigtest.NewMockOVSBridgeClient(ctrl)
+	nodeConfig := &config.NodeConfig{
+		Name:          localNodeName,
+		GatewayConfig: &config.GatewayConfig{MAC: gatewayMAC},
+	}
+	networkConfig := &config.NetworkConfig{TunnelType: ovsconfig.GeneveTunnel}
+	c := NewMulticlusterController(nodeConfig, networkConfig, testNamespace, ofClient, ovsClient,
+		interfacestore.NewInterfaceStore(), informerFactory, crdInformerFactory.Multicluster().V1alpha1().ClusterInfos())
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	crdInformerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	crdInformerFactory.WaitForCacheSync(stopCh)
+	return &multiclusterController{c, informerFactory, crdInformerFactory, ofClient, ovsClient}
+}
+
+func newNode(name, ip string, gateway, ready bool) *corev1.Node {
+	labels := map[string]string{}
+	if gateway {
+		labels[mcv1alpha1.GatewayNodeLabelKey] = "true"
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newClusterInfo(clusterID, gatewayIP string, podCIDRs ...string) *mcv1alpha1.ClusterInfo {
+	return &mcv1alpha1.ClusterInfo{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: clusterID},
+		Spec: mcv1alpha1.ClusterInfoSpec{
+			ClusterID: clusterID,
+			GatewayIP: gatewayIP,
+			PodCIDRs:  podCIDRs,
+		},
+	}
+}
+
+func TestSyncCluster(t *testing.T) {
+	node1 := newNode(localNodeName, "192.168.1.1", true, true)
+	node2 := newNode("node2", "192.168.1.2", true, true)
+	info := newClusterInfo("cluster-b", "192.168.2.1", "10.20.0.0/24", "10.20.1.0/24")
+	c := newMulticlusterController(t, []runtime.Object{node1, node2}, []runtime.Object{info})
+	_, podCIDR1, _ := net.ParseCIDR("10.20.0.0/24")
+	_, podCIDR2, _ := net.ParseCIDR("10.20.1.0/24")
+	podCIDRs := []*net.IPNet{podCIDR1, podCIDR2}
+
+	// This Node is the gateway Node, a tunnel port is created to the gateway
+	// Node of the remote cluster.
+	portName := util.GenerateClusterTunnelInterfaceName("cluster-b")
+	externalIDs := map[string]interface{}{noderoute.OVSExternalIDClusterID: "cluster-b"}
+	c.ovsClient.EXPECT().CreateTunnelPortExt(portName, ovsconfig.TunnelType(ovsconfig.GeneveTunnel), int32(0), false, "", "192.168.2.1", "", externalIDs).Return("uuid1", nil)
+	c.ovsClient.EXPECT().GetOFPort(portName).Return(int32(10), nil)
+	c.ofClient.EXPECT().InstallMulticlusterGatewayFlows("cluster-b", gatewayMAC, podCIDRs, uint32(10))
+	require.NoError(t, c.syncCluster("cluster-b"))
+	// Nothing is changed if the cluster is processed again.
+	require.NoError(t, c.syncCluster("cluster-b"))
+
+	// Another Node becomes the gateway Node, the traffic to the remote
+	// cluster is sent to it.
+	c.informerFactory.Core().V1().Nodes().Informer().GetIndexer().Update(newNode(localNodeName, "192.168.1.1", true, false))
+	c.ofClient.EXPECT().UninstallMulticlusterFlows("cluster-b")
+	c.ovsClient.EXPECT().DeletePort("uuid1")
+	c.ofClient.EXPECT().InstallMulticlusterNodeFlows("cluster-b", gatewayMAC, podCIDRs, net.ParseIP("192.168.1.2"), uint32(config.DefaultTunOFPort))
+	require.NoError(t, c.syncCluster("cluster-b"))
+	_, exists := c.interfaceStore.GetClusterTunnelInterface("cluster-b")
+	assert.False(t, exists)
+
+	// The connection is removed when the remote cluster is removed.
+	c.crdInformerFactory.Multicluster().V1alpha1().ClusterInfos().Informer().GetIndexer().Delete(info)
+	c.ofClient.EXPECT().UninstallMulticlusterFlows("cluster-b")
+	require.NoError(t, c.syncCluster("cluster-b"))
+	_, exists = c.installedClusters["cluster-b"]
+	assert.False(t, exists)
+}
+
+func TestRemoveStaleTunnelPorts(t *testing.T) {
+	info := newClusterInfo("cluster-b", "192.168.2.1", "10.20.0.0/24")
+	c := newMulticlusterController(t, nil, []runtime.Object{info})
+	validPort := interfacestore.NewClusterTunnelInterface("mc-b", ovsconfig.GeneveTunnel, "cluster-b", net.ParseIP("192.168.2.1"))
+	validPort.OVSPortConfig = &interfacestore.OVSPortConfig{PortUUID: "uuid1"}
+	stalePort := interfacestore.NewClusterTunnelInterface("mc-c", ovsconfig.GeneveTunnel, "cluster-c", net.ParseIP("192.168.3.1"))
+	stalePort.OVSPortConfig = &interfacestore.OVSPortConfig{PortUUID: "uuid2"}
+	defaultPort := interfacestore.NewTunnelInterface("antrea-tun0", ovsconfig.GeneveTunnel, nil, false)
+	c.interfaceStore.Initialize([]*interfacestore.InterfaceConfig{validPort, stalePort, defaultPort})
+
+	c.ovsClient.EXPECT().DeletePort("uuid2")
+	c.removeStaleTunnelPorts()
+	assert.Equal(t, 2, c.interfaceStore.Len())
+	_, exists := c.interfaceStore.GetClusterTunnelInterface("cluster-b")
+	assert.True(t, exists)
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"golang.org/x/time/rate"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+const (
+	// auditLogDir is the directory of the audit log files on the Node. It's
+	// under the log directory of antrea-agent, which is mounted from the host.
+	auditLogDir      = "/var/log/antrea/networkpolicy"
+	auditLogFileName = "np.log"
+	// Maximum size in megabytes of the audit log file before it gets rotated.
+	auditLogMaxSize = 500
+	// Maximum number of the rotated audit log files to retain.
+	auditLogMaxBackups = 3
+	// Maximum number of days to retain the rotated audit log files.
+	auditLogMaxAge = 28
+	// The audit log entries are rate limited to prevent the log files from
+	// being flooded, e.g. by the packets dropped by a rule.
+	auditLogRateLimit = 500
+	auditLogBurstSize = 1000
+)
+
+// auditLogEntry is a structured audit log entry for a packet matched by an
+// Antrea-native policy rule with logging enabled. Each entry is written as a
+// single JSON line.
+type auditLogEntry struct {
+	Timestamp       string `json:"timestamp"`
+	Table           string `json:"table"`
+	PolicyType      string `json:"policyType"`
+	PolicyNamespace string `json:"policyNamespace,omitempty"`
+	PolicyName      string `json:"policyName"`
+	Direction       string `json:"direction"`
+	// RuleIndex is the index of the rule in the ingress or egress rules of
+	// the policy.
+	RuleIndex int32  `json:"ruleIndex"`
+	Action    string `json:"action"`
+	Protocol  string `json:"protocol"`
+	SrcIP     string `json:"srcIP"`
+	SrcPort   uint16 `json:"srcPort,omitempty"`
+	DstIP     string `json:"dstIP"`
+	DstPort   uint16 `json:"dstPort,omitempty"`
+	// SrcPod and DstPod are the "<namespace>/<name>" of the local Pods, empty
+	// if the addresses do not belong to a Pod on this Node.
+	SrcPod string `json:"srcPod,omitempty"`
+	DstPod string `json:"dstPod,omitempty"`
+	// DroppedEntries is the number of entries dropped by rate limiting since
+	// the previous entry was written.
+	DroppedEntries uint64 `json:"droppedEntries,omitempty"`
+}
+
+// auditLogger writes rate-limited audit log entries to the provided writer.
+type auditLogger struct {
+	mutex          sync.Mutex
+	writer         io.Writer
+	limiter        *rate.Limiter
+	droppedEntries uint64
+}
+
+func newAuditLogger(writer io.Writer) *auditLogger {
+	return &auditLogger{
+		writer:  writer,
+		limiter: rate.NewLimiter(auditLogRateLimit, auditLogBurstSize),
+	}
+}
+
+// newFileAuditLogger returns an auditLogger which writes to the audit log file
+// on the Node. The file is created on the first write, and is rotated based on
+// its size.
+func newFileAuditLogger() *auditLogger {
+	return newAuditLogger(&lumberjack.Logger{
+		Filename:   filepath.Join(auditLogDir, auditLogFileName),
+		MaxSize:    auditLogMaxSize,
+		MaxBackups: auditLogMaxBackups,
+		MaxAge:     auditLogMaxAge,
+		Compress:   true,
+	})
+}
+
+// log writes the provided entry if the rate limit is not exceeded, otherwise
+// the entry is dropped and counted in the next written entry.
+func (l *auditLogger) log(entry *auditLogEntry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.limiter.Allow() {
+		l.droppedEntries++
+		return nil
+	}
+	entry.DroppedEntries = l.droppedEntries
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.writer.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	l.droppedEntries = 0
+	return nil
+}
+
+// logPacket generates an audit log entry for the packet sent to the controller
+// by a rule with logging enabled.
+func (c *Controller) logPacket(pktIn *ofctrl.PacketIn, marks uint32) error {
+	entry, err := c.getAuditLogEntry(pktIn, marks)
+	if err != nil {
+		return err
+	}
+	return c.auditLogger.log(entry)
+}
+
+func (c *Controller) getAuditLogEntry(pktIn *ofctrl.PacketIn, marks uint32) (*auditLogEntry, error) {
+	tableID := binding.TableIDType(pktIn.TableId)
+	// The ID of the matched rule is stored in CNPDropConjunctionIDReg for the
+	// rules dropping packets, and in EgressReg or IngressReg for the others.
+	conjIDReg := openflow.IngressReg
+	if ofctrl.GetUint32ValueWithRange(marks, openflow.CNPDropMarkRange.ToNXRange()) == openflow.CNPDropMark {
+		conjIDReg = openflow.CNPDropConjunctionIDReg
+	} else {
+		for _, table := range openflow.GetAntreaPolicyEgressTables() {
+			if table == tableID {
+				conjIDReg = openflow.EgressReg
+				break
+			}
+		}
+	}
+	conjID, err := getRegValue(pktIn, int(conjIDReg))
+	if err != nil {
+		return nil, err
+	}
+	rule, exists := c.reconciler.GetRuleByFlowID(conjID)
+	if !exists || rule.SourceRef == nil {
+		return nil, fmt.Errorf("rule of Openflow ID %d not found", conjID)
+	}
+	// The packets dropped by a rule are sent to the controller by the metric
+	// table, the table of the rule is logged instead.
+	if conjIDReg == openflow.CNPDropConjunctionIDReg {
+		tableID = getOFRuleTable(rule)
+	}
+
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return nil, fmt.Errorf("unsupported ethertype %#x of logged packet", pktIn.Data.Ethertype)
+	}
+	ipPkt, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return nil, errors.New("invalid IPv4 packet of logged packet")
+	}
+	action := secv1alpha1.RuleActionAllow
+	if rule.Action != nil {
+		action = *rule.Action
+	}
+	entry := &auditLogEntry{
+		Timestamp:       time.Now().Format(time.RFC3339Nano),
+		Table:           openflow.GetFlowTableName(tableID),
+		PolicyType:      string(rule.SourceRef.Type),
+		PolicyNamespace: rule.SourceRef.Namespace,
+		PolicyName:      rule.SourceRef.Name,
+		Direction:       string(rule.Direction),
+		RuleIndex:       rule.Priority,
+		Action:          string(action),
+		SrcIP:           ipPkt.NWSrc.String(),
+		DstIP:           ipPkt.NWDst.String(),
+		SrcPod:          getPodByIP(c.ifaceStore, ipPkt.NWSrc.String()),
+		DstPod:          getPodByIP(c.ifaceStore, ipPkt.NWDst.String()),
+	}
+	switch ipPkt.Protocol {
+	case protocol.Type_TCP:
+		entry.Protocol = "TCP"
+		// TCP segments are not decoded by the IPv4 parser.
+		tcpData, err := ipPkt.Data.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TCP header of logged packet: %v", err)
+		}
+		tcpPkt := new(protocol.TCP)
+		if err := tcpPkt.UnmarshalBinary(tcpData); err != nil {
+			return nil, fmt.Errorf("failed to parse TCP header of logged packet: %v", err)
+		}
+		entry.SrcPort, entry.DstPort = tcpPkt.PortSrc, tcpPkt.PortDst
+	case protocol.Type_UDP:
+		entry.Protocol = "UDP"
+		if udpPkt, ok := ipPkt.Data.(*protocol.UDP); ok {
+			entry.SrcPort, entry.DstPort = udpPkt.PortSrc, udpPkt.PortDst
+		}
+	case protocol.Type_ICMP:
+		entry.Protocol = "ICMP"
+	default:
+		entry.Protocol = fmt.Sprintf("%d", ipPkt.Protocol)
+	}
+	return entry, nil
+}
+
+// getRegValue returns the value of the provided register in the packet-in
+// message.
+func getRegValue(pktIn *ofctrl.PacketIn, reg int) (uint32, error) {
+	match := pktIn.GetMatches().GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", reg))
+	if match == nil {
+		return 0, fmt.Errorf("reg%d of packet-in not found", reg)
+	}
+	regValue, ok := match.GetValue().(*ofctrl.NXRegister)
+	if !ok {
+		return 0, fmt.Errorf("reg%d of packet-in cannot be got", reg)
+	}
+	return regValue.Data, nil
+}
+
+// getPodByIP returns the "<namespace>/<name>" of the local Pod which has the
+// provided IP, or an empty string if no such Pod exists.
+func getPodByIP(ifaceStore interfacestore.InterfaceStore, ip string) string {
+	if ifaceStore == nil {
+		return ""
+	}
+	iface, exists := ifaceStore.GetInterfaceByIP(ip)
+	if !exists || iface.Type != interfacestore.ContainerInterface {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", iface.PodNamespace, iface.PodName)
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+func newLoggingPacketIn(tableID uint8, marks uint32, conjIDReg int, conjID uint32, ipPkt *protocol.IPv4) *ofctrl.PacketIn {
+	fields := []openflow13.MatchField{
+		*openflow13.NewRegMatchField(int(openflow.CustomReasonMarkReg), marks, nil),
+	}
+	// The drop mark and the custom reasons share the same register.
+	if conjIDReg != int(openflow.CustomReasonMarkReg) {
+		fields = append(fields, *openflow13.NewRegMatchField(conjIDReg, conjID, nil))
+	}
+	return &ofctrl.PacketIn{
+		TableId: tableID,
+		Match:   openflow13.Match{Fields: fields},
+		Data: protocol.Ethernet{
+			HWDst:     rejectDstMAC,
+			HWSrc:     rejectSrcMAC,
+			Ethertype: protocol.IPv4_MSG,
+			Data:      ipPkt,
+		},
+	}
+}
+
+func TestAuditLogging(t *testing.T) {
+	ingressTable := openflow.GetAntreaPolicyIngressTables()[0]
+	egressTable := openflow.GetAntreaPolicyEgressTables()[0]
+	loggingReason := uint32(openflow.CustomReasonLogging) << uint32(openflow.CustomReasonMarkRange[0])
+	dropMark := uint32(openflow.CNPDropMark) << uint32(openflow.CNPDropMarkRange[0])
+	actionDrop := secv1alpha1.RuleActionDrop
+	tierPriority := int32(100)
+	policyRef := &v1beta1.NetworkPolicyReference{
+		Type:      v1beta1.AntreaNetworkPolicy,
+		Namespace: "ns1",
+		Name:      "np1",
+	}
+
+	udpPkt := newIPv4Packet(protocol.Type_UDP, 28)
+	udpPkt.Data = &protocol.UDP{PortSrc: 34567, PortDst: 53, Length: 8}
+	tcpPkt := newIPv4Packet(protocol.Type_TCP, 40)
+	tcpPkt.Data = &protocol.TCP{PortSrc: 34567, PortDst: 80, HdrLen: 5, Code: tcpFlagSYN}
+
+	tests := []struct {
+		name          string
+		rule          *CompletedRule
+		packetIn      *ofctrl.PacketIn
+		expectedEntry auditLogEntry
+	}{
+		{
+			name: "ingress-allow-tcp",
+			rule: &CompletedRule{rule: &rule{Direction: v1beta1.DirectionIn, Priority: 1, SourceRef: policyRef}},
+			packetIn: newLoggingPacketIn(uint8(ingressTable), loggingReason,
+				int(openflow.IngressReg), 10, tcpPkt),
+			expectedEntry: auditLogEntry{
+				Table:           openflow.GetFlowTableName(ingressTable),
+				PolicyType:      string(v1beta1.AntreaNetworkPolicy),
+				PolicyNamespace: "ns1",
+				PolicyName:      "np1",
+				Direction:       string(v1beta1.DirectionIn),
+				RuleIndex:       1,
+				Action:          string(secv1alpha1.RuleActionAllow),
+				Protocol:        "TCP",
+				SrcIP:           rejectSrcIP.String(),
+				SrcPort:         34567,
+				DstIP:           rejectDstIP.String(),
+				DstPort:         80,
+				SrcPod:          "ns1/pod1",
+			},
+		},
+		{
+			name: "egress-drop-udp",
+			rule: &CompletedRule{rule: &rule{Direction: v1beta1.DirectionOut, Action: &actionDrop, SourceRef: policyRef, TierPriority: &tierPriority}},
+			// The packets dropped by a rule are sent to the controller by the metric table.
+			packetIn: newLoggingPacketIn(uint8(openflow.EgressMetricTable), loggingReason|dropMark,
+				int(openflow.CNPDropConjunctionIDReg), 10, udpPkt),
+			expectedEntry: auditLogEntry{
+				Table:           openflow.GetFlowTableName(egressTable),
+				PolicyType:      string(v1beta1.AntreaNetworkPolicy),
+				PolicyNamespace: "ns1",
+				PolicyName:      "np1",
+				Direction:       string(v1beta1.DirectionOut),
+				Action:          string(secv1alpha1.RuleActionDrop),
+				Protocol:        "UDP",
+				SrcIP:           rejectSrcIP.String(),
+				SrcPort:         34567,
+				DstIP:           rejectDstIP.String(),
+				DstPort:         53,
+				SrcPod:          "ns1/pod1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ifaceStore := interfacestore.NewInterfaceStore()
+			ifaceStore.AddInterface(interfacestore.NewContainerInterface("pod1-abc", "container1", "pod1", "ns1", rejectSrcMAC, []net.IP{rejectSrcIP}))
+			reconciler := newMockReconciler()
+			reconciler.ruleByFlowID[10] = tt.rule
+			buf := new(bytes.Buffer)
+			c := &Controller{reconciler: reconciler, ifaceStore: ifaceStore, auditLogger: newAuditLogger(buf)}
+
+			require.NoError(t, c.HandlePacketIn(tt.packetIn))
+			entry := auditLogEntry{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.NotEmpty(t, entry.Timestamp)
+			entry.Timestamp = ""
+			assert.Equal(t, tt.expectedEntry, entry)
+		})
+	}
+}
+
+func TestAuditLoggerRateLimit(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := newAuditLogger(buf)
+	logger.limiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	require.NoError(t, logger.log(&auditLogEntry{PolicyName: "np1"}))
+	require.NoError(t, logger.log(&auditLogEntry{PolicyName: "np2"}))
+	require.NoError(t, logger.log(&auditLogEntry{PolicyName: "np3"}))
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, uint64(2), logger.droppedEntries)
+
+	logger.limiter = rate.NewLimiter(rate.Inf, 1)
+	require.NoError(t, logger.log(&auditLogEntry{PolicyName: "np4"}))
+	lines = bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	entry := auditLogEntry{}
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.Equal(t, "np4", entry.PolicyName)
+	assert.Equal(t, uint64(2), entry.DroppedEntries)
+	assert.Equal(t, uint64(0), logger.droppedEntries)
+}
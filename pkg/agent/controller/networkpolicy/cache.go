@@ -68,8 +68,15 @@ type rule struct {
 	PolicyPriority *float64
 	// Priority of the tier that the NetworkPolicy belongs to. nil for K8s NetworkPolicy.
 	TierPriority *int32
+	// EnableLogging indicates whether or not to generate logs when this rule is matched.
+	EnableLogging bool
 	// Targets of this rule.
 	AppliedToGroups []string
+	// OwnAppliedToGroups indicates whether AppliedToGroups are the rule's own
+	// instead of the ones of the policy. The controller doesn't send the rule's
+	// own AppliedToGroups that select no Pods on this Node, so they are not
+	// expected to be received.
+	OwnAppliedToGroups bool `json:",omitempty"`
 	// The parent Policy ID. Used to identify rules belong to a specified
 	// policy for deletion.
 	PolicyUID types.UID
@@ -140,7 +147,7 @@ type ruleCache struct {
 
 	policyMapLock sync.RWMutex
 	// policyMap is a map using NetworkPolicy UID as the key.
-	policyMap map[string]*types.NamespacedName
+	policyMap map[string]*v1beta1.NetworkPolicy
 
 	// rules is a storage that supports listing rules using multiple indexing functions.
 	// rules is thread-safe.
@@ -184,6 +191,20 @@ func (c *ruleCache) getNetworkPolicy(npName, npNamespace string) *v1beta1.Networ
 	return c.buildNetworkPolicyFromRules(npUID)
 }
 
+// getNetworkPolicyAndRuleIDs returns the cached NetworkPolicy of the provided
+// UID and the IDs of its rules. The returned bool indicates whether the
+// NetworkPolicy is found.
+func (c *ruleCache) getNetworkPolicyAndRuleIDs(uid string) (*v1beta1.NetworkPolicy, []string, bool) {
+	c.policyMapLock.RLock()
+	defer c.policyMapLock.RUnlock()
+	policy, exists := c.policyMap[uid]
+	if !exists {
+		return nil, nil, false
+	}
+	ruleIDs, _ := c.rules.IndexKeys(policyIndex, uid)
+	return policy, ruleIDs, true
+}
+
 func (c *ruleCache) buildNetworkPolicyFromRules(uid string) *v1beta1.NetworkPolicy {
 	var np *v1beta1.NetworkPolicy
 	rules, _ := c.rules.ByIndex(policyIndex, uid)
@@ -336,7 +357,7 @@ func newRuleCache(dirtyRuleHandler func(string), podUpdate <-chan v1beta1.PodRef
 	cache := &ruleCache{
 		podSetByGroup:     make(map[string]v1beta1.GroupMemberPodSet),
 		addressSetByGroup: make(map[string]v1beta1.GroupMemberSet),
-		policyMap:         make(map[string]*types.NamespacedName),
+		policyMap:         make(map[string]*v1beta1.NetworkPolicy),
 		rules:             rules,
 		dirtyRuleHandler:  dirtyRuleHandler,
 		podUpdates:        podUpdate,
@@ -561,18 +582,27 @@ func (c *ruleCache) DeleteAppliedToGroup(group *v1beta1.AppliedToGroup) error {
 
 // toRule converts v1beta1.NetworkPolicyRule to *rule.
 func toRule(r *v1beta1.NetworkPolicyRule, policy *v1beta1.NetworkPolicy) *rule {
+	// The rule applies to the AppliedToGroups of the policy unless it has its
+	// own AppliedToGroups.
+	appliedToGroups := policy.AppliedToGroups
+	ownAppliedToGroups := len(r.AppliedToGroups) > 0
+	if ownAppliedToGroups {
+		appliedToGroups = r.AppliedToGroups
+	}
 	rule := &rule{
-		Direction:       r.Direction,
-		From:            r.From,
-		To:              r.To,
-		Services:        r.Services,
-		Action:          r.Action,
-		Priority:        r.Priority,
-		PolicyPriority:  policy.Priority,
-		TierPriority:    policy.TierPriority,
-		AppliedToGroups: policy.AppliedToGroups,
-		PolicyUID:       policy.UID,
-		SourceRef:       policy.SourceRef,
+		Direction:          r.Direction,
+		From:               r.From,
+		To:                 r.To,
+		Services:           r.Services,
+		Action:             r.Action,
+		Priority:           r.Priority,
+		PolicyPriority:     policy.Priority,
+		TierPriority:       policy.TierPriority,
+		AppliedToGroups:    appliedToGroups,
+		OwnAppliedToGroups: ownAppliedToGroups,
+		PolicyUID:          policy.UID,
+		SourceRef:          policy.SourceRef,
+		EnableLogging:      r.EnableLogging,
 	}
 	rule.ID = hashRule(rule)
 	rule.PolicyNamespace = policy.Namespace
@@ -623,14 +653,22 @@ func (c *ruleCache) AddNetworkPolicy(policy *v1beta1.NetworkPolicy) error {
 }
 
 func (c *ruleCache) addNetworkPolicyLocked(policy *v1beta1.NetworkPolicy) error {
-	c.policyMap[string(policy.UID)] = &types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}
+	c.policyMap[string(policy.UID)] = policy
 	metrics.NetworkPolicyCount.Inc()
-	return c.UpdateNetworkPolicy(policy)
+	return c.updateNetworkPolicyLocked(policy)
 }
 
 // UpdateNetworkPolicy updates a cached *v1beta1.NetworkPolicy.
 // The added rules and removed rules will be regarded as dirty.
 func (c *ruleCache) UpdateNetworkPolicy(policy *v1beta1.NetworkPolicy) error {
+	c.policyMapLock.Lock()
+	defer c.policyMapLock.Unlock()
+
+	c.policyMap[string(policy.UID)] = policy
+	return c.updateNetworkPolicyLocked(policy)
+}
+
+func (c *ruleCache) updateNetworkPolicyLocked(policy *v1beta1.NetworkPolicy) error {
 	existingRules, _ := c.rules.ByIndex(policyIndex, string(policy.UID))
 	ruleByID := map[string]interface{}{}
 	for _, r := range existingRules {
@@ -717,7 +755,7 @@ func (c *ruleCache) GetCompletedRule(ruleID string) (completedRule *CompletedRul
 		return nil, true, false
 	}
 
-	pods, completed := c.unionAppliedToGroups(r.AppliedToGroups)
+	pods, completed := c.unionAppliedToGroups(r.AppliedToGroups, r.OwnAppliedToGroups)
 	if !completed {
 		return nil, true, false
 	}
@@ -770,8 +808,9 @@ func (c *ruleCache) unionAddressGroups(groupNames []string) (v1beta1.GroupMember
 
 // unionAppliedToGroups gets the union of pods of the provided appliedTo groups.
 // If any group is not found, nil and false will be returned to indicate the
-// set is not complete yet.
-func (c *ruleCache) unionAppliedToGroups(groupNames []string) (v1beta1.GroupMemberPodSet, bool) {
+// set is not complete yet, unless ignoreMissing is true, in which case the
+// missing groups are considered empty.
+func (c *ruleCache) unionAppliedToGroups(groupNames []string, ignoreMissing bool) (v1beta1.GroupMemberPodSet, bool) {
 	c.podSetLock.RLock()
 	defer c.podSetLock.RUnlock()
 
@@ -779,6 +818,9 @@ func (c *ruleCache) unionAppliedToGroups(groupNames []string) (v1beta1.GroupMemb
 	for _, groupName := range groupNames {
 		curSet, exists := c.podSetByGroup[groupName]
 		if !exists {
+			if ignoreMissing {
+				continue
+			}
 			klog.V(2).Infof("AppliedToGroup %v was not found", groupName)
 			return nil, false
 		}
@@ -22,7 +22,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
@@ -434,7 +433,9 @@ func TestRuleCacheReplaceNetworkPolicies(t *testing.T) {
 			c, recorder, _ := newFakeRuleCache()
 			for _, rule := range tt.rules {
 				c.rules.Add(rule)
-				c.policyMap[string(rule.PolicyUID)] = &types.NamespacedName{Namespace: rule.PolicyNamespace, Name: rule.PolicyName}
+				c.policyMap[string(rule.PolicyUID)] = &v1beta1.NetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{UID: rule.PolicyUID, Namespace: rule.PolicyNamespace, Name: rule.PolicyName},
+				}
 			}
 			c.ReplaceNetworkPolicies(tt.args)
 
@@ -572,6 +573,28 @@ func TestRuleCacheAddNetworkPolicy(t *testing.T) {
 	}
 }
 
+func TestToRuleAppliedToGroups(t *testing.T) {
+	networkPolicy := &v1beta1.NetworkPolicy{
+		ObjectMeta:      metav1.ObjectMeta{UID: "policy1", Name: "name1"},
+		AppliedToGroups: []string{"appliedToGroup1", "appliedToGroup2"},
+	}
+	policyRule := &v1beta1.NetworkPolicyRule{
+		Direction: v1beta1.DirectionIn,
+		From:      v1beta1.NetworkPolicyPeer{AddressGroups: []string{"addressGroup1"}},
+	}
+	ruleWithAppliedToGroups := policyRule.DeepCopy()
+	ruleWithAppliedToGroups.AppliedToGroups = []string{"appliedToGroup2"}
+
+	// A rule without its own AppliedToGroups applies to the ones of the policy.
+	rule1 := toRule(policyRule, networkPolicy)
+	assert.Equal(t, []string{"appliedToGroup1", "appliedToGroup2"}, rule1.AppliedToGroups)
+	assert.False(t, rule1.OwnAppliedToGroups)
+	rule2 := toRule(ruleWithAppliedToGroups, networkPolicy)
+	assert.Equal(t, []string{"appliedToGroup2"}, rule2.AppliedToGroups)
+	assert.True(t, rule2.OwnAppliedToGroups)
+	assert.NotEqual(t, rule1.ID, rule2.ID)
+}
+
 func TestRuleCacheDeleteNetworkPolicy(t *testing.T) {
 	rule1 := &rule{
 		ID:        "rule1",
@@ -662,6 +685,19 @@ func TestRuleCacheGetCompletedRule(t *testing.T) {
 		From:            v1beta1.NetworkPolicyPeer{AddressGroups: []string{"addressGroup1", "addressGroup2", "addressGroup3"}},
 		AppliedToGroups: []string{"appliedToGroup1", "appliedToGroup2"},
 	}
+	rule4 := &rule{
+		ID:                 "rule4",
+		Direction:          v1beta1.DirectionIn,
+		From:               v1beta1.NetworkPolicyPeer{AddressGroups: []string{"addressGroup1"}},
+		AppliedToGroups:    []string{"appliedToGroup1", "appliedToGroup3"},
+		OwnAppliedToGroups: true,
+	}
+	rule5 := &rule{
+		ID:              "rule5",
+		Direction:       v1beta1.DirectionIn,
+		From:            v1beta1.NetworkPolicyPeer{AddressGroups: []string{"addressGroup1"}},
+		AppliedToGroups: []string{"appliedToGroup1", "appliedToGroup3"},
+	}
 	tests := []struct {
 		name              string
 		args              string
@@ -700,9 +736,28 @@ func TestRuleCacheGetCompletedRule(t *testing.T) {
 			true,
 			false,
 		},
+		{
+			"rule-with-missing-own-appliedto-group",
+			rule4.ID,
+			&CompletedRule{
+				rule:          rule4,
+				FromAddresses: addressGroup1,
+				ToAddresses:   nil,
+				Pods:          appliedToGroup1,
+			},
+			true,
+			true,
+		},
+		{
+			"rule-with-missing-policy-appliedto-group",
+			rule5.ID,
+			nil,
+			true,
+			false,
+		},
 		{
 			"non-existing-rule",
-			"rule4",
+			"rule6",
 			nil,
 			false,
 			false,
@@ -718,6 +773,8 @@ func TestRuleCacheGetCompletedRule(t *testing.T) {
 			c.rules.Add(rule1)
 			c.rules.Add(rule2)
 			c.rules.Add(rule3)
+			c.rules.Add(rule4)
+			c.rules.Add(rule5)
 
 			gotCompletedRule, gotExists, gotCompleted := c.GetCompletedRule(tt.args)
 			if !reflect.DeepEqual(gotCompletedRule, tt.wantCompletedRule) {
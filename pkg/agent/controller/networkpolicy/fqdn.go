@@ -0,0 +1,528 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/libOpenflow/util"
+	"github.com/contiv/ofnet/ofctrl"
+	"golang.org/x/net/dns/dnsmessage"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+)
+
+const (
+	// fqdnWildcardRegex is the regular expression a wildcard "*" in an FQDN
+	// selector is converted to. It matches any sequence of characters which
+	// are valid in a domain name.
+	fqdnWildcardRegex = "[-a-z0-9.]*"
+	// dnsResponseHoldTimeout is the maximum time a DNS response is held
+	// while waiting for the rules updated with the IPs in it to be realized,
+	// after which it's reinjected anyway.
+	dnsResponseHoldTimeout = 2 * time.Second
+	// tcpMinHeaderLen is the length of the TCP header without options.
+	tcpMinHeaderLen = 20
+)
+
+// fqdnSelector matches FQDNs with an exact name, or with a regular expression
+// if the name contains wildcards.
+type fqdnSelector struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+func newFQDNSelector(name string) *fqdnSelector {
+	name = normalizeFQDN(name)
+	selector := &fqdnSelector{name: name}
+	if strings.Contains(name, "*") {
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(name), `\*`, fqdnWildcardRegex)
+		selector.regex = regexp.MustCompile("^" + pattern + "$")
+	}
+	return selector
+}
+
+func (s *fqdnSelector) matches(fqdn string) bool {
+	if s.regex != nil {
+		return s.regex.MatchString(fqdn)
+	}
+	return s.name == fqdn
+}
+
+// normalizeFQDN converts the provided FQDN to lower case and removes the
+// trailing dot of it, as domain names are case-insensitive.
+func normalizeFQDN(fqdn string) string {
+	return strings.TrimSuffix(strings.ToLower(fqdn), ".")
+}
+
+// fqdnController learns the IP addresses of FQDNs from the DNS responses
+// received by local Pods, and maintains them until their TTLs expire. It
+// notifies the rules with matching FQDN selectors when the IP addresses of an
+// FQDN change, so that the reconciler can update the flows of the rules.
+//
+// The flows sending the DNS responses to the controller are only installed
+// when there are rules with FQDN selectors, to avoid the overhead for the
+// clusters not using them. The DNS responses are held by the flows, and are
+// reinjected after the rules updated with the IPs in them are realized, so that
+// the Pods never connect to the IPs before the connections are allowed.
+type fqdnController struct {
+	ofClient openflow.Client
+	// dirtyRuleHandler is called with the ID of a rule whose matched IP
+	// addresses have changed.
+	dirtyRuleHandler func(string)
+	// expirationQueue maintains the FQDNs whose IP addresses need to be
+	// checked for expiration at a given time.
+	expirationQueue workqueue.DelayingInterface
+	clock           clock.Clock
+
+	mutex sync.Mutex
+	// dnsEntryCache maps FQDNs to the IP addresses they resolve to. Each IP
+	// address is mapped to its expiration time.
+	dnsEntryCache map[string]map[string]time.Time
+	// selectorsByRule maps the IDs of the rules with FQDN peers to their
+	// FQDN selectors.
+	selectorsByRule map[string][]*fqdnSelector
+	// pendingRuleSyncs maps the IDs of the rules whose IPs have changed but
+	// have not been got for reconciliation to the channel closed when the
+	// rules are realized with the changed IPs.
+	pendingRuleSyncs map[string]chan struct{}
+	// syncingRuleSyncs maps the IDs of the rules whose IPs have been got for
+	// reconciliation to the channels closed when the reconciliation succeeds.
+	syncingRuleSyncs map[string][]chan struct{}
+}
+
+func newFQDNController(ofClient openflow.Client, dirtyRuleHandler func(string), clock clock.Clock) *fqdnController {
+	return &fqdnController{
+		ofClient:         ofClient,
+		dirtyRuleHandler: dirtyRuleHandler,
+		expirationQueue:  workqueue.NewDelayingQueueWithCustomClock(clock, "fqdn"),
+		clock:            clock,
+		dnsEntryCache:    map[string]map[string]time.Time{},
+		selectorsByRule:  map[string][]*fqdnSelector{},
+		pendingRuleSyncs: map[string]chan struct{}{},
+		syncingRuleSyncs: map[string][]chan struct{}{},
+	}
+}
+
+// addFQDNRule registers the FQDN selectors of the provided rule. The flows
+// intercepting DNS responses are installed when the first rule is registered.
+func (f *fqdnController) addFQDNRule(ruleID string, fqdns []string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, exists := f.selectorsByRule[ruleID]; exists {
+		return nil
+	}
+	if len(f.selectorsByRule) == 0 {
+		if err := f.ofClient.InstallDNSInterceptFlows(); err != nil {
+			return fmt.Errorf("error installing DNS intercept flows: %v", err)
+		}
+	}
+	selectors := make([]*fqdnSelector, 0, len(fqdns))
+	for _, fqdn := range fqdns {
+		selectors = append(selectors, newFQDNSelector(fqdn))
+	}
+	f.selectorsByRule[ruleID] = selectors
+	return nil
+}
+
+// deleteFQDNRule unregisters the FQDN selectors of the provided rule. The flows
+// intercepting DNS responses are removed when the last rule is unregistered.
+func (f *fqdnController) deleteFQDNRule(ruleID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, exists := f.selectorsByRule[ruleID]; !exists {
+		return nil
+	}
+	if len(f.selectorsByRule) == 1 {
+		if err := f.ofClient.UninstallDNSInterceptFlows(); err != nil {
+			return fmt.Errorf("error uninstalling DNS intercept flows: %v", err)
+		}
+	}
+	delete(f.selectorsByRule, ruleID)
+	// The DNS responses waiting for the rule don't need to wait anymore.
+	if ch, exists := f.pendingRuleSyncs[ruleID]; exists {
+		close(ch)
+		delete(f.pendingRuleSyncs, ruleID)
+	}
+	f.closeSyncingRuleSyncs(ruleID)
+	return nil
+}
+
+// getIPsForRule returns the IP addresses of all the FQDNs matched by the FQDN
+// selectors of the provided rule. It's called when reconciling the rule, the
+// DNS responses waiting for the rule are then notified by onRuleRealized.
+func (f *fqdnController) getIPsForRule(ruleID string) sets.String {
+	ips := sets.NewString()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if ch, exists := f.pendingRuleSyncs[ruleID]; exists {
+		f.syncingRuleSyncs[ruleID] = append(f.syncingRuleSyncs[ruleID], ch)
+		delete(f.pendingRuleSyncs, ruleID)
+	}
+	selectors := f.selectorsByRule[ruleID]
+	for fqdn, entry := range f.dnsEntryCache {
+		for _, selector := range selectors {
+			if selector.matches(fqdn) {
+				for ip := range entry {
+					ips.Insert(ip)
+				}
+				break
+			}
+		}
+	}
+	return ips
+}
+
+// onRuleRealized notifies the DNS responses waiting for the provided rule that
+// the rule has been realized with the IPs got by getIPsForRule.
+func (f *fqdnController) onRuleRealized(ruleID string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.closeSyncingRuleSyncs(ruleID)
+}
+
+// closeSyncingRuleSyncs must be called with the mutex held.
+func (f *fqdnController) closeSyncingRuleSyncs(ruleID string) {
+	for _, ch := range f.syncingRuleSyncs[ruleID] {
+		close(ch)
+	}
+	delete(f.syncingRuleSyncs, ruleID)
+}
+
+// onDNSResponse updates the IP addresses of the provided FQDN with the ones
+// learned from a DNS response. The IP addresses which are not in the response
+// are kept until they expire, as DNS servers may return a subset of the IP
+// addresses of an FQDN in each response. It returns the channels which are
+// closed when the rules affected by the new IP addresses are realized.
+func (f *fqdnController) onDNSResponse(fqdn string, ips map[string]time.Time) []<-chan struct{} {
+	if len(ips) == 0 {
+		return nil
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	entry, exists := f.dnsEntryCache[fqdn]
+	if !exists {
+		entry = map[string]time.Time{}
+		f.dnsEntryCache[fqdn] = entry
+	}
+	ipsChanged := false
+	var nextExpiration time.Time
+	for ip, expiration := range ips {
+		if oldExpiration, exists := entry[ip]; !exists {
+			ipsChanged = true
+			entry[ip] = expiration
+		} else if expiration.After(oldExpiration) {
+			entry[ip] = expiration
+		}
+		if nextExpiration.IsZero() || expiration.Before(nextExpiration) {
+			nextExpiration = expiration
+		}
+	}
+	f.expirationQueue.AddAfter(fqdn, nextExpiration.Sub(f.clock.Now()))
+	if !ipsChanged {
+		return nil
+	}
+	klog.V(2).Infof("IP addresses of FQDN %s changed: %v", fqdn, entry)
+	return f.notifyRulesForFQDN(fqdn)
+}
+
+// expireFQDN removes the expired IP addresses of the provided FQDN, and
+// schedules the next check if there are remaining IP addresses.
+func (f *fqdnController) expireFQDN(fqdn string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	entry, exists := f.dnsEntryCache[fqdn]
+	if !exists {
+		return
+	}
+	now := f.clock.Now()
+	ipsChanged := false
+	var nextExpiration time.Time
+	for ip, expiration := range entry {
+		if !expiration.After(now) {
+			delete(entry, ip)
+			ipsChanged = true
+		} else if nextExpiration.IsZero() || expiration.Before(nextExpiration) {
+			nextExpiration = expiration
+		}
+	}
+	if len(entry) == 0 {
+		delete(f.dnsEntryCache, fqdn)
+	} else {
+		f.expirationQueue.AddAfter(fqdn, nextExpiration.Sub(now))
+	}
+	if ipsChanged {
+		klog.V(2).Infof("IP addresses of FQDN %s expired", fqdn)
+		f.notifyRulesForFQDN(fqdn)
+	}
+}
+
+// notifyRulesForFQDN calls dirtyRuleHandler for the rules with FQDN selectors
+// matching the provided FQDN, and returns the channels which are closed when
+// the rules are realized. It must be called with the mutex held.
+func (f *fqdnController) notifyRulesForFQDN(fqdn string) []<-chan struct{} {
+	var syncChs []<-chan struct{}
+	for ruleID, selectors := range f.selectorsByRule {
+		for _, selector := range selectors {
+			if selector.matches(fqdn) {
+				ch, exists := f.pendingRuleSyncs[ruleID]
+				if !exists {
+					ch = make(chan struct{})
+					f.pendingRuleSyncs[ruleID] = ch
+				}
+				syncChs = append(syncChs, ch)
+				f.dirtyRuleHandler(ruleID)
+				break
+			}
+		}
+	}
+	return syncChs
+}
+
+// Run starts the worker which removes the expired IP addresses of FQDNs. It
+// will not return until stopCh is closed.
+func (f *fqdnController) Run(stopCh <-chan struct{}) {
+	defer f.expirationQueue.ShutDown()
+	go wait.Until(f.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (f *fqdnController) worker() {
+	for {
+		key, quit := f.expirationQueue.Get()
+		if quit {
+			return
+		}
+		f.expireFQDN(key.(string))
+		f.expirationQueue.Done(key)
+	}
+}
+
+// handlePacketIn learns the IP addresses of FQDNs from the DNS response sent to
+// the controller by the DNS intercept flows, and reinjects the DNS response
+// after the rules updated with the learned IP addresses are realized. The DNS
+// response is reinjected even if it cannot be parsed, to not break the DNS
+// resolution of the Pods.
+func (f *fqdnController) handlePacketIn(pktIn *ofctrl.PacketIn) error {
+	syncChs, err := f.learnDNSResponse(pktIn)
+	if len(syncChs) == 0 {
+		if reinjectErr := f.ofClient.ReinjectDNSResponse(pktIn); reinjectErr != nil {
+			klog.Errorf("Failed to reinject DNS response: %v", reinjectErr)
+		}
+	} else {
+		go f.reinjectDNSResponseAfterSync(pktIn, syncChs)
+	}
+	return err
+}
+
+// reinjectDNSResponseAfterSync waits for the provided channels to be closed or
+// dnsResponseHoldTimeout to elapse, and reinjects the DNS response.
+func (f *fqdnController) reinjectDNSResponseAfterSync(pktIn *ofctrl.PacketIn, syncChs []<-chan struct{}) {
+	timeoutCh := f.clock.After(dnsResponseHoldTimeout)
+waitLoop:
+	for _, ch := range syncChs {
+		select {
+		case <-ch:
+		case <-timeoutCh:
+			klog.Warningf("Timeout when waiting for the rules updated by DNS response to be realized")
+			break waitLoop
+		}
+	}
+	if err := f.ofClient.ReinjectDNSResponse(pktIn); err != nil {
+		klog.Errorf("Failed to reinject DNS response: %v", err)
+	}
+}
+
+// learnDNSResponse learns the IP addresses of FQDNs from the DNS response in
+// the provided packet, and returns the channels which are closed when the rules
+// affected by the learned IP addresses are realized. The DNS responses over TCP
+// are only learned if the DNS message is in a single segment.
+func (f *fqdnController) learnDNSResponse(pktIn *ofctrl.PacketIn) ([]<-chan struct{}, error) {
+	var ipProtocol uint8
+	var ipPayload util.Message
+	switch pktIn.Data.Ethertype {
+	case protocol.IPv4_MSG:
+		ipPkt, ok := pktIn.Data.Data.(*protocol.IPv4)
+		if !ok {
+			return nil, errors.New("invalid IPv4 packet of DNS response")
+		}
+		ipProtocol, ipPayload = ipPkt.Protocol, ipPkt.Data
+	case protocol.IPv6_MSG:
+		// The Ethernet payload is not parsed for IPv6 packets.
+		ipData, err := pktIn.Data.Data.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get IPv6 packet of DNS response: %v", err)
+		}
+		ipPkt := new(protocol.IPv6)
+		if err := ipPkt.UnmarshalBinary(ipData); err != nil {
+			return nil, fmt.Errorf("failed to parse IPv6 packet of DNS response: %v", err)
+		}
+		ipProtocol, ipPayload = ipPkt.NextHeader, ipPkt.Data
+	default:
+		return nil, fmt.Errorf("unsupported ethertype %#x of DNS response", pktIn.Data.Ethertype)
+	}
+	var dnsData []byte
+	switch ipProtocol {
+	case protocol.Type_UDP:
+		udpPkt, ok := ipPayload.(*protocol.UDP)
+		if !ok {
+			return nil, errors.New("invalid UDP datagram of DNS response")
+		}
+		dnsData = udpPkt.Data
+	case protocol.Type_TCP:
+		tcpData, err := ipPayload.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TCP segment of DNS response: %v", err)
+		}
+		var complete bool
+		if dnsData, complete = getDNSMessageFromTCPSegment(tcpData); !complete {
+			// The segment has no payload, e.g. the handshake, or has a
+			// partial DNS message.
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported IP protocol %d of DNS response", ipProtocol)
+	}
+	responses, err := parseDNSResponse(dnsData, f.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNS response: %v", err)
+	}
+	var syncChs []<-chan struct{}
+	for fqdn, ips := range responses {
+		syncChs = append(syncChs, f.onDNSResponse(fqdn, ips)...)
+	}
+	return syncChs, nil
+}
+
+// getDNSMessageFromTCPSegment returns the DNS message in the payload of the
+// provided TCP segment, and whether the payload has a complete DNS message.
+// Over TCP, the DNS message is prefixed with its length in two bytes.
+func getDNSMessageFromTCPSegment(tcpData []byte) ([]byte, bool) {
+	if len(tcpData) < tcpMinHeaderLen {
+		return nil, false
+	}
+	headerLen := int(tcpData[12]>>4) * 4
+	if headerLen < tcpMinHeaderLen || len(tcpData) < headerLen+2 {
+		return nil, false
+	}
+	payload := tcpData[headerLen:]
+	msgLen := int(binary.BigEndian.Uint16(payload[:2]))
+	if len(payload) < 2+msgLen {
+		return nil, false
+	}
+	return payload[2 : 2+msgLen], true
+}
+
+// parseDNSResponse returns the IP addresses of the FQDNs in the answers of the
+// provided DNS response, with their expiration times calculated from the TTLs.
+// If an FQDN is an alias of another name via CNAME records, the IP addresses
+// of the canonical name are also returned for the alias.
+func parseDNSResponse(data []byte, now time.Time) (map[string]map[string]time.Time, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(data)
+	if err != nil {
+		return nil, err
+	}
+	if !header.Response || header.RCode != dnsmessage.RCodeSuccess {
+		return nil, nil
+	}
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, err
+	}
+	responses := map[string]map[string]time.Time{}
+	addIP := func(fqdn string, ip net.IP, ttl uint32) {
+		if responses[fqdn] == nil {
+			responses[fqdn] = map[string]time.Time{}
+		}
+		responses[fqdn][ip.String()] = now.Add(time.Duration(ttl) * time.Second)
+	}
+	// aliases maps canonical names to the names aliased to them.
+	aliases := map[string][]string{}
+	for {
+		answer, err := parser.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fqdn := normalizeFQDN(answer.Name.String())
+		switch answer.Type {
+		case dnsmessage.TypeA:
+			r, err := parser.AResource()
+			if err != nil {
+				return nil, err
+			}
+			addIP(fqdn, net.IP(r.A[:]), answer.TTL)
+		case dnsmessage.TypeAAAA:
+			r, err := parser.AAAAResource()
+			if err != nil {
+				return nil, err
+			}
+			addIP(fqdn, net.IP(r.AAAA[:]), answer.TTL)
+		case dnsmessage.TypeCNAME:
+			r, err := parser.CNAMEResource()
+			if err != nil {
+				return nil, err
+			}
+			canonicalName := normalizeFQDN(r.CNAME.String())
+			aliases[canonicalName] = append(aliases[canonicalName], fqdn)
+		default:
+			if err := parser.SkipAnswer(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	canonicalNames := make([]string, 0, len(responses))
+	for fqdn := range responses {
+		canonicalNames = append(canonicalNames, fqdn)
+	}
+	for _, canonicalName := range canonicalNames {
+		ips := responses[canonicalName]
+		// Walk the alias chain of the canonical name. visited prevents
+		// looping forever on malformed responses with CNAME loops.
+		visited := sets.NewString(canonicalName)
+		pending := aliases[canonicalName]
+		for len(pending) > 0 {
+			alias := pending[0]
+			pending = pending[1:]
+			if visited.Has(alias) {
+				continue
+			}
+			visited.Insert(alias)
+			if responses[alias] == nil {
+				responses[alias] = map[string]time.Time{}
+			}
+			for ip, expiration := range ips {
+				responses[alias][ip] = expiration
+			}
+			pending = append(pending, aliases[alias]...)
+		}
+	}
+	return responses, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/libOpenflow/util"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+)
+
+func TestFQDNSelectorMatches(t *testing.T) {
+	tests := []struct {
+		selector string
+		fqdn     string
+		matches  bool
+	}{
+		{"www.example.com", "www.example.com", true},
+		{"WWW.Example.com.", "www.example.com", true},
+		{"www.example.com", "api.example.com", false},
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "api.eu.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "www.example.org", false},
+		{"*.example.com", "wwwexample.com", false},
+		{"api-*.example.com", "api-eu.example.com", true},
+		{"api-*.example.com", "www.example.com", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.matches, newFQDNSelector(tt.selector).matches(tt.fqdn), "selector %s, fqdn %s", tt.selector, tt.fqdn)
+	}
+}
+
+func newDNSResponse(t *testing.T, build func(b *dnsmessage.Builder)) []byte {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, RCode: dnsmessage.RCodeSuccess})
+	require.NoError(t, b.StartAnswers())
+	build(&b)
+	data, err := b.Finish()
+	require.NoError(t, err)
+	return data
+}
+
+func answerHeader(name string, rrType dnsmessage.Type, ttl uint32) dnsmessage.ResourceHeader {
+	return dnsmessage.ResourceHeader{
+		Name:  dnsmessage.MustNewName(name),
+		Type:  rrType,
+		Class: dnsmessage.ClassINET,
+		TTL:   ttl,
+	}
+}
+
+func TestParseDNSResponse(t *testing.T) {
+	now := time.Now()
+	data := newDNSResponse(t, func(b *dnsmessage.Builder) {
+		require.NoError(t, b.CNAMEResource(answerHeader("www.example.com.", dnsmessage.TypeCNAME, 300),
+			dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("example.cdn.net.")}))
+		require.NoError(t, b.AResource(answerHeader("Example.CDN.net.", dnsmessage.TypeA, 30),
+			dnsmessage.AResource{A: [4]byte{1, 1, 1, 1}}))
+		require.NoError(t, b.AResource(answerHeader("example.cdn.net.", dnsmessage.TypeA, 60),
+			dnsmessage.AResource{A: [4]byte{1, 1, 1, 2}}))
+		require.NoError(t, b.AAAAResource(answerHeader("example.cdn.net.", dnsmessage.TypeAAAA, 60),
+			dnsmessage.AAAAResource{AAAA: [16]byte{0xfd, 0, 15: 1}}))
+	})
+	expectedIPs := map[string]time.Time{
+		"1.1.1.1": now.Add(30 * time.Second),
+		"1.1.1.2": now.Add(60 * time.Second),
+		"fd00::1": now.Add(60 * time.Second),
+	}
+	responses, err := parseDNSResponse(data, now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]time.Time{
+		"example.cdn.net": expectedIPs,
+		"www.example.com": expectedIPs,
+	}, responses)
+
+	failure := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, RCode: dnsmessage.RCodeNameError})
+	data, err = failure.Finish()
+	require.NoError(t, err)
+	responses, err = parseDNSResponse(data, now)
+	require.NoError(t, err)
+	assert.Empty(t, responses)
+
+	_, err = parseDNSResponse([]byte{0x1}, now)
+	assert.Error(t, err)
+}
+
+func TestFQDNController(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	fakeClock := clock.NewFakeClock(time.Now())
+	dirtyRules := sets.NewString()
+	f := newFQDNController(mockOFClient, func(ruleID string) { dirtyRules.Insert(ruleID) }, fakeClock)
+
+	// The DNS intercept flows are only installed for the first rule.
+	mockOFClient.EXPECT().InstallDNSInterceptFlows().Times(1)
+	require.NoError(t, f.addFQDNRule("rule1", []string{"www.example.com"}))
+	require.NoError(t, f.addFQDNRule("rule2", []string{"*.example.com"}))
+
+	now := fakeClock.Now()
+	f.onDNSResponse("www.example.com", map[string]time.Time{"1.1.1.1": now.Add(10 * time.Second)})
+	f.onDNSResponse("api.example.com", map[string]time.Time{"1.1.1.2": now.Add(20 * time.Second)})
+	f.onDNSResponse("www.example.org", map[string]time.Time{"1.1.1.3": now.Add(20 * time.Second)})
+	assert.Equal(t, sets.NewString("rule1", "rule2"), dirtyRules)
+	assert.Equal(t, sets.NewString("1.1.1.1"), f.getIPsForRule("rule1"))
+	assert.Equal(t, sets.NewString("1.1.1.1", "1.1.1.2"), f.getIPsForRule("rule2"))
+
+	// Refreshing the expiration time of known IPs doesn't affect the rules.
+	dirtyRules = sets.NewString()
+	f.onDNSResponse("www.example.com", map[string]time.Time{"1.1.1.1": now.Add(30 * time.Second)})
+	assert.Empty(t, dirtyRules)
+
+	fakeClock.Step(20 * time.Second)
+	f.expireFQDN("www.example.com")
+	f.expireFQDN("api.example.com")
+	assert.Equal(t, sets.NewString("rule2"), dirtyRules)
+	assert.Equal(t, sets.NewString("1.1.1.1"), f.getIPsForRule("rule1"))
+	assert.Equal(t, sets.NewString("1.1.1.1"), f.getIPsForRule("rule2"))
+
+	// The DNS intercept flows are only uninstalled for the last rule.
+	mockOFClient.EXPECT().UninstallDNSInterceptFlows().Times(1)
+	require.NoError(t, f.deleteFQDNRule("rule1"))
+	require.NoError(t, f.deleteFQDNRule("rule2"))
+}
+
+func newDNSPacketIn(ipPkt *protocol.IPv4) *ofctrl.PacketIn {
+	dnsReason := uint32(openflow.CustomReasonDNS) << uint32(openflow.CustomReasonMarkRange[0])
+	return &ofctrl.PacketIn{
+		Match: openflow13.Match{Fields: []openflow13.MatchField{
+			*openflow13.NewRegMatchField(int(openflow.CustomReasonMarkReg), dnsReason, nil),
+		}},
+		Data: protocol.Ethernet{
+			HWDst:     rejectDstMAC,
+			HWSrc:     rejectSrcMAC,
+			Ethertype: protocol.IPv4_MSG,
+			Data:      ipPkt,
+		},
+	}
+}
+
+func TestFQDNControllerHandlePacketIn(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	fakeClock := clock.NewFakeClock(time.Now())
+	f := newFQDNController(mockOFClient, func(string) {}, fakeClock)
+	f.selectorsByRule["rule1"] = []*fqdnSelector{newFQDNSelector("www.example.com")}
+	c := &Controller{fqdnController: f}
+	reinjected := make(chan *ofctrl.PacketIn, 1)
+	mockOFClient.EXPECT().ReinjectDNSResponse(gomock.Any()).DoAndReturn(func(pktIn *ofctrl.PacketIn) error {
+		reinjected <- pktIn
+		return nil
+	}).AnyTimes()
+	assertReinjected := func(pktIn *ofctrl.PacketIn) {
+		select {
+		case p := <-reinjected:
+			assert.Equal(t, pktIn, p)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout when waiting for DNS response to be reinjected")
+		}
+	}
+	assertNotReinjected := func() {
+		select {
+		case <-reinjected:
+			t.Fatal("DNS response was reinjected before the rule was realized")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	// The DNS response over UDP is held until rule1 is realized with the new IP.
+	data := newDNSResponse(t, func(b *dnsmessage.Builder) {
+		require.NoError(t, b.AResource(answerHeader("www.example.com.", dnsmessage.TypeA, 30),
+			dnsmessage.AResource{A: [4]byte{1, 1, 1, 1}}))
+	})
+	ipPkt := newIPv4Packet(protocol.Type_UDP, uint16(20+8+len(data)))
+	ipPkt.NWSrc, ipPkt.NWDst = net.ParseIP("10.96.0.10"), net.ParseIP("10.10.0.2")
+	ipPkt.Data = &protocol.UDP{PortSrc: 53, PortDst: 34567, Length: uint16(8 + len(data)), Data: data}
+	pktIn := newDNSPacketIn(ipPkt)
+	require.NoError(t, c.HandlePacketIn(pktIn))
+	assertNotReinjected()
+	assert.Equal(t, sets.NewString("1.1.1.1"), f.getIPsForRule("rule1"))
+	assertNotReinjected()
+	f.onRuleRealized("rule1")
+	assertReinjected(pktIn)
+
+	// The DNS response without new IPs is reinjected immediately.
+	require.NoError(t, c.HandlePacketIn(pktIn))
+	assertReinjected(pktIn)
+
+	// The DNS response over TCP is held until the timeout if rule1 is not
+	// realized.
+	data = newDNSResponse(t, func(b *dnsmessage.Builder) {
+		require.NoError(t, b.AResource(answerHeader("www.example.com.", dnsmessage.TypeA, 30),
+			dnsmessage.AResource{A: [4]byte{1, 1, 1, 2}}))
+	})
+	tcpData := make([]byte, 20+2+len(data))
+	binary.BigEndian.PutUint16(tcpData[0:2], 53)
+	binary.BigEndian.PutUint16(tcpData[2:4], 34568)
+	tcpData[12] = 5 << 4
+	binary.BigEndian.PutUint16(tcpData[20:22], uint16(len(data)))
+	copy(tcpData[22:], data)
+	ipPkt = newIPv4Packet(protocol.Type_TCP, uint16(20+len(tcpData)))
+	ipPkt.Data = util.NewBuffer(tcpData)
+	pktIn = newDNSPacketIn(ipPkt)
+	require.NoError(t, c.HandlePacketIn(pktIn))
+	assert.Equal(t, sets.NewString("1.1.1.1", "1.1.1.2"), f.getIPsForRule("rule1"))
+	assertNotReinjected()
+	require.Eventually(t, fakeClock.HasWaiters, 5*time.Second, 10*time.Millisecond)
+	fakeClock.Step(dnsResponseHoldTimeout)
+	assertReinjected(pktIn)
+
+	// The TCP segment without a complete DNS message is reinjected
+	// immediately.
+	ipPkt = newIPv4Packet(protocol.Type_TCP, uint16(20+len(tcpData)-1))
+	ipPkt.Data = util.NewBuffer(tcpData[:len(tcpData)-1])
+	pktIn = newDNSPacketIn(ipPkt)
+	require.NoError(t, c.HandlePacketIn(pktIn))
+	assertReinjected(pktIn)
+}
@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/util/workqueue"
@@ -72,6 +73,21 @@ type Controller struct {
 	// reconciler provides interfaces to reconcile the desired state of
 	// NetworkPolicy rules with the actual state of Openflow entries.
 	reconciler Reconciler
+	// ofClient is used to send the reject responses for the packets dropped
+	// by the rules with the Reject action.
+	ofClient openflow.Client
+	// ifaceStore is used to resolve the Pods of the packets logged by the
+	// rules with logging enabled.
+	ifaceStore interfacestore.InterfaceStore
+	// auditLogger writes the audit logs of the rules with logging enabled.
+	auditLogger *auditLogger
+	// fqdnController learns the IPs of the FQDNs used in the rules from the
+	// DNS responses received by local Pods.
+	fqdnController *fqdnController
+	// statusController tracks the realization status of the rules and
+	// reports the realization status of Antrea-native policies to
+	// antrea-controller.
+	statusController *statusController
 
 	networkPolicyWatcher  *watcher
 	appliedToGroupWatcher *watcher
@@ -89,10 +105,19 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 	c := &Controller{
 		antreaClientProvider: antreaClientGetter,
 		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicyrule"),
-		reconciler:           newReconciler(ofClient, ifaceStore),
+		ofClient:             ofClient,
+		ifaceStore:           ifaceStore,
 		antreaPolicyEnabled:  antreaPolicyEnabled,
 	}
+	if antreaPolicyEnabled {
+		c.auditLogger = newFileAuditLogger()
+		c.fqdnController = newFQDNController(ofClient, c.enqueueRule, clock.RealClock{})
+	}
+	c.reconciler = newReconciler(ofClient, ifaceStore, c.fqdnController)
 	c.ruleCache = newRuleCache(c.enqueueRule, podUpdates)
+	if antreaPolicyEnabled {
+		c.statusController = newStatusController(antreaClientGetter, nodeName, c.ruleCache)
+	}
 	// Create a WaitGroup that is used to block network policy workers from asynchronously processing
 	// NP rules until the events preceding bookmark are synced. It can also be used as part of the
 	// solution to a deterministic mechanism for when to cleanup flows from previous round.
@@ -124,6 +149,7 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				return nil
 			}
 			c.ruleCache.AddNetworkPolicy(policy)
+			c.resyncPolicyStatus(policy)
 			klog.Infof("NetworkPolicy %s applied to Pods on this Node", policy.SourceRef.ToString())
 			return nil
 		},
@@ -138,6 +164,7 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				return nil
 			}
 			c.ruleCache.UpdateNetworkPolicy(policy)
+			c.resyncPolicyStatus(policy)
 			return nil
 		},
 		DeleteFunc: func(obj runtime.Object) error {
@@ -170,6 +197,14 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				klog.Infof("NetworkPolicy %s applied to Pods on this Node", policies[i].SourceRef.ToString())
 			}
 			c.ruleCache.ReplaceNetworkPolicies(policies)
+			// The statuses are reported again as antrea-controller may
+			// not have them after the watch is reconnected.
+			if c.statusController != nil {
+				c.statusController.ClearReportedStatuses()
+			}
+			for _, policy := range policies {
+				c.resyncPolicyStatus(policy)
+			}
 			return nil
 		},
 		fullSyncWaitGroup: &c.fullSyncGroup,
@@ -307,6 +342,18 @@ func (c *Controller) GetNetworkPolicy(npName, npNamespace string) *v1beta1.Netwo
 	return c.ruleCache.getNetworkPolicy(npName, npNamespace)
 }
 
+// GetNetworkPolicyByRuleFlowID returns the NetworkPolicy of the rule realized
+// with the provided Openflow ID, which is also the conjunction ID committed to
+// the conntrack label of the connections allowed by the rule.
+// nil is returned if no such rule exists.
+func (c *Controller) GetNetworkPolicyByRuleFlowID(ruleFlowID uint32) *v1beta1.NetworkPolicyReference {
+	rule, exists := c.reconciler.GetRuleByFlowID(ruleFlowID)
+	if !exists {
+		return nil
+	}
+	return rule.SourceRef
+}
+
 func (c *Controller) GetAddressGroups() []v1beta1.AddressGroup {
 	return c.ruleCache.GetAddressGroups()
 }
@@ -353,6 +400,14 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	// Batch install all rules in queue after fullSync is finished.
 	c.processAllItemsInQueue()
 
+	if c.fqdnController != nil {
+		go c.fqdnController.Run(stopCh)
+	}
+
+	if c.statusController != nil {
+		go c.statusController.Run(stopCh)
+	}
+
 	klog.Infof("Starting NetworkPolicy workers now")
 	for i := 0; i < defaultWorkers; i++ {
 		go wait.Until(c.worker, time.Second, stopCh)
@@ -365,6 +420,14 @@ func (c *Controller) enqueueRule(ruleID string) {
 	c.queue.Add(ruleID)
 }
 
+// resyncPolicyStatus triggers a report of the realization status of the
+// provided NetworkPolicy if it's an Antrea-native policy.
+func (c *Controller) resyncPolicyStatus(policy *v1beta1.NetworkPolicy) {
+	if c.statusController != nil && policy.SourceRef.Type != v1beta1.K8sNetworkPolicy {
+		c.statusController.Resync(policy.UID)
+	}
+}
+
 // worker runs a worker thread that just dequeues items, processes them, and
 // marks them done. You may run as many of these in parallel as you wish; the
 // workqueue guarantees that they will not end up processing the same rule at
@@ -419,6 +482,9 @@ func (c *Controller) syncRule(key string) error {
 		if err := c.reconciler.Forget(key); err != nil {
 			return err
 		}
+		if c.statusController != nil {
+			c.statusController.DeleteRuleRealization(key)
+		}
 		return nil
 	}
 	// If the rule is not complete, we can simply skip it as it will be marked as dirty
@@ -428,8 +494,17 @@ func (c *Controller) syncRule(key string) error {
 		return nil
 	}
 	if err := c.reconciler.Reconcile(rule); err != nil {
+		if c.statusController != nil {
+			c.statusController.SetRuleRealizationFailure(key, rule.PolicyUID, err)
+		}
 		return err
 	}
+	if c.statusController != nil {
+		c.statusController.SetRuleRealization(key, rule.PolicyUID)
+	}
+	if c.fqdnController != nil {
+		c.fqdnController.onRuleRealized(key)
+	}
 	return nil
 }
 
@@ -454,6 +529,14 @@ func (c *Controller) syncRules(keys []string) error {
 	if err := c.reconciler.BatchReconcile(allRules); err != nil {
 		return err
 	}
+	for _, rule := range allRules {
+		if c.statusController != nil {
+			c.statusController.SetRuleRealization(rule.ID, rule.PolicyUID)
+		}
+		if c.fqdnController != nil {
+			c.fqdnController.onRuleRealized(rule.ID)
+		}
+	}
 	return nil
 }
 
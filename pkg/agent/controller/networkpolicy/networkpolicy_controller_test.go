@@ -61,6 +61,7 @@ func newTestController() (*Controller, *fake.Clientset, *mockReconciler) {
 type mockReconciler struct {
 	sync.Mutex
 	lastRealized map[string]*CompletedRule
+	ruleByFlowID map[uint32]*CompletedRule
 	updated      chan string
 	deleted      chan string
 }
@@ -68,6 +69,7 @@ type mockReconciler struct {
 func newMockReconciler() *mockReconciler {
 	return &mockReconciler{
 		lastRealized: map[string]*CompletedRule{},
+		ruleByFlowID: map[uint32]*CompletedRule{},
 		updated:      make(chan string, 10),
 		deleted:      make(chan string, 10),
 	}
@@ -99,6 +101,13 @@ func (r *mockReconciler) Forget(ruleID string) error {
 	return nil
 }
 
+func (r *mockReconciler) GetRuleByFlowID(ruleFlowID uint32) (*CompletedRule, bool) {
+	r.Lock()
+	defer r.Unlock()
+	rule, exists := r.ruleByFlowID[ruleFlowID]
+	return rule, exists
+}
+
 func (r *mockReconciler) getLastRealized(ruleID string) (*CompletedRule, bool) {
 	r.Lock()
 	defer r.Unlock()
@@ -573,3 +582,16 @@ func TestNetworkPolicyMetrics(t *testing.T) {
 	waitForReconcilerDeleted()
 	checkNetworkPolicyMetrics()
 }
+
+func TestGetNetworkPolicyByRuleFlowID(t *testing.T) {
+	controller, _, reconciler := newTestController()
+	policyRef := &v1beta1.NetworkPolicyReference{
+		Type:      v1beta1.K8sNetworkPolicy,
+		Namespace: "ns1",
+		Name:      "policy1",
+	}
+	reconciler.ruleByFlowID[10] = &CompletedRule{rule: &rule{ID: "rule1", SourceRef: policyRef}}
+
+	assert.Equal(t, policyRef, controller.GetNetworkPolicyByRuleFlowID(10))
+	assert.Nil(t, controller.GetNetworkPolicyByRuleFlowID(11))
+}
@@ -52,6 +52,9 @@ type Reconciler interface {
 
 	// Forget cleanups the actual state of Openflow entries of the specified ruleID.
 	Forget(ruleID string) error
+
+	// GetRuleByFlowID returns the rule realized with the provided Openflow ID.
+	GetRuleByFlowID(ruleFlowID uint32) (*CompletedRule, bool)
 }
 
 // servicesKey is used to identify Services based on their numbered ports.
@@ -73,6 +76,9 @@ func normalizeServices(services []v1beta1.Service) servicesKey {
 			} else {
 				binary.Write(&b, binary.BigEndian, svc.Port.IntVal)
 			}
+			if svc.EndPort != nil {
+				binary.Write(&b, binary.BigEndian, *svc.EndPort)
+			}
 		}
 	}
 	return servicesKey(b.String())
@@ -139,6 +145,10 @@ type lastRealized struct {
 	// It's same in all Openflow rules, because named port is only for
 	// destination Pods.
 	podIPs sets.String
+	// The IP set we have realized for the FQDNs matched by the rule. It's
+	// only used for egress rule as part of its "to" addresses, and it's only
+	// in the Openflow rule of the original services like IPBlocks.
+	fqdnIPs sets.String
 }
 
 func newLastRealized(rule *CompletedRule) *lastRealized {
@@ -172,15 +182,23 @@ type reconciler struct {
 	// It's a mapping from ruleID to *lastRealized.
 	lastRealizeds sync.Map
 
+	// ruleIDsByOFID caches the rule each installed Openflow rule belongs to.
+	// It's a mapping from Openflow ID to ruleID.
+	ruleIDsByOFID sync.Map
+
 	// idAllocator provides interfaces to allocate and release uint32 id.
 	idAllocator *idAllocator
 
 	// priorityAssigners provides interfaces to manage OF priorities for each OVS table.
 	priorityAssigners map[binding.TableIDType]*tablePriorityAssigner
+
+	// fqdnController provides the IP addresses of the FQDNs matched by the
+	// rules. It's nil if Antrea-native policies are not enabled.
+	fqdnController *fqdnController
 }
 
 // newReconciler returns a new *reconciler.
-func newReconciler(ofClient openflow.Client, ifaceStore interfacestore.InterfaceStore) *reconciler {
+func newReconciler(ofClient openflow.Client, ifaceStore interfacestore.InterfaceStore, fqdnController *fqdnController) *reconciler {
 	priorityAssigners := map[binding.TableIDType]*tablePriorityAssigner{}
 	for _, table := range openflow.GetAntreaPolicySingleTierTables() {
 		priorityAssigners[table] = &tablePriorityAssigner{
@@ -198,6 +216,7 @@ func newReconciler(ofClient openflow.Client, ifaceStore interfacestore.Interface
 		lastRealizeds:     sync.Map{},
 		idAllocator:       newIDAllocator(),
 		priorityAssigners: priorityAssigners,
+		fqdnController:    fqdnController,
 	}
 	return reconciler
 }
@@ -210,7 +229,7 @@ func (r *reconciler) Reconcile(rule *CompletedRule) error {
 	var ofPriority *uint16
 
 	value, exists := r.lastRealizeds.Load(rule.ID)
-	ruleTable := r.getOFRuleTable(rule)
+	ruleTable := getOFRuleTable(rule)
 	priorityAssigner, _ := r.priorityAssigners[ruleTable]
 	if rule.isAntreaNetworkPolicyRule() {
 		// For CNP, only release priorityMutex after rule is installed on OVS. Otherwise,
@@ -238,7 +257,7 @@ func (r *reconciler) Reconcile(rule *CompletedRule) error {
 // getOFRuleTable retreives the OpenFlow table to install the CompletedRule.
 // The decision is made based on whether the rule is created for a CNP/ANP, and
 // the Tier of that NetworkPolicy.
-func (r *reconciler) getOFRuleTable(rule *CompletedRule) binding.TableIDType {
+func getOFRuleTable(rule *CompletedRule) binding.TableIDType {
 	if !rule.isAntreaNetworkPolicyRule() {
 		if rule.Direction == v1beta1.DirectionIn {
 			return openflow.IngressRuleTable
@@ -304,7 +323,7 @@ func (r *reconciler) BatchReconcile(rules []*CompletedRule) error {
 		return err
 	}
 	for _, rule := range rulesToInstall {
-		ruleTable := r.getOFRuleTable(rule)
+		ruleTable := getOFRuleTable(rule)
 		priorityAssigner := r.priorityAssigners[ruleTable]
 		klog.V(2).Infof("Adding rule %s of NetworkPolicy %s to be reconciled in batch", rule.ID, rule.SourceRef.ToString())
 		ofPriority, _ := r.getOFPriority(rule, ruleTable, priorityAssigner)
@@ -331,7 +350,7 @@ func (r *reconciler) registerOFPriorities(rules []*CompletedRule) error {
 	prioritiesToRegister := map[binding.TableIDType][]types.Priority{}
 	for _, rule := range rules {
 		if rule.isAntreaNetworkPolicyRule() {
-			ruleTable := r.getOFRuleTable(rule)
+			ruleTable := getOFRuleTable(rule)
 			p := types.Priority{
 				TierPriority:   *rule.TierPriority,
 				PolicyPriority: *rule.PolicyPriority,
@@ -351,6 +370,9 @@ func (r *reconciler) registerOFPriorities(rules []*CompletedRule) error {
 // add converts CompletedRule to PolicyRule(s) and invokes installOFRule to install them.
 func (r *reconciler) add(rule *CompletedRule, ofPriority *uint16, table binding.TableIDType) error {
 	klog.V(2).Infof("Adding new rule %v", rule)
+	if err := r.registerFQDNRule(rule); err != nil {
+		return err
+	}
 	ofRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriority, table)
 	for svcKey, ofRule := range ofRuleByServicesMap {
 		// Each pod group gets an Openflow ID.
@@ -364,6 +386,7 @@ func (r *reconciler) add(rule *CompletedRule, ofPriority *uint16, table binding.
 		}
 		// Record ofID only if its Openflow is installed successfully.
 		lastRealized.ofIDs[svcKey] = ofID
+		r.ruleIDsByOFID.Store(ofID, rule.ID)
 	}
 	return nil
 }
@@ -388,14 +411,15 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 			ofPorts := r.getPodOFPorts(pods)
 			lastRealized.podOFPorts[svcKey] = ofPorts
 			ofRuleByServicesMap[svcKey] = &types.PolicyRule{
-				Direction: v1beta1.DirectionIn,
-				From:      append(from1, from2...),
-				To:        ofPortsToOFAddresses(ofPorts),
-				Service:   filterUnresolvablePort(servicesMap[svcKey]),
-				Action:    rule.Action,
-				Priority:  ofPriority,
-				TableID:   table,
-				PolicyRef: rule.SourceRef,
+				Direction:     v1beta1.DirectionIn,
+				From:          append(from1, from2...),
+				To:            ofPortsToOFAddresses(ofPorts),
+				Service:       filterUnresolvablePort(servicesMap[svcKey]),
+				Action:        rule.Action,
+				Priority:      ofPriority,
+				TableID:       table,
+				PolicyRef:     rule.SourceRef,
+				EnableLogging: rule.EnableLogging,
 			}
 		}
 	} else {
@@ -405,36 +429,38 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 		memberByServicesMap, servicesMap := groupMembersByServices(rule.Services, rule.ToAddresses)
 		for svcKey, members := range memberByServicesMap {
 			ofRuleByServicesMap[svcKey] = &types.PolicyRule{
-				Direction: v1beta1.DirectionOut,
-				From:      from,
-				To:        groupMembersToOFAddresses(members),
-				Service:   filterUnresolvablePort(servicesMap[svcKey]),
-				Action:    rule.Action,
-				Priority:  ofPriority,
-				TableID:   table,
-				PolicyRef: rule.SourceRef,
+				Direction:     v1beta1.DirectionOut,
+				From:          from,
+				To:            groupMembersToOFAddresses(members),
+				Service:       filterUnresolvablePort(servicesMap[svcKey]),
+				Action:        rule.Action,
+				Priority:      ofPriority,
+				TableID:       table,
+				PolicyRef:     rule.SourceRef,
+				EnableLogging: rule.EnableLogging,
 			}
 		}
 
 		// If there are no "ToAddresses", the above process doesn't create any PolicyRule.
 		// We must ensure there is at least one PolicyRule, otherwise the Pods won't be
 		// isolated, so we create a PolicyRule with the original services if it doesn't exist.
-		// If there are IPBlocks or Pods that cannot resolve any named port, they will share
-		// this PolicyRule. Antrea policies do not need this default isolation.
-		if !rule.isAntreaNetworkPolicyRule() || len(rule.To.IPBlocks) > 0 {
+		// If there are IPBlocks, FQDNs or Pods that cannot resolve any named port, they will
+		// share this PolicyRule. Antrea policies do not need this default isolation.
+		if !rule.isAntreaNetworkPolicyRule() || len(rule.To.IPBlocks) > 0 || len(rule.To.FQDNs) > 0 {
 			svcKey := normalizeServices(rule.Services)
 			ofRule, exists := ofRuleByServicesMap[svcKey]
 			// Create a new Openflow rule if the group doesn't exist.
 			if !exists {
 				ofRule = &types.PolicyRule{
-					Direction: v1beta1.DirectionOut,
-					From:      from,
-					To:        []types.Address{},
-					Service:   filterUnresolvablePort(rule.Services),
-					Action:    rule.Action,
-					Priority:  nil,
-					TableID:   table,
-					PolicyRef: rule.SourceRef,
+					Direction:     v1beta1.DirectionOut,
+					From:          from,
+					To:            []types.Address{},
+					Service:       filterUnresolvablePort(rule.Services),
+					Action:        rule.Action,
+					Priority:      ofPriority,
+					TableID:       table,
+					PolicyRef:     rule.SourceRef,
+					EnableLogging: rule.EnableLogging,
 				}
 				ofRuleByServicesMap[svcKey] = ofRule
 			}
@@ -443,6 +469,11 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 				to := ipBlocksToOFAddresses(rule.To.IPBlocks)
 				ofRule.To = append(ofRule.To, to...)
 			}
+			if len(rule.To.FQDNs) > 0 && r.fqdnController != nil {
+				fqdnIPs := r.fqdnController.getIPsForRule(rule.ID)
+				lastRealized.fqdnIPs = fqdnIPs
+				ofRule.To = append(ofRule.To, ipsToOFAddresses(fqdnIPs)...)
+			}
 		}
 	}
 	return ofRuleByServicesMap, lastRealized
@@ -456,7 +487,10 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 	var allOFRules []*types.PolicyRule
 
 	for idx, rule := range rules {
-		ruleTable := r.getOFRuleTable(rule)
+		if err := r.registerFQDNRule(rule); err != nil {
+			return err
+		}
+		ruleTable := getOFRuleTable(rule)
 		ofRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriorities[idx], ruleTable)
 		lastRealizeds[idx] = lastRealized
 		for svcKey, ofRule := range ofRuleByServicesMap {
@@ -482,6 +516,7 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 		ofIDUpdatesByRule := ofIDUpdateMaps[i]
 		for svcKey, ofID := range ofIDUpdatesByRule {
 			lastRealized.ofIDs[svcKey] = ofID
+			r.ruleIDsByOFID.Store(ofID, lastRealized.ID)
 		}
 	}
 	return nil
@@ -517,20 +552,22 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 					return fmt.Errorf("error allocating Openflow ID")
 				}
 				ofRule := &types.PolicyRule{
-					Direction: v1beta1.DirectionIn,
-					From:      append(from1, from2...),
-					To:        ofPortsToOFAddresses(newOFPorts),
-					Service:   filterUnresolvablePort(servicesMap[svcKey]),
-					Action:    newRule.Action,
-					Priority:  ofPriority,
-					FlowID:    ofID,
-					TableID:   table,
-					PolicyRef: newRule.SourceRef,
+					Direction:     v1beta1.DirectionIn,
+					From:          append(from1, from2...),
+					To:            ofPortsToOFAddresses(newOFPorts),
+					Service:       filterUnresolvablePort(servicesMap[svcKey]),
+					Action:        newRule.Action,
+					Priority:      ofPriority,
+					FlowID:        ofID,
+					TableID:       table,
+					PolicyRef:     newRule.SourceRef,
+					EnableLogging: newRule.EnableLogging,
 				}
 				if err = r.installOFRule(ofRule); err != nil {
 					return err
 				}
 				lastRealized.ofIDs[svcKey] = ofID
+				r.ruleIDsByOFID.Store(ofID, newRule.ID)
 			} else {
 				addedTo := ofPortsToOFAddresses(newOFPorts.Difference(lastRealized.podOFPorts[svcKey]))
 				deletedTo := ofPortsToOFAddresses(lastRealized.podOFPorts[svcKey].Difference(newOFPorts))
@@ -551,12 +588,19 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 		memberByServicesMap, servicesMap := groupMembersByServices(newRule.Services, newRule.ToAddresses)
 		// Same as the process in `add`, we must ensure the group for the original services is present
 		// in memberByServicesMap, so that this group won't be removed and its "From" will be updated.
-		svcKey := normalizeServices(newRule.Services)
-		if _, exists := memberByServicesMap[svcKey]; !exists {
-			memberByServicesMap[svcKey] = v1beta1.NewGroupMemberSet()
-			servicesMap[svcKey] = newRule.Services
+		defaultSvcKey := normalizeServices(newRule.Services)
+		if _, exists := memberByServicesMap[defaultSvcKey]; !exists {
+			memberByServicesMap[defaultSvcKey] = v1beta1.NewGroupMemberSet()
+			servicesMap[defaultSvcKey] = newRule.Services
 		}
 		prevMembersByServicesMap, _ := groupMembersByServices(lastRealized.Services, lastRealized.ToAddresses)
+		// The IPs of the FQDNs matched by the rule can change without the
+		// rule being changed, and they are only in the Openflow rule of the
+		// original services.
+		var newFQDNIPs sets.String
+		if len(newRule.To.FQDNs) > 0 && r.fqdnController != nil {
+			newFQDNIPs = r.fqdnController.getIPsForRule(newRule.ID)
+		}
 		for svcKey, members := range memberByServicesMap {
 			ofID, exists := lastRealized.ofIDs[svcKey]
 			if !exists {
@@ -565,23 +609,32 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 					return fmt.Errorf("error allocating Openflow ID")
 				}
 				ofRule := &types.PolicyRule{
-					Direction: v1beta1.DirectionOut,
-					From:      from,
-					To:        groupMembersToOFAddresses(members),
-					Service:   filterUnresolvablePort(servicesMap[svcKey]),
-					Action:    newRule.Action,
-					Priority:  ofPriority,
-					FlowID:    ofID,
-					TableID:   table,
-					PolicyRef: newRule.SourceRef,
+					Direction:     v1beta1.DirectionOut,
+					From:          from,
+					To:            groupMembersToOFAddresses(members),
+					Service:       filterUnresolvablePort(servicesMap[svcKey]),
+					Action:        newRule.Action,
+					Priority:      ofPriority,
+					FlowID:        ofID,
+					TableID:       table,
+					PolicyRef:     newRule.SourceRef,
+					EnableLogging: newRule.EnableLogging,
+				}
+				if svcKey == defaultSvcKey && newFQDNIPs != nil {
+					ofRule.To = append(ofRule.To, ipsToOFAddresses(newFQDNIPs)...)
 				}
 				if err = r.installOFRule(ofRule); err != nil {
 					return err
 				}
 				lastRealized.ofIDs[svcKey] = ofID
+				r.ruleIDsByOFID.Store(ofID, newRule.ID)
 			} else {
 				addedTo := groupMembersToOFAddresses(members.Difference(prevMembersByServicesMap[svcKey]))
 				deletedTo := groupMembersToOFAddresses(prevMembersByServicesMap[svcKey].Difference(members))
+				if svcKey == defaultSvcKey && newFQDNIPs != nil {
+					addedTo = append(addedTo, ipsToOFAddresses(newFQDNIPs.Difference(lastRealized.fqdnIPs))...)
+					deletedTo = append(deletedTo, ipsToOFAddresses(lastRealized.fqdnIPs.Difference(newFQDNIPs))...)
+				}
 				if err := r.updateOFRule(ofID, addedFrom, addedTo, deletedFrom, deletedTo, ofPriority); err != nil {
 					return err
 				}
@@ -590,6 +643,7 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 			}
 		}
 		lastRealized.podIPs = newIPs
+		lastRealized.fqdnIPs = newFQDNIPs
 	}
 	// Remove stale Openflow rules.
 	for svcKey, ofID := range staleOFIDs {
@@ -659,6 +713,7 @@ func (r *reconciler) uninstallOFRule(ofID uint32, table binding.TableIDType) err
 			priorityAssigner.assigner.Release(uint16(priorityNum))
 		}
 	}
+	r.ruleIDsByOFID.Delete(ofID)
 	if err := r.idAllocator.release(ofID); err != nil {
 		// This should never happen. If it does, it is a programming error.
 		klog.Errorf("Error releasing Openflow ID for ofRule %v: %v", ofID, err)
@@ -678,7 +733,7 @@ func (r *reconciler) Forget(ruleID string) error {
 	}
 
 	lastRealized := value.(*lastRealized)
-	table := r.getOFRuleTable(lastRealized.CompletedRule)
+	table := getOFRuleTable(lastRealized.CompletedRule)
 	priorityAssigner, exists := r.priorityAssigners[table]
 	if exists {
 		priorityAssigner.mutex.Lock()
@@ -691,11 +746,39 @@ func (r *reconciler) Forget(ruleID string) error {
 		delete(lastRealized.ofIDs, svcKey)
 		delete(lastRealized.podOFPorts, svcKey)
 	}
+	if r.fqdnController != nil {
+		if err := r.fqdnController.deleteFQDNRule(ruleID); err != nil {
+			return err
+		}
+	}
 
 	r.lastRealizeds.Delete(ruleID)
 	return nil
 }
 
+// registerFQDNRule registers the FQDN selectors of the provided rule with the
+// fqdnController if the rule has any.
+func (r *reconciler) registerFQDNRule(rule *CompletedRule) error {
+	if len(rule.To.FQDNs) == 0 || r.fqdnController == nil {
+		return nil
+	}
+	return r.fqdnController.addFQDNRule(rule.ID, rule.To.FQDNs)
+}
+
+// GetRuleByFlowID returns the CompletedRule which has been realized with the
+// provided Openflow ID, and false if no such rule exists.
+func (r *reconciler) GetRuleByFlowID(ruleFlowID uint32) (*CompletedRule, bool) {
+	ruleID, exists := r.ruleIDsByOFID.Load(ruleFlowID)
+	if !exists {
+		return nil, false
+	}
+	value, exists := r.lastRealizeds.Load(ruleID)
+	if !exists {
+		return nil, false
+	}
+	return value.(*lastRealized).CompletedRule, true
+}
+
 func (r *reconciler) getPodOFPorts(pods v1beta1.GroupMemberPodSet) sets.Int32 {
 	ofPorts := sets.NewInt32()
 	for _, pod := range pods {
@@ -723,8 +806,10 @@ func (r *reconciler) getPodIPs(pods v1beta1.GroupMemberPodSet) sets.String {
 			continue
 		}
 		for _, iface := range ifaces {
-			klog.V(2).Infof("Got IP %v for Pod %s/%s", iface.IP, pod.Pod.Namespace, pod.Pod.Name)
-			ips.Insert(iface.IP.String())
+			klog.V(2).Infof("Got IPs %v for Pod %s/%s", iface.IPs, pod.Pod.Namespace, pod.Pod.Name)
+			for _, ipAddr := range iface.IPs {
+				ips.Insert(ipAddr.String())
+			}
 		}
 	}
 	return ips
@@ -835,13 +920,17 @@ func ipBlocksToOFAddresses(ipBlocks []v1beta1.IPBlock) []types.Address {
 	// Must not return nil as it means not restricted by addresses in Openflow implementation.
 	addresses := make([]types.Address, 0)
 	for _, b := range ipBlocks {
+		if len(b.Except) == 0 {
+			addresses = append(addresses, ipNetToOFAddress(b.CIDR))
+			continue
+		}
 		exceptIPNet := make([]*net.IPNet, 0, len(b.Except))
 		for _, c := range b.Except {
 			exceptIPNet = append(exceptIPNet, ip.IPNetToNetIPNet(&c))
 		}
 		diffCIDRs, err := ip.DiffFromCIDRs(ip.IPNetToNetIPNet(&b.CIDR), exceptIPNet)
 		if err != nil {
-			// Currently only IPv4 addresses are supported
+			// Currently only IPv4 addresses are supported when except CIDRs are specified
 			klog.Errorf("Error when determining diffCIDRs: %v", err)
 			continue
 		}
@@ -854,9 +943,13 @@ func ipBlocksToOFAddresses(ipBlocks []v1beta1.IPBlock) []types.Address {
 }
 
 func ipNetToOFAddress(in v1beta1.IPNet) *openflow.IPNetAddress {
+	bits := 32
+	if net.IP(in.IP).To4() == nil {
+		bits = 128
+	}
 	ipNet := net.IPNet{
 		IP:   net.IP(in.IP),
-		Mask: net.CIDRMask(int(in.PrefixLength), 32),
+		Mask: net.CIDRMask(int(in.PrefixLength), bits),
 	}
 	return openflow.NewIPNetAddress(ipNet)
 }
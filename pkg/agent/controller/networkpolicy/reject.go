@@ -0,0 +1,188 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+)
+
+const (
+	tcpFlagSYN = 0x02
+	tcpFlagRST = 0x04
+	tcpFlagACK = 0x10
+
+	icmpDstUnreachableType = 3
+	icmpSourceQuenchType   = 4
+	icmpRedirectType       = 5
+	icmpTimeExceededType   = 11
+	icmpParamProblemType   = 12
+	// icmpHostAdminProhibitedCode is the code of ICMP Destination Unreachable
+	// messages which indicates that the communication with the destination
+	// host is administratively prohibited.
+	icmpHostAdminProhibitedCode = 10
+	// icmpInvokingPayloadLen is the length of the original payload included in
+	// an ICMP error message, after the original IP header.
+	icmpInvokingPayloadLen = 8
+)
+
+// HandlePacketIn processes the packets sent to the controller by the flows of
+// Antrea-native policy rules, and the DNS responses sent to the controller for
+// the rules with FQDN peers.
+func (c *Controller) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
+	if pktIn == nil {
+		return errors.New("empty packet-in for NetworkPolicy")
+	}
+	marks, err := getRegValue(pktIn, int(openflow.CustomReasonMarkReg))
+	if err != nil {
+		return fmt.Errorf("custom reasons of NetworkPolicy packet-in cannot be got: %v", err)
+	}
+	customReasons := ofctrl.GetUint32ValueWithRange(marks, openflow.CustomReasonMarkRange.ToNXRange())
+	if customReasons&openflow.CustomReasonDNS == openflow.CustomReasonDNS {
+		if c.fqdnController == nil {
+			return errors.New("DNS response received while FQDN is not supported")
+		}
+		return c.fqdnController.handlePacketIn(pktIn)
+	}
+	if customReasons&openflow.CustomReasonLogging == openflow.CustomReasonLogging {
+		// Failing to log the packet should not prevent the reject response
+		// from being sent.
+		if err := c.logPacket(pktIn, marks); err != nil {
+			klog.Errorf("Failed to generate audit log for NetworkPolicy packet-in: %v", err)
+		}
+	}
+	if customReasons&openflow.CustomReasonReject == openflow.CustomReasonReject {
+		return c.rejectRequest(pktIn)
+	}
+	return nil
+}
+
+// rejectRequest sends a reject response for the packet dropped by a rule with
+// the Reject action. A TCP RST is sent for TCP packets, and an ICMP Destination
+// Unreachable message is sent for other packets. The response is output to the
+// OVS port from which the packet was received directly, so it reaches the
+// source of the packet without being processed by the OVS pipeline. Only IPv4
+// packets are supported, the IPv6 packets matched by the rules with the Reject
+// action are dropped without being sent to the controller.
+func (c *Controller) rejectRequest(pktIn *ofctrl.PacketIn) error {
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return fmt.Errorf("unsupported ethertype %#x of rejected packet", pktIn.Data.Ethertype)
+	}
+	ipPkt, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return errors.New("invalid IPv4 packet of rejected packet")
+	}
+	matches := pktIn.GetMatches()
+	inPortMatch := matches.GetMatchByName("OXM_OF_IN_PORT")
+	if inPortMatch == nil {
+		return errors.New("in_port of rejected packet not found")
+	}
+	inPort, ok := inPortMatch.GetValue().(uint32)
+	if !ok {
+		return errors.New("in_port of rejected packet cannot be got")
+	}
+	// If the packet was received from the tunnel, the response must be sent
+	// back to the Node from which the packet was sent.
+	var tunnelDstIP net.IP
+	if tunSrcMatch := matches.GetMatchByName("NXM_NX_TUN_IPV4_SRC"); tunSrcMatch != nil {
+		if tunSrcIP, ok := tunSrcMatch.GetValue().(net.IP); ok && !tunSrcIP.IsUnspecified() {
+			tunnelDstIP = tunSrcIP
+		}
+	}
+
+	// The response is sent from the destination of the rejected packet to
+	// its source.
+	srcMAC, dstMAC := pktIn.Data.HWDst, pktIn.Data.HWSrc
+	srcIP, dstIP := ipPkt.NWDst, ipPkt.NWSrc
+
+	if ipPkt.Protocol == protocol.Type_TCP {
+		tcpData, err := ipPkt.Data.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to get TCP header of rejected packet: %v", err)
+		}
+		tcpPkt := new(protocol.TCP)
+		if err := tcpPkt.UnmarshalBinary(tcpData); err != nil {
+			return fmt.Errorf("failed to parse TCP header of rejected packet: %v", err)
+		}
+		// The captured packet might be truncated, use the length in the IP
+		// header to calculate the length of the TCP segment.
+		seqNum, ackNum, flags := getTCPRSTFields(tcpPkt, int(ipPkt.Length)-int(ipPkt.IHL)*4)
+		klog.V(2).Infof("Sending TCP RST from %s:%d to %s:%d", srcIP, tcpPkt.PortDst, dstIP, tcpPkt.PortSrc)
+		return c.ofClient.SendTCPPacketOut(srcMAC, dstMAC, srcIP, dstIP, inPort, tunnelDstIP,
+			tcpPkt.PortDst, tcpPkt.PortSrc, seqNum, ackNum, flags)
+	}
+
+	if ipPkt.Protocol == protocol.Type_ICMP {
+		if icmpPkt, ok := ipPkt.Data.(*protocol.ICMP); ok && isICMPErrorMessage(icmpPkt.Type) {
+			// An ICMP error message must not be sent in response to
+			// another ICMP error message, see RFC 1122.
+			return nil
+		}
+	}
+	ipData, err := ipPkt.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to get IP header of rejected packet: %v", err)
+	}
+	icmpData := getICMPUnreachableData(ipData, int(ipPkt.IHL)*4)
+	klog.V(2).Infof("Sending ICMP Destination Unreachable from %s to %s", srcIP, dstIP)
+	return c.ofClient.SendICMPPacketOut(srcMAC, dstMAC, srcIP, dstIP, inPort, tunnelDstIP,
+		icmpDstUnreachableType, icmpHostAdminProhibitedCode, icmpData)
+}
+
+// getTCPRSTFields returns the sequence number, the acknowledgment number and
+// the flags of the TCP RST for the provided TCP segment, as defined in RFC 793:
+// if the segment has an ACK field, the RST takes its sequence number from the
+// ACK field, otherwise the RST has sequence number zero and the ACK field is
+// set to the sum of the sequence number and the segment length.
+func getTCPRSTFields(tcpPkt *protocol.TCP, tcpLen int) (uint32, uint32, uint8) {
+	if tcpPkt.Code&tcpFlagACK != 0 {
+		return tcpPkt.AckNum, 0, tcpFlagRST
+	}
+	segLen := uint32(tcpLen - int(tcpPkt.HdrLen)*4)
+	if tcpPkt.Code&tcpFlagSYN != 0 {
+		segLen++
+	}
+	return 0, tcpPkt.SeqNum + segLen, tcpFlagRST | tcpFlagACK
+}
+
+// getICMPUnreachableData returns the data of an ICMP Destination Unreachable
+// message for the provided IP packet, which includes 4 unused bytes, the IP
+// header and the first 8 bytes of the IP payload.
+func getICMPUnreachableData(ipData []byte, ipHdrLen int) []byte {
+	invokingLen := ipHdrLen + icmpInvokingPayloadLen
+	if invokingLen > len(ipData) {
+		invokingLen = len(ipData)
+	}
+	data := make([]byte, 4+invokingLen)
+	binary.BigEndian.PutUint32(data[:4], 0)
+	copy(data[4:], ipData[:invokingLen])
+	return data
+}
+
+func isICMPErrorMessage(icmpType uint8) bool {
+	switch icmpType {
+	case icmpDstUnreachableType, icmpSourceQuenchType, icmpRedirectType, icmpTimeExceededType, icmpParamProblemType:
+		return true
+	}
+	return false
+}
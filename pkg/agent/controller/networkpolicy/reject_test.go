@@ -0,0 +1,167 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+)
+
+var (
+	rejectSrcMAC, _ = net.ParseMAC("aa:bb:cc:dd:ee:01")
+	rejectDstMAC, _ = net.ParseMAC("aa:bb:cc:dd:ee:02")
+	rejectSrcIP     = net.ParseIP("10.10.0.1").To4()
+	rejectDstIP     = net.ParseIP("10.10.1.2").To4()
+	rejectTunSrcIP  = net.ParseIP("192.168.0.2").To4()
+)
+
+func newRejectPacketIn(inPort uint32, tunSrcIP net.IP, ipPkt *protocol.IPv4) *ofctrl.PacketIn {
+	customReasons := uint32(openflow.CustomReasonReject) << uint32(openflow.CustomReasonMarkRange[0])
+	fields := []openflow13.MatchField{
+		*openflow13.NewInPortField(inPort),
+		*openflow13.NewRegMatchField(int(openflow.CustomReasonMarkReg), customReasons, nil),
+	}
+	if tunSrcIP != nil {
+		fields = append(fields, *openflow13.NewTunnelIpv4SrcField(tunSrcIP, nil))
+	}
+	return &ofctrl.PacketIn{
+		Match: openflow13.Match{Fields: fields},
+		Data: protocol.Ethernet{
+			HWDst:     rejectDstMAC,
+			HWSrc:     rejectSrcMAC,
+			Ethertype: protocol.IPv4_MSG,
+			Data:      ipPkt,
+		},
+	}
+}
+
+func newIPv4Packet(ipProtocol uint8, length uint16) *protocol.IPv4 {
+	return &protocol.IPv4{
+		Version:  4,
+		IHL:      5,
+		Length:   length,
+		TTL:      64,
+		Protocol: ipProtocol,
+		NWSrc:    rejectSrcIP,
+		NWDst:    rejectDstIP,
+	}
+}
+
+func TestRejectTCP(t *testing.T) {
+	tests := []struct {
+		name           string
+		tcpFlags       uint8
+		payloadLen     int
+		tunSrcIP       net.IP
+		expectedSeqNum uint32
+		expectedAckNum uint32
+		expectedFlags  uint8
+		expectedTunDst net.IP
+	}{
+		{
+			name:           "syn",
+			tcpFlags:       tcpFlagSYN,
+			expectedSeqNum: 0,
+			expectedAckNum: 101,
+			expectedFlags:  tcpFlagRST | tcpFlagACK,
+		},
+		{
+			name:           "syn-from-tunnel",
+			tcpFlags:       tcpFlagSYN,
+			tunSrcIP:       rejectTunSrcIP,
+			expectedSeqNum: 0,
+			expectedAckNum: 101,
+			expectedFlags:  tcpFlagRST | tcpFlagACK,
+			expectedTunDst: rejectTunSrcIP,
+		},
+		{
+			name:           "ack-with-payload",
+			tcpFlags:       tcpFlagACK,
+			payloadLen:     10,
+			expectedSeqNum: 200,
+			expectedAckNum: 0,
+			expectedFlags:  tcpFlagRST,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			ofClient := openflowtest.NewMockClient(controller)
+			c := &Controller{ofClient: ofClient}
+
+			tcpPkt := &protocol.TCP{
+				PortSrc: 34567,
+				PortDst: 80,
+				SeqNum:  100,
+				AckNum:  200,
+				HdrLen:  5,
+				Code:    tt.tcpFlags,
+				Data:    make([]byte, tt.payloadLen),
+			}
+			ipPkt := newIPv4Packet(protocol.Type_TCP, 20+tcpPkt.Len())
+			ipPkt.Data = tcpPkt
+			pktIn := newRejectPacketIn(3, tt.tunSrcIP, ipPkt)
+
+			ofClient.EXPECT().SendTCPPacketOut(rejectDstMAC, rejectSrcMAC, rejectDstIP, rejectSrcIP, uint32(3), tt.expectedTunDst,
+				uint16(80), uint16(34567), tt.expectedSeqNum, tt.expectedAckNum, tt.expectedFlags)
+			require.NoError(t, c.HandlePacketIn(pktIn))
+		})
+	}
+}
+
+func TestRejectUDP(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	ofClient := openflowtest.NewMockClient(controller)
+	c := &Controller{ofClient: ofClient}
+
+	udpPkt := &protocol.UDP{PortSrc: 34567, PortDst: 53, Length: 20, Data: make([]byte, 12)}
+	ipPkt := newIPv4Packet(protocol.Type_UDP, 40)
+	ipPkt.Data = udpPkt
+	pktIn := newRejectPacketIn(3, nil, ipPkt)
+
+	ipData, err := ipPkt.MarshalBinary()
+	require.NoError(t, err)
+	expectedData := append(make([]byte, 4), ipData[:28]...)
+	ofClient.EXPECT().SendICMPPacketOut(rejectDstMAC, rejectSrcMAC, rejectDstIP, rejectSrcIP, uint32(3), nil,
+		uint8(icmpDstUnreachableType), uint8(icmpHostAdminProhibitedCode), expectedData)
+	require.NoError(t, c.HandlePacketIn(pktIn))
+}
+
+func TestRejectICMPErrorMessage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	ofClient := openflowtest.NewMockClient(controller)
+	c := &Controller{ofClient: ofClient}
+
+	icmpPkt := protocol.NewICMP()
+	icmpPkt.Type = icmpDstUnreachableType
+	icmpPkt.Data = make([]byte, 4)
+	ipPkt := newIPv4Packet(protocol.Type_ICMP, 28)
+	ipPkt.Data = icmpPkt
+	// No response should be sent for an ICMP error message.
+	assert.NoError(t, c.HandlePacketIn(newRejectPacketIn(3, nil, ipPkt)))
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+)
+
+// ruleRealization is the realization status of a rule.
+type ruleRealization struct {
+	policyID types.UID
+	// err is nil if the rule has been realized successfully.
+	err error
+}
+
+// statusController keeps track of the realization status of NetworkPolicy
+// rules and reports the realization status of Antrea-native policies to
+// antrea-controller once all their rules have been processed by the reconciler.
+type statusController struct {
+	nodeName             string
+	ruleCache            *ruleCache
+	antreaClientProvider agent.AntreaClientProvider
+
+	// queue maintains the UIDs of the NetworkPolicies whose realization
+	// status need to be reported.
+	queue workqueue.RateLimitingInterface
+
+	// realizedRules tracks the realization status of the rules, using rule
+	// ID as the key.
+	realizedRules     map[string]*ruleRealization
+	realizedRulesLock sync.RWMutex
+
+	// lastReported caches the last status reported for each NetworkPolicy to
+	// avoid sending duplicate reports.
+	lastReported     map[types.UID]v1beta1.NetworkPolicyNodeStatus
+	lastReportedLock sync.Mutex
+}
+
+func newStatusController(antreaClientProvider agent.AntreaClientProvider, nodeName string, ruleCache *ruleCache) *statusController {
+	return &statusController{
+		nodeName:             nodeName,
+		ruleCache:            ruleCache,
+		antreaClientProvider: antreaClientProvider,
+		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicystatus"),
+		realizedRules:        map[string]*ruleRealization{},
+		lastReported:         map[types.UID]v1beta1.NetworkPolicyNodeStatus{},
+	}
+}
+
+// SetRuleRealization marks the rule of the NetworkPolicy as realized.
+func (c *statusController) SetRuleRealization(ruleID string, policyID types.UID) {
+	c.setRuleRealization(ruleID, policyID, nil)
+}
+
+// SetRuleRealizationFailure marks the rule of the NetworkPolicy as failed to be
+// realized with the provided error.
+func (c *statusController) SetRuleRealizationFailure(ruleID string, policyID types.UID, err error) {
+	c.setRuleRealization(ruleID, policyID, err)
+}
+
+func (c *statusController) setRuleRealization(ruleID string, policyID types.UID, err error) {
+	c.realizedRulesLock.Lock()
+	defer c.realizedRulesLock.Unlock()
+	c.realizedRules[ruleID] = &ruleRealization{policyID: policyID, err: err}
+	c.queue.Add(policyID)
+}
+
+// DeleteRuleRealization removes the realization status of the rule.
+func (c *statusController) DeleteRuleRealization(ruleID string) {
+	c.realizedRulesLock.Lock()
+	defer c.realizedRulesLock.Unlock()
+	delete(c.realizedRules, ruleID)
+}
+
+// Resync triggers a report of the realization status of the NetworkPolicy.
+func (c *statusController) Resync(policyID types.UID) {
+	c.queue.Add(policyID)
+}
+
+// ClearReportedStatuses forgets the statuses reported for all NetworkPolicies,
+// so that they are reported again even if they have not changed. It's called
+// when the NetworkPolicies are resynced after reconnecting to antrea-controller,
+// which may have restarted and lost the reported statuses.
+func (c *statusController) ClearReportedStatuses() {
+	c.lastReportedLock.Lock()
+	defer c.lastReportedLock.Unlock()
+	c.lastReported = map[types.UID]v1beta1.NetworkPolicyNodeStatus{}
+}
+
+// Run spawns workers that report the realization status of NetworkPolicies.
+// Run will not return until stopCh is closed.
+func (c *statusController) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting NetworkPolicy status workers")
+	go wait.Until(c.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (c *statusController) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *statusController) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncHandler(key.(types.UID)); err != nil {
+		klog.Errorf("Error syncing status of NetworkPolicy %s, retrying. Error: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// getNodeStatus calculates the realization status of the NetworkPolicy on
+// this Node. The returned bool is false if any of its rules hasn't been
+// processed yet, in which case there is nothing to report.
+func (c *statusController) getNodeStatus(policy *v1beta1.NetworkPolicy, ruleIDs []string) (*v1beta1.NetworkPolicyNodeStatus, bool) {
+	c.realizedRulesLock.RLock()
+	defer c.realizedRulesLock.RUnlock()
+
+	status := &v1beta1.NetworkPolicyNodeStatus{
+		NodeName:   c.nodeName,
+		Generation: policy.Generation,
+	}
+	for _, ruleID := range ruleIDs {
+		realization, exists := c.realizedRules[ruleID]
+		if !exists {
+			return nil, false
+		}
+		if realization.err != nil && !status.RealizationFailure {
+			status.RealizationFailure = true
+			status.Message = realization.err.Error()
+		}
+	}
+	return status, true
+}
+
+func (c *statusController) syncHandler(policyID types.UID) error {
+	policy, ruleIDs, exists := c.ruleCache.getNetworkPolicyAndRuleIDs(string(policyID))
+	if !exists {
+		c.lastReportedLock.Lock()
+		delete(c.lastReported, policyID)
+		c.lastReportedLock.Unlock()
+		return nil
+	}
+	// Only the status of Antrea-native policies is reported.
+	if policy.SourceRef == nil || policy.SourceRef.Type == v1beta1.K8sNetworkPolicy {
+		return nil
+	}
+	nodeStatus, ok := c.getNodeStatus(policy, ruleIDs)
+	if !ok {
+		return nil
+	}
+
+	c.lastReportedLock.Lock()
+	defer c.lastReportedLock.Unlock()
+	if lastReported, exists := c.lastReported[policyID]; exists && reflect.DeepEqual(lastReported, *nodeStatus) {
+		return nil
+	}
+	antreaClient, err := c.antreaClientProvider.GetAntreaClient()
+	if err != nil {
+		return err
+	}
+	status := &v1beta1.NetworkPolicyStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: string(policyID),
+		},
+		NetworkPolicy: *policy.SourceRef,
+		Nodes:         []v1beta1.NetworkPolicyNodeStatus{*nodeStatus},
+	}
+	klog.V(2).Infof("Reporting status of NetworkPolicy %s: %#v", policy.SourceRef.ToString(), *nodeStatus)
+	if _, err := antreaClient.ControlplaneV1beta1().NetworkPolicyStatuses().Create(context.TODO(), status, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	c.lastReported[policyID] = *nodeStatus
+	return nil
+}
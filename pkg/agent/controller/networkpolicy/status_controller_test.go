@@ -0,0 +1,104 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+)
+
+func newTestStatusController() (*statusController, *ruleCache, *[]*v1beta1.NetworkPolicyStatus) {
+	clientset := &fake.Clientset{}
+	var reported []*v1beta1.NetworkPolicyStatus
+	clientset.AddReactor("create", "networkpolicystatuses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		status := action.(k8stesting.CreateAction).GetObject().(*v1beta1.NetworkPolicyStatus)
+		reported = append(reported, status)
+		return true, status, nil
+	})
+	ruleCache, _, _ := newFakeRuleCache()
+	return newStatusController(&antreaClientGetter{clientset}, "node1", ruleCache), ruleCache, &reported
+}
+
+func TestStatusControllerSyncHandler(t *testing.T) {
+	c, ruleCache, reported := newTestStatusController()
+	policy := getNetworkPolicyWithMultipleRules("policy1", []string{"addressGroup1"}, []string{}, []string{"appliedToGroup1"}, nil)
+	policy.SourceRef.Type = v1beta1.AntreaNetworkPolicy
+	policy.Generation = 1
+	ruleCache.AddNetworkPolicy(policy)
+	_, ruleIDs, exists := ruleCache.getNetworkPolicyAndRuleIDs("policy1")
+	require.True(t, exists)
+	require.Len(t, ruleIDs, 2)
+
+	// Nothing should be reported until all rules are processed.
+	c.SetRuleRealization(ruleIDs[0], policy.UID)
+	require.NoError(t, c.syncHandler(policy.UID))
+	assert.Empty(t, *reported)
+
+	c.SetRuleRealizationFailure(ruleIDs[1], policy.UID, fmt.Errorf("error installing flows"))
+	require.NoError(t, c.syncHandler(policy.UID))
+	require.Len(t, *reported, 1)
+	assert.Equal(t, *policy.SourceRef, (*reported)[0].NetworkPolicy)
+	assert.Equal(t, []v1beta1.NetworkPolicyNodeStatus{
+		{NodeName: "node1", Generation: 1, RealizationFailure: true, Message: "error installing flows"},
+	}, (*reported)[0].Nodes)
+
+	// The same status shouldn't be reported again.
+	require.NoError(t, c.syncHandler(policy.UID))
+	assert.Len(t, *reported, 1)
+
+	c.SetRuleRealization(ruleIDs[1], policy.UID)
+	require.NoError(t, c.syncHandler(policy.UID))
+	require.Len(t, *reported, 2)
+	assert.Equal(t, []v1beta1.NetworkPolicyNodeStatus{
+		{NodeName: "node1", Generation: 1},
+	}, (*reported)[1].Nodes)
+
+	// The same status should be reported again after the reported statuses
+	// are cleared.
+	c.ClearReportedStatuses()
+	require.NoError(t, c.syncHandler(policy.UID))
+	require.Len(t, *reported, 3)
+	assert.Equal(t, (*reported)[1].Nodes, (*reported)[2].Nodes)
+
+	// A new generation of the policy should be reported.
+	newPolicy := policy.DeepCopy()
+	newPolicy.Generation = 2
+	ruleCache.UpdateNetworkPolicy(newPolicy)
+	require.NoError(t, c.syncHandler(policy.UID))
+	require.Len(t, *reported, 4)
+	assert.Equal(t, []v1beta1.NetworkPolicyNodeStatus{
+		{NodeName: "node1", Generation: 2},
+	}, (*reported)[3].Nodes)
+}
+
+func TestStatusControllerIgnoreK8sNetworkPolicy(t *testing.T) {
+	c, ruleCache, reported := newTestStatusController()
+	policy := newNetworkPolicy("policy1", []string{"addressGroup1"}, []string{}, []string{"appliedToGroup1"}, nil)
+	ruleCache.AddNetworkPolicy(policy)
+	_, ruleIDs, _ := ruleCache.getNetworkPolicyAndRuleIDs("policy1")
+	for _, ruleID := range ruleIDs {
+		c.SetRuleRealization(ruleID, policy.UID)
+	}
+	require.NoError(t, c.syncHandler(policy.UID))
+	assert.Empty(t, *reported)
+}
@@ -51,6 +51,9 @@ const (
 	defaultWorkers = 4
 
 	ovsExternalIDNodeName = "node-name"
+	// OVSExternalIDClusterID is the external ID of the tunnel ports to the
+	// gateway Nodes of remote clusters, whose value is the cluster ID.
+	OVSExternalIDClusterID = "cluster-id"
 )
 
 // Controller is responsible for setting up necessary IP routes and Openflow entries for inter-node traffic.
@@ -155,7 +158,7 @@ func (c *Controller) removeStaleGatewayRoutes() error {
 		if node.Spec.PodCIDR == "" {
 			continue
 		}
-		desiredPodCIDRs = append(desiredPodCIDRs, node.Spec.PodCIDR)
+		desiredPodCIDRs = append(desiredPodCIDRs, getPodCIDRs(node)...)
 	}
 
 	// routeClient will remove orphaned routes whose destinations are not in desiredPodCIDRs.
@@ -223,6 +226,10 @@ func (c *Controller) removeStaleTunnelPorts() error {
 		if interfaceConfig.InterfaceName == c.nodeConfig.DefaultTunName {
 			continue
 		}
+		if interfaceConfig.ClusterID != "" {
+			// Tunnel ports to remote clusters are managed by the multicluster controller.
+			continue
+		}
 		if err := c.ovsBridgeClient.DeletePort(interfaceConfig.PortUUID); err != nil {
 			klog.Errorf("Failed to delete OVS tunnel port %s: %v", interfaceConfig.InterfaceName, err)
 		} else {
@@ -353,14 +360,16 @@ func (c *Controller) syncNodeRoute(nodeName string) error {
 func (c *Controller) deleteNodeRoute(nodeName string) error {
 	klog.Infof("Deleting routes and flows to Node %s", nodeName)
 
-	podCIDR, installed := c.installedNodes.Load(nodeName)
+	podCIDRs, installed := c.installedNodes.Load(nodeName)
 	if !installed {
 		// Route is not added for this Node.
 		return nil
 	}
 
-	if err := c.routeClient.DeleteRoutes(podCIDR.(*net.IPNet)); err != nil {
-		return fmt.Errorf("failed to delete the route to Node %s: %v", nodeName, err)
+	for _, podCIDR := range podCIDRs.([]*net.IPNet) {
+		if err := c.routeClient.DeleteRoutes(podCIDR); err != nil {
+			return fmt.Errorf("failed to delete the route to Node %s: %v", nodeName, err)
+		}
 	}
 
 	if err := c.ofClient.UninstallNodeFlows(nodeName); err != nil {
@@ -390,25 +399,31 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 		return nil
 	}
 
-	klog.Infof("Adding routes and flows to Node %s, podCIDR: %s, addresses: %v",
-		nodeName, node.Spec.PodCIDR, node.Status.Addresses)
+	klog.Infof("Adding routes and flows to Node %s, podCIDRs: %v, addresses: %v",
+		nodeName, getPodCIDRs(node), node.Status.Addresses)
 
 	if node.Spec.PodCIDR == "" {
 		klog.Errorf("PodCIDR is empty for Node %s", nodeName)
 		// Does not help to return an error and trigger controller retries.
 		return nil
 	}
-	peerPodCIDRAddr, peerPodCIDR, err := net.ParseCIDR(node.Spec.PodCIDR)
-	if err != nil {
-		klog.Errorf("Failed to parse PodCIDR %s for Node %s", node.Spec.PodCIDR, nodeName)
-		return nil
+	// peerConfigs maps each Pod CIDR of the peer Node to the IP of the peer gateway in that CIDR.
+	peerConfigs := make(map[*net.IPNet]net.IP)
+	var peerPodCIDRs []*net.IPNet
+	for _, podCIDR := range getPodCIDRs(node) {
+		peerPodCIDRAddr, peerPodCIDR, err := net.ParseCIDR(podCIDR)
+		if err != nil {
+			klog.Errorf("Failed to parse PodCIDR %s for Node %s", podCIDR, nodeName)
+			return nil
+		}
+		peerConfigs[peerPodCIDR] = ip.NextIP(peerPodCIDRAddr)
+		peerPodCIDRs = append(peerPodCIDRs, peerPodCIDR)
 	}
 	peerNodeIP, err := GetNodeAddr(node)
 	if err != nil {
 		klog.Errorf("Failed to retrieve IP address of Node %s: %v", nodeName, err)
 		return nil
 	}
-	peerGatewayIP := ip.NextIP(peerPodCIDRAddr)
 
 	ipsecTunOFPort := int32(0)
 	if c.networkConfig.EnableIPSecTunnel {
@@ -423,8 +438,7 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 	err = c.ofClient.InstallNodeFlows(
 		nodeName,
 		c.nodeConfig.GatewayConfig.MAC,
-		*peerPodCIDR,
-		peerGatewayIP,
+		peerConfigs,
 		peerNodeIP,
 		config.DefaultTunOFPort,
 		uint32(ipsecTunOFPort))
@@ -432,13 +446,32 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 		return fmt.Errorf("failed to install flows to Node %s: %v", nodeName, err)
 	}
 
-	if err := c.routeClient.AddRoutes(peerPodCIDR, peerNodeIP, peerGatewayIP); err != nil {
-		return err
+	for _, peerPodCIDR := range peerPodCIDRs {
+		routeNodeIP := peerNodeIP
+		// The traffic to the IPv6 Pod CIDR is routed to the IPv6 address of the peer Node when the Nodes have
+		// IPv6 addresses; otherwise it is sent over the tunnel to the IPv4 address of the peer Node.
+		if peerPodCIDR.IP.To4() == nil && c.nodeConfig.NodeIPv6Addr != nil {
+			if peerNodeIPv6 := GetNodeIPv6Addr(node); peerNodeIPv6 != nil {
+				routeNodeIP = peerNodeIPv6
+			}
+		}
+		if err := c.routeClient.AddRoutes(peerPodCIDR, routeNodeIP, peerConfigs[peerPodCIDR]); err != nil {
+			return err
+		}
 	}
-	c.installedNodes.Store(nodeName, peerPodCIDR)
+	c.installedNodes.Store(nodeName, peerPodCIDRs)
 	return err
 }
 
+// getPodCIDRs returns the Pod CIDRs of a Node. Node.Spec.PodCIDRs includes both the IPv4 and
+// IPv6 Pod CIDRs of a dual-stack Node, and Node.Spec.PodCIDR is used if it is not set.
+func getPodCIDRs(node *corev1.Node) []string {
+	if len(node.Spec.PodCIDRs) > 0 {
+		return node.Spec.PodCIDRs
+	}
+	return []string{node.Spec.PodCIDR}
+}
+
 // createIPSecTunnelPort creates an IPSec tunnel port for the remote Node if the
 // tunnel does not exist, and returns the ofport number.
 func (c *Controller) createIPSecTunnelPort(nodeName string, nodeIP net.IP) (int32, error) {
@@ -491,7 +524,7 @@ func (c *Controller) createIPSecTunnelPort(nodeName string, nodeIP net.IP) (int3
 
 // ParseTunnelInterfaceConfig initializes and returns an InterfaceConfig struct
 // for a tunnel interface. It reads tunnel type, remote IP, IPSec PSK from the
-// OVS interface options, and NodeName from the OVS port external_ids.
+// OVS interface options, and NodeName or ClusterID from the OVS port external_ids.
 // nil is returned, if the OVS port and interface configurations are not valid
 // for a tunnel interface.
 func ParseTunnelInterfaceConfig(
@@ -504,11 +537,18 @@ func ParseTunnelInterfaceConfig(
 	remoteIP, localIP, psk, csum := ovsconfig.ParseTunnelInterfaceOptions(portData)
 
 	var interfaceConfig *interfacestore.InterfaceConfig
-	var nodeName string
+	var nodeName, clusterID string
 	if portData.ExternalIDs != nil {
 		nodeName = portData.ExternalIDs[ovsExternalIDNodeName]
+		clusterID = portData.ExternalIDs[OVSExternalIDClusterID]
 	}
-	if psk != "" {
+	if clusterID != "" {
+		interfaceConfig = interfacestore.NewClusterTunnelInterface(
+			portData.Name,
+			ovsconfig.TunnelType(portData.IFType),
+			clusterID,
+			remoteIP)
+	} else if psk != "" {
 		interfaceConfig = interfacestore.NewIPSecTunnelInterface(
 			portData.Name,
 			ovsconfig.TunnelType(portData.IFType),
@@ -523,23 +563,40 @@ func ParseTunnelInterfaceConfig(
 }
 
 // GetNodeAddr gets the available IP address of a Node. GetNodeAddr will first try to get the
-// NodeInternalIP, then try to get the NodeExternalIP.
+// NodeInternalIP, then try to get the NodeExternalIP. If the Node has both IPv4 and IPv6
+// addresses, the IPv4 address is preferred.
 func GetNodeAddr(node *corev1.Node) (net.IP, error) {
-	addresses := make(map[corev1.NodeAddressType]string)
-	for _, addr := range node.Status.Addresses {
-		addresses[addr.Type] = addr.Address
+	if ipAddr := getNodeAddrByFamily(node, false); ipAddr != nil {
+		return ipAddr, nil
 	}
-	var ipAddrStr string
-	if internalIP, ok := addresses[corev1.NodeInternalIP]; ok {
-		ipAddrStr = internalIP
-	} else if externalIP, ok := addresses[corev1.NodeExternalIP]; ok {
-		ipAddrStr = externalIP
-	} else {
-		return nil, fmt.Errorf("node %s has neither external ip nor internal ip", node.Name)
+	if ipAddr := getNodeAddrByFamily(node, true); ipAddr != nil {
+		return ipAddr, nil
 	}
-	ipAddr := net.ParseIP(ipAddrStr)
-	if ipAddr == nil {
-		return nil, fmt.Errorf("<%v> is not a valid ip address", ipAddrStr)
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP || addr.Type == corev1.NodeExternalIP {
+			return nil, fmt.Errorf("<%v> is not a valid ip address", addr.Address)
+		}
+	}
+	return nil, fmt.Errorf("node %s has neither external ip nor internal ip", node.Name)
+}
+
+// GetNodeIPv6Addr gets the IPv6 address of a Node in the same way as GetNodeAddr. It returns
+// nil if the Node has no IPv6 address.
+func GetNodeIPv6Addr(node *corev1.Node) net.IP {
+	return getNodeAddrByFamily(node, true)
+}
+
+func getNodeAddrByFamily(node *corev1.Node, isIPv6 bool) net.IP {
+	for _, addrType := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP} {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type != addrType {
+				continue
+			}
+			ipAddr := net.ParseIP(addr.Address)
+			if ipAddr != nil && (ipAddr.To4() == nil) == isIPv6 {
+				return ipAddr
+			}
+		}
 	}
-	return ipAddr, nil
+	return nil
 }
@@ -42,6 +42,7 @@ import (
 	opslisters "github.com/vmware-tanzu/antrea/pkg/client/listers/ops/v1alpha1"
 	"github.com/vmware-tanzu/antrea/pkg/features"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
 )
 
 type icmpType uint8
@@ -126,7 +127,7 @@ func NewTraceflowController(
 		resyncPeriod,
 	)
 	// Register packetInHandler
-	c.ofClient.RegisterPacketInHandler("traceflow", c)
+	c.ofClient.RegisterPacketInHandler(uint8(openflow.PacketInReasonTF), "traceflow", c)
 	// Add serviceLister if AntreaProxy enabled
 	if features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
 		c.serviceLister = informerFactory.Core().V1().Services().Lister()
@@ -301,6 +302,10 @@ func (c *Controller) validateTraceflow(tf *opsv1alpha1.Traceflow) error {
 		if destIP == nil {
 			return fmt.Errorf("destination IP is not valid: %s", tf.Spec.Destination.IP)
 		}
+		// The Traceflow packet can only be built as an IPv4 packet.
+		if destIP.To4() == nil {
+			return fmt.Errorf("destination IP %s is not supported, only IPv4 is supported by Traceflow", tf.Spec.Destination.IP)
+		}
 		// When AntreaProxy is enabled, serviceCIDR is not required and may be set to a
 		// default value which does not match the cluster configuration.
 		if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) && c.serviceCIDR.Contains(destIP) {
@@ -312,6 +317,12 @@ func (c *Controller) validateTraceflow(tf *opsv1alpha1.Traceflow) error {
 
 func (c *Controller) injectPacket(tf *opsv1alpha1.Traceflow) error {
 	podInterfaces := c.interfaceStore.GetContainerInterfacesByPod(tf.Spec.Source.Pod, tf.Spec.Source.Namespace)
+	// The Traceflow packet can only be built as an IPv4 packet, the IPv6
+	// addresses of dual-stack Pods are not traced.
+	srcIP := ip.GetIPv4Addr(podInterfaces[0].IPs)
+	if srcIP == nil {
+		return fmt.Errorf("source Pod %s/%s has no IPv4 address, only IPv4 is supported by Traceflow", tf.Spec.Source.Namespace, tf.Spec.Source.Pod)
+	}
 	// Update Traceflow phase to Running.
 	klog.V(2).Infof("Injecting packet for Traceflow %s", tf.Name)
 	c.injectedTagsMutex.Lock()
@@ -331,22 +342,39 @@ func (c *Controller) injectPacket(tf *opsv1alpha1.Traceflow) error {
 		dstPodInterfaces := c.interfaceStore.GetContainerInterfacesByPod(tf.Spec.Destination.Pod, tf.Spec.Destination.Namespace)
 		if len(dstPodInterfaces) > 0 {
 			dstMAC = dstPodInterfaces[0].MAC.String()
-			dstIP = dstPodInterfaces[0].IP.String()
+			if dstPodIP := ip.GetIPv4Addr(dstPodInterfaces[0].IPs); dstPodIP != nil {
+				dstIP = dstPodIP.String()
+			}
 		} else {
 			dstPod, err := c.kubeClient.CoreV1().Pods(tf.Spec.Destination.Namespace).Get(context.TODO(), tf.Spec.Destination.Pod, metav1.GetOptions{})
 			if err != nil {
 				return err
 			}
 			// dstMAC is "" here, will be set to Gateway MAC in ofClient.SendTraceflowPacket
-			dstIP = dstPod.Status.PodIP
+			var dstPodIPs []net.IP
+			for _, podIP := range dstPod.Status.PodIPs {
+				dstPodIPs = append(dstPodIPs, net.ParseIP(podIP.IP))
+			}
+			if len(dstPodIPs) == 0 {
+				dstPodIPs = append(dstPodIPs, net.ParseIP(dstPod.Status.PodIP))
+			}
+			if dstPodIP := ip.GetIPv4Addr(dstPodIPs); dstPodIP != nil {
+				dstIP = dstPodIP.String()
+			}
 			dstNodeIP = dstPod.Status.HostIP
 		}
+		if dstIP == "" {
+			return fmt.Errorf("destination Pod %s/%s has no IPv4 address, only IPv4 is supported by Traceflow", tf.Spec.Destination.Namespace, tf.Spec.Destination.Pod)
+		}
 	} else if tf.Spec.Destination.Service != "" {
 		dstSvc, err := c.serviceLister.Services(tf.Spec.Destination.Namespace).Get(tf.Spec.Destination.Service)
 		if err != nil {
 			return err
 		}
 		dstIP = dstSvc.Spec.ClusterIP
+		if svcIP := net.ParseIP(dstIP); svcIP == nil || svcIP.To4() == nil {
+			return fmt.Errorf("ClusterIP %s of destination Service %s/%s is not supported, only IPv4 is supported by Traceflow", dstIP, tf.Spec.Destination.Namespace, tf.Spec.Destination.Service)
+		}
 	}
 	// Check encap status if no dstMAC found which means the destination is Service or the destination Pod/IP is not on local Node.
 	if dstMAC == "" {
@@ -390,7 +418,7 @@ func (c *Controller) injectPacket(tf *opsv1alpha1.Traceflow) error {
 		tf.Status.DataplaneTag,
 		podInterfaces[0].MAC.String(),
 		dstMAC,
-		podInterfaces[0].IP.String(),
+		srcIP.String(),
 		dstIP,
 		uint8(tf.Spec.Packet.IPHeader.Protocol),
 		uint8(tf.Spec.Packet.IPHeader.TTL),
@@ -15,6 +15,7 @@
 package connections
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
 	"sync"
@@ -28,6 +29,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/agent/proxy"
+	"github.com/vmware-tanzu/antrea/pkg/querier"
 )
 
 var serviceProtocolMap = map[uint8]corev1.Protocol{
@@ -36,30 +38,65 @@ var serviceProtocolMap = map[uint8]corev1.Protocol{
 	132: corev1.ProtocolSCTP,
 }
 
+const (
+	// connEndedBufferSize is the number of destroyed connections buffered before they are exported. The
+	// connections exceeding it are exported in the next export cycle.
+	connEndedBufferSize = 1000
+	// connTrackEventsRetryInterval is the interval of subscribing to conntrack events again after the subscription
+	// is broken.
+	connTrackEventsRetryInterval = 5 * time.Second
+)
+
 type ConnectionStore struct {
-	connections   map[flowexporter.ConnectionKey]flowexporter.Connection
-	connDumper    ConnTrackDumper
-	ifaceStore    interfacestore.InterfaceStore
-	serviceCIDR   *net.IPNet
-	antreaProxier proxy.Proxier
-	pollInterval  time.Duration
-	mutex         sync.Mutex
+	connections          map[flowexporter.ConnectionKey]flowexporter.Connection
+	connDumper           ConnTrackDumper
+	ifaceStore           interfacestore.InterfaceStore
+	serviceCIDR          *net.IPNet
+	antreaProxier        proxy.Proxier
+	networkPolicyQuerier querier.AgentNetworkPolicyInfoQuerier
+	pollInterval         time.Duration
+	mutex                sync.Mutex
+	connListener         ConnTrackListener
+	connEnded            chan flowexporter.ConnectionKey
+	// resync is set when the connections may be missed by the conntrack events, so that the next poll adds the
+	// connections which are not in the connection map.
+	resync bool
 }
 
-func NewConnectionStore(connTrackDumper ConnTrackDumper, ifaceStore interfacestore.InterfaceStore, serviceCIDR *net.IPNet, proxier proxy.Proxier, pollInterval time.Duration) *ConnectionStore {
+func NewConnectionStore(connTrackDumper ConnTrackDumper, ifaceStore interfacestore.InterfaceStore, serviceCIDR *net.IPNet, proxier proxy.Proxier, npQuerier querier.AgentNetworkPolicyInfoQuerier, pollInterval time.Duration) *ConnectionStore {
 	return &ConnectionStore{
-		connections:   make(map[flowexporter.ConnectionKey]flowexporter.Connection),
-		connDumper:    connTrackDumper,
-		ifaceStore:    ifaceStore,
-		serviceCIDR:   serviceCIDR,
-		antreaProxier: proxier,
-		pollInterval:  pollInterval,
+		connections:          make(map[flowexporter.ConnectionKey]flowexporter.Connection),
+		connDumper:           connTrackDumper,
+		ifaceStore:           ifaceStore,
+		serviceCIDR:          serviceCIDR,
+		antreaProxier:        proxier,
+		networkPolicyQuerier: npQuerier,
+		pollInterval:         pollInterval,
+	}
+}
+
+// EnableConnTrackEvents makes the ConnectionStore maintain the connection map incrementally with the NEW, UPDATE and
+// DESTROY conntrack events, while polling only refreshes the counters of the connections. It must be called before
+// Run. It returns the channel of the keys of the connections destroyed in conntrack, whose final stats can be exported
+// immediately.
+func (cs *ConnectionStore) EnableConnTrackEvents() (<-chan flowexporter.ConnectionKey, error) {
+	listener, ok := cs.connDumper.(ConnTrackListener)
+	if !ok {
+		return nil, fmt.Errorf("conntrack events are only supported with the OVS kernel datapath on Linux")
 	}
+	cs.connListener = listener
+	cs.connEnded = make(chan flowexporter.ConnectionKey, connEndedBufferSize)
+	// The connections which exist before subscribing to conntrack events are added by the first poll.
+	cs.resync = true
+	return cs.connEnded, nil
 }
 
 // Run enables the periodical polling of conntrack connections, at the given flowPollInterval
 func (cs *ConnectionStore) Run(stopCh <-chan struct{}, pollDone chan struct{}) {
 	klog.Infof("Starting conntrack polling")
+	if cs.connListener != nil {
+		go cs.listenConnTrackEvents(stopCh)
+	}
 
 	pollTicker := time.NewTicker(cs.pollInterval)
 	defer pollTicker.Stop()
@@ -83,6 +120,70 @@ func (cs *ConnectionStore) Run(stopCh <-chan struct{}, pollDone chan struct{}) {
 	}
 }
 
+// listenConnTrackEvents subscribes to conntrack events until stopCh is closed. When the subscription is broken, the
+// events may be lost, so the next poll resyncs the connection map before subscribing again.
+func (cs *ConnectionStore) listenConnTrackEvents(stopCh <-chan struct{}) {
+	for {
+		err := cs.connListener.ListenEvents(openflow.CtZone, cs.handleConnTrackEvent, stopCh)
+		if err == nil {
+			return
+		}
+		klog.Errorf("Error when listening to conntrack events: %v", err)
+		cs.mutex.Lock()
+		cs.resync = true
+		cs.mutex.Unlock()
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(connTrackEventsRetryInterval):
+		}
+	}
+}
+
+// handleConnTrackEvent adds or updates the connection of the NEW and UPDATE events. The connection of the DESTROY
+// event is updated with its final stats and marked inactive, and its key is sent to the exporter to export it
+// immediately.
+func (cs *ConnectionStore) handleConnTrackEvent(eventType ConnTrackEventType, conn *flowexporter.Connection) {
+	cs.addOrUpdateConn(conn)
+	if eventType != ConnTrackEventDestroy {
+		return
+	}
+	connKey := flowexporter.NewConnectionKey(conn)
+	cs.mutex.Lock()
+	existingConn, exists := cs.connections[connKey]
+	if exists {
+		existingConn.IsActive = false
+		cs.connections[connKey] = existingConn
+	}
+	cs.mutex.Unlock()
+	if !exists {
+		return
+	}
+	select {
+	case cs.connEnded <- connKey:
+	default:
+		klog.V(2).Infof("Too many destroyed connections, the connection with key %v will be exported in the next export cycle", connKey)
+	}
+}
+
+// refreshConn updates the counters of the connection if it is in the connection map. It never adds the connection or
+// marks it active, which is done by the conntrack events.
+func (cs *ConnectionStore) refreshConn(conn *flowexporter.Connection) {
+	connKey := flowexporter.NewConnectionKey(conn)
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	existingConn, exists := cs.connections[connKey]
+	if !exists {
+		return
+	}
+	existingConn.StopTime = conn.StopTime
+	existingConn.OriginalBytes = conn.OriginalBytes
+	existingConn.OriginalPackets = conn.OriginalPackets
+	existingConn.ReverseBytes = conn.ReverseBytes
+	existingConn.ReversePackets = conn.ReversePackets
+	cs.connections[connKey] = existingConn
+}
+
 // addOrUpdateConn updates the connection if it is already present, i.e., update timestamp, counters etc.,
 // or adds a new Connection by 5-tuple of the flow along with local Pod and PodNameSpace.
 func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
@@ -121,12 +222,10 @@ func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 			conn.DestinationPodName = dIface.ContainerInterfaceConfig.PodName
 			conn.DestinationPodNamespace = dIface.ContainerInterfaceConfig.PodNamespace
 		}
-		// Do not export flow records of connections whose destination is local Pod and source is remote Pod.
-		// We export flow records only from "source node", where the connection is originated from. This is to avoid
-		// 2 copies of flow records at flow collector. This restriction will be removed when flow records store network policy rule ID.
-		// TODO: Remove this when network policy rule IDs are added to flow records.
-		if !srcFound && dstFound {
-			conn.DoExport = false
+		// The flow records of inter-Node connections are exported by both the source Node and the destination Node,
+		// as they may apply different NetworkPolicies to the connection.
+		if cs.networkPolicyQuerier != nil {
+			cs.addNetworkPolicyInfo(conn)
 		}
 
 		// Process Pod-to-Service flows when Antrea Proxy is enabled.
@@ -155,6 +254,34 @@ func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 	}
 }
 
+// addNetworkPolicyInfo resolves the NetworkPolicies of the ingress and egress rules that allowed the connection. The
+// conjunction IDs of the rules are committed to the 0..31 bits and the 32..63 bits of the ct_label respectively.
+func (cs *ConnectionStore) addNetworkPolicyInfo(conn *flowexporter.Connection) {
+	if len(conn.Labels) < 8 {
+		return
+	}
+	ingressRuleID := binary.LittleEndian.Uint32(conn.Labels[:4])
+	egressRuleID := binary.LittleEndian.Uint32(conn.Labels[4:8])
+	if ingressRuleID != 0 {
+		policy := cs.networkPolicyQuerier.GetNetworkPolicyByRuleFlowID(ingressRuleID)
+		if policy == nil {
+			klog.Warningf("Could not retrieve the NetworkPolicy of the ingress rule %d", ingressRuleID)
+		} else {
+			conn.IngressNetworkPolicyName = policy.Name
+			conn.IngressNetworkPolicyNamespace = policy.Namespace
+		}
+	}
+	if egressRuleID != 0 {
+		policy := cs.networkPolicyQuerier.GetNetworkPolicyByRuleFlowID(egressRuleID)
+		if policy == nil {
+			klog.Warningf("Could not retrieve the NetworkPolicy of the egress rule %d", egressRuleID)
+		} else {
+			conn.EgressNetworkPolicyName = policy.Name
+			conn.EgressNetworkPolicyNamespace = policy.Namespace
+		}
+	}
+}
+
 // GetConnByKey gets the connection in connection map given the connection key
 func (cs *ConnectionStore) GetConnByKey(flowTuple flowexporter.ConnectionKey) (*flowexporter.Connection, bool) {
 	cs.mutex.Lock()
@@ -182,23 +309,60 @@ func (cs *ConnectionStore) ForAllConnectionsDo(callback flowexporter.ConnectionM
 func (cs *ConnectionStore) Poll() (int, error) {
 	klog.V(2).Infof("Polling conntrack")
 
-	// Reset isActive flag for all connections in connection map before dumping flows in conntrack module.
-	// This is to specify that the connection and the flow record can be deleted after the next export.
-	resetConn := func(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
-		conn.IsActive = false
-		cs.connections[key] = conn
-		return nil
+	cs.mutex.Lock()
+	resync := cs.connListener == nil || cs.resync
+	cs.resync = false
+	cs.mutex.Unlock()
+
+	var knownConns map[flowexporter.ConnectionKey]bool
+	if resync {
+		// Reset isActive flag for all connections in connection map before dumping flows in conntrack module.
+		// This is to specify that the connection and the flow record can be deleted after the next export.
+		resetConn := func(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
+			conn.IsActive = false
+			cs.connections[key] = conn
+			return nil
+		}
+		// We do not expect any error as resetConn is not returning any error
+		cs.ForAllConnectionsDo(resetConn)
+	} else {
+		// With conntrack events, only the connections known before dumping flows are marked inactive if they are
+		// not dumped, in case their DESTROY events are lost. The connections added by the events meanwhile are not
+		// in the dumped flows.
+		knownConns = make(map[flowexporter.ConnectionKey]bool)
+		cs.ForAllConnectionsDo(func(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
+			knownConns[key] = true
+			return nil
+		})
 	}
-	// We do not expect any error as resetConn is not returning any error
-	cs.ForAllConnectionsDo(resetConn)
 
 	filteredConnsList, totalConns, err := cs.connDumper.DumpFlows(openflow.CtZone)
 	if err != nil {
+		if resync && cs.connListener != nil {
+			cs.mutex.Lock()
+			cs.resync = true
+			cs.mutex.Unlock()
+		}
 		return 0, err
 	}
 	// Update only the Connection store. IPFIX records are generated based on Connection store.
 	for _, conn := range filteredConnsList {
-		cs.addOrUpdateConn(conn)
+		if resync {
+			cs.addOrUpdateConn(conn)
+		} else {
+			cs.refreshConn(conn)
+			delete(knownConns, flowexporter.NewConnectionKey(conn))
+		}
+	}
+	if len(knownConns) > 0 {
+		cs.mutex.Lock()
+		for key := range knownConns {
+			if conn, exists := cs.connections[key]; exists {
+				conn.IsActive = false
+				cs.connections[key] = conn
+			}
+		}
+		cs.mutex.Unlock()
 	}
 	connsLen := len(filteredConnsList)
 	filteredConnsList = nil
@@ -36,6 +36,8 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	proxytest "github.com/vmware-tanzu/antrea/pkg/agent/proxy/testing"
+	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	queriertest "github.com/vmware-tanzu/antrea/pkg/querier/testing"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
@@ -78,7 +80,7 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 		TupleReply:      revTuple1,
 		IsActive:        true,
 	}
-	// Flow-2, which is not in ConnectionStore
+	// Flow-2, which is not in ConnectionStore, allowed by ingress rule 1 and egress rule 2.
 	tuple2, revTuple2 := makeTuple(&net.IP{5, 6, 7, 8}, &net.IP{8, 7, 6, 5}, 6, 60001, 200)
 	testFlow2 := flowexporter.Connection{
 		StartTime:       refTime.Add(-(time.Second * 20)),
@@ -90,6 +92,7 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 		TupleOrig:       tuple2,
 		TupleReply:      revTuple2,
 		IsActive:        true,
+		Labels:          []byte{1, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
 	}
 	tuple3, revTuple3 := makeTuple(&net.IP{10, 10, 10, 10}, &net.IP{20, 20, 20, 20}, 6, 5000, 80)
 	testFlow3 := flowexporter.Connection{
@@ -121,7 +124,7 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 	}
 	interfaceFlow2 := &interfacestore.InterfaceConfig{
 		InterfaceName:            "interface2",
-		IP:                       net.IP{8, 7, 6, 5},
+		IPs:                      []net.IP{net.IP{8, 7, 6, 5}},
 		ContainerInterfaceConfig: podConfigFlow2,
 	}
 	serviceCIDR := &net.IPNet{
@@ -140,7 +143,8 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
 	mockProxier := proxytest.NewMockProxier(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, serviceCIDR, mockProxier, testPollInterval)
+	mockNPQuerier := queriertest.NewMockAgentNetworkPolicyInfoQuerier(ctrl)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, serviceCIDR, mockProxier, mockNPQuerier, testPollInterval)
 
 	// Add flow1conn to the Connection map
 	testFlow1Tuple := flowexporter.NewConnectionKey(&testFlow1)
@@ -166,6 +170,11 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 			expConn.DestinationPodName = "pod2"
 			mockIfaceStore.EXPECT().GetInterfaceByIP(test.flow.TupleOrig.SourceAddress.String()).Return(nil, false)
 			mockIfaceStore.EXPECT().GetInterfaceByIP(test.flow.TupleReply.SourceAddress.String()).Return(interfaceFlow2, true)
+			mockNPQuerier.EXPECT().GetNetworkPolicyByRuleFlowID(uint32(1)).Return(&cpv1beta1.NetworkPolicyReference{Name: "np1", Namespace: "ns2"})
+			// The NetworkPolicy of the egress rule has been deleted.
+			mockNPQuerier.EXPECT().GetNetworkPolicyByRuleFlowID(uint32(2)).Return(nil)
+			expConn.IngressNetworkPolicyName = "np1"
+			expConn.IngressNetworkPolicyNamespace = "ns2"
 		} else {
 			mockIfaceStore.EXPECT().GetInterfaceByIP(expConn.TupleOrig.SourceAddress.String()).Return(nil, false)
 			mockIfaceStore.EXPECT().GetInterfaceByIP(expConn.TupleReply.SourceAddress.String()).Return(nil, false)
@@ -223,7 +232,7 @@ func TestConnectionStore_ForAllConnectionsDo(t *testing.T) {
 	// Create ConnectionStore
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, testPollInterval)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, nil, testPollInterval)
 	// Add flows to the Connection store
 	for i, flow := range testFlows {
 		connStore.connections[*testFlowKeys[i]] = *flow
@@ -288,7 +297,7 @@ func TestConnectionStore_DeleteConnectionByKey(t *testing.T) {
 	// Create ConnectionStore
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, testPollInterval)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, nil, testPollInterval)
 	// Add flows to the connection store.
 	for i, flow := range testFlows {
 		connStore.connections[*testFlowKeys[i]] = *flow
@@ -312,7 +321,7 @@ func TestConnectionStore_MetricSettingInPoll(t *testing.T) {
 	// Create ConnectionStore
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, testPollInterval)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, nil, testPollInterval)
 	// Hard-coded conntrack occupancy metrics for test
 	TotalConnections := 0
 	MaxConnections := 300000
@@ -354,3 +363,97 @@ func checkMaxConnectionsMetric(t *testing.T, maxConns int) {
 	err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expectedMaxConnectionsCount), "antrea_agent_conntrack_max_connection_count")
 	assert.NoError(t, err)
 }
+
+// connTrackDumperListener is a ConnTrackDumper which also supports conntrack events. The events are handled by
+// calling ConnectionStore.handleConnTrackEvent directly in the tests.
+type connTrackDumperListener struct {
+	*connectionstest.MockConnTrackDumper
+}
+
+func (ct *connTrackDumperListener) ListenEvents(zoneFilter uint16, handler ConnTrackEventHandler, stopCh <-chan struct{}) error {
+	<-stopCh
+	return nil
+}
+
+func TestConnectionStore_ConnTrackEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+
+	refTime := time.Now()
+	newConn := func(srcIP net.IP, dstIP net.IP, packets uint64) *flowexporter.Connection {
+		tuple, revTuple := makeTuple(&srcIP, &dstIP, 6, 65280, 255)
+		return &flowexporter.Connection{
+			StartTime:       refTime.Add(-(time.Second * 50)),
+			StopTime:        refTime,
+			OriginalPackets: packets,
+			TupleOrig:       tuple,
+			TupleReply:      revTuple,
+			IsActive:        true,
+			DoExport:        true,
+		}
+	}
+	// Conn-1 exists before subscribing to conntrack events, Conn-2 is added by the NEW event, and Conn-3 is only dumped.
+	conn1 := newConn(net.IP{1, 2, 3, 4}, net.IP{4, 3, 2, 1}, 10)
+	conn2 := newConn(net.IP{5, 6, 7, 8}, net.IP{8, 7, 6, 5}, 20)
+	conn3 := newConn(net.IP{9, 10, 11, 12}, net.IP{12, 11, 10, 9}, 30)
+	connKey1 := flowexporter.NewConnectionKey(conn1)
+	connKey2 := flowexporter.NewConnectionKey(conn2)
+	connKey3 := flowexporter.NewConnectionKey(conn3)
+
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(gomock.Any()).Return(nil, false).AnyTimes()
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	mockConnDumper.EXPECT().GetMaxConnections().Return(300000, nil).AnyTimes()
+	connStore := NewConnectionStore(&connTrackDumperListener{mockConnDumper}, mockIfaceStore, nil, nil, nil, testPollInterval)
+	connEnded, err := connStore.EnableConnTrackEvents()
+	require.NoError(t, err)
+
+	// The first poll adds the existing connections.
+	mockConnDumper.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return([]*flowexporter.Connection{conn1}, 1, nil)
+	_, err = connStore.Poll()
+	require.NoError(t, err)
+	_, exists := connStore.GetConnByKey(connKey1)
+	assert.True(t, exists, "connection should be added by the first poll")
+
+	connStore.handleConnTrackEvent(ConnTrackEventNew, conn2)
+	_, exists = connStore.GetConnByKey(connKey2)
+	assert.True(t, exists, "connection should be added by the NEW event")
+
+	// The following polls only refresh the counters, and mark the known connections which are not dumped inactive.
+	updatedConn1 := *conn1
+	updatedConn1.OriginalPackets = 15
+	mockConnDumper.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return([]*flowexporter.Connection{&updatedConn1, conn3}, 2, nil)
+	_, err = connStore.Poll()
+	require.NoError(t, err)
+	conn, _ := connStore.GetConnByKey(connKey1)
+	assert.Equal(t, uint64(15), conn.OriginalPackets)
+	assert.True(t, conn.IsActive)
+	conn, _ = connStore.GetConnByKey(connKey2)
+	assert.False(t, conn.IsActive, "connection which is not dumped should be marked inactive")
+	_, exists = connStore.GetConnByKey(connKey3)
+	assert.False(t, exists, "connection should not be added by the poll")
+
+	// The DESTROY event updates the final stats of the connection and sends its key to be exported.
+	destroyedConn1 := *conn1
+	destroyedConn1.OriginalPackets = 18
+	connStore.handleConnTrackEvent(ConnTrackEventDestroy, &destroyedConn1)
+	conn, _ = connStore.GetConnByKey(connKey1)
+	assert.Equal(t, uint64(18), conn.OriginalPackets)
+	assert.False(t, conn.IsActive)
+	select {
+	case connKey := <-connEnded:
+		assert.Equal(t, connKey1, connKey)
+	default:
+		t.Fatal("Key of the destroyed connection should be sent")
+	}
+}
+
+func TestConnectionStore_EnableConnTrackEventsNotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	connStore := NewConnectionStore(mockConnDumper, interfacestoretest.NewMockInterfaceStore(ctrl), nil, nil, nil, testPollInterval)
+	_, err := connStore.EnableConnTrackEvents()
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"net"
 
 	"github.com/ti-mo/conntrack"
+	"github.com/ti-mo/netfilter"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
@@ -28,8 +29,13 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/sysctl"
 )
 
-// connTrackSystem implements ConnTrackDumper. This is for linux kernel datapath.
+// connTrackSystem implements ConnTrackDumper and ConnTrackListener. This is for linux kernel datapath.
 var _ ConnTrackDumper = new(connTrackSystem)
+var _ ConnTrackListener = new(connTrackSystem)
+
+// connTrackEventReadBuffer is the size of the receive buffer of the netlink socket subscribed to conntrack events. The
+// kernel drops the events when the buffer overflows, which breaks the subscription.
+const connTrackEventReadBuffer = 8 * 1024 * 1024
 
 type connTrackSystem struct {
 	nodeConfig  *config.NodeConfig
@@ -71,6 +77,62 @@ func (ct *connTrackSystem) DumpFlows(zoneFilter uint16) ([]*flowexporter.Connect
 	return filteredConns, len(conns), nil
 }
 
+// ListenEvents opens a netlink connection subscribed to the NEW, UPDATE and DESTROY conntrack events, and calls the
+// handler for the events of the connections in Antrea ZoneID until stopCh is closed.
+func (ct *connTrackSystem) ListenEvents(zoneFilter uint16, handler ConnTrackEventHandler, stopCh <-chan struct{}) error {
+	conn, err := conntrack.Dial(nil)
+	if err != nil {
+		return fmt.Errorf("error when getting netlink socket: %v", err)
+	}
+	if err := conn.SetReadBuffer(connTrackEventReadBuffer); err != nil {
+		klog.Warningf("Error when setting the receive buffer of the conntrack event socket: %v", err)
+	}
+	evChan := make(chan conntrack.Event, 1024)
+	// A single worker is used so that the events of a connection are handled in order.
+	errChan, err := conn.Listen(evChan, 1, netfilter.GroupsCT)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error when subscribing to conntrack events: %v", err)
+	}
+	klog.Infof("Subscribed to conntrack events")
+	for {
+		select {
+		case <-stopCh:
+			conn.Close()
+			// Wait for the worker to exit after the socket is closed.
+			for {
+				select {
+				case <-evChan:
+				case <-errChan:
+					return nil
+				}
+			}
+		case err := <-errChan:
+			conn.Close()
+			return fmt.Errorf("error when receiving conntrack events: %v", err)
+		case ev := <-evChan:
+			if ev.Flow == nil {
+				continue
+			}
+			var eventType ConnTrackEventType
+			switch ev.Type {
+			case conntrack.EventNew:
+				eventType = ConnTrackEventNew
+			case conntrack.EventUpdate:
+				eventType = ConnTrackEventUpdate
+			case conntrack.EventDestroy:
+				eventType = ConnTrackEventDestroy
+			default:
+				continue
+			}
+			filteredConns := filterAntreaConns([]*flowexporter.Connection{netlinkFlowToAntreaConnection(ev.Flow)}, ct.nodeConfig, ct.serviceCIDR, zoneFilter)
+			if len(filteredConns) == 1 {
+				handler(eventType, filteredConns[0])
+			}
+		}
+	}
+}
+
 // NetFilterConnTrack interface helps for testing the code that contains the third party library functions ("github.com/ti-mo/conntrack")
 type NetFilterConnTrack interface {
 	Dial() error
@@ -139,6 +201,7 @@ func netlinkFlowToAntreaConnection(conn *conntrack.Flow) *flowexporter.Connectio
 		OriginalBytes:           conn.CountersOrig.Bytes,
 		ReversePackets:          conn.CountersReply.Packets,
 		ReverseBytes:            conn.CountersReply.Bytes,
+		Labels:                  conn.Labels,
 		SourcePodNamespace:      "",
 		SourcePodName:           "",
 		DestinationPodNamespace: "",
@@ -16,6 +16,7 @@ package connections
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
@@ -97,7 +98,7 @@ func (ct *connTrackOvsCtl) ovsAppctlDumpConnections(zoneFilter uint16) ([]*flowe
 
 // flowStringToAntreaConnection parses the flow string and converts to Antrea connection.
 // Example of flow string:
-// tcp,orig=(src=10.10.1.2,dst=10.96.0.1,sport=42540,dport=443),reply=(src=10.96.0.1,dst=10.10.1.2,sport=443,dport=42540),zone=65520,protoinfo=(state=TIME_WAIT)
+// tcp,orig=(src=10.10.1.2,dst=10.96.0.1,sport=42540,dport=443),reply=(src=10.96.0.1,dst=10.10.1.2,sport=443,dport=42540),zone=65520,labels=0x200000001,protoinfo=(state=TIME_WAIT)
 func flowStringToAntreaConnection(flow string, zoneFilter uint16) (*flowexporter.Connection, error) {
 	conn := flowexporter.Connection{}
 	flowSlice := strings.Split(flow, ",")
@@ -116,6 +117,12 @@ func flowStringToAntreaConnection(flow string, zoneFilter uint16) (*flowexporter
 				return nil, err
 			}
 			conn.TupleReply.Protocol = conn.TupleOrig.Protocol
+		} else if strings.HasPrefix(fs, "labels=") {
+			fields := strings.Split(fs, "=")
+			conn.Labels, err = parseCTLabels(fields[len(fields)-1])
+			if err != nil {
+				return nil, err
+			}
 		} else if strings.Contains(fs, "src") {
 			fields := strings.Split(fs, "=")
 			if !isReply {
@@ -192,6 +199,22 @@ func flowStringToAntreaConnection(flow string, zoneFilter uint16) (*flowexporter
 	return &conn, nil
 }
 
+// parseCTLabels converts the ct_label in hexadecimal format to bytes in the
+// same order as the labels dumped through netlink, i.e. the 0..31 bits of the
+// ct_label are the first 4 bytes in little endian.
+func parseCTLabels(hexLabels string) ([]byte, error) {
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(hexLabels, "0x"), 16)
+	if !ok || value.BitLen() > 128 {
+		return nil, fmt.Errorf("conversion of labels %s to 128-bit value failed", hexLabels)
+	}
+	bigEndian := value.Bytes()
+	labels := make([]byte, 16)
+	for i, b := range bigEndian {
+		labels[len(bigEndian)-1-i] = b
+	}
+	return labels, nil
+}
+
 // lookupProtocolMap returns protocol identifier given protocol name
 func lookupProtocolMap(name string) (uint8, error) {
 	name = strings.TrimSpace(name)
@@ -0,0 +1,247 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/querier"
+)
+
+// maxDenyConnections is the maximum number of denied connections stored by
+// DenyConnectionStore. As the denied connections are removed after an export
+// interval without any dropped packet, the connections of the packets dropped
+// after the limit is reached are not recorded until then, so that the memory
+// used by the store is bounded when many connections are denied, e.g. during a
+// port scan.
+const maxDenyConnections = 10000
+
+// DenyConnectionStore stores the connections denied by NetworkPolicies. As
+// the denied connections are never committed to conntrack, they are learned
+// from the packets sent to the controller by the drop flows, and the packets
+// of the same connection are aggregated by 5-tuple.
+type DenyConnectionStore struct {
+	connections map[flowexporter.ConnectionKey]*flowexporter.FlowRecord
+	// maxConnections is the maximum number of connections in connections.
+	maxConnections int
+	// ignoredConnections counts the new connections which have not been
+	// recorded since the last export as the limit has been reached.
+	ignoredConnections   uint64
+	ifaceStore           interfacestore.InterfaceStore
+	networkPolicyQuerier querier.AgentNetworkPolicyInfoQuerier
+	mutex                sync.Mutex
+}
+
+func NewDenyConnectionStore(ifaceStore interfacestore.InterfaceStore, npQuerier querier.AgentNetworkPolicyInfoQuerier) *DenyConnectionStore {
+	return &DenyConnectionStore{
+		connections:          make(map[flowexporter.ConnectionKey]*flowexporter.FlowRecord),
+		maxConnections:       maxDenyConnections,
+		ifaceStore:           ifaceStore,
+		networkPolicyQuerier: npQuerier,
+	}
+}
+
+// HandlePacketIn records the connection of the packet dropped by a
+// NetworkPolicy rule or by the isolation of NetworkPolicies. The other packets
+// sent to the controller by the NetworkPolicy flows are ignored.
+func (ds *DenyConnectionStore) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
+	if pktIn == nil {
+		return errors.New("empty packet-in for denied connection")
+	}
+	marks, err := getRegValue(pktIn, int(openflow.CustomReasonMarkReg))
+	if err != nil {
+		return fmt.Errorf("custom reasons of NetworkPolicy packet-in cannot be got: %v", err)
+	}
+	customReasons := ofctrl.GetUint32ValueWithRange(marks, openflow.CustomReasonMarkRange.ToNXRange())
+	if customReasons&openflow.CustomReasonDeny != openflow.CustomReasonDeny {
+		return nil
+	}
+	// The template of the flow records only has IPv4 information elements.
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return nil
+	}
+	ipPkt, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return errors.New("invalid IPv4 packet of denied connection")
+	}
+	conn, err := ipPacketToConnection(ipPkt)
+	if err != nil {
+		return err
+	}
+	// The ID of the rule is only available for the packets dropped by a
+	// NetworkPolicy rule. The packets dropped by the isolation of
+	// NetworkPolicies are not attributed to any NetworkPolicy.
+	if ofctrl.GetUint32ValueWithRange(marks, openflow.CNPDropMarkRange.ToNXRange()) == openflow.CNPDropMark {
+		ruleID, err := getRegValue(pktIn, int(openflow.CNPDropConjunctionIDReg))
+		if err != nil {
+			return fmt.Errorf("rule ID of denied connection cannot be got: %v", err)
+		}
+		ds.addNetworkPolicyInfo(conn, binding.TableIDType(pktIn.TableId), ruleID)
+	}
+	ds.AddOrUpdateConn(conn, time.Now(), uint64(ipPkt.Length))
+	return nil
+}
+
+// AddOrUpdateConn adds the denied connection with the first packet seen at
+// timeSeen, or updates the counters of the connection if it already exists.
+// The connection is not added if the store is full.
+func (ds *DenyConnectionStore) AddOrUpdateConn(conn *flowexporter.Connection, timeSeen time.Time, bytes uint64) {
+	connKey := flowexporter.NewConnectionKey(conn)
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	if record, exists := ds.connections[connKey]; exists {
+		record.Conn.OriginalPackets++
+		record.Conn.OriginalBytes += bytes
+		record.Conn.StopTime = timeSeen
+		record.Conn.IsActive = true
+		return
+	}
+	if len(ds.connections) >= ds.maxConnections {
+		ds.ignoredConnections++
+		return
+	}
+	conn.StartTime = timeSeen
+	conn.StopTime = timeSeen
+	conn.OriginalPackets = 1
+	conn.OriginalBytes = bytes
+	conn.IsActive = true
+	conn.DoExport = true
+	conn.IsDenied = true
+	sIface, srcFound := ds.ifaceStore.GetInterfaceByIP(conn.TupleOrig.SourceAddress.String())
+	if srcFound && sIface.Type == interfacestore.ContainerInterface {
+		conn.SourcePodName = sIface.ContainerInterfaceConfig.PodName
+		conn.SourcePodNamespace = sIface.ContainerInterfaceConfig.PodNamespace
+	}
+	dIface, dstFound := ds.ifaceStore.GetInterfaceByIP(conn.TupleOrig.DestinationAddress.String())
+	if dstFound && dIface.Type == interfacestore.ContainerInterface {
+		conn.DestinationPodName = dIface.ContainerInterfaceConfig.PodName
+		conn.DestinationPodNamespace = dIface.ContainerInterfaceConfig.PodNamespace
+	}
+	klog.V(4).Infof("New denied connection added: %v", conn)
+	ds.connections[connKey] = &flowexporter.FlowRecord{Conn: conn}
+}
+
+// ForAllFlowRecordsDo executes the callback for the flow records of the denied
+// connections which have been updated since the last call, and updates the
+// previous counters of the flow records after the callback succeeds. The
+// connections which have not been updated since the last call are removed, as
+// no packet of them has been dropped for an interval.
+func (ds *DenyConnectionStore) ForAllFlowRecordsDo(callback flowexporter.FlowRecordCallBack) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	if ds.ignoredConnections > 0 {
+		klog.Warningf("%d denied connections were not recorded as the limit of %d denied connections was reached", ds.ignoredConnections, ds.maxConnections)
+		ds.ignoredConnections = 0
+	}
+	for key, record := range ds.connections {
+		if !record.Conn.IsActive {
+			klog.V(2).Infof("Deleting the inactive denied connection with key: %v", key)
+			delete(ds.connections, key)
+			continue
+		}
+		if err := callback(key, *record); err != nil {
+			klog.Errorf("Callback execution failed for denied connection with key: %v: %v", key, err)
+			return err
+		}
+		record.PrevPackets = record.Conn.OriginalPackets
+		record.PrevBytes = record.Conn.OriginalBytes
+		record.Conn.IsActive = false
+	}
+	return nil
+}
+
+// addNetworkPolicyInfo resolves the NetworkPolicy of the rule which denied the
+// connection. The direction of the rule is given by the table of the flow.
+func (ds *DenyConnectionStore) addNetworkPolicyInfo(conn *flowexporter.Connection, tableID binding.TableIDType, ruleID uint32) {
+	policy := ds.networkPolicyQuerier.GetNetworkPolicyByRuleFlowID(ruleID)
+	if policy == nil {
+		klog.Warningf("Could not retrieve the NetworkPolicy of the rule %d", ruleID)
+		return
+	}
+	// The packets dropped by a rule are sent to the controller by the metric
+	// table of the direction of the rule.
+	if tableID == openflow.EgressMetricTable {
+		conn.EgressNetworkPolicyName = policy.Name
+		conn.EgressNetworkPolicyNamespace = policy.Namespace
+		return
+	}
+	conn.IngressNetworkPolicyName = policy.Name
+	conn.IngressNetworkPolicyNamespace = policy.Namespace
+}
+
+// ipPacketToConnection returns the connection of the IPv4 packet, with the
+// reply tuple reversed from the original tuple as the packet is not NAT'd
+// after the NetworkPolicy tables.
+func ipPacketToConnection(ipPkt *protocol.IPv4) (*flowexporter.Connection, error) {
+	tuple := flowexporter.Tuple{
+		SourceAddress:      append(net.IP(nil), ipPkt.NWSrc.To4()...),
+		DestinationAddress: append(net.IP(nil), ipPkt.NWDst.To4()...),
+		Protocol:           ipPkt.Protocol,
+	}
+	switch ipPkt.Protocol {
+	case protocol.Type_TCP:
+		// TCP segments are not decoded by the IPv4 parser.
+		tcpData, err := ipPkt.Data.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TCP header of denied packet: %v", err)
+		}
+		tcpPkt := new(protocol.TCP)
+		if err := tcpPkt.UnmarshalBinary(tcpData); err != nil {
+			return nil, fmt.Errorf("failed to parse TCP header of denied packet: %v", err)
+		}
+		tuple.SourcePort, tuple.DestinationPort = tcpPkt.PortSrc, tcpPkt.PortDst
+	case protocol.Type_UDP:
+		if udpPkt, ok := ipPkt.Data.(*protocol.UDP); ok {
+			tuple.SourcePort, tuple.DestinationPort = udpPkt.PortSrc, udpPkt.PortDst
+		}
+	}
+	return &flowexporter.Connection{
+		Zone:      openflow.CtZone,
+		TupleOrig: tuple,
+		TupleReply: flowexporter.Tuple{
+			SourceAddress:      tuple.DestinationAddress,
+			DestinationAddress: tuple.SourceAddress,
+			Protocol:           tuple.Protocol,
+			SourcePort:         tuple.DestinationPort,
+			DestinationPort:    tuple.SourcePort,
+		},
+	}, nil
+}
+
+// getRegValue returns the value of the provided register in the packet-in
+// message.
+func getRegValue(pktIn *ofctrl.PacketIn, reg int) (uint32, error) {
+	match := pktIn.GetMatches().GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", reg))
+	if match == nil {
+		return 0, fmt.Errorf("reg%d of packet-in not found", reg)
+	}
+	regValue, ok := match.GetValue().(*ofctrl.NXRegister)
+	if !ok {
+		return 0, fmt.Errorf("reg%d of packet-in cannot be got", reg)
+	}
+	return regValue.Data, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	queriertest "github.com/vmware-tanzu/antrea/pkg/querier/testing"
+)
+
+var (
+	denySrcIP = net.ParseIP("10.10.0.2").To4()
+	denyDstIP = net.ParseIP("10.10.1.3").To4()
+)
+
+func newDenyPacketIn(tableID uint8, marks uint32, ruleID uint32, ipPkt *protocol.IPv4) *ofctrl.PacketIn {
+	return &ofctrl.PacketIn{
+		TableId: tableID,
+		Match: openflow13.Match{Fields: []openflow13.MatchField{
+			*openflow13.NewRegMatchField(int(openflow.CustomReasonMarkReg), marks, nil),
+			*openflow13.NewRegMatchField(int(openflow.CNPDropConjunctionIDReg), ruleID, nil),
+		}},
+		Data: protocol.Ethernet{
+			Ethertype: protocol.IPv4_MSG,
+			Data:      ipPkt,
+		},
+	}
+}
+
+func newTCPPacket(srcPort uint16) *protocol.IPv4 {
+	return &protocol.IPv4{
+		Version:  4,
+		IHL:      5,
+		Length:   60,
+		TTL:      64,
+		Protocol: protocol.Type_TCP,
+		NWSrc:    denySrcIP,
+		NWDst:    denyDstIP,
+		Data:     &protocol.TCP{PortSrc: srcPort, PortDst: 80, HdrLen: 5, Code: 0x2},
+	}
+}
+
+func TestDenyConnectionStore_HandlePacketIn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	denyReason := uint32(openflow.CustomReasonDeny) << uint32(openflow.CustomReasonMarkRange[0])
+	loggingReason := uint32(openflow.CustomReasonLogging) << uint32(openflow.CustomReasonMarkRange[0])
+	dropMark := uint32(openflow.CNPDropMark) << uint32(openflow.CNPDropMarkRange[0])
+	egressMetricTable := uint8(openflow.EgressMetricTable)
+	ingressTable := uint8(openflow.GetAntreaPolicyIngressTables()[0])
+
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceStore.AddInterface(interfacestore.NewContainerInterface("pod1-abc", "container1", "pod1", "ns1", nil, []net.IP{denySrcIP}))
+	mockNPQuerier := queriertest.NewMockAgentNetworkPolicyInfoQuerier(ctrl)
+	mockNPQuerier.EXPECT().GetNetworkPolicyByRuleFlowID(uint32(10)).Return(&cpv1beta1.NetworkPolicyReference{Name: "np1", Namespace: "ns1"}).Times(2)
+	ds := NewDenyConnectionStore(ifaceStore, mockNPQuerier)
+
+	// The packets dropped by an egress rule are aggregated by 5-tuple.
+	require.NoError(t, ds.HandlePacketIn(newDenyPacketIn(egressMetricTable, denyReason|dropMark, 10, newTCPPacket(34567))))
+	require.NoError(t, ds.HandlePacketIn(newDenyPacketIn(egressMetricTable, denyReason|dropMark|loggingReason, 10, newTCPPacket(34567))))
+	// The packet dropped by the isolation of NetworkPolicies has no rule.
+	require.NoError(t, ds.HandlePacketIn(newDenyPacketIn(ingressTable, denyReason, 0, newTCPPacket(34568))))
+	// The packet which is only logged is ignored.
+	require.NoError(t, ds.HandlePacketIn(newDenyPacketIn(ingressTable, loggingReason, 10, newTCPPacket(34569))))
+	require.Len(t, ds.connections, 2)
+
+	tuple, revTuple := makeTuple(&denySrcIP, &denyDstIP, protocol.Type_TCP, 34567, 80)
+	record := ds.connections[flowexporter.NewConnectionKey(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple})]
+	require.NotNil(t, record)
+	conn := record.Conn
+	assert.Equal(t, tuple, conn.TupleOrig)
+	assert.Equal(t, revTuple, conn.TupleReply)
+	assert.Equal(t, uint64(2), conn.OriginalPackets)
+	assert.Equal(t, uint64(120), conn.OriginalBytes)
+	assert.True(t, conn.IsDenied)
+	assert.Equal(t, "pod1", conn.SourcePodName)
+	assert.Equal(t, "ns1", conn.SourcePodNamespace)
+	assert.Empty(t, conn.DestinationPodName)
+	assert.Equal(t, "np1", conn.EgressNetworkPolicyName)
+	assert.Equal(t, "ns1", conn.EgressNetworkPolicyNamespace)
+	assert.Empty(t, conn.IngressNetworkPolicyName)
+
+	tuple, revTuple = makeTuple(&denySrcIP, &denyDstIP, protocol.Type_TCP, 34568, 80)
+	record = ds.connections[flowexporter.NewConnectionKey(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple})]
+	require.NotNil(t, record)
+	assert.Equal(t, uint64(1), record.Conn.OriginalPackets)
+	assert.Empty(t, record.Conn.IngressNetworkPolicyName)
+	assert.Empty(t, record.Conn.EgressNetworkPolicyName)
+}
+
+func TestDenyConnectionStore_ForAllFlowRecordsDo(t *testing.T) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ds := NewDenyConnectionStore(ifaceStore, nil)
+	tuple, revTuple := makeTuple(&denySrcIP, &denyDstIP, protocol.Type_TCP, 34567, 80)
+	refTime := time.Now()
+	ds.AddOrUpdateConn(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}, refTime, 60)
+	ds.AddOrUpdateConn(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}, refTime.Add(time.Second), 60)
+
+	collectRecords := func() []flowexporter.FlowRecord {
+		var records []flowexporter.FlowRecord
+		err := ds.ForAllFlowRecordsDo(func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
+			records = append(records, record)
+			return nil
+		})
+		require.NoError(t, err)
+		return records
+	}
+
+	records := collectRecords()
+	require.Len(t, records, 1)
+	assert.Equal(t, refTime, records[0].Conn.StartTime)
+	assert.Equal(t, refTime.Add(time.Second), records[0].Conn.StopTime)
+	assert.Equal(t, uint64(2), records[0].Conn.OriginalPackets)
+	assert.Equal(t, uint64(0), records[0].PrevPackets)
+
+	// The counters of the connection keep increasing in the next interval.
+	ds.AddOrUpdateConn(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}, refTime.Add(2*time.Second), 60)
+	records = collectRecords()
+	require.Len(t, records, 1)
+	assert.Equal(t, uint64(3), records[0].Conn.OriginalPackets)
+	assert.Equal(t, uint64(2), records[0].PrevPackets)
+	assert.Equal(t, uint64(120), records[0].PrevBytes)
+
+	// The connection is removed after an interval without any denied packet.
+	assert.Empty(t, collectRecords())
+	assert.Empty(t, ds.connections)
+}
+
+func TestDenyConnectionStore_MaxConnections(t *testing.T) {
+	ds := NewDenyConnectionStore(interfacestore.NewInterfaceStore(), nil)
+	ds.maxConnections = 2
+	refTime := time.Now()
+	for srcPort := uint16(34567); srcPort < 34570; srcPort++ {
+		tuple, revTuple := makeTuple(&denySrcIP, &denyDstIP, protocol.Type_TCP, srcPort, 80)
+		ds.AddOrUpdateConn(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}, refTime, 60)
+	}
+	// The third connection is not added as the store is full, but the existing
+	// connections are still updated.
+	require.Len(t, ds.connections, 2)
+	assert.Equal(t, uint64(1), ds.ignoredConnections)
+	tuple, revTuple := makeTuple(&denySrcIP, &denyDstIP, protocol.Type_TCP, 34567, 80)
+	ds.AddOrUpdateConn(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}, refTime, 60)
+	record := ds.connections[flowexporter.NewConnectionKey(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple})]
+	require.NotNil(t, record)
+	assert.Equal(t, uint64(2), record.Conn.OriginalPackets)
+
+	// New connections are added again after the inactive ones are removed.
+	noop := func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
+		return nil
+	}
+	require.NoError(t, ds.ForAllFlowRecordsDo(noop))
+	assert.Equal(t, uint64(0), ds.ignoredConnections)
+	require.NoError(t, ds.ForAllFlowRecordsDo(noop))
+	require.Empty(t, ds.connections)
+	tuple, revTuple = makeTuple(&denySrcIP, &denyDstIP, protocol.Type_TCP, 34569, 80)
+	ds.AddOrUpdateConn(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}, refTime, 60)
+	assert.Len(t, ds.connections, 1)
+}
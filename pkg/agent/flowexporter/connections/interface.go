@@ -27,3 +27,23 @@ type ConnTrackDumper interface {
 	// GetMaxConnections returns the size of the connection tracking table.
 	GetMaxConnections() (int, error)
 }
+
+// ConnTrackEventType is the type of a conntrack event.
+type ConnTrackEventType uint8
+
+const (
+	ConnTrackEventNew ConnTrackEventType = iota
+	ConnTrackEventUpdate
+	ConnTrackEventDestroy
+)
+
+// ConnTrackEventHandler handles a conntrack event of the connection.
+type ConnTrackEventHandler func(eventType ConnTrackEventType, conn *flowexporter.Connection)
+
+// ConnTrackListener is an interface that is used to subscribe to the NEW, UPDATE and DESTROY conntrack events over
+// netlink. It's only supported by the OVS kernel datapath on Linux.
+type ConnTrackListener interface {
+	// ListenEvents calls the handler for each event of the connections in the given zone until stopCh is closed. It
+	// returns an error if the subscription fails or is broken, e.g. when events are lost as the socket buffer overflows.
+	ListenEvents(zoneFilter uint16, handler ConnTrackEventHandler, stopCh <-chan struct{}) error
+}
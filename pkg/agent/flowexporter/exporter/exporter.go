@@ -17,22 +17,30 @@ package exporter
 import (
 	"fmt"
 	"hash/fnv"
-	"net"
 
-	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
-	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/flowrecords"
-	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix"
 	"github.com/vmware-tanzu/antrea/pkg/util/env"
 )
 
+// The values of the flowEndReason information element, see
+// https://www.iana.org/assignments/ipfix/ipfix.xhtml#ipfix-flow-end-reason.
+const (
+	ActiveTimeoutReason uint8 = 0x02
+	EndOfFlowReason     uint8 = 0x03
+	// DeniedReason is not assigned by IANA. It indicates that the connection
+	// is denied by NetworkPolicies.
+	DeniedReason uint8 = 0x80
+)
+
 var (
 	IANAInfoElements = []string{
 		"flowStartSeconds",
 		"flowEndSeconds",
+		"flowEndReason",
 		"sourceIPv4Address",
 		"destinationIPv4Address",
 		"sourceTransportPort",
@@ -43,6 +51,22 @@ var (
 		"packetDeltaCount",
 		"octetDeltaCount",
 	}
+	// IANAInfoElementsIPv6 are the IANA information elements of the template
+	// record for IPv6 connections.
+	IANAInfoElementsIPv6 = []string{
+		"flowStartSeconds",
+		"flowEndSeconds",
+		"flowEndReason",
+		"sourceIPv6Address",
+		"destinationIPv6Address",
+		"sourceTransportPort",
+		"destinationTransportPort",
+		"protocolIdentifier",
+		"packetTotalCount",
+		"octetTotalCount",
+		"packetDeltaCount",
+		"octetDeltaCount",
+	}
 	// Substring "reverse" is an indication to get reverse element of go-ipfix library.
 	IANAReverseInfoElements = []string{
 		"reverse_PacketTotalCount",
@@ -59,17 +83,35 @@ var (
 		"destinationNodeName",
 		"destinationClusterIP",
 		"destinationServicePortName",
+		"ingressNetworkPolicyName",
+		"ingressNetworkPolicyNamespace",
+		"egressNetworkPolicyName",
+		"egressNetworkPolicyNamespace",
+	}
+	// AntreaInfoElementsIPv6 are the Antrea information elements of the
+	// template record for IPv6 connections.
+	AntreaInfoElementsIPv6 = []string{
+		"sourcePodName",
+		"sourcePodNamespace",
+		"sourceNodeName",
+		"destinationPodName",
+		"destinationPodNamespace",
+		"destinationNodeName",
+		"destinationClusterIPv6",
+		"destinationServicePortName",
+		"ingressNetworkPolicyName",
+		"ingressNetworkPolicyNamespace",
+		"egressNetworkPolicyName",
+		"egressNetworkPolicyNamespace",
 	}
 )
 
 type flowExporter struct {
 	flowRecords     *flowrecords.FlowRecords
-	process         ipfix.IPFIXExportingProcess
-	elementsList    []*ipfixentities.InfoElement
+	denyConnStore   *connections.DenyConnectionStore
+	sinks           []*bufferedSink
 	exportFrequency uint
 	pollCycle       uint
-	templateID      uint16
-	registry        ipfix.IPFIXRegistry
 }
 
 func genObservationID() (uint32, error) {
@@ -82,22 +124,28 @@ func genObservationID() (uint32, error) {
 	return h.Sum32(), nil
 }
 
-func NewFlowExporter(records *flowrecords.FlowRecords, exportFrequency uint) *flowExporter {
-	registry := ipfix.NewIPFIXRegistry()
-	registry.LoadRegistry()
+func NewFlowExporter(records *flowrecords.FlowRecords, denyConnStore *connections.DenyConnectionStore, exportFrequency uint) *flowExporter {
 	return &flowExporter{
-		records,
-		nil,
-		nil,
-		exportFrequency,
-		0,
-		0,
-		registry,
+		flowRecords:     records,
+		denyConnStore:   denyConnStore,
+		exportFrequency: exportFrequency,
 	}
 }
 
-// DoExport enables us to export flow records periodically at a given flow export frequency.
-func (exp *flowExporter) Export(collector net.Addr, stopCh <-chan struct{}, pollDone <-chan struct{}) {
+// AddSink adds a Sink the flow records are exported to. Up to bufferSize flow
+// records are buffered for the Sink while it's unavailable, the flow records
+// exceeding it are dropped. It must be called before Export.
+func (exp *flowExporter) AddSink(sink flowexporter.Sink, bufferSize int) {
+	exp.sinks = append(exp.sinks, newBufferedSink(sink, bufferSize))
+}
+
+// Export exports flow records to the Sinks periodically at a given flow export frequency. The flow records of the
+// connections received from connEnded, which are destroyed in conntrack, are exported immediately. connEnded is nil
+// if conntrack events are not enabled.
+func (exp *flowExporter) Export(stopCh <-chan struct{}, pollDone <-chan struct{}, connEnded <-chan flowexporter.ConnectionKey) {
+	for _, sink := range exp.sinks {
+		go sink.run(stopCh)
+	}
 	for {
 		select {
 		case <-stopCh:
@@ -107,248 +155,92 @@ func (exp *flowExporter) Export(collector net.Addr, stopCh <-chan struct{}, poll
 			// the export cycle. This is necessary because IPFIX collector computes throughput based on flow records received interval.
 			exp.pollCycle++
 			if exp.pollCycle%exp.exportFrequency == 0 {
-				// Retry to connect to IPFIX collector if the exporting process gets reset
-				if exp.process == nil {
-					err := exp.initFlowExporter(collector)
-					if err != nil {
-						klog.Errorf("Error when initializing flow exporter: %v", err)
-						// There could be other errors while initializing flow exporter other than connecting to IPFIX collector,
-						// therefore closing the connection and resetting the process.
-						if exp.process != nil {
-							exp.process.CloseConnToCollector()
-							exp.process = nil
-						}
-						return
-					}
-				}
-				// Build and send flow records to IPFIX collector.
 				exp.flowRecords.BuildFlowRecords()
-				err := exp.sendFlowRecords()
-				if err != nil {
-					klog.Errorf("Error when sending flow records: %v", err)
-					// If there is an error when sending flow records because of intermittent connectivity, we reset the connection
-					// to IPFIX collector and retry in the next export cycle to reinitialize the connection and send flow records.
-					exp.process.CloseConnToCollector()
-					exp.process = nil
-					return
+				if err := exp.exportFlowRecords(); err != nil {
+					klog.Errorf("Error when exporting flow records: %v", err)
 				}
-
 				exp.pollCycle = 0
-				klog.V(2).Infof("Successfully exported IPFIX flow records")
+			}
+		case connKey := <-connEnded:
+			if err := exp.exportEndedFlowRecord(connKey); err != nil {
+				klog.Errorf("Error when exporting flow record of the ended connection: %v", err)
 			}
 		}
 	}
-
 }
 
-func (exp *flowExporter) initFlowExporter(collector net.Addr) error {
-	// Create IPFIX exporting expProcess, initialize registries and other related entities
-	obsID, err := genObservationID()
-	if err != nil {
-		return fmt.Errorf("cannot generate obsID for IPFIX ipfixexport: %v", err)
+// exportFlowRecords adds the flow records to the buffers of the Sinks, which
+// send them asynchronously, so an unavailable Sink never delays the others.
+func (exp *flowExporter) exportFlowRecords() error {
+	var records []flowexporter.FlowRecord
+	addFlowRecord := func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
+		// The connection is copied as the flow record is buffered, while
+		// the connections of the denied connections are updated in place.
+		conn := *record.Conn
+		record.Conn = &conn
+		records = append(records, record)
+		return nil
 	}
-
-	var expProcess ipfix.IPFIXExportingProcess
-	if collector.Network() == "tcp" {
-		// TCP transport do not need any tempRefTimeout, so sending 0.
-		expProcess, err = ipfix.NewIPFIXExportingProcess(collector, obsID, 0)
-	} else {
-		// For UDP transport, hardcoding tempRefTimeout value as 1800s.
-		expProcess, err = ipfix.NewIPFIXExportingProcess(collector, obsID, 1800)
+	addAndUpdateFlowRecord := func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
+		addFlowRecord(key, record)
+		return exp.flowRecords.ValidateAndUpdateStats(key, record)
 	}
+	err := exp.flowRecords.ForAllFlowRecordsDo(addAndUpdateFlowRecord)
 	if err != nil {
-		return err
-	}
-	exp.process = expProcess
-	exp.templateID = expProcess.NewTemplateID()
-
-	templateRec := ipfix.NewIPFIXTemplateRecord(uint16(len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements)), exp.templateID)
-
-	sentBytes, err := exp.sendTemplateRecord(templateRec)
-	if err != nil {
-		return err
+		return fmt.Errorf("error when iterating flow records: %v", err)
 	}
-	klog.V(2).Infof("Initialized flow exporter and sent %d bytes size of template record", sentBytes)
-
-	return nil
-}
-
-func (exp *flowExporter) sendFlowRecords() error {
-	sendAndUpdateFlowRecord := func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
-		dataRec := ipfix.NewIPFIXDataRecord(exp.templateID)
-		if err := exp.sendDataRecord(dataRec, record); err != nil {
-			return err
-		}
-		if err := exp.flowRecords.ValidateAndUpdateStats(key, record); err != nil {
-			return err
+	// The flow records of the denied connections are updated by the deny
+	// connection store after they are added.
+	if exp.denyConnStore != nil {
+		if err := exp.denyConnStore.ForAllFlowRecordsDo(addFlowRecord); err != nil {
+			return fmt.Errorf("error when iterating flow records of denied connections: %v", err)
 		}
-		return nil
 	}
-	err := exp.flowRecords.ForAllFlowRecordsDo(sendAndUpdateFlowRecord)
-	if err != nil {
-		return fmt.Errorf("error when iterating flow records: %v", err)
+	for _, sink := range exp.sinks {
+		sink.add(records)
 	}
+	klog.V(2).Infof("Exported %d flow records to %d sinks", len(records), len(exp.sinks))
 	return nil
 }
 
-func (exp *flowExporter) sendTemplateRecord(templateRec ipfix.IPFIXRecord) (int, error) {
-	// Add template header
-	_, err := templateRec.PrepareRecord()
-	if err != nil {
-		return 0, fmt.Errorf("error when writing template header: %v", err)
-	}
-
-	for _, ie := range IANAInfoElements {
-		element, err := exp.registry.GetInfoElement(ie, ipfixregistry.IANAEnterpriseID)
-		if err != nil {
-			return 0, fmt.Errorf("%s not present. returned error: %v", ie, err)
-		}
-		if _, err = templateRec.AddInfoElement(element, nil); err != nil {
-			return 0, fmt.Errorf("error when adding %s to template: %v", element.Name, err)
-		}
+// exportEndedFlowRecord exports the final flow record of the connection destroyed in conntrack without waiting for the
+// next export cycle, and deletes the connection.
+func (exp *flowExporter) exportEndedFlowRecord(connKey flowexporter.ConnectionKey) error {
+	record, exists := exp.flowRecords.BuildFlowRecordByConnKey(connKey)
+	if !exists {
+		// The connection has been exported and deleted in an export cycle.
+		return nil
 	}
-	for _, ie := range IANAReverseInfoElements {
-		element, err := exp.registry.GetInfoElement(ie, ipfixregistry.ReverseEnterpriseID)
-		if err != nil {
-			return 0, fmt.Errorf("%s not present. returned error: %v", ie, err)
-		}
-		if _, err = templateRec.AddInfoElement(element, nil); err != nil {
-			return 0, fmt.Errorf("error when adding %s to template: %v", element.Name, err)
-		}
+	if err := exp.flowRecords.ValidateAndUpdateStats(connKey, *record); err != nil {
+		return err
 	}
-	for _, ie := range AntreaInfoElements {
-		element, err := exp.registry.GetInfoElement(ie, ipfixregistry.AntreaEnterpriseID)
-		if err != nil {
-			return 0, fmt.Errorf("information element %s is not present in Antrea registry", ie)
-		}
-		if _, err := templateRec.AddInfoElement(element, nil); err != nil {
-			return 0, fmt.Errorf("error when adding %s to template: %v", element.Name, err)
-		}
+	for _, sink := range exp.sinks {
+		sink.add([]flowexporter.FlowRecord{*record})
 	}
+	klog.V(4).Infof("Exported flow record of the ended connection with key: %v", connKey)
+	return nil
+}
 
-	sentBytes, err := exp.process.AddRecordAndSendMsg(ipfixentities.Template, templateRec.GetRecord())
-	if err != nil {
-		return 0, fmt.Errorf("error in IPFIX exporting process when sending template record: %v", err)
+// flowEndReason returns the value of the flowEndReason information element of
+// the connection.
+func flowEndReason(conn *flowexporter.Connection) uint8 {
+	if conn.IsDenied {
+		return DeniedReason
+	} else if conn.IsActive {
+		return ActiveTimeoutReason
 	}
-
-	// Get all elements from template record.
-	exp.elementsList = templateRec.GetTemplateElements()
-
-	return sentBytes, nil
+	// The connection is no longer in conntrack.
+	return EndOfFlowReason
 }
 
-func (exp *flowExporter) sendDataRecord(dataRec ipfix.IPFIXRecord, record flowexporter.FlowRecord) error {
-	nodeName, _ := env.GetNodeName()
-	// Iterate over all infoElements in the list
-	for _, ie := range exp.elementsList {
-		var err error
-		switch ieName := ie.Name; ieName {
-		case "flowStartSeconds":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.StartTime.Unix())
-		case "flowEndSeconds":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.StopTime.Unix())
-		case "sourceIPv4Address":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.SourceAddress)
-		case "destinationIPv4Address":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleReply.SourceAddress)
-		case "sourceTransportPort":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.SourcePort)
-		case "destinationTransportPort":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleReply.SourcePort)
-		case "protocolIdentifier":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.Protocol)
-		case "packetTotalCount":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.OriginalPackets)
-		case "octetTotalCount":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.OriginalBytes)
-		case "packetDeltaCount":
-			deltaPkts := 0
-			if record.PrevPackets != 0 {
-				deltaPkts = int(record.Conn.OriginalPackets) - int(record.PrevPackets)
-			}
-			if deltaPkts < 0 {
-				klog.Warningf("Delta packets is not expected to be negative: %d", deltaPkts)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaPkts))
-		case "octetDeltaCount":
-			deltaBytes := 0
-			if record.PrevBytes != 0 {
-				deltaBytes = int(record.Conn.OriginalBytes) - int(record.PrevBytes)
-			}
-			if deltaBytes < 0 {
-				klog.Warningf("Delta bytes is not expected to be negative: %d", deltaBytes)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaBytes))
-		case "reverse_PacketTotalCount":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.ReversePackets)
-		case "reverse_OctetTotalCount":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.ReverseBytes)
-		case "reverse_PacketDeltaCount":
-			deltaPkts := 0
-			if record.PrevReversePackets != 0 {
-				deltaPkts = int(record.Conn.ReversePackets) - int(record.PrevReversePackets)
-			}
-			if deltaPkts < 0 {
-				klog.Warningf("Delta packets is not expected to be negative: %d", deltaPkts)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaPkts))
-		case "reverse_OctetDeltaCount":
-			deltaBytes := 0
-			if record.PrevReverseBytes != 0 {
-				deltaBytes = int(record.Conn.ReverseBytes) - int(record.PrevReverseBytes)
-			}
-			if deltaBytes < 0 {
-				klog.Warningf("Delta bytes is not expected to be negative: %d", deltaBytes)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaBytes))
-		case "sourcePodNamespace":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.SourcePodNamespace)
-		case "sourcePodName":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.SourcePodName)
-		case "sourceNodeName":
-			// Add nodeName for only local pods whose pod names are resolved.
-			if record.Conn.SourcePodName != "" {
-				_, err = dataRec.AddInfoElement(ie, nodeName)
-			} else {
-				_, err = dataRec.AddInfoElement(ie, "")
-			}
-		case "destinationPodNamespace":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationPodNamespace)
-		case "destinationPodName":
-			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationPodName)
-		case "destinationNodeName":
-			// Add nodeName for only local pods whose pod names are resolved.
-			if record.Conn.DestinationPodName != "" {
-				_, err = dataRec.AddInfoElement(ie, nodeName)
-			} else {
-				_, err = dataRec.AddInfoElement(ie, "")
-			}
-		case "destinationClusterIP":
-			if record.Conn.DestinationServicePortName != "" {
-				_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.DestinationAddress)
-			} else {
-				// Sending dummy IP as IPFIX collector expects constant length of data for IP field.
-				// We should probably think of better approach as this involves customization of IPFIX collector to ignore
-				// this dummy IP address.
-				_, err = dataRec.AddInfoElement(ie, net.IP{0, 0, 0, 0})
-			}
-		case "destinationServicePortName":
-			if record.Conn.DestinationServicePortName != "" {
-				_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationServicePortName)
-			} else {
-				_, err = dataRec.AddInfoElement(ie, "")
-			}
-		}
-		if err != nil {
-			return fmt.Errorf("error while adding info element: %s to data record: %v", ie.Name, err)
-		}
+// deltaCount returns the count since the last export of the flow record. It's
+// 0 in the first flow record of a connection.
+func deltaCount(count, prevCount uint64) uint64 {
+	if prevCount == 0 {
+		return 0
 	}
-
-	sentBytes, err := exp.process.AddRecordAndSendMsg(ipfixentities.Data, dataRec.GetRecord())
-	if err != nil {
-		return fmt.Errorf("error in IPFIX exporting process when sending data record: %v", err)
+	if count < prevCount {
+		klog.Warningf("Delta count is not expected to be negative: %d", int64(count)-int64(prevCount))
 	}
-	klog.V(4).Infof("Flow record created and sent. Bytes sent: %d", sentBytes)
-
-	return nil
+	return count - prevCount
 }
@@ -15,17 +15,29 @@
 package exporter
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
 	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	certutil "k8s.io/client-go/util/cert"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
+	connectionstest "github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/flowrecords"
 	ipfixtest "github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 )
 
 const (
@@ -33,32 +45,38 @@ const (
 	testFlowExportFrequency = 12
 )
 
-func TestFlowExporter_sendTemplateRecord(t *testing.T) {
+func TestIPFIXSink_sendTemplateRecord(t *testing.T) {
+	t.Run("IPv4", func(t *testing.T) { testSendTemplateRecord(t, false) })
+	t.Run("IPv6", func(t *testing.T) { testSendTemplateRecord(t, true) })
+}
+
+func testSendTemplateRecord(t *testing.T, isIPv6 bool) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockIPFIXExpProc := ipfixtest.NewMockIPFIXExportingProcess(ctrl)
 	mockTempRec := ipfixtest.NewMockIPFIXRecord(ctrl)
 	mockIPFIXRegistry := ipfixtest.NewMockIPFIXRegistry(ctrl)
-	flowExp := &flowExporter{
-		nil,
-		mockIPFIXExpProc,
-		nil,
-		testFlowExportFrequency,
-		0,
-		testTemplateID,
-		mockIPFIXRegistry,
+	flowExp := &ipfixSink{
+		process:      mockIPFIXExpProc,
+		templateIDv4: testTemplateID,
+		templateIDv6: testTemplateID + 1,
+		registry:     mockIPFIXRegistry,
+	}
+	ianaInfoElements, antreaInfoElements := IANAInfoElements, AntreaInfoElements
+	if isIPv6 {
+		ianaInfoElements, antreaInfoElements = IANAInfoElementsIPv6, AntreaInfoElementsIPv6
 	}
 	// Following consists of all elements that are in IANAInfoElements and AntreaInfoElements (globals)
 	// Only the element name is needed, other arguments have dummy values.
 	elemList := make([]*ipfixentities.InfoElement, 0)
-	for _, ie := range IANAInfoElements {
+	for _, ie := range ianaInfoElements {
 		elemList = append(elemList, ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.IANAEnterpriseID, 0))
 	}
 	for _, ie := range IANAReverseInfoElements {
 		elemList = append(elemList, ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.ReverseEnterpriseID, 0))
 	}
-	for _, ie := range AntreaInfoElements {
+	for _, ie := range antreaInfoElements {
 		elemList = append(elemList, ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.AntreaEnterpriseID, 0))
 	}
 	// Expect calls for different mock objects
@@ -66,17 +84,17 @@ func TestFlowExporter_sendTemplateRecord(t *testing.T) {
 	var templateRecord ipfixentities.Record
 
 	mockTempRec.EXPECT().PrepareRecord().Return(tempBytes, nil)
-	for i, ie := range IANAInfoElements {
+	for i, ie := range ianaInfoElements {
 		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.IANAEnterpriseID).Return(elemList[i], nil)
 		mockTempRec.EXPECT().AddInfoElement(elemList[i], nil).Return(tempBytes, nil)
 	}
 	for i, ie := range IANAReverseInfoElements {
-		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.ReverseEnterpriseID).Return(elemList[i+len(IANAInfoElements)], nil)
-		mockTempRec.EXPECT().AddInfoElement(elemList[i+len(IANAInfoElements)], nil).Return(tempBytes, nil)
+		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.ReverseEnterpriseID).Return(elemList[i+len(ianaInfoElements)], nil)
+		mockTempRec.EXPECT().AddInfoElement(elemList[i+len(ianaInfoElements)], nil).Return(tempBytes, nil)
 	}
-	for i, ie := range AntreaInfoElements {
-		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.AntreaEnterpriseID).Return(elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)], nil)
-		mockTempRec.EXPECT().AddInfoElement(elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)], nil).Return(tempBytes, nil)
+	for i, ie := range antreaInfoElements {
+		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.AntreaEnterpriseID).Return(elemList[i+len(ianaInfoElements)+len(IANAReverseInfoElements)], nil)
+		mockTempRec.EXPECT().AddInfoElement(elemList[i+len(ianaInfoElements)+len(IANAReverseInfoElements)], nil).Return(tempBytes, nil)
 	}
 	mockTempRec.EXPECT().GetRecord().Return(templateRecord)
 	mockTempRec.EXPECT().GetTemplateElements().Return(elemList)
@@ -84,17 +102,27 @@ func TestFlowExporter_sendTemplateRecord(t *testing.T) {
 	// above elements: IANAInfoElements, IANAReverseInfoElements and AntreaInfoElements.
 	mockIPFIXExpProc.EXPECT().AddRecordAndSendMsg(ipfixentities.Template, templateRecord).Return(0, nil)
 
-	_, err := flowExp.sendTemplateRecord(mockTempRec)
+	_, err := flowExp.sendTemplateRecord(mockTempRec, isIPv6)
 	if err != nil {
 		t.Errorf("Error in sending templated record: %v", err)
 	}
 
-	assert.Equal(t, len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements), len(flowExp.elementsList), flowExp.elementsList, "flowExp.elementsList and template record should have same number of elements")
+	elementsList, otherElementsList := flowExp.elementsListv4, flowExp.elementsListv6
+	if isIPv6 {
+		elementsList, otherElementsList = flowExp.elementsListv6, flowExp.elementsListv4
+	}
+	assert.Equal(t, len(ianaInfoElements)+len(IANAReverseInfoElements)+len(antreaInfoElements), len(elementsList), elementsList, "flowExp.elementsList and template record should have same number of elements")
+	assert.Empty(t, otherElementsList)
 }
 
-// TestFlowExporter_sendDataRecord tests essentially if element names in the switch-case matches globals
+// TestIPFIXSink_sendDataRecord tests essentially if element names in the switch-case matches globals
 // IANAInfoElements and AntreaInfoElements.
-func TestFlowExporter_sendDataRecord(t *testing.T) {
+func TestIPFIXSink_sendDataRecord(t *testing.T) {
+	t.Run("IPv4", func(t *testing.T) { testSendDataRecord(t, false) })
+	t.Run("IPv6", func(t *testing.T) { testSendDataRecord(t, true) })
+}
+
+func testSendDataRecord(t *testing.T, isIPv6 bool) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -132,57 +160,184 @@ func TestFlowExporter_sendDataRecord(t *testing.T) {
 		PrevReversePackets: 0,
 		PrevReverseBytes:   0,
 	}
+	ianaInfoElements, antreaInfoElements := IANAInfoElements, AntreaInfoElements
+	if isIPv6 {
+		ianaInfoElements, antreaInfoElements = IANAInfoElementsIPv6, AntreaInfoElementsIPv6
+	}
 	// Following consists of all elements that are in IANAInfoElements and AntreaInfoElements (globals)
 	// Need only element name and other are dummys
-	elemList := make([]*ipfixentities.InfoElement, len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements))
-	for i, ie := range IANAInfoElements {
+	elemList := make([]*ipfixentities.InfoElement, len(ianaInfoElements)+len(IANAReverseInfoElements)+len(antreaInfoElements))
+	for i, ie := range ianaInfoElements {
 		elemList[i] = ipfixentities.NewInfoElement(ie, 0, 0, 0, 0)
 	}
 	for i, ie := range IANAReverseInfoElements {
-		elemList[i+len(IANAInfoElements)] = ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.ReverseEnterpriseID, 0)
+		elemList[i+len(ianaInfoElements)] = ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.ReverseEnterpriseID, 0)
 	}
-	for i, ie := range AntreaInfoElements {
-		elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)] = ipfixentities.NewInfoElement(ie, 0, 0, 0, 0)
+	for i, ie := range antreaInfoElements {
+		elemList[i+len(ianaInfoElements)+len(IANAReverseInfoElements)] = ipfixentities.NewInfoElement(ie, 0, 0, 0, 0)
 	}
 
 	mockIPFIXExpProc := ipfixtest.NewMockIPFIXExportingProcess(ctrl)
 	mockDataRec := ipfixtest.NewMockIPFIXRecord(ctrl)
 	mockIPFIXRegistry := ipfixtest.NewMockIPFIXRegistry(ctrl)
-	flowExp := &flowExporter{
-		nil,
-		mockIPFIXExpProc,
-		elemList,
-		testFlowExportFrequency,
-		0,
-		testTemplateID,
-		mockIPFIXRegistry,
+	flowExp := &ipfixSink{
+		process:      mockIPFIXExpProc,
+		templateIDv4: testTemplateID,
+		templateIDv6: testTemplateID + 1,
+		registry:     mockIPFIXRegistry,
+	}
+	if isIPv6 {
+		flowExp.elementsListv6 = elemList
+	} else {
+		flowExp.elementsListv4 = elemList
 	}
 	// Expect calls required
 	var dataRecord ipfixentities.Record
 	tempBytes := uint16(0)
-	for _, ie := range flowExp.elementsList {
+	for _, ie := range elemList {
 		switch ieName := ie.Name; ieName {
 		case "flowStartSeconds", "flowEndSeconds":
 			mockDataRec.EXPECT().AddInfoElement(ie, time.Time{}.Unix()).Return(tempBytes, nil)
-		case "sourceIPv4Address", "destinationIPv4Address":
+		case "flowEndReason":
+			mockDataRec.EXPECT().AddInfoElement(ie, EndOfFlowReason).Return(tempBytes, nil)
+		case "sourceIPv4Address", "destinationIPv4Address", "sourceIPv6Address", "destinationIPv6Address":
 			mockDataRec.EXPECT().AddInfoElement(ie, nil).Return(tempBytes, nil)
 		case "destinationClusterIP":
 			mockDataRec.EXPECT().AddInfoElement(ie, net.IP{0, 0, 0, 0}).Return(tempBytes, nil)
+		case "destinationClusterIPv6":
+			mockDataRec.EXPECT().AddInfoElement(ie, net.IPv6zero).Return(tempBytes, nil)
 		case "sourceTransportPort", "destinationTransportPort":
 			mockDataRec.EXPECT().AddInfoElement(ie, uint16(0)).Return(tempBytes, nil)
 		case "protocolIdentifier":
 			mockDataRec.EXPECT().AddInfoElement(ie, uint8(0)).Return(tempBytes, nil)
 		case "packetTotalCount", "octetTotalCount", "packetDeltaCount", "octetDeltaCount", "reverse_PacketTotalCount", "reverse_OctetTotalCount", "reverse_PacketDeltaCount", "reverse_OctetDeltaCount":
 			mockDataRec.EXPECT().AddInfoElement(ie, uint64(0)).Return(tempBytes, nil)
-		case "sourcePodName", "sourcePodNamespace", "sourceNodeName", "destinationPodName", "destinationPodNamespace", "destinationNodeName", "destinationServicePortName":
+		case "sourcePodName", "sourcePodNamespace", "sourceNodeName", "destinationPodName", "destinationPodNamespace", "destinationNodeName", "destinationServicePortName",
+			"ingressNetworkPolicyName", "ingressNetworkPolicyNamespace", "egressNetworkPolicyName", "egressNetworkPolicyNamespace":
 			mockDataRec.EXPECT().AddInfoElement(ie, "").Return(tempBytes, nil)
+		default:
+			t.Errorf("Unexpected information element %s", ieName)
 		}
 	}
 	mockDataRec.EXPECT().GetRecord().Return(dataRecord)
 	mockIPFIXExpProc.EXPECT().AddRecordAndSendMsg(ipfixentities.Data, dataRecord).Return(0, nil)
 
-	err := flowExp.sendDataRecord(mockDataRec, record1)
+	err := flowExp.sendDataRecord(mockDataRec, record1, isIPv6)
 	if err != nil {
 		t.Errorf("Error in sending data record: %v", err)
 	}
 }
+
+// TestIPFIXSinkTLS sends flow records to a local TLS server, and validates the
+// sets of the received IPFIX messages.
+func TestIPFIXSinkTLS(t *testing.T) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	defer listener.Close()
+	// The set ID of each received message is sent to the channel.
+	setIDs := make(chan uint16, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			header := make([]byte, 20)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+			assert.Equal(t, uint16(10), binary.BigEndian.Uint16(header[0:2]))
+			if _, err := io.ReadFull(conn, make([]byte, binary.BigEndian.Uint16(header[2:4])-20)); err != nil {
+				return
+			}
+			setIDs <- binary.BigEndian.Uint16(header[16:18])
+		}
+	}()
+
+	rootCAs := x509.NewCertPool()
+	require.True(t, rootCAs.AppendCertsFromPEM(certPEM))
+	sink := NewIPFIXSink(listener.Addr(), &tls.Config{RootCAs: rootCAs})
+	assert.Equal(t, "IPFIX collector "+listener.Addr().String()+":tls", sink.String())
+	require.NoError(t, sink.Connect())
+	defer sink.Close()
+	recordIPv6 := newTestFlowRecord()
+	connIPv6 := *recordIPv6.Conn
+	connIPv6.TupleOrig.SourceAddress = net.ParseIP("fd00:10:10::1")
+	connIPv6.TupleOrig.DestinationAddress = net.ParseIP("fd00:10:96::10")
+	connIPv6.TupleReply.SourceAddress = net.ParseIP("fd00:10:10:1::2")
+	connIPv6.TupleReply.DestinationAddress = net.ParseIP("fd00:10:10::1")
+	recordIPv6.Conn = &connIPv6
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord(), recordIPv6}))
+	// The template sets of IPv4 and IPv6 are followed by the data sets.
+	for _, expectedSetID := range []uint16{2, 2, sink.templateIDv4, sink.templateIDv6} {
+		select {
+		case setID := <-setIDs:
+			assert.Equal(t, expectedSetID, setID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout when waiting for IPFIX message")
+		}
+	}
+}
+
+func TestFlowExporter_exportFlowRecords(t *testing.T) {
+	denyConnStore := connections.NewDenyConnectionStore(interfacestore.NewInterfaceStore(), nil)
+	record := newTestFlowRecord()
+	denyConnStore.AddOrUpdateConn(record.Conn, time.Now(), 60)
+	flowExp := NewFlowExporter(flowrecords.NewFlowRecords(nil), denyConnStore, testFlowExportFrequency)
+	sink1, sink2 := &fakeSink{}, &fakeSink{}
+	flowExp.AddSink(sink1, DefaultSinkBufferSize)
+	flowExp.AddSink(sink2, 1)
+
+	require.NoError(t, flowExp.exportFlowRecords())
+	var batches [][]flowexporter.FlowRecord
+	for _, bs := range flowExp.sinks {
+		batch := bs.fillBatch(nil)
+		require.Len(t, batch, 1)
+		assert.True(t, batch[0].Conn.IsDenied)
+		batches = append(batches, batch)
+	}
+	// The buffered flow records are not updated with the denied connection.
+	denyConnStore.AddOrUpdateConn(record.Conn, time.Now(), 60)
+	assert.Equal(t, uint64(2), record.Conn.OriginalPackets)
+	for _, batch := range batches {
+		assert.Equal(t, uint64(1), batch[0].Conn.OriginalPackets)
+	}
+}
+
+func TestFlowExporter_exportEndedFlowRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+
+	conn := newTestFlowRecord().Conn
+	conn.DoExport = true
+	connKey := flowexporter.NewConnectionKey(conn)
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	mockConnDumper.EXPECT().GetMaxConnections().Return(300000, nil).AnyTimes()
+	connStore := connections.NewConnectionStore(mockConnDumper, interfacestore.NewInterfaceStore(), nil, nil, nil, 0)
+	mockConnDumper.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return([]*flowexporter.Connection{conn}, 1, nil)
+	_, err := connStore.Poll()
+	require.NoError(t, err)
+	// The connection is no longer in conntrack.
+	mockConnDumper.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return(nil, 0, nil)
+	_, err = connStore.Poll()
+	require.NoError(t, err)
+
+	flowExp := NewFlowExporter(flowrecords.NewFlowRecords(connStore), nil, testFlowExportFrequency)
+	flowExp.AddSink(&fakeSink{}, DefaultSinkBufferSize)
+	require.NoError(t, flowExp.exportEndedFlowRecord(connKey))
+	batch := flowExp.sinks[0].fillBatch(nil)
+	require.Len(t, batch, 1)
+	assert.Equal(t, EndOfFlowReason, flowEndReason(batch[0].Conn))
+	assert.Equal(t, conn.OriginalPackets, batch[0].Conn.OriginalPackets)
+	// The connection is deleted after its final flow record is exported.
+	_, exists := connStore.GetConnByKey(connKey)
+	assert.False(t, exists)
+	require.NoError(t, flowExp.exportEndedFlowRecord(connKey))
+	assert.Empty(t, flowExp.sinks[0].fillBatch(nil))
+}
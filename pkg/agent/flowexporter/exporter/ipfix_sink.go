@@ -0,0 +1,288 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
+)
+
+var _ flowexporter.Sink = new(ipfixSink)
+
+// ipfixSink exports the flow records to an IPFIX collector over UDP, TCP or
+// TLS.
+type ipfixSink struct {
+	collector      net.Addr
+	tlsConfig      *tls.Config
+	process        ipfix.IPFIXExportingProcess
+	elementsListv4 []*ipfixentities.InfoElement
+	elementsListv6 []*ipfixentities.InfoElement
+	templateIDv4   uint16
+	templateIDv6   uint16
+	registry       ipfix.IPFIXRegistry
+}
+
+// NewIPFIXSink returns a Sink exporting the flow records to the IPFIX
+// collector. If tlsConfig is not nil, the flow records are sent over TLS to
+// the TCP address of the collector.
+func NewIPFIXSink(collector net.Addr, tlsConfig *tls.Config) *ipfixSink {
+	registry := ipfix.NewIPFIXRegistry()
+	registry.LoadRegistry()
+	return &ipfixSink{
+		collector: collector,
+		tlsConfig: tlsConfig,
+		registry:  registry,
+	}
+}
+
+func (s *ipfixSink) String() string {
+	network := s.collector.Network()
+	if s.tlsConfig != nil {
+		network = "tls"
+	}
+	return fmt.Sprintf("IPFIX collector %s:%s", s.collector.String(), network)
+}
+
+// Connect creates the IPFIX exporting process and sends the template records
+// of IPv4 and IPv6 connections.
+func (s *ipfixSink) Connect() error {
+	obsID, err := genObservationID()
+	if err != nil {
+		return fmt.Errorf("cannot generate obsID for IPFIX ipfixexport: %v", err)
+	}
+
+	var expProcess ipfix.IPFIXExportingProcess
+	if s.tlsConfig != nil {
+		expProcess, err = ipfix.NewIPFIXTLSExportingProcess(s.collector.String(), s.tlsConfig, obsID, sinkWriteTimeout)
+	} else if s.collector.Network() == "tcp" {
+		// TCP transport do not need any tempRefTimeout, so sending 0.
+		expProcess, err = ipfix.NewIPFIXExportingProcess(s.collector, obsID, 0)
+	} else {
+		// For UDP transport, hardcoding tempRefTimeout value as 1800s.
+		expProcess, err = ipfix.NewIPFIXExportingProcess(s.collector, obsID, 1800)
+	}
+	if err != nil {
+		return err
+	}
+	s.process = expProcess
+	s.templateIDv4 = expProcess.NewTemplateID()
+	s.templateIDv6 = expProcess.NewTemplateID()
+
+	for _, isIPv6 := range []bool{false, true} {
+		templateRec := ipfix.NewIPFIXTemplateRecord(uint16(len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements)), s.templateID(isIPv6))
+		sentBytes, err := s.sendTemplateRecord(templateRec, isIPv6)
+		if err != nil {
+			s.Close()
+			return err
+		}
+		klog.V(2).Infof("Initialized IPFIX exporting process and sent %d bytes size of template record (IPv6: %t)", sentBytes, isIPv6)
+	}
+
+	return nil
+}
+
+func (s *ipfixSink) Send(records []flowexporter.FlowRecord) error {
+	for _, record := range records {
+		isIPv6 := record.Conn.TupleOrig.SourceAddress.To4() == nil
+		dataRec := ipfix.NewIPFIXDataRecord(s.templateID(isIPv6))
+		if err := s.sendDataRecord(dataRec, record, isIPv6); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ipfixSink) Close() {
+	if s.process != nil {
+		s.process.CloseConnToCollector()
+		s.process = nil
+	}
+}
+
+func (s *ipfixSink) templateID(isIPv6 bool) uint16 {
+	if isIPv6 {
+		return s.templateIDv6
+	}
+	return s.templateIDv4
+}
+
+func (s *ipfixSink) sendTemplateRecord(templateRec ipfix.IPFIXRecord, isIPv6 bool) (int, error) {
+	// Add template header
+	_, err := templateRec.PrepareRecord()
+	if err != nil {
+		return 0, fmt.Errorf("error when writing template header: %v", err)
+	}
+
+	ianaInfoElements, antreaInfoElements := IANAInfoElements, AntreaInfoElements
+	if isIPv6 {
+		ianaInfoElements, antreaInfoElements = IANAInfoElementsIPv6, AntreaInfoElementsIPv6
+	}
+	for _, ie := range ianaInfoElements {
+		element, err := s.registry.GetInfoElement(ie, ipfixregistry.IANAEnterpriseID)
+		if err != nil {
+			return 0, fmt.Errorf("%s not present. returned error: %v", ie, err)
+		}
+		if _, err = templateRec.AddInfoElement(element, nil); err != nil {
+			return 0, fmt.Errorf("error when adding %s to template: %v", element.Name, err)
+		}
+	}
+	for _, ie := range IANAReverseInfoElements {
+		element, err := s.registry.GetInfoElement(ie, ipfixregistry.ReverseEnterpriseID)
+		if err != nil {
+			return 0, fmt.Errorf("%s not present. returned error: %v", ie, err)
+		}
+		if _, err = templateRec.AddInfoElement(element, nil); err != nil {
+			return 0, fmt.Errorf("error when adding %s to template: %v", element.Name, err)
+		}
+	}
+	for _, ie := range antreaInfoElements {
+		element, err := s.registry.GetInfoElement(ie, ipfixregistry.AntreaEnterpriseID)
+		if err != nil {
+			return 0, fmt.Errorf("information element %s is not present in Antrea registry", ie)
+		}
+		if _, err := templateRec.AddInfoElement(element, nil); err != nil {
+			return 0, fmt.Errorf("error when adding %s to template: %v", element.Name, err)
+		}
+	}
+
+	sentBytes, err := s.process.AddRecordAndSendMsg(ipfixentities.Template, templateRec.GetRecord())
+	if err != nil {
+		return 0, fmt.Errorf("error in IPFIX exporting process when sending template record: %v", err)
+	}
+
+	// Get all elements from template record.
+	if isIPv6 {
+		s.elementsListv6 = templateRec.GetTemplateElements()
+	} else {
+		s.elementsListv4 = templateRec.GetTemplateElements()
+	}
+
+	return sentBytes, nil
+}
+
+func (s *ipfixSink) sendDataRecord(dataRec ipfix.IPFIXRecord, record flowexporter.FlowRecord, isIPv6 bool) error {
+	nodeName, _ := env.GetNodeName()
+	elementsList := s.elementsListv4
+	if isIPv6 {
+		elementsList = s.elementsListv6
+	}
+	// Iterate over all infoElements in the list
+	for _, ie := range elementsList {
+		var err error
+		switch ieName := ie.Name; ieName {
+		case "flowStartSeconds":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.StartTime.Unix())
+		case "flowEndSeconds":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.StopTime.Unix())
+		case "flowEndReason":
+			_, err = dataRec.AddInfoElement(ie, flowEndReason(record.Conn))
+		case "sourceIPv4Address", "sourceIPv6Address":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.SourceAddress)
+		case "destinationIPv4Address", "destinationIPv6Address":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleReply.SourceAddress)
+		case "sourceTransportPort":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.SourcePort)
+		case "destinationTransportPort":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleReply.SourcePort)
+		case "protocolIdentifier":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.Protocol)
+		case "packetTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.OriginalPackets)
+		case "octetTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.OriginalBytes)
+		case "packetDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.Conn.OriginalPackets, record.PrevPackets))
+		case "octetDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.Conn.OriginalBytes, record.PrevBytes))
+		case "reverse_PacketTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.ReversePackets)
+		case "reverse_OctetTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.ReverseBytes)
+		case "reverse_PacketDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.Conn.ReversePackets, record.PrevReversePackets))
+		case "reverse_OctetDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.Conn.ReverseBytes, record.PrevReverseBytes))
+		case "sourcePodNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.SourcePodNamespace)
+		case "sourcePodName":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.SourcePodName)
+		case "sourceNodeName":
+			// Add nodeName for only local pods whose pod names are resolved.
+			if record.Conn.SourcePodName != "" {
+				_, err = dataRec.AddInfoElement(ie, nodeName)
+			} else {
+				_, err = dataRec.AddInfoElement(ie, "")
+			}
+		case "destinationPodNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationPodNamespace)
+		case "destinationPodName":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationPodName)
+		case "destinationNodeName":
+			// Add nodeName for only local pods whose pod names are resolved.
+			if record.Conn.DestinationPodName != "" {
+				_, err = dataRec.AddInfoElement(ie, nodeName)
+			} else {
+				_, err = dataRec.AddInfoElement(ie, "")
+			}
+		case "destinationClusterIP", "destinationClusterIPv6":
+			if record.Conn.DestinationServicePortName != "" {
+				_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.DestinationAddress)
+			} else {
+				// Sending dummy IP as IPFIX collector expects constant length of data for IP field.
+				// We should probably think of better approach as this involves customization of IPFIX collector to ignore
+				// this dummy IP address.
+				if isIPv6 {
+					_, err = dataRec.AddInfoElement(ie, net.IPv6zero)
+				} else {
+					_, err = dataRec.AddInfoElement(ie, net.IP{0, 0, 0, 0})
+				}
+			}
+		case "destinationServicePortName":
+			if record.Conn.DestinationServicePortName != "" {
+				_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationServicePortName)
+			} else {
+				_, err = dataRec.AddInfoElement(ie, "")
+			}
+		case "ingressNetworkPolicyName":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.IngressNetworkPolicyName)
+		case "ingressNetworkPolicyNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.IngressNetworkPolicyNamespace)
+		case "egressNetworkPolicyName":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.EgressNetworkPolicyName)
+		case "egressNetworkPolicyNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.EgressNetworkPolicyNamespace)
+		}
+		if err != nil {
+			return fmt.Errorf("error while adding info element: %s to data record: %v", ie.Name, err)
+		}
+	}
+
+	sentBytes, err := s.process.AddRecordAndSendMsg(ipfixentities.Data, dataRec.GetRecord())
+	if err != nil {
+		return fmt.Errorf("error in IPFIX exporting process when sending data record: %v", err)
+	}
+	klog.V(4).Infof("Flow record created and sent. Bytes sent: %d", sentBytes)
+
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
+)
+
+const (
+	// JSONFileNetwork is the network of the JSON Sink writing the flow
+	// records to a file.
+	JSONFileNetwork = "file"
+	// sinkWriteTimeout is the timeout of connecting a socket Sink, and of
+	// writing the flow records to it.
+	sinkWriteTimeout = 10 * time.Second
+	// maxJSONFileSize is the size of the file of a JSON Sink above which
+	// the file is rotated. Only one rotated file is kept, with the suffix
+	// ".1", so that the flow records don't fill up the disk of the Node.
+	maxJSONFileSize = 100 * 1024 * 1024
+)
+
+var _ flowexporter.Sink = new(jsonSink)
+
+// flowRecordJSON is the JSON document of a flow record. The fields are named
+// after the IPFIX information elements of the flow record. Unlike the IPFIX
+// flow records, IPv6 connections are exported.
+type flowRecordJSON struct {
+	FlowStartSeconds              int64  `json:"flowStartSeconds"`
+	FlowEndSeconds                int64  `json:"flowEndSeconds"`
+	FlowEndReason                 uint8  `json:"flowEndReason"`
+	SourceIPv4Address             string `json:"sourceIPv4Address,omitempty"`
+	DestinationIPv4Address        string `json:"destinationIPv4Address,omitempty"`
+	SourceIPv6Address             string `json:"sourceIPv6Address,omitempty"`
+	DestinationIPv6Address        string `json:"destinationIPv6Address,omitempty"`
+	SourceTransportPort           uint16 `json:"sourceTransportPort"`
+	DestinationTransportPort      uint16 `json:"destinationTransportPort"`
+	ProtocolIdentifier            uint8  `json:"protocolIdentifier"`
+	PacketTotalCount              uint64 `json:"packetTotalCount"`
+	OctetTotalCount               uint64 `json:"octetTotalCount"`
+	PacketDeltaCount              uint64 `json:"packetDeltaCount"`
+	OctetDeltaCount               uint64 `json:"octetDeltaCount"`
+	ReversePacketTotalCount       uint64 `json:"reversePacketTotalCount"`
+	ReverseOctetTotalCount        uint64 `json:"reverseOctetTotalCount"`
+	ReversePacketDeltaCount       uint64 `json:"reversePacketDeltaCount"`
+	ReverseOctetDeltaCount        uint64 `json:"reverseOctetDeltaCount"`
+	SourcePodName                 string `json:"sourcePodName,omitempty"`
+	SourcePodNamespace            string `json:"sourcePodNamespace,omitempty"`
+	SourceNodeName                string `json:"sourceNodeName,omitempty"`
+	DestinationPodName            string `json:"destinationPodName,omitempty"`
+	DestinationPodNamespace       string `json:"destinationPodNamespace,omitempty"`
+	DestinationNodeName           string `json:"destinationNodeName,omitempty"`
+	DestinationClusterIP          string `json:"destinationClusterIP,omitempty"`
+	DestinationServicePortName    string `json:"destinationServicePortName,omitempty"`
+	IngressNetworkPolicyName      string `json:"ingressNetworkPolicyName,omitempty"`
+	IngressNetworkPolicyNamespace string `json:"ingressNetworkPolicyNamespace,omitempty"`
+	EgressNetworkPolicyName       string `json:"egressNetworkPolicyName,omitempty"`
+	EgressNetworkPolicyNamespace  string `json:"egressNetworkPolicyNamespace,omitempty"`
+}
+
+func newFlowRecordJSON(record flowexporter.FlowRecord, nodeName string) *flowRecordJSON {
+	conn := record.Conn
+	doc := &flowRecordJSON{
+		FlowStartSeconds:              conn.StartTime.Unix(),
+		FlowEndSeconds:                conn.StopTime.Unix(),
+		FlowEndReason:                 flowEndReason(conn),
+		SourceTransportPort:           conn.TupleOrig.SourcePort,
+		DestinationTransportPort:      conn.TupleReply.SourcePort,
+		ProtocolIdentifier:            conn.TupleOrig.Protocol,
+		PacketTotalCount:              conn.OriginalPackets,
+		OctetTotalCount:               conn.OriginalBytes,
+		PacketDeltaCount:              deltaCount(conn.OriginalPackets, record.PrevPackets),
+		OctetDeltaCount:               deltaCount(conn.OriginalBytes, record.PrevBytes),
+		ReversePacketTotalCount:       conn.ReversePackets,
+		ReverseOctetTotalCount:        conn.ReverseBytes,
+		ReversePacketDeltaCount:       deltaCount(conn.ReversePackets, record.PrevReversePackets),
+		ReverseOctetDeltaCount:        deltaCount(conn.ReverseBytes, record.PrevReverseBytes),
+		SourcePodName:                 conn.SourcePodName,
+		SourcePodNamespace:            conn.SourcePodNamespace,
+		DestinationPodName:            conn.DestinationPodName,
+		DestinationPodNamespace:       conn.DestinationPodNamespace,
+		DestinationServicePortName:    conn.DestinationServicePortName,
+		IngressNetworkPolicyName:      conn.IngressNetworkPolicyName,
+		IngressNetworkPolicyNamespace: conn.IngressNetworkPolicyNamespace,
+		EgressNetworkPolicyName:       conn.EgressNetworkPolicyName,
+		EgressNetworkPolicyNamespace:  conn.EgressNetworkPolicyNamespace,
+	}
+	// The destination is the Endpoint after Service DNAT.
+	if conn.TupleOrig.SourceAddress.To4() != nil {
+		doc.SourceIPv4Address = conn.TupleOrig.SourceAddress.String()
+		doc.DestinationIPv4Address = conn.TupleReply.SourceAddress.String()
+	} else {
+		doc.SourceIPv6Address = conn.TupleOrig.SourceAddress.String()
+		doc.DestinationIPv6Address = conn.TupleReply.SourceAddress.String()
+	}
+	// The Node name is only added for local Pods whose Pod names are resolved.
+	if conn.SourcePodName != "" {
+		doc.SourceNodeName = nodeName
+	}
+	if conn.DestinationPodName != "" {
+		doc.DestinationNodeName = nodeName
+	}
+	if conn.DestinationServicePortName != "" {
+		doc.DestinationClusterIP = conn.TupleOrig.DestinationAddress.String()
+	}
+	return doc
+}
+
+// jsonSink writes the flow records as newline-delimited JSON documents to a
+// file or to a socket. Over UDP, each flow record is sent in its own datagram.
+type jsonSink struct {
+	network     string
+	address     string
+	nodeName    string
+	writer      io.WriteCloser
+	maxFileSize int64
+	// fileSize is the size of the file when writing to a file.
+	fileSize int64
+}
+
+// NewJSONSink returns a Sink writing the flow records to the file at address
+// if network is JSONFileNetwork, otherwise to the socket at address, where
+// network is "tcp", "udp" or "unix".
+func NewJSONSink(network, address string) *jsonSink {
+	nodeName, _ := env.GetNodeName()
+	return &jsonSink{
+		network:     network,
+		address:     address,
+		nodeName:    nodeName,
+		maxFileSize: maxJSONFileSize,
+	}
+}
+
+func (s *jsonSink) String() string {
+	return fmt.Sprintf("JSON %s %s", s.network, s.address)
+}
+
+func (s *jsonSink) Connect() error {
+	if s.network == JSONFileNetwork {
+		return s.openFile()
+	}
+	var err error
+	s.writer, err = net.DialTimeout(s.network, s.address, sinkWriteTimeout)
+	return err
+}
+
+func (s *jsonSink) openFile() error {
+	file, err := os.OpenFile(s.address, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.writer = file
+	s.fileSize = info.Size()
+	return nil
+}
+
+// rotateFile renames the file with the suffix ".1", replacing the previously
+// rotated file, and opens a new file.
+func (s *jsonSink) rotateFile() error {
+	s.writer.Close()
+	s.writer = nil
+	if err := os.Rename(s.address, s.address+".1"); err != nil {
+		return fmt.Errorf("error when rotating flow records file: %v", err)
+	}
+	return s.openFile()
+}
+
+func (s *jsonSink) Send(records []flowexporter.FlowRecord) error {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, record := range records {
+		if err := encoder.Encode(newFlowRecordJSON(record, s.nodeName)); err != nil {
+			return fmt.Errorf("error when encoding flow record: %v", err)
+		}
+		if s.network == "udp" {
+			if err := s.write(buffer.Bytes()); err != nil {
+				return err
+			}
+			buffer.Reset()
+		}
+	}
+	if buffer.Len() == 0 {
+		return nil
+	}
+	return s.write(buffer.Bytes())
+}
+
+func (s *jsonSink) write(data []byte) error {
+	if s.network == JSONFileNetwork && s.fileSize > 0 && s.fileSize+int64(len(data)) > s.maxFileSize {
+		if err := s.rotateFile(); err != nil {
+			return err
+		}
+	}
+	if conn, ok := s.writer.(net.Conn); ok {
+		conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	}
+	n, err := s.writer.Write(data)
+	s.fileSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("error when writing flow records: %v", err)
+	}
+	return nil
+}
+
+func (s *jsonSink) Close() {
+	if s.writer != nil {
+		s.writer.Close()
+		s.writer = nil
+	}
+}
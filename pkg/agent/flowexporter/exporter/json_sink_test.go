@@ -0,0 +1,195 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+func newTestFlowRecord() flowexporter.FlowRecord {
+	return flowexporter.FlowRecord{
+		Conn: &flowexporter.Connection{
+			StartTime: time.Unix(1600000000, 0),
+			StopTime:  time.Unix(1600000060, 0),
+			IsActive:  true,
+			TupleOrig: flowexporter.Tuple{
+				SourceAddress:      net.ParseIP("10.10.0.1"),
+				DestinationAddress: net.ParseIP("10.96.0.10"),
+				Protocol:           6,
+				SourcePort:         35402,
+				DestinationPort:    80,
+			},
+			TupleReply: flowexporter.Tuple{
+				SourceAddress:      net.ParseIP("10.10.1.2"),
+				DestinationAddress: net.ParseIP("10.10.0.1"),
+				Protocol:           6,
+				SourcePort:         8080,
+				DestinationPort:    35402,
+			},
+			OriginalPackets:              100,
+			OriginalBytes:                10000,
+			ReversePackets:               80,
+			ReverseBytes:                 200000,
+			SourcePodName:                "client",
+			SourcePodNamespace:           "default",
+			DestinationServicePortName:   "default/web:http",
+			EgressNetworkPolicyName:      "allow-web",
+			EgressNetworkPolicyNamespace: "default",
+		},
+		PrevPackets:        90,
+		PrevBytes:          9000,
+		PrevReversePackets: 70,
+		PrevReverseBytes:   150000,
+	}
+}
+
+func TestNewFlowRecordJSON(t *testing.T) {
+	expected := &flowRecordJSON{
+		FlowStartSeconds:             1600000000,
+		FlowEndSeconds:               1600000060,
+		FlowEndReason:                ActiveTimeoutReason,
+		SourceIPv4Address:            "10.10.0.1",
+		DestinationIPv4Address:       "10.10.1.2",
+		SourceTransportPort:          35402,
+		DestinationTransportPort:     8080,
+		ProtocolIdentifier:           6,
+		PacketTotalCount:             100,
+		OctetTotalCount:              10000,
+		PacketDeltaCount:             10,
+		OctetDeltaCount:              1000,
+		ReversePacketTotalCount:      80,
+		ReverseOctetTotalCount:       200000,
+		ReversePacketDeltaCount:      10,
+		ReverseOctetDeltaCount:       50000,
+		SourcePodName:                "client",
+		SourcePodNamespace:           "default",
+		SourceNodeName:               "node1",
+		DestinationClusterIP:         "10.96.0.10",
+		DestinationServicePortName:   "default/web:http",
+		EgressNetworkPolicyName:      "allow-web",
+		EgressNetworkPolicyNamespace: "default",
+	}
+	assert.Equal(t, expected, newFlowRecordJSON(newTestFlowRecord(), "node1"))
+
+	record := newTestFlowRecord()
+	record.Conn.TupleOrig.SourceAddress = net.ParseIP("fd00:10:10::1")
+	record.Conn.TupleReply.SourceAddress = net.ParseIP("fd00:10:10::2")
+	doc := newFlowRecordJSON(record, "node1")
+	assert.Equal(t, "fd00:10:10::1", doc.SourceIPv6Address)
+	assert.Equal(t, "fd00:10:10::2", doc.DestinationIPv6Address)
+	assert.Empty(t, doc.SourceIPv4Address)
+}
+
+func TestJSONSinkFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flows.json")
+
+	sink := NewJSONSink(JSONFileNetwork, path)
+	require.NoError(t, sink.Connect())
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord(), newTestFlowRecord()}))
+	sink.Close()
+	// The flow records are appended to the file after reconnecting.
+	require.NoError(t, sink.Connect())
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord()}))
+	sink.Close()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for scanner.Scan() {
+		var doc flowRecordJSON
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &doc))
+		assert.Equal(t, "10.10.0.1", doc.SourceIPv4Address)
+		assert.Equal(t, uint64(100), doc.PacketTotalCount)
+		lines++
+	}
+	assert.Equal(t, 3, lines)
+}
+
+func TestJSONSinkFileRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flows.json")
+
+	sink := NewJSONSink(JSONFileNetwork, path)
+	require.NoError(t, sink.Connect())
+	defer sink.Close()
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord()}))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	recordSize := info.Size()
+	// The file is rotated when the next flow record doesn't fit.
+	sink.maxFileSize = 2*recordSize + 1
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord()}))
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord()}))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, recordSize, info.Size())
+	info, err = os.Stat(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, 2*recordSize, info.Size())
+}
+
+func TestJSONSinkTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	docs := make(chan *flowRecordJSON, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		decoder := json.NewDecoder(conn)
+		for {
+			doc := new(flowRecordJSON)
+			if err := decoder.Decode(doc); err != nil {
+				return
+			}
+			docs <- doc
+		}
+	}()
+
+	sink := NewJSONSink("tcp", listener.Addr().String())
+	require.NoError(t, sink.Connect())
+	defer sink.Close()
+	require.NoError(t, sink.Send([]flowexporter.FlowRecord{newTestFlowRecord(), newTestFlowRecord()}))
+	for i := 0; i < 2; i++ {
+		select {
+		case doc := <-docs:
+			assert.Equal(t, "default/web:http", doc.DestinationServicePortName)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout when waiting for JSON flow record")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
+)
+
+const (
+	kafkaClientID = "antrea-agent"
+	// kafkaMaxAttempts is the number of attempts of the Kafka writer to
+	// produce a batch, before the error is returned and the Sink is
+	// reconnected after a backoff.
+	kafkaMaxAttempts = 3
+	// kafkaBatchTimeout is how long the Kafka writer waits for more records
+	// before producing a batch. The flow records are already batched by the
+	// bufferedSink, so it's kept short.
+	kafkaBatchTimeout = 10 * time.Millisecond
+)
+
+var _ flowexporter.Sink = new(kafkaSink)
+
+// kafkaSink produces the flow records as JSON documents to a Kafka topic. The
+// records are keyed by the Node name, so that the flow records of a Node are
+// all produced to the same partition of the topic.
+type kafkaSink struct {
+	brokers  []string
+	topic    string
+	nodeName string
+	dialer   *kafka.Dialer
+	writer   *kafka.Writer
+}
+
+// NewKafkaSink returns a Sink producing the flow records to the topic. The
+// brokers are the bootstrap brokers used to find the leaders of the
+// partitions, in the format of <host>:<port>. If tlsConfig is not nil, the
+// connections to the brokers use TLS.
+func NewKafkaSink(brokers []string, topic string, tlsConfig *tls.Config) *kafkaSink {
+	nodeName, _ := env.GetNodeName()
+	return &kafkaSink{
+		brokers:  brokers,
+		topic:    topic,
+		nodeName: nodeName,
+		dialer: &kafka.Dialer{
+			ClientID:  kafkaClientID,
+			Timeout:   sinkWriteTimeout,
+			DualStack: true,
+			TLS:       tlsConfig,
+		},
+	}
+}
+
+func (s *kafkaSink) String() string {
+	return fmt.Sprintf("Kafka topic %s on %s", s.topic, strings.Join(s.brokers, ","))
+}
+
+// Connect checks that the topic can be found with one of the bootstrap
+// brokers, and creates the Kafka writer. The writer connects to the leaders of
+// the partitions lazily.
+func (s *kafkaSink) Connect() error {
+	var lastErr error
+	for _, broker := range s.brokers {
+		ctx, cancel := context.WithTimeout(context.Background(), sinkWriteTimeout)
+		partitions, err := s.dialer.LookupPartitions(ctx, "tcp", broker, s.topic)
+		cancel()
+		if err == nil && len(partitions) == 0 {
+			err = fmt.Errorf("topic %s has no partition", s.topic)
+		}
+		if err != nil {
+			klog.V(2).Infof("Failed to look up partitions of Kafka topic %s with broker %s: %v", s.topic, broker, err)
+			lastErr = err
+			continue
+		}
+		s.writer = kafka.NewWriter(kafka.WriterConfig{
+			Brokers:      s.brokers,
+			Topic:        s.topic,
+			Dialer:       s.dialer,
+			Balancer:     &kafka.Hash{},
+			MaxAttempts:  kafkaMaxAttempts,
+			BatchSize:    maxSinkBatchSize,
+			BatchTimeout: kafkaBatchTimeout,
+			ReadTimeout:  sinkWriteTimeout,
+			WriteTimeout: sinkWriteTimeout,
+			RequiredAcks: 1,
+		})
+		return nil
+	}
+	return fmt.Errorf("failed to connect to Kafka brokers: %v", lastErr)
+}
+
+// Send produces the flow records and waits until they are acknowledged by the
+// leaders of the partitions.
+func (s *kafkaSink) Send(records []flowexporter.FlowRecord) error {
+	msgs, err := s.newMessages(records)
+	if err != nil {
+		return err
+	}
+	if err := s.writer.WriteMessages(context.Background(), msgs...); err != nil {
+		return fmt.Errorf("error when producing records: %v", err)
+	}
+	return nil
+}
+
+func (s *kafkaSink) newMessages(records []flowexporter.FlowRecord) ([]kafka.Message, error) {
+	msgs := make([]kafka.Message, 0, len(records))
+	for _, record := range records {
+		value, err := json.Marshal(newFlowRecordJSON(record, s.nodeName))
+		if err != nil {
+			return nil, fmt.Errorf("error when encoding flow record: %v", err)
+		}
+		msgs = append(msgs, kafka.Message{Key: []byte(s.nodeName), Value: value})
+	}
+	return msgs, nil
+}
+
+func (s *kafkaSink) Close() {
+	if s.writer != nil {
+		s.writer.Close()
+		s.writer = nil
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+func TestKafkaSinkMessages(t *testing.T) {
+	sink := NewKafkaSink([]string{"127.0.0.1:9092"}, "flows", nil)
+	sink.nodeName = "node1"
+	msgs, err := sink.newMessages([]flowexporter.FlowRecord{newTestFlowRecord(), newTestFlowRecord()})
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	for _, msg := range msgs {
+		// All the records of a Node are keyed by the Node name, so that
+		// they are produced to the same partition.
+		assert.Equal(t, []byte("node1"), msg.Key)
+		var doc flowRecordJSON
+		require.NoError(t, json.Unmarshal(msg.Value, &doc))
+		assert.Equal(t, newFlowRecordJSON(newTestFlowRecord(), "node1"), &doc)
+	}
+}
+
+func TestKafkaSinkBrokersUnavailable(t *testing.T) {
+	var brokers []string
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		brokers = append(brokers, listener.Addr().String())
+		listener.Close()
+	}
+	sink := NewKafkaSink(brokers, "flows", nil)
+	assert.Error(t, sink.Connect())
+	assert.Nil(t, sink.writer)
+	// Closing a Sink which is not connected is a no-op.
+	sink.Close()
+}
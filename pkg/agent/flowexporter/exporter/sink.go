@@ -0,0 +1,149 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+const (
+	// DefaultSinkBufferSize is the default number of flow records buffered
+	// for a Sink.
+	DefaultSinkBufferSize = 10000
+	// maxSinkBatchSize is the maximum number of flow records sent to a Sink
+	// at once.
+	maxSinkBatchSize = 100
+)
+
+// sinkBackoff is the backoff of reconnecting a Sink after it fails to connect
+// or to send flow records.
+var sinkBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      time.Minute,
+}
+
+// bufferedSink buffers the flow records exported to a Sink, and sends them to
+// the Sink asynchronously.
+type bufferedSink struct {
+	sink    flowexporter.Sink
+	records chan flowexporter.FlowRecord
+	backoff wait.Backoff
+}
+
+func newBufferedSink(sink flowexporter.Sink, bufferSize int) *bufferedSink {
+	return &bufferedSink{
+		sink:    sink,
+		records: make(chan flowexporter.FlowRecord, bufferSize),
+		backoff: sinkBackoff,
+	}
+}
+
+// add adds the flow records to the buffer. The flow records which don't fit
+// in the buffer are dropped, so that the memory is bounded when the Sink is
+// unavailable for a long time.
+func (s *bufferedSink) add(records []flowexporter.FlowRecord) {
+	dropped := 0
+	for _, record := range records {
+		select {
+		case s.records <- record:
+		default:
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		klog.Warningf("Buffer of flow export sink %s is full, dropped %d flow records", s.sink, dropped)
+	}
+}
+
+// run connects the Sink and sends the buffered flow records to it until stopCh
+// is closed. When the Sink fails to connect or to send flow records, it's
+// closed and connected again after a backoff, and the flow records which
+// failed to be sent are sent again.
+func (s *bufferedSink) run(stopCh <-chan struct{}) {
+	backoff := s.backoff
+	connected := false
+	defer func() {
+		if connected {
+			s.sink.Close()
+		}
+	}()
+	// waitBackoff returns false if stopCh is closed during the backoff.
+	waitBackoff := func() bool {
+		select {
+		case <-stopCh:
+			return false
+		case <-time.After(backoff.Step()):
+			return true
+		}
+	}
+	var batch []flowexporter.FlowRecord
+	for {
+		if !connected {
+			if err := s.sink.Connect(); err != nil {
+				klog.Errorf("Error when connecting flow export sink %s: %v", s.sink, err)
+				if !waitBackoff() {
+					return
+				}
+				continue
+			}
+			klog.Infof("Connected flow export sink %s", s.sink)
+			connected = true
+		}
+		if len(batch) == 0 {
+			select {
+			case <-stopCh:
+				return
+			case record := <-s.records:
+				batch = append(batch, record)
+			}
+			batch = s.fillBatch(batch)
+		}
+		if err := s.sink.Send(batch); err != nil {
+			klog.Errorf("Error when sending flow records to flow export sink %s: %v", s.sink, err)
+			s.sink.Close()
+			connected = false
+			if !waitBackoff() {
+				return
+			}
+			continue
+		}
+		klog.V(4).Infof("Sent %d flow records to flow export sink %s", len(batch), s.sink)
+		batch = batch[:0]
+		backoff = s.backoff
+	}
+}
+
+// fillBatch appends the buffered flow records to the batch without waiting,
+// until the batch is full.
+func (s *bufferedSink) fillBatch(batch []flowexporter.FlowRecord) []flowexporter.FlowRecord {
+	for len(batch) < maxSinkBatchSize {
+		select {
+		case record := <-s.records:
+			batch = append(batch, record)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
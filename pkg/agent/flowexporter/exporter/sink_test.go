@@ -0,0 +1,130 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+// fakeSink fails to connect and to send flow records the given numbers of
+// times, and records the flow records sent successfully.
+type fakeSink struct {
+	mutex        sync.Mutex
+	connectFails int
+	sendFails    int
+	connects     int
+	closes       int
+	records      []flowexporter.FlowRecord
+}
+
+func (s *fakeSink) Connect() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.connects++
+	if s.connectFails > 0 {
+		s.connectFails--
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (s *fakeSink) Send(records []flowexporter.FlowRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sendFails > 0 {
+		s.sendFails--
+		return errors.New("broken pipe")
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *fakeSink) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closes++
+}
+
+func (s *fakeSink) String() string {
+	return "fake"
+}
+
+func (s *fakeSink) sentRecords() []flowexporter.FlowRecord {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]flowexporter.FlowRecord(nil), s.records...)
+}
+
+func newTestFlowRecords(n int) []flowexporter.FlowRecord {
+	records := make([]flowexporter.FlowRecord, n)
+	for i := range records {
+		records[i] = flowexporter.FlowRecord{Conn: &flowexporter.Connection{ID: uint32(i)}}
+	}
+	return records
+}
+
+func TestBufferedSink(t *testing.T) {
+	sink := &fakeSink{connectFails: 2, sendFails: 1}
+	bs := newBufferedSink(sink, 10)
+	bs.backoff = wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 10}
+	records := newTestFlowRecords(3)
+	bs.add(records)
+
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		bs.run(stopCh)
+		close(done)
+	}()
+	err := wait.PollImmediate(time.Millisecond, 5*time.Second, func() (bool, error) {
+		return len(sink.sentRecords()) == len(records), nil
+	})
+	require.NoError(t, err)
+	// The batch which failed to be sent is sent again after reconnecting.
+	assert.Equal(t, records, sink.sentRecords())
+
+	// The flow records added later are sent with the same connection.
+	moreRecords := newTestFlowRecords(2)
+	bs.add(moreRecords)
+	err = wait.PollImmediate(time.Millisecond, 5*time.Second, func() (bool, error) {
+		return len(sink.sentRecords()) == len(records)+len(moreRecords), nil
+	})
+	require.NoError(t, err)
+	close(stopCh)
+	<-done
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	assert.Equal(t, 4, sink.connects)
+	// The Sink is closed after failing to send, and when stopping.
+	assert.Equal(t, 2, sink.closes)
+}
+
+func TestBufferedSinkFull(t *testing.T) {
+	bs := newBufferedSink(&fakeSink{}, 2)
+	bs.add(newTestFlowRecords(3))
+	assert.Len(t, bs.records, 2)
+	batch := bs.fillBatch(nil)
+	assert.Len(t, batch, 2)
+	assert.Empty(t, bs.records)
+}
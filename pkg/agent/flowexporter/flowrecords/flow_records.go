@@ -42,6 +42,18 @@ func (fr *FlowRecords) BuildFlowRecords() error {
 	return nil
 }
 
+// BuildFlowRecordByConnKey builds the flow record of the connection given the connection key. It returns false if the
+// connection is not in the connection store.
+func (fr *FlowRecords) BuildFlowRecordByConnKey(connKey flowexporter.ConnectionKey) (*flowexporter.FlowRecord, bool) {
+	conn, exists := fr.connStore.GetConnByKey(connKey)
+	if !exists {
+		return nil, false
+	}
+	fr.addOrUpdateFlowRecord(connKey, *conn)
+	record, exists := fr.recordsMap[connKey]
+	return &record, exists
+}
+
 // GetFlowRecordByConnKey gets the record from the flow record map given the connection key
 func (fr *FlowRecords) GetFlowRecordByConnKey(connKey flowexporter.ConnectionKey) (*flowexporter.FlowRecord, bool) {
 	record, found := fr.recordsMap[connKey]
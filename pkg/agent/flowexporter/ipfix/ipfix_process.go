@@ -15,14 +15,28 @@
 package ipfix
 
 import (
+	"crypto/tls"
+	"encoding/binary"
 	"fmt"
 	"net"
+	"time"
 
 	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
 	ipfixexport "github.com/vmware/go-ipfix/pkg/exporter"
 )
 
 var _ IPFIXExportingProcess = new(ipfixExportingProcess)
+var _ IPFIXExportingProcess = new(ipfixTLSExportingProcess)
+
+const (
+	// ipfixVersion is the version number of IPFIX in the message header.
+	ipfixVersion = 10
+	// ipfixStartTemplateID is the ID of the first template. The IDs below
+	// 256 are reserved for the sets which are not data sets.
+	ipfixStartTemplateID uint16 = 255
+	// ipfixTemplateSetID is the ID of the template sets.
+	ipfixTemplateSetID uint16 = 2
+)
 
 // IPFIXExportingProcess interface is added to facilitate unit testing without involving the code from go-ipfix library.
 type IPFIXExportingProcess interface {
@@ -59,3 +73,73 @@ func (exp *ipfixExportingProcess) CloseConnToCollector() {
 func (exp *ipfixExportingProcess) NewTemplateID() uint16 {
 	return exp.ExportingProcess.NewTemplateID()
 }
+
+// ipfixTLSExportingProcess sends IPFIX messages to a collector over TLS, see
+// https://tools.ietf.org/html/rfc7011#section-10.4.1. The exporting process of
+// go-ipfix only supports plain TCP and UDP connections. As for TCP, templates
+// need not be refreshed periodically over TLS.
+type ipfixTLSExportingProcess struct {
+	conn         net.Conn
+	writeTimeout time.Duration
+	obsDomainID  uint32
+	seqNumber    uint32
+	templateID   uint16
+}
+
+// NewIPFIXTLSExportingProcess connects to the collector over TLS. The timeout
+// bounds both the connection, including the TLS handshake, and each message
+// sent to the collector.
+func NewIPFIXTLSExportingProcess(collector string, tlsConfig *tls.Config, obsID uint32, timeout time.Duration) (*ipfixTLSExportingProcess, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", collector, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error while initializing IPFIX exporting process over TLS: %v", err)
+	}
+	return &ipfixTLSExportingProcess{
+		conn:         conn,
+		writeTimeout: timeout,
+		obsDomainID:  obsID,
+		templateID:   ipfixStartTemplateID,
+	}, nil
+}
+
+// AddRecordAndSendMsg sends a message which only has the set of the record.
+func (exp *ipfixTLSExportingProcess) AddRecordAndSendMsg(setType ipfixentities.ContentType, record ipfixentities.Record) (int, error) {
+	recBytes := record.GetBuffer().Bytes()
+	// The message header takes 16 bytes and the set header 4 bytes.
+	msgLen := 16 + 4 + len(recBytes)
+	if msgLen > int(ipfixentities.MaxTcpSocketMsgSize) {
+		return 0, fmt.Errorf("IPFIX message of %d bytes exceeds the maximum size", msgLen)
+	}
+	msg := make([]byte, msgLen)
+	binary.BigEndian.PutUint16(msg[0:2], ipfixVersion)
+	binary.BigEndian.PutUint16(msg[2:4], uint16(msgLen))
+	binary.BigEndian.PutUint32(msg[4:8], uint32(time.Now().Unix()))
+	binary.BigEndian.PutUint32(msg[8:12], exp.seqNumber)
+	binary.BigEndian.PutUint32(msg[12:16], exp.obsDomainID)
+	if setType == ipfixentities.Template {
+		binary.BigEndian.PutUint16(msg[16:18], ipfixTemplateSetID)
+	} else {
+		binary.BigEndian.PutUint16(msg[16:18], record.GetTemplateID())
+	}
+	binary.BigEndian.PutUint16(msg[18:20], uint16(4+len(recBytes)))
+	copy(msg[20:], recBytes)
+	exp.conn.SetWriteDeadline(time.Now().Add(exp.writeTimeout))
+	sentBytes, err := exp.conn.Write(msg)
+	if err != nil {
+		return sentBytes, fmt.Errorf("error when sending message to collector over TLS: %v", err)
+	}
+	// The sequence number is the total number of data records sent.
+	if setType == ipfixentities.Data {
+		exp.seqNumber++
+	}
+	return sentBytes, nil
+}
+
+func (exp *ipfixTLSExportingProcess) CloseConnToCollector() {
+	exp.conn.Close()
+}
+
+func (exp *ipfixTLSExportingProcess) NewTemplateID() uint16 {
+	exp.templateID++
+	return exp.templateID
+}
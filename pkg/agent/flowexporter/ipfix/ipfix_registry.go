@@ -21,6 +21,16 @@ import (
 
 var _ IPFIXRegistry = new(ipfixRegistry)
 
+// antreaInfoElements are the Antrea information elements which are assigned in
+// the Antrea registry but not loaded by the current version of go-ipfix yet.
+var antreaInfoElements = []*ipfixentities.InfoElement{
+	ipfixentities.NewInfoElement("ingressNetworkPolicyName", 109, ipfixentities.String, ipfixregistry.AntreaEnterpriseID, ipfixentities.VariableLength),
+	ipfixentities.NewInfoElement("ingressNetworkPolicyNamespace", 110, ipfixentities.String, ipfixregistry.AntreaEnterpriseID, ipfixentities.VariableLength),
+	ipfixentities.NewInfoElement("egressNetworkPolicyName", 111, ipfixentities.String, ipfixregistry.AntreaEnterpriseID, ipfixentities.VariableLength),
+	ipfixentities.NewInfoElement("egressNetworkPolicyNamespace", 112, ipfixentities.String, ipfixregistry.AntreaEnterpriseID, ipfixentities.VariableLength),
+	ipfixentities.NewInfoElement("destinationClusterIPv6", 135, ipfixentities.Ipv6Address, ipfixregistry.AntreaEnterpriseID, 16),
+}
+
 // IPFIXRegistry interface is added to facilitate unit testing without involving the code from go-ipfix library.
 type IPFIXRegistry interface {
 	LoadRegistry()
@@ -38,5 +48,27 @@ func (reg *ipfixRegistry) LoadRegistry() {
 }
 
 func (reg *ipfixRegistry) GetInfoElement(name string, enterpriseID uint32) (*ipfixentities.InfoElement, error) {
-	return ipfixregistry.GetInfoElement(name, enterpriseID)
+	element, err := ipfixregistry.GetInfoElement(name, enterpriseID)
+	if err != nil && enterpriseID == ipfixregistry.AntreaEnterpriseID {
+		for _, ie := range antreaInfoElements {
+			if ie.Name == name {
+				return ie, nil
+			}
+		}
+	}
+	return element, err
+}
+
+// GetInfoElementFromID returns the information element of the given ID from the
+// registry, including the Antrea information elements missing in go-ipfix.
+func GetInfoElementFromID(elementID uint16, enterpriseID uint32) (*ipfixentities.InfoElement, error) {
+	element, err := ipfixregistry.GetInfoElementFromID(elementID, enterpriseID)
+	if err != nil && enterpriseID == ipfixregistry.AntreaEnterpriseID {
+		for _, ie := range antreaInfoElements {
+			if ie.ElementId == elementID {
+				return ie, nil
+			}
+		}
+	}
+	return element, err
 }
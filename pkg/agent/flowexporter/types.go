@@ -43,19 +43,28 @@ type Connection struct {
 	// IsActive flag helps in cleaning up connections when they are not in conntrack any module more.
 	IsActive bool
 	// DoExport flag helps in tagging connections that can be exported by Flow Exporter
-	DoExport   bool
+	DoExport bool
+	// IsDenied flag indicates that the connection is denied by NetworkPolicies, hence not tracked by conntrack.
+	IsDenied   bool
 	Zone       uint16
 	StatusFlag uint32
 	// TODO: Have a separate field for protocol. No need to keep it in Tuple.
 	TupleOrig, TupleReply          Tuple
 	OriginalPackets, OriginalBytes uint64
 	ReversePackets, ReverseBytes   uint64
+	// Labels is the ct_label of the connection, which stores the IDs of the
+	// NetworkPolicy rules that allowed the connection.
+	Labels []byte
 	// Fields specific to Antrea
-	SourcePodNamespace         string
-	SourcePodName              string
-	DestinationPodNamespace    string
-	DestinationPodName         string
-	DestinationServicePortName string
+	SourcePodNamespace            string
+	SourcePodName                 string
+	DestinationPodNamespace       string
+	DestinationPodName            string
+	DestinationServicePortName    string
+	IngressNetworkPolicyName      string
+	IngressNetworkPolicyNamespace string
+	EgressNetworkPolicyName       string
+	EgressNetworkPolicyNamespace  string
 }
 
 type FlowRecord struct {
@@ -65,3 +74,19 @@ type FlowRecord struct {
 	PrevReversePackets uint64
 	PrevReverseBytes   uint64
 }
+
+// Sink is a destination the flow records are exported to, e.g. an IPFIX
+// collector or a Kafka topic. The Flow Exporter buffers the flow records of
+// each Sink separately and calls Connect again, with backoff, after Send
+// fails, so a Sink need not handle reconnection by itself.
+type Sink interface {
+	// Connect establishes the connection to the destination.
+	Connect() error
+	// Send sends the flow records to the destination. It's only called after
+	// Connect succeeds.
+	Send(records []FlowRecord) error
+	// Close closes the connection to the destination.
+	Close()
+	// String returns the description of the Sink used in logs.
+	String() string
+}
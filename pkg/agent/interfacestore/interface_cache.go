@@ -37,8 +37,8 @@ const (
 	// Only container interfaces will be indexed.
 	// One Pod may get more than one interface.
 	podIndex = "pod"
-	// interfaceIPIndex is the index built with InterfaceConfig.IP
-	// Only the interfaces with IP get indexed.
+	// interfaceIPIndex is the index built with InterfaceConfig.IPs
+	// Only the interfaces with IPs get indexed, one key for each IP.
 	interfaceIPIndex = "ip"
 )
 
@@ -85,6 +85,9 @@ func getInterfaceKey(obj interface{}) (string, error) {
 	} else if interfaceConfig.Type == TunnelInterface && interfaceConfig.NodeName != "" {
 		// Tunnel interface for a Node.
 		key = util.GenerateNodeTunnelInterfaceKey(interfaceConfig.NodeName)
+	} else if interfaceConfig.Type == TunnelInterface && interfaceConfig.ClusterID != "" {
+		// Tunnel interface for a remote cluster.
+		key = util.GenerateClusterTunnelInterfaceKey(interfaceConfig.ClusterID)
 	} else {
 		// Use the interface name as the key by default.
 		key = interfaceConfig.InterfaceName
@@ -217,6 +220,19 @@ func (c *interfaceCache) GetNodeTunnelInterface(nodeName string) (*InterfaceConf
 	return obj.(*InterfaceConfig), true
 }
 
+// GetClusterTunnelInterface retrieves InterfaceConfig for the tunnel to the
+// gateway Node of the remote cluster.
+func (c *interfaceCache) GetClusterTunnelInterface(clusterID string) (*InterfaceConfig, bool) {
+	key := util.GenerateClusterTunnelInterfaceKey(clusterID)
+	c.RLock()
+	defer c.RUnlock()
+	obj, ok, _ := c.cache.GetByKey(key)
+	if !ok {
+		return nil, false
+	}
+	return obj.(*InterfaceConfig), true
+}
+
 func interfaceNameIndexFunc(obj interface{}) ([]string, error) {
 	interfaceConfig := obj.(*InterfaceConfig)
 	return []string{interfaceConfig.InterfaceName}, nil
@@ -245,11 +261,12 @@ func podIndexFunc(obj interface{}) ([]string, error) {
 
 func interfaceIPIndexFunc(obj interface{}) ([]string, error) {
 	interfaceConfig := obj.(*InterfaceConfig)
-	if interfaceConfig.IP == nil {
-		// If interfaceConfig IP is not set, we return empty key.
-		return []string{}, nil
+	keys := make([]string, 0, len(interfaceConfig.IPs))
+	// If interfaceConfig IPs are not set, we return empty keys.
+	for _, ip := range interfaceConfig.IPs {
+		keys = append(keys, ip.String())
 	}
-	return []string{interfaceConfig.IP.String()}, nil
+	return keys, nil
 }
 
 func NewInterfaceStore() InterfaceStore {
@@ -72,6 +72,21 @@ func (mr *MockInterfaceStoreMockRecorder) DeleteInterface(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInterface", reflect.TypeOf((*MockInterfaceStore)(nil).DeleteInterface), arg0)
 }
 
+// GetClusterTunnelInterface mocks base method
+func (m *MockInterfaceStore) GetClusterTunnelInterface(arg0 string) (*interfacestore.InterfaceConfig, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterTunnelInterface", arg0)
+	ret0, _ := ret[0].(*interfacestore.InterfaceConfig)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetClusterTunnelInterface indicates an expected call of GetClusterTunnelInterface
+func (mr *MockInterfaceStoreMockRecorder) GetClusterTunnelInterface(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterTunnelInterface", reflect.TypeOf((*MockInterfaceStore)(nil).GetClusterTunnelInterface), arg0)
+}
+
 // GetContainerInterface mocks base method
 func (m *MockInterfaceStore) GetContainerInterface(arg0 string) (*interfacestore.InterfaceConfig, bool) {
 	m.ctrl.T.Helper()
@@ -53,6 +53,9 @@ type TunnelInterfaceConfig struct {
 	Type ovsconfig.TunnelType
 	// Name of the remote Node.
 	NodeName string
+	// ID of the remote cluster, for a tunnel to the gateway Node of a
+	// remote cluster.
+	ClusterID string
 	// IP address of the local Node.
 	LocalIP net.IP
 	// IP address of the remote Node.
@@ -67,8 +70,9 @@ type InterfaceConfig struct {
 	Type InterfaceType
 	// Unique name of the interface, also used for the OVS port name.
 	InterfaceName string
-	IP            net.IP
-	MAC           net.HardwareAddr
+	// IPs of the interface. A dual-stack interface has both an IPv4 and an IPv6 address.
+	IPs []net.IP
+	MAC net.HardwareAddr
 	*OVSPortConfig
 	*ContainerInterfaceConfig
 	*TunnelInterfaceConfig
@@ -86,6 +90,7 @@ type InterfaceStore interface {
 	GetContainerInterfacesByPod(podName string, podNamespace string) []*InterfaceConfig
 	GetInterfaceByIP(interfaceIP string) (*InterfaceConfig, bool)
 	GetNodeTunnelInterface(nodeName string) (*InterfaceConfig, bool)
+	GetClusterTunnelInterface(clusterID string) (*InterfaceConfig, bool)
 	GetContainerInterfaceNum() int
 	GetInterfacesByType(interfaceType InterfaceType) []*InterfaceConfig
 	Len() int
@@ -99,7 +104,7 @@ func NewContainerInterface(
 	podName string,
 	podNamespace string,
 	mac net.HardwareAddr,
-	ip net.IP) *InterfaceConfig {
+	ips []net.IP) *InterfaceConfig {
 	containerConfig := &ContainerInterfaceConfig{
 		ContainerID:  containerID,
 		PodName:      podName,
@@ -107,7 +112,7 @@ func NewContainerInterface(
 	return &InterfaceConfig{
 		InterfaceName:            interfaceName,
 		Type:                     ContainerInterface,
-		IP:                       ip,
+		IPs:                      ips,
 		MAC:                      mac,
 		ContainerInterfaceConfig: containerConfig}
 }
@@ -132,6 +137,13 @@ func NewIPSecTunnelInterface(interfaceName string, tunnelType ovsconfig.TunnelTy
 	return &InterfaceConfig{InterfaceName: interfaceName, Type: TunnelInterface, TunnelInterfaceConfig: tunnelConfig}
 }
 
+// NewClusterTunnelInterface creates InterfaceConfig for the tunnel to the
+// gateway Node of a remote cluster.
+func NewClusterTunnelInterface(interfaceName string, tunnelType ovsconfig.TunnelType, clusterID string, remoteIP net.IP) *InterfaceConfig {
+	tunnelConfig := &TunnelInterfaceConfig{Type: tunnelType, ClusterID: clusterID, RemoteIP: remoteIP}
+	return &InterfaceConfig{InterfaceName: interfaceName, Type: TunnelInterface, TunnelInterfaceConfig: tunnelConfig}
+}
+
 // NewUplinkInterface creates InterfaceConfig for the uplink interface.
 func NewUplinkInterface(uplinkName string) *InterfaceConfig {
 	uplinkConfig := &InterfaceConfig{InterfaceName: uplinkName, Type: UplinkInterface}
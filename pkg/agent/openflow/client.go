@@ -15,10 +15,12 @@
 package openflow
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"net"
 
+	"github.com/contiv/libOpenflow/openflow13"
 	"github.com/contiv/ofnet/ofctrl"
 	"k8s.io/klog"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
 	"github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
@@ -42,7 +45,9 @@ type Client interface {
 	Initialize(roundInfo types.RoundInfo, config *config.NodeConfig, encapMode config.TrafficEncapModeType, gatewayOFPort uint32) (<-chan struct{}, error)
 
 	// InstallGatewayFlows sets up flows related to an OVS gateway port, the gateway must exist.
-	InstallGatewayFlows(gatewayAddr net.IP, gatewayMAC net.HardwareAddr, gatewayOFPort uint32) error
+	// gatewayAddrs contains the IPv4 address of the gateway, and also the IPv6 address if the
+	// Node is dual-stack.
+	InstallGatewayFlows(gatewayAddrs []net.IP, gatewayMAC net.HardwareAddr, gatewayOFPort uint32) error
 
 	// InstallBridgeUplinkFlows installs Openflow flows between bridge local port and uplink port to support
 	// host networking. These flows are only needed on windows platform.
@@ -57,11 +62,18 @@ type Client interface {
 	// the different Services running in the Cluster. This method needs to be invoked once.
 	InstallClusterServiceFlows() error
 
+	// InstallGatewayServiceFlows sets up the appropriate flows so that Service traffic which is
+	// sent to the switch from the host gateway, i.e. NodePort traffic and ClusterIP traffic from
+	// the host network, can be load-balanced by the Service tables. This method needs to be
+	// invoked once after InstallClusterServiceFlows.
+	InstallGatewayServiceFlows() error
+
 	// InstallDefaultTunnelFlows sets up the classification flow for the default (flow based) tunnel.
 	InstallDefaultTunnelFlows(tunnelOFPort uint32) error
 
 	// InstallNodeFlows should be invoked when a connection to a remote Node is going to be set
-	// up. The hostname is used to identify the added flows. When IPSec tunnel is enabled,
+	// up. The hostname is used to identify the added flows. peerConfigs maps each Pod CIDR of
+	// the remote Node to the IP of the remote gateway in that CIDR. When IPSec tunnel is enabled,
 	// ipsecTunOFPort must be set to the OFPort number of the IPSec tunnel port to the remote Node;
 	// otherwise ipsecTunOFPort must be set to 0.
 	// InstallNodeFlows has all-or-nothing semantics(call succeeds if all the flows are installed
@@ -71,31 +83,61 @@ type Client interface {
 	InstallNodeFlows(
 		hostname string,
 		localGatewayMAC net.HardwareAddr,
-		peerPodCIDR net.IPNet,
-		peerGatewayIP, tunnelPeerIP net.IP,
+		peerConfigs map[*net.IPNet]net.IP,
+		tunnelPeerIP net.IP,
 		tunOFPort, ipsecTunOFPort uint32) error
 
 	// UninstallNodeFlows removes the connection to the remote Node specified with the
 	// hostname. UninstallNodeFlows will do nothing if no connection to the host was established.
 	UninstallNodeFlows(hostname string) error
 
+	// InstallMulticlusterGatewayFlows should be invoked on the gateway Node of the cluster when a
+	// connection to the remote cluster identified by clusterID is going to be set up. The traffic
+	// to peerPodCIDRs is output to tunOFPort, which must be the OFPort number of the tunnel port
+	// to the gateway Node of the remote cluster, and the traffic received from tunOFPort is
+	// classified as tunnel traffic. Calls to InstallMulticlusterGatewayFlows are idempotent.
+	InstallMulticlusterGatewayFlows(
+		clusterID string,
+		localGatewayMAC net.HardwareAddr,
+		peerPodCIDRs []*net.IPNet,
+		tunOFPort uint32) error
+
+	// InstallMulticlusterNodeFlows should be invoked on the other Nodes of the cluster when a
+	// connection to the remote cluster identified by clusterID is going to be set up. The
+	// traffic to peerPodCIDRs is sent over the tunnel to gatewayNodeIP, which must be the IP of
+	// the gateway Node of the local cluster. Calls to InstallMulticlusterNodeFlows are idempotent.
+	InstallMulticlusterNodeFlows(
+		clusterID string,
+		localGatewayMAC net.HardwareAddr,
+		peerPodCIDRs []*net.IPNet,
+		gatewayNodeIP net.IP,
+		tunOFPort uint32) error
+
+	// UninstallMulticlusterFlows removes the connection to the remote cluster specified with
+	// the clusterID, which is installed by either InstallMulticlusterGatewayFlows or
+	// InstallMulticlusterNodeFlows.
+	UninstallMulticlusterFlows(clusterID string) error
+
 	// InstallPodFlows should be invoked when a connection to a Pod on current Node. The
 	// interfaceName is used to identify the added flows. InstallPodFlows has all-or-nothing
 	// semantics(call succeeds if all the flows are installed successfully, otherwise no
 	// flows will be installed). Calls to InstallPodFlows are idempotent. Concurrent calls
 	// to InstallPodFlows and / or UninstallPodFlows are supported as long as they are all
-	// for different interfaceNames.
-	InstallPodFlows(interfaceName string, podInterfaceIP net.IP, podInterfaceMAC, gatewayMAC net.HardwareAddr, ofPort uint32) error
+	// for different interfaceNames. podInterfaceIPs contains all the IPs of the Pod interface.
+	InstallPodFlows(interfaceName string, podInterfaceIPs []net.IP, podInterfaceMAC, gatewayMAC net.HardwareAddr, ofPort uint32) error
 
 	// UninstallPodFlows removes the connection to the local Pod specified with the
 	// interfaceName. UninstallPodFlows will do nothing if no connection to the Pod was established.
 	UninstallPodFlows(interfaceName string) error
 
-	// InstallServiceGroup installs a group for Service LB. Each endpoint
-	// is a bucket of the group. For now, each bucket has the same weight.
-	InstallServiceGroup(groupID binding.GroupIDType, withSessionAffinity bool, endpoints []proxy.Endpoint) error
-	// UninstallServiceGroup removes the group and its buckets that are
-	// installed by InstallServiceGroup.
+	// InstallServiceGroup installs a group for Service LB, which selects the
+	// endpoints with the lbAlgorithm. For the random algorithm, each endpoint
+	// is a bucket of the group, whose weight is the one of the endpoint IP in
+	// weights, or 100 if absent. For the hash based algorithms, the flows which
+	// map the hash values to the endpoints are also installed.
+	InstallServiceGroup(groupID binding.GroupIDType, withSessionAffinity bool, lbAlgorithm types.LBAlgorithm, weights map[string]uint16, endpoints []proxy.Endpoint) error
+	// UninstallServiceGroup removes the group and its buckets, and the flows
+	// that are installed by InstallServiceGroup.
 	UninstallServiceGroup(groupID binding.GroupIDType) error
 
 	// InstallEndpointFlows installs flows for accessing Endpoints.
@@ -120,6 +162,17 @@ type Client interface {
 	// kube-proxy will handle the traffic.
 	// This function is only used for Windows platform.
 	InstallLoadBalancerServiceFromOutsideFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
+	// InstallLoadBalancerDSRFlows sets up the flows shared by the LoadBalancer Services in DSR mode, and the TLV
+	// maps of the tun_metadata fields which carry the ingress IP and the selected Endpoint port of the DSR traffic
+	// in the Geneve tunnel. This method needs to be invoked once after InstallGatewayServiceFlows.
+	InstallLoadBalancerDSRFlows() error
+	// InstallLoadBalancerServiceDSRFlows installs flows for the traffic sent to the ingress IP of a LoadBalancer
+	// Service in DSR mode. The traffic from outside the cluster is received from the gateway and forwarded to the
+	// Node of the selected Endpoint via the tunnel without DNAT, where it's DNAT'd to the Endpoint, so that the
+	// reply traffic is sent to the client from the Endpoint Node directly.
+	InstallLoadBalancerServiceDSRFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
+	// UninstallLoadBalancerServiceDSRFlows removes flows installed by InstallLoadBalancerServiceDSRFlows.
+	UninstallLoadBalancerServiceDSRFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
 
 	// GetFlowTableStatus should return an array of flow table status, all existing flow tables should be included in the list.
 	GetFlowTableStatus() []binding.TableStatus
@@ -151,6 +204,23 @@ type Client interface {
 	// in the connection tracking context, and 3) SNAT the packets with Node IP.
 	InstallExternalFlows(nodeIP net.IP, localSubnet net.IPNet) error
 
+	// InstallPodSNATFlows installs the SNAT flows for a local Pod to which an Egress is applied. If the SNAT IP is
+	// on the local Node, snatMark must be set to the ID of the SNAT IP, and the new connections from the Pod to the
+	// external network will be marked with it; otherwise snatMark must be 0, and the traffic from the Pod to the
+	// external network will be tunnelled to the SNAT IP.
+	InstallPodSNATFlows(ofPort uint32, snatIP net.IP, snatMark uint32) error
+
+	// UninstallPodSNATFlows removes the SNAT flows for the local Pod with the specified ofPort.
+	UninstallPodSNATFlows(ofPort uint32) error
+
+	// InstallRemotePodSNATFlows installs the SNAT flows for a remote Pod to which an Egress with a SNAT IP on the
+	// local Node is applied. The new connections tunnelled from the Pod to the external network will be marked with
+	// snatMark, which must be the ID of the SNAT IP.
+	InstallRemotePodSNATFlows(podIP net.IP, snatMark uint32) error
+
+	// UninstallRemotePodSNATFlows removes the SNAT flows for the remote Pod with the specified IP.
+	UninstallRemotePodSNATFlows(podIP net.IP) error
+
 	// Disconnect disconnects the connection between client and OFSwitch.
 	Disconnect() error
 
@@ -168,7 +238,7 @@ type Client interface {
 	// the new round number.
 	DeleteStaleFlows() error
 
-	// GetTunnelVirtualMAC() returns globalVirtualMAC used for tunnel traffic.
+	// GetTunnelVirtualMAC() returns GlobalVirtualMAC used for tunnel traffic.
 	GetTunnelVirtualMAC() net.HardwareAddr
 
 	// GetPodFlowKeys returns the keys (match strings) of the cached flows for a
@@ -211,17 +281,62 @@ type Client interface {
 		inPort uint32,
 		outPort int32) error
 
+	// SendTCPPacketOut sends a TCP packet to the specified OVS port directly,
+	// bypassing the OVS pipeline. If tunnelDstIP is not nil, it's used as the
+	// tunnel destination of the packet.
+	SendTCPPacketOut(
+		srcMAC net.HardwareAddr,
+		dstMAC net.HardwareAddr,
+		srcIP net.IP,
+		dstIP net.IP,
+		outPort uint32,
+		tunnelDstIP net.IP,
+		tcpSrcPort uint16,
+		tcpDstPort uint16,
+		tcpSeqNum uint32,
+		tcpAckNum uint32,
+		tcpFlags uint8) error
+
+	// SendICMPPacketOut sends an ICMP packet to the specified OVS port
+	// directly, bypassing the OVS pipeline. If tunnelDstIP is not nil, it's
+	// used as the tunnel destination of the packet.
+	SendICMPPacketOut(
+		srcMAC net.HardwareAddr,
+		dstMAC net.HardwareAddr,
+		srcIP net.IP,
+		dstIP net.IP,
+		outPort uint32,
+		tunnelDstIP net.IP,
+		icmpType uint8,
+		icmpCode uint8,
+		icmpData []byte) error
+
 	// InstallTraceflowFlows installs flows for specific traceflow request.
 	InstallTraceflowFlows(dataplaneTag uint8) error
 
+	// InstallDNSInterceptFlows installs flows to send the DNS responses
+	// received by local Pods to the controller, which holds them until they
+	// are reinjected with ReinjectDNSResponse.
+	InstallDNSInterceptFlows() error
+
+	// UninstallDNSInterceptFlows removes the flows installed by
+	// InstallDNSInterceptFlows.
+	UninstallDNSInterceptFlows() error
+
+	// ReinjectDNSResponse sends the DNS response held by the flows installed
+	// by InstallDNSInterceptFlows back to OVS, which processes it from the
+	// conntrack table.
+	ReinjectDNSResponse(pktIn *ofctrl.PacketIn) error
+
 	// Initial tun_metadata0 in TLV map for Traceflow.
 	InitialTLVMap() error
 
 	// Find network policy and namespace by conjunction ID.
 	GetPolicyFromConjunction(ruleID uint32) *v1beta1.NetworkPolicyReference
 
-	// RegisterPacketInHandler registers PacketIn handler to process PacketIn event.
-	RegisterPacketInHandler(packetHandlerName string, packetInHandler interface{})
+	// RegisterPacketInHandler registers PacketIn handler to process PacketIn event
+	// sent with the provided reason.
+	RegisterPacketInHandler(packetHandlerReason uint8, packetHandlerName string, packetInHandler interface{})
 	// RegisterPacketInHandler uses SubscribePacketIn to get PacketIn message and process received
 	// packets through registered handlers.
 	StartPacketInHandler(stopCh <-chan struct{})
@@ -285,19 +400,24 @@ func (c *client) deleteFlows(cache *flowCategoryCache, flowCacheKey string) erro
 
 func (c *client) InstallNodeFlows(hostname string,
 	localGatewayMAC net.HardwareAddr,
-	peerPodCIDR net.IPNet,
-	peerGatewayIP, tunnelPeerIP net.IP,
+	peerConfigs map[*net.IPNet]net.IP,
+	tunnelPeerIP net.IP,
 	tunOFPort, ipsecTunOFPort uint32) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
 
-	flows := []binding.Flow{
-		c.arpResponderFlow(peerGatewayIP, cookie.Node),
-	}
-	if c.encapMode.NeedsEncapToPeer(tunnelPeerIP, c.nodeConfig.NodeIPAddr) {
-		flows = append(flows, c.l3FwdFlowToRemote(localGatewayMAC, peerPodCIDR, tunnelPeerIP, tunOFPort, cookie.Node))
-	} else {
-		flows = append(flows, c.l3FwdFlowToRemoteViaGW(localGatewayMAC, peerPodCIDR, cookie.Node))
+	var flows []binding.Flow
+	for peerPodCIDR, peerGatewayIP := range peerConfigs {
+		if peerGatewayIP.To4() != nil {
+			// The ARP responder is only needed for the IPv4 gateway. IPv6 neighbors of the remote gateways are
+			// resolved with the static neighbor entries configured by the route client.
+			flows = append(flows, c.arpResponderFlow(peerGatewayIP, cookie.Node))
+		}
+		if c.encapMode.NeedsEncapToPeer(tunnelPeerIP, c.nodeConfig.NodeIPAddr) {
+			flows = append(flows, c.l3FwdFlowToRemote(localGatewayMAC, *peerPodCIDR, tunnelPeerIP, tunOFPort, cookie.Node))
+		} else {
+			flows = append(flows, c.l3FwdFlowToRemoteViaGW(localGatewayMAC, *peerPodCIDR, cookie.Node))
+		}
 	}
 	if ipsecTunOFPort != 0 {
 		// When IPSec tunnel is enabled, packets received from the remote Node are
@@ -315,22 +435,69 @@ func (c *client) UninstallNodeFlows(hostname string) error {
 	return c.deleteFlows(c.nodeFlowCache, hostname)
 }
 
-func (c *client) InstallPodFlows(interfaceName string, podInterfaceIP net.IP, podInterfaceMAC, gatewayMAC net.HardwareAddr, ofPort uint32) error {
+// multiclusterFlowCacheKey returns the key of the flows to a remote cluster in nodeFlowCache.
+// It can't conflict with the key of a Node, as Node names can't contain "/".
+func multiclusterFlowCacheKey(clusterID string) string {
+	return fmt.Sprintf("multicluster/%s", clusterID)
+}
+
+func (c *client) InstallMulticlusterGatewayFlows(clusterID string,
+	localGatewayMAC net.HardwareAddr,
+	peerPodCIDRs []*net.IPNet,
+	tunOFPort uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+
+	// Packets received from the remote cluster are input from the tunnel port to the remote
+	// gateway Node, not the default tunnel port.
+	flows := []binding.Flow{c.tunnelClassifierFlow(tunOFPort, cookie.Node)}
+	for _, peerPodCIDR := range peerPodCIDRs {
+		flows = append(flows, c.l3FwdFlowToRemoteCluster(localGatewayMAC, *peerPodCIDR, tunOFPort, cookie.Node))
+	}
+	return c.addFlows(c.nodeFlowCache, multiclusterFlowCacheKey(clusterID), flows)
+}
+
+func (c *client) InstallMulticlusterNodeFlows(clusterID string,
+	localGatewayMAC net.HardwareAddr,
+	peerPodCIDRs []*net.IPNet,
+	gatewayNodeIP net.IP,
+	tunOFPort uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+
+	var flows []binding.Flow
+	for _, peerPodCIDR := range peerPodCIDRs {
+		flows = append(flows, c.l3FwdFlowToRemote(localGatewayMAC, *peerPodCIDR, gatewayNodeIP, tunOFPort, cookie.Node))
+	}
+	return c.addFlows(c.nodeFlowCache, multiclusterFlowCacheKey(clusterID), flows)
+}
+
+func (c *client) UninstallMulticlusterFlows(clusterID string) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.deleteFlows(c.nodeFlowCache, multiclusterFlowCacheKey(clusterID))
+}
+
+func (c *client) InstallPodFlows(interfaceName string, podInterfaceIPs []net.IP, podInterfaceMAC, gatewayMAC net.HardwareAddr, ofPort uint32) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
 	flows := []binding.Flow{
 		c.podClassifierFlow(ofPort, cookie.Pod),
-		c.podIPSpoofGuardFlow(podInterfaceIP, podInterfaceMAC, ofPort, cookie.Pod),
-		c.arpSpoofGuardFlow(podInterfaceIP, podInterfaceMAC, ofPort, cookie.Pod),
 		c.l2ForwardCalcFlow(podInterfaceMAC, ofPort, cookie.Pod),
-		c.l3FlowsToPod(gatewayMAC, podInterfaceIP, podInterfaceMAC, cookie.Pod),
 	}
+	flows = append(flows, c.podIPSpoofGuardFlows(podInterfaceIPs, podInterfaceMAC, ofPort, cookie.Pod)...)
+	if podInterfaceIPv4 := ip.GetIPv4Addr(podInterfaceIPs); podInterfaceIPv4 != nil {
+		flows = append(flows, c.arpSpoofGuardFlow(podInterfaceIPv4, podInterfaceMAC, ofPort, cookie.Pod))
+	}
+	flows = append(flows, c.l3FlowsToPod(gatewayMAC, podInterfaceIPs, podInterfaceMAC, cookie.Pod)...)
 
 	if c.encapMode.IsNetworkPolicyOnly() {
 		// In policy-only mode, traffic to local Pod is routed based on destination IP.
-		flows = append(flows,
-			c.l3ToPodFlow(podInterfaceIP, podInterfaceMAC, cookie.Pod),
-		)
+		for _, podInterfaceIP := range podInterfaceIPs {
+			flows = append(flows,
+				c.l3ToPodFlow(podInterfaceIP, podInterfaceMAC, cookie.Pod),
+			)
+		}
 	}
 	return c.addFlows(c.podFlowCache, interfaceName, flows)
 }
@@ -360,14 +527,45 @@ func (c *client) GetPodFlowKeys(interfaceName string) []string {
 	return flowKeys
 }
 
-func (c *client) InstallServiceGroup(groupID binding.GroupIDType, withSessionAffinity bool, endpoints []proxy.Endpoint) error {
+func (c *client) InstallServiceGroup(groupID binding.GroupIDType, withSessionAffinity bool, lbAlgorithm types.LBAlgorithm, weights map[string]uint16, endpoints []proxy.Endpoint) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
-	group := c.serviceEndpointGroup(groupID, withSessionAffinity, endpoints...)
+	group, slotFlows := c.serviceEndpointGroup(groupID, withSessionAffinity, lbAlgorithm, weights, endpoints...)
+	// The slot flows are installed before the group which refers to them, and
+	// the ones which are not used by the group anymore are removed after it.
+	if len(slotFlows) > 0 {
+		if err := c.ofEntryOperations.AddAll(slotFlows); err != nil {
+			return fmt.Errorf("error when installing Service Endpoints slot flows: %w", err)
+		}
+	}
 	if err := group.Add(); err != nil {
 		return fmt.Errorf("error when installing Service Endpoints Group: %w", err)
 	}
 	c.groupCache.Store(groupID, group)
+
+	cacheKey := fmt.Sprintf("Group:%d", groupID)
+	fCache := flowCache{}
+	for _, flow := range slotFlows {
+		fCache[flow.MatchString()] = flow
+	}
+	if oldFCache, ok := c.serviceFlowCache.Load(cacheKey); ok {
+		var staleFlows []binding.Flow
+		for key, flow := range oldFCache.(flowCache) {
+			if _, ok := fCache[key]; !ok {
+				staleFlows = append(staleFlows, flow)
+			}
+		}
+		if len(staleFlows) > 0 {
+			if err := c.ofEntryOperations.DeleteAll(staleFlows); err != nil {
+				return fmt.Errorf("error when removing stale Service Endpoints slot flows: %w", err)
+			}
+		}
+	}
+	if len(fCache) > 0 {
+		c.serviceFlowCache.Store(cacheKey, fCache)
+	} else {
+		c.serviceFlowCache.Delete(cacheKey)
+	}
 	return nil
 }
 
@@ -378,7 +576,7 @@ func (c *client) UninstallServiceGroup(groupID binding.GroupIDType) error {
 		return fmt.Errorf("group %d delete failed", groupID)
 	}
 	c.groupCache.Delete(groupID)
-	return nil
+	return c.deleteFlows(c.serviceFlowCache, fmt.Sprintf("Group:%d", groupID))
 }
 
 func (c *client) InstallEndpointFlows(protocol binding.Protocol, endpoints []proxy.Endpoint) error {
@@ -442,6 +640,38 @@ func (c *client) InstallLoadBalancerServiceFromOutsideFlows(svcIP net.IP, svcPor
 	return c.addFlows(c.serviceFlowCache, cacheKey, flows)
 }
 
+func (c *client) InstallLoadBalancerDSRFlows() error {
+	// The ingress IP and the Endpoint port are transported with the Geneve options of class 0x0104 and type 0x81
+	// and 0x82.
+	if err := c.bridge.AddTLVMap(0x0104, 0x81, 4, dsrServiceIPTunMetadataIndex); err != nil {
+		return err
+	}
+	if err := c.bridge.AddTLVMap(0x0104, 0x82, 4, dsrEndpointPortTunMetadataIndex); err != nil {
+		return err
+	}
+	flows := c.loadBalancerDSRFlows(*c.nodeConfig.PodCIDR)
+	if err := c.ofEntryOperations.AddAll(flows); err != nil {
+		return err
+	}
+	c.defaultServiceFlows = append(c.defaultServiceFlows, flows...)
+	return nil
+}
+
+func (c *client) InstallLoadBalancerServiceDSRFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	flows := c.loadBalancerServiceDSRFlows(config.HostGatewayOFPort, config.DefaultTunOFPort, svcIP, svcPort, protocol)
+	cacheKey := fmt.Sprintf("LoadBalancerServiceDSR:%s:%d:%s", svcIP, svcPort, protocol)
+	return c.addFlows(c.serviceFlowCache, cacheKey, flows)
+}
+
+func (c *client) UninstallLoadBalancerServiceDSRFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	cacheKey := fmt.Sprintf("LoadBalancerServiceDSR:%s:%d:%s", svcIP, svcPort, protocol)
+	return c.deleteFlows(c.serviceFlowCache, cacheKey)
+}
+
 func (c *client) InstallClusterServiceFlows() error {
 	flows := []binding.Flow{
 		c.l2ForwardOutputServiceHairpinFlow(),
@@ -457,6 +687,16 @@ func (c *client) InstallClusterServiceFlows() error {
 	return nil
 }
 
+func (c *client) InstallGatewayServiceFlows() error {
+	flows := c.gatewayServiceCommitFlows()
+	flows = append(flows, c.gatewayServiceHairpinFlows(c.nodeConfig.GatewayConfig.MAC)...)
+	if err := c.ofEntryOperations.AddAll(flows); err != nil {
+		return err
+	}
+	c.defaultServiceFlows = append(c.defaultServiceFlows, flows...)
+	return nil
+}
+
 func (c *client) InstallClusterServiceCIDRFlows(serviceNet *net.IPNet, gatewayOFPort uint32) error {
 	flow := c.serviceCIDRDNATFlow(serviceNet, gatewayOFPort)
 	if err := c.ofEntryOperations.Add(flow); err != nil {
@@ -466,19 +706,25 @@ func (c *client) InstallClusterServiceCIDRFlows(serviceNet *net.IPNet, gatewayOF
 	return nil
 }
 
-func (c *client) InstallGatewayFlows(gatewayAddr net.IP, gatewayMAC net.HardwareAddr, gatewayOFPort uint32) error {
+func (c *client) InstallGatewayFlows(gatewayAddrs []net.IP, gatewayMAC net.HardwareAddr, gatewayOFPort uint32) error {
 	flows := []binding.Flow{
 		c.gatewayClassifierFlow(gatewayOFPort, cookie.Default),
-		c.gatewayIPSpoofGuardFlow(gatewayOFPort, cookie.Default),
-		c.gatewayARPSpoofGuardFlow(gatewayOFPort, gatewayAddr, gatewayMAC, cookie.Default),
-		c.ctRewriteDstMACFlow(gatewayMAC, cookie.Default),
 		c.l2ForwardCalcFlow(gatewayMAC, gatewayOFPort, cookie.Default),
-		c.localProbeFlow(gatewayAddr, cookie.Default),
 	}
+	flows = append(flows, c.gatewayIPSpoofGuardFlows(gatewayOFPort, cookie.Default)...)
+	if gatewayIPv4 := ip.GetIPv4Addr(gatewayAddrs); gatewayIPv4 != nil {
+		flows = append(flows, c.gatewayARPSpoofGuardFlow(gatewayOFPort, gatewayIPv4, gatewayMAC, cookie.Default))
+	}
+	flows = append(flows, c.ctRewriteDstMACFlows(gatewayMAC, cookie.Default)...)
+	flows = append(flows, c.localProbeFlows(gatewayAddrs, cookie.Default)...)
 
 	// In NoEncap , no traffic from tunnel port
 	if c.encapMode.SupportsEncap() {
-		flows = append(flows, c.l3ToGatewayFlow(gatewayAddr, gatewayMAC, cookie.Default))
+		flows = append(flows, c.l3ToGatewayFlows(gatewayAddrs, gatewayMAC, cookie.Default)...)
+		// Egress relies on the tunnel to forward the traffic to the Node which owns the SNAT IP.
+		if c.enableEgress {
+			flows = append(flows, c.snatCommonFlows(c.nodeConfig.NodeIPAddr.IP, *c.nodeConfig.PodCIDR, gatewayMAC, cookie.SNAT)...)
+		}
 	}
 
 	if err := c.ofEntryOperations.AddAll(flows); err != nil {
@@ -508,13 +754,24 @@ func (c *client) InstallBridgeUplinkFlows(uplinkPort uint32, bridgeLocalPort uin
 }
 
 func (c *client) initialize() error {
+	if c.ovsMetersAreSupported {
+		// The meter must be added before the flows using it.
+		if err := c.bridge.AddMeter(PacketInMeterIDNP, PacketInMeterRateNP, PacketInMeterBurstNP); err != nil {
+			return fmt.Errorf("failed to add packet-in meter for NetworkPolicy: %v", err)
+		}
+	}
 	if err := c.ofEntryOperations.AddAll(c.defaultFlows()); err != nil {
 		return fmt.Errorf("failed to install default flows: %v", err)
 	}
 	if err := c.ofEntryOperations.Add(c.arpNormalFlow(cookie.Default)); err != nil {
 		return fmt.Errorf("failed to install arp normal flow: %v", err)
 	}
-	if err := c.ofEntryOperations.Add(c.l2ForwardOutputFlow(cookie.Default)); err != nil {
+	if c.nodeConfig.PodIPv6CIDR != nil {
+		if err := c.ofEntryOperations.AddAll(c.ipv6NeighborDiscoveryFlows(cookie.Default)); err != nil {
+			return fmt.Errorf("failed to install IPv6 Neighbor Discovery flows: %v", err)
+		}
+	}
+	if err := c.ofEntryOperations.AddAll(c.l2ForwardOutputFlows(cookie.Default)); err != nil {
 		return fmt.Errorf("failed to install L2 forward output flows: %v", err)
 	}
 	if err := c.ofEntryOperations.AddAll(c.connectionTrackFlows(cookie.Default)); err != nil {
@@ -535,6 +792,10 @@ func (c *client) Initialize(roundInfo types.RoundInfo, nodeConfig *config.NodeCo
 	c.nodeConfig = nodeConfig
 	c.encapMode = encapMode
 	c.gatewayPort = gatewayOFPort
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	if nodeConfig.PodIPv6CIDR != nil {
+		c.ipProtocols = append(c.ipProtocols, binding.ProtocolIPv6)
+	}
 
 	// Initiate connections to target OFswitch, and create tables on the switch.
 	connCh := make(chan struct{})
@@ -569,6 +830,41 @@ func (c *client) InstallExternalFlows(nodeIP net.IP, localSubnet net.IPNet) erro
 	return nil
 }
 
+func (c *client) InstallPodSNATFlows(ofPort uint32, snatIP net.IP, snatMark uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	flows := []binding.Flow{c.snatRuleFlow(ofPort, snatIP, snatMark, c.nodeConfig.GatewayConfig.MAC)}
+	cacheKey := fmt.Sprintf("PodSNAT:%d", ofPort)
+	// Delete the existing flows first as the SNAT IP or the mark might have changed.
+	if err := c.deleteFlows(c.snatFlowCache, cacheKey); err != nil {
+		return err
+	}
+	return c.addFlows(c.snatFlowCache, cacheKey, flows)
+}
+
+func (c *client) UninstallPodSNATFlows(ofPort uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.deleteFlows(c.snatFlowCache, fmt.Sprintf("PodSNAT:%d", ofPort))
+}
+
+func (c *client) InstallRemotePodSNATFlows(podIP net.IP, snatMark uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	flows := []binding.Flow{c.snatRemotePodFlow(podIP, snatMark)}
+	cacheKey := fmt.Sprintf("RemotePodSNAT:%s", podIP)
+	if err := c.deleteFlows(c.snatFlowCache, cacheKey); err != nil {
+		return err
+	}
+	return c.addFlows(c.snatFlowCache, cacheKey, flows)
+}
+
+func (c *client) UninstallRemotePodSNATFlows(podIP net.IP) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.deleteFlows(c.snatFlowCache, fmt.Sprintf("RemotePodSNAT:%s", podIP))
+}
+
 func (c *client) ReplayFlows() {
 	c.replayMutex.Lock()
 	defer c.replayMutex.Unlock()
@@ -619,6 +915,8 @@ func (c *client) ReplayFlows() {
 	c.nodeFlowCache.Range(installCachedFlows)
 	c.podFlowCache.Range(installCachedFlows)
 	c.serviceFlowCache.Range(installCachedFlows)
+	c.snatFlowCache.Range(installCachedFlows)
+	c.dnsFlowCache.Range(installCachedFlows)
 
 	c.replayPolicyFlows()
 }
@@ -726,6 +1024,69 @@ func (c *client) SendTraceflowPacket(
 	return c.bridge.SendPacketOut(packetOutObj)
 }
 
+func (c *client) SendTCPPacketOut(
+	srcMAC net.HardwareAddr,
+	dstMAC net.HardwareAddr,
+	srcIP net.IP,
+	dstIP net.IP,
+	outPort uint32,
+	tunnelDstIP net.IP,
+	tcpSrcPort uint16,
+	tcpDstPort uint16,
+	tcpSeqNum uint32,
+	tcpAckNum uint32,
+	tcpFlags uint8) error {
+	packetOutBuilder := c.newPacketOutBuilder(srcMAC, dstMAC, srcIP, dstIP, outPort, tunnelDstIP)
+	packetOutBuilder = packetOutBuilder.SetIPProtocol(binding.ProtocolTCP)
+	packetOutBuilder = packetOutBuilder.SetTCPSrcPort(tcpSrcPort)
+	packetOutBuilder = packetOutBuilder.SetTCPDstPort(tcpDstPort)
+	packetOutBuilder = packetOutBuilder.SetTCPSeqNum(tcpSeqNum)
+	packetOutBuilder = packetOutBuilder.SetTCPAckNum(tcpAckNum)
+	packetOutBuilder = packetOutBuilder.SetTCPFlags(tcpFlags)
+
+	packetOutObj := packetOutBuilder.Done()
+	return c.bridge.SendPacketOut(packetOutObj)
+}
+
+func (c *client) SendICMPPacketOut(
+	srcMAC net.HardwareAddr,
+	dstMAC net.HardwareAddr,
+	srcIP net.IP,
+	dstIP net.IP,
+	outPort uint32,
+	tunnelDstIP net.IP,
+	icmpType uint8,
+	icmpCode uint8,
+	icmpData []byte) error {
+	packetOutBuilder := c.newPacketOutBuilder(srcMAC, dstMAC, srcIP, dstIP, outPort, tunnelDstIP)
+	packetOutBuilder = packetOutBuilder.SetIPProtocol(binding.ProtocolICMP)
+	packetOutBuilder = packetOutBuilder.SetICMPType(icmpType)
+	packetOutBuilder = packetOutBuilder.SetICMPCode(icmpCode)
+	packetOutBuilder = packetOutBuilder.SetICMPData(icmpData)
+
+	packetOutObj := packetOutBuilder.Done()
+	return c.bridge.SendPacketOut(packetOutObj)
+}
+
+// newPacketOutBuilder returns a PacketOutBuilder with the common fields of a
+// packet which is output to the specified OVS port directly.
+func (c *client) newPacketOutBuilder(srcMAC, dstMAC net.HardwareAddr, srcIP, dstIP net.IP, outPort uint32, tunnelDstIP net.IP) binding.PacketOutBuilder {
+	packetOutBuilder := c.bridge.BuildPacketOut()
+	packetOutBuilder = packetOutBuilder.SetSrcMAC(srcMAC)
+	packetOutBuilder = packetOutBuilder.SetDstMAC(dstMAC)
+	packetOutBuilder = packetOutBuilder.SetSrcIP(srcIP)
+	packetOutBuilder = packetOutBuilder.SetDstIP(dstIP)
+	packetOutBuilder = packetOutBuilder.SetTTL(64)
+	// The packet is generated by the controller, and OVS doesn't allow
+	// outputting a packet to its in_port.
+	packetOutBuilder = packetOutBuilder.SetInport(openflow13.P_CONTROLLER)
+	packetOutBuilder = packetOutBuilder.SetOutport(outPort)
+	if tunnelDstIP != nil {
+		packetOutBuilder = packetOutBuilder.AddLoadAction(binding.NxmFieldTunIPv4Dst, uint64(binary.BigEndian.Uint32(tunnelDstIP.To4())), binding.Range{0, 31})
+	}
+	return packetOutBuilder
+}
+
 func (c *client) InstallTraceflowFlows(dataplaneTag uint8) error {
 	flow := c.traceflowL2ForwardOutputFlow(dataplaneTag, cookie.Default)
 	if err := c.Add(flow); err != nil {
@@ -745,13 +1106,36 @@ func (c *client) InstallTraceflowFlows(dataplaneTag uint8) error {
 				ctx.dropFlow.CopyToBuilder(priorityNormal+2, false).
 					MatchRegRange(int(TraceflowReg), uint32(dataplaneTag), OfTraceflowMarkRange).
 					SetHardTimeout(300).
-					Action().SendToController(1).
+					Action().SendToController(uint8(PacketInReasonTF)).
 					Done())
 		}
 	}
 	return c.AddAll(flows)
 }
 
+func (c *client) InstallDNSInterceptFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+
+	flows := c.dnsInterceptFlows(cookie.Policy)
+	return c.addFlows(c.dnsFlowCache, "DNSIntercept", flows)
+}
+
+func (c *client) UninstallDNSInterceptFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+
+	return c.deleteFlows(c.dnsFlowCache, "DNSIntercept")
+}
+
+func (c *client) ReinjectDNSResponse(pktIn *ofctrl.PacketIn) error {
+	// The DNS responses are intercepted before NAT. With dnsResponseMark
+	// restored from the PacketIn, they are looked up in conntrack with NAT
+	// again, so that their ct_state, ct_mark and ct_label are restored and
+	// they are processed like the other packets of established connections.
+	return c.bridge.ResubmitPacketIn(pktIn, conntrackTable)
+}
+
 // Add TLV map optClass 0x0104, optType 0x80 optLength 4 tunMetadataIndex 0 to store data plane tag
 // in tunnel. Data plane tag will be stored to NXM_NX_TUN_METADATA0[28..31] when packet get encapsulated
 // into geneve, and will be stored back to NXM_NX_REG9[28..31] when packet get decapsulated.
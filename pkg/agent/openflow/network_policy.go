@@ -39,6 +39,11 @@ const (
 	MatchTCPDstPort
 	MatchUDPDstPort
 	MatchSCTPDstPort
+	MatchTCPv6DstPort
+	MatchUDPv6DstPort
+	MatchSCTPv6DstPort
+	MatchICMP
+	MatchICMPv6
 	Unsupported
 )
 
@@ -163,7 +168,11 @@ func (m *conjunctiveMatch) generateGlobalMapKey() string {
 		// and IPNet. This is because OVS treats IP and IP/32 as the same condition, if Antrea has two different
 		// conjunctive match flow contexts, only one flow entry is installed on OVS, and the conjunctive actions in the
 		// first context wil be overwritten by those in the second one.
-		valueStr = fmt.Sprintf("%s/32", v.String())
+		if v.To4() != nil {
+			valueStr = fmt.Sprintf("%s/32", v.String())
+		} else {
+			valueStr = fmt.Sprintf("%s/128", v.String())
+		}
 		switch m.matchKey {
 		case MatchDstIP:
 			matchType = MatchDstIPNet
@@ -546,33 +555,133 @@ func (c *clause) generateAddressConjMatch(addr types.Address, addrType types.Add
 	return match
 }
 
-func getServiceMatchType(protocol *v1beta1.Protocol) int {
+// portMaskRange is a range of transport layer ports, which is matched with the port value and the bitwise mask.
+type portMaskRange struct {
+	port uint16
+	mask uint16
+}
+
+func (r portMaskRange) String() string {
+	return fmt.Sprintf("0x%x/0x%x", r.port, r.mask)
+}
+
+// getPortMaskRanges decomposes the port range [start, end] into the minimal set of portMaskRanges, each of which
+// covers a block of ports aligned on a power of 2. This avoids installing one flow per port for a large range.
+func getPortMaskRanges(start, end uint16) []portMaskRange {
+	var ranges []portMaskRange
+	// uint32 is used for the calculation to avoid overflow when the range ends at 65535.
+	for s, e := uint32(start), uint32(end); s <= e; {
+		// Find the largest block which starts at s and doesn't go beyond e.
+		size := uint32(1)
+		for s&(size<<1-1) == 0 && s+(size<<1)-1 <= e {
+			size <<= 1
+		}
+		ranges = append(ranges, portMaskRange{port: uint16(s), mask: uint16(^(size - 1))})
+		s += size
+	}
+	return ranges
+}
+
+// icmpTypeCode is the type and code of ICMP or ICMPv6 packets. A nil type or code matches all values.
+type icmpTypeCode struct {
+	icmpType *uint8
+	icmpCode *uint8
+}
+
+func (c icmpTypeCode) String() string {
+	var typeStr, codeStr = "*", "*"
+	if c.icmpType != nil {
+		typeStr = strconv.Itoa(int(*c.icmpType))
+	}
+	if c.icmpCode != nil {
+		codeStr = strconv.Itoa(int(*c.icmpCode))
+	}
+	return fmt.Sprintf("type=%s,code=%s", typeStr, codeStr)
+}
+
+func getServiceMatchType(protocol *v1beta1.Protocol, isIPv6 bool) int {
 	switch *protocol {
-	case v1beta1.ProtocolTCP:
-		return MatchTCPDstPort
+	case v1beta1.ProtocolICMP:
+		if isIPv6 {
+			return Unsupported
+		}
+		return MatchICMP
+	case v1beta1.ProtocolICMPv6:
+		if !isIPv6 {
+			return Unsupported
+		}
+		return MatchICMPv6
 	case v1beta1.ProtocolUDP:
+		if isIPv6 {
+			return MatchUDPv6DstPort
+		}
 		return MatchUDPDstPort
 	case v1beta1.ProtocolSCTP:
+		if isIPv6 {
+			return MatchSCTPv6DstPort
+		}
 		return MatchSCTPDstPort
 	default:
+		if isIPv6 {
+			return MatchTCPv6DstPort
+		}
 		return MatchTCPDstPort
 	}
 }
 
-func (c *clause) generateServicePortConjMatch(port v1beta1.Service, priority *uint16) *conjunctiveMatch {
-	matchKey := getServiceMatchType(port.Protocol)
-	// Match all ports with the given protocol type if the matchValue is not specified (value is 0).
-	matchValue := uint16(0)
-	if port.Port != nil {
-		matchValue = uint16(port.Port.IntVal)
+// generateServicePortConjMatches generates the conjunctiveMatches for the provided Service. Multiple conjunctiveMatches
+// are generated for a port range which can't be matched with a single port mask. No conjunctiveMatch is generated if the
+// Service protocol doesn't apply to the IP family, e.g. ICMP for IPv6.
+func (c *clause) generateServicePortConjMatches(port v1beta1.Service, priority *uint16, isIPv6 bool) []*conjunctiveMatch {
+	matchKey := getServiceMatchType(port.Protocol, isIPv6)
+	var matchValues []interface{}
+	switch matchKey {
+	case Unsupported:
+		return nil
+	case MatchICMP, MatchICMPv6:
+		var value icmpTypeCode
+		if port.ICMPType != nil {
+			icmpType := uint8(*port.ICMPType)
+			value.icmpType = &icmpType
+		}
+		if port.ICMPCode != nil {
+			icmpCode := uint8(*port.ICMPCode)
+			value.icmpCode = &icmpCode
+		}
+		matchValues = append(matchValues, value)
+	default:
+		if port.Port != nil && port.EndPort != nil {
+			for _, r := range getPortMaskRanges(uint16(port.Port.IntVal), uint16(*port.EndPort)) {
+				switch r.mask {
+				case 0xffff:
+					// Use the same value as a single port, as OVS treats them as the same match condition.
+					matchValues = append(matchValues, r.port)
+				case 0:
+					// Use the same value as all ports, as OVS treats them as the same match condition.
+					matchValues = append(matchValues, uint16(0))
+				default:
+					matchValues = append(matchValues, r)
+				}
+			}
+		} else {
+			// Match all ports with the given protocol type if the matchValue is not specified (value is 0).
+			matchValue := uint16(0)
+			if port.Port != nil {
+				matchValue = uint16(port.Port.IntVal)
+			}
+			matchValues = append(matchValues, matchValue)
+		}
 	}
-	match := &conjunctiveMatch{
-		tableID:    c.ruleTable.GetID(),
-		matchKey:   matchKey,
-		matchValue: matchValue,
-		priority:   priority,
+	var matches []*conjunctiveMatch
+	for _, matchValue := range matchValues {
+		matches = append(matches, &conjunctiveMatch{
+			tableID:    c.ruleTable.GetID(),
+			matchKey:   matchKey,
+			matchValue: matchValue,
+			priority:   priority,
+		})
 	}
-	return match
+	return matches
 }
 
 // addAddrFlows translates the specified addresses to conjunctiveMatchFlows, and returns the corresponding changes on the
@@ -595,9 +704,14 @@ func (c *clause) addAddrFlows(client *client, addrType types.AddressType, addres
 func (c *clause) addServiceFlows(client *client, ports []v1beta1.Service, priority *uint16) []*conjMatchFlowContextChange {
 	var conjMatchFlowContextChanges []*conjMatchFlowContextChange
 	for _, port := range ports {
-		match := c.generateServicePortConjMatch(port, priority)
-		ctxChange := c.addConjunctiveMatchFlow(client, match)
-		conjMatchFlowContextChanges = append(conjMatchFlowContextChanges, ctxChange)
+		// The Service ports are matched for each IP protocol of the Node.
+		for _, ipProtocol := range client.ipProtocols {
+			matches := c.generateServicePortConjMatches(port, priority, ipProtocol == binding.ProtocolIPv6)
+			for _, match := range matches {
+				ctxChange := c.addConjunctiveMatchFlow(client, match)
+				conjMatchFlowContextChanges = append(conjMatchFlowContextChanges, ctxChange)
+			}
+		}
 	}
 	return conjMatchFlowContextChanges
 }
@@ -763,12 +877,12 @@ func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) *poli
 		// Install action flows.
 		var actionFlows []binding.Flow
 		var metricFlows []binding.Flow
-		if rule.IsAntreaNetworkPolicyRule() && *rule.Action == secv1alpha1.RuleActionDrop {
-			metricFlows = append(metricFlows, c.dropRuleMetricFlow(ruleID, isIngress))
-			actionFlows = append(actionFlows, c.conjunctionActionDropFlow(ruleID, ruleTable.GetID(), rule.Priority))
+		if rule.IsAntreaNetworkPolicyRule() && (*rule.Action == secv1alpha1.RuleActionDrop || *rule.Action == secv1alpha1.RuleActionReject) {
+			metricFlows = append(metricFlows, c.dropRuleMetricFlows(ruleID, isIngress, rule.EnableLogging, *rule.Action == secv1alpha1.RuleActionReject)...)
+			actionFlows = append(actionFlows, c.conjunctionActionDropFlows(ruleID, ruleTable.GetID(), rule.Priority)...)
 		} else {
 			metricFlows = append(metricFlows, c.allowRulesMetricFlows(ruleID, isIngress)...)
-			actionFlows = append(actionFlows, c.conjunctionActionFlow(ruleID, ruleTable.GetID(), dropTable.GetNext(), rule.Priority))
+			actionFlows = append(actionFlows, c.conjunctionActionFlows(ruleID, ruleTable.GetID(), dropTable.GetNext(), rule.Priority, rule.EnableLogging)...)
 		}
 		conj.actionFlows = actionFlows
 		conj.metricFlows = metricFlows
@@ -1338,6 +1452,11 @@ func (c *client) ReassignFlowPriorities(updates map[uint16]uint16, table binding
 func parseDropFlow(flow string) (uint32, types.RuleMetric) {
 	// example format:
 	// table=101, n_packets=9, n_bytes=666, priority=200,ip,reg0=0x100000/0x100000,reg3=0x5 actions=drop
+	// table=101, n_packets=9, n_bytes=666, priority=200,ct_state=+new,ip,reg0=0x100000/0x100000,reg3=0x5 actions=meter:1,...
+	// The actions may also contain "reg" fields, so only the matches are parsed.
+	if i := strings.Index(flow, " actions="); i >= 0 {
+		flow = flow[:i]
+	}
 	segs := strings.Split(flow, ",")
 	m := types.RuleMetric{}
 	pkts, _ := strconv.ParseUint(segs[1][strings.Index(segs[1], "=")+1:], 10, 64)
@@ -1345,7 +1464,12 @@ func parseDropFlow(flow string) (uint32, types.RuleMetric) {
 	m.Sessions = pkts
 	bytes, _ := strconv.ParseUint(segs[2][strings.Index(segs[2], "=")+1:], 10, 64)
 	m.Bytes = bytes
-	id, _ := strconv.ParseUint(segs[6][strings.Index(segs[6], "0x")+2:strings.Index(segs[6], " ")], 16, 64)
+	var id uint64
+	for _, seg := range segs[3:] {
+		if strings.HasPrefix(seg, "reg3=0x") {
+			id, _ = strconv.ParseUint(strings.TrimPrefix(seg, "reg3=0x"), 16, 64)
+		}
+	}
 	return uint32(id), m
 }
 
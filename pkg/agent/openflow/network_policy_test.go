@@ -399,6 +399,78 @@ func TestConjMatchFlowContextKeyConflict(t *testing.T) {
 	assert.Equal(t, clause2.action, act2)
 }
 
+func TestGetPortMaskRanges(t *testing.T) {
+	for _, tc := range []struct {
+		start, end     uint16
+		expectedRanges []portMaskRange
+	}{
+		{start: 80, end: 80, expectedRanges: []portMaskRange{{port: 80, mask: 0xffff}}},
+		{start: 1024, end: 2047, expectedRanges: []portMaskRange{{port: 1024, mask: 0xfc00}}},
+		{start: 0, end: 65535, expectedRanges: []portMaskRange{{port: 0, mask: 0}}},
+		{start: 65534, end: 65535, expectedRanges: []portMaskRange{{port: 65534, mask: 0xfffe}}},
+		{
+			start: 1000,
+			end:   1999,
+			expectedRanges: []portMaskRange{
+				{port: 1000, mask: 0xfff8},
+				{port: 1008, mask: 0xfff0},
+				{port: 1024, mask: 0xfe00},
+				{port: 1536, mask: 0xff00},
+				{port: 1792, mask: 0xff80},
+				{port: 1920, mask: 0xffc0},
+				{port: 1984, mask: 0xfff0},
+			},
+		},
+	} {
+		ranges := getPortMaskRanges(tc.start, tc.end)
+		assert.Equal(t, tc.expectedRanges, ranges, "range %d-%d", tc.start, tc.end)
+		// The ranges must cover all the ports in the range exactly once.
+		count := 0
+		for port := 0; port <= 65535; port++ {
+			for _, r := range ranges {
+				if uint16(port)&r.mask == r.port {
+					count++
+				}
+			}
+		}
+		assert.Equal(t, int(tc.end)-int(tc.start)+1, count, "range %d-%d", tc.start, tc.end)
+	}
+}
+
+func TestGenerateServicePortConjMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conj := &policyRuleConjunction{id: 1}
+	clause := conj.newClause(1, 3, createMockTable(ctrl, EgressRuleTable, EgressDefaultTable, binding.TableMissActionNext), nil)
+
+	protocolTCP := v1beta1.ProtocolTCP
+	protocolICMP := v1beta1.ProtocolICMP
+	protocolICMPv6 := v1beta1.ProtocolICMPv6
+	port1000 := intstr.FromInt(1000)
+	endPort1023, endPort1024 := int32(1023), int32(1024)
+	icmpType := int32(8)
+	echoRequest := uint8(8)
+
+	getMatchValues := func(service v1beta1.Service, isIPv6 bool) []interface{} {
+		var values []interface{}
+		for _, match := range clause.generateServicePortConjMatches(service, nil, isIPv6) {
+			values = append(values, match.matchValue)
+		}
+		return values
+	}
+	assert.Equal(t, []interface{}{uint16(1000)}, getMatchValues(v1beta1.Service{Protocol: &protocolTCP, Port: &port1000}, false))
+	assert.Equal(t, []interface{}{portMaskRange{port: 1000, mask: 0xfff8}, portMaskRange{port: 1008, mask: 0xfff0}},
+		getMatchValues(v1beta1.Service{Protocol: &protocolTCP, Port: &port1000, EndPort: &endPort1023}, false))
+	// A block of a single port uses the same match value as the port itself.
+	assert.Equal(t, []interface{}{portMaskRange{port: 1000, mask: 0xfff8}, portMaskRange{port: 1008, mask: 0xfff0}, uint16(1024)},
+		getMatchValues(v1beta1.Service{Protocol: &protocolTCP, Port: &port1000, EndPort: &endPort1024}, false))
+	assert.Equal(t, []interface{}{icmpTypeCode{icmpType: &echoRequest}}, getMatchValues(v1beta1.Service{Protocol: &protocolICMP, ICMPType: &icmpType}, false))
+	// ICMP is not matched for IPv6, and ICMPv6 is not matched for IPv4.
+	assert.Empty(t, getMatchValues(v1beta1.Service{Protocol: &protocolICMP}, true))
+	assert.Empty(t, getMatchValues(v1beta1.Service{Protocol: &protocolICMPv6}, false))
+	assert.Equal(t, "type=8,code=*", icmpTypeCode{icmpType: &echoRequest}.String())
+}
+
 func getChangedFlowCount(flows []*flowChange) int {
 	var count int
 	for _, changedFlow := range flows {
@@ -606,6 +678,7 @@ func prepareClient(ctrl *gomock.Controller) *client {
 		policyCache:              policyCache,
 		globalConjMatchFlowCache: map[string]*conjMatchFlowContext{},
 		bridge:                   bridge,
+		ipProtocols:              []binding.Protocol{binding.ProtocolIP},
 	}
 	c.cookieAllocator = cookie.NewAllocator(0)
 	m := oftest.NewMockOFEntryOperations(ctrl)
@@ -630,6 +703,15 @@ func TestParseMetricFlow(t *testing.T) {
 				Sessions: 9,
 			},
 		},
+		"Drop flow sending packets to the controller": {
+			flow: "table=101, n_packets=9, n_bytes=666, priority=200,ct_state=+new,ip,reg0=0x100000/0x100000,reg3=0x5 actions=meter:1,load:0x1->NXM_NX_REG0[25..27],controller(reason=no_match,id=62373,userdata=01.00)",
+			rule: 5,
+			metric: types.RuleMetric{
+				Bytes:    666,
+				Packets:  9,
+				Sessions: 9,
+			},
+		},
 		"New allow flow": {
 			flow: "table=101, n_packets=123, n_bytes=456, priority=200,ct_state=+new,ct_label=0x112345678/0xffffffff00000000,ip actions=goto_table:105",
 			rule: 1,
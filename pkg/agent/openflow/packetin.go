@@ -15,6 +15,8 @@
 package openflow
 
 import (
+	"fmt"
+
 	"github.com/contiv/ofnet/ofctrl"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
@@ -27,33 +29,45 @@ type PacketInHandler interface {
 }
 
 const (
-	// Action explicitly output to controller.
-	ofprAction ofpPacketInReason = 1
+	// PacketInReasonTF is the reason of the packets sent to the controller
+	// by the Traceflow flows.
+	PacketInReasonTF ofpPacketInReason = 1
+	// PacketInReasonNP is the reason of the packets sent to the controller
+	// by the NetworkPolicy flows.
+	PacketInReasonNP ofpPacketInReason = 0
 	// Max packetInQueue size.
 	packetInQueueSize int = 256
 )
 
-func (c *client) RegisterPacketInHandler(packetHandlerName string, packetInHandler interface{}) {
+func (c *client) RegisterPacketInHandler(packetHandlerReason uint8, packetHandlerName string, packetInHandler interface{}) {
 	handler, ok := packetInHandler.(PacketInHandler)
 	if !ok {
-		klog.Errorf("Invalid Traceflow controller.")
+		klog.Errorf("Invalid PacketIn handler %s.", packetHandlerName)
 		return
 	}
-	c.packetInHandlers[packetHandlerName] = handler
+	if c.packetInHandlers[packetHandlerReason] == nil {
+		c.packetInHandlers[packetHandlerReason] = map[string]PacketInHandler{}
+	}
+	c.packetInHandlers[packetHandlerReason][packetHandlerName] = handler
 }
 
+// StartPacketInHandler subscribes the packets sent to the controller with the
+// reasons of the registered handlers, and dispatches them to the handlers.
 func (c *client) StartPacketInHandler(stopCh <-chan struct{}) {
-	if len(c.packetInHandlers) == 0 {
-		return
+	for reason := range c.packetInHandlers {
+		go c.startPacketInHandlerForReason(reason, stopCh)
 	}
+}
+
+func (c *client) startPacketInHandlerForReason(reason uint8, stopCh <-chan struct{}) {
 	ch := make(chan *ofctrl.PacketIn)
-	err := c.SubscribePacketIn(uint8(ofprAction), ch)
+	err := c.SubscribePacketIn(reason, ch)
 	if err != nil {
-		klog.Errorf("Subscribe PacketIn failed %+v", err)
+		klog.Errorf("Subscribe PacketIn with reason %d failed %+v", reason, err)
 		return
 	}
-	packetInQueue := workqueue.NewNamed("packetIn")
-	go c.parsePacketIn(packetInQueue, stopCh)
+	packetInQueue := workqueue.NewNamed(fmt.Sprintf("packetIn-%d", reason))
+	go c.parsePacketIn(reason, packetInQueue, stopCh)
 
 	for {
 		select {
@@ -71,7 +85,7 @@ func (c *client) StartPacketInHandler(stopCh <-chan struct{}) {
 	}
 }
 
-func (c *client) parsePacketIn(packetInQueue workqueue.Interface, stopCh <-chan struct{}) {
+func (c *client) parsePacketIn(reason uint8, packetInQueue workqueue.Interface, stopCh <-chan struct{}) {
 	for {
 		obj, quit := packetInQueue.Get()
 		if quit {
@@ -83,7 +97,7 @@ func (c *client) parsePacketIn(packetInQueue workqueue.Interface, stopCh <-chan
 			klog.Errorf("Invalid packet in data in queue, skipping.")
 			continue
 		}
-		for name, handler := range c.packetInHandlers {
+		for name, handler := range c.packetInHandlers[reason] {
 			err := handler.HandlePacketIn(pktIn)
 			if err != nil {
 				klog.Errorf("PacketIn handler %s failed to process packet: %+v", name, err)
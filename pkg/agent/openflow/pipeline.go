@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
@@ -53,6 +54,7 @@ const (
 	EgressDefaultTable          binding.TableIDType = 60
 	EgressMetricTable           binding.TableIDType = 61
 	l3ForwardingTable           binding.TableIDType = 70
+	snatTable                   binding.TableIDType = 71
 	l2ForwardingCalcTable       binding.TableIDType = 80
 	MultiTierIngressRuleTable   binding.TableIDType = 85
 	DefaultTierIngressRuleTable binding.TableIDType = 89
@@ -70,6 +72,7 @@ const (
 	prioritySNAT            = uint16(180)
 	priorityMiss            = uint16(0)
 	priorityTopAntreaPolicy = uint16(64990)
+	priorityDNSIntercept    = uint16(64991)
 
 	// Index for priority cache
 	priorityIndex = "priority"
@@ -112,6 +115,7 @@ var (
 		{EgressDefaultTable, "EgressDefaultRule"},
 		{EgressMetricTable, "EgressMetric"},
 		{l3ForwardingTable, "l3Forwarding"},
+		{snatTable, "SNAT"},
 		{l2ForwardingCalcTable, "L2Forwarding"},
 		{MultiTierIngressRuleTable, "AntreaPolicyMultiTierIngressRule"},
 		{DefaultTierIngressRuleTable, "AntreaPolicyAppTierIngressRule"},
@@ -201,9 +205,12 @@ const (
 	EgressReg       regType = 5
 	IngressReg      regType = 6
 	TraceflowReg    regType = 9 // Use reg9[28..31] to store traceflow dataplaneTag.
-	// cnpDropConjunctionIDReg reuses reg3 which will also be used for storing endpoint IP to store the rule ID. Since
+	// CNPDropConjunctionIDReg reuses reg3 which will also be used for storing endpoint IP to store the rule ID. Since
 	// the service selection will finish when a packet hitting NetworkPolicy related rules, there is no conflict.
-	cnpDropConjunctionIDReg regType = 3
+	CNPDropConjunctionIDReg regType = 3
+	// CustomReasonMarkReg stores the reasons of sending a packet to the
+	// controller by the NetworkPolicy flows in CustomReasonMarkRange.
+	CustomReasonMarkReg = marksReg
 	// marksRegServiceNeedLB indicates a packet need to do service selection.
 	marksRegServiceNeedLB uint32 = 0b001
 	// marksRegServiceSelected indicates a packet has done service selection.
@@ -211,18 +218,63 @@ const (
 	// marksRegServiceNeedLearn indicates a packet has done service selection and
 	// the selection result needs to be cached.
 	marksRegServiceNeedLearn uint32 = 0b011
+	// marksRegServiceHashed indicates a packet has been hashed by the group of
+	// a Service with a hash based LB algorithm, and the Endpoint of the hash
+	// value needs to be selected.
+	marksRegServiceHashed uint32 = 0b100
 
 	CtZone = 0xfff0
+	// SNATCtZone is the conntrack zone used to SNAT the Service traffic which is load-balanced from the host gateway
+	// to the host gateway again. It must differ from CtZone in which the traffic has been DNAT'd.
+	SNATCtZone = 0xfff1
+
+	// icmp6TypeNeighborSolicitation and icmp6TypeNeighborAdvertisement are the ICMPv6 types of the Neighbor
+	// Discovery packets.
+	icmp6TypeNeighborSolicitation  = 135
+	icmp6TypeNeighborAdvertisement = 136
+
+	// PacketInMeterIDNP is the ID of the meter which limits the rate of the packets dropped by NetworkPolicies and
+	// sent to the controller. PacketInMeterRateNP and PacketInMeterBurstNP are the rate in packets per second and the
+	// burst size in packets of the meter.
+	PacketInMeterIDNP    binding.MeterIDType = 1
+	PacketInMeterRateNP                      = 100
+	PacketInMeterBurstNP                     = 200
+
+	// dnsPort is the UDP port on which DNS servers serve queries.
+	dnsPort = 53
 
 	portFoundMark    = 0b1
 	snatRequiredMark = 0b1
 	hairpinMark      = 0b1
 	macRewriteMark   = 0b1
-	cnpDropMark      = 0b1
+	CNPDropMark      = 0b1
+	dsrMark          = 0b1
+	dnsResponseMark  = 0b1
+
+	// CustomReasonReject indicates that the packet is dropped by a rule with
+	// the Reject action, and a reject response should be sent back.
+	CustomReasonReject = 0b01
+	// CustomReasonLogging indicates that the packet is matched by a rule with
+	// logging enabled, and an audit log entry should be generated.
+	CustomReasonLogging = 0b10
+	// CustomReasonDNS indicates that the packet is a DNS response received by
+	// a local Pod, and should be parsed to learn the IPs of FQDNs.
+	CustomReasonDNS = 0b100
+	// CustomReasonDeny indicates that the packet is dropped by a NetworkPolicy
+	// rule or by the isolation of NetworkPolicies, and the denied connection
+	// should be recorded by the Flow Exporter.
+	CustomReasonDeny = 0b1000
 
 	gatewayCTMark = 0x20
 	snatCTMark    = 0x40
 	serviceCTMark = 0x21
+
+	// dsrServiceIPTunMetadataIndex is the index of the tun_metadata field which
+	// carries the LoadBalancer ingress IP of the DSR traffic in the tunnel.
+	dsrServiceIPTunMetadataIndex = 1
+	// dsrEndpointPortTunMetadataIndex is the index of the tun_metadata field
+	// which carries the selected Endpoint port of the DSR traffic in the tunnel.
+	dsrEndpointPortTunMetadataIndex = 2
 )
 
 var (
@@ -242,7 +294,21 @@ var (
 	// macRewriteMarkRange takes the 19th bit of register marksReg to indicate
 	// if the packet's MAC addresses need to be rewritten. Its value is 0x1 if yes.
 	macRewriteMarkRange = binding.Range{19, 19}
-	cnpDropMarkRange    = binding.Range{20, 20}
+	CNPDropMarkRange    = binding.Range{20, 20}
+	// CustomReasonMarkRange takes the 21st to 24th bits of register marksReg
+	// to indicate the reasons of sending a packet to the controller.
+	CustomReasonMarkRange = binding.Range{21, 24}
+	// dsrMarkRange takes the 25th bit of register marksReg to indicate if the
+	// packet is sent to a LoadBalancer Service in DSR mode from outside the
+	// cluster. Its value is 0x1 if yes.
+	dsrMarkRange = binding.Range{25, 25}
+	// dnsResponseMarkRange takes the 26th bit of register marksReg to indicate
+	// if the DNS response has been checked by the DNS intercept flows. Its
+	// value is 0x1 if yes.
+	dnsResponseMarkRange = binding.Range{26, 26}
+	// snatPktMarkRange takes an 8-bit range of pkt_mark to store the ID of
+	// a SNAT IP. The bit range must match SNATIPMarkMask.
+	snatPktMarkRange = binding.Range{0, 7}
 	// endpointIPRegRange takes a 32-bit range of register endpointIPReg to store
 	// the selected Service Endpoint IP.
 	endpointIPRegRange = binding.Range{0, 31}
@@ -259,10 +325,13 @@ var (
 	// metricEgressRuleIDRange takes 32..63 range of ct_label to store the egress rule ID.
 	metricEgressRuleIDRange = binding.Range{32, 63}
 
-	globalVirtualMAC, _ = net.ParseMAC("aa:bb:cc:dd:ee:ff")
-	hairpinIP           = net.ParseIP("169.254.169.252").To4()
+	hairpinIP = net.ParseIP("169.254.169.252").To4()
 )
 
+// GlobalVirtualMAC is the MAC address used as the destination MAC of the tunnel traffic, and as the MAC address of
+// the peer Node gateways.
+var GlobalVirtualMAC, _ = net.ParseMAC("aa:bb:cc:dd:ee:ff")
+
 type OFEntryOperations interface {
 	Add(flow binding.Flow) error
 	Modify(flow binding.Flow) error
@@ -280,13 +349,19 @@ type flowCategoryCache struct {
 }
 
 type client struct {
-	enableProxy                                   bool
-	enableAntreaPolicy                            bool
-	roundInfo                                     types.RoundInfo
-	cookieAllocator                               cookie.Allocator
-	bridge                                        binding.Bridge
-	pipeline                                      map[binding.TableIDType]binding.Table
-	nodeFlowCache, podFlowCache, serviceFlowCache *flowCategoryCache // cache for corresponding deletions
+	enableProxy                                                  bool
+	enableAntreaPolicy                                           bool
+	enableEgress                                                 bool
+	enableDenyTracking                                           bool
+	ovsMetersAreSupported                                        bool
+	roundInfo                                                    types.RoundInfo
+	cookieAllocator                                              cookie.Allocator
+	bridge                                                       binding.Bridge
+	pipeline                                                     map[binding.TableIDType]binding.Table
+	nodeFlowCache, podFlowCache, serviceFlowCache, snatFlowCache *flowCategoryCache // cache for corresponding deletions
+	// dnsFlowCache caches the flows sending DNS responses to the controller, which are only installed when there
+	// are rules with FQDN peers.
+	dnsFlowCache *flowCategoryCache
 	// "fixed" flows installed by the agent after initialization and which do not change during
 	// the lifetime of the client.
 	gatewayFlows, defaultServiceFlows, defaultTunnelFlows, hostNetworkingFlows []binding.Flow
@@ -307,11 +382,13 @@ type client struct {
 	encapMode   config.TrafficEncapModeType
 	gatewayPort uint32 // OVSOFPort number
 	// packetInHandlers stores handler to process PacketIn event
-	packetInHandlers map[string]PacketInHandler
+	packetInHandlers map[uint8]map[string]PacketInHandler
+	// ipProtocols contains the IP protocols of the Pod CIDRs of the Node, "ip" for IPv4 and "ipv6" for IPv6.
+	ipProtocols []binding.Protocol
 }
 
 func (c *client) GetTunnelVirtualMAC() net.HardwareAddr {
-	return globalVirtualMAC
+	return GlobalVirtualMAC
 }
 
 func (c *client) Add(flow binding.Flow) error {
@@ -514,52 +591,59 @@ func (c *client) connectionTrackFlows(category cookie.Category) []binding.Flow {
 				Action().ResubmitToTable(sessionAffinityTable).
 				Action().ResubmitToTable(serviceLBTable).
 				Done(),
-			// Enable NAT.
-			connectionTrackTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-				Action().CT(false, connectionTrackTable.GetNext(), CtZone).NAT().CTDone().
+		)
+	}
+	for _, ipProtocol := range c.ipProtocols {
+		if c.enableProxy {
+			flows = append(flows,
+				// Enable NAT.
+				connectionTrackTable.BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+					Action().CT(false, connectionTrackTable.GetNext(), CtZone).NAT().CTDone().
+					Cookie(c.cookieAllocator.Request(category).Raw()).
+					Done(),
+				connectionTrackCommitTable.BuildFlow(priorityLow).MatchProtocol(ipProtocol).
+					MatchCTStateTrk(true).
+					MatchCTMark(serviceCTMark).
+					MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+					Cookie(c.cookieAllocator.Request(category).Raw()).
+					Action().GotoTable(connectionTrackCommitTable.GetNext()).
+					Done(),
+			)
+		} else {
+			flows = append(flows,
+				connectionTrackTable.BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+					Action().CT(false, connectionTrackTable.GetNext(), CtZone).CTDone().
+					Cookie(c.cookieAllocator.Request(category).Raw()).
+					Done(),
+			)
+		}
+		flows = append(flows,
+			connectionTrackStateTable.BuildFlow(priorityHigh).MatchProtocol(ipProtocol).
+				MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+				MatchCTMark(gatewayCTMark).
+				MatchCTStateNew(false).MatchCTStateTrk(true).
+				Action().GotoTable(connectionTrackStateTable.GetNext()).
 				Cookie(c.cookieAllocator.Request(category).Raw()).
 				Done(),
-			connectionTrackCommitTable.BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
-				MatchCTStateTrk(true).
-				MatchCTMark(serviceCTMark).
-				MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+			connectionTrackStateTable.BuildFlow(priorityLow).MatchProtocol(ipProtocol).
+				MatchCTStateInv(true).MatchCTStateTrk(true).
+				Action().Drop().
 				Cookie(c.cookieAllocator.Request(category).Raw()).
-				Action().GotoTable(connectionTrackCommitTable.GetNext()).
 				Done(),
-		)
-	} else {
-		flows = append(flows,
-			connectionTrackTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-				Action().CT(false, connectionTrackTable.GetNext(), CtZone).CTDone().
+			connectionTrackCommitTable.BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+				MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+				MatchCTStateNew(true).MatchCTStateTrk(true).
+				Action().CT(true, connectionTrackCommitTable.GetNext(), CtZone).LoadToMark(gatewayCTMark).CTDone().
+				Cookie(c.cookieAllocator.Request(category).Raw()).
+				Done(),
+			connectionTrackCommitTable.BuildFlow(priorityLow).MatchProtocol(ipProtocol).
+				MatchCTStateNew(true).MatchCTStateTrk(true).
+				Action().CT(true, connectionTrackCommitTable.GetNext(), CtZone).CTDone().
 				Cookie(c.cookieAllocator.Request(category).Raw()).
 				Done(),
 		)
 	}
-	return append(flows,
-		connectionTrackStateTable.BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
-			MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
-			MatchCTMark(gatewayCTMark).
-			MatchCTStateNew(false).MatchCTStateTrk(true).
-			Action().GotoTable(connectionTrackStateTable.GetNext()).
-			Cookie(c.cookieAllocator.Request(category).Raw()).
-			Done(),
-		connectionTrackStateTable.BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
-			MatchCTStateInv(true).MatchCTStateTrk(true).
-			Action().Drop().
-			Cookie(c.cookieAllocator.Request(category).Raw()).
-			Done(),
-		connectionTrackCommitTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-			MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
-			MatchCTStateNew(true).MatchCTStateTrk(true).
-			Action().CT(true, connectionTrackCommitTable.GetNext(), CtZone).LoadToMark(gatewayCTMark).CTDone().
-			Cookie(c.cookieAllocator.Request(category).Raw()).
-			Done(),
-		connectionTrackCommitTable.BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
-			MatchCTStateNew(true).MatchCTStateTrk(true).
-			Action().CT(true, connectionTrackCommitTable.GetNext(), CtZone).CTDone().
-			Cookie(c.cookieAllocator.Request(category).Raw()).
-			Done(),
-	)
+	return flows
 }
 
 // TODO: Use DuplicateToBuilder or integrate this function into original one to avoid unexpected difference.
@@ -582,17 +666,21 @@ func (c *client) traceflowConnectionTrackFlows(dataplaneTag uint8, category cook
 	return flowBuilder.Done()
 }
 
-// ctRewriteDstMACFlow rewrites the destination MAC with local host gateway MAC if the packets has set ct_mark but not sent from the host gateway.
-func (c *client) ctRewriteDstMACFlow(gatewayMAC net.HardwareAddr, category cookie.Category) binding.Flow {
+// ctRewriteDstMACFlows rewrite the destination MAC with local host gateway MAC if the packets has set ct_mark but not sent from the host gateway.
+func (c *client) ctRewriteDstMACFlows(gatewayMAC net.HardwareAddr, category cookie.Category) []binding.Flow {
 	connectionTrackStateTable := c.pipeline[conntrackStateTable]
 	macData, _ := strconv.ParseUint(strings.Replace(gatewayMAC.String(), ":", "", -1), 16, 64)
-	return connectionTrackStateTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchCTMark(gatewayCTMark).
-		MatchCTStateNew(false).MatchCTStateTrk(true).
-		Action().LoadRange(binding.NxmFieldDstMAC, macData, binding.Range{0, 47}).
-		Action().GotoTable(connectionTrackStateTable.GetNext()).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		flows = append(flows, connectionTrackStateTable.BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+			MatchCTMark(gatewayCTMark).
+			MatchCTStateNew(false).MatchCTStateTrk(true).
+			Action().LoadRange(binding.NxmFieldDstMAC, macData, binding.Range{0, 47}).
+			Action().GotoTable(connectionTrackStateTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	return flows
 }
 
 // serviceLBBypassFlow makes packets that belong to a tracked connection bypass
@@ -608,6 +696,25 @@ func (c *client) serviceLBBypassFlow() binding.Flow {
 		Done()
 }
 
+// gatewayServiceCommitFlows generates the flows that make the Service connections, which are sent to the switch from
+// the host gateway, keep serviceCTMark instead of gatewayCTMark in the conntrackCommitTable, so that the subsequent
+// packets of them bypass the Service LB tables with the MAC rewriting.
+func (c *client) gatewayServiceCommitFlows() []binding.Flow {
+	connectionTrackCommitTable := c.pipeline[conntrackCommitTable]
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		flows = append(flows, connectionTrackCommitTable.BuildFlow(priorityHigh).MatchProtocol(ipProtocol).
+			MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+			MatchCTStateTrk(true).
+			MatchCTMark(serviceCTMark).
+			MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+			Action().GotoTable(connectionTrackCommitTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done())
+	}
+	return flows
+}
+
 // l2ForwardCalcFlow generates the flow that matches dst MAC and loads ofPort to reg.
 func (c *client) l2ForwardCalcFlow(dstMAC net.HardwareAddr, ofPort uint32, category cookie.Category) binding.Flow {
 	l2FwdCalcTable := c.pipeline[l2ForwardingCalcTable]
@@ -620,13 +727,17 @@ func (c *client) l2ForwardCalcFlow(dstMAC net.HardwareAddr, ofPort uint32, categ
 		Done()
 }
 
-// l2ForwardOutputFlow generates the flow that outputs packets to OVS port after L2 forwarding calculation.
-func (c *client) l2ForwardOutputFlow(category cookie.Category) binding.Flow {
-	return c.pipeline[L2ForwardingOutTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
-		Action().OutputRegRange(int(portCacheReg), ofPortRegRange).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+// l2ForwardOutputFlows generate the flows that output packets to OVS port after L2 forwarding calculation.
+func (c *client) l2ForwardOutputFlows(category cookie.Category) []binding.Flow {
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		flows = append(flows, c.pipeline[L2ForwardingOutTable].BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+			MatchRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+			Action().OutputRegRange(int(portCacheReg), ofPortRegRange).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	return flows
 }
 
 // traceflowL2ForwardOutputFlow generates Traceflow specific flow that outputs traceflow packets to OVS port and Antrea
@@ -641,7 +752,7 @@ func (c *client) traceflowL2ForwardOutputFlow(dataplaneTag uint8, category cooki
 		MatchRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
 		Action().MoveRange(regName, tunMetadataName, OfTraceflowMarkRange, OfTraceflowMarkRange).
 		Action().OutputRegRange(int(portCacheReg), ofPortRegRange).
-		Action().SendToController(1).
+		Action().SendToController(uint8(PacketInReasonTF)).
 		Cookie(c.cookieAllocator.Request(category).Raw()).
 		Done()
 }
@@ -656,24 +767,66 @@ func (c *client) l2ForwardOutputServiceHairpinFlow() binding.Flow {
 		Done()
 }
 
-// l3FlowsToPod generates the flow to rewrite MAC if the packet is received from tunnel port and destined for local Pods.
-func (c *client) l3FlowsToPod(localGatewayMAC net.HardwareAddr, podInterfaceIP net.IP, podInterfaceMAC net.HardwareAddr, category cookie.Category) binding.Flow {
+// gatewayServiceHairpinFlows generates the flows for the Service traffic which is sent to the switch from the host
+// gateway, and load-balanced to an Endpoint which is reached via the host gateway again, e.g. a hostNetwork Endpoint.
+// The request packets are sent back to the host gateway with the in_port action, and their source IP is translated
+// to the virtual Service IP in SNATCtZone, as the host would drop the packets whose source IP is its own IP otherwise.
+// The reply packets to the virtual Service IP are reverse translated in SNATCtZone before entering conntrackTable,
+// and sent back to the host gateway with the in_port action too.
+func (c *client) gatewayServiceHairpinFlows(gatewayMAC net.HardwareAddr) []binding.Flow {
+	return []binding.Flow{
+		c.pipeline[serviceHairpinTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+			MatchDstIP(config.VirtualServiceIP).
+			Action().LoadRegRange(int(marksReg), hairpinMark, hairpinMarkRange).
+			Action().CT(false, conntrackTable, SNATCtZone).NAT().CTDone().
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+		// The Service packets from the host gateway, which are not forwarded to a Pod by other l3ForwardingTable
+		// flows, are forwarded to the host gateway again.
+		c.pipeline[l3ForwardingTable].BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+			MatchRegRange(int(marksReg), macRewriteMark, macRewriteMarkRange).
+			Action().SetDstMAC(gatewayMAC).
+			Action().LoadRegRange(int(marksReg), hairpinMark, hairpinMarkRange).
+			Action().GotoTable(c.pipeline[l3ForwardingTable].GetNext()).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+		c.pipeline[hairpinSNATTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+			MatchRegRange(int(marksReg), hairpinMark, hairpinMarkRange).
+			MatchCTStateRpl(false).MatchCTStateTrk(true).
+			Action().CT(true, L2ForwardingOutTable, SNATCtZone).
+			SNAT(&binding.IPRange{StartIP: config.VirtualServiceIP, EndIP: config.VirtualServiceIP}, nil).
+			CTDone().
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+	}
+}
+
+// l3FlowsToPod generates the flows to rewrite MAC if the packet is received from tunnel port and destined for local Pods.
+// One flow is generated for each IP of the Pod.
+func (c *client) l3FlowsToPod(localGatewayMAC net.HardwareAddr, podInterfaceIPs []net.IP, podInterfaceMAC net.HardwareAddr, category cookie.Category) []binding.Flow {
 	l3FwdTable := c.pipeline[l3ForwardingTable]
-	flowBuilder := l3FwdTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP)
-	if c.enableProxy {
-		flowBuilder = flowBuilder.MatchRegRange(int(marksReg), macRewriteMark, macRewriteMarkRange)
-	} else {
-		flowBuilder = flowBuilder.MatchDstMAC(globalVirtualMAC)
+	var flows []binding.Flow
+	for _, podInterfaceIP := range podInterfaceIPs {
+		flowBuilder := l3FwdTable.BuildFlow(priorityNormal).MatchProtocol(getIPProtocol(podInterfaceIP))
+		if c.enableProxy {
+			flowBuilder = flowBuilder.MatchRegRange(int(marksReg), macRewriteMark, macRewriteMarkRange)
+		} else {
+			flowBuilder = flowBuilder.MatchDstMAC(GlobalVirtualMAC)
+		}
+		// Rewrite src MAC to local gateway MAC, and rewrite dst MAC to pod MAC
+		flows = append(flows, flowBuilder.
+			MatchDstIP(podInterfaceIP).
+			Action().SetSrcMAC(localGatewayMAC).
+			Action().SetDstMAC(podInterfaceMAC).
+			Action().DecTTL().
+			Action().GotoTable(l3FwdTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
 	}
-	// Rewrite src MAC to local gateway MAC, and rewrite dst MAC to pod MAC
-	return flowBuilder.
-		MatchDstIP(podInterfaceIP).
-		Action().SetSrcMAC(localGatewayMAC).
-		Action().SetDstMAC(podInterfaceMAC).
-		Action().DecTTL().
-		Action().GotoTable(l3FwdTable.GetNext()).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+	return flows
 }
 
 // l3ToPodFromGwFlow generates the flow to rewrite MAC if the packet IP matches an local IP.
@@ -701,16 +854,21 @@ func (c *client) l3ToGWFlow(gwMAC net.HardwareAddr, category cookie.Category) bi
 		Done()
 }
 
-// l3ToGatewayFlow generates flow that rewrites MAC of the packet received from tunnel port and destined to local gateway.
-func (c *client) l3ToGatewayFlow(localGatewayIP net.IP, localGatewayMAC net.HardwareAddr, category cookie.Category) binding.Flow {
+// l3ToGatewayFlows generate flows that rewrite MAC of the packet received from tunnel port and destined to local gateway.
+// One flow is generated for each IP of the local gateway.
+func (c *client) l3ToGatewayFlows(localGatewayIPs []net.IP, localGatewayMAC net.HardwareAddr, category cookie.Category) []binding.Flow {
 	l3FwdTable := c.pipeline[l3ForwardingTable]
-	return l3FwdTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchDstMAC(globalVirtualMAC).
-		MatchDstIP(localGatewayIP).
-		Action().SetDstMAC(localGatewayMAC).
-		Action().GotoTable(l3FwdTable.GetNext()).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+	var flows []binding.Flow
+	for _, localGatewayIP := range localGatewayIPs {
+		flows = append(flows, l3FwdTable.BuildFlow(priorityNormal).MatchProtocol(getIPProtocol(localGatewayIP)).
+			MatchDstMAC(GlobalVirtualMAC).
+			MatchDstIP(localGatewayIP).
+			Action().SetDstMAC(localGatewayMAC).
+			Action().GotoTable(l3FwdTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	return flows
 }
 
 // l3FwdFlowToRemote generates the L3 forward flow on source node to support traffic to remote pods/gateway.
@@ -720,12 +878,12 @@ func (c *client) l3FwdFlowToRemote(
 	tunnelPeer net.IP,
 	tunOFPort uint32,
 	category cookie.Category) binding.Flow {
-	return c.pipeline[l3ForwardingTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+	return c.pipeline[l3ForwardingTable].BuildFlow(priorityNormal).MatchProtocol(getIPProtocol(peerSubnet.IP)).
 		MatchDstIPNet(peerSubnet).
 		Action().DecTTL().
 		// Rewrite src MAC to local gateway MAC and rewrite dst MAC to virtual MAC.
 		Action().SetSrcMAC(localGatewayMAC).
-		Action().SetDstMAC(globalVirtualMAC).
+		Action().SetDstMAC(GlobalVirtualMAC).
 		// Load ofport of the tunnel interface.
 		Action().LoadRegRange(int(portCacheReg), tunOFPort, ofPortRegRange).
 		// Set MAC-known.
@@ -739,13 +897,37 @@ func (c *client) l3FwdFlowToRemote(
 		Done()
 }
 
+// l3FwdFlowToRemoteCluster generates the L3 forward flow on the gateway Node of the cluster to
+// support traffic to the Pods of a remote cluster, which is output to the tunnel port to the
+// gateway Node of the remote cluster.
+func (c *client) l3FwdFlowToRemoteCluster(
+	localGatewayMAC net.HardwareAddr,
+	peerSubnet net.IPNet,
+	tunOFPort uint32,
+	category cookie.Category) binding.Flow {
+	return c.pipeline[l3ForwardingTable].BuildFlow(priorityNormal).MatchProtocol(getIPProtocol(peerSubnet.IP)).
+		MatchDstIPNet(peerSubnet).
+		Action().DecTTL().
+		// Rewrite src MAC to local gateway MAC and rewrite dst MAC to virtual MAC.
+		Action().SetSrcMAC(localGatewayMAC).
+		Action().SetDstMAC(GlobalVirtualMAC).
+		// Load ofport of the tunnel interface. The tunnel destination is
+		// configured on the tunnel port.
+		Action().LoadRegRange(int(portCacheReg), tunOFPort, ofPortRegRange).
+		// Set MAC-known.
+		Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+		Action().GotoTable(conntrackCommitTable).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
 // l3FwdFlowToRemoteViaGW generates the L3 forward flow on source node to support traffic to remote via gateway.
 func (c *client) l3FwdFlowToRemoteViaGW(
 	localGatewayMAC net.HardwareAddr,
 	peerSubnet net.IPNet,
 	category cookie.Category) binding.Flow {
 	l3FwdTable := c.pipeline[l3ForwardingTable]
-	return l3FwdTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+	return l3FwdTable.BuildFlow(priorityNormal).MatchProtocol(getIPProtocol(peerSubnet.IP)).
 		MatchDstIPNet(peerSubnet).
 		Action().DecTTL().
 		Action().SetDstMAC(localGatewayMAC).
@@ -761,10 +943,10 @@ func (c *client) arpResponderFlow(peerGatewayIP net.IP, category cookie.Category
 		MatchARPOp(1).
 		MatchARPTpa(peerGatewayIP).
 		Action().Move(binding.NxmFieldSrcMAC, binding.NxmFieldDstMAC).
-		Action().SetSrcMAC(globalVirtualMAC).
+		Action().SetSrcMAC(GlobalVirtualMAC).
 		Action().LoadARPOperation(2).
 		Action().Move(binding.NxmFieldARPSha, binding.NxmFieldARPTha).
-		Action().SetARPSha(globalVirtualMAC).
+		Action().SetARPSha(GlobalVirtualMAC).
 		Action().Move(binding.NxmFieldARPSpa, binding.NxmFieldARPTpa).
 		Action().SetARPSpa(peerGatewayIP).
 		Action().OutputInPort().
@@ -778,10 +960,10 @@ func (c *client) arpResponderStaticFlow(category cookie.Category) binding.Flow {
 	return c.pipeline[arpResponderTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolARP).
 		MatchARPOp(1).
 		Action().Move(binding.NxmFieldSrcMAC, binding.NxmFieldDstMAC).
-		Action().SetSrcMAC(globalVirtualMAC).
+		Action().SetSrcMAC(GlobalVirtualMAC).
 		Action().LoadARPOperation(2).
 		Action().Move(binding.NxmFieldARPSha, binding.NxmFieldARPTha).
-		Action().SetARPSha(globalVirtualMAC).
+		Action().SetARPSha(GlobalVirtualMAC).
 		Action().Move(binding.NxmFieldARPTpa, swapReg.nxm()).
 		Action().Move(binding.NxmFieldARPSpa, binding.NxmFieldARPTpa).
 		Action().Move(swapReg.nxm(), binding.NxmFieldARPSpa).
@@ -791,18 +973,23 @@ func (c *client) arpResponderStaticFlow(category cookie.Category) binding.Flow {
 
 }
 
-// podIPSpoofGuardFlow generates the flow to check IP traffic sent out from local pod. Traffic from host gateway interface
-// will not be checked, since it might be pod to service traffic or host namespace traffic.
-func (c *client) podIPSpoofGuardFlow(ifIP net.IP, ifMAC net.HardwareAddr, ifOFPort uint32, category cookie.Category) binding.Flow {
+// podIPSpoofGuardFlows generate the flows to check IP traffic sent out from local pod. Traffic from host gateway interface
+// will not be checked, since it might be pod to service traffic or host namespace traffic. One flow is generated for
+// each IP of the Pod.
+func (c *client) podIPSpoofGuardFlows(ifIPs []net.IP, ifMAC net.HardwareAddr, ifOFPort uint32, category cookie.Category) []binding.Flow {
 	ipPipeline := c.pipeline
 	ipSpoofGuardTable := ipPipeline[spoofGuardTable]
-	return ipSpoofGuardTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchInPort(ifOFPort).
-		MatchSrcMAC(ifMAC).
-		MatchSrcIP(ifIP).
-		Action().GotoTable(ipSpoofGuardTable.GetNext()).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+	var flows []binding.Flow
+	for _, ifIP := range ifIPs {
+		flows = append(flows, ipSpoofGuardTable.BuildFlow(priorityNormal).MatchProtocol(getIPProtocol(ifIP)).
+			MatchInPort(ifOFPort).
+			MatchSrcMAC(ifMAC).
+			MatchSrcIP(ifIP).
+			Action().GotoTable(ipSpoofGuardTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	return flows
 }
 
 // serviceHairpinResponseDNATFlow generates the flow which transforms destination
@@ -839,15 +1026,39 @@ func (c *client) arpSpoofGuardFlow(ifIP net.IP, ifMAC net.HardwareAddr, ifOFPort
 		Done()
 }
 
-// gatewayIPSpoofGuardFlow generates the flow to skip spoof guard checking for traffic sent from gateway interface.
-func (c *client) gatewayIPSpoofGuardFlow(gatewayOFPort uint32, category cookie.Category) binding.Flow {
+// gatewayIPSpoofGuardFlows generate the flows to skip spoof guard checking for traffic sent from gateway interface.
+func (c *client) gatewayIPSpoofGuardFlows(gatewayOFPort uint32, category cookie.Category) []binding.Flow {
 	ipPipeline := c.pipeline
 	ipSpoofGuardTable := ipPipeline[spoofGuardTable]
-	return ipSpoofGuardTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchInPort(gatewayOFPort).
-		Action().GotoTable(ipSpoofGuardTable.GetNext()).
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		flows = append(flows, ipSpoofGuardTable.BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+			MatchInPort(gatewayOFPort).
+			Action().GotoTable(ipSpoofGuardTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	return flows
+}
+
+// ipv6NeighborDiscoveryFlows generate the flows to forward IPv6 Neighbor Solicitation and Neighbor Advertisement
+// packets from any port to arpResponderTable, where they are handled in the normal way. This is the IPv6 counterpart
+// of the ARP flows. Neighbor Discovery packets may use link-local source addresses, so they are not checked with the
+// Pod IPv6 spoof guard flows.
+func (c *client) ipv6NeighborDiscoveryFlows(category cookie.Category) []binding.Flow {
+	var flows []binding.Flow
+	for _, icmp6Type := range []uint8{icmp6TypeNeighborSolicitation, icmp6TypeNeighborAdvertisement} {
+		flows = append(flows, c.pipeline[spoofGuardTable].BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolICMPv6).
+			MatchICMPv6Type(icmp6Type).
+			Action().GotoTable(arpResponderTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	flows = append(flows, c.pipeline[arpResponderTable].BuildFlow(priorityLow).MatchProtocol(binding.ProtocolICMPv6).
+		Action().Normal().
 		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+		Done())
+	return flows
 }
 
 // sessionAffinityReselectFlow generates the flow which resubmits the service accessing
@@ -905,41 +1116,97 @@ func (c *client) allowRulesMetricFlows(conjunctionID uint32, ingress bool) []bin
 	// The flow matching 'ct_state=+new' tracks the number of sessions and byte count of the first packet for each
 	// session.
 	// The flow matching 'ct_state=-new' tracks the byte/packet count of an established connection (both directions).
-	return []binding.Flow{
-		c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-			MatchPriority(priorityNormal).
-			MatchCTStateNew(true).
-			MatchCTLabelRange(0, uint64(conjunctionID)<<offset, labelRange).
-			Action().GotoTable(c.pipeline[metricTableID].GetNext()).
-			Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
-			Done(),
-		c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-			MatchPriority(priorityNormal).
-			MatchCTStateNew(false).
-			MatchCTLabelRange(0, uint64(conjunctionID)<<offset, labelRange).
-			Action().GotoTable(c.pipeline[metricTableID].GetNext()).
-			Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
-			Done(),
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		flows = append(flows,
+			c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+				MatchPriority(priorityNormal).
+				MatchCTStateNew(true).
+				MatchCTLabelRange(0, uint64(conjunctionID)<<offset, labelRange).
+				Action().GotoTable(c.pipeline[metricTableID].GetNext()).
+				Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+				Done(),
+			c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+				MatchPriority(priorityNormal).
+				MatchCTStateNew(false).
+				MatchCTLabelRange(0, uint64(conjunctionID)<<offset, labelRange).
+				Action().GotoTable(c.pipeline[metricTableID].GetNext()).
+				Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+				Done(),
+		)
 	}
+	return flows
 }
 
-func (c *client) dropRuleMetricFlow(conjunctionID uint32, ingress bool) binding.Flow {
+// dropRuleMetricFlows generates the flows to count and drop the packets dropped by the rule of conjunctionID in the
+// metric table. If enableReject is true, the packet is also sent to the controller, which will send a reject response
+// back to the source of the packet. If enableLogging is true, the packet is also sent to the controller to generate an
+// audit log entry. If deny tracking is enabled, the packet is also sent to the controller to record the denied
+// connection. Only the packets in ct_state "new" are sent to the controller, at a rate limited by the PacketInMeterIDNP
+// meter if OVS meters are supported. The meter is applied by the metric flow, which counts the packets before the meter
+// drops the ones exceeding the rate, so that the rule metrics include all the dropped packets. Two flows are generated
+// for each IP protocol of the Node if the packets are sent to the controller, otherwise one flow is generated for each
+// IP protocol.
+func (c *client) dropRuleMetricFlows(conjunctionID uint32, ingress bool, enableLogging, enableReject bool) []binding.Flow {
 	metricTableID := IngressMetricTable
 	if !ingress {
 		metricTableID = EgressMetricTable
 	}
-	return c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchPriority(priorityNormal).
-		MatchRegRange(int(marksReg), cnpDropMark, cnpDropMarkRange).
-		MatchReg(int(cnpDropConjunctionIDReg), conjunctionID).
-		Action().Drop().
-		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
-		Done()
+	var customReasons uint32
+	if enableLogging {
+		customReasons |= CustomReasonLogging
+	}
+	if enableReject {
+		customReasons |= CustomReasonReject
+	}
+	if c.enableDenyTracking {
+		customReasons |= CustomReasonDeny
+	}
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		ipCustomReasons := customReasons
+		if ipProtocol == binding.ProtocolIPv6 {
+			// Reject responses can only be sent for IPv4 packets, the IPv6 packets matched by a rule with the
+			// Reject action are dropped without a response.
+			ipCustomReasons &^= CustomReasonReject
+		}
+		newFlowBuilder := func() binding.FlowBuilder {
+			return c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(ipProtocol).
+				MatchPriority(priorityNormal).
+				MatchRegRange(int(marksReg), CNPDropMark, CNPDropMarkRange).
+				MatchReg(int(CNPDropConjunctionIDReg), conjunctionID)
+		}
+		if ipCustomReasons == 0 {
+			flows = append(flows, newFlowBuilder().
+				Action().Drop().
+				Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+				Done())
+			continue
+		}
+		packetInFlowBuilder := newFlowBuilder().MatchCTStateNew(true)
+		if c.ovsMetersAreSupported {
+			packetInFlowBuilder = packetInFlowBuilder.Action().Meter(PacketInMeterIDNP)
+		}
+		// The packet is dropped after being sent to the controller as there is no other action.
+		flows = append(flows,
+			packetInFlowBuilder.
+				Action().LoadRegRange(int(CustomReasonMarkReg), ipCustomReasons, CustomReasonMarkRange).
+				Action().SendToController(uint8(PacketInReasonNP)).
+				Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+				Done(),
+			newFlowBuilder().MatchCTStateNew(false).
+				Action().Drop().
+				Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+				Done())
+	}
+	return flows
 }
 
-// conjunctionActionFlow generates the flow to jump to a specific table if policyRuleConjunction ID is matched. Priority of
-// conjunctionActionFlow is created at priorityLow for k8s network policies, and *priority assigned by PriorityAssigner for AntreaPolicy.
-func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, priority *uint16) binding.Flow {
+// conjunctionActionFlows generate the flows to jump to a specific table if policyRuleConjunction ID is matched. Priority of
+// conjunctionActionFlows is created at priorityLow for k8s network policies, and *priority assigned by PriorityAssigner for AntreaPolicy.
+// If enableLogging is true, the packet is also sent to the controller to generate an audit log entry. One flow is
+// generated for each IP protocol of the Node.
+func (c *client) conjunctionActionFlows(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, priority *uint16, enableLogging bool) []binding.Flow {
 	var ofPriority uint16
 	if priority == nil {
 		ofPriority = priorityLow
@@ -952,34 +1219,49 @@ func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.Tab
 		conjReg = EgressReg
 		labelRange = metricEgressRuleIDRange
 	}
-	return c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
-		MatchConjID(conjunctionID).
-		MatchPriority(ofPriority).
-		Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}). // Traceflow.
-		Action().CT(true, nextTable, CtZone).                                     // CT action requires commit flag if actions other than NAT without arguments are specified.
-		LoadToLabelRange(uint64(conjunctionID), &labelRange).
-		CTDone().
-		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
-		Done()
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		flowBuilder := c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(ipProtocol).
+			MatchConjID(conjunctionID).
+			MatchPriority(ofPriority).
+			Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}) // Traceflow.
+		if enableLogging {
+			flowBuilder = flowBuilder.
+				Action().LoadRegRange(int(CustomReasonMarkReg), CustomReasonLogging, CustomReasonMarkRange).
+				Action().SendToController(uint8(PacketInReasonNP))
+		}
+		flows = append(flows, flowBuilder.
+			Action().CT(true, nextTable, CtZone). // CT action requires commit flag if actions other than NAT without arguments are specified.
+			LoadToLabelRange(uint64(conjunctionID), &labelRange).
+			CTDone().
+			Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+			Done())
+	}
+	return flows
 }
 
-// conjunctionActionDropFlow generates the flow to mark the packet to be dropped if policyRuleConjunction ID is matched.
-// Any matched flow will be dropped in corresponding metric tables.
-func (c *client) conjunctionActionDropFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16) binding.Flow {
+// conjunctionActionDropFlows generate the flows to mark the packet to be dropped if policyRuleConjunction ID is matched.
+// Any matched flow will be dropped in corresponding metric tables, which also send the packet to the controller if
+// needed, see dropRuleMetricFlows. One flow is generated for each IP protocol of the Node.
+func (c *client) conjunctionActionDropFlows(conjunctionID uint32, tableID binding.TableIDType, priority *uint16) []binding.Flow {
 	ofPriority := *priority
 	metricTableID := IngressMetricTable
 	if _, ok := egressTables[tableID]; ok {
 		metricTableID = EgressMetricTable
 	}
-	// We do not drop the packet immediately but send the packet to the metric table to update the rule metrics.
-	return c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
-		MatchConjID(conjunctionID).
-		MatchPriority(ofPriority).
-		Action().LoadRegRange(int(cnpDropConjunctionIDReg), conjunctionID, binding.Range{0, 31}).
-		Action().LoadRegRange(int(marksReg), cnpDropMark, cnpDropMarkRange).
-		Action().GotoTable(metricTableID).
-		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
-		Done()
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		// We do not drop the packet immediately but send the packet to the metric table to update the rule metrics.
+		flows = append(flows, c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(ipProtocol).
+			MatchConjID(conjunctionID).
+			MatchPriority(ofPriority).
+			Action().LoadRegRange(int(CNPDropConjunctionIDReg), conjunctionID, binding.Range{0, 31}).
+			Action().LoadRegRange(int(marksReg), CNPDropMark, CNPDropMarkRange).
+			Action().GotoTable(metricTableID).
+			Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+			Done())
+	}
+	return flows
 }
 
 func (c *client) Disconnect() error {
@@ -996,82 +1278,213 @@ func (c *client) establishedConnectionFlows(category cookie.Category) (flows []b
 	// matching the NetworkPolicy rules. Packets in the established connections need not to be checked with the
 	// egressRuleTable or the egressDropTable.
 	egressDropTable := c.pipeline[EgressDefaultTable]
-	egressEstFlow := c.pipeline[EgressRuleTable].BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
-		MatchCTStateNew(false).MatchCTStateEst(true).
-		Action().GotoTable(egressDropTable.GetNext()).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
 	// ingressDropTable checks the destination address of packets, and drops packets sent to the AppliedToGroup but not
 	// matching the NetworkPolicy rules. Packets in the established connections need not to be checked with the
 	// ingressRuleTable or ingressDropTable.
 	ingressDropTable := c.pipeline[IngressDefaultTable]
-	ingressEstFlow := c.pipeline[IngressRuleTable].BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
-		MatchCTStateNew(false).MatchCTStateEst(true).
-		Action().GotoTable(ingressDropTable.GetNext()).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
-	allEstFlows := []binding.Flow{egressEstFlow, ingressEstFlow}
-	if !c.enableAntreaPolicy {
-		return allEstFlows
-	}
-	apFlows := make([]binding.Flow, len(GetAntreaPolicyEgressTables())+len(GetAntreaPolicyIngressTables()))
-	for i, tableID := range GetAntreaPolicyEgressTables() {
-		apEgressEstFlow := c.pipeline[tableID].BuildFlow(priorityTopAntreaPolicy).MatchProtocol(binding.ProtocolIP).
+	var allEstFlows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		egressEstFlow := c.pipeline[EgressRuleTable].BuildFlow(priorityHigh).MatchProtocol(ipProtocol).
 			MatchCTStateNew(false).MatchCTStateEst(true).
 			Action().GotoTable(egressDropTable.GetNext()).
 			Cookie(c.cookieAllocator.Request(category).Raw()).
 			Done()
-		apFlows[i] = apEgressEstFlow
-	}
-	for i, tableID := range GetAntreaPolicyIngressTables() {
-		apIngressEstFlow := c.pipeline[tableID].BuildFlow(priorityTopAntreaPolicy).MatchProtocol(binding.ProtocolIP).
+		ingressEstFlow := c.pipeline[IngressRuleTable].BuildFlow(priorityHigh).MatchProtocol(ipProtocol).
 			MatchCTStateNew(false).MatchCTStateEst(true).
 			Action().GotoTable(ingressDropTable.GetNext()).
 			Cookie(c.cookieAllocator.Request(category).Raw()).
 			Done()
-		apFlows[i+len(GetAntreaPolicyEgressTables())] = apIngressEstFlow
+		allEstFlows = append(allEstFlows, egressEstFlow, ingressEstFlow)
+	}
+	if !c.enableAntreaPolicy {
+		return allEstFlows
+	}
+	var apFlows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		for _, tableID := range GetAntreaPolicyEgressTables() {
+			apEgressEstFlow := c.pipeline[tableID].BuildFlow(priorityTopAntreaPolicy).MatchProtocol(ipProtocol).
+				MatchCTStateNew(false).MatchCTStateEst(true).
+				Action().GotoTable(egressDropTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(category).Raw()).
+				Done()
+			apFlows = append(apFlows, apEgressEstFlow)
+		}
+		for _, tableID := range GetAntreaPolicyIngressTables() {
+			apIngressEstFlow := c.pipeline[tableID].BuildFlow(priorityTopAntreaPolicy).MatchProtocol(ipProtocol).
+				MatchCTStateNew(false).MatchCTStateEst(true).
+				Action().GotoTable(ingressDropTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(category).Raw()).
+				Done()
+			apFlows = append(apFlows, apIngressEstFlow)
+		}
 	}
 	allEstFlows = append(allEstFlows, apFlows...)
 	return allEstFlows
 }
 
+// dnsInterceptFlows generates the flows to send the DNS responses received by
+// local Pods over UDP and TCP to the controller, from which the IPs of FQDNs
+// used in Antrea-native policy rules are learned. The DNS responses are held
+// by the flows rather than forwarded, so that the controller can reinject them
+// with ReinjectDNSResponse after the flows of the rules are updated with the
+// learned IPs, otherwise the Pods may connect to the IPs before the connections
+// are allowed.
+// The DNS responses are intercepted before NAT: they are looked up in
+// conntrackTable without NAT, and only the replies of established connections
+// are sent to the controller. The reinjected DNS responses are resubmitted to
+// conntrackTable with dnsResponseMark, so that they are processed with NAT
+// like other packets and their ct_state, ct_mark and ct_label are restored.
+// The DNS responses which are not intercepted are looked up again with NAT.
+func (c *client) dnsInterceptFlows(category cookie.Category) []binding.Flow {
+	connectionTrackTable := c.pipeline[conntrackTable]
+	connectionTrackStateTable := c.pipeline[conntrackStateTable]
+	var flows []binding.Flow
+	for _, ipProtocol := range c.ipProtocols {
+		// The Pod CIDRs are not allocated by Antrea in networkPolicyOnly mode,
+		// in which case the DNS responses are not matched by destination.
+		localSubnet := c.nodeConfig.PodCIDR
+		if ipProtocol == binding.ProtocolIPv6 {
+			localSubnet = c.nodeConfig.PodIPv6CIDR
+		}
+		for _, matchSrcPort := range []func(fb binding.FlowBuilder) binding.FlowBuilder{
+			func(fb binding.FlowBuilder) binding.FlowBuilder { return fb.MatchUDPSrcPort(dnsPort) },
+			func(fb binding.FlowBuilder) binding.FlowBuilder { return fb.MatchTCPSrcPort(dnsPort) },
+		} {
+			ctFlow := matchSrcPort(connectionTrackTable.BuildFlow(priorityDNSIntercept).MatchProtocol(ipProtocol)).
+				MatchRegRange(int(marksReg), 0, dnsResponseMarkRange)
+			interceptFlow := matchSrcPort(connectionTrackStateTable.BuildFlow(priorityDNSIntercept).MatchProtocol(ipProtocol)).
+				MatchRegRange(int(marksReg), 0, dnsResponseMarkRange).
+				MatchCTStateNew(false).MatchCTStateEst(true).MatchCTStateRpl(true)
+			if localSubnet != nil {
+				interceptFlow = interceptFlow.MatchDstIPNet(*localSubnet)
+			}
+			natFlow := matchSrcPort(connectionTrackStateTable.BuildFlow(priorityDNSIntercept-1).MatchProtocol(ipProtocol)).
+				MatchRegRange(int(marksReg), 0, dnsResponseMarkRange).
+				Action().LoadRegRange(int(marksReg), dnsResponseMark, dnsResponseMarkRange)
+			if c.enableProxy {
+				natFlow = natFlow.Action().CT(false, connectionTrackTable.GetNext(), CtZone).NAT().CTDone()
+			} else {
+				natFlow = natFlow.Action().CT(false, connectionTrackTable.GetNext(), CtZone).CTDone()
+			}
+			flows = append(flows,
+				// Look up the DNS response in conntrack without NAT.
+				ctFlow.Action().CT(false, conntrackStateTable, CtZone).CTDone().
+					Cookie(c.cookieAllocator.Request(category).Raw()).
+					Done(),
+				// Send the DNS response of an established connection to local Pods to the controller.
+				interceptFlow.Action().LoadRegRange(int(CustomReasonMarkReg), CustomReasonDNS, CustomReasonMarkRange).
+					Action().LoadRegRange(int(marksReg), dnsResponseMark, dnsResponseMarkRange).
+					Action().SendToControllerWithFullPacket(uint8(PacketInReasonNP)).
+					Cookie(c.cookieAllocator.Request(category).Raw()).
+					Done(),
+				// Look up the other DNS responses in conntrack again, with NAT if AntreaProxy is enabled.
+				natFlow.Cookie(c.cookieAllocator.Request(category).Raw()).
+					Done())
+		}
+	}
+	return flows
+}
+
 func (c *client) addFlowMatch(fb binding.FlowBuilder, matchType int, matchValue interface{}) binding.FlowBuilder {
 	switch matchType {
 	case MatchDstIP:
-		fb = fb.MatchProtocol(binding.ProtocolIP).MatchDstIP(matchValue.(net.IP))
+		ipValue := matchValue.(net.IP)
+		fb = fb.MatchProtocol(getIPProtocol(ipValue)).MatchDstIP(ipValue)
 	case MatchDstIPNet:
-		fb = fb.MatchProtocol(binding.ProtocolIP).MatchDstIPNet(matchValue.(net.IPNet))
+		ipNetValue := matchValue.(net.IPNet)
+		fb = fb.MatchProtocol(getIPProtocol(ipNetValue.IP)).MatchDstIPNet(ipNetValue)
 	case MatchSrcIP:
-		fb = fb.MatchProtocol(binding.ProtocolIP).MatchSrcIP(matchValue.(net.IP))
+		ipValue := matchValue.(net.IP)
+		fb = fb.MatchProtocol(getIPProtocol(ipValue)).MatchSrcIP(ipValue)
 	case MatchSrcIPNet:
-		fb = fb.MatchProtocol(binding.ProtocolIP).MatchSrcIPNet(matchValue.(net.IPNet))
+		ipNetValue := matchValue.(net.IPNet)
+		fb = fb.MatchProtocol(getIPProtocol(ipNetValue.IP)).MatchSrcIPNet(ipNetValue)
 	case MatchDstOFPort:
 		// ofport number in NXM_NX_REG1 is used in ingress rule to match packets sent to local Pod.
-		fb = fb.MatchProtocol(binding.ProtocolIP).MatchReg(int(portCacheReg), uint32(matchValue.(int32)))
+		fb = c.matchIPProtocols(fb).MatchReg(int(portCacheReg), uint32(matchValue.(int32)))
 	case MatchSrcOFPort:
-		fb = fb.MatchProtocol(binding.ProtocolIP).MatchInPort(uint32(matchValue.(int32)))
-	case MatchTCPDstPort:
-		fb = fb.MatchProtocol(binding.ProtocolTCP)
-		portValue := matchValue.(uint16)
-		if portValue > 0 {
-			fb = fb.MatchTCPDstPort(portValue)
+		fb = c.matchIPProtocols(fb).MatchInPort(uint32(matchValue.(int32)))
+	case MatchTCPDstPort, MatchTCPv6DstPort:
+		if matchType == MatchTCPv6DstPort {
+			fb = fb.MatchProtocol(binding.ProtocolTCPv6)
+		} else {
+			fb = fb.MatchProtocol(binding.ProtocolTCP)
+		}
+		switch v := matchValue.(type) {
+		case uint16:
+			if v > 0 {
+				fb = fb.MatchTCPDstPort(v)
+			}
+		case portMaskRange:
+			fb = fb.MatchTCPDstPortWithMask(v.port, v.mask)
+		}
+	case MatchUDPDstPort, MatchUDPv6DstPort:
+		if matchType == MatchUDPv6DstPort {
+			fb = fb.MatchProtocol(binding.ProtocolUDPv6)
+		} else {
+			fb = fb.MatchProtocol(binding.ProtocolUDP)
+		}
+		switch v := matchValue.(type) {
+		case uint16:
+			if v > 0 {
+				fb = fb.MatchUDPDstPort(v)
+			}
+		case portMaskRange:
+			fb = fb.MatchUDPDstPortWithMask(v.port, v.mask)
+		}
+	case MatchSCTPDstPort, MatchSCTPv6DstPort:
+		if matchType == MatchSCTPv6DstPort {
+			fb = fb.MatchProtocol(binding.ProtocolSCTPv6)
+		} else {
+			fb = fb.MatchProtocol(binding.ProtocolSCTP)
 		}
-	case MatchUDPDstPort:
-		fb = fb.MatchProtocol(binding.ProtocolUDP)
-		portValue := matchValue.(uint16)
-		if portValue > 0 {
-			fb = fb.MatchUDPDstPort(portValue)
+		switch v := matchValue.(type) {
+		case uint16:
+			if v > 0 {
+				fb = fb.MatchSCTPDstPort(v)
+			}
+		case portMaskRange:
+			fb = fb.MatchSCTPDstPortWithMask(v.port, v.mask)
 		}
-	case MatchSCTPDstPort:
-		fb = fb.MatchProtocol(binding.ProtocolSCTP)
-		portValue := matchValue.(uint16)
-		if portValue > 0 {
-			fb = fb.MatchSCTPDstPort(portValue)
+	case MatchICMP:
+		icmp := matchValue.(icmpTypeCode)
+		fb = fb.MatchProtocol(binding.ProtocolICMP)
+		if icmp.icmpType != nil {
+			fb = fb.MatchICMPType(*icmp.icmpType)
+		}
+		if icmp.icmpCode != nil {
+			fb = fb.MatchICMPCode(*icmp.icmpCode)
+		}
+	case MatchICMPv6:
+		icmp := matchValue.(icmpTypeCode)
+		fb = fb.MatchProtocol(binding.ProtocolICMPv6)
+		if icmp.icmpType != nil {
+			fb = fb.MatchICMPv6Type(*icmp.icmpType)
+		}
+		if icmp.icmpCode != nil {
+			fb = fb.MatchICMPv6Code(*icmp.icmpCode)
 		}
 	}
 	return fb
 }
 
+// matchIPProtocols matches the IP protocol of the Node if the Node has only IPv4 Pod CIDR. For a dual-stack Node, no
+// protocol is matched, so that both IPv4 and IPv6 packets are matched.
+func (c *client) matchIPProtocols(fb binding.FlowBuilder) binding.FlowBuilder {
+	if len(c.ipProtocols) == 1 {
+		return fb.MatchProtocol(c.ipProtocols[0])
+	}
+	return fb
+}
+
+// getIPProtocol returns the IP protocol matching the address family of the provided IP.
+func getIPProtocol(ip net.IP) binding.Protocol {
+	if ip.To4() == nil {
+		return binding.ProtocolIPv6
+	}
+	return binding.ProtocolIP
+}
+
 // conjunctionExceptionFlow generates the flow to jump to a specific table if both policyRuleConjunction ID and except address are matched.
 // Keeping this for reference to generic exception flow.
 func (c *client) conjunctionExceptionFlow(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, matchKey int, matchValue interface{}) binding.Flow {
@@ -1103,28 +1516,44 @@ func (c *client) conjunctiveMatchFlow(tableID binding.TableIDType, matchKey int,
 	return fb.Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).Done()
 }
 
-// defaultDropFlow generates the flow to drop packets if the match condition is matched.
+// defaultDropFlow generates the flow to drop packets if the match condition is matched. If deny tracking is enabled,
+// the packet is sent to the controller before being dropped, so the denied connection can be recorded, at a rate
+// limited by the PacketInMeterIDNP meter if OVS meters are supported.
 func (c *client) defaultDropFlow(tableID binding.TableIDType, matchKey int, matchValue interface{}) binding.Flow {
-	fb := c.pipeline[tableID].BuildFlow(priorityNormal)
-	return c.addFlowMatch(fb, matchKey, matchValue).
-		Action().Drop().
-		Cookie(c.cookieAllocator.Request(cookie.Default).Raw()).
+	fb := c.addFlowMatch(c.pipeline[tableID].BuildFlow(priorityNormal), matchKey, matchValue)
+	if c.enableDenyTracking {
+		// The packet is dropped as no output action follows. The packets exceeding the rate of the meter are dropped
+		// by the meter directly without being sent to the controller.
+		if c.ovsMetersAreSupported {
+			fb = fb.Action().Meter(PacketInMeterIDNP)
+		}
+		fb = fb.Action().LoadRegRange(int(CustomReasonMarkReg), CustomReasonDeny, CustomReasonMarkRange).
+			Action().SendToController(uint8(PacketInReasonNP))
+	} else {
+		fb = fb.Action().Drop()
+	}
+	return fb.Cookie(c.cookieAllocator.Request(cookie.Default).Raw()).
 		Done()
 }
 
-// localProbeFlow generates the flow to forward packets to conntrackCommitTable. The packets are sent from Node to probe the liveness/readiness of local Pods.
-func (c *client) localProbeFlow(localGatewayIP net.IP, category cookie.Category) binding.Flow {
-	return c.pipeline[IngressRuleTable].BuildFlow(priorityHigh).
-		MatchProtocol(binding.ProtocolIP).
-		MatchSrcIP(localGatewayIP).
-		Action().GotoTable(conntrackCommitTable).
-		Cookie(c.cookieAllocator.Request(category).Raw()).
-		Done()
+// localProbeFlows generate the flows to forward packets to conntrackCommitTable. The packets are sent from Node to probe the liveness/readiness of local Pods.
+// One flow is generated for each IP of the local gateway.
+func (c *client) localProbeFlows(localGatewayIPs []net.IP, category cookie.Category) []binding.Flow {
+	var flows []binding.Flow
+	for _, localGatewayIP := range localGatewayIPs {
+		flows = append(flows, c.pipeline[IngressRuleTable].BuildFlow(priorityHigh).
+			MatchProtocol(getIPProtocol(localGatewayIP)).
+			MatchSrcIP(localGatewayIP).
+			Action().GotoTable(conntrackCommitTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done())
+	}
+	return flows
 }
 
 func (c *client) bridgeAndUplinkFlows(uplinkOfport uint32, bridgeLocalPort uint32, nodeIP net.IP, localSubnet net.IPNet, category cookie.Category) []binding.Flow {
 	snatIPRange := &binding.IPRange{StartIP: nodeIP, EndIP: nodeIP}
-	vMACInt, _ := strconv.ParseUint(strings.Replace(globalVirtualMAC.String(), ":", "", -1), 16, 64)
+	vMACInt, _ := strconv.ParseUint(strings.Replace(GlobalVirtualMAC.String(), ":", "", -1), 16, 64)
 	ctStateNext := dnatTable
 	if c.enableProxy {
 		ctStateNext = endpointDNATTable
@@ -1245,6 +1674,120 @@ func (c *client) l3ToExternalFlows(nodeIP net.IP, localSubnet net.IPNet, outputP
 	return flows
 }
 
+// snatCommonFlows generates the default flows for performing SNAT with Egress
+// IPs for the traffic to the external network. The flows identify the packets
+// from local Pods and from remote Nodes which are sent to the external network,
+// and send them to snatTable, where the SNAT IPs are looked up for the packets.
+func (c *client) snatCommonFlows(nodeIP net.IP, localSubnet net.IPNet, localGatewayMAC net.HardwareAddr, category cookie.Category) []binding.Flow {
+	l3FwdTable := c.pipeline[l3ForwardingTable]
+	nextTable := l3FwdTable.GetNext()
+	return []binding.Flow{
+		// Forward the packet to L2ForwardingCalc table if it is the reply of a connection initiated from the host
+		// gateway, e.g. NodePort Service traffic.
+		l3FwdTable.BuildFlow(priorityNormal).
+			MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromLocal, binding.Range{0, 15}).
+			MatchCTMark(gatewayCTMark).
+			Action().GotoTable(nextTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Forward the packet to L2ForwardingCalc table if it is sent to the Node IP.
+		l3FwdTable.BuildFlow(priorityLow).
+			MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromLocal, binding.Range{0, 15}).
+			MatchDstIP(nodeIP).
+			Action().GotoTable(nextTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Forward the packet to L2ForwardingCalc table if it is sent to a local Pod. This flow entry has a low
+		// priority to avoid overlapping with those packets received from tunnel port.
+		l3FwdTable.BuildFlow(priorityLow).
+			MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromLocal, binding.Range{0, 15}).
+			MatchDstIPNet(localSubnet).
+			Action().GotoTable(nextTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Send the packet from a local Pod to snatTable if it is not filtered by other flow entries in L3Forwarding
+		// table, i.e. it is sent to the external network.
+		l3FwdTable.BuildFlow(prioritySNAT).
+			MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromLocal, binding.Range{0, 15}).
+			Action().GotoTable(snatTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Send the packet received from the tunnel port to snatTable if it is not destined for a local Pod or the
+		// host gateway. The packet is tunnelled from a remote Node to be SNAT'd with an Egress IP on this Node, so
+		// rewrite its destination MAC to the local gateway MAC.
+		l3FwdTable.BuildFlow(prioritySNAT).
+			MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+			Action().SetDstMAC(localGatewayMAC).
+			Action().GotoTable(snatTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Drop the new connections tunnelled from remote Nodes if no Egress IP on this Node is applied to them.
+		c.pipeline[snatTable].BuildFlow(priorityLow).
+			MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+			MatchCTStateNew(true).MatchCTStateTrk(true).
+			Action().Drop().
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+	}
+}
+
+// snatRuleFlow generates the flow that applies the SNAT IP of an Egress to the traffic from a local Pod to the
+// external network. If the SNAT IP is on the local Node (snatMark is not 0), it sets the packet mark to the ID of the
+// SNAT IP, and the packet will be SNAT'd by the host network stack. Otherwise, the packet is tunnelled to the Node
+// which owns the SNAT IP.
+func (c *client) snatRuleFlow(ofPort uint32, snatIP net.IP, snatMark uint32, localGatewayMAC net.HardwareAddr) binding.Flow {
+	snatTable := c.pipeline[snatTable]
+	if snatMark != 0 {
+		// Only the first packet of a connection needs to be marked, as SNAT is done by iptables based on conntrack.
+		return snatTable.BuildFlow(priorityNormal).
+			MatchProtocol(binding.ProtocolIP).
+			MatchCTStateNew(true).MatchCTStateTrk(true).
+			MatchInPort(ofPort).
+			Action().LoadRange(binding.NxmFieldPktMark, uint64(snatMark), snatPktMarkRange).
+			Action().GotoTable(snatTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+			Done()
+	}
+	return snatTable.BuildFlow(priorityNormal).
+		MatchProtocol(binding.ProtocolIP).
+		MatchInPort(ofPort).
+		Action().DecTTL().
+		// Rewrite src MAC to local gateway MAC and rewrite dst MAC to virtual MAC.
+		Action().SetSrcMAC(localGatewayMAC).
+		Action().SetDstMAC(GlobalVirtualMAC).
+		// Load ofport of the default tunnel interface.
+		Action().LoadRegRange(int(portCacheReg), config.DefaultTunOFPort, ofPortRegRange).
+		// Set MAC-known.
+		Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+		// Flow based tunnel. The tunnel destination is the SNAT IP, which is configured on the remote Node.
+		Action().SetTunnelDst(snatIP).
+		// Bypass l2ForwardingCalcTable and tables for ingress rules.
+		Action().GotoTable(conntrackCommitTable).
+		Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+		Done()
+}
+
+// snatRemotePodFlow generates the flow that sets the packet mark to the ID of a SNAT IP on the local Node, for the
+// new connections tunnelled from a remote Pod to which the SNAT IP is applied.
+func (c *client) snatRemotePodFlow(podIP net.IP, snatMark uint32) binding.Flow {
+	snatTable := c.pipeline[snatTable]
+	return snatTable.BuildFlow(priorityNormal).
+		MatchProtocol(binding.ProtocolIP).
+		MatchRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+		MatchCTStateNew(true).MatchCTStateTrk(true).
+		MatchSrcIP(podIP).
+		Action().LoadRange(binding.NxmFieldPktMark, uint64(snatMark), snatPktMarkRange).
+		Action().GotoTable(snatTable.GetNext()).
+		Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+		Done()
+}
+
 // loadBalancerServiceFromOutsideFlow generates the flow to forward LoadBalancer service traffic from outside node
 // to gateway. kube-proxy will then handle the traffic.
 func (c *client) loadBalancerServiceFromOutsideFlow(uplinkPort uint32, gwPort uint32, svcIP net.IP, svcPort uint16, protocol binding.Protocol) binding.Flow {
@@ -1264,6 +1807,96 @@ func (c *client) loadBalancerServiceFromOutsideFlow(uplinkPort uint32, gwPort ui
 		Done()
 }
 
+// loadBalancerServiceDSRFlows generates the flows for the traffic sent to the ingress IP of a LoadBalancer Service
+// in DSR mode:
+// 1) The traffic from outside the cluster, which is routed to the host gateway by the host network of the ingress
+//    Node, is marked with dsrMark and sent to serviceLBTable without connection tracking, as its reply traffic
+//    doesn't go through the ingress Node.
+// 2) The traffic tunneled from the ingress Node, whose destination IP is the selected Endpoint IP, restores the
+//    selected Endpoint from the destination IP and the tun_metadata field, and the ingress IP as the destination
+//    IP, so that it's DNAT'd to the Endpoint on the Endpoint Node, and the reply traffic is reverse translated to
+//    be sent from the ingress IP.
+func (c *client) loadBalancerServiceDSRFlows(gatewayOFPort, tunnelOFPort uint32, svcIP net.IP, svcPort uint16, protocol binding.Protocol) []binding.Flow {
+	classifierTable := c.pipeline[ClassifierTable]
+	fromGatewayFlowBuilder := classifierTable.BuildFlow(priorityHigh).MatchInPort(gatewayOFPort)
+	fromTunnelFlowBuilder := classifierTable.BuildFlow(priorityHigh).MatchInPort(tunnelOFPort)
+	if protocol == binding.ProtocolTCP {
+		fromGatewayFlowBuilder = fromGatewayFlowBuilder.MatchTCPDstPort(svcPort)
+		fromTunnelFlowBuilder = fromTunnelFlowBuilder.MatchTCPDstPort(svcPort)
+	} else if protocol == binding.ProtocolUDP {
+		fromGatewayFlowBuilder = fromGatewayFlowBuilder.MatchUDPDstPort(svcPort)
+		fromTunnelFlowBuilder = fromTunnelFlowBuilder.MatchUDPDstPort(svcPort)
+	} else if protocol == binding.ProtocolSCTP {
+		fromGatewayFlowBuilder = fromGatewayFlowBuilder.MatchSCTPDstPort(svcPort)
+		fromTunnelFlowBuilder = fromTunnelFlowBuilder.MatchSCTPDstPort(svcPort)
+	}
+	endpointIPRegName := fmt.Sprintf("%s%d", binding.NxmFieldReg, endpointIPReg)
+	endpointPortRegName := fmt.Sprintf("%s%d", binding.NxmFieldReg, endpointPortReg)
+	endpointPortTunMetadataName := fmt.Sprintf("%s%d", binding.NxmFieldTunMetadata, dsrEndpointPortTunMetadataIndex)
+	return []binding.Flow{
+		fromGatewayFlowBuilder.
+			MatchDstIP(svcIP).
+			Action().LoadRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+			Action().LoadRegRange(int(marksReg), dsrMark, dsrMarkRange).
+			Action().LoadRegRange(int(serviceLearnReg), marksRegServiceNeedLB, serviceLearnRegRange).
+			Action().GotoTable(serviceLBTable).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+		fromTunnelFlowBuilder.
+			MatchTunMetadata(dsrServiceIPTunMetadataIndex, binary.BigEndian.Uint32(svcIP.To4())).
+			Action().MoveRange(binding.NxmFieldIPDst, endpointIPRegName, endpointIPRegRange, endpointIPRegRange).
+			Action().MoveRange(endpointPortTunMetadataName, endpointPortRegName, endpointPortRegRange, endpointPortRegRange).
+			Action().LoadRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+			Action().SetDstIP(svcIP).
+			Action().LoadRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+			Action().LoadRegRange(int(marksReg), macRewriteMark, macRewriteMarkRange).
+			Action().GotoTable(conntrackTable).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+	}
+}
+
+// loadBalancerDSRFlows generates the flows shared by the LoadBalancer Services in DSR mode:
+// 1) The DSR traffic whose selected Endpoint is a local Pod is DNAT'd by the endpointDNATTable flows like the
+//    traffic in NAT mode.
+// 2) The other DSR traffic stores its destination IP, i.e. the ingress IP, and the selected Endpoint port in the
+//    tun_metadata fields, and uses the selected Endpoint IP as the destination IP, with which it's forwarded to the
+//    Endpoint Node via the tunnel by the l3ForwardingTable flows.
+// 3) The traffic tunneled from the ingress Nodes, whose Endpoint has been selected, bypasses the session affinity.
+func (c *client) loadBalancerDSRFlows(localSubnet net.IPNet) []binding.Flow {
+	endpointDNATFlowTable := c.pipeline[endpointDNATTable]
+	prefixLength, _ := localSubnet.Mask.Size()
+	subnetVal := binary.BigEndian.Uint32(localSubnet.IP.To4()) >> uint(32-prefixLength)
+	endpointIPRegName := fmt.Sprintf("%s%d", binding.NxmFieldReg, endpointIPReg)
+	endpointPortRegName := fmt.Sprintf("%s%d", binding.NxmFieldReg, endpointPortReg)
+	serviceIPTunMetadataName := fmt.Sprintf("%s%d", binding.NxmFieldTunMetadata, dsrServiceIPTunMetadataIndex)
+	endpointPortTunMetadataName := fmt.Sprintf("%s%d", binding.NxmFieldTunMetadata, dsrEndpointPortTunMetadataIndex)
+	return []binding.Flow{
+		endpointDNATFlowTable.BuildFlow(priorityHigh+1).MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), dsrMark, dsrMarkRange).
+			MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+			MatchRegRange(int(endpointIPReg), subnetVal, binding.Range{uint32(32 - prefixLength), 31}).
+			Action().LoadRegRange(int(marksReg), 0, dsrMarkRange).
+			Action().ResubmitToTable(endpointDNATTable).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+		endpointDNATFlowTable.BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), dsrMark, dsrMarkRange).
+			MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+			Action().MoveRange(binding.NxmFieldIPDst, serviceIPTunMetadataName, endpointIPRegRange, endpointIPRegRange).
+			Action().MoveRange(endpointPortRegName, endpointPortTunMetadataName, endpointPortRegRange, endpointPortRegRange).
+			Action().MoveRange(endpointIPRegName, binding.NxmFieldIPDst, endpointIPRegRange, endpointIPRegRange).
+			Action().GotoTable(l3ForwardingTable).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+		c.pipeline[sessionAffinityTable].BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
+			MatchRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+			MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+	}
+}
+
 // serviceLearnFlow generates the flow with learn action which adds new flows in
 // sessionAffinityTable according to the Endpoint selection decision.
 func (c *client) serviceLearnFlow(groupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol, affinityTimeout uint16) binding.Flow {
@@ -1360,7 +1993,13 @@ func (c *client) hairpinSNATFlow(endpointIP net.IP) binding.Flow {
 // serviceLBTable to trigger the learn flow, the learn flow will then send packets
 // to endpointDNATTable. Otherwise, buckets will resubmit packets to
 // endpointDNATTable directly.
-func (c *client) serviceEndpointGroup(groupID binding.GroupIDType, withSessionAffinity bool, endpoints ...proxy.Endpoint) binding.Group {
+// For the random algorithm, each Endpoint is a bucket whose weight is the one of
+// the Endpoint IP in weights, or defaultEndpointWeight. For the hash based
+// algorithms, the group has a single bucket which stores the slot of
+// the packet computed by the multipath action in endpointPortReg, and the
+// groupID in endpointIPReg, then resubmits the packet to serviceLBTable, in
+// which the flows returned by this function select the Endpoint of the slot.
+func (c *client) serviceEndpointGroup(groupID binding.GroupIDType, withSessionAffinity bool, lbAlgorithm types.LBAlgorithm, weights map[string]uint16, endpoints ...proxy.Endpoint) (binding.Group, []binding.Flow) {
 	group := c.bridge.CreateGroup(groupID).ResetBuckets()
 	var resubmitTableID binding.TableIDType
 	var lbResultMark uint32
@@ -1372,12 +2011,34 @@ func (c *client) serviceEndpointGroup(groupID binding.GroupIDType, withSessionAf
 		lbResultMark = marksRegServiceSelected
 	}
 
+	if lbAlgorithm.IsHashBased() && len(endpoints) > 0 {
+		slots, fields, algorithm := hashSlots(lbAlgorithm, endpoints)
+		if slots != nil {
+			group = group.Bucket().Weight(defaultEndpointWeight).
+				Multipath(fields, algorithm, uint16(len(slots)), int(endpointPortReg), endpointPortRegRange).
+				LoadReg(int(endpointIPReg), uint32(groupID)).
+				LoadRegRange(int(serviceLearnReg), marksRegServiceHashed, serviceLearnRegRange).
+				ResubmitToTable(serviceLBTable).
+				Done()
+			slotFlows := make([]binding.Flow, 0, len(slots))
+			for slot, endpoint := range slots {
+				slotFlows = append(slotFlows, c.serviceLBSlotFlow(groupID, uint16(slot), endpoint, resubmitTableID, lbResultMark))
+			}
+			return group, slotFlows
+		}
+		klog.Warningf("Too many Endpoints for the hash based LB algorithm of group %d, falling back to the random selection", groupID)
+	}
+
 	for _, endpoint := range endpoints {
 		endpointPort, _ := endpoint.Port()
 		endpointIP := net.ParseIP(endpoint.IP()).To4()
 		ipVal := binary.BigEndian.Uint32(endpointIP)
 		portVal := uint16(endpointPort)
-		group = group.Bucket().Weight(100).
+		weight := defaultEndpointWeight
+		if w, ok := weights[endpoint.IP()]; ok {
+			weight = w
+		}
+		group = group.Bucket().Weight(weight).
 			LoadReg(int(endpointIPReg), ipVal).
 			LoadRegRange(int(endpointPortReg), uint32(portVal), endpointPortRegRange).
 			LoadRegRange(int(serviceLearnReg), lbResultMark, serviceLearnRegRange).
@@ -1385,7 +2046,26 @@ func (c *client) serviceEndpointGroup(groupID binding.GroupIDType, withSessionAf
 			ResubmitToTable(resubmitTableID).
 			Done()
 	}
-	return group
+	return group, nil
+}
+
+// serviceLBSlotFlow generates the flow which selects the Endpoint of a slot of
+// the group with the hash based LB algorithm. Its actions are the same as the
+// ones of the buckets of the groups with the random LB algorithm.
+func (c *client) serviceLBSlotFlow(groupID binding.GroupIDType, slot uint16, endpoint proxy.Endpoint, resubmitTableID binding.TableIDType, lbResultMark uint32) binding.Flow {
+	endpointPort, _ := endpoint.Port()
+	endpointIP := net.ParseIP(endpoint.IP()).To4()
+	unionVal := (marksRegServiceHashed << endpointPortRegRange.Length()) + uint32(slot)
+	return c.pipeline[serviceLBTable].BuildFlow(priorityNormal).
+		Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+		MatchReg(int(endpointIPReg), uint32(groupID)).
+		MatchRegRange(int(endpointPortReg), unionVal, binding.Range{0, 18}).
+		Action().LoadRegRange(int(endpointIPReg), binary.BigEndian.Uint32(endpointIP), endpointIPRegRange).
+		Action().LoadRegRange(int(endpointPortReg), uint32(endpointPort), endpointPortRegRange).
+		Action().LoadRegRange(int(serviceLearnReg), lbResultMark, serviceLearnRegRange).
+		Action().LoadRegRange(int(marksReg), macRewriteMark, macRewriteMarkRange).
+		Action().ResubmitToTable(resubmitTableID).
+		Done()
 }
 
 // policyConjKeyFuncKeyFunc knows how to get key of a *policyRuleConjunction.
@@ -1401,7 +2081,7 @@ func priorityIndexFunc(obj interface{}) ([]string, error) {
 	return conj.ActionFlowPriorities(), nil
 }
 
-func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) map[binding.TableIDType]binding.Table {
+func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP, enableEgress bool) map[binding.TableIDType]binding.Table {
 	var egressEntryTable, IngressEntryTable binding.TableIDType
 	if enableAntreaNP {
 		egressEntryTable, IngressEntryTable = MultiTierEgressRuleTable, MultiTierIngressRuleTable
@@ -1452,6 +2132,9 @@ func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) m
 			L2ForwardingOutTable:  bridge.CreateTable(L2ForwardingOutTable, binding.LastTableID, binding.TableMissActionDrop),
 		}
 	}
+	if enableEgress {
+		pipeline[snatTable] = bridge.CreateTable(snatTable, l2ForwardingCalcTable, binding.TableMissActionNext)
+	}
 	if !enableAntreaNP {
 		return pipeline
 	}
@@ -1463,7 +2146,7 @@ func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) m
 }
 
 // NewClient is the constructor of the Client interface.
-func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy bool) Client {
+func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy, enableEgress, enableDenyTracking, ovsMetersAreSupported bool) Client {
 	bridge := binding.NewOFBridge(bridgeName, mgmtAddr)
 	policyCache := cache.NewIndexer(
 		policyConjKeyFunc,
@@ -1471,17 +2154,23 @@ func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy bool
 	)
 	c := &client{
 		bridge:                   bridge,
-		pipeline:                 generatePipeline(bridge, enableProxy, enableAntreaPolicy),
+		pipeline:                 generatePipeline(bridge, enableProxy, enableAntreaPolicy, enableEgress),
 		nodeFlowCache:            newFlowCategoryCache(),
 		podFlowCache:             newFlowCategoryCache(),
 		serviceFlowCache:         newFlowCategoryCache(),
+		snatFlowCache:            newFlowCategoryCache(),
+		dnsFlowCache:             newFlowCategoryCache(),
 		policyCache:              policyCache,
 		groupCache:               sync.Map{},
 		globalConjMatchFlowCache: map[string]*conjMatchFlowContext{},
-		packetInHandlers:         map[string]PacketInHandler{},
+		packetInHandlers:         map[uint8]map[string]PacketInHandler{},
 	}
 	c.ofEntryOperations = c
 	c.enableProxy = enableProxy
 	c.enableAntreaPolicy = enableAntreaPolicy
+	c.enableEgress = enableEgress
+	c.enableDenyTracking = enableDenyTracking
+	c.ovsMetersAreSupported = ovsMetersAreSupported
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
 	return c
 }
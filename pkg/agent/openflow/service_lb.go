@@ -0,0 +1,109 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/types"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+	"github.com/vmware-tanzu/antrea/third_party/proxy"
+)
+
+// defaultEndpointWeight is the weight of the Endpoints which are not given a
+// weight.
+const defaultEndpointWeight uint16 = 100
+
+// maglevTableSizes are the sizes of the Maglev lookup tables. They must be
+// prime numbers and fit in endpointPortRegRange. The smallest one which is at
+// least 10 times of the number of Endpoints is used, to keep the distribution
+// of the connections even.
+var maglevTableSizes = []int{251, 509, 1021, 2039, 4093, 8191, 16381, 32749, 65521}
+
+// hashSlots returns the Endpoints of the slots to which the hash values are
+// mapped with the hash based LB algorithm, and the multipath parameters to
+// compute the slot of a packet. It returns nil if the Endpoints can't be
+// selected by hashing.
+// The slot is computed with the NXAST_MULTIPATH action and matched by one flow
+// per slot, instead of using a select group with selection_method=hash: the
+// selection_method group property is an OpenFlow 1.5 extension which
+// libOpenflow doesn't support, and OVS maps the hash values to the buckets by
+// itself, so neither the Maglev lookup table nor the same mapping on all
+// Nodes could be enforced with it.
+func hashSlots(lbAlgorithm types.LBAlgorithm, endpoints []proxy.Endpoint) ([]proxy.Endpoint, binding.MultipathFields, binding.MultipathAlgorithm) {
+	sorted := make([]proxy.Endpoint, len(endpoints))
+	copy(sorted, endpoints)
+	// The Endpoints are sorted so that the slots are the same on all Nodes.
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	switch lbAlgorithm {
+	case types.LBAlgorithmSourceIPHash:
+		if len(sorted) > int(^uint16(0)) {
+			return nil, 0, 0
+		}
+		return sorted, binding.MultipathFieldsNwSrc, binding.MultipathAlgorithmHRW
+	case types.LBAlgorithmMaglev:
+		size := maglevTableSizes[len(maglevTableSizes)-1]
+		for _, s := range maglevTableSizes {
+			if s >= len(sorted)*10 {
+				size = s
+				break
+			}
+		}
+		if size < len(sorted) {
+			return nil, 0, 0
+		}
+		return maglevLookupTable(sorted, size), binding.MultipathFieldsSymmetricL3L4UDP, binding.MultipathAlgorithmModuloN
+	}
+	return nil, 0, 0
+}
+
+// maglevLookupTable populates a Maglev lookup table with the given size, which
+// must be a prime number not less than the number of Endpoints. See the paper
+// "Maglev: A Fast and Reliable Software Network Load Balancer" for details.
+func maglevLookupTable(endpoints []proxy.Endpoint, size int) []proxy.Endpoint {
+	n := len(endpoints)
+	offsets := make([]int, n)
+	skips := make([]int, n)
+	for i, endpoint := range endpoints {
+		h1 := fnv.New32a()
+		h1.Write([]byte(endpoint.String()))
+		h2 := fnv.New32()
+		h2.Write([]byte(endpoint.String()))
+		offsets[i] = int(h1.Sum32() % uint32(size))
+		skips[i] = int(h2.Sum32()%uint32(size-1)) + 1
+	}
+
+	table := make([]proxy.Endpoint, size)
+	next := make([]int, n)
+	filled := 0
+	for {
+		for i := 0; i < n; i++ {
+			slot := (offsets[i] + next[i]*skips[i]) % size
+			for table[slot] != nil {
+				next[i]++
+				slot = (offsets[i] + next[i]*skips[i]) % size
+			}
+			table[slot] = endpoints[i]
+			next[i]++
+			filled++
+			if filled == size {
+				return table
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/types"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+	"github.com/vmware-tanzu/antrea/third_party/proxy"
+)
+
+func makeEndpoints(n int) []proxy.Endpoint {
+	var endpoints []proxy.Endpoint
+	for i := 0; i < n; i++ {
+		endpoints = append(endpoints, &proxy.BaseEndpointInfo{Endpoint: fmt.Sprintf("10.10.%d.%d:80", i/256, i%256)})
+	}
+	return endpoints
+}
+
+func TestMaglevLookupTable(t *testing.T) {
+	endpoints := makeEndpoints(10)
+	table := maglevLookupTable(endpoints, 251)
+	require.Equal(t, 251, len(table))
+
+	// Each Endpoint takes either floor(251/10) or ceil(251/10) slots.
+	counts := map[string]int{}
+	for _, endpoint := range table {
+		require.NotNil(t, endpoint)
+		counts[endpoint.String()]++
+	}
+	assert.Equal(t, 10, len(counts))
+	for endpoint, count := range counts {
+		assert.True(t, count == 25 || count == 26, "Endpoint %s has %d slots", endpoint, count)
+	}
+
+	// Removing an Endpoint should remap its slots, and only few of the others.
+	newTable := maglevLookupTable(endpoints[1:], 251)
+	removed := endpoints[0].String()
+	remapped := 0
+	for slot := range table {
+		if table[slot].String() != removed && table[slot].String() != newTable[slot].String() {
+			remapped++
+		}
+	}
+	assert.Less(t, remapped, 251/10)
+}
+
+func TestHashSlots(t *testing.T) {
+	endpoints := makeEndpoints(3)
+	reversed := []proxy.Endpoint{endpoints[2], endpoints[1], endpoints[0]}
+
+	slots, fields, algorithm := hashSlots(types.LBAlgorithmSourceIPHash, reversed)
+	assert.Equal(t, endpoints, slots)
+	assert.Equal(t, binding.MultipathFieldsNwSrc, fields)
+	assert.Equal(t, binding.MultipathAlgorithmHRW, algorithm)
+
+	// The Maglev lookup table doesn't depend on the order of the Endpoints.
+	slots, fields, algorithm = hashSlots(types.LBAlgorithmMaglev, reversed)
+	assert.Equal(t, maglevLookupTable(endpoints, 251), slots)
+	assert.Equal(t, binding.MultipathFieldsSymmetricL3L4UDP, fields)
+	assert.Equal(t, binding.MultipathAlgorithmModuloN, algorithm)
+
+	slots, _, _ = hashSlots(types.LBAlgorithmMaglev, makeEndpoints(30))
+	assert.Equal(t, 509, len(slots))
+
+	slots, _, _ = hashSlots(types.LBAlgorithmRandom, endpoints)
+	assert.Nil(t, slots)
+}
@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
 
@@ -45,13 +46,22 @@ type endpointsChangesTracker struct {
 	initialized bool
 	// changes contains endpoints changes since the last checkoutChanges call.
 	changes map[apimachinerytypes.NamespacedName]*endpointsChange
+	// endpointSliceCache caches the EndpointSlices of all Services. It's only
+	// used when the Endpoints are tracked with EndpointSlices.
+	endpointSliceCache *k8sproxy.EndpointSliceCache
 }
 
-func newEndpointsChangesTracker(hostname string) *endpointsChangesTracker {
-	return &endpointsChangesTracker{
+func newEndpointsChangesTracker(hostname string, enableEndpointSlice bool) *endpointsChangesTracker {
+	t := &endpointsChangesTracker{
 		hostname: hostname,
 		changes:  map[apimachinerytypes.NamespacedName]*endpointsChange{},
 	}
+	if enableEndpointSlice {
+		// Like the Services, only the IPv4 Endpoints are handled.
+		enableIPV6 := false
+		t.endpointSliceCache = k8sproxy.NewEndpointSliceCache(hostname, &enableIPV6, types.NewEndpointInfo)
+	}
+	return t
 }
 
 // OnEndpointUpdate updates given Service's Endpoints change map based on the
@@ -95,6 +105,41 @@ func (t *endpointsChangesTracker) OnEndpointUpdate(previous, current *corev1.End
 	return len(t.changes) > 0
 }
 
+// OnEndpointSliceUpdate updates the given Service's Endpoints change map based
+// on the EndpointSlice which is added, updated or removed (if removeSlice is
+// true). It returns true if items changed, otherwise it returns false.
+func (t *endpointsChangesTracker) OnEndpointSliceUpdate(endpointSlice *discovery.EndpointSlice, removeSlice bool) bool {
+	namespacedName, err := k8sproxy.EndpointSliceServiceKey(endpointSlice)
+	if err != nil {
+		klog.Warningf("Error getting Service of EndpointSlice: %v", err)
+		return false
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	change, exists := t.changes[namespacedName]
+	if !exists {
+		change = &endpointsChange{}
+		change.previous = t.endpointSliceCache.EndpointsMap(namespacedName)
+		t.changes[namespacedName] = change
+	}
+
+	if removeSlice {
+		t.endpointSliceCache.Delete(endpointSlice)
+	} else {
+		t.endpointSliceCache.Update(endpointSlice)
+	}
+
+	change.current = t.endpointSliceCache.EndpointsMap(namespacedName)
+	// If change.previous equals to change.current, it means no change.
+	if reflect.DeepEqual(change.previous, change.current) {
+		delete(t.changes, namespacedName)
+	}
+
+	return len(t.changes) > 0
+}
+
 func (t *endpointsChangesTracker) checkoutChanges() []*endpointsChange {
 	t.Lock()
 	defer t.Unlock()
@@ -146,18 +191,26 @@ func (t *endpointsChangesTracker) endpointsToEndpointsMap(endpoints *corev1.Endp
 			if _, ok := endpointsMap[svcPortName]; !ok {
 				endpointsMap[svcPortName] = map[string]k8sproxy.Endpoint{}
 			}
-			for i := range ss.Addresses {
-				addr := &ss.Addresses[i]
-				if addr.IP == "" {
-					klog.Warningf("ignoring invalid endpoint port %s with empty host", port.Name)
-					continue
+			// The not ready addresses are also tracked, as they may still serve
+			// the existing connections.
+			for _, addrs := range []struct {
+				addresses []corev1.EndpointAddress
+				ready     bool
+			}{{ss.Addresses, true}, {ss.NotReadyAddresses, false}} {
+				for i := range addrs.addresses {
+					addr := &addrs.addresses[i]
+					if addr.IP == "" {
+						klog.Warningf("ignoring invalid endpoint port %s with empty host", port.Name)
+						continue
+					}
+					isLocal := addr.NodeName != nil && *addr.NodeName == t.hostname
+					ei := types.NewEndpointInfo(&k8sproxy.BaseEndpointInfo{
+						Endpoint: net.JoinHostPort(addr.IP, fmt.Sprint(port.Port)),
+						IsLocal:  isLocal,
+						Ready:    addrs.ready,
+					})
+					endpointsMap[svcPortName][ei.String()] = ei
 				}
-				isLocal := addr.NodeName != nil && *addr.NodeName == t.hostname
-				ei := types.NewEndpointInfo(&k8sproxy.BaseEndpointInfo{
-					Endpoint: net.JoinHostPort(addr.IP, fmt.Sprint(port.Port)),
-					IsLocal:  isLocal,
-				})
-				endpointsMap[svcPortName][ei.String()] = ei
 			}
 		}
 	}
@@ -0,0 +1,143 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// serviceHealthServer serves the health check NodePorts of the Services whose
+// externalTrafficPolicy is Local. Each health check NodePort reports the number
+// of local Endpoints of the Service, so that external load balancers only send
+// traffic to the Nodes which have local Endpoints.
+type serviceHealthServer struct {
+	mu sync.Mutex
+	// listen opens a listener on the provided port. It's replaceable for testing.
+	listen   func(port uint16) (net.Listener, error)
+	services map[apimachinerytypes.NamespacedName]*healthCheckInstance
+}
+
+// healthCheckInstance is the health check server of a single Service.
+type healthCheckInstance struct {
+	port           uint16
+	server         *http.Server
+	localEndpoints int
+}
+
+// healthCheckResponse is the response body of the health check requests. It's
+// compatible with the one of kube-proxy.
+type healthCheckResponse struct {
+	Service struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"service"`
+	LocalEndpoints int `json:"localEndpoints"`
+}
+
+func newServiceHealthServer() *serviceHealthServer {
+	return &serviceHealthServer{
+		listen: func(port uint16) (net.Listener, error) {
+			return net.Listen("tcp", fmt.Sprintf(":%d", port))
+		},
+		services: map[apimachinerytypes.NamespacedName]*healthCheckInstance{},
+	}
+}
+
+// SyncServices makes the health check servers consistent with the provided
+// map of Services to health check NodePorts. The servers of the Services which
+// are not in the map anymore or whose ports have changed are closed.
+func (s *serviceHealthServer) SyncServices(newServices map[apimachinerytypes.NamespacedName]uint16) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for nsn, instance := range s.services {
+		if port, ok := newServices[nsn]; ok && port == instance.port {
+			continue
+		}
+		klog.V(2).Infof("Closing health check server for Service %s on port %d", nsn, instance.port)
+		if err := instance.server.Close(); err != nil {
+			klog.Errorf("Error when closing health check server for Service %s: %v", nsn, err)
+		}
+		delete(s.services, nsn)
+	}
+
+	var errs []error
+	for nsn, port := range newServices {
+		if _, ok := s.services[nsn]; ok {
+			continue
+		}
+		klog.V(2).Infof("Opening health check server for Service %s on port %d", nsn, port)
+		listener, err := s.listen(port)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error when listening on port %d for Service %s: %v", port, nsn, err))
+			continue
+		}
+		instance := &healthCheckInstance{port: port}
+		instance.server = &http.Server{Handler: s.handler(nsn)}
+		s.services[nsn] = instance
+		go func(nsn apimachinerytypes.NamespacedName, server *http.Server) {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				klog.Errorf("Health check server for Service %s stopped: %v", nsn, err)
+			}
+		}(nsn, instance.server)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to open health check servers: %v", errs)
+	}
+	return nil
+}
+
+// SyncEndpoints updates the numbers of local Endpoints reported by the health
+// check servers. The Services which are not in the map have no local Endpoints.
+func (s *serviceHealthServer) SyncEndpoints(localEndpoints map[apimachinerytypes.NamespacedName]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for nsn, instance := range s.services {
+		instance.localEndpoints = localEndpoints[nsn]
+	}
+}
+
+func (s *serviceHealthServer) handler(nsn apimachinerytypes.NamespacedName) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		count := 0
+		if instance, ok := s.services[nsn]; ok {
+			count = instance.localEndpoints
+		}
+		s.mu.Unlock()
+
+		resp := healthCheckResponse{LocalEndpoints: count}
+		resp.Service.Namespace = nsn.Namespace
+		resp.Service.Name = nsn.Name
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if count == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			klog.Errorf("Error when writing health check response for Service %s: %v", nsn, err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestServiceHealthServer(t *testing.T) {
+	listeners := map[uint16]net.Listener{}
+	s := newServiceHealthServer()
+	s.listen = func(port uint16) (net.Listener, error) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err == nil {
+			listeners[port] = listener
+		}
+		return listener, err
+	}
+
+	get := func(port uint16) (int, *healthCheckResponse) {
+		resp, err := http.Get(fmt.Sprintf("http://%s/healthz", listeners[port].Addr()))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body := &healthCheckResponse{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+		return resp.StatusCode, body
+	}
+
+	svc1 := apimachinerytypes.NamespacedName{Namespace: "ns1", Name: "svc1"}
+	svc2 := apimachinerytypes.NamespacedName{Namespace: "ns1", Name: "svc2"}
+	require.NoError(t, s.SyncServices(map[apimachinerytypes.NamespacedName]uint16{svc1: 30001, svc2: 30002}))
+	s.SyncEndpoints(map[apimachinerytypes.NamespacedName]int{svc1: 2})
+
+	code, body := get(30001)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ns1", body.Service.Namespace)
+	assert.Equal(t, "svc1", body.Service.Name)
+	assert.Equal(t, 2, body.LocalEndpoints)
+
+	code, body = get(30002)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "svc2", body.Service.Name)
+	assert.Equal(t, 0, body.LocalEndpoints)
+
+	// The server of a Service is closed when the Service is removed.
+	require.NoError(t, s.SyncServices(map[apimachinerytypes.NamespacedName]uint16{svc1: 30001}))
+	assert.Len(t, s.services, 1)
+	_, err := http.Get(fmt.Sprintf("http://%s/healthz", listeners[30002].Addr()))
+	assert.Error(t, err)
+}
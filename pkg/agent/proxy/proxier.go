@@ -20,14 +20,24 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 
+	agentconfig "github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/agent/proxy/types"
 	"github.com/vmware-tanzu/antrea/pkg/agent/querier"
+	"github.com/vmware-tanzu/antrea/pkg/agent/route"
+	agenttypes "github.com/vmware-tanzu/antrea/pkg/agent/types"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 	"github.com/vmware-tanzu/antrea/third_party/proxy/config"
@@ -49,7 +59,21 @@ type Proxier interface {
 type proxier struct {
 	once            sync.Once
 	endpointsConfig *config.EndpointsConfig
-	serviceConfig   *config.ServiceConfig
+	// endpointSliceConfig is used instead of endpointsConfig when the Endpoints
+	// are tracked with EndpointSlices.
+	endpointSliceConfig *config.EndpointSliceConfig
+	serviceConfig       *config.ServiceConfig
+	// nodeLister is used to get the topology labels of the current Node.
+	nodeLister corelisters.NodeLister
+	// nodeListerSynced is a function which returns true if the Node shared
+	// informer has been synced at least once. It's only set when the Endpoints
+	// are tracked with EndpointSlices, so that the Endpoints are not selected
+	// before the topology labels of the current Node are known.
+	nodeListerSynced cache.InformerSynced
+	// nodeLabels stores the labels of the current Node which the Endpoints of
+	// the installed Services have been selected with.
+	nodeLabels map[string]string
+	hostname   string
 	// endpointsChanges and serviceChanges contains all changes to endpoints and
 	// services that happened since last syncProxyRules call. For a single object,
 	// changes are accumulated. Once both endpointsChanges and serviceChanges
@@ -62,8 +86,13 @@ type proxier struct {
 	serviceInstalledMap k8sproxy.ServiceMap
 	// endpointsMap stores endpoints we expect to be installed.
 	endpointsMap types.EndpointsMap
-	// endpointInstalledMap stores endpoints we actually installed.
-	endpointInstalledMap map[k8sproxy.ServicePortName]map[string]struct{}
+	// endpointInstalledMap stores endpoints we actually installed, and whether
+	// they were ready, i.e. in the Service group, when they were installed.
+	endpointInstalledMap map[k8sproxy.ServicePortName]map[string]bool
+	// terminatingEndpoints stores the endpoints which have been removed from
+	// Services with a drain timeout. Their flows are kept until the timeout
+	// expires, so that they can keep serving the existing connections.
+	terminatingEndpoints map[k8sproxy.ServicePortName]map[string]*terminatingEndpoint
 	groupCounter         types.GroupCounter
 	// serviceStringMap provides map from serviceString(ClusterIP:Port/Proto) to ServicePortName.
 	serviceStringMap map[string]k8sproxy.ServicePortName
@@ -74,9 +103,41 @@ type proxier struct {
 	stopChan     <-chan struct{}
 	agentQuerier querier.AgentQuerier
 	ofClient     openflow.Client
+	routeClient  route.Interface
+	// enableNodePort indicates whether NodePort Services are handled by the proxier.
+	enableNodePort bool
+	// serviceHealthServer serves the health check NodePorts of the Services whose
+	// externalTrafficPolicy is Local. It's only set when enableNodePort is true.
+	serviceHealthServer *serviceHealthServer
+	// enableEndpointSlice indicates whether the Endpoints are tracked with
+	// EndpointSlices, which also enables topology-aware Endpoint selection.
+	enableEndpointSlice bool
+	// proxyHostNetwork indicates whether the ClusterIP traffic from the host
+	// network is routed to OVS and handled by the proxier.
+	proxyHostNetwork bool
+	// enableLoadBalancerDSR indicates whether the traffic to the ingress IPs of
+	// the LoadBalancer Services in DSR mode is handled by the proxier.
+	enableLoadBalancerDSR bool
+	// gatewayServiceFlowsInstalled and loadBalancerDSRFlowsInstalled indicate
+	// whether the flows installed once for the host gateway and the DSR mode
+	// have been installed. They are retried in each sync until installed.
+	gatewayServiceFlowsInstalled  bool
+	loadBalancerDSRFlowsInstalled bool
+	clock                         clock.Clock
+}
+
+// terminatingEndpoint is an endpoint removed from a Service, whose flows are
+// kept until the deadline.
+type terminatingEndpoint struct {
+	endpoint k8sproxy.Endpoint
+	protocol binding.Protocol
+	deadline time.Time
 }
 
 func (p *proxier) isInitialized() bool {
+	if p.nodeListerSynced != nil && !p.nodeListerSynced() {
+		return false
+	}
 	return p.endpointsChanges.Synced() && p.serviceChanges.Synced()
 }
 
@@ -96,35 +157,69 @@ func (p *proxier) removeStaleServices() {
 					klog.Errorf("Error when installing Service flows: %v", err)
 					continue
 				}
+				if p.isLoadBalancerDSR(svcInfo) {
+					if err := p.uninstallLoadBalancerServiceDSR(net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+						klog.Errorf("Failed to remove DSR mode of LoadBalancer Service %v: %v", svcPortName, err)
+						continue
+					}
+				}
+			}
+		}
+		if p.enableNodePort && svcInfo.NodePort() > 0 {
+			if err := p.uninstallNodePortService(uint16(svcInfo.NodePort()), svcInfo.OFProtocol); err != nil {
+				klog.Errorf("Failed to remove NodePort of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+		if svcInfo.OnlyNodeLocalEndpoints() {
+			if err := p.uninstallLocalServiceGroup(svcPortName); err != nil {
+				klog.Errorf("Failed to remove local group of Service %v: %v", svcPortName, err)
+				continue
 			}
 		}
 		for _, endpoint := range p.endpointsMap[svcPortName] {
+			if p.isEndpointInUse(svcPortName, endpoint) {
+				continue
+			}
 			if err := p.ofClient.UninstallEndpointFlows(svcInfo.OFProtocol, endpoint); err != nil {
 				klog.Errorf("Failed to remove flows of Service Endpoints %v: %v", svcPortName, err)
 				continue
 			}
 		}
-		groupID, _ := p.groupCounter.Get(svcPortName)
+		delete(p.endpointInstalledMap, svcPortName)
+		groupID, _ := p.groupCounter.Get(svcPortName, false)
 		if err := p.ofClient.UninstallServiceGroup(groupID); err != nil {
 			klog.Errorf("Failed to remove flows of Service %v: %v", svcPortName, err)
 			continue
 		}
 		delete(p.serviceInstalledMap, svcPortName)
 		p.deleteServiceByIP(svcInfo.String())
-		p.groupCounter.Recycle(svcPortName)
+		p.groupCounter.Recycle(svcPortName, false)
 	}
 }
 
 func (p *proxier) removeStaleEndpoints(staleEndpoints map[k8sproxy.ServicePortName]map[string]k8sproxy.Endpoint) {
 	for svcPortName, endpoints := range staleEndpoints {
-		bindingProtocol := binding.ProtocolTCP
-		if svcPortName.Protocol == corev1.ProtocolUDP {
-			bindingProtocol = binding.ProtocolUDP
-		} else if svcPortName.Protocol == corev1.ProtocolSCTP {
-			bindingProtocol = binding.ProtocolSCTP
+		bindingProtocol := types.ToOFProtocol(svcPortName.Protocol)
+		var drainTimeout time.Duration
+		if svcPort, ok := p.serviceMap[svcPortName]; ok {
+			drainTimeout = svcPort.(*types.ServiceInfo).DrainTimeout
 		}
 		for _, endpoint := range endpoints {
-			if err := p.ofClient.UninstallEndpointFlows(bindingProtocol, endpoint); err != nil {
+			if drainTimeout > 0 {
+				// The Endpoint is removed from the Service group by installServices, while its flows are kept
+				// until the drain timeout expires.
+				if _, ok := p.terminatingEndpoints[svcPortName]; !ok {
+					p.terminatingEndpoints[svcPortName] = map[string]*terminatingEndpoint{}
+				}
+				p.terminatingEndpoints[svcPortName][endpoint.String()] = &terminatingEndpoint{
+					endpoint: endpoint,
+					protocol: bindingProtocol,
+					deadline: p.clock.Now().Add(drainTimeout),
+				}
+			} else if p.isEndpointInUse(svcPortName, endpoint) {
+				klog.V(2).Infof("Keeping flows of Endpoint %v removed from %v, which is used by other Services", endpoint, svcPortName)
+			} else if err := p.ofClient.UninstallEndpointFlows(bindingProtocol, endpoint); err != nil {
 				klog.Errorf("Error when removing Endpoint %v for %v", endpoint, svcPortName)
 				continue
 			}
@@ -138,60 +233,201 @@ func (p *proxier) removeStaleEndpoints(staleEndpoints map[k8sproxy.ServicePortNa
 	}
 }
 
-func (p *proxier) installServices() {
+// isEndpointInUse returns whether the flows of the Endpoint, which are shared by
+// all the Service ports with the same Endpoint IP, port and protocol, are still
+// used by a Service port other than svcPortName. It happens when Services select
+// the same Pods, or when multiple ports of a Service target the same named port.
+func (p *proxier) isEndpointInUse(svcPortName k8sproxy.ServicePortName, endpoint k8sproxy.Endpoint) bool {
+	key := endpoint.String()
+	for otherPortName, endpoints := range p.endpointInstalledMap {
+		if otherPortName == svcPortName || otherPortName.Protocol != svcPortName.Protocol {
+			continue
+		}
+		if _, ok := endpoints[key]; ok {
+			return true
+		}
+	}
+	for otherPortName, endpoints := range p.terminatingEndpoints {
+		if otherPortName == svcPortName || otherPortName.Protocol != svcPortName.Protocol {
+			continue
+		}
+		if _, ok := endpoints[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTerminatedEndpoints removes the flows of the terminating Endpoints whose
+// drain timeout has expired. The terminating Endpoints which have been added back
+// to their Services are not removed.
+func (p *proxier) removeTerminatedEndpoints() {
+	now := p.clock.Now()
+	for svcPortName, endpoints := range p.terminatingEndpoints {
+		for key, te := range endpoints {
+			if _, ok := p.endpointsMap[svcPortName][key]; ok {
+				delete(endpoints, key)
+				continue
+			}
+			if now.Before(te.deadline) {
+				continue
+			}
+			if p.isEndpointInUse(svcPortName, te.endpoint) {
+				delete(endpoints, key)
+				continue
+			}
+			if err := p.ofClient.UninstallEndpointFlows(te.protocol, te.endpoint); err != nil {
+				klog.Errorf("Error when removing terminating Endpoint %v for %v", te.endpoint, svcPortName)
+				continue
+			}
+			delete(endpoints, key)
+		}
+		if len(endpoints) == 0 {
+			delete(p.terminatingEndpoints, svcPortName)
+		}
+	}
+}
+
+// installServices installs the flows of the Services and their Endpoints. The
+// Service groups only contain the ready Endpoints, while the flows of the other
+// Endpoints are kept, so that they can keep serving the existing connections.
+// staleEndpoints are the Endpoints removed since the last sync, whose Services
+// need to update their groups.
+func (p *proxier) installServices(staleEndpoints map[k8sproxy.ServicePortName]map[string]k8sproxy.Endpoint, nodeLabelsChanged bool) {
 	for svcPortName, svcPort := range p.serviceMap {
 		svcInfo := svcPort.(*types.ServiceInfo)
-		groupID, _ := p.groupCounter.Get(svcPortName)
-		endpoints, ok := p.endpointsMap[svcPortName]
-		if !ok || len(endpoints) == 0 {
+		groupID, _ := p.groupCounter.Get(svcPortName, false)
+		var installedSvcInfo *types.ServiceInfo
+		installedSvcPort, installed := p.serviceInstalledMap[svcPortName]
+		if installed {
+			installedSvcInfo = installedSvcPort.(*types.ServiceInfo)
+		}
+		_, hasStaleEndpoints := staleEndpoints[svcPortName]
+		endpoints := p.endpointsMap[svcPortName]
+		// The group of an installed Service must be updated when all its
+		// Endpoints are removed.
+		if len(endpoints) == 0 && !(installed && hasStaleEndpoints) {
 			continue
 		}
 
 		endpointInstalled, ok := p.endpointInstalledMap[svcPortName]
 		if !ok {
-			p.endpointInstalledMap[svcPortName] = map[string]struct{}{}
+			p.endpointInstalledMap[svcPortName] = map[string]bool{}
 			endpointInstalled = p.endpointInstalledMap[svcPortName]
 		}
 
-		installedSvcPort, ok := p.serviceInstalledMap[svcPortName]
-		needUpdate := !ok || !installedSvcPort.(*types.ServiceInfo).Equal(svcInfo)
+		needUpdate := !installed || !installedSvcInfo.Equal(svcInfo) || hasStaleEndpoints
+		// The Endpoints must be selected again according to the new topology
+		// labels of the current Node.
+		if nodeLabelsChanged && len(svcInfo.TopologyKeys()) > 0 {
+			needUpdate = true
+		}
 
-		var endpointUpdateList []k8sproxy.Endpoint
+		var endpointUpdateList, readyEndpoints []k8sproxy.Endpoint
 		for _, endpoint := range endpoints {
-			if _, ok := endpointInstalled[endpoint.String()]; !ok {
+			if ready, ok := endpointInstalled[endpoint.String()]; !ok || ready != endpoint.IsReady() {
 				needUpdate = true
-				endpointInstalled[endpoint.String()] = struct{}{}
+				endpointInstalled[endpoint.String()] = endpoint.IsReady()
 			}
 			endpointUpdateList = append(endpointUpdateList, endpoint)
+			if endpoint.IsReady() {
+				readyEndpoints = append(readyEndpoints, endpoint)
+			}
 		}
 
 		if !needUpdate {
 			continue
 		}
 
-		if err := p.ofClient.InstallEndpointFlows(svcInfo.OFProtocol, endpointUpdateList); err != nil {
-			klog.Errorf("Error when installing Endpoints flows: %v", err)
-			continue
+		if len(endpointUpdateList) > 0 {
+			if err := p.ofClient.InstallEndpointFlows(svcInfo.OFProtocol, endpointUpdateList); err != nil {
+				klog.Errorf("Error when installing Endpoints flows: %v", err)
+				continue
+			}
 		}
-		err := p.ofClient.InstallServiceGroup(groupID, svcInfo.StickyMaxAgeSeconds() != 0, endpointUpdateList)
+		err := p.ofClient.InstallServiceGroup(groupID, svcInfo.StickyMaxAgeSeconds() != 0, toOFLBAlgorithm(svcInfo.LBAlgorithm), svcInfo.EndpointWeights, p.filterTopologyEndpoints(svcInfo, readyEndpoints))
 		if err != nil {
 			klog.Errorf("Error when installing Endpoints groups: %v", err)
-			p.endpointInstalledMap[svcPortName] = nil
+			delete(p.endpointInstalledMap, svcPortName)
 			continue
 		}
 		if err := p.ofClient.InstallServiceFlows(groupID, svcInfo.ClusterIP(), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
 			klog.Errorf("Error when installing Service flows: %v", err)
 			continue
 		}
+		// When the externalTrafficPolicy is Local, the external traffic, i.e.
+		// the traffic to the NodePort and the ingress IPs of LoadBalancer, is
+		// only load-balanced to the local Endpoints with a separate group, so
+		// that it doesn't need to be SNAT'd and the client IP is preserved.
+		externalGroupID := groupID
+		if svcInfo.OnlyNodeLocalEndpoints() {
+			externalGroupID, _ = p.groupCounter.Get(svcPortName, true)
+			var localEndpoints []k8sproxy.Endpoint
+			for _, endpoint := range readyEndpoints {
+				if endpoint.GetIsLocal() {
+					localEndpoints = append(localEndpoints, endpoint)
+				}
+			}
+			if err := p.ofClient.InstallServiceGroup(externalGroupID, svcInfo.StickyMaxAgeSeconds() != 0, toOFLBAlgorithm(svcInfo.LBAlgorithm), svcInfo.EndpointWeights, localEndpoints); err != nil {
+				klog.Errorf("Error when installing local Endpoints group: %v", err)
+				continue
+			}
+		}
+		// Remove the DSR mode previously installed for the ingress IPs of the
+		// LoadBalancer Service if it's not used by them anymore.
+		if installedSvcInfo != nil && p.isLoadBalancerDSR(installedSvcInfo) {
+			for _, ingress := range installedSvcInfo.LoadBalancerIPStrings() {
+				if ingress == "" || p.isLoadBalancerDSR(svcInfo) && installedSvcInfo.Port() == svcInfo.Port() &&
+					installedSvcInfo.OFProtocol == svcInfo.OFProtocol && sets.NewString(svcInfo.LoadBalancerIPStrings()...).Has(ingress) {
+					continue
+				}
+				if err := p.uninstallLoadBalancerServiceDSR(net.ParseIP(ingress), uint16(installedSvcInfo.Port()), installedSvcInfo.OFProtocol); err != nil {
+					klog.Errorf("Error when removing DSR mode of LoadBalancer Service: %v", err)
+					continue
+				}
+			}
+		}
 		// Install OpenFlow entries for the ingress IPs of LoadBalancer Service.
 		// The LoadBalancer Service should can be accessed from Pod, Node and
 		// external host.
 		for _, ingress := range svcInfo.LoadBalancerIPStrings() {
 			if ingress != "" {
-				if err := p.installLoadBalancerServiceFlows(groupID, net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
+				if err := p.installLoadBalancerServiceFlows(externalGroupID, net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
 					klog.Errorf("Error when installing LoadBalancer Service flows: %v", err)
 					continue
 				}
+				if p.isLoadBalancerDSR(svcInfo) {
+					if err := p.installLoadBalancerServiceDSR(net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+						klog.Errorf("Error when installing DSR mode of LoadBalancer Service: %v", err)
+						continue
+					}
+				}
+			}
+		}
+		if p.enableNodePort {
+			// Remove the NodePort previously installed for the Service if it or its externalTrafficPolicy has been
+			// changed, as the installed flows may use a different group.
+			if installedSvcInfo != nil && installedSvcInfo.NodePort() > 0 &&
+				(installedSvcInfo.NodePort() != svcInfo.NodePort() || installedSvcInfo.OnlyNodeLocalEndpoints() != svcInfo.OnlyNodeLocalEndpoints()) {
+				if err := p.uninstallNodePortService(uint16(installedSvcInfo.NodePort()), installedSvcInfo.OFProtocol); err != nil {
+					klog.Errorf("Error when removing NodePort Service flows: %v", err)
+					continue
+				}
+			}
+			// NodePort Services are only supported for IPv4.
+			if svcInfo.NodePort() > 0 && svcInfo.ClusterIP().To4() != nil {
+				if err := p.installNodePortService(externalGroupID, uint16(svcInfo.NodePort()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds()), svcInfo.OnlyNodeLocalEndpoints()); err != nil {
+					klog.Errorf("Error when installing NodePort Service flows: %v", err)
+					continue
+				}
+			}
+		}
+		// The local group is not used anymore once the flows of the external
+		// traffic have been updated with the Service group.
+		if installedSvcInfo != nil && installedSvcInfo.OnlyNodeLocalEndpoints() && !svcInfo.OnlyNodeLocalEndpoints() {
+			if err := p.uninstallLocalServiceGroup(svcPortName); err != nil {
+				klog.Errorf("Error when removing local Endpoints group: %v", err)
+				continue
 			}
 		}
 		p.serviceInstalledMap[svcPortName] = svcPort
@@ -199,6 +435,133 @@ func (p *proxier) installServices() {
 	}
 }
 
+// filterTopologyEndpoints returns the Endpoints which should be selected by the
+// Service according to its topologyKeys and the topology labels of the current
+// Node, e.g. with topologyKeys ["kubernetes.io/hostname",
+// "topology.kubernetes.io/zone", "*"], the Endpoints on the same Node are
+// preferred, then the ones in the same zone, then all the Endpoints. The
+// topology of Endpoints is only known when they are tracked with EndpointSlices.
+func (p *proxier) filterTopologyEndpoints(svcInfo *types.ServiceInfo, endpoints []k8sproxy.Endpoint) []k8sproxy.Endpoint {
+	if !p.enableEndpointSlice || len(svcInfo.TopologyKeys()) == 0 {
+		return endpoints
+	}
+	return k8sproxy.FilterTopologyEndpoint(p.nodeLabels, svcInfo.TopologyKeys(), endpoints)
+}
+
+// toOFLBAlgorithm returns the algorithm of the OpenFlow group of a Service
+// which uses the given LB algorithm. The weighted algorithm is the random one
+// with the weights of the Endpoints set to the buckets.
+func toOFLBAlgorithm(algorithm types.LBAlgorithm) agenttypes.LBAlgorithm {
+	switch algorithm {
+	case types.LBAlgorithmSourceIPHash:
+		return agenttypes.LBAlgorithmSourceIPHash
+	case types.LBAlgorithmMaglev:
+		return agenttypes.LBAlgorithmMaglev
+	default:
+		return agenttypes.LBAlgorithmRandom
+	}
+}
+
+// syncNodeLabels gets the labels of the current Node, and returns true if they
+// have changed since the last sync.
+func (p *proxier) syncNodeLabels() bool {
+	if !p.enableEndpointSlice {
+		return false
+	}
+	node, err := p.nodeLister.Get(p.hostname)
+	if err != nil {
+		klog.Errorf("Failed to get Node %s for topology-aware Endpoint selection: %v", p.hostname, err)
+		return false
+	}
+	if p.nodeLabels != nil && labels.Equals(p.nodeLabels, node.Labels) {
+		return false
+	}
+	// An empty map is stored for a Node without labels, to tell it from the
+	// labels which have never been synced.
+	p.nodeLabels = map[string]string{}
+	for k, v := range node.Labels {
+		p.nodeLabels[k] = v
+	}
+	return true
+}
+
+// installNodePortService installs the OpenFlow entries for the NodePort with the
+// virtual NodePort IP, and redirects the traffic to the NodePort on all the Node
+// IPs to the virtual NodePort IP. If preserveClientIP is true, the NodePort
+// traffic is not SNAT'd.
+func (p *proxier) installNodePortService(groupID binding.GroupIDType, svcPort uint16, protocol binding.Protocol, affinityTimeout uint16, preserveClientIP bool) error {
+	if err := p.ofClient.InstallServiceFlows(groupID, agentconfig.VirtualNodePortIP, svcPort, protocol, affinityTimeout); err != nil {
+		return err
+	}
+	return p.routeClient.AddNodePort(svcPort, protocol, preserveClientIP)
+}
+
+// uninstallNodePortService removes the redirection of the traffic to the
+// NodePort and the OpenFlow entries for the NodePort.
+func (p *proxier) uninstallNodePortService(svcPort uint16, protocol binding.Protocol) error {
+	if err := p.routeClient.DeleteNodePort(svcPort, protocol); err != nil {
+		return err
+	}
+	return p.ofClient.UninstallServiceFlows(agentconfig.VirtualNodePortIP, svcPort, protocol)
+}
+
+// isLoadBalancerDSR returns whether the traffic to the ingress IPs of the
+// LoadBalancer Service from outside the cluster is load-balanced in DSR mode,
+// which is only supported for IPv4.
+func (p *proxier) isLoadBalancerDSR(svcInfo *types.ServiceInfo) bool {
+	return p.enableLoadBalancerDSR && svcInfo.LoadBalancerMode == types.LoadBalancerModeDSR && svcInfo.ClusterIP().To4() != nil
+}
+
+// installLoadBalancerServiceDSR installs the OpenFlow entries for the ingress IP
+// and port in DSR mode, and routes the traffic to them to OVS.
+func (p *proxier) installLoadBalancerServiceDSR(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	if err := p.ofClient.InstallLoadBalancerServiceDSRFlows(svcIP, svcPort, protocol); err != nil {
+		return err
+	}
+	return p.routeClient.AddLoadBalancerDSR(svcIP, svcPort, protocol)
+}
+
+// uninstallLoadBalancerServiceDSR stops routing the traffic to the ingress IP
+// and port to OVS, and removes the OpenFlow entries for them in DSR mode.
+func (p *proxier) uninstallLoadBalancerServiceDSR(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	if err := p.routeClient.DeleteLoadBalancerDSR(svcIP, svcPort, protocol); err != nil {
+		return err
+	}
+	return p.ofClient.UninstallLoadBalancerServiceDSRFlows(svcIP, svcPort, protocol)
+}
+
+// uninstallLocalServiceGroup removes the group which only contains the local
+// Endpoints of the Service, and recycles its ID.
+func (p *proxier) uninstallLocalServiceGroup(svcPortName k8sproxy.ServicePortName) error {
+	groupID, _ := p.groupCounter.Get(svcPortName, true)
+	if err := p.ofClient.UninstallServiceGroup(groupID); err != nil {
+		return err
+	}
+	p.groupCounter.Recycle(svcPortName, true)
+	return nil
+}
+
+// localEndpointsCount returns the number of local Endpoint IPs of each Service.
+func (p *proxier) localEndpointsCount() map[apimachinerytypes.NamespacedName]int {
+	localIPs := map[apimachinerytypes.NamespacedName]sets.String{}
+	for svcPortName, endpoints := range p.endpointsMap {
+		for _, endpoint := range endpoints {
+			if !endpoint.GetIsLocal() || !endpoint.IsReady() {
+				continue
+			}
+			if _, ok := localIPs[svcPortName.NamespacedName]; !ok {
+				localIPs[svcPortName.NamespacedName] = sets.NewString()
+			}
+			localIPs[svcPortName.NamespacedName].Insert(endpoint.IP())
+		}
+	}
+	counts := make(map[apimachinerytypes.NamespacedName]int, len(localIPs))
+	for nsn, ips := range localIPs {
+		counts[nsn] = ips.Len()
+	}
+	return counts
+}
+
 // syncProxyRules applies current changes in change trackers and then updates
 // flows for services and endpoints. It will abort if either endpoints or services
 // resources is not synced. syncProxyRules is only called through the Run method
@@ -210,17 +573,48 @@ func (p *proxier) syncProxyRules() {
 	defer func() {
 		klog.V(4).Infof("syncProxyRules took %v", time.Since(start))
 	}()
+	// The flows failed to be installed when the proxier started are retried.
+	p.installHostServiceFlows()
 	if !p.isInitialized() {
 		klog.V(4).Info("Not syncing rules until both Services and Endpoints have been synced")
 		return
 	}
 
 	staleEndpoints := p.endpointsChanges.Update(p.endpointsMap)
-	p.serviceChanges.Update(p.serviceMap)
+	serviceUpdateResult := p.serviceChanges.Update(p.serviceMap)
 
 	p.removeStaleEndpoints(staleEndpoints)
+	p.removeTerminatedEndpoints()
 	p.removeStaleServices()
-	p.installServices()
+	p.installServices(staleEndpoints, p.syncNodeLabels())
+
+	if p.serviceHealthServer != nil {
+		if err := p.serviceHealthServer.SyncServices(serviceUpdateResult.HCServiceNodePorts); err != nil {
+			klog.Errorf("Error when syncing health check Services: %v", err)
+		}
+		p.serviceHealthServer.SyncEndpoints(p.localEndpointsCount())
+	}
+}
+
+// installHostServiceFlows installs the Service flows for the host gateway and
+// the DSR mode if they are required and haven't been installed. The failed ones
+// are retried in the next call, which happens at least once in the maximum
+// interval of the runner.
+func (p *proxier) installHostServiceFlows() {
+	if (p.enableNodePort || p.proxyHostNetwork || p.enableLoadBalancerDSR) && !p.gatewayServiceFlowsInstalled {
+		if err := p.ofClient.InstallGatewayServiceFlows(); err != nil {
+			klog.Errorf("Error when installing Service flows for the host gateway: %v", err)
+		} else {
+			p.gatewayServiceFlowsInstalled = true
+		}
+	}
+	if p.enableLoadBalancerDSR && !p.loadBalancerDSRFlowsInstalled {
+		if err := p.ofClient.InstallLoadBalancerDSRFlows(); err != nil {
+			klog.Errorf("Error when installing Service flows for DSR mode: %v", err)
+		} else {
+			p.loadBalancerDSRFlowsInstalled = true
+		}
+	}
 }
 
 func (p *proxier) SyncLoop() {
@@ -248,6 +642,28 @@ func (p *proxier) OnEndpointsSynced() {
 	}
 }
 
+func (p *proxier) OnEndpointSliceAdd(endpointSlice *discovery.EndpointSlice) {
+	if p.endpointsChanges.OnEndpointSliceUpdate(endpointSlice, false) && p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
+func (p *proxier) OnEndpointSliceUpdate(oldEndpointSlice, newEndpointSlice *discovery.EndpointSlice) {
+	if p.endpointsChanges.OnEndpointSliceUpdate(newEndpointSlice, false) && p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
+func (p *proxier) OnEndpointSliceDelete(endpointSlice *discovery.EndpointSlice) {
+	if p.endpointsChanges.OnEndpointSliceUpdate(endpointSlice, true) && p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
+func (p *proxier) OnEndpointSlicesSynced() {
+	p.OnEndpointsSynced()
+}
+
 func (p *proxier) OnServiceAdd(service *corev1.Service) {
 	p.OnServiceUpdate(nil, service)
 }
@@ -269,6 +685,29 @@ func (p *proxier) OnServiceSynced() {
 	}
 }
 
+// onNodeAdd triggers a sync when the current Node is added to the Node
+// informer, as the Services are not synced before it.
+func (p *proxier) onNodeAdd(obj interface{}) {
+	if p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
+// onNodeUpdate triggers a sync when the labels of the current Node are
+// updated, so that the Endpoints of the Services with topologyKeys are
+// selected again.
+func (p *proxier) onNodeUpdate(oldObj, newObj interface{}) {
+	oldNode := oldObj.(*corev1.Node)
+	newNode := newObj.(*corev1.Node)
+	if labels.Equals(oldNode.Labels, newNode.Labels) {
+		return
+	}
+	klog.V(2).Infof("Labels of Node %s were updated, syncing Services", newNode.Name)
+	if p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
 func (p *proxier) GetServiceByIP(serviceStr string) (k8sproxy.ServicePortName, bool) {
 	p.serviceStringMapMutex.Lock()
 	defer p.serviceStringMapMutex.Unlock()
@@ -294,32 +733,69 @@ func (p *proxier) deleteServiceByIP(serviceStr string) {
 func (p *proxier) Run(stopCh <-chan struct{}) {
 	p.once.Do(func() {
 		go p.serviceConfig.Run(stopCh)
-		go p.endpointsConfig.Run(stopCh)
+		p.installHostServiceFlows()
+		if p.enableEndpointSlice {
+			go p.endpointSliceConfig.Run(stopCh)
+		} else {
+			go p.endpointsConfig.Run(stopCh)
+		}
 		p.stopChan = stopCh
 		p.SyncLoop()
 	})
 }
 
-func New(hostname string, informerFactory informers.SharedInformerFactory, ofClient openflow.Client) *proxier {
+func New(hostname string, informerFactory informers.SharedInformerFactory, ofClient openflow.Client, routeClient route.Interface, enableNodePort, enableEndpointSlice, proxyHostNetwork, enableLoadBalancerDSR bool) *proxier {
 	recorder := record.NewBroadcaster().NewRecorder(
 		runtime.NewScheme(),
 		corev1.EventSource{Component: componentName, Host: hostname},
 	)
 	p := &proxier{
-		endpointsConfig:      config.NewEndpointsConfig(informerFactory.Core().V1().Endpoints(), resyncPeriod),
-		serviceConfig:        config.NewServiceConfig(informerFactory.Core().V1().Services(), resyncPeriod),
-		endpointsChanges:     newEndpointsChangesTracker(hostname),
-		serviceChanges:       newServiceChangesTracker(recorder),
-		serviceMap:           k8sproxy.ServiceMap{},
-		serviceInstalledMap:  k8sproxy.ServiceMap{},
-		endpointInstalledMap: map[k8sproxy.ServicePortName]map[string]struct{}{},
-		endpointsMap:         types.EndpointsMap{},
-		serviceStringMap:     map[string]k8sproxy.ServicePortName{},
-		groupCounter:         types.NewGroupCounter(),
-		ofClient:             ofClient,
+		serviceConfig:         config.NewServiceConfig(informerFactory.Core().V1().Services(), resyncPeriod),
+		endpointsChanges:      newEndpointsChangesTracker(hostname, enableEndpointSlice),
+		serviceChanges:        newServiceChangesTracker(recorder),
+		serviceMap:            k8sproxy.ServiceMap{},
+		serviceInstalledMap:   k8sproxy.ServiceMap{},
+		endpointInstalledMap:  map[k8sproxy.ServicePortName]map[string]bool{},
+		terminatingEndpoints:  map[k8sproxy.ServicePortName]map[string]*terminatingEndpoint{},
+		endpointsMap:          types.EndpointsMap{},
+		serviceStringMap:      map[string]k8sproxy.ServicePortName{},
+		groupCounter:          types.NewGroupCounter(),
+		ofClient:              ofClient,
+		routeClient:           routeClient,
+		enableNodePort:        enableNodePort,
+		enableEndpointSlice:   enableEndpointSlice,
+		proxyHostNetwork:      proxyHostNetwork,
+		enableLoadBalancerDSR: enableLoadBalancerDSR,
+		hostname:              hostname,
+		clock:                 clock.RealClock{},
+	}
+	if enableNodePort {
+		p.serviceHealthServer = newServiceHealthServer()
 	}
 	p.serviceConfig.RegisterEventHandler(p)
-	p.endpointsConfig.RegisterEventHandler(p)
+	if enableEndpointSlice {
+		p.endpointSliceConfig = config.NewEndpointSliceConfig(informerFactory.Discovery().V1beta1().EndpointSlices(), resyncPeriod)
+		p.endpointSliceConfig.RegisterEventHandler(p)
+		nodeInformer := informerFactory.Core().V1().Nodes()
+		p.nodeLister = nodeInformer.Lister()
+		p.nodeListerSynced = nodeInformer.Informer().HasSynced
+		nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.FilteringResourceEventHandler{
+				FilterFunc: func(obj interface{}) bool {
+					node, ok := obj.(*corev1.Node)
+					return ok && node.Name == hostname
+				},
+				Handler: cache.ResourceEventHandlerFuncs{
+					AddFunc:    p.onNodeAdd,
+					UpdateFunc: p.onNodeUpdate,
+				},
+			},
+			resyncPeriod,
+		)
+	} else {
+		p.endpointsConfig = config.NewEndpointsConfig(informerFactory.Core().V1().Endpoints(), resyncPeriod)
+		p.endpointsConfig.RegisterEventHandler(p)
+	}
 	p.runner = k8sproxy.NewBoundedFrequencyRunner(componentName, p.syncProxyRules, 0, 30*time.Second, -1)
 	return p
 }
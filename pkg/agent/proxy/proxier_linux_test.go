@@ -0,0 +1,174 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	ofmock "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/proxy/types"
+	routemock "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
+	agenttypes "github.com/vmware-tanzu/antrea/pkg/agent/types"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
+)
+
+func TestLoadBalancerDSR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routemock.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.routeClient = mockRouteClient
+	fp.enableLoadBalancerDSR = true
+	mockOFClient.EXPECT().InstallGatewayServiceFlows().Times(1)
+	mockOFClient.EXPECT().InstallLoadBalancerDSRFlows().Times(1)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	ingressIP := net.ParseIP("169.254.1.1")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeLoadBalancerService := func(mode types.LoadBalancerMode) *corev1.Service {
+		return makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Annotations = map[string]string{types.ServiceLoadBalancerModeAnnotationKey: string(mode)}
+			svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ingressIP.String()}}
+		})
+	}
+	svc := makeLoadBalancerService(types.LoadBalancerModeDSR)
+	makeServiceMap(fp, svc)
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "10.180.0.1"}},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	// The Endpoints of the Service in DSR mode are selected with the maglev algorithm by default.
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, agenttypes.LBAlgorithmMaglev, gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, ingressIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallLoadBalancerServiceDSRFlows(ingressIP, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().AddLoadBalancerDSR(ingressIP, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	fp.syncProxyRules()
+
+	// Switching the Service to NAT mode removes the DSR mode of the ingress IP.
+	natSvc := makeLoadBalancerService(types.LoadBalancerModeNAT)
+	fp.serviceChanges.OnServiceUpdate(svc, natSvc)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, agenttypes.LBAlgorithmRandom, gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, ingressIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().DeleteLoadBalancerDSR(ingressIP, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallLoadBalancerServiceDSRFlows(ingressIP, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	fp.syncProxyRules()
+}
+
+func TestLoadBalancerLocal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	ingressIP := net.ParseIP("169.254.1.1")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeLoadBalancerService := func(policy corev1.ServiceExternalTrafficPolicyType) *corev1.Service {
+		return makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+			svc.Spec.ExternalTrafficPolicy = policy
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ingressIP.String()}}
+		})
+	}
+	svc := makeLoadBalancerService(corev1.ServiceExternalTrafficPolicyTypeLocal)
+	makeServiceMap(fp, svc)
+	localNodeName := "localhost"
+	remoteNodeName := "remote"
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.180.0.1", NodeName: &localNodeName},
+					{IP: "10.180.1.1", NodeName: &remoteNodeName},
+				},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	// The traffic to the ingress IP is only load-balanced to the local Endpoints.
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	localGroupID, _ := fp.groupCounter.Get(svcPortName, true)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(localGroupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.Equal(t, 1, len(endpoints))
+			assert.Equal(t, "10.180.0.1", endpoints[0].IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(localGroupID, ingressIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// Switching the Service to the Cluster policy load-balances the traffic to the ingress IP to all the Endpoints,
+	// and removes the local group.
+	clusterSvc := makeLoadBalancerService(corev1.ServiceExternalTrafficPolicyTypeCluster)
+	fp.serviceChanges.OnServiceUpdate(svc, clusterSvc)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, ingressIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(localGroupID).Times(1)
+	fp.syncProxyRules()
+}
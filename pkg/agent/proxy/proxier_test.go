@@ -18,17 +18,26 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 
+	agentconfig "github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	ofmock "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
 	"github.com/vmware-tanzu/antrea/pkg/agent/proxy/types"
+	routemock "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
+	agenttypes "github.com/vmware-tanzu/antrea/pkg/agent/types"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
@@ -84,19 +93,68 @@ func NewFakeProxier(ofClient openflow.Client) *proxier {
 		corev1.EventSource{Component: componentName, Host: hostname},
 	)
 	p := &proxier{
-		endpointsChanges:     newEndpointsChangesTracker(hostname),
+		endpointsChanges:     newEndpointsChangesTracker(hostname, false),
 		serviceChanges:       newServiceChangesTracker(recorder),
 		serviceMap:           k8sproxy.ServiceMap{},
 		serviceInstalledMap:  k8sproxy.ServiceMap{},
-		endpointInstalledMap: map[k8sproxy.ServicePortName]map[string]struct{}{},
+		endpointInstalledMap: map[k8sproxy.ServicePortName]map[string]bool{},
+		terminatingEndpoints: map[k8sproxy.ServicePortName]map[string]*terminatingEndpoint{},
 		endpointsMap:         types.EndpointsMap{},
 		groupCounter:         types.NewGroupCounter(),
 		ofClient:             ofClient,
 		serviceStringMap:     map[string]k8sproxy.ServicePortName{},
+		clock:                clock.NewFakeClock(time.Now()),
 	}
 	return p
 }
 
+// NewFakeProxierWithEndpointSlice returns a fake proxier which tracks the
+// Endpoints with EndpointSlices and runs on a Node with the given labels.
+func NewFakeProxierWithEndpointSlice(ofClient openflow.Client, nodeLabels map[string]string) *proxier {
+	p := NewFakeProxier(ofClient)
+	p.hostname = "localhost"
+	p.enableEndpointSlice = true
+	p.endpointsChanges = newEndpointsChangesTracker(p.hostname, true)
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: p.hostname, Labels: nodeLabels}})
+	p.nodeLister = corelisters.NewNodeLister(nodeIndexer)
+	return p
+}
+
+func makeEndpointSliceMap(proxier *proxier, allEndpointSlices ...*discovery.EndpointSlice) {
+	for i := range allEndpointSlices {
+		proxier.endpointsChanges.OnEndpointSliceUpdate(allEndpointSlices[i], false)
+	}
+	proxier.endpointsChanges.OnEndpointsSynced()
+}
+
+func makeTestEndpointSlice(namespace, svcName, name string, port int32, endpoints ...discovery.Endpoint) *discovery.EndpointSlice {
+	portName := fmt.Sprint(port)
+	protocol := corev1.ProtocolTCP
+	return &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{discovery.LabelServiceName: svcName},
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Endpoints:   endpoints,
+		Ports: []discovery.EndpointPort{{
+			Name:     &portName,
+			Port:     &port,
+			Protocol: &protocol,
+		}},
+	}
+}
+
+func makeTestSliceEndpoint(ip string, ready bool, topology map[string]string) discovery.Endpoint {
+	return discovery.Endpoint{
+		Addresses:  []string{ip},
+		Conditions: discovery.EndpointConditions{Ready: &ready},
+		Topology:   topology,
+	}
+}
+
 func TestClusterIP(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -137,8 +195,8 @@ func TestClusterIP(t *testing.T) {
 		}),
 	)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
 
@@ -183,8 +241,8 @@ func TestClusterIPRemoval(t *testing.T) {
 	}
 	ep := makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, epFunc)
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
 	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
@@ -197,6 +255,421 @@ func TestClusterIPRemoval(t *testing.T) {
 	fp.syncProxyRules()
 }
 
+func TestNodePort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routemock.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.routeClient = mockRouteClient
+	fp.enableNodePort = true
+	mockOFClient.EXPECT().InstallGatewayServiceFlows().Times(1)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcNodePort := 30080
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	svc := makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+		svc.Spec.Type = corev1.ServiceTypeNodePort
+		svc.Spec.ClusterIP = svcIPv4.String()
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:     svcPortName.Port,
+			Port:     int32(svcPort),
+			NodePort: int32(svcNodePort),
+			Protocol: corev1.ProtocolTCP,
+		}}
+	})
+	makeServiceMap(fp, svc)
+
+	epIP := net.ParseIP("10.180.0.1")
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{
+					IP: epIP.String(),
+				}},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, agentconfig.VirtualNodePortIP, uint16(svcNodePort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(uint16(svcNodePort), binding.ProtocolTCP, false).Times(1)
+	fp.syncProxyRules()
+
+	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(agentconfig.VirtualNodePortIP, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().DeleteNodePort(uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(groupID).Times(1)
+	fp.serviceChanges.OnServiceUpdate(svc, nil)
+	fp.syncProxyRules()
+}
+
+func TestNodePortLocal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routemock.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.routeClient = mockRouteClient
+	fp.enableNodePort = true
+	mockOFClient.EXPECT().InstallGatewayServiceFlows().Times(1)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcNodePort := 30080
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	svc := makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+		svc.Spec.Type = corev1.ServiceTypeNodePort
+		svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+		svc.Spec.ClusterIP = svcIPv4.String()
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:     svcPortName.Port,
+			Port:     int32(svcPort),
+			NodePort: int32(svcNodePort),
+			Protocol: corev1.ProtocolTCP,
+		}}
+	})
+	makeServiceMap(fp, svc)
+
+	localNodeName := "localhost"
+	remoteNodeName := "remote"
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.180.0.1", NodeName: &localNodeName},
+					{IP: "10.180.1.1", NodeName: &remoteNodeName},
+				},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	localGroupID, _ := fp.groupCounter.Get(svcPortName, true)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(localGroupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.Equal(t, 1, len(endpoints))
+			assert.Equal(t, "10.180.0.1", endpoints[0].IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(localGroupID, agentconfig.VirtualNodePortIP, uint16(svcNodePort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(uint16(svcNodePort), binding.ProtocolTCP, true).Times(1)
+	fp.syncProxyRules()
+	assert.Equal(t, map[apimachinerytypes.NamespacedName]int{svcPortName.NamespacedName: 1}, fp.localEndpointsCount())
+
+	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(agentconfig.VirtualNodePortIP, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().DeleteNodePort(uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(2)
+	mockOFClient.EXPECT().UninstallServiceGroup(localGroupID).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(groupID).Times(1)
+	fp.serviceChanges.OnServiceUpdate(svc, nil)
+	fp.syncProxyRules()
+}
+
+func TestGatewayServiceFlowsRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.enableNodePort = true
+
+	// The flows failed to be installed are retried in the next sync.
+	mockOFClient.EXPECT().InstallGatewayServiceFlows().Return(fmt.Errorf("bundle error")).Times(1)
+	fp.syncProxyRules()
+	assert.False(t, fp.gatewayServiceFlowsInstalled)
+	mockOFClient.EXPECT().InstallGatewayServiceFlows().Return(nil).Times(1)
+	fp.syncProxyRules()
+	assert.True(t, fp.gatewayServiceFlowsInstalled)
+	// The flows are not installed again once installed.
+	fp.syncProxyRules()
+}
+
+func TestEndpointSlice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxierWithEndpointSlice(mockOFClient, nil)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+	slice1 := makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-abc", int32(svcPort),
+		makeTestSliceEndpoint("10.180.0.1", true, map[string]string{corev1.LabelHostname: "localhost"}),
+		makeTestSliceEndpoint("10.180.0.2", false, map[string]string{corev1.LabelHostname: "localhost"}),
+	)
+	slice2 := makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-def", int32(svcPort),
+		makeTestSliceEndpoint("10.180.1.1", true, map[string]string{corev1.LabelHostname: "remote"}),
+	)
+	makeEndpointSliceMap(fp, slice1, slice2)
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.ElementsMatch(t, []string{"10.180.0.1", "10.180.1.1"}, []string{endpoints[0].IP(), endpoints[1].IP()})
+		}).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+	// The not ready Endpoint is tracked but not selected by the group.
+	assert.Equal(t, 3, len(fp.endpointsMap[svcPortName]))
+
+	// Removing one of the EndpointSlices should only remove its Endpoints.
+	fp.endpointsChanges.OnEndpointSliceUpdate(slice2, true)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Do(
+		func(_ binding.Protocol, endpoint k8sproxy.Endpoint) {
+			assert.Equal(t, "10.180.1.1", endpoint.IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.Equal(t, 1, len(endpoints))
+			assert.Equal(t, "10.180.0.1", endpoints[0].IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+	assert.Equal(t, 2, len(fp.endpointsMap[svcPortName]))
+}
+
+func TestEndpointSliceAddressTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxierWithEndpointSlice(mockOFClient, nil)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+	ipv4Slice := makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-abc", int32(svcPort),
+		makeTestSliceEndpoint("10.180.0.1", true, map[string]string{corev1.LabelHostname: "localhost"}),
+	)
+	ipv6Slice := makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-def", int32(svcPort),
+		makeTestSliceEndpoint("fec0::1", true, map[string]string{corev1.LabelHostname: "localhost"}),
+	)
+	ipv6Slice.AddressType = discovery.AddressTypeIPv6
+	fqdnSlice := makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-ghi", int32(svcPort),
+		makeTestSliceEndpoint("example.com", true, map[string]string{corev1.LabelHostname: "localhost"}),
+	)
+	fqdnSlice.AddressType = discovery.AddressTypeFQDN
+	makeEndpointSliceMap(fp, ipv4Slice, ipv6Slice, fqdnSlice)
+
+	// Only the Endpoints of the IPv4 EndpointSlice are selected by the IPv4 proxier.
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.Equal(t, 1, len(endpoints))
+			assert.Equal(t, "10.180.0.1", endpoints[0].IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+	assert.Equal(t, 1, len(fp.endpointsMap[svcPortName]))
+}
+
+func TestTopologyAwareEndpoints(t *testing.T) {
+	const zoneKey = "topology.kubernetes.io/zone"
+	testCases := []struct {
+		name         string
+		topologyKeys []string
+		expectedIPs  []string
+	}{
+		{
+			name:         "prefer Node",
+			topologyKeys: []string{corev1.LabelHostname, zoneKey, corev1.TopologyKeyAny},
+			expectedIPs:  []string{"10.180.0.1"},
+		},
+		{
+			name:         "prefer zone",
+			topologyKeys: []string{zoneKey, corev1.TopologyKeyAny},
+			expectedIPs:  []string{"10.180.0.1", "10.180.1.1"},
+		},
+		{
+			name:         "any",
+			topologyKeys: []string{corev1.TopologyKeyAny},
+			expectedIPs:  []string{"10.180.0.1", "10.180.1.1", "10.180.2.1"},
+		},
+		{
+			name:         "no match",
+			topologyKeys: []string{"topology.kubernetes.io/region"},
+			expectedIPs:  []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOFClient := ofmock.NewMockClient(ctrl)
+			fp := NewFakeProxierWithEndpointSlice(mockOFClient, map[string]string{
+				corev1.LabelHostname: "localhost",
+				zoneKey:              "zone-a",
+			})
+
+			svcIPv4 := net.ParseIP("10.20.30.41")
+			svcPort := 80
+			svcPortName := k8sproxy.ServicePortName{
+				NamespacedName: makeNamespaceName("ns1", "svc1"),
+				Port:           fmt.Sprint(svcPort),
+				Protocol:       corev1.ProtocolTCP,
+			}
+			makeServiceMap(fp,
+				makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+					svc.Spec.ClusterIP = svcIPv4.String()
+					svc.Spec.TopologyKeys = tc.topologyKeys
+					svc.Spec.Ports = []corev1.ServicePort{{
+						Name:     svcPortName.Port,
+						Port:     int32(svcPort),
+						Protocol: corev1.ProtocolTCP,
+					}}
+				}),
+			)
+			makeEndpointSliceMap(fp,
+				makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-abc", int32(svcPort),
+					makeTestSliceEndpoint("10.180.0.1", true, map[string]string{corev1.LabelHostname: "localhost", zoneKey: "zone-a"}),
+					makeTestSliceEndpoint("10.180.1.1", true, map[string]string{corev1.LabelHostname: "node-a", zoneKey: "zone-a"}),
+					makeTestSliceEndpoint("10.180.2.1", true, map[string]string{corev1.LabelHostname: "node-b", zoneKey: "zone-b"}),
+				),
+			)
+
+			groupID, _ := fp.groupCounter.Get(svcPortName, false)
+			mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+					ips := []string{}
+					for _, endpoint := range endpoints {
+						ips = append(ips, endpoint.IP())
+					}
+					assert.ElementsMatch(t, tc.expectedIPs, ips)
+				}).Times(1)
+			// Endpoint flows are installed for all Endpoints regardless of topology.
+			mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Do(
+				func(_ binding.Protocol, endpoints []k8sproxy.Endpoint) {
+					assert.Equal(t, 3, len(endpoints))
+				}).Times(1)
+			mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+			fp.syncProxyRules()
+		})
+	}
+}
+
+func TestTopologyAwareEndpointsNodeLabelsUpdate(t *testing.T) {
+	const zoneKey = "topology.kubernetes.io/zone"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxierWithEndpointSlice(mockOFClient, map[string]string{zoneKey: "zone-a"})
+	nodeSynced := false
+	fp.nodeListerSynced = func() bool {
+		return nodeSynced
+	}
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           fmt.Sprint(svcPort),
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.TopologyKeys = []string{zoneKey}
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+	makeEndpointSliceMap(fp,
+		makeTestEndpointSlice(svcPortName.Namespace, svcPortName.Name, "svc1-abc", int32(svcPort),
+			makeTestSliceEndpoint("10.180.0.1", true, map[string]string{zoneKey: "zone-a"}),
+			makeTestSliceEndpoint("10.180.1.1", true, map[string]string{zoneKey: "zone-b"}),
+		),
+	)
+
+	// Nothing should be installed before the Node informer is synced.
+	fp.syncProxyRules()
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	expectServiceGroup := func(expectedIPs ...string) {
+		mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+			func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+				ips := []string{}
+				for _, endpoint := range endpoints {
+					ips = append(ips, endpoint.IP())
+				}
+				assert.ElementsMatch(t, expectedIPs, ips)
+			}).Times(1)
+		mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+		mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	}
+	nodeSynced = true
+	expectServiceGroup("10.180.0.1")
+	fp.syncProxyRules()
+
+	// The Endpoints should be selected again after the Node moves to another zone.
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fp.hostname, Labels: map[string]string{zoneKey: "zone-b"}}})
+	fp.nodeLister = corelisters.NewNodeLister(nodeIndexer)
+	expectServiceGroup("10.180.1.1")
+	fp.syncProxyRules()
+
+	// Nothing should be updated if the labels don't change.
+	fp.syncProxyRules()
+}
+
 func TestClusterIPNoEndpoint(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -292,17 +765,20 @@ func TestClusterIPRemoveSamePortEndpoint(t *testing.T) {
 	})
 	makeEndpointsMap(fp, ep, epUDP)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	groupIDUDP, _ := fp.groupCounter.Get(svcPortNameUDP)
-	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
-	mockOFClient.EXPECT().InstallServiceGroup(groupIDUDP, false, gomock.Any()).Times(1)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	groupIDUDP, _ := fp.groupCounter.Get(svcPortNameUDP, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupIDUDP, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolUDP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupIDUDP, svcIPv4, uint16(svcPort), binding.ProtocolUDP, uint16(0)).Times(1)
-	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolUDP, gomock.Any()).Times(1)
 	fp.syncProxyRules()
 
+	// Removing the Endpoints of the UDP Service should empty its group.
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolUDP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupIDUDP, false, gomock.Any(), gomock.Any(), gomock.Len(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupIDUDP, svcIPv4, uint16(svcPort), binding.ProtocolUDP, uint16(0)).Times(1)
 	fp.endpointsChanges.OnEndpointUpdate(epUDP, nil)
 	fp.syncProxyRules()
 }
@@ -345,13 +821,15 @@ func TestClusterIPRemoveEndpoints(t *testing.T) {
 		}}
 	})
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
-	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	fp.syncProxyRules()
 
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Len(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
 	fp.endpointsChanges.OnEndpointUpdate(ep, nil)
 	fp.syncProxyRules()
 }
@@ -408,8 +886,8 @@ func TestSessionAffinityNoEndpoint(t *testing.T) {
 		}),
 	)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	mockOFClient.EXPECT().InstallServiceGroup(groupID, true, gomock.Any()).Times(1)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, true, gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), binding.ProtocolTCP, uint16(corev1.DefaultClientIPServiceAffinitySeconds)).Times(1)
 
@@ -456,3 +934,354 @@ func TestSessionAffinity(t *testing.T) {
 
 	fp.syncProxyRules()
 }
+
+func TestSCTPSessionAffinity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 3868
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "diameter",
+		Protocol:       corev1.ProtocolSCTP,
+	}
+	timeoutSeconds := corev1.DefaultClientIPServiceAffinitySeconds
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{
+					TimeoutSeconds: &timeoutSeconds,
+				},
+			}
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolSCTP,
+			}}
+		}),
+	)
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{
+					IP: "10.180.0.1",
+				}},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolSCTP,
+				}},
+			}}
+		}),
+	)
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, true, gomock.Any(), gomock.Any(), gomock.Len(1)).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolSCTP, gomock.Len(1)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolSCTP, uint16(timeoutSeconds)).Times(1)
+	fp.syncProxyRules()
+}
+
+// TestSharedEndpointRemoval verifies that the flows of an Endpoint shared by
+// multiple Service ports, whose target port is the same named port, are kept
+// until the Endpoint is removed from all of them.
+func TestSharedEndpointRemoval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	httpPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "http",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	altPortName := k8sproxy.ServicePortName{
+		NamespacedName: httpPortName.NamespacedName,
+		Port:           "http-alt",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	ports := []corev1.ServicePort{
+		{Name: httpPortName.Port, Port: 80, Protocol: corev1.ProtocolTCP},
+		{Name: altPortName.Port, Port: 8080, Protocol: corev1.ProtocolTCP},
+	}
+	svc := makeTestService(httpPortName.Namespace, httpPortName.Name, func(svc *corev1.Service) {
+		svc.Spec.ClusterIP = svcIPv4.String()
+		svc.Spec.Ports = ports
+	})
+	makeServiceMap(fp, svc)
+	// Both Service ports target the named port "web" of the Pod.
+	makeEndpointsMap(fp,
+		makeTestEndpoints(httpPortName.Namespace, httpPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{
+					IP: "10.180.0.1",
+				}},
+				Ports: []corev1.EndpointPort{
+					{Name: httpPortName.Port, Port: 8000, Protocol: corev1.ProtocolTCP},
+					{Name: altPortName.Port, Port: 8000, Protocol: corev1.ProtocolTCP},
+				},
+			}}
+		}),
+	)
+
+	httpGroupID, _ := fp.groupCounter.Get(httpPortName, false)
+	altGroupID, _ := fp.groupCounter.Get(altPortName, false)
+	mockOFClient.EXPECT().InstallServiceGroup(httpGroupID, false, gomock.Any(), gomock.Any(), gomock.Len(1)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(altGroupID, false, gomock.Any(), gomock.Any(), gomock.Len(1)).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Len(1)).Times(2)
+	mockOFClient.EXPECT().InstallServiceFlows(httpGroupID, svcIPv4, uint16(80), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(altGroupID, svcIPv4, uint16(8080), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// Removing the port "http-alt" from the Service must not remove the flows
+	// of the Endpoint, which is still used by the port "http".
+	newSvc := svc.DeepCopy()
+	newSvc.Spec.Ports = ports[:1]
+	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(8080), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(altGroupID).Times(1)
+	fp.serviceChanges.OnServiceUpdate(svc, newSvc)
+	fp.syncProxyRules()
+
+	// Removing the Service removes the flows of the Endpoint.
+	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(80), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(httpGroupID).Times(1)
+	fp.serviceChanges.OnServiceUpdate(newSvc, nil)
+	fp.syncProxyRules()
+}
+
+func TestNotReadyEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+
+	makeSubsets := func(readyIPs, notReadyIPs []string) []corev1.EndpointSubset {
+		subset := corev1.EndpointSubset{
+			Ports: []corev1.EndpointPort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}},
+		}
+		for _, ip := range readyIPs {
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+		}
+		for _, ip := range notReadyIPs {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, corev1.EndpointAddress{IP: ip})
+		}
+		return []corev1.EndpointSubset{subset}
+	}
+	ep := makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+		ept.Subsets = makeSubsets([]string{"10.180.0.1"}, []string{"10.180.0.2"})
+	})
+	makeEndpointsMap(fp, ep)
+
+	// The flows of the not ready Endpoint are installed so that it can keep
+	// serving the existing connections, but it's not selected by the group.
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Len(2)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.Equal(t, 1, len(endpoints))
+			assert.Equal(t, "10.180.0.1", endpoints[0].IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// The group is updated when the Endpoint becomes ready.
+	newEp := makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+		ept.Subsets = makeSubsets([]string{"10.180.0.1", "10.180.0.2"}, nil)
+	})
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Len(2)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Len(2)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.endpointsChanges.OnEndpointUpdate(ep, newEp)
+	fp.syncProxyRules()
+
+	// Nothing is changed when the Endpoints are synced again.
+	fp.syncProxyRules()
+}
+
+func TestDrainTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fakeClock := fp.clock.(*clock.FakeClock)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Annotations = map[string]string{types.ServiceDrainTimeoutAnnotationKey: "60"}
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+
+	makeEp := func(ips ...string) *corev1.Endpoints {
+		return makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			subset := corev1.EndpointSubset{
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}
+			for _, ip := range ips {
+				subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+			}
+			ept.Subsets = []corev1.EndpointSubset{subset}
+		})
+	}
+	ep := makeEp("10.180.0.1", "10.180.0.2")
+	makeEndpointsMap(fp, ep)
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Len(2)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Len(2)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// The removed Endpoint leaves the group but its flows are kept until the
+	// drain timeout expires.
+	newEp := makeEp("10.180.0.1")
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Len(1)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ binding.GroupIDType, _ bool, _ agenttypes.LBAlgorithm, _ map[string]uint16, endpoints []k8sproxy.Endpoint) {
+			assert.Equal(t, 1, len(endpoints))
+			assert.Equal(t, "10.180.0.1", endpoints[0].IP())
+		}).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.endpointsChanges.OnEndpointUpdate(ep, newEp)
+	fp.syncProxyRules()
+	assert.Equal(t, 1, len(fp.terminatingEndpoints[svcPortName]))
+
+	fakeClock.Step(30 * time.Second)
+	fp.syncProxyRules()
+	assert.Equal(t, 1, len(fp.terminatingEndpoints[svcPortName]))
+
+	fakeClock.Step(30 * time.Second)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Do(
+		func(_ binding.Protocol, endpoint k8sproxy.Endpoint) {
+			assert.Equal(t, "10.180.0.2", endpoint.IP())
+		}).Times(1)
+	fp.syncProxyRules()
+	assert.Equal(t, 0, len(fp.terminatingEndpoints))
+}
+
+func TestLBAlgorithm(t *testing.T) {
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		expectedAlgorithm agenttypes.LBAlgorithm
+		expectedWeights   map[string]uint16
+	}{
+		{
+			name:              "default",
+			expectedAlgorithm: agenttypes.LBAlgorithmRandom,
+		},
+		{
+			name: "weighted",
+			annotations: map[string]string{
+				types.ServiceLBAlgorithmAnnotationKey:     "weighted",
+				types.ServiceEndpointWeightsAnnotationKey: "10.180.0.1=90, 10.180.0.2=10,invalid=1,10.180.0.3=-1",
+			},
+			expectedAlgorithm: agenttypes.LBAlgorithmRandom,
+			expectedWeights:   map[string]uint16{"10.180.0.1": 90, "10.180.0.2": 10},
+		},
+		{
+			name: "weights ignored",
+			annotations: map[string]string{
+				types.ServiceLBAlgorithmAnnotationKey:     "maglev",
+				types.ServiceEndpointWeightsAnnotationKey: "10.180.0.1=90",
+			},
+			expectedAlgorithm: agenttypes.LBAlgorithmMaglev,
+		},
+		{
+			name: "invalid algorithm",
+			annotations: map[string]string{
+				types.ServiceLBAlgorithmAnnotationKey: "round-robin",
+			},
+			expectedAlgorithm: agenttypes.LBAlgorithmRandom,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOFClient := ofmock.NewMockClient(ctrl)
+			fp := NewFakeProxier(mockOFClient)
+
+			svcIPv4 := net.ParseIP("10.20.30.41")
+			svcPort := 80
+			svcPortName := k8sproxy.ServicePortName{
+				NamespacedName: makeNamespaceName("ns1", "svc1"),
+				Port:           "80",
+				Protocol:       corev1.ProtocolTCP,
+			}
+			makeServiceMap(fp,
+				makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+					svc.Annotations = tc.annotations
+					svc.Spec.ClusterIP = svcIPv4.String()
+					svc.Spec.Ports = []corev1.ServicePort{{
+						Name:     svcPortName.Port,
+						Port:     int32(svcPort),
+						Protocol: corev1.ProtocolTCP,
+					}}
+				}),
+			)
+			makeEndpointsMap(fp, makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+				ept.Subsets = []corev1.EndpointSubset{{
+					Addresses: []corev1.EndpointAddress{{IP: "10.180.0.1"}, {IP: "10.180.0.2"}},
+					Ports: []corev1.EndpointPort{{
+						Name:     svcPortName.Port,
+						Port:     int32(svcPort),
+						Protocol: corev1.ProtocolTCP,
+					}},
+				}}
+			}))
+
+			groupID, _ := fp.groupCounter.Get(svcPortName, false)
+			mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Len(2)).Times(1)
+			mockOFClient.EXPECT().InstallServiceGroup(groupID, false, tc.expectedAlgorithm, tc.expectedWeights, gomock.Len(2)).Times(1)
+			mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+			fp.syncProxyRules()
+		})
+	}
+}
@@ -15,17 +15,95 @@
 package types
 
 import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
+// ServiceDrainTimeoutAnnotationKey is the annotation of a Service which sets
+// the number of seconds the Endpoints removed from the Service keep serving
+// their existing connections. By default, the flows of the removed Endpoints
+// are uninstalled immediately.
+const ServiceDrainTimeoutAnnotationKey = "service.antrea.tanzu.vmware.com/drain-timeout"
+
+// LBAlgorithm is the algorithm used to select the Endpoint of a Service for a
+// new connection.
+type LBAlgorithm string
+
+const (
+	// LBAlgorithmRandom selects the Endpoints with the same probability.
+	LBAlgorithmRandom LBAlgorithm = "random"
+	// LBAlgorithmWeighted selects the Endpoints with probabilities proportional
+	// to their weights.
+	LBAlgorithmWeighted LBAlgorithm = "weighted"
+	// LBAlgorithmSourceIPHash selects the Endpoint by the hash of the source IP,
+	// so that the connections from a client are always sent to the same
+	// Endpoint as long as the Endpoints don't change.
+	LBAlgorithmSourceIPHash LBAlgorithm = "source-ip-hash"
+	// LBAlgorithmMaglev selects the Endpoint by the hash of the connection with
+	// a Maglev lookup table, which minimizes the remapping of the connections
+	// when the Endpoints change, and is consistent across Nodes.
+	LBAlgorithmMaglev LBAlgorithm = "maglev"
+)
+
+// IsHashBased returns whether the algorithm selects the Endpoint by hashing
+// the packet fields.
+func (a LBAlgorithm) IsHashBased() bool {
+	return a == LBAlgorithmSourceIPHash || a == LBAlgorithmMaglev
+}
+
+// ServiceLBAlgorithmAnnotationKey is the annotation of a Service which sets
+// the algorithm used to select its Endpoints, one of "random" (default),
+// "weighted", "source-ip-hash" and "maglev".
+const ServiceLBAlgorithmAnnotationKey = "service.antrea.tanzu.vmware.com/lb-algorithm"
+
+// ServiceEndpointWeightsAnnotationKey is the annotation of a Service which
+// sets the weights of its Endpoints for the "weighted" algorithm, in the
+// format "<Endpoint IP>=<weight>,...". The weight of the Endpoints absent from
+// the annotation is 100.
+const ServiceEndpointWeightsAnnotationKey = "service.antrea.tanzu.vmware.com/endpoint-weights"
+
+// LoadBalancerMode is the mode in which the traffic sent to the ingress IPs of
+// a LoadBalancer Service from outside the cluster is load-balanced.
+type LoadBalancerMode string
+
+const (
+	// LoadBalancerModeNAT DNATs the traffic on the ingress Node, so the reply
+	// traffic is sent back to the client via the ingress Node.
+	LoadBalancerModeNAT LoadBalancerMode = "nat"
+	// LoadBalancerModeDSR forwards the traffic to the Node of the selected
+	// Endpoint via the tunnel without DNAT, and the Endpoint Node replies to
+	// the client directly with the ingress IP as the source IP.
+	LoadBalancerModeDSR LoadBalancerMode = "dsr"
+)
+
+// ServiceLoadBalancerModeAnnotationKey is the annotation of a LoadBalancer
+// Service which sets its load balancer mode, one of "nat" (default) and "dsr".
+const ServiceLoadBalancerModeAnnotationKey = "service.antrea.tanzu.vmware.com/load-balancer-mode"
+
 // ServiceInfo is the internal struct for caching service information.
 type ServiceInfo struct {
 	*k8sproxy.BaseServiceInfo
 	// cache for performance
 	OFProtocol openflow.Protocol
+	// DrainTimeout is the time the removed Endpoints keep serving their
+	// existing connections.
+	DrainTimeout time.Duration
+	// LBAlgorithm is the algorithm used to select the Endpoints.
+	LBAlgorithm LBAlgorithm
+	// EndpointWeights are the weights of the Endpoints by IP, which are only
+	// set for the weighted algorithm.
+	EndpointWeights map[string]uint16
+	// LoadBalancerMode is the mode of the traffic sent to the ingress IPs.
+	LoadBalancerMode LoadBalancerMode
 }
 
 func (si *ServiceInfo) Equal(bSvcInfo *ServiceInfo) bool {
@@ -33,21 +111,96 @@ func (si *ServiceInfo) Equal(bSvcInfo *ServiceInfo) bool {
 		si.StickyMaxAgeSeconds() == bSvcInfo.StickyMaxAgeSeconds() &&
 		si.OFProtocol == bSvcInfo.OFProtocol &&
 		si.Port() == bSvcInfo.Port() &&
-		len(si.LoadBalancerIPStrings()) == len(bSvcInfo.LoadBalancerIPStrings())
+		si.NodePort() == bSvcInfo.NodePort() &&
+		si.OnlyNodeLocalEndpoints() == bSvcInfo.OnlyNodeLocalEndpoints() &&
+		len(si.LoadBalancerIPStrings()) == len(bSvcInfo.LoadBalancerIPStrings()) &&
+		si.LBAlgorithm == bSvcInfo.LBAlgorithm &&
+		reflect.DeepEqual(si.EndpointWeights, bSvcInfo.EndpointWeights) &&
+		si.LoadBalancerMode == bSvcInfo.LoadBalancerMode
+}
+
+// ToOFProtocol returns the OpenFlow protocol matching the Service protocol.
+func ToOFProtocol(protocol corev1.Protocol) openflow.Protocol {
+	switch protocol {
+	case corev1.ProtocolUDP:
+		return openflow.ProtocolUDP
+	case corev1.ProtocolSCTP:
+		return openflow.ProtocolSCTP
+	default:
+		return openflow.ProtocolTCP
+	}
 }
 
 // NewServiceInfo returns a new k8sproxy.ServicePort which abstracts a serviceInfo.
 func NewServiceInfo(port *corev1.ServicePort, service *corev1.Service, baseInfo *k8sproxy.BaseServiceInfo) k8sproxy.ServicePort {
 	info := &ServiceInfo{BaseServiceInfo: baseInfo}
-	info.OFProtocol = openflow.ProtocolTCP
-	if port.Protocol == corev1.ProtocolUDP {
-		info.OFProtocol = openflow.ProtocolUDP
-	} else if port.Protocol == corev1.ProtocolSCTP {
-		info.OFProtocol = openflow.ProtocolSCTP
+	info.OFProtocol = ToOFProtocol(port.Protocol)
+	if value, ok := service.Annotations[ServiceDrainTimeoutAnnotationKey]; ok {
+		if seconds, err := strconv.Atoi(value); err != nil || seconds < 0 {
+			klog.Warningf("Ignoring invalid value %q of annotation %s for Service %s/%s", value, ServiceDrainTimeoutAnnotationKey, service.Namespace, service.Name)
+		} else {
+			info.DrainTimeout = time.Duration(seconds) * time.Second
+		}
+	}
+	info.LBAlgorithm = LBAlgorithmRandom
+	if value, ok := service.Annotations[ServiceLBAlgorithmAnnotationKey]; ok {
+		switch algorithm := LBAlgorithm(value); algorithm {
+		case LBAlgorithmRandom, LBAlgorithmWeighted, LBAlgorithmSourceIPHash, LBAlgorithmMaglev:
+			info.LBAlgorithm = algorithm
+		default:
+			klog.Warningf("Ignoring invalid value %q of annotation %s for Service %s/%s", value, ServiceLBAlgorithmAnnotationKey, service.Namespace, service.Name)
+		}
+	}
+	info.LoadBalancerMode = LoadBalancerModeNAT
+	if value, ok := service.Annotations[ServiceLoadBalancerModeAnnotationKey]; ok && service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		switch mode := LoadBalancerMode(value); mode {
+		case LoadBalancerModeNAT, LoadBalancerModeDSR:
+			info.LoadBalancerMode = mode
+		default:
+			klog.Warningf("Ignoring invalid value %q of annotation %s for Service %s/%s", value, ServiceLoadBalancerModeAnnotationKey, service.Namespace, service.Name)
+		}
+	}
+	// In DSR mode, the ingress Node doesn't track the connections, so every
+	// packet of a connection must be load-balanced to the same Endpoint, which
+	// requires a hash based algorithm.
+	if info.LoadBalancerMode == LoadBalancerModeDSR && !info.LBAlgorithm.IsHashBased() {
+		if service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+			info.LBAlgorithm = LBAlgorithmSourceIPHash
+		} else {
+			info.LBAlgorithm = LBAlgorithmMaglev
+		}
+	}
+	if info.LBAlgorithm == LBAlgorithmWeighted {
+		info.EndpointWeights = parseEndpointWeights(service)
 	}
 	return info
 }
 
+// parseEndpointWeights parses the Endpoint weights annotation of the Service.
+// The invalid entries are ignored.
+func parseEndpointWeights(service *corev1.Service) map[string]uint16 {
+	value, ok := service.Annotations[ServiceEndpointWeightsAnnotationKey]
+	if !ok {
+		return nil
+	}
+	weights := map[string]uint16{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) == 2 && net.ParseIP(strings.TrimSpace(parts[0])) != nil {
+			if weight, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16); err == nil {
+				weights[net.ParseIP(strings.TrimSpace(parts[0])).String()] = uint16(weight)
+				continue
+			}
+		}
+		klog.Warningf("Ignoring invalid entry %q of annotation %s for Service %s/%s", entry, ServiceEndpointWeightsAnnotationKey, service.Namespace, service.Name)
+	}
+	return weights
+}
+
 // NewEndpointInfo returns a new k8sproxy.Endpoint which abstracts an endpointsInfo.
 func NewEndpointInfo(baseInfo *k8sproxy.BaseEndpointInfo) k8sproxy.Endpoint {
 	return baseInfo
@@ -18,6 +18,7 @@ import (
 	"net"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 // Interface is the interface for routing container packets in host network.
@@ -26,6 +27,10 @@ type Interface interface {
 	// It should be idempotent and can be safely called on every startup.
 	Initialize(nodeConfig *config.NodeConfig) error
 
+	// Run should keep the host network configuration in sync with the changes of this Node, e.g. its addresses,
+	// until stopCh is closed.
+	Run(stopCh <-chan struct{})
+
 	// Reconcile should remove orphaned routes and related configuration based on the desired podCIDRs.
 	Reconcile(podCIDRs []string) error
 
@@ -43,4 +48,31 @@ type Interface interface {
 	// UnMigrateRoutesFromGw should move routes back from local gateway to original device linkName
 	// if linkName is nil, it should remove the routes.
 	UnMigrateRoutesFromGw(route *net.IPNet, linkName string) error
+
+	// AddSNATRule should add rule to SNAT outgoing traffic with the mark, using the provided SNAT IP.
+	// It should do nothing if the rule already exists, without error.
+	AddSNATRule(snatIP net.IP, mark uint32) error
+
+	// DeleteSNATRule should delete rule to SNAT outgoing traffic with the mark.
+	// It should do nothing if the rule doesn't exist, without error.
+	DeleteSNATRule(mark uint32) error
+
+	// AddNodePort should redirect the NodePort traffic to the Node IPs to the virtual NodePort IP, so that it can be
+	// load-balanced by OVS. If preserveClientIP is true, the NodePort traffic should not be SNAT'd.
+	// It should do nothing if the NodePort is already added, without error.
+	AddNodePort(port uint16, protocol binding.Protocol, preserveClientIP bool) error
+
+	// DeleteNodePort should stop redirecting the NodePort traffic to the virtual NodePort IP.
+	// It should do nothing if the NodePort doesn't exist, without error.
+	DeleteNodePort(port uint16, protocol binding.Protocol) error
+
+	// AddLoadBalancerDSR should route the traffic to the LoadBalancer ingress IP and port to the host gateway without
+	// connection tracking, so that it can be load-balanced by OVS in DSR mode, and let the reply traffic from the
+	// ingress IP and port, which is sent from the host gateway, bypass connection tracking too.
+	// It should do nothing if it's already added, without error.
+	AddLoadBalancerDSR(svcIP net.IP, port uint16, protocol binding.Protocol) error
+
+	// DeleteLoadBalancerDSR should stop routing the traffic to the LoadBalancer ingress IP and port to the host gateway.
+	// It should do nothing if it doesn't exist, without error.
+	DeleteLoadBalancerDSR(svcIP net.IP, port uint16, protocol binding.Protocol) error
 }
@@ -21,28 +21,51 @@ import (
 	"os/exec"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/types"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/ipset"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/iptables"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/util/env"
 )
 
 const (
 	// Antrea managed ipset.
-	// antreaPodIPSet contains all Pod CIDRs of this cluster.
+	// antreaPodIPSet contains all IPv4 Pod CIDRs of this cluster.
 	antreaPodIPSet = "ANTREA-POD-IP"
+	// antreaPodIP6Set contains all IPv6 Pod CIDRs of this cluster.
+	antreaPodIP6Set = "ANTREA-POD-IP6"
+	// antreaNodePortIPSet contains all NodePorts of the IPv4 addresses of this Node, in the form of
+	// "<nodeIP>,<protocol>:<port>".
+	antreaNodePortIPSet = "ANTREA-NODEPORT-IP"
+	// antreaNodePortLocalIPSet contains the NodePorts of the virtual NodePort IP whose traffic should not be SNAT'd,
+	// in the form of "<virtualNodePortIP>,<protocol>:<port>".
+	antreaNodePortLocalIPSet = "ANTREA-NODEPORT-LOCAL"
+	// antreaLoadBalancerDSRIPSet contains the ingress IPs and ports of the LoadBalancer Services in DSR mode, in the
+	// form of "<ingressIP>,<protocol>:<port>".
+	antreaLoadBalancerDSRIPSet = "ANTREA-LB-DSR"
 
 	// Antrea managed iptables chains.
 	antreaForwardChain     = "ANTREA-FORWARD"
+	antreaPreRoutingChain  = "ANTREA-PREROUTING"
+	antreaOutputChain      = "ANTREA-OUTPUT"
 	antreaPostRoutingChain = "ANTREA-POSTROUTING"
 	antreaMangleChain      = "ANTREA-MANGLE"
+	antreaRawChain         = "ANTREA-RAW"
+
+	// addrSubscribeRetryInterval is the interval to subscribe to the address updates of the Node again after the
+	// subscription fails.
+	addrSubscribeRetryInterval = 5 * time.Second
 )
 
 // Client implements Interface.
@@ -54,21 +77,53 @@ type Client struct {
 	encapMode   config.TrafficEncapModeType
 	serviceCIDR *net.IPNet
 	ipt         *iptables.Client
+	// ip6t manages the ip6tables rules. It's only set when the Node has an IPv6 Pod CIDR.
+	ip6t *iptables.Client
 	// nodeRoutes caches ip routes to remote Pods. It's a map of podCIDR to routes.
 	nodeRoutes sync.Map
+	// nodeNeighbors caches IPv6 neighbors of remote Node gateways. It's a map of podCIDR to neighbors.
+	nodeNeighbors sync.Map
+	// markToSNATIP caches marks to SNAT IPs. It's used in Egress feature.
+	markToSNATIP sync.Map
+	// enableNodePort indicates whether NodePort Services are handled by AntreaProxy.
+	enableNodePort bool
+	// nodePortIPs are the IPv4 addresses of this Node which NodePort Services can be accessed with.
+	nodePortIPs []net.IP
+	// nodePorts caches the NodePorts which have been added.
+	nodePorts map[nodePort]struct{}
+	// nodePortsMutex protects nodePortIPs and nodePorts, as nodePortIPs are updated when the addresses of this Node
+	// change.
+	nodePortsMutex sync.Mutex
+	// proxyHostNetwork indicates whether the ClusterIP traffic from the host network is handled by AntreaProxy.
+	proxyHostNetwork bool
+	// enableLoadBalancerDSR indicates whether LoadBalancer Services in DSR mode are handled by AntreaProxy.
+	enableLoadBalancerDSR bool
+	// loadBalancerDSRs caches the ingress IPs of the LoadBalancer Services in DSR mode. It's a map of ipset entries
+	// to ingress IPs.
+	loadBalancerDSRs sync.Map
+}
+
+// nodePort is a NodePort added to the Node IPs.
+type nodePort struct {
+	port     uint16
+	protocol binding.Protocol
 }
 
 // NewClient returns a route client.
-func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType) (*Client, error) {
+func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType, enableNodePort, proxyHostNetwork, enableLoadBalancerDSR bool) (*Client, error) {
 	ipt, err := iptables.New()
 	if err != nil {
 		return nil, fmt.Errorf("error creating IPTables instance: %v", err)
 	}
 
 	return &Client{
-		serviceCIDR: serviceCIDR,
-		encapMode:   encapMode,
-		ipt:         ipt,
+		serviceCIDR:           serviceCIDR,
+		encapMode:             encapMode,
+		ipt:                   ipt,
+		enableNodePort:        enableNodePort,
+		nodePorts:             map[nodePort]struct{}{},
+		proxyHostNetwork:      proxyHostNetwork,
+		enableLoadBalancerDSR: enableLoadBalancerDSR,
 	}, nil
 }
 
@@ -76,6 +131,13 @@ func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType) (*
 // It is idempotent and can be safely called on every startup.
 func (c *Client) Initialize(nodeConfig *config.NodeConfig) error {
 	c.nodeConfig = nodeConfig
+	if nodeConfig.PodIPv6CIDR != nil {
+		ip6t, err := iptables.NewIPv6()
+		if err != nil {
+			return fmt.Errorf("error creating IP6Tables instance: %v", err)
+		}
+		c.ip6t = ip6t
+	}
 
 	// Sets up the ipset that will be used in iptables.
 	if err := c.initIPSet(); err != nil {
@@ -106,6 +168,108 @@ func (c *Client) Initialize(nodeConfig *config.NodeConfig) error {
 		return err
 	}
 
+	// IPv6 forwarding is not enabled by default on most distributions.
+	if nodeConfig.PodIPv6CIDR != nil {
+		if err := enableIPv6Forwarding(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run keeps antreaNodePortIPSet in sync with the IPv4 addresses of this Node, so that NodePort Services can be
+// accessed with the addresses added after the Node started, and not with the deleted ones. IPv6 addresses are
+// ignored as NodePort Services are only supported for IPv4.
+func (c *Client) Run(stopCh <-chan struct{}) {
+	if !c.enableNodePort || c.encapMode.IsNetworkPolicyOnly() {
+		return
+	}
+	wait.Until(func() {
+		c.watchNodePortIPs(stopCh)
+	}, addrSubscribeRetryInterval, stopCh)
+}
+
+// watchNodePortIPs subscribes to the address updates of this Node, and syncs the NodePort IPs when any IPv4 address
+// is added or deleted. It returns when the subscription ends or stopCh is closed.
+func (c *Client) watchNodePortIPs(stopCh <-chan struct{}) {
+	ch := make(chan netlink.AddrUpdate, 100)
+	done := make(chan struct{})
+	defer close(done)
+	if err := netlink.AddrSubscribeWithOptions(ch, done, netlink.AddrSubscribeOptions{
+		ErrorCallback: func(err error) {
+			klog.Errorf("Error receiving address updates: %v", err)
+		},
+	}); err != nil {
+		klog.Errorf("Error subscribing to address updates: %v", err)
+		return
+	}
+	// The updates before the subscription may have been missed.
+	if err := c.syncNodePortIPs(); err != nil {
+		klog.Errorf("Error syncing NodePort IPs: %v", err)
+	}
+	for {
+		select {
+		case <-stopCh:
+			return
+		case update, ok := <-ch:
+			if !ok {
+				return
+			}
+			if update.LinkAddress.IP.To4() == nil {
+				continue
+			}
+			klog.V(2).Infof("Address %s was updated, syncing NodePort IPs", update.LinkAddress.String())
+			if err := c.syncNodePortIPs(); err != nil {
+				klog.Errorf("Error syncing NodePort IPs: %v", err)
+			}
+		}
+	}
+}
+
+// syncNodePortIPs updates the NodePort IPs with the current IPv4 addresses of this Node, adding the NodePorts on the
+// new addresses to antreaNodePortIPSet and deleting the ones on the stale addresses from it.
+func (c *Client) syncNodePortIPs() error {
+	nodePortIPs, err := c.getNodePortIPs()
+	if err != nil {
+		return err
+	}
+	c.nodePortsMutex.Lock()
+	defer c.nodePortsMutex.Unlock()
+	curIPs := sets.NewString()
+	for _, ip := range nodePortIPs {
+		curIPs.Insert(ip.String())
+	}
+	oldIPs := sets.NewString()
+	for _, ip := range c.nodePortIPs {
+		oldIPs.Insert(ip.String())
+	}
+	// The NodePort IPs are only updated after the ipset is updated successfully, so that the failed entries are
+	// retried in the next sync.
+	for _, ip := range nodePortIPs {
+		if oldIPs.Has(ip.String()) {
+			continue
+		}
+		for np := range c.nodePorts {
+			if err := ipset.AddEntry(antreaNodePortIPSet, getNodePortIPSetEntry(ip, np.port, np.protocol)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, ip := range c.nodePortIPs {
+		if curIPs.Has(ip.String()) {
+			continue
+		}
+		for np := range c.nodePorts {
+			if err := ipset.DelEntry(antreaNodePortIPSet, getNodePortIPSetEntry(ip, np.port, np.protocol)); err != nil {
+				return err
+			}
+		}
+	}
+	if !curIPs.Equal(oldIPs) {
+		klog.Infof("Updated NodePort IPs from %v to %v", oldIPs.List(), curIPs.List())
+	}
+	c.nodePortIPs = nodePortIPs
 	return nil
 }
 
@@ -115,16 +279,92 @@ func (c *Client) initIPSet() error {
 	if c.encapMode.IsNetworkPolicyOnly() {
 		return nil
 	}
-	if err := ipset.CreateIPSet(antreaPodIPSet, ipset.HashNet); err != nil {
+	if err := ipset.CreateIPSet(antreaPodIPSet, ipset.HashNet, false); err != nil {
 		return err
 	}
 	// Ensure its own PodCIDR is in it.
 	if err := ipset.AddEntry(antreaPodIPSet, c.nodeConfig.PodCIDR.String()); err != nil {
 		return err
 	}
+	if c.enableNodePort {
+		// The NodePorts are added to the sets again by AntreaProxy, flush the sets to remove the stale entries left
+		// by the previous run, e.g. the ones of the Services deleted while the Agent was down.
+		for _, setName := range []string{antreaNodePortIPSet, antreaNodePortLocalIPSet} {
+			if err := ipset.CreateIPSet(setName, ipset.HashIPPort, false); err != nil {
+				return err
+			}
+			if err := ipset.FlushIPSet(setName); err != nil {
+				return err
+			}
+		}
+		nodePortIPs, err := c.getNodePortIPs()
+		if err != nil {
+			return err
+		}
+		c.nodePortIPs = nodePortIPs
+	}
+	if c.enableLoadBalancerDSR {
+		if err := ipset.CreateIPSet(antreaLoadBalancerDSRIPSet, ipset.HashIPPort, false); err != nil {
+			return err
+		}
+		// Like the NodePort sets, the LoadBalancer IPs are added to the set again by AntreaProxy.
+		if err := ipset.FlushIPSet(antreaLoadBalancerDSRIPSet); err != nil {
+			return err
+		}
+	}
+	if c.nodeConfig.PodIPv6CIDR == nil {
+		return nil
+	}
+	if err := ipset.CreateIPSet(antreaPodIP6Set, ipset.HashNet, true); err != nil {
+		return err
+	}
+	// Ensure its own IPv6 PodCIDR is in it.
+	if err := ipset.AddEntry(antreaPodIP6Set, c.nodeConfig.PodIPv6CIDR.String()); err != nil {
+		return err
+	}
 	return nil
 }
 
+// getNodePortIPs returns the IPv4 addresses of this Node which NodePort Services can be accessed with, i.e. all
+// the addresses except the ones of the host gateway and the loopback interface, and the link-local addresses.
+func (c *Client) getNodePortIPs() ([]net.IP, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("error listing links: %v", err)
+	}
+	var ips []net.IP
+	for _, link := range links {
+		if link.Attrs().Name == c.nodeConfig.GatewayConfig.Name || link.Attrs().Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := netlink.AddrList(link, netlink.FAMILY_V4)
+		if err != nil {
+			return nil, fmt.Errorf("error listing addresses of link %s: %v", link.Attrs().Name, err)
+		}
+		for _, addr := range addrs {
+			if addr.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			ips = append(ips, addr.IP)
+		}
+	}
+	return ips, nil
+}
+
+// getNodePortIPSetEntry returns the ipset entry of the NodePort on the provided Node IP.
+func getNodePortIPSetEntry(nodeIP net.IP, port uint16, protocol binding.Protocol) string {
+	return fmt.Sprintf("%s,%s:%d", nodeIP, protocol, port)
+}
+
+// getIPSetName returns the name of the ipset which contains the Pod CIDRs of the same IP family as the provided
+// CIDR.
+func getIPSetName(cidr *net.IPNet) string {
+	if cidr.IP.To4() == nil {
+		return antreaPodIP6Set
+	}
+	return antreaPodIPSet
+}
+
 // writeEKSMangleRule writes an additional iptables mangle rule to the
 // iptablesData buffer, which is required to ensure that the reverse path for
 // NodePort Service traffic is correct on EKS.
@@ -151,6 +391,20 @@ func (c *Client) writeEKSMangleRule(iptablesData *bytes.Buffer) {
 // initIPTables ensure that the iptables infrastructure we use is set up.
 // It's idempotent and can safely be called on every startup.
 func (c *Client) initIPTables() error {
+	if err := c.initIPTablesForFamily(c.ipt, c.nodeConfig.PodCIDR, antreaPodIPSet, false); err != nil {
+		return err
+	}
+	if c.ip6t != nil {
+		if err := c.initIPTablesForFamily(c.ip6t, c.nodeConfig.PodIPv6CIDR, antreaPodIP6Set, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initIPTablesForFamily sets up the iptables (or ip6tables if isIPv6 is true) infrastructure for the Pod CIDR of
+// one IP family.
+func (c *Client) initIPTablesForFamily(ipt *iptables.Client, podCIDR *net.IPNet, podIPSet string, isIPv6 bool) error {
 	// Create the antrea managed chains and link them to built-in chains.
 	// We cannot use iptables-restore for these jump rules because there
 	// are non antrea managed rules in built-in chains.
@@ -159,12 +413,27 @@ func (c *Client) initIPTables() error {
 		{iptables.NATTable, iptables.PostRoutingChain, antreaPostRoutingChain, "Antrea: jump to Antrea postrouting rules"},
 		{iptables.MangleTable, iptables.PreRoutingChain, antreaMangleChain, "Antrea: jump to Antrea mangle rules"},
 	}
+	// NodePort Services are only supported for IPv4.
+	nodePortEnabled := c.enableNodePort && !isIPv6
+	if nodePortEnabled {
+		jumpRules = append(jumpRules,
+			struct{ table, srcChain, dstChain, comment string }{iptables.NATTable, iptables.PreRoutingChain, antreaPreRoutingChain, "Antrea: jump to Antrea prerouting rules"},
+			struct{ table, srcChain, dstChain, comment string }{iptables.NATTable, iptables.OutputChain, antreaOutputChain, "Antrea: jump to Antrea output rules"},
+		)
+	}
+	// LoadBalancer Services in DSR mode are only supported for IPv4.
+	loadBalancerDSREnabled := c.enableLoadBalancerDSR && !isIPv6
+	if loadBalancerDSREnabled {
+		jumpRules = append(jumpRules,
+			struct{ table, srcChain, dstChain, comment string }{iptables.RawTable, iptables.PreRoutingChain, antreaRawChain, "Antrea: jump to Antrea raw rules"},
+		)
+	}
 	for _, rule := range jumpRules {
-		if err := c.ipt.EnsureChain(rule.table, rule.dstChain); err != nil {
+		if err := ipt.EnsureChain(rule.table, rule.dstChain); err != nil {
 			return err
 		}
 		ruleSpec := []string{"-j", rule.dstChain, "-m", "comment", "--comment", rule.comment}
-		if err := c.ipt.EnsureRule(rule.table, rule.srcChain, ruleSpec); err != nil {
+		if err := ipt.EnsureRule(rule.table, rule.srcChain, ruleSpec); err != nil {
 			return err
 		}
 	}
@@ -179,11 +448,33 @@ func (c *Client) initIPTables() error {
 	hostGateway := c.nodeConfig.GatewayConfig.Name
 	// When Antrea is used to enforce NetworkPolicies in EKS, an additional iptables
 	// mangle rule is required. See https://github.com/vmware-tanzu/antrea/issues/678.
-	if env.IsCloudEKS() {
+	if env.IsCloudEKS() && !isIPv6 {
 		c.writeEKSMangleRule(iptablesData)
 	}
 	writeLine(iptablesData, "COMMIT")
 
+	writeLine(iptablesData, "*raw")
+	writeLine(iptablesData, iptables.MakeChainLine(antreaRawChain))
+	if loadBalancerDSREnabled {
+		// The traffic to the LoadBalancer ingress IPs in DSR mode is not tracked, so that it's not DNAT'd by
+		// kube-proxy, but routed to the host gateway. Its reply traffic from the host gateway is not tracked either,
+		// as the request traffic may not go through the Node, in which case the reply traffic would be invalid.
+		writeLine(iptablesData, []string{
+			"-A", antreaRawChain,
+			"-m", "comment", "--comment", `"Antrea: do not track DSR LoadBalancer packets"`,
+			"-m", "set", "--match-set", antreaLoadBalancerDSRIPSet, "dst,dst",
+			"-j", iptables.NoTrackTarget,
+		}...)
+		writeLine(iptablesData, []string{
+			"-A", antreaRawChain,
+			"-m", "comment", "--comment", `"Antrea: do not track DSR LoadBalancer reply packets"`,
+			"-i", hostGateway,
+			"-m", "set", "--match-set", antreaLoadBalancerDSRIPSet, "src,src",
+			"-j", iptables.NoTrackTarget,
+		}...)
+	}
+	writeLine(iptablesData, "COMMIT")
+
 	writeLine(iptablesData, "*filter")
 	writeLine(iptablesData, iptables.MakeChainLine(antreaForwardChain))
 	writeLine(iptablesData, []string{
@@ -204,23 +495,103 @@ func (c *Client) initIPTables() error {
 	// Antrea should not get involved.
 	writeLine(iptablesData, "*nat")
 	writeLine(iptablesData, iptables.MakeChainLine(antreaPostRoutingChain))
+	if nodePortEnabled {
+		writeLine(iptablesData, iptables.MakeChainLine(antreaPreRoutingChain))
+		writeLine(iptablesData, iptables.MakeChainLine(antreaOutputChain))
+		// The NodePort traffic to the Node IPs, no matter whether it comes from external hosts or the Node itself, is
+		// DNAT'd to the virtual NodePort IP, which is routed to the host gateway, so that OVS can do Endpoint
+		// selection for it.
+		for _, chain := range []string{antreaPreRoutingChain, antreaOutputChain} {
+			writeLine(iptablesData, []string{
+				"-A", chain,
+				"-m", "comment", "--comment", `"Antrea: DNAT NodePort packets to virtual NodePort IP"`,
+				"-m", "set", "--match-set", antreaNodePortIPSet, "dst,dst",
+				"-j", iptables.DNATTarget, "--to-destination", config.VirtualNodePortIP.String(),
+			}...)
+		}
+		// The NodePort traffic is masqueraded with the host gateway IP, so that the reply packets from the Endpoints
+		// can be sent back to the host network, whether the client is external or local, and the Endpoint is local
+		// or remote. The NodePorts in antreaNodePortLocalIPSet are excluded to preserve the client IP, as they are
+		// only load-balanced to local Endpoints, whose reply packets always go through the host gateway.
+		writeLine(iptablesData, []string{
+			"-A", antreaPostRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: masquerade NodePort packets"`,
+			"-o", hostGateway, "-d", config.VirtualNodePortIP.String(),
+			"-m", "set", "!", "--match-set", antreaNodePortLocalIPSet, "dst,dst",
+			"-j", iptables.MasqueradeTarget,
+		}...)
+	}
+	if c.proxyHostNetwork && !isIPv6 {
+		// The ClusterIP traffic from the host network is masqueraded with the host gateway IP, even if the client
+		// socket is bound to another IP of the Node, so that the reply packets from remote Endpoints are sent back
+		// via the tunnel and can be reverse translated by OVS.
+		writeLine(iptablesData, []string{
+			"-A", antreaPostRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: masquerade host network to ClusterIP packets"`,
+			"-o", hostGateway, "-d", c.serviceCIDR.String(),
+			"-j", iptables.MasqueradeTarget,
+		}...)
+	}
 	if !c.encapMode.IsNetworkPolicyOnly() {
+		// The SNAT rules of Egresses must be in front of the masquerade rule. Egress only supports IPv4 SNAT IPs.
+		if !isIPv6 {
+			c.markToSNATIP.Range(func(key, value interface{}) bool {
+				snatMark := key.(uint32)
+				snatIP := value.(net.IP)
+				writeLine(iptablesData, append([]string{"-A", antreaPostRoutingChain}, c.snatRuleSpec(snatIP, snatMark, true)...)...)
+				return true
+			})
+		}
 		writeLine(iptablesData, []string{
 			"-A", antreaPostRoutingChain,
 			"-m", "comment", "--comment", `"Antrea: masquerade pod to external packets"`,
-			"-s", c.nodeConfig.PodCIDR.String(), "-m", "set", "!", "--match-set", antreaPodIPSet, "dst",
+			"-s", podCIDR.String(), "-m", "set", "!", "--match-set", podIPSet, "dst",
 			"-j", iptables.MasqueradeTarget,
 		}...)
 	}
 	writeLine(iptablesData, "COMMIT")
 
 	// Setting --noflush to keep the previous contents (i.e. non antrea managed chains) of the tables.
-	if err := c.ipt.Restore(iptablesData.Bytes(), false); err != nil {
+	if err := ipt.Restore(iptablesData.Bytes(), false); err != nil {
 		return err
 	}
 	return nil
 }
 
+// snatRuleSpec returns the iptables rule spec which SNATs the packets with the provided mark, using the SNAT IP. The
+// comment must be quoted when the rule spec is written to the iptables-restore input.
+func (c *Client) snatRuleSpec(snatIP net.IP, snatMark uint32, quoteComment bool) []string {
+	comment := "Antrea: SNAT Pod to external packets"
+	if quoteComment {
+		comment = `"` + comment + `"`
+	}
+	return []string{
+		"-m", "comment", "--comment", comment,
+		// The packets to local Pods are excluded, as they might have the same mark.
+		"!", "-o", c.nodeConfig.GatewayConfig.Name,
+		"-m", "mark", "--mark", fmt.Sprintf("%#x/%#x", snatMark, types.SNATIPMarkMask),
+		"-j", iptables.SNATTarget, "--to", snatIP.String(),
+	}
+}
+
+// AddSNATRule adds an iptables rule to SNAT the packets with the provided mark, using the SNAT IP.
+func (c *Client) AddSNATRule(snatIP net.IP, mark uint32) error {
+	c.markToSNATIP.Store(mark, snatIP)
+	return c.ipt.InsertRule(iptables.NATTable, antreaPostRoutingChain, c.snatRuleSpec(snatIP, mark, false))
+}
+
+// DeleteSNATRule deletes the iptables rule which SNATs the packets with the provided mark.
+func (c *Client) DeleteSNATRule(mark uint32) error {
+	value, ok := c.markToSNATIP.Load(mark)
+	if !ok {
+		klog.Warningf("Didn't find SNAT rule with mark %#x", mark)
+		return nil
+	}
+	c.markToSNATIP.Delete(mark)
+	snatIP := value.(net.IP)
+	return c.ipt.DeleteRule(iptables.NATTable, antreaPostRoutingChain, c.snatRuleSpec(snatIP, mark, false))
+}
+
 func (c *Client) initIPRoutes() error {
 	if c.encapMode.IsNetworkPolicyOnly() {
 		gwLink := util.GetNetLink(c.nodeConfig.GatewayConfig.Name)
@@ -229,6 +600,57 @@ func (c *Client) initIPRoutes() error {
 			return fmt.Errorf("failed to add address %s to gw %s: %v", gwIP, gwLink.Attrs().Name, err)
 		}
 	}
+	if c.enableNodePort {
+		// The NodePort traffic is DNAT'd to the virtual NodePort IP, which must be sent to OVS.
+		if err := c.addVirtualIPRoute(config.VirtualNodePortIP); err != nil {
+			return err
+		}
+	}
+	if c.enableNodePort || c.proxyHostNetwork || c.enableLoadBalancerDSR {
+		// The virtual Service IP is used as the source IP of the Service traffic which is load-balanced from the host
+		// gateway to the host gateway again, the reply traffic to it must be sent back to OVS. It's also the next hop
+		// of the routes to the LoadBalancer ingress IPs in DSR mode.
+		if err := c.addVirtualIPRoute(config.VirtualServiceIP); err != nil {
+			return err
+		}
+	}
+	if c.proxyHostNetwork {
+		// Route the Service CIDR to the host gateway via the virtual Service IP, so that the ClusterIP traffic from
+		// the host network is sent to OVS with the global virtual MAC, where it's load-balanced by AntreaProxy.
+		route := &netlink.Route{
+			Dst:       c.serviceCIDR,
+			Gw:        config.VirtualServiceIP,
+			Src:       c.nodeConfig.GatewayConfig.IP,
+			LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+		}
+		if err := netlink.RouteReplace(route); err != nil {
+			return fmt.Errorf("failed to install route to Service CIDR %s: %v", c.serviceCIDR, err)
+		}
+	}
+	return nil
+}
+
+// addVirtualIPRoute routes the virtual IP to the host gateway, and resolves it to the global virtual MAC as there is
+// no host owning it.
+func (c *Client) addVirtualIPRoute(virtualIP net.IP) error {
+	route := &netlink.Route{
+		Dst:       &net.IPNet{IP: virtualIP, Mask: net.CIDRMask(32, 32)},
+		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+		Scope:     netlink.SCOPE_LINK,
+	}
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to virtual IP %s: %v", virtualIP, err)
+	}
+	neigh := &netlink.Neigh{
+		LinkIndex:    c.nodeConfig.GatewayConfig.LinkIndex,
+		Family:       netlink.FAMILY_V4,
+		State:        netlink.NUD_PERMANENT,
+		IP:           virtualIP,
+		HardwareAddr: openflow.GlobalVirtualMAC,
+	}
+	if err := netlink.NeighSet(neigh); err != nil {
+		return fmt.Errorf("failed to add neighbor for virtual IP %s: %v", virtualIP, err)
+	}
 	return nil
 }
 
@@ -237,26 +659,32 @@ func (c *Client) initIPRoutes() error {
 func (c *Client) Reconcile(podCIDRs []string) error {
 	desiredPodCIDRs := sets.NewString(podCIDRs...)
 
-	// Remove orphaned podCIDRs from antreaPodIPSet.
-	entries, err := ipset.ListEntries(antreaPodIPSet)
-	if err != nil {
-		return err
+	// Remove orphaned podCIDRs from antreaPodIPSet and antreaPodIP6Set.
+	podIPSets := []string{antreaPodIPSet}
+	if c.nodeConfig.PodIPv6CIDR != nil {
+		podIPSets = append(podIPSets, antreaPodIP6Set)
 	}
-	for _, entry := range entries {
-		if desiredPodCIDRs.Has(entry) {
-			continue
-		}
-		klog.Infof("Deleting orphaned PodIP %s from ipset and route table", entry)
-		if err := ipset.DelEntry(antreaPodIPSet, entry); err != nil {
-			return err
-		}
-		_, cidr, err := net.ParseCIDR(entry)
+	for _, podIPSet := range podIPSets {
+		entries, err := ipset.ListEntries(podIPSet)
 		if err != nil {
 			return err
 		}
-		route := &netlink.Route{Dst: cidr}
-		if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
-			return err
+		for _, entry := range entries {
+			if desiredPodCIDRs.Has(entry) {
+				continue
+			}
+			klog.Infof("Deleting orphaned PodIP %s from ipset and route table", entry)
+			if err := ipset.DelEntry(podIPSet, entry); err != nil {
+				return err
+			}
+			_, cidr, err := net.ParseCIDR(entry)
+			if err != nil {
+				return err
+			}
+			route := &netlink.Route{Dst: cidr}
+			if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
+				return err
+			}
 		}
 	}
 
@@ -266,7 +694,14 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 		return fmt.Errorf("error listing ip routes: %v", err)
 	}
 	for _, route := range routes {
-		if reflect.DeepEqual(route.Dst, c.nodeConfig.PodCIDR) {
+		if reflect.DeepEqual(route.Dst, c.nodeConfig.PodCIDR) || reflect.DeepEqual(route.Dst, c.nodeConfig.PodIPv6CIDR) {
+			continue
+		}
+		if c.proxyHostNetwork && route.Dst != nil && route.Dst.String() == c.serviceCIDR.String() {
+			continue
+		}
+		// Skip the IPv6 link-local and multicast routes which are added by the kernel.
+		if route.Dst != nil && (route.Dst.IP.IsLinkLocalUnicast() || route.Dst.IP.IsMulticast()) {
 			continue
 		}
 		if desiredPodCIDRs.Has(route.Dst.String()) {
@@ -284,25 +719,52 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 func (c *Client) listIPRoutesOnGW() ([]netlink.Route, error) {
 	filter := &netlink.Route{
 		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex}
-	return netlink.RouteListFiltered(netlink.FAMILY_V4, filter, netlink.RT_FILTER_OIF)
+	family := netlink.FAMILY_V4
+	if c.nodeConfig.PodIPv6CIDR != nil {
+		family = netlink.FAMILY_ALL
+	}
+	return netlink.RouteListFiltered(family, filter, netlink.RT_FILTER_OIF)
 }
 
 // AddRoutes adds routes to a new podCIDR. It overrides the routes if they already exist.
 func (c *Client) AddRoutes(podCIDR *net.IPNet, nodeIP, nodeGwIP net.IP) error {
 	podCIDRStr := podCIDR.String()
 	// Add this podCIDR to antreaPodIPSet so that packets to them won't be masqueraded when they leave the host.
-	if err := ipset.AddEntry(antreaPodIPSet, podCIDRStr); err != nil {
+	if err := ipset.AddEntry(getIPSetName(podCIDR), podCIDRStr); err != nil {
 		return err
 	}
+	// The peer Node IP is compared with the local Node IP of the same IP family.
+	localNodeIP := c.nodeConfig.NodeIPAddr
+	if nodeIP.To4() == nil && c.nodeConfig.NodeIPv6Addr != nil {
+		localNodeIP = c.nodeConfig.NodeIPv6Addr
+	}
 	// Install routes to this Node.
 	route := &netlink.Route{
 		Dst: podCIDR,
 	}
-	if c.encapMode.NeedsEncapToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
+	if c.encapMode.NeedsEncapToPeer(nodeIP, localNodeIP) {
 		route.Flags = int(netlink.FLAG_ONLINK)
 		route.LinkIndex = c.nodeConfig.GatewayConfig.LinkIndex
 		route.Gw = nodeGwIP
-	} else if !c.encapMode.NeedsRoutingToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
+		if nodeGwIP.To4() == nil {
+			// Resolve the IPv6 address of the peer gateway to the global virtual MAC with a permanent
+			// neighbor entry, which is the IPv6 counterpart of the ARP responder flows. An NDP responder
+			// in OVS would have to rewrite the flags and the Source Link-Layer Address option of the
+			// Neighbor Solicitation into the ones of a Neighbor Advertisement, with the nd_reserved and
+			// nd_options_type fields which require OVS 2.11 and are not supported by libOpenflow.
+			neigh := &netlink.Neigh{
+				LinkIndex:    c.nodeConfig.GatewayConfig.LinkIndex,
+				Family:       netlink.FAMILY_V6,
+				State:        netlink.NUD_PERMANENT,
+				IP:           nodeGwIP,
+				HardwareAddr: openflow.GlobalVirtualMAC,
+			}
+			if err := netlink.NeighSet(neigh); err != nil {
+				return fmt.Errorf("failed to add neighbor %s for peer gateway with netlink: %v", nodeGwIP, err)
+			}
+			c.nodeNeighbors.Store(podCIDRStr, neigh)
+		}
+	} else if !c.encapMode.NeedsRoutingToPeer(nodeIP, localNodeIP) {
 		// NoEncap traffic need routing help.
 		route.Gw = nodeIP
 	} else {
@@ -320,10 +782,19 @@ func (c *Client) AddRoutes(podCIDR *net.IPNet, nodeIP, nodeGwIP net.IP) error {
 func (c *Client) DeleteRoutes(podCIDR *net.IPNet) error {
 	podCIDRStr := podCIDR.String()
 	// Delete this podCIDR from antreaPodIPSet as the CIDR is no longer for Pods.
-	if err := ipset.DelEntry(antreaPodIPSet, podCIDRStr); err != nil {
+	if err := ipset.DelEntry(getIPSetName(podCIDR), podCIDRStr); err != nil {
 		return err
 	}
 
+	if n, exists := c.nodeNeighbors.Load(podCIDRStr); exists {
+		neigh := n.(*netlink.Neigh)
+		klog.V(4).Infof("Deleting neighbor %v", neigh)
+		if err := netlink.NeighDel(neigh); err != nil && err != unix.ENOENT {
+			return err
+		}
+		c.nodeNeighbors.Delete(podCIDRStr)
+	}
+
 	i, exists := c.nodeRoutes.Load(podCIDRStr)
 	if !exists {
 		return nil
@@ -337,6 +808,99 @@ func (c *Client) DeleteRoutes(podCIDR *net.IPNet) error {
 	return nil
 }
 
+// AddNodePort adds the NodePort on all the Node IPs to antreaNodePortIPSet, so that the NodePort traffic is DNAT'd
+// to the virtual NodePort IP. If preserveClientIP is true, the NodePort on the virtual NodePort IP is added to
+// antreaNodePortLocalIPSet, otherwise it's removed from it.
+func (c *Client) AddNodePort(port uint16, protocol binding.Protocol, preserveClientIP bool) error {
+	c.nodePortsMutex.Lock()
+	defer c.nodePortsMutex.Unlock()
+	localEntry := getNodePortIPSetEntry(config.VirtualNodePortIP, port, protocol)
+	if preserveClientIP {
+		if err := ipset.AddEntry(antreaNodePortLocalIPSet, localEntry); err != nil {
+			return err
+		}
+	} else {
+		if err := ipset.DelEntry(antreaNodePortLocalIPSet, localEntry); err != nil {
+			return err
+		}
+	}
+	for _, nodeIP := range c.nodePortIPs {
+		if err := ipset.AddEntry(antreaNodePortIPSet, getNodePortIPSetEntry(nodeIP, port, protocol)); err != nil {
+			return err
+		}
+	}
+	c.nodePorts[nodePort{port: port, protocol: protocol}] = struct{}{}
+	return nil
+}
+
+// DeleteNodePort deletes the NodePort on all the Node IPs from antreaNodePortIPSet, and the NodePort on the virtual
+// NodePort IP from antreaNodePortLocalIPSet.
+func (c *Client) DeleteNodePort(port uint16, protocol binding.Protocol) error {
+	c.nodePortsMutex.Lock()
+	defer c.nodePortsMutex.Unlock()
+	for _, nodeIP := range c.nodePortIPs {
+		if err := ipset.DelEntry(antreaNodePortIPSet, getNodePortIPSetEntry(nodeIP, port, protocol)); err != nil {
+			return err
+		}
+	}
+	if err := ipset.DelEntry(antreaNodePortLocalIPSet, getNodePortIPSetEntry(config.VirtualNodePortIP, port, protocol)); err != nil {
+		return err
+	}
+	delete(c.nodePorts, nodePort{port: port, protocol: protocol})
+	return nil
+}
+
+// AddLoadBalancerDSR adds the ingress IP and port to antreaLoadBalancerDSRIPSet, so that the traffic to it and the
+// reply traffic from it are not tracked, and routes the ingress IP to the host gateway via the virtual Service IP, so
+// that the traffic to it is sent to OVS with the global virtual MAC. The route also makes the reply traffic from the
+// ingress IP, which is received from the host gateway, pass the reverse path filtering.
+func (c *Client) AddLoadBalancerDSR(svcIP net.IP, port uint16, protocol binding.Protocol) error {
+	entry := getNodePortIPSetEntry(svcIP, port, protocol)
+	route := &netlink.Route{
+		Dst:       &net.IPNet{IP: svcIP, Mask: net.CIDRMask(32, 32)},
+		Gw:        config.VirtualServiceIP,
+		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+	}
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to LoadBalancer ingress IP %s: %v", svcIP, err)
+	}
+	if err := ipset.AddEntry(antreaLoadBalancerDSRIPSet, entry); err != nil {
+		return err
+	}
+	c.loadBalancerDSRs.Store(entry, svcIP)
+	return nil
+}
+
+// DeleteLoadBalancerDSR deletes the ingress IP and port from antreaLoadBalancerDSRIPSet, and the route to the ingress
+// IP if no other port of it is in DSR mode.
+func (c *Client) DeleteLoadBalancerDSR(svcIP net.IP, port uint16, protocol binding.Protocol) error {
+	entry := getNodePortIPSetEntry(svcIP, port, protocol)
+	if err := ipset.DelEntry(antreaLoadBalancerDSRIPSet, entry); err != nil {
+		return err
+	}
+	c.loadBalancerDSRs.Delete(entry)
+	inUse := false
+	c.loadBalancerDSRs.Range(func(_, value interface{}) bool {
+		if value.(net.IP).Equal(svcIP) {
+			inUse = true
+			return false
+		}
+		return true
+	})
+	if inUse {
+		return nil
+	}
+	route := &netlink.Route{
+		Dst:       &net.IPNet{IP: svcIP, Mask: net.CIDRMask(32, 32)},
+		Gw:        config.VirtualServiceIP,
+		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+	}
+	if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
+		return fmt.Errorf("failed to delete route to LoadBalancer ingress IP %s: %v", svcIP, err)
+	}
+	return nil
+}
+
 // Join all words with spaces, terminate with newline and write to buf.
 func writeLine(buf *bytes.Buffer, words ...string) {
 	// We avoid strings.Join for performance reasons.
@@ -360,6 +924,16 @@ func disableICMPSendRedirects(intfName string) error {
 	return nil
 }
 
+func enableIPv6Forwarding() error {
+	cmdStr := "echo 1 > /proc/sys/net/ipv6/conf/all/forwarding"
+	cmd := exec.Command("/bin/sh", "-c", cmdStr)
+	if err := cmd.Run(); err != nil {
+		klog.Errorf("Failed to enable IPv6 forwarding: %v", err)
+		return err
+	}
+	return nil
+}
+
 // MigrateRoutesToGw moves routes (including assigned IP addresses if any) from link linkName to
 // host gateway.
 func (c *Client) MigrateRoutesToGw(linkName string) error {
@@ -28,6 +28,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/winfirewall"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 const (
@@ -44,7 +45,7 @@ type Client struct {
 }
 
 // NewClient returns a route client.
-func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType) (*Client, error) {
+func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType, enableNodePort, proxyHostNetwork, enableLoadBalancerDSR bool) (*Client, error) {
 	nr := netroute.New()
 	return &Client{
 		nr:          nr,
@@ -73,6 +74,10 @@ func (c *Client) Initialize(nodeConfig *config.NodeConfig) error {
 	return nil
 }
 
+// Run does nothing on Windows as NodePort Services are not handled by the route client on Windows.
+func (c *Client) Run(stopCh <-chan struct{}) {
+}
+
 // Reconcile removes the orphaned routes and related configuration based on the desired podCIDRs. Only the route
 // entries on the host gateway interface are stored in the cache.
 func (c *Client) Reconcile(podCIDRs []string) error {
@@ -150,6 +155,36 @@ func (c *Client) UnMigrateRoutesFromGw(route *net.IPNet, linkName string) error
 	return errors.New("UnMigrateRoutesFromGw is unsupported on Windows")
 }
 
+// AddSNATRule is not supported on Windows.
+func (c *Client) AddSNATRule(snatIP net.IP, mark uint32) error {
+	return errors.New("AddSNATRule is unsupported on Windows")
+}
+
+// DeleteSNATRule is not supported on Windows.
+func (c *Client) DeleteSNATRule(mark uint32) error {
+	return errors.New("DeleteSNATRule is unsupported on Windows")
+}
+
+// AddNodePort is not supported on Windows.
+func (c *Client) AddNodePort(port uint16, protocol binding.Protocol, preserveClientIP bool) error {
+	return errors.New("AddNodePort is unsupported on Windows")
+}
+
+// DeleteNodePort is not supported on Windows.
+func (c *Client) DeleteNodePort(port uint16, protocol binding.Protocol) error {
+	return errors.New("DeleteNodePort is unsupported on Windows")
+}
+
+// AddLoadBalancerDSR is not supported on Windows.
+func (c *Client) AddLoadBalancerDSR(svcIP net.IP, port uint16, protocol binding.Protocol) error {
+	return errors.New("AddLoadBalancerDSR is unsupported on Windows")
+}
+
+// DeleteLoadBalancerDSR is not supported on Windows.
+func (c *Client) DeleteLoadBalancerDSR(svcIP net.IP, port uint16, protocol binding.Protocol) error {
+	return errors.New("DeleteLoadBalancerDSR is unsupported on Windows")
+}
+
 func (c *Client) listRoutes() (map[string]*netroute.Route, error) {
 	routes, err := c.nr.GetNetRoutesAll()
 	if err != nil {
@@ -43,15 +43,16 @@ type Address interface {
 
 // PolicyRule groups configurations to set up conjunctive match for egress/ingress policy rules.
 type PolicyRule struct {
-	Direction v1beta1.Direction
-	From      []Address
-	To        []Address
-	Service   []v1beta1.Service
-	Action    *secv1alpha1.RuleAction
-	Priority  *uint16
-	FlowID    uint32
-	TableID   binding.TableIDType
-	PolicyRef *v1beta1.NetworkPolicyReference
+	Direction     v1beta1.Direction
+	From          []Address
+	To            []Address
+	Service       []v1beta1.Service
+	Action        *secv1alpha1.RuleAction
+	Priority      *uint16
+	FlowID        uint32
+	TableID       binding.TableIDType
+	PolicyRef     *v1beta1.NetworkPolicyReference
+	EnableLogging bool
 }
 
 // IsAntreaNetworkPolicyRule returns if a PolicyRule is created for Antrea NetworkPolicy types.
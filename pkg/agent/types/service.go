@@ -0,0 +1,37 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// LBAlgorithm is the algorithm used by the OpenFlow group of a Service to
+// select the Endpoint of a new connection.
+type LBAlgorithm uint8
+
+const (
+	// LBAlgorithmRandom selects the Endpoints with the probabilities
+	// proportional to the weights of their buckets, which are all the same
+	// unless weights are given.
+	LBAlgorithmRandom LBAlgorithm = iota
+	// LBAlgorithmSourceIPHash selects the Endpoint by the hash of the source IP.
+	LBAlgorithmSourceIPHash
+	// LBAlgorithmMaglev selects the Endpoint by the symmetric hash of the
+	// connection with a Maglev lookup table.
+	LBAlgorithmMaglev
+)
+
+// IsHashBased returns whether the algorithm selects the Endpoint by hashing
+// the packet fields.
+func (a LBAlgorithm) IsHashBased() bool {
+	return a == LBAlgorithmSourceIPHash || a == LBAlgorithmMaglev
+}
@@ -27,20 +27,38 @@ const (
 	// The hash:net set type uses a hash to store different sized IP network addresses.
 	// The lookup time grows linearly with the number of the different prefix values added to the set.
 	HashNet SetType = "hash:net"
+	// The hash:ip,port set type uses a hash to store IP address and protocol-port pairs.
+	// Entries are in the form of "<ip>,<protocol>:<port>", e.g. "192.168.1.1,tcp:30000".
+	HashIPPort SetType = "hash:ip,port"
 )
 
 // memberPattern is used to match the members part of ipset list result.
 var memberPattern = regexp.MustCompile("(?m)^(.*\n)*Members:\n")
 
 // CreateIPSet creates a new set, it will ignore error when the set already exists.
-func CreateIPSet(name string, setType SetType) error {
-	cmd := exec.Command("ipset", "create", name, string(setType), "-exist")
+// If isIPv6 is true, the set is created for IPv6 addresses.
+func CreateIPSet(name string, setType SetType, isIPv6 bool) error {
+	var cmd *exec.Cmd
+	if isIPv6 {
+		cmd = exec.Command("ipset", "create", name, string(setType), "family", "inet6", "-exist")
+	} else {
+		cmd = exec.Command("ipset", "create", name, string(setType), "-exist")
+	}
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("error creating ipset %s: %v", name, err)
 	}
 	return nil
 }
 
+// FlushIPSet deletes all the entries of the set.
+func FlushIPSet(name string) error {
+	cmd := exec.Command("ipset", "flush", name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error flushing ipset %s: %v", name, err)
+	}
+	return nil
+}
+
 // AddEntry adds a new entry to the set, it will ignore error when the entry already exists.
 func AddEntry(name string, entry string) error {
 	cmd := exec.Command("ipset", "add", name, entry, "-exist")
@@ -34,12 +34,16 @@ const (
 
 	AcceptTarget     = "ACCEPT"
 	MasqueradeTarget = "MASQUERADE"
+	SNATTarget       = "SNAT"
+	DNATTarget       = "DNAT"
 	MarkTarget       = "MARK"
 	ConnTrackTarget  = "CT"
+	NoTrackTarget    = "NOTRACK"
 
 	PreRoutingChain  = "PREROUTING"
 	ForwardChain     = "FORWARD"
 	PostRoutingChain = "POSTROUTING"
+	OutputChain      = "OUTPUT"
 
 	waitSeconds              = 10
 	waitIntervalMicroSeconds = 200000
@@ -53,6 +57,8 @@ type Client struct {
 	ipt *iptables.IPTables
 	// restoreWaitSupported indicates whether iptables-restore supports --wait flag.
 	restoreWaitSupported bool
+	// isIPv6 indicates whether the Client manages ip6tables instead of iptables.
+	isIPv6 bool
 }
 
 func New() (*Client, error) {
@@ -63,6 +69,23 @@ func New() (*Client, error) {
 	return &Client{ipt: ipt, restoreWaitSupported: isRestoreWaitSupported(ipt)}, nil
 }
 
+// NewIPv6 returns a Client which manages the ip6tables rules.
+func NewIPv6() (*Client, error) {
+	ipt, err := iptables.NewWithProtocol(iptables.ProtocolIPv6)
+	if err != nil {
+		return nil, fmt.Errorf("error creating IP6Tables instance: %v", err)
+	}
+	return &Client{ipt: ipt, restoreWaitSupported: isRestoreWaitSupported(ipt), isIPv6: true}, nil
+}
+
+// restoreCommand returns the name of the iptables-restore command for the managed IP family.
+func (c *Client) restoreCommand() string {
+	if c.isIPv6 {
+		return "ip6tables-restore"
+	}
+	return "iptables-restore"
+}
+
 func isRestoreWaitSupported(ipt *iptables.IPTables) bool {
 	major, minor, patch := ipt.GetIptablesVersion()
 	version := semver.Version{Major: uint64(major), Minor: uint64(minor), Patch: uint64(patch)}
@@ -101,6 +124,38 @@ func (c *Client) EnsureRule(table string, chain string, ruleSpec []string) error
 	return nil
 }
 
+// InsertRule checks if target rule already exists, inserts it at the beginning of the chain if not.
+func (c *Client) InsertRule(table string, chain string, ruleSpec []string) error {
+	exist, err := c.ipt.Exists(table, chain, ruleSpec...)
+	if err != nil {
+		return fmt.Errorf("error checking if rule %v exists in table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	if exist {
+		return nil
+	}
+	if err := c.ipt.Insert(table, chain, 1, ruleSpec...); err != nil {
+		return fmt.Errorf("error inserting rule %v to table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	klog.V(2).Infof("Inserted rule %v to table %s chain %s", ruleSpec, table, chain)
+	return nil
+}
+
+// DeleteRule checks if target rule already exists, deletes the rule if found.
+func (c *Client) DeleteRule(table string, chain string, ruleSpec []string) error {
+	exist, err := c.ipt.Exists(table, chain, ruleSpec...)
+	if err != nil {
+		return fmt.Errorf("error checking if rule %v exists in table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	if !exist {
+		return nil
+	}
+	if err := c.ipt.Delete(table, chain, ruleSpec...); err != nil {
+		return fmt.Errorf("error deleting rule %v from table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	klog.V(2).Infof("Deleted rule %v from table %s chain %s", ruleSpec, table, chain)
+	return nil
+}
+
 // Restore calls iptable-restore to restore iptables with the provided content.
 // If flush is true, all previous contents of the respective tables will be flushed.
 // Otherwise only involved chains will be flushed.
@@ -109,7 +164,7 @@ func (c *Client) Restore(data []byte, flush bool) error {
 	if !flush {
 		args = append(args, "--noflush")
 	}
-	cmd := exec.Command("iptables-restore", args...)
+	cmd := exec.Command(c.restoreCommand(), args...)
 	cmd.Stdin = bytes.NewBuffer(data)
 	stderr := &bytes.Buffer{}
 	cmd.Stderr = stderr
@@ -131,14 +186,17 @@ func (c *Client) Restore(data []byte, flush bool) error {
 		defer unlockFunc()
 	}
 	if err := cmd.Run(); err != nil {
-		klog.Errorf("Failed to execute iptables-restore: %v\nstdin:\n%s\nstderr:\n%s", err, data, stderr)
-		return fmt.Errorf("error executing iptables-restore: %v", err)
+		klog.Errorf("Failed to execute %s: %v\nstdin:\n%s\nstderr:\n%s", c.restoreCommand(), err, data, stderr)
+		return fmt.Errorf("error executing %s: %v", c.restoreCommand(), err)
 	}
 	return nil
 }
 
 // Save calls iptables-saves to dump chains and tables in iptables.
 func (c *Client) Save() ([]byte, error) {
+	if c.isIPv6 {
+		return exec.Command("ip6tables-save", "-c").CombinedOutput()
+	}
 	return exec.Command("iptables-save", "-c").CombinedOutput()
 }
 
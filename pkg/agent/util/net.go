@@ -78,6 +78,18 @@ func GenerateNodeTunnelInterfaceName(nodeName string) string {
 	return generateInterfaceName(GenerateNodeTunnelInterfaceKey(nodeName), nodeName, false)
 }
 
+// GenerateClusterTunnelInterfaceKey generates a unique string for the tunnel
+// interface to a remote cluster as: cluster/<cluster-ID>.
+func GenerateClusterTunnelInterfaceKey(clusterID string) string {
+	return fmt.Sprintf("cluster/%s", clusterID)
+}
+
+// GenerateClusterTunnelInterfaceName generates a unique interface name for the
+// tunnel to the gateway Node of a remote cluster, using the cluster ID.
+func GenerateClusterTunnelInterfaceName(clusterID string) string {
+	return generateInterfaceName(GenerateClusterTunnelInterfaceKey(clusterID), "mc-"+clusterID, true)
+}
+
 type LinkNotFound struct {
 	error
 }
@@ -134,15 +134,19 @@ func ConfigureLinkAddress(idx int, gwIPNet *net.IPNet) error {
 	// No need to check the error here, since the link is found in previous steps.
 	link, _ := netlink.LinkByIndex(idx)
 	gwAddr := &netlink.Addr{IPNet: gwIPNet, Label: ""}
+	family, familyName := netlink.FAMILY_V4, "IPv4"
+	if gwIPNet.IP.To4() == nil {
+		family, familyName = netlink.FAMILY_V6, "IPv6"
+	}
 
-	if addrs, err := netlink.AddrList(link, netlink.FAMILY_V4); err != nil {
-		klog.Errorf("Failed to query IPv4 address list for interface %s: %v", link.Attrs().Name, err)
+	if addrs, err := netlink.AddrList(link, family); err != nil {
+		klog.Errorf("Failed to query %s address list for interface %s: %v", familyName, link.Attrs().Name, err)
 		return err
 	} else if addrs != nil {
 		for _, addr := range addrs {
-			klog.V(4).Infof("Found IPv4 address %s for interface %s", addr.IP.String(), link.Attrs().Name)
+			klog.V(4).Infof("Found %s address %s for interface %s", familyName, addr.IP.String(), link.Attrs().Name)
 			if addr.IP.Equal(gwAddr.IPNet.IP) {
-				klog.V(2).Infof("IPv4 address %s already assigned to interface %s", addr.IP.String(), link.Attrs().Name)
+				klog.V(2).Infof("%s address %s already assigned to interface %s", familyName, addr.IP.String(), link.Attrs().Name)
 				return nil
 			}
 		}
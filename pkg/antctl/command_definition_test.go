@@ -241,7 +241,7 @@ GroupName <NONE>
 					PodName:       "nginx-6db489d4b7-vgv7v",
 					PodNamespace:  "default",
 					InterfaceName: "Interface",
-					IP:            "127.0.0.1",
+					IPs:           []string{"127.0.0.1"},
 					MAC:           "07-16-76-00-02-86",
 					PortUUID:      "portuuid0",
 					OFPort:        80,
@@ -251,14 +251,14 @@ GroupName <NONE>
 					PodName:       "nginx-32b489d4b7-vgv7v",
 					PodNamespace:  "default",
 					InterfaceName: "Interface2",
-					IP:            "127.0.0.2",
+					IPs:           []string{"127.0.0.2"},
 					MAC:           "07-16-76-00-02-87",
 					PortUUID:      "portuuid1",
 					OFPort:        35572,
 					ContainerID:   "uci2ucsd6dx87dasuk232312csse",
 				},
 			},
-			expected: `NAMESPACE NAME                   INTERFACE-NAME IP        MAC               PORT-UUID OF-PORT CONTAINER-ID
+			expected: `NAMESPACE NAME                   INTERFACE-NAME IPS       MAC               PORT-UUID OF-PORT CONTAINER-ID
 default   nginx-32b489d4b7-vgv7v Interface2     127.0.0.2 07-16-76-00-02-87 portuuid1 35572   uci2ucsd6dx 
 default   nginx-6db489d4b7-vgv7v Interface      127.0.0.1 07-16-76-00-02-86 portuuid0 80      dve7a2d6c22 
 `,
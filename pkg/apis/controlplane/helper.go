@@ -18,20 +18,34 @@ import "fmt"
 
 // Conversion functions between GroupMember and GroupMemberPod
 func (g *GroupMember) ToGroupMemberPod() *GroupMemberPod {
-	return &GroupMemberPod{
+	memberPod := &GroupMemberPod{
 		Pod:   g.Pod,
 		IP:    g.Endpoints[0].IP,
 		Ports: g.Endpoints[0].Ports,
 	}
+	// A dual-stack Pod has one Endpoint for each IP.
+	if len(g.Endpoints) > 1 {
+		for _, ep := range g.Endpoints {
+			memberPod.IPs = append(memberPod.IPs, ep.IP)
+		}
+	}
+	return memberPod
 }
 
 func (p *GroupMemberPod) ToGroupMember() *GroupMember {
-	return &GroupMember{
-		Pod: p.Pod,
-		Endpoints: []Endpoint{
-			{IP: p.IP, Ports: p.Ports},
-		},
+	if len(p.IPs) == 0 {
+		return &GroupMember{
+			Pod: p.Pod,
+			Endpoints: []Endpoint{
+				{IP: p.IP, Ports: p.Ports},
+			},
+		}
+	}
+	member := &GroupMember{Pod: p.Pod}
+	for _, ip := range p.IPs {
+		member.Endpoints = append(member.Endpoints, Endpoint{IP: ip, Ports: p.Ports})
 	}
+	return member
 }
 
 func (r *NetworkPolicyReference) ToString() string {
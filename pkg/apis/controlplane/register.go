@@ -54,6 +54,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NetworkPolicy{},
 		&NetworkPolicyList{},
 		&NodeStatsSummary{},
+		&ClusterGroupMembers{},
+		&NetworkPolicyStatus{},
 	)
 	return nil
 }
@@ -60,6 +60,9 @@ type GroupMemberPod struct {
 	IP IPAddress
 	// Ports maintain the list of named port associated with this Pod member.
 	Ports []NamedPort
+	// IPs maintains all the IPAddresses of the Pod. A dual-stack Pod has both
+	// an IPv4 and an IPv6 address, the first of which is the same as IP.
+	IPs []IPAddress
 }
 
 // ExternalEntityReference represents a ExternalEntity Reference.
@@ -213,6 +216,11 @@ type NetworkPolicyRule struct {
 	// action “nil” defaults to Allow action, which would be the case for rules created for
 	// K8s NetworkPolicy.
 	Action *secv1alpha1.RuleAction
+	// EnableLogging indicates whether or not to generate logs when rules are matched. Default to false.
+	EnableLogging bool
+	// AppliedToGroups is a list of names of AppliedToGroups to which this rule applies.
+	// If it's empty, the rule applies to the AppliedToGroups of the NetworkPolicy.
+	AppliedToGroups []string
 }
 
 // Protocol defines network protocols supported for things like container ports.
@@ -225,17 +233,31 @@ const (
 	ProtocolUDP Protocol = "UDP"
 	// ProtocolSCTP is the SCTP protocol.
 	ProtocolSCTP Protocol = "SCTP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
+	// ProtocolICMPv6 is the ICMPv6 protocol.
+	ProtocolICMPv6 Protocol = "ICMPv6"
 )
 
 // Service describes a port to allow traffic on.
 type Service struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this
-	// field defaults to TCP.
+	// The protocol (TCP, UDP, SCTP, ICMP or ICMPv6) which traffic must match. If not
+	// specified, this field defaults to TCP.
 	// +optional
 	Protocol *Protocol
 	// The port name or number on the given protocol. If not specified, this matches all port numbers.
 	// +optional
 	Port *intstr.IntOrString
+	// EndPort is the end of the port range, inclusive. It can only be set along with a
+	// numerical Port.
+	// +optional
+	EndPort *int32
+	// ICMPType is the type of the ICMP or ICMPv6 messages which traffic must match.
+	// +optional
+	ICMPType *int32
+	// ICMPCode is the code of the ICMP or ICMPv6 messages which traffic must match.
+	// +optional
+	ICMPCode *int32
 }
 
 // NetworkPolicyPeer describes a peer of NetworkPolicyRules.
@@ -245,6 +267,9 @@ type NetworkPolicyPeer struct {
 	AddressGroups []string
 	// A list of IPBlock.
 	IPBlocks []IPBlock
+	// A list of FQDN selectors, which match the IP addresses the FQDNs
+	// resolve to.
+	FQDNs []string
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24"). The except entry describes CIDRs that should
@@ -266,6 +291,17 @@ type NetworkPolicyList struct {
 	Items []NetworkPolicy
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// ClusterGroupMembers is the resolved membership of a ClusterGroup.
+type ClusterGroupMembers struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+	// EffectiveMembers is a list of Pods and ExternalEntities currently selected by the ClusterGroup.
+	EffectiveMembers []GroupMember
+	// EffectiveIPBlocks is a list of IPNets which are members of the ClusterGroup.
+	EffectiveIPBlocks []IPNet
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // NodeStatsSummary contains stats produced on a Node. It's used by the antrea-agents to report stats to the antrea-controller.
 type NodeStatsSummary struct {
@@ -287,3 +323,28 @@ type NetworkPolicyStats struct {
 	// The stats of the NetworkPolicy.
 	TrafficStats statsv1alpha1.TrafficStats
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// NetworkPolicyStatus is the status of a NetworkPolicy. It's used by the antrea-agents to report the realization
+// status of NetworkPolicies to the antrea-controller.
+type NetworkPolicyStatus struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// The reference of the NetworkPolicy.
+	NetworkPolicy NetworkPolicyReference
+	// Nodes contains statuses produced on a list of Nodes.
+	Nodes []NetworkPolicyNodeStatus
+}
+
+// NetworkPolicyNodeStatus is the status of a NetworkPolicy on a Node.
+type NetworkPolicyNodeStatus struct {
+	// The name of the Node that produces the status.
+	NodeName string
+	// The generation realized by the Node.
+	Generation int64
+	// RealizationFailure is true if the Node failed to realize the NetworkPolicy.
+	RealizationFailure bool
+	// Message is a human readable message indicating details about the realization failure.
+	Message string
+}
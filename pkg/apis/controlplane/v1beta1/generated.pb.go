@@ -213,10 +213,38 @@ func (m *AppliedToGroupPatch) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AppliedToGroupPatch proto.InternalMessageInfo
 
+func (m *ClusterGroupMembers) Reset()      { *m = ClusterGroupMembers{} }
+func (*ClusterGroupMembers) ProtoMessage() {}
+func (*ClusterGroupMembers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{6}
+}
+func (m *ClusterGroupMembers) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClusterGroupMembers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ClusterGroupMembers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterGroupMembers.Merge(m, src)
+}
+func (m *ClusterGroupMembers) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClusterGroupMembers) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterGroupMembers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterGroupMembers proto.InternalMessageInfo
+
 func (m *Endpoint) Reset()      { *m = Endpoint{} }
 func (*Endpoint) ProtoMessage() {}
 func (*Endpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{6}
+	return fileDescriptor_345cd0a9074e5729, []int{7}
 }
 func (m *Endpoint) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -244,7 +272,7 @@ var xxx_messageInfo_Endpoint proto.InternalMessageInfo
 func (m *ExternalEntityReference) Reset()      { *m = ExternalEntityReference{} }
 func (*ExternalEntityReference) ProtoMessage() {}
 func (*ExternalEntityReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{7}
+	return fileDescriptor_345cd0a9074e5729, []int{8}
 }
 func (m *ExternalEntityReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -272,7 +300,7 @@ var xxx_messageInfo_ExternalEntityReference proto.InternalMessageInfo
 func (m *GroupMember) Reset()      { *m = GroupMember{} }
 func (*GroupMember) ProtoMessage() {}
 func (*GroupMember) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{8}
+	return fileDescriptor_345cd0a9074e5729, []int{9}
 }
 func (m *GroupMember) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -300,7 +328,7 @@ var xxx_messageInfo_GroupMember proto.InternalMessageInfo
 func (m *GroupMemberPod) Reset()      { *m = GroupMemberPod{} }
 func (*GroupMemberPod) ProtoMessage() {}
 func (*GroupMemberPod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{9}
+	return fileDescriptor_345cd0a9074e5729, []int{10}
 }
 func (m *GroupMemberPod) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -328,7 +356,7 @@ var xxx_messageInfo_GroupMemberPod proto.InternalMessageInfo
 func (m *IPBlock) Reset()      { *m = IPBlock{} }
 func (*IPBlock) ProtoMessage() {}
 func (*IPBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{10}
+	return fileDescriptor_345cd0a9074e5729, []int{11}
 }
 func (m *IPBlock) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -356,7 +384,7 @@ var xxx_messageInfo_IPBlock proto.InternalMessageInfo
 func (m *IPNet) Reset()      { *m = IPNet{} }
 func (*IPNet) ProtoMessage() {}
 func (*IPNet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{11}
+	return fileDescriptor_345cd0a9074e5729, []int{12}
 }
 func (m *IPNet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -384,7 +412,7 @@ var xxx_messageInfo_IPNet proto.InternalMessageInfo
 func (m *NamedPort) Reset()      { *m = NamedPort{} }
 func (*NamedPort) ProtoMessage() {}
 func (*NamedPort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{12}
+	return fileDescriptor_345cd0a9074e5729, []int{13}
 }
 func (m *NamedPort) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -412,7 +440,7 @@ var xxx_messageInfo_NamedPort proto.InternalMessageInfo
 func (m *NetworkPolicy) Reset()      { *m = NetworkPolicy{} }
 func (*NetworkPolicy) ProtoMessage() {}
 func (*NetworkPolicy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{13}
+	return fileDescriptor_345cd0a9074e5729, []int{14}
 }
 func (m *NetworkPolicy) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -440,7 +468,7 @@ var xxx_messageInfo_NetworkPolicy proto.InternalMessageInfo
 func (m *NetworkPolicyList) Reset()      { *m = NetworkPolicyList{} }
 func (*NetworkPolicyList) ProtoMessage() {}
 func (*NetworkPolicyList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{14}
+	return fileDescriptor_345cd0a9074e5729, []int{15}
 }
 func (m *NetworkPolicyList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -465,10 +493,38 @@ func (m *NetworkPolicyList) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyList proto.InternalMessageInfo
 
+func (m *NetworkPolicyNodeStatus) Reset()      { *m = NetworkPolicyNodeStatus{} }
+func (*NetworkPolicyNodeStatus) ProtoMessage() {}
+func (*NetworkPolicyNodeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{16}
+}
+func (m *NetworkPolicyNodeStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkPolicyNodeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NetworkPolicyNodeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkPolicyNodeStatus.Merge(m, src)
+}
+func (m *NetworkPolicyNodeStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkPolicyNodeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkPolicyNodeStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkPolicyNodeStatus proto.InternalMessageInfo
+
 func (m *NetworkPolicyPeer) Reset()      { *m = NetworkPolicyPeer{} }
 func (*NetworkPolicyPeer) ProtoMessage() {}
 func (*NetworkPolicyPeer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{15}
+	return fileDescriptor_345cd0a9074e5729, []int{17}
 }
 func (m *NetworkPolicyPeer) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -496,7 +552,7 @@ var xxx_messageInfo_NetworkPolicyPeer proto.InternalMessageInfo
 func (m *NetworkPolicyReference) Reset()      { *m = NetworkPolicyReference{} }
 func (*NetworkPolicyReference) ProtoMessage() {}
 func (*NetworkPolicyReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{16}
+	return fileDescriptor_345cd0a9074e5729, []int{18}
 }
 func (m *NetworkPolicyReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -524,7 +580,7 @@ var xxx_messageInfo_NetworkPolicyReference proto.InternalMessageInfo
 func (m *NetworkPolicyRule) Reset()      { *m = NetworkPolicyRule{} }
 func (*NetworkPolicyRule) ProtoMessage() {}
 func (*NetworkPolicyRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{17}
+	return fileDescriptor_345cd0a9074e5729, []int{19}
 }
 func (m *NetworkPolicyRule) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -552,7 +608,7 @@ var xxx_messageInfo_NetworkPolicyRule proto.InternalMessageInfo
 func (m *NetworkPolicyStats) Reset()      { *m = NetworkPolicyStats{} }
 func (*NetworkPolicyStats) ProtoMessage() {}
 func (*NetworkPolicyStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{18}
+	return fileDescriptor_345cd0a9074e5729, []int{20}
 }
 func (m *NetworkPolicyStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -577,10 +633,38 @@ func (m *NetworkPolicyStats) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyStats proto.InternalMessageInfo
 
+func (m *NetworkPolicyStatus) Reset()      { *m = NetworkPolicyStatus{} }
+func (*NetworkPolicyStatus) ProtoMessage() {}
+func (*NetworkPolicyStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{21}
+}
+func (m *NetworkPolicyStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkPolicyStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NetworkPolicyStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkPolicyStatus.Merge(m, src)
+}
+func (m *NetworkPolicyStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkPolicyStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkPolicyStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkPolicyStatus proto.InternalMessageInfo
+
 func (m *NodeStatsSummary) Reset()      { *m = NodeStatsSummary{} }
 func (*NodeStatsSummary) ProtoMessage() {}
 func (*NodeStatsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{19}
+	return fileDescriptor_345cd0a9074e5729, []int{22}
 }
 func (m *NodeStatsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -608,7 +692,7 @@ var xxx_messageInfo_NodeStatsSummary proto.InternalMessageInfo
 func (m *PodReference) Reset()      { *m = PodReference{} }
 func (*PodReference) ProtoMessage() {}
 func (*PodReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{20}
+	return fileDescriptor_345cd0a9074e5729, []int{23}
 }
 func (m *PodReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -636,7 +720,7 @@ var xxx_messageInfo_PodReference proto.InternalMessageInfo
 func (m *Service) Reset()      { *m = Service{} }
 func (*Service) ProtoMessage() {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{21}
+	return fileDescriptor_345cd0a9074e5729, []int{24}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -668,6 +752,7 @@ func init() {
 	proto.RegisterType((*AppliedToGroup)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.AppliedToGroup")
 	proto.RegisterType((*AppliedToGroupList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.AppliedToGroupList")
 	proto.RegisterType((*AppliedToGroupPatch)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.AppliedToGroupPatch")
+	proto.RegisterType((*ClusterGroupMembers)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.ClusterGroupMembers")
 	proto.RegisterType((*Endpoint)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.Endpoint")
 	proto.RegisterType((*ExternalEntityReference)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.ExternalEntityReference")
 	proto.RegisterType((*GroupMember)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.GroupMember")
@@ -677,10 +762,12 @@ func init() {
 	proto.RegisterType((*NamedPort)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NamedPort")
 	proto.RegisterType((*NetworkPolicy)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicy")
 	proto.RegisterType((*NetworkPolicyList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyList")
+	proto.RegisterType((*NetworkPolicyNodeStatus)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyNodeStatus")
 	proto.RegisterType((*NetworkPolicyPeer)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyPeer")
 	proto.RegisterType((*NetworkPolicyReference)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyReference")
 	proto.RegisterType((*NetworkPolicyRule)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyRule")
 	proto.RegisterType((*NetworkPolicyStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyStats")
+	proto.RegisterType((*NetworkPolicyStatus)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyStatus")
 	proto.RegisterType((*NodeStatsSummary)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NodeStatsSummary")
 	proto.RegisterType((*PodReference)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.PodReference")
 	proto.RegisterType((*Service)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.Service")
@@ -691,109 +778,129 @@ func init() {
 }
 
 var fileDescriptor_345cd0a9074e5729 = []byte{
-	// 1625 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xbf, 0x73, 0xdb, 0x46,
-	0x16, 0x16, 0x40, 0x52, 0x12, 0x57, 0xd4, 0xaf, 0xd5, 0xf9, 0xcc, 0xf3, 0xf9, 0x48, 0x19, 0x77,
-	0x85, 0x8a, 0x33, 0x68, 0xf9, 0x7c, 0x89, 0x0b, 0xa7, 0x10, 0x2d, 0xd9, 0xc3, 0x44, 0x96, 0x39,
-	0x2b, 0xb9, 0xc9, 0x64, 0x26, 0x81, 0x80, 0x25, 0x05, 0x0b, 0xc0, 0xc2, 0x8b, 0xa5, 0x6c, 0x65,
-	0x26, 0x99, 0x78, 0x52, 0xc5, 0x4d, 0x7e, 0x35, 0x69, 0x52, 0x66, 0x26, 0x93, 0xc9, 0x5f, 0x90,
-	0x2a, 0xe9, 0x5c, 0xba, 0x74, 0x13, 0x4e, 0x44, 0x4f, 0x3c, 0xe9, 0xd2, 0xab, 0xca, 0xec, 0x62,
-	0x41, 0x00, 0xa4, 0x68, 0x2b, 0x43, 0x4a, 0x93, 0xc2, 0x95, 0xb4, 0xbb, 0x6f, 0xdf, 0xf7, 0xed,
-	0x7b, 0x6f, 0x3f, 0x3c, 0x80, 0x60, 0xbd, 0x69, 0xb3, 0x9d, 0xd6, 0xb6, 0x6e, 0x12, 0xb7, 0xb2,
-	0xe7, 0xde, 0x37, 0x28, 0xbe, 0xc8, 0x0c, 0xef, 0xfd, 0x56, 0xc5, 0xf0, 0x18, 0xc5, 0x46, 0xc5,
-	0xdf, 0x6d, 0x56, 0x0c, 0xdf, 0x0e, 0x2a, 0x26, 0xf1, 0x18, 0x25, 0x8e, 0xef, 0x18, 0x1e, 0xae,
-	0xec, 0x2d, 0x6f, 0x63, 0x66, 0x2c, 0x57, 0x9a, 0xd8, 0xc3, 0xd4, 0x60, 0xd8, 0xd2, 0x7d, 0x4a,
-	0x18, 0x81, 0xd7, 0x62, 0x6f, 0x7a, 0xe8, 0xed, 0x5d, 0xe1, 0x4d, 0x0f, 0xbd, 0xe9, 0xfe, 0x6e,
-	0x53, 0xe7, 0xde, 0xf4, 0xa4, 0x37, 0x5d, 0x7a, 0x3b, 0x77, 0x31, 0xc1, 0xa5, 0x49, 0x9a, 0xa4,
-	0x22, 0x9c, 0x6e, 0xb7, 0x1a, 0x62, 0x24, 0x06, 0xe2, 0xbf, 0x10, 0xec, 0xdc, 0x8d, 0xe3, 0x52,
-	0x0f, 0x98, 0xc1, 0x82, 0xca, 0xde, 0xb2, 0xe1, 0xf8, 0x3b, 0xfd, 0xa4, 0xcf, 0x5d, 0xd9, 0xbd,
-	0x1a, 0xe8, 0x36, 0xe1, 0xb6, 0xae, 0x61, 0xee, 0xd8, 0x1e, 0xa6, 0xfb, 0xf1, 0x66, 0x17, 0x33,
-	0xa3, 0xb2, 0xd7, 0xbf, 0xab, 0x32, 0x68, 0x17, 0x6d, 0x79, 0xcc, 0x76, 0x71, 0xdf, 0x86, 0xd7,
-	0x5e, 0xb6, 0x21, 0x30, 0x77, 0xb0, 0x6b, 0xf4, 0xed, 0xfb, 0xdf, 0xa0, 0x7d, 0x2d, 0x66, 0x3b,
-	0x15, 0xdb, 0x63, 0x01, 0xa3, 0xbd, 0x9b, 0xb4, 0xe7, 0x2a, 0x28, 0xac, 0x58, 0x16, 0xc5, 0x41,
-	0x70, 0x93, 0x92, 0x96, 0x0f, 0xdf, 0x03, 0x93, 0xfc, 0x24, 0x96, 0xc1, 0x8c, 0xa2, 0xb2, 0xa8,
-	0x2c, 0x4d, 0x5d, 0xbe, 0xa4, 0x87, 0x8e, 0xf5, 0xa4, 0xe3, 0x38, 0x43, 0xdc, 0x5a, 0xdf, 0x5b,
-	0xd6, 0x6f, 0x6f, 0xdf, 0xc5, 0x26, 0xbb, 0x85, 0x99, 0x51, 0x85, 0x8f, 0xdb, 0xe5, 0xb1, 0x4e,
-	0xbb, 0x0c, 0xe2, 0x39, 0xd4, 0xf5, 0x0a, 0x3d, 0x90, 0xf5, 0x89, 0x15, 0x14, 0xd5, 0xc5, 0xcc,
-	0xd2, 0xd4, 0xe5, 0x75, 0x7d, 0x98, 0x52, 0xd0, 0x05, 0xe9, 0x5b, 0xd8, 0xdd, 0xc6, 0xb4, 0x4e,
-	0xac, 0x6a, 0x41, 0x22, 0x67, 0xeb, 0xc4, 0x0a, 0x90, 0xc0, 0x81, 0x1f, 0x2b, 0xa0, 0xd0, 0x8c,
-	0xcd, 0x82, 0x62, 0x46, 0x00, 0xd7, 0x46, 0x06, 0x5c, 0xfd, 0x9b, 0x44, 0x2d, 0x24, 0x26, 0x03,
-	0x94, 0x02, 0xd5, 0x0e, 0x14, 0x30, 0x97, 0x0c, 0xf4, 0xba, 0x1d, 0x30, 0xf8, 0x4e, 0x5f, 0xb0,
-	0xf5, 0xe3, 0x05, 0x9b, 0xef, 0x16, 0xa1, 0x9e, 0x93, 0xd0, 0x93, 0xd1, 0x4c, 0x22, 0xd0, 0x04,
-	0xe4, 0x6c, 0x86, 0xdd, 0x28, 0xd2, 0x6f, 0x0e, 0x77, 0xe0, 0x24, 0xf9, 0xea, 0xb4, 0x84, 0xcd,
-	0xd5, 0x38, 0x00, 0x0a, 0x71, 0xb4, 0xef, 0x72, 0x60, 0x3e, 0x69, 0x56, 0x37, 0x98, 0xb9, 0x73,
-	0x0a, 0x15, 0xf5, 0x01, 0xc8, 0x1b, 0x96, 0x85, 0xad, 0xfa, 0x49, 0x95, 0xd5, 0xbc, 0x84, 0xcf,
-	0xaf, 0x44, 0x30, 0x28, 0x46, 0xe4, 0x05, 0x36, 0x45, 0xb1, 0x4b, 0xf6, 0x24, 0x83, 0xcc, 0x09,
-	0x30, 0x58, 0x90, 0x0c, 0xa6, 0x50, 0x0c, 0x84, 0x92, 0xa8, 0xf0, 0x0b, 0x05, 0xcc, 0x0b, 0x4e,
-	0xc9, 0x22, 0x2c, 0x66, 0x47, 0x5d, 0xeb, 0xff, 0x90, 0x44, 0xe6, 0x57, 0x7a, 0xb1, 0x50, 0x3f,
-	0x3c, 0xfc, 0x4a, 0x01, 0x0b, 0x92, 0x64, 0x8a, 0x56, 0x6e, 0xd4, 0xb4, 0xfe, 0x29, 0x69, 0x2d,
-	0xa0, 0x7e, 0x34, 0x74, 0x14, 0x05, 0xed, 0x37, 0x15, 0xcc, 0xac, 0xf8, 0xbe, 0x63, 0x63, 0x6b,
-	0x8b, 0xbc, 0xd2, 0xbe, 0x93, 0xd4, 0xbe, 0x5f, 0x15, 0x00, 0xd3, 0xa1, 0x3e, 0x05, 0xf5, 0xbb,
-	0x97, 0x56, 0xbf, 0x21, 0x63, 0x9d, 0xa6, 0x3f, 0x40, 0xff, 0xbe, 0xcf, 0x81, 0x85, 0xb4, 0xe1,
-	0x2b, 0x05, 0x7c, 0xa5, 0x80, 0x7f, 0x59, 0x05, 0xfc, 0x5a, 0x01, 0x93, 0x6b, 0x9e, 0xe5, 0x13,
-	0xdb, 0x63, 0xf0, 0xdf, 0x40, 0xb5, 0x7d, 0x51, 0x9d, 0x85, 0xea, 0x42, 0xa7, 0x5d, 0x56, 0x6b,
-	0xf5, 0xc3, 0x76, 0x39, 0x5f, 0xab, 0xcb, 0x07, 0x3a, 0x52, 0x6d, 0x1f, 0x3a, 0x20, 0xe7, 0x13,
-	0xca, 0xa2, 0x12, 0xbb, 0x39, 0x1c, 0xfb, 0x0d, 0xc3, 0xe5, 0x99, 0xa3, 0x2c, 0xbe, 0x4e, 0x7c,
-	0x14, 0xa0, 0x10, 0x44, 0x73, 0xc0, 0xd9, 0xb5, 0x07, 0x0c, 0x53, 0xcf, 0x70, 0xd6, 0x3c, 0x66,
-	0xb3, 0x7d, 0x84, 0x1b, 0x98, 0x62, 0xcf, 0xc4, 0x70, 0x11, 0x64, 0x3d, 0xc3, 0xc5, 0x82, 0x6f,
-	0x3e, 0x56, 0x3e, 0xee, 0x11, 0x89, 0x15, 0x58, 0x01, 0x79, 0xfe, 0x37, 0xf0, 0x0d, 0x13, 0x17,
-	0x55, 0x61, 0xd6, 0xad, 0xe1, 0x8d, 0x68, 0x01, 0xc5, 0x36, 0xda, 0xc3, 0x0c, 0x98, 0x4a, 0x84,
-	0x07, 0x62, 0x90, 0xf1, 0x89, 0x25, 0xef, 0xeb, 0x90, 0xbd, 0x53, 0x9d, 0x58, 0x5d, 0xee, 0xd5,
-	0x89, 0x4e, 0xbb, 0x9c, 0xe1, 0x33, 0xdc, 0x3f, 0xfc, 0x5c, 0x01, 0x33, 0x38, 0x75, 0x4a, 0xc1,
-	0x76, 0xea, 0xf2, 0x9d, 0xe1, 0x20, 0x07, 0x44, 0xae, 0x0a, 0x3b, 0xed, 0xf2, 0x4c, 0xcf, 0x62,
-	0x0f, 0x01, 0x78, 0x1f, 0xe4, 0xb1, 0xac, 0x8b, 0xe8, 0x2e, 0xdf, 0x18, 0x92, 0x8d, 0x74, 0x17,
-	0xe7, 0x20, 0x9a, 0x09, 0x50, 0x8c, 0xa5, 0x3d, 0x52, 0xc1, 0x4c, 0xfa, 0xda, 0x9f, 0x56, 0x1a,
-	0xc2, 0xf2, 0x57, 0x8f, 0x59, 0xfe, 0x99, 0xd3, 0x28, 0xff, 0x9f, 0x15, 0x30, 0x51, 0xab, 0x57,
-	0x1d, 0x62, 0xee, 0x42, 0x0c, 0xb2, 0xa6, 0x6d, 0x51, 0x19, 0x86, 0xeb, 0xc3, 0x01, 0xd7, 0xea,
-	0x1b, 0x98, 0xc5, 0x97, 0xe6, 0x7a, 0x6d, 0x15, 0x21, 0xe1, 0x1e, 0xee, 0x82, 0x71, 0xfc, 0xc0,
-	0xc4, 0x3e, 0x93, 0x17, 0x7c, 0x24, 0x40, 0x33, 0x12, 0x68, 0x7c, 0x4d, 0xb8, 0x46, 0x12, 0x42,
-	0x6b, 0x80, 0x9c, 0x30, 0x38, 0x9e, 0xf4, 0x5c, 0x05, 0x05, 0x9f, 0xe2, 0x86, 0xfd, 0x60, 0x1d,
-	0x7b, 0x4d, 0xb6, 0x23, 0x52, 0x95, 0x8b, 0xbb, 0x8f, 0x7a, 0x62, 0x0d, 0xa5, 0x2c, 0xb5, 0x4f,
-	0x14, 0x90, 0xef, 0xc6, 0x9a, 0x2b, 0x07, 0x0f, 0xaf, 0x80, 0xcb, 0x25, 0x7b, 0x26, 0xca, 0x90,
-	0x58, 0xe9, 0x6a, 0x8b, 0x3a, 0x50, 0x5b, 0xae, 0x82, 0x49, 0xf1, 0xf6, 0x6c, 0x12, 0xa7, 0x98,
-	0x11, 0x56, 0xe7, 0xa3, 0x46, 0xa4, 0x2e, 0xe7, 0x0f, 0x13, 0xff, 0xa3, 0xae, 0xb5, 0xf6, 0x28,
-	0x0b, 0xa6, 0x37, 0x30, 0xbb, 0x4f, 0xe8, 0x6e, 0x9d, 0x38, 0xb6, 0xb9, 0x7f, 0x0a, 0xbd, 0x01,
-	0x03, 0x39, 0xda, 0x72, 0x70, 0x24, 0xda, 0xb7, 0x87, 0xac, 0xda, 0x24, 0x7b, 0xd4, 0x72, 0x70,
-	0x5c, 0xbd, 0x7c, 0x14, 0xa0, 0x10, 0x0c, 0xbe, 0x01, 0x66, 0x8d, 0x54, 0x2b, 0x14, 0xde, 0x9a,
-	0xbc, 0xc8, 0xf0, 0x6c, 0xba, 0x4b, 0x0a, 0x50, 0xaf, 0x2d, 0x5c, 0xe2, 0x21, 0xb6, 0x09, 0xe5,
-	0x7a, 0x98, 0x5d, 0x54, 0x96, 0x94, 0x6a, 0x21, 0x0c, 0x6f, 0x38, 0x87, 0xba, 0xab, 0xf0, 0x0a,
-	0x28, 0x30, 0x1b, 0xd3, 0x68, 0xa5, 0x98, 0x13, 0x89, 0x9d, 0xe3, 0x45, 0xb1, 0x95, 0x98, 0x47,
-	0x29, 0x2b, 0xf8, 0x50, 0x01, 0xf9, 0x80, 0xb4, 0xa8, 0x89, 0x11, 0x6e, 0x14, 0xc7, 0x45, 0xe0,
-	0xb7, 0x46, 0x19, 0x99, 0xae, 0xce, 0x4c, 0x73, 0xb5, 0xdb, 0x8c, 0xa0, 0x50, 0x8c, 0xaa, 0x3d,
-	0x53, 0xc0, 0x7c, 0x6a, 0xd3, 0x29, 0x74, 0xc5, 0x7e, 0xba, 0x2b, 0x7e, 0x6b, 0x84, 0x47, 0x1e,
-	0xd0, 0x14, 0xff, 0xd4, 0x7b, 0xca, 0x3a, 0xc6, 0x14, 0xbe, 0x0e, 0xa6, 0x8d, 0xc4, 0x97, 0x82,
-	0xa0, 0xa8, 0x88, 0xe2, 0x98, 0xef, 0xb4, 0xcb, 0xd3, 0xc9, 0x4f, 0x08, 0x01, 0x4a, 0xdb, 0xc1,
-	0x00, 0x4c, 0xda, 0xbe, 0x10, 0xc5, 0xe8, 0x0c, 0x6b, 0xc3, 0x8a, 0x94, 0xf0, 0x16, 0x47, 0x4d,
-	0x4e, 0x04, 0xa8, 0x0b, 0xa4, 0x3d, 0x57, 0xc0, 0xdf, 0x8f, 0x4e, 0x2f, 0xfc, 0x3f, 0xc8, 0xb2,
-	0x7d, 0x3f, 0xea, 0x44, 0x2e, 0x44, 0x6a, 0xb1, 0xb5, 0xef, 0xe3, 0xc3, 0x76, 0x39, 0x7d, 0x72,
-	0x3e, 0x89, 0x84, 0xf9, 0x9f, 0x6e, 0x4f, 0xba, 0xaa, 0x94, 0x19, 0xa8, 0x4a, 0x55, 0x90, 0x69,
-	0xd9, 0x96, 0xb8, 0x2d, 0xf9, 0xea, 0x25, 0x69, 0x90, 0xb9, 0x53, 0x5b, 0x3d, 0x6c, 0x97, 0x2f,
-	0x0c, 0xfa, 0x36, 0xc8, 0xc9, 0x04, 0xfa, 0x9d, 0xda, 0x2a, 0xe2, 0x9b, 0xb5, 0x1f, 0xb3, 0x3d,
-	0xc9, 0xe2, 0x77, 0x1a, 0x5e, 0x03, 0x79, 0xcb, 0xa6, 0xd8, 0x64, 0x36, 0xf1, 0xe4, 0x41, 0x4b,
-	0x11, 0xd9, 0xd5, 0x68, 0xe1, 0x30, 0x39, 0x40, 0xf1, 0x06, 0x78, 0x0f, 0x64, 0x1b, 0x94, 0xb8,
-	0xb2, 0xad, 0x19, 0xa5, 0xfc, 0xf0, 0x4a, 0x8a, 0x43, 0x71, 0x83, 0x12, 0x17, 0x09, 0x28, 0xb8,
-	0x0b, 0x54, 0x46, 0x44, 0xa8, 0x4e, 0x00, 0x10, 0x48, 0x40, 0x75, 0x8b, 0x20, 0x95, 0x11, 0x5e,
-	0x91, 0x01, 0xa6, 0x7b, 0xb6, 0x89, 0xa3, 0x97, 0x8d, 0x21, 0x2b, 0x72, 0x33, 0xf4, 0x16, 0x57,
-	0xa4, 0x9c, 0x08, 0x50, 0x17, 0x08, 0xfe, 0x37, 0xa1, 0x8f, 0x52, 0xf1, 0xe2, 0x47, 0x50, 0x9f,
-	0x46, 0xde, 0x05, 0xe3, 0x46, 0x98, 0xbd, 0x71, 0x91, 0x3d, 0xc4, 0x1f, 0xc7, 0x2b, 0x51, 0xda,
-	0x56, 0x8f, 0xfd, 0x7d, 0x1c, 0x9b, 0x2d, 0xee, 0xaf, 0xfb, 0x89, 0x5c, 0xe7, 0xe5, 0x11, 0xfa,
-	0x41, 0x12, 0x41, 0xfb, 0x56, 0x05, 0x30, 0x15, 0xb4, 0x4d, 0x66, 0xb0, 0x80, 0xf7, 0xb9, 0xd3,
-	0x5e, 0x72, 0x5a, 0x8a, 0xdb, 0xc9, 0x88, 0xee, 0x19, 0x19, 0x8c, 0xf4, 0xc3, 0x16, 0xa5, 0x19,
-	0xc0, 0x0f, 0x41, 0x81, 0x51, 0xa3, 0xd1, 0xb0, 0x4d, 0xc1, 0x51, 0x56, 0xe8, 0xea, 0xb1, 0x19,
-	0x89, 0xdf, 0x0b, 0xf4, 0x6e, 0x30, 0xb6, 0x12, 0xbe, 0xe2, 0xce, 0x24, 0x39, 0x8b, 0x52, 0x78,
-	0xda, 0xef, 0x59, 0x30, 0xb7, 0x41, 0x2c, 0x2c, 0x46, 0x9b, 0x2d, 0xd7, 0x35, 0xe8, 0x69, 0x34,
-	0x04, 0x5f, 0x2a, 0x60, 0x36, 0x19, 0x08, 0xbb, 0xdb, 0x1b, 0xd4, 0x47, 0x98, 0x8c, 0x30, 0x0c,
-	0x67, 0x25, 0x93, 0xd9, 0x8d, 0x34, 0x20, 0xea, 0x65, 0x00, 0x7f, 0x50, 0xc0, 0xf9, 0x10, 0xe5,
-	0xba, 0xd3, 0x0a, 0x18, 0xa6, 0x3d, 0x3b, 0x64, 0xd3, 0x3d, 0x7a, 0x8a, 0xff, 0x91, 0x14, 0xcf,
-	0xaf, 0xbc, 0x00, 0x1d, 0xbd, 0x90, 0x1b, 0xfc, 0x46, 0x01, 0x67, 0x42, 0x83, 0x5e, 0xd6, 0xd9,
-	0x13, 0x62, 0xfd, 0x2f, 0xc9, 0xfa, 0xcc, 0xca, 0x51, 0xb0, 0xe8, 0x68, 0x36, 0x9a, 0x01, 0x0a,
-	0xc9, 0x97, 0xa0, 0x93, 0x78, 0x8f, 0xfe, 0x54, 0x01, 0x13, 0x52, 0xb0, 0xe0, 0x95, 0x44, 0xa3,
-	0x1c, 0x42, 0x14, 0x5f, 0xde, 0x24, 0xc3, 0x0d, 0xd9, 0xa2, 0xab, 0x2f, 0xa9, 0xfe, 0x16, 0xb3,
-	0x1d, 0x3d, 0xfc, 0x5d, 0x4b, 0xaf, 0x79, 0xec, 0x36, 0xdd, 0x64, 0xd4, 0xf6, 0x9a, 0xd5, 0xc9,
-	0x74, 0x43, 0x5f, 0xbd, 0xf8, 0xf8, 0xa0, 0x34, 0xf6, 0xe4, 0xa0, 0x34, 0xf6, 0xf4, 0xa0, 0x34,
-	0xf6, 0x51, 0xa7, 0xa4, 0x3c, 0xee, 0x94, 0x94, 0x27, 0x9d, 0x92, 0xf2, 0xb4, 0x53, 0x52, 0x7e,
-	0xe9, 0x94, 0x94, 0xcf, 0x9e, 0x95, 0xc6, 0xde, 0x9e, 0x90, 0xc1, 0xfe, 0x23, 0x00, 0x00, 0xff,
-	0xff, 0x93, 0xfc, 0x68, 0xa3, 0xea, 0x1c, 0x00, 0x00,
+	// 1942 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcd, 0x8f, 0x1b, 0x49,
+	0x15, 0x4f, 0xb7, 0xed, 0x99, 0xf1, 0x1b, 0xcf, 0x57, 0xcd, 0x86, 0x98, 0x10, 0xec, 0x6c, 0xf3,
+	0xa1, 0x20, 0x91, 0xf6, 0x26, 0x04, 0x88, 0xc4, 0x72, 0x18, 0xcf, 0x47, 0xe4, 0x65, 0xe2, 0x98,
+	0x9a, 0xc9, 0x05, 0x21, 0x41, 0x4f, 0x77, 0xd9, 0xd3, 0x3b, 0x76, 0x57, 0xa7, 0xba, 0x3c, 0xc9,
+	0x44, 0x02, 0xb1, 0xe2, 0x80, 0x58, 0x21, 0xf1, 0x75, 0xd9, 0x0b, 0x47, 0x24, 0x84, 0x38, 0x71,
+	0x44, 0xfc, 0x01, 0x39, 0xee, 0x71, 0x2f, 0x58, 0xc4, 0x2b, 0x56, 0xdc, 0x90, 0x38, 0xce, 0x09,
+	0x55, 0x75, 0xf5, 0x97, 0x3d, 0xb3, 0x19, 0x64, 0x7b, 0xe0, 0x90, 0x93, 0xdd, 0xaf, 0x5e, 0xbd,
+	0xdf, 0xaf, 0xdf, 0x7b, 0xf5, 0xea, 0x55, 0x35, 0xec, 0x76, 0x5c, 0x7e, 0xd8, 0x3f, 0x30, 0x6d,
+	0xda, 0xab, 0x1d, 0xf7, 0x9e, 0x5a, 0x8c, 0xdc, 0xe6, 0x96, 0xf7, 0xbc, 0x5f, 0xb3, 0x3c, 0xce,
+	0x88, 0x55, 0xf3, 0x8f, 0x3a, 0x35, 0xcb, 0x77, 0x83, 0x9a, 0x4d, 0x3d, 0xce, 0x68, 0xd7, 0xef,
+	0x5a, 0x1e, 0xa9, 0x1d, 0xdf, 0x39, 0x20, 0xdc, 0xba, 0x53, 0xeb, 0x10, 0x8f, 0x30, 0x8b, 0x13,
+	0xc7, 0xf4, 0x19, 0xe5, 0x14, 0xbd, 0x9d, 0x58, 0x33, 0x43, 0x6b, 0x3f, 0x90, 0xd6, 0xcc, 0xd0,
+	0x9a, 0xe9, 0x1f, 0x75, 0x4c, 0x61, 0xcd, 0x4c, 0x5b, 0x33, 0x95, 0xb5, 0xeb, 0xb7, 0x53, 0x5c,
+	0x3a, 0xb4, 0x43, 0x6b, 0xd2, 0xe8, 0x41, 0xbf, 0x2d, 0x9f, 0xe4, 0x83, 0xfc, 0x17, 0x82, 0x5d,
+	0xdf, 0xb9, 0x28, 0xf5, 0x80, 0x5b, 0x3c, 0xa8, 0x1d, 0xdf, 0xb1, 0xba, 0xfe, 0xe1, 0x38, 0xe9,
+	0xeb, 0xf7, 0x8e, 0xee, 0x07, 0xa6, 0x4b, 0x85, 0x6e, 0xcf, 0xb2, 0x0f, 0x5d, 0x8f, 0xb0, 0x93,
+	0x64, 0x72, 0x8f, 0x70, 0xab, 0x76, 0x3c, 0x3e, 0xab, 0x76, 0xde, 0x2c, 0xd6, 0xf7, 0xb8, 0xdb,
+	0x23, 0x63, 0x13, 0xbe, 0xf1, 0xaa, 0x09, 0x81, 0x7d, 0x48, 0x7a, 0xd6, 0xd8, 0xbc, 0xaf, 0x9d,
+	0x37, 0xaf, 0xcf, 0xdd, 0x6e, 0xcd, 0xf5, 0x78, 0xc0, 0xd9, 0xe8, 0x24, 0xe3, 0x13, 0x1d, 0x4a,
+	0x1b, 0x8e, 0xc3, 0x48, 0x10, 0x3c, 0x60, 0xb4, 0xef, 0xa3, 0x1f, 0xc2, 0x82, 0x78, 0x13, 0xc7,
+	0xe2, 0x56, 0x59, 0xbb, 0xa9, 0xdd, 0x5a, 0xbc, 0xfb, 0x96, 0x19, 0x1a, 0x36, 0xd3, 0x86, 0x93,
+	0x08, 0x09, 0x6d, 0xf3, 0xf8, 0x8e, 0xf9, 0xe8, 0xe0, 0x5d, 0x62, 0xf3, 0x87, 0x84, 0x5b, 0x75,
+	0xf4, 0x62, 0x50, 0xbd, 0x32, 0x1c, 0x54, 0x21, 0x91, 0xe1, 0xd8, 0x2a, 0xf2, 0x20, 0xef, 0x53,
+	0x27, 0x28, 0xeb, 0x37, 0x73, 0xb7, 0x16, 0xef, 0xee, 0x9a, 0x93, 0xa4, 0x82, 0x29, 0x49, 0x3f,
+	0x24, 0xbd, 0x03, 0xc2, 0x5a, 0xd4, 0xa9, 0x97, 0x14, 0x72, 0xbe, 0x45, 0x9d, 0x00, 0x4b, 0x1c,
+	0xf4, 0x53, 0x0d, 0x4a, 0x9d, 0x44, 0x2d, 0x28, 0xe7, 0x24, 0x70, 0x63, 0x6a, 0xc0, 0xf5, 0x37,
+	0x14, 0x6a, 0x29, 0x25, 0x0c, 0x70, 0x06, 0xd4, 0x78, 0xa9, 0xc1, 0x6a, 0xda, 0xd1, 0xbb, 0x6e,
+	0xc0, 0xd1, 0xf7, 0xc7, 0x9c, 0x6d, 0x5e, 0xcc, 0xd9, 0x62, 0xb6, 0x74, 0xf5, 0xaa, 0x82, 0x5e,
+	0x88, 0x24, 0x29, 0x47, 0x53, 0x28, 0xb8, 0x9c, 0xf4, 0x22, 0x4f, 0xbf, 0x33, 0xd9, 0x0b, 0xa7,
+	0xc9, 0xd7, 0x97, 0x14, 0x6c, 0xa1, 0x21, 0x00, 0x70, 0x88, 0x63, 0xfc, 0xb1, 0x00, 0x6b, 0x69,
+	0xb5, 0x96, 0xc5, 0xed, 0xc3, 0x4b, 0xc8, 0xa8, 0x1f, 0x41, 0xd1, 0x72, 0x1c, 0xe2, 0xb4, 0x66,
+	0x95, 0x56, 0x6b, 0x0a, 0xbe, 0xb8, 0x11, 0xc1, 0xe0, 0x04, 0x51, 0x24, 0xd8, 0x22, 0x23, 0x3d,
+	0x7a, 0xac, 0x18, 0xe4, 0x66, 0xc0, 0x60, 0x5d, 0x31, 0x58, 0xc4, 0x09, 0x10, 0x4e, 0xa3, 0xa2,
+	0xdf, 0x68, 0xb0, 0x26, 0x39, 0xa5, 0x93, 0xb0, 0x9c, 0x9f, 0x76, 0xae, 0x7f, 0x56, 0x11, 0x59,
+	0xdb, 0x18, 0xc5, 0xc2, 0xe3, 0xf0, 0xe8, 0x03, 0x0d, 0xd6, 0x15, 0xc9, 0x0c, 0xad, 0xc2, 0xb4,
+	0x69, 0x7d, 0x4e, 0xd1, 0x5a, 0xc7, 0xe3, 0x68, 0xf8, 0x2c, 0x0a, 0xc6, 0x3f, 0x75, 0x58, 0xde,
+	0xf0, 0xfd, 0xae, 0x4b, 0x9c, 0x7d, 0xfa, 0xba, 0xf6, 0xcd, 0xb2, 0xf6, 0xfd, 0x43, 0x03, 0x94,
+	0x75, 0xf5, 0x25, 0x54, 0xbf, 0x27, 0xd9, 0xea, 0x37, 0xa1, 0xaf, 0xb3, 0xf4, 0xcf, 0xa9, 0x7f,
+	0x7f, 0x2a, 0xc0, 0x7a, 0x56, 0xf1, 0x75, 0x05, 0x7c, 0x5d, 0x01, 0xff, 0x6f, 0x2b, 0xe0, 0x07,
+	0x39, 0x58, 0xdf, 0xec, 0xf6, 0x03, 0x4e, 0x58, 0x86, 0xf2, 0xec, 0xd3, 0xf5, 0x97, 0x1a, 0xac,
+	0x92, 0x76, 0x9b, 0xd8, 0xdc, 0x3d, 0x26, 0x91, 0x47, 0xf4, 0x69, 0x7b, 0xa4, 0xac, 0x38, 0xac,
+	0x6e, 0x8f, 0x40, 0xe1, 0x31, 0x70, 0xf4, 0x0b, 0x0d, 0xd6, 0x62, 0x61, 0xa3, 0x55, 0xef, 0x52,
+	0xfb, 0x28, 0xca, 0xe3, 0xcd, 0xc9, 0x28, 0x35, 0x5a, 0x4d, 0xc2, 0x93, 0xac, 0xd9, 0x1e, 0x45,
+	0xc1, 0xe3, 0xc0, 0xc6, 0xef, 0x34, 0x58, 0xd8, 0xf6, 0x1c, 0x9f, 0xba, 0x1e, 0x47, 0x5f, 0x00,
+	0xdd, 0xf5, 0x65, 0x24, 0x4a, 0xf5, 0xf5, 0xe1, 0xa0, 0xaa, 0x37, 0x5a, 0xa7, 0x83, 0x6a, 0xb1,
+	0xd1, 0x52, 0xbd, 0x16, 0xd6, 0x5d, 0x1f, 0x75, 0xa1, 0xe0, 0x53, 0xc6, 0x23, 0x37, 0x3e, 0x98,
+	0x8c, 0x73, 0xd3, 0xea, 0x89, 0x45, 0xc5, 0x78, 0x52, 0xe9, 0xc4, 0x53, 0x80, 0x43, 0x10, 0xa3,
+	0x0b, 0xd7, 0xb6, 0x9f, 0x71, 0xc2, 0x3c, 0xab, 0xbb, 0xed, 0x71, 0x97, 0x9f, 0x60, 0xd2, 0x26,
+	0x8c, 0x78, 0x36, 0x41, 0x37, 0x21, 0xef, 0x59, 0x3d, 0x22, 0xf9, 0x16, 0x93, 0x4d, 0x49, 0x58,
+	0xc4, 0x72, 0x04, 0xd5, 0xa0, 0x28, 0x7e, 0x03, 0xdf, 0xb2, 0x49, 0x59, 0x97, 0x6a, 0x71, 0x79,
+	0x69, 0x46, 0x03, 0x38, 0xd1, 0x31, 0xde, 0xcb, 0xc1, 0x62, 0x2a, 0xb0, 0x88, 0x40, 0xce, 0xa7,
+	0x8e, 0xca, 0xcd, 0x09, 0xdb, 0xda, 0x16, 0x75, 0x62, 0xee, 0xf5, 0xf9, 0xe1, 0xa0, 0x9a, 0x13,
+	0x12, 0x61, 0x1f, 0xfd, 0x5a, 0x83, 0x65, 0x92, 0x79, 0x4b, 0xc9, 0x76, 0xf1, 0xee, 0xe3, 0xc9,
+	0x20, 0xcf, 0xf1, 0x5c, 0x1d, 0x0d, 0x07, 0xd5, 0xe5, 0x91, 0xc1, 0x11, 0x02, 0xe8, 0x29, 0x14,
+	0x89, 0xca, 0x8b, 0x28, 0x3d, 0x77, 0x26, 0x64, 0xa3, 0xcc, 0x25, 0x31, 0x88, 0x24, 0x01, 0x4e,
+	0xb0, 0x8c, 0x3f, 0xeb, 0xb0, 0x9c, 0xad, 0xc8, 0x97, 0x15, 0x86, 0x30, 0xfd, 0xf5, 0x0b, 0xa6,
+	0x7f, 0xee, 0x12, 0xd2, 0x1f, 0x7d, 0x19, 0x72, 0xae, 0x1f, 0x6e, 0x2d, 0xa5, 0xfa, 0x1b, 0x82,
+	0x6d, 0xa3, 0x15, 0x64, 0x49, 0x09, 0x05, 0xe3, 0x6f, 0x1a, 0xcc, 0xab, 0x35, 0x8d, 0x08, 0xe4,
+	0x6d, 0xd7, 0x61, 0xca, 0x5d, 0x53, 0xa9, 0x29, 0xf1, 0xe2, 0xda, 0x6c, 0x6c, 0x61, 0x2c, 0xcd,
+	0xa3, 0x23, 0x98, 0x23, 0xcf, 0x6c, 0xe2, 0x73, 0x55, 0x08, 0xa6, 0x02, 0xb4, 0xac, 0x80, 0xe6,
+	0xb6, 0xa5, 0x69, 0xac, 0x20, 0x8c, 0x36, 0x14, 0xa4, 0xc2, 0xc5, 0x4a, 0xd4, 0x7d, 0x28, 0xf9,
+	0x8c, 0xb4, 0xdd, 0x67, 0xbb, 0xc4, 0xeb, 0xf0, 0x43, 0x19, 0xd2, 0x42, 0xd2, 0x40, 0xb6, 0x52,
+	0x63, 0x38, 0xa3, 0x69, 0xfc, 0x5c, 0x83, 0x62, 0x1c, 0x13, 0x51, 0x61, 0x44, 0x18, 0x24, 0x5c,
+	0x21, 0xdd, 0xf6, 0x32, 0x8e, 0xf3, 0xbe, 0xd2, 0x90, 0x35, 0x48, 0x3f, 0xb7, 0x06, 0xdd, 0x87,
+	0x05, 0x79, 0x01, 0x62, 0xd3, 0x6e, 0x39, 0x27, 0xb5, 0x6e, 0x44, 0xbd, 0x64, 0x4b, 0xc9, 0x4f,
+	0x53, 0xff, 0x71, 0xac, 0x6d, 0xbc, 0x9f, 0x87, 0xa5, 0x26, 0xe1, 0x4f, 0x29, 0x3b, 0x6a, 0xd1,
+	0xae, 0x6b, 0x9f, 0x5c, 0xc2, 0x7e, 0xc9, 0xa1, 0xc0, 0xfa, 0x5d, 0x12, 0x15, 0xf7, 0x47, 0x13,
+	0x66, 0x77, 0x9a, 0x3d, 0xee, 0x77, 0x49, 0x92, 0xe5, 0xe2, 0x29, 0xc0, 0x21, 0x18, 0xfa, 0x36,
+	0xac, 0x58, 0x99, 0x6e, 0x36, 0x5c, 0x5d, 0x45, 0x19, 0xe1, 0x95, 0x6c, 0xa3, 0x1b, 0xe0, 0x51,
+	0x5d, 0x74, 0x4b, 0xb8, 0xd8, 0xa5, 0x4c, 0xd4, 0xcd, 0xfc, 0x4d, 0xed, 0x96, 0x56, 0x2f, 0x85,
+	0xee, 0x0d, 0x65, 0x38, 0x1e, 0x45, 0xf7, 0xa0, 0xc4, 0x5d, 0xc2, 0xa2, 0x91, 0x72, 0x41, 0x06,
+	0x76, 0x55, 0x24, 0xc5, 0x7e, 0x4a, 0x8e, 0x33, 0x5a, 0xe8, 0x3d, 0x0d, 0x8a, 0x01, 0xed, 0x33,
+	0x9b, 0x60, 0xd2, 0x2e, 0xcf, 0x49, 0xc7, 0xef, 0x4f, 0xd3, 0x33, 0x71, 0x3d, 0x5a, 0x12, 0x55,
+	0x71, 0x2f, 0x82, 0xc2, 0x09, 0xaa, 0xf1, 0xb1, 0x06, 0x6b, 0x99, 0x49, 0x97, 0x70, 0xb0, 0xf1,
+	0xb3, 0x07, 0x9b, 0xef, 0x4c, 0xf1, 0x95, 0xcf, 0x39, 0xd7, 0xfc, 0x5b, 0x83, 0x6b, 0x19, 0xbd,
+	0x26, 0x75, 0xc8, 0x1e, 0xb7, 0x78, 0x3f, 0x40, 0x5f, 0x85, 0x05, 0x8f, 0x3a, 0xa4, 0x99, 0x6c,
+	0xf9, 0x31, 0xf7, 0xa6, 0x92, 0xe3, 0x58, 0x03, 0xdd, 0x05, 0x50, 0x37, 0x90, 0x2e, 0xf5, 0xe4,
+	0xf2, 0xcc, 0x25, 0xa9, 0xff, 0x20, 0x1e, 0xc1, 0x29, 0x2d, 0xf4, 0x0e, 0x20, 0x46, 0xac, 0xae,
+	0xfb, 0x5c, 0x3e, 0xee, 0x58, 0x6e, 0xb7, 0xcf, 0x88, 0x5c, 0xb4, 0x0b, 0xf5, 0xeb, 0x6a, 0x2e,
+	0xc2, 0x63, 0x1a, 0xf8, 0x8c, 0x59, 0xe8, 0x2b, 0x30, 0xdf, 0x23, 0x41, 0x60, 0x75, 0x88, 0x4c,
+	0xc9, 0x62, 0x7d, 0x45, 0x19, 0x98, 0x7f, 0x18, 0x8a, 0x71, 0x34, 0x6e, 0x0c, 0x47, 0x43, 0xdb,
+	0x22, 0x84, 0xa1, 0x6f, 0xc2, 0x92, 0x95, 0xba, 0xe1, 0x0a, 0xca, 0x9a, 0x5c, 0x11, 0x6b, 0xc3,
+	0x41, 0x75, 0x29, 0x7d, 0xf5, 0x15, 0xe0, 0xac, 0x1e, 0x0a, 0x60, 0xc1, 0xf5, 0x55, 0x5b, 0x19,
+	0x06, 0x6e, 0x7b, 0xd2, 0xca, 0x2c, 0xad, 0x25, 0xee, 0x8e, 0xfb, 0xc9, 0x18, 0x08, 0x55, 0xa1,
+	0xd0, 0x7e, 0xe2, 0x78, 0xd1, 0xba, 0x2d, 0x8a, 0xc8, 0xee, 0x7c, 0x77, 0xab, 0x19, 0xe0, 0x50,
+	0x6e, 0x7c, 0xa2, 0xc1, 0x67, 0xce, 0x4e, 0x7a, 0xf4, 0x75, 0xc8, 0xf3, 0x13, 0x3f, 0x0a, 0xea,
+	0x9b, 0x51, 0x0d, 0xdd, 0x3f, 0xf1, 0xc9, 0xe9, 0xa0, 0x9a, 0x75, 0x8d, 0x10, 0x62, 0xa9, 0xfe,
+	0x5f, 0x37, 0x77, 0x71, 0xad, 0xce, 0x9d, 0x5b, 0xab, 0xeb, 0x90, 0xeb, 0xbb, 0x8e, 0x0a, 0xd8,
+	0x5b, 0x4a, 0x21, 0xf7, 0xb8, 0xb1, 0x75, 0x3a, 0xa8, 0xbe, 0x79, 0xde, 0xa5, 0xb7, 0x20, 0x13,
+	0x98, 0x8f, 0x1b, 0x5b, 0x58, 0x4c, 0x36, 0xfe, 0x5a, 0x18, 0x89, 0xa6, 0xa8, 0x74, 0xe8, 0x6d,
+	0x28, 0x3a, 0x2e, 0x13, 0xbd, 0x37, 0xf5, 0xd4, 0x8b, 0x56, 0x22, 0xb2, 0x5b, 0xd1, 0xc0, 0x69,
+	0xfa, 0x01, 0x27, 0x13, 0xd0, 0x13, 0xc8, 0xb7, 0x19, 0xed, 0xa9, 0xa6, 0x70, 0x9a, 0x45, 0x59,
+	0xa4, 0x5a, 0xe2, 0x8a, 0x1d, 0x46, 0x7b, 0x58, 0x42, 0xa1, 0x23, 0xd0, 0x39, 0x2d, 0xe7, 0x66,
+	0x03, 0x08, 0x0a, 0x50, 0xdf, 0xa7, 0x58, 0xe7, 0x54, 0xa4, 0x6c, 0x40, 0xd8, 0xb1, 0x6b, 0x93,
+	0xe8, 0x14, 0x3d, 0x61, 0xca, 0xee, 0x85, 0xd6, 0x92, 0x94, 0x55, 0x82, 0x00, 0xc7, 0x40, 0xa2,
+	0x9e, 0xf8, 0x23, 0xfb, 0x40, 0xb2, 0x31, 0x8f, 0xed, 0x1c, 0xef, 0xc2, 0x9c, 0x15, 0x46, 0x6f,
+	0x4e, 0x46, 0x0f, 0x8b, 0x26, 0x65, 0x23, 0x0a, 0xdb, 0xd6, 0x85, 0x3f, 0xfc, 0x10, 0xbb, 0x2f,
+	0xec, 0xc5, 0xdf, 0x7e, 0x4c, 0x91, 0x1e, 0xa1, 0x1d, 0xac, 0x10, 0xd0, 0xb7, 0x60, 0x89, 0x78,
+	0xd6, 0x41, 0x97, 0xec, 0xd2, 0x4e, 0xc7, 0xf5, 0x3a, 0xe5, 0x79, 0x59, 0x82, 0xae, 0x2a, 0x7a,
+	0x4b, 0xdb, 0xe9, 0x41, 0x9c, 0xd5, 0x3d, 0x6b, 0x2f, 0x5d, 0xb8, 0xf8, 0x5e, 0x6a, 0xfc, 0x41,
+	0x07, 0x94, 0x09, 0x98, 0xa8, 0xbe, 0x81, 0x38, 0xa1, 0x2c, 0x79, 0x69, 0x71, 0x59, 0x9b, 0xe1,
+	0x36, 0x18, 0xbf, 0x69, 0x76, 0x3c, 0xcb, 0x00, 0xfd, 0x18, 0x4a, 0x9c, 0x59, 0xed, 0xb6, 0x6b,
+	0x4b, 0x8e, 0x6a, 0x75, 0x6c, 0x5d, 0x98, 0x91, 0xfc, 0x08, 0x67, 0xc6, 0x81, 0xd8, 0x4f, 0xd9,
+	0x4a, 0x7a, 0xc5, 0xb4, 0x14, 0x67, 0xf0, 0x8c, 0x9f, 0xe5, 0x60, 0x7d, 0xcc, 0x55, 0xfd, 0xcb,
+	0xb8, 0xd5, 0x18, 0x8f, 0x86, 0xfe, 0x3f, 0x8f, 0xc6, 0x73, 0x28, 0x88, 0xcd, 0x37, 0x3a, 0x17,
+	0x3d, 0x9e, 0x22, 0x95, 0xa4, 0x09, 0x48, 0xda, 0x06, 0x21, 0x0b, 0x70, 0x08, 0x69, 0xfc, 0x2b,
+	0x0f, 0xab, 0x91, 0x52, 0xb0, 0xd7, 0xef, 0xf5, 0x2c, 0x76, 0x19, 0xcd, 0xf2, 0x6f, 0x35, 0x58,
+	0x49, 0x3b, 0xc1, 0x8d, 0xfb, 0xe6, 0xd6, 0x14, 0xdf, 0x3e, 0x4c, 0xc8, 0x6b, 0x8a, 0xc9, 0x4a,
+	0x33, 0x0b, 0x88, 0x47, 0x19, 0xa0, 0xbf, 0x68, 0x70, 0x23, 0x44, 0x51, 0x57, 0x6e, 0x23, 0x33,
+	0xca, 0xb9, 0x19, 0x51, 0xfc, 0xa2, 0xa2, 0x78, 0x63, 0xe3, 0x53, 0xd0, 0xf1, 0xa7, 0x72, 0x43,
+	0xbf, 0xd7, 0xe0, 0x6a, 0xa8, 0x30, 0xca, 0x3a, 0x3f, 0x23, 0xd6, 0x9f, 0x57, 0xac, 0xaf, 0x6e,
+	0x9c, 0x05, 0x8b, 0xcf, 0x66, 0x63, 0x58, 0x50, 0x4a, 0x5f, 0x24, 0xcc, 0xe2, 0x2e, 0xea, 0x7d,
+	0x1d, 0xe6, 0xd5, 0xb6, 0x85, 0xee, 0xa5, 0x0e, 0x91, 0x21, 0x44, 0xf9, 0xd5, 0x07, 0x48, 0xd4,
+	0x54, 0xc7, 0x57, 0xfd, 0x15, 0xd9, 0x2f, 0x3e, 0xdb, 0x9b, 0xe1, 0x67, 0x7b, 0xb3, 0xe1, 0xf1,
+	0x47, 0x6c, 0x8f, 0x33, 0xd7, 0xeb, 0xd4, 0x17, 0x46, 0x0e, 0xbb, 0x5f, 0x82, 0x79, 0xe2, 0xc9,
+	0x93, 0xb1, 0x6c, 0x0c, 0x0a, 0xf5, 0x45, 0xd1, 0xcf, 0x6e, 0x87, 0x22, 0x1c, 0x8d, 0x89, 0xe3,
+	0x98, 0x6b, 0xf7, 0x7c, 0xd1, 0xaa, 0xc9, 0x56, 0xaa, 0x10, 0x1e, 0xc7, 0x1a, 0x9b, 0x0f, 0x5b,
+	0x42, 0x86, 0xe3, 0xd1, 0x48, 0x73, 0x93, 0x3a, 0xa4, 0x5c, 0xc8, 0x6a, 0x0a, 0x19, 0x8e, 0x47,
+	0xeb, 0xb7, 0x5f, 0xbc, 0xac, 0x5c, 0xf9, 0xf0, 0x65, 0xe5, 0xca, 0x47, 0x2f, 0x2b, 0x57, 0x7e,
+	0x32, 0xac, 0x68, 0x2f, 0x86, 0x15, 0xed, 0xc3, 0x61, 0x45, 0xfb, 0x68, 0x58, 0xd1, 0xfe, 0x3e,
+	0xac, 0x68, 0xbf, 0xfa, 0xb8, 0x72, 0xe5, 0x7b, 0xf3, 0x2a, 0xce, 0xff, 0x19, 0x00, 0xfc, 0x2d,
+	0x26, 0x2c, 0x44, 0x22, 0x00, 0x00,
 }
 
 func (m *AddressGroup) Marshal() (dAtA []byte, err error) {
@@ -1190,6 +1297,67 @@ func (m *AppliedToGroupPatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ClusterGroupMembers) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClusterGroupMembers) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClusterGroupMembers) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.EffectiveIPBlocks) > 0 {
+		for iNdEx := len(m.EffectiveIPBlocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.EffectiveIPBlocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.EffectiveMembers) > 0 {
+		for iNdEx := len(m.EffectiveMembers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.EffectiveMembers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	{
+		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *Endpoint) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1348,6 +1516,15 @@ func (m *GroupMemberPod) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.IPs) > 0 {
+		for iNdEx := len(m.IPs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.IPs[iNdEx])
+			copy(dAtA[i:], m.IPs[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.IPs[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Ports) > 0 {
 		for iNdEx := len(m.Ports) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1626,6 +1803,50 @@ func (m *NetworkPolicyList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NetworkPolicyNodeStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetworkPolicyNodeStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NetworkPolicyNodeStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.Message)
+	copy(dAtA[i:], m.Message)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Message)))
+	i--
+	dAtA[i] = 0x22
+	i--
+	if m.RealizationFailure {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Generation))
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.NodeName)
+	copy(dAtA[i:], m.NodeName)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.NodeName)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *NetworkPolicyPeer) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1646,6 +1867,15 @@ func (m *NetworkPolicyPeer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.FQDNs) > 0 {
+		for iNdEx := len(m.FQDNs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.FQDNs[iNdEx])
+			copy(dAtA[i:], m.FQDNs[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.FQDNs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.IPBlocks) > 0 {
 		for iNdEx := len(m.IPBlocks) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1735,6 +1965,23 @@ func (m *NetworkPolicyRule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.AppliedToGroups) > 0 {
+		for iNdEx := len(m.AppliedToGroups) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AppliedToGroups[iNdEx])
+			copy(dAtA[i:], m.AppliedToGroups[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.AppliedToGroups[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	i--
+	if m.EnableLogging {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x38
 	if m.Action != nil {
 		i -= len(*m.Action)
 		copy(dAtA[i:], *m.Action)
@@ -1830,6 +2077,63 @@ func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NetworkPolicyStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetworkPolicyStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NetworkPolicyStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Nodes) > 0 {
+		for iNdEx := len(m.Nodes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Nodes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	{
+		size, err := m.NetworkPolicy.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *NodeStatsSummary) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1958,6 +2262,21 @@ func (m *Service) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ICMPCode != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.ICMPCode))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.ICMPType != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.ICMPType))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.EndPort != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.EndPort))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.Port != nil {
 		{
 			size, err := m.Port.MarshalToSizedBuffer(dAtA[:i])
@@ -2141,6 +2460,29 @@ func (m *AppliedToGroupPatch) Size() (n int) {
 	return n
 }
 
+func (m *ClusterGroupMembers) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.EffectiveMembers) > 0 {
+		for _, e := range m.EffectiveMembers {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.EffectiveIPBlocks) > 0 {
+		for _, e := range m.EffectiveIPBlocks {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *Endpoint) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2216,6 +2558,12 @@ func (m *GroupMemberPod) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if len(m.IPs) > 0 {
+		for _, b := range m.IPs {
+			l = len(b)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2314,6 +2662,21 @@ func (m *NetworkPolicyList) Size() (n int) {
 	return n
 }
 
+func (m *NetworkPolicyNodeStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.NodeName)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.Generation))
+	n += 2
+	l = len(m.Message)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
 func (m *NetworkPolicyPeer) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2332,6 +2695,12 @@ func (m *NetworkPolicyPeer) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if len(m.FQDNs) > 0 {
+		for _, s := range m.FQDNs {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2375,6 +2744,13 @@ func (m *NetworkPolicyRule) Size() (n int) {
 		l = len(*m.Action)
 		n += 1 + l + sovGenerated(uint64(l))
 	}
+	n += 2
+	if len(m.AppliedToGroups) > 0 {
+		for _, s := range m.AppliedToGroups {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2391,6 +2767,25 @@ func (m *NetworkPolicyStats) Size() (n int) {
 	return n
 }
 
+func (m *NetworkPolicyStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.NetworkPolicy.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Nodes) > 0 {
+		for _, e := range m.Nodes {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *NodeStatsSummary) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2447,6 +2842,15 @@ func (m *Service) Size() (n int) {
 		l = m.Port.Size()
 		n += 1 + l + sovGenerated(uint64(l))
 	}
+	if m.EndPort != nil {
+		n += 1 + sovGenerated(uint64(*m.EndPort))
+	}
+	if m.ICMPType != nil {
+		n += 1 + sovGenerated(uint64(*m.ICMPType))
+	}
+	if m.ICMPCode != nil {
+		n += 1 + sovGenerated(uint64(*m.ICMPCode))
+	}
 	return n
 }
 
@@ -2600,6 +3004,28 @@ func (this *AppliedToGroupPatch) String() string {
 	}, "")
 	return s
 }
+func (this *ClusterGroupMembers) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForEffectiveMembers := "[]GroupMember{"
+	for _, f := range this.EffectiveMembers {
+		repeatedStringForEffectiveMembers += strings.Replace(strings.Replace(f.String(), "GroupMember", "GroupMember", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForEffectiveMembers += "}"
+	repeatedStringForEffectiveIPBlocks := "[]IPNet{"
+	for _, f := range this.EffectiveIPBlocks {
+		repeatedStringForEffectiveIPBlocks += strings.Replace(strings.Replace(f.String(), "IPNet", "IPNet", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForEffectiveIPBlocks += "}"
+	s := strings.Join([]string{`&ClusterGroupMembers{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`EffectiveMembers:` + repeatedStringForEffectiveMembers + `,`,
+		`EffectiveIPBlocks:` + repeatedStringForEffectiveIPBlocks + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *Endpoint) String() string {
 	if this == nil {
 		return "nil"
@@ -2657,6 +3083,7 @@ func (this *GroupMemberPod) String() string {
 		`Pod:` + strings.Replace(this.Pod.String(), "PodReference", "PodReference", 1) + `,`,
 		`IP:` + valueToStringGenerated(this.IP) + `,`,
 		`Ports:` + repeatedStringForPorts + `,`,
+		`IPs:` + fmt.Sprintf("%v", this.IPs) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2736,6 +3163,19 @@ func (this *NetworkPolicyList) String() string {
 	}, "")
 	return s
 }
+func (this *NetworkPolicyNodeStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NetworkPolicyNodeStatus{`,
+		`NodeName:` + fmt.Sprintf("%v", this.NodeName) + `,`,
+		`Generation:` + fmt.Sprintf("%v", this.Generation) + `,`,
+		`RealizationFailure:` + fmt.Sprintf("%v", this.RealizationFailure) + `,`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *NetworkPolicyPeer) String() string {
 	if this == nil {
 		return "nil"
@@ -2748,6 +3188,7 @@ func (this *NetworkPolicyPeer) String() string {
 	s := strings.Join([]string{`&NetworkPolicyPeer{`,
 		`AddressGroups:` + fmt.Sprintf("%v", this.AddressGroups) + `,`,
 		`IPBlocks:` + repeatedStringForIPBlocks + `,`,
+		`FQDNs:` + fmt.Sprintf("%v", this.FQDNs) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2781,6 +3222,8 @@ func (this *NetworkPolicyRule) String() string {
 		`Services:` + repeatedStringForServices + `,`,
 		`Priority:` + fmt.Sprintf("%v", this.Priority) + `,`,
 		`Action:` + valueToStringGenerated(this.Action) + `,`,
+		`EnableLogging:` + fmt.Sprintf("%v", this.EnableLogging) + `,`,
+		`AppliedToGroups:` + fmt.Sprintf("%v", this.AppliedToGroups) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2796,6 +3239,23 @@ func (this *NetworkPolicyStats) String() string {
 	}, "")
 	return s
 }
+func (this *NetworkPolicyStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForNodes := "[]NetworkPolicyNodeStatus{"
+	for _, f := range this.Nodes {
+		repeatedStringForNodes += strings.Replace(strings.Replace(f.String(), "NetworkPolicyNodeStatus", "NetworkPolicyNodeStatus", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForNodes += "}"
+	s := strings.Join([]string{`&NetworkPolicyStatus{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`NetworkPolicy:` + strings.Replace(strings.Replace(this.NetworkPolicy.String(), "NetworkPolicyReference", "NetworkPolicyReference", 1), `&`, ``, 1) + `,`,
+		`Nodes:` + repeatedStringForNodes + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *NodeStatsSummary) String() string {
 	if this == nil {
 		return "nil"
@@ -2842,6 +3302,9 @@ func (this *Service) String() string {
 	s := strings.Join([]string{`&Service{`,
 		`Protocol:` + valueToStringGenerated(this.Protocol) + `,`,
 		`Port:` + strings.Replace(fmt.Sprintf("%v", this.Port), "IntOrString", "intstr.IntOrString", 1) + `,`,
+		`EndPort:` + valueToStringGenerated(this.EndPort) + `,`,
+		`ICMPType:` + valueToStringGenerated(this.ICMPType) + `,`,
+		`ICMPCode:` + valueToStringGenerated(this.ICMPCode) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3846,6 +4309,160 @@ func (m *AppliedToGroupPatch) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ClusterGroupMembers) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClusterGroupMembers: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClusterGroupMembers: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EffectiveMembers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EffectiveMembers = append(m.EffectiveMembers, GroupMember{})
+			if err := m.EffectiveMembers[len(m.EffectiveMembers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EffectiveIPBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EffectiveIPBlocks = append(m.EffectiveIPBlocks, IPNet{})
+			if err := m.EffectiveIPBlocks[len(m.EffectiveIPBlocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Endpoint) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -4376,6 +4993,38 @@ func (m *GroupMemberPod) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IPs", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IPs = append(m.IPs, make([]byte, postIndex-iNdEx))
+			copy(m.IPs[len(m.IPs)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -5102,7 +5751,7 @@ func (m *NetworkPolicyList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
+func (m *NetworkPolicyNodeStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5125,15 +5774,15 @@ func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NetworkPolicyPeer: wiretype end group for non-group")
+			return fmt.Errorf("proto: NetworkPolicyNodeStatus: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NetworkPolicyPeer: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NetworkPolicyNodeStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AddressGroups", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NodeName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5161,13 +5810,13 @@ func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AddressGroups = append(m.AddressGroups, string(dAtA[iNdEx:postIndex]))
+			m.NodeName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IPBlocks", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Generation", wireType)
 			}
-			var msglen int
+			m.Generation = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -5177,13 +5826,169 @@ func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Generation |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RealizationFailure", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.RealizationFailure = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkPolicyPeer: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkPolicyPeer: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AddressGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AddressGroups = append(m.AddressGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IPBlocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
 			}
 			postIndex := iNdEx + msglen
 			if postIndex < 0 {
@@ -5197,6 +6002,38 @@ func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FQDNs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FQDNs = append(m.FQDNs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -5615,6 +6452,58 @@ func (m *NetworkPolicyRule) Unmarshal(dAtA []byte) error {
 			s := github_com_vmware_tanzu_antrea_pkg_apis_security_v1alpha1.RuleAction(dAtA[iNdEx:postIndex])
 			m.Action = &s
 			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EnableLogging", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EnableLogging = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppliedToGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppliedToGroups = append(m.AppliedToGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -5758,6 +6647,159 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *NetworkPolicyStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkPolicyStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkPolicyStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkPolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.NetworkPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nodes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nodes = append(m.Nodes, NetworkPolicyNodeStatus{})
+			if err := m.Nodes[len(m.Nodes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *NodeStatsSummary) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -6161,6 +7203,66 @@ func (m *Service) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndPort", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EndPort = &v
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ICMPType", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ICMPType = &v
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ICMPCode", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ICMPCode = &v
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -64,6 +64,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NetworkPolicy{},
 		&NetworkPolicyList{},
 		&NodeStatsSummary{},
+		&ClusterGroupMembers{},
+		&NetworkPolicyStatus{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -63,6 +63,10 @@ type GroupMemberPod struct {
 	IP IPAddress `json:"ip,omitempty" protobuf:"bytes,2,opt,name=ip"`
 	// Ports maintain the named port mapping of this Pod.
 	Ports []NamedPort `json:"ports,omitempty" protobuf:"bytes,3,rep,name=ports"`
+	// IPs maintains all the IPAddresses associated with the Pod. A dual-stack
+	// Pod has both an IPv4 and an IPv6 address, the first of which is the same
+	// as IP.
+	IPs []IPAddress `json:"ips,omitempty" protobuf:"bytes,4,rep,name=ips,casttype=IPAddress"`
 }
 
 // ExternalEntityReference represents a ExternalEntity Reference.
@@ -220,6 +224,11 @@ type NetworkPolicyRule struct {
 	// action “nil” defaults to Allow action, which would be the case for rules created for
 	// K8s Network Policy.
 	Action *secv1alpha1.RuleAction `json:"action,omitempty" protobuf:"bytes,6,opt,name=action,casttype=github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1.RuleAction"`
+	// EnableLogging indicates whether or not to generate logs when rules are matched. Default to false.
+	EnableLogging bool `json:"enableLogging" protobuf:"varint,7,opt,name=enableLogging"`
+	// AppliedToGroups is a list of names of AppliedToGroups to which this rule applies.
+	// If it's empty, the rule applies to the AppliedToGroups of the NetworkPolicy.
+	AppliedToGroups []string `json:"appliedToGroups,omitempty" protobuf:"bytes,8,rep,name=appliedToGroups"`
 }
 
 // Protocol defines network protocols supported for things like container ports.
@@ -232,17 +241,31 @@ const (
 	ProtocolUDP Protocol = "UDP"
 	// ProtocolSCTP is the SCTP protocol.
 	ProtocolSCTP Protocol = "SCTP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
+	// ProtocolICMPv6 is the ICMPv6 protocol.
+	ProtocolICMPv6 Protocol = "ICMPv6"
 )
 
 // Service describes a port to allow traffic on.
 type Service struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this
-	// field defaults to TCP.
+	// The protocol (TCP, UDP, SCTP, ICMP or ICMPv6) which traffic must match. If not
+	// specified, this field defaults to TCP.
 	// +optional
 	Protocol *Protocol `json:"protocol,omitempty" protobuf:"bytes,1,opt,name=protocol"`
 	// The port name or number on the given protocol. If not specified, this matches all port numbers.
 	// +optional
 	Port *intstr.IntOrString `json:"port,omitempty" protobuf:"bytes,2,opt,name=port"`
+	// EndPort is the end of the port range, inclusive. It can only be set along with a
+	// numerical Port.
+	// +optional
+	EndPort *int32 `json:"endPort,omitempty" protobuf:"varint,3,opt,name=endPort"`
+	// ICMPType is the type of the ICMP or ICMPv6 messages which traffic must match.
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty" protobuf:"varint,4,opt,name=icmpType"`
+	// ICMPCode is the code of the ICMP or ICMPv6 messages which traffic must match.
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty" protobuf:"varint,5,opt,name=icmpCode"`
 }
 
 // NetworkPolicyPeer describes a peer of NetworkPolicyRules.
@@ -252,6 +275,9 @@ type NetworkPolicyPeer struct {
 	AddressGroups []string `json:"addressGroups,omitempty" protobuf:"bytes,1,rep,name=addressGroups"`
 	// A list of IPBlock.
 	IPBlocks []IPBlock `json:"ipBlocks,omitempty" protobuf:"bytes,2,rep,name=ipBlocks"`
+	// A list of FQDN selectors, which match the IP addresses the FQDNs
+	// resolve to.
+	FQDNs []string `json:"fqdns,omitempty" protobuf:"bytes,3,rep,name=fqdns"`
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24"). The except entry describes CIDRs that should
@@ -273,6 +299,20 @@ type NetworkPolicyList struct {
 	Items           []NetworkPolicy `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=get
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// ClusterGroupMembers is the resolved membership of a ClusterGroup.
+type ClusterGroupMembers struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	// EffectiveMembers is a list of Pods and ExternalEntities currently selected by the ClusterGroup.
+	EffectiveMembers []GroupMember `json:"effectiveMembers,omitempty" protobuf:"bytes,2,rep,name=effectiveMembers"`
+	// EffectiveIPBlocks is a list of IPNets which are members of the ClusterGroup.
+	EffectiveIPBlocks []IPNet `json:"effectiveIPBlocks,omitempty" protobuf:"bytes,3,rep,name=effectiveIPBlocks"`
+}
+
 // +genclient
 // +genclient:nonNamespaced
 // +genclient:onlyVerbs=create
@@ -298,3 +338,31 @@ type NetworkPolicyStats struct {
 	// The stats of the NetworkPolicy.
 	TrafficStats statsv1alpha1.TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,2,opt,name=trafficStats"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// NetworkPolicyStatus is the status of a NetworkPolicy. It's used by the antrea-agents to report the realization
+// status of NetworkPolicies to the antrea-controller.
+type NetworkPolicyStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// The reference of the NetworkPolicy.
+	NetworkPolicy NetworkPolicyReference `json:"networkPolicy,omitempty" protobuf:"bytes,2,opt,name=networkPolicy"`
+	// Nodes contains statuses produced on a list of Nodes.
+	Nodes []NetworkPolicyNodeStatus `json:"nodes,omitempty" protobuf:"bytes,3,rep,name=nodes"`
+}
+
+// NetworkPolicyNodeStatus is the status of a NetworkPolicy on a Node.
+type NetworkPolicyNodeStatus struct {
+	// The name of the Node that produces the status.
+	NodeName string `json:"nodeName,omitempty" protobuf:"bytes,1,opt,name=nodeName"`
+	// The generation realized by the Node.
+	Generation int64 `json:"generation,omitempty" protobuf:"varint,2,opt,name=generation"`
+	// RealizationFailure is true if the Node failed to realize the NetworkPolicy.
+	RealizationFailure bool `json:"realizationFailure,omitempty" protobuf:"varint,3,opt,name=realizationFailure"`
+	// Message is a human readable message indicating details about the realization failure.
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+}
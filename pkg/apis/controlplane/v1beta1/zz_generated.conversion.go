@@ -96,6 +96,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterGroupMembers)(nil), (*controlplane.ClusterGroupMembers)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClusterGroupMembers_To_controlplane_ClusterGroupMembers(a.(*ClusterGroupMembers), b.(*controlplane.ClusterGroupMembers), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.ClusterGroupMembers)(nil), (*ClusterGroupMembers)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_ClusterGroupMembers_To_v1beta1_ClusterGroupMembers(a.(*controlplane.ClusterGroupMembers), b.(*ClusterGroupMembers), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Endpoint)(nil), (*controlplane.Endpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Endpoint_To_controlplane_Endpoint(a.(*Endpoint), b.(*controlplane.Endpoint), scope)
 	}); err != nil {
@@ -186,6 +196,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NetworkPolicyNodeStatus)(nil), (*controlplane.NetworkPolicyNodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(a.(*NetworkPolicyNodeStatus), b.(*controlplane.NetworkPolicyNodeStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.NetworkPolicyNodeStatus)(nil), (*NetworkPolicyNodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(a.(*controlplane.NetworkPolicyNodeStatus), b.(*NetworkPolicyNodeStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkPolicyPeer)(nil), (*controlplane.NetworkPolicyPeer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(a.(*NetworkPolicyPeer), b.(*controlplane.NetworkPolicyPeer), scope)
 	}); err != nil {
@@ -226,6 +246,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NetworkPolicyStatus)(nil), (*controlplane.NetworkPolicyStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(a.(*NetworkPolicyStatus), b.(*controlplane.NetworkPolicyStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.NetworkPolicyStatus)(nil), (*NetworkPolicyStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(a.(*controlplane.NetworkPolicyStatus), b.(*NetworkPolicyStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeStatsSummary)(nil), (*controlplane.NodeStatsSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NodeStatsSummary_To_controlplane_NodeStatsSummary(a.(*NodeStatsSummary), b.(*controlplane.NodeStatsSummary), scope)
 	}); err != nil {
@@ -407,6 +437,30 @@ func Convert_controlplane_AppliedToGroupPatch_To_v1beta1_AppliedToGroupPatch(in
 	return autoConvert_controlplane_AppliedToGroupPatch_To_v1beta1_AppliedToGroupPatch(in, out, s)
 }
 
+func autoConvert_v1beta1_ClusterGroupMembers_To_controlplane_ClusterGroupMembers(in *ClusterGroupMembers, out *controlplane.ClusterGroupMembers, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.EffectiveMembers = *(*[]controlplane.GroupMember)(unsafe.Pointer(&in.EffectiveMembers))
+	out.EffectiveIPBlocks = *(*[]controlplane.IPNet)(unsafe.Pointer(&in.EffectiveIPBlocks))
+	return nil
+}
+
+// Convert_v1beta1_ClusterGroupMembers_To_controlplane_ClusterGroupMembers is an autogenerated conversion function.
+func Convert_v1beta1_ClusterGroupMembers_To_controlplane_ClusterGroupMembers(in *ClusterGroupMembers, out *controlplane.ClusterGroupMembers, s conversion.Scope) error {
+	return autoConvert_v1beta1_ClusterGroupMembers_To_controlplane_ClusterGroupMembers(in, out, s)
+}
+
+func autoConvert_controlplane_ClusterGroupMembers_To_v1beta1_ClusterGroupMembers(in *controlplane.ClusterGroupMembers, out *ClusterGroupMembers, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.EffectiveMembers = *(*[]GroupMember)(unsafe.Pointer(&in.EffectiveMembers))
+	out.EffectiveIPBlocks = *(*[]IPNet)(unsafe.Pointer(&in.EffectiveIPBlocks))
+	return nil
+}
+
+// Convert_controlplane_ClusterGroupMembers_To_v1beta1_ClusterGroupMembers is an autogenerated conversion function.
+func Convert_controlplane_ClusterGroupMembers_To_v1beta1_ClusterGroupMembers(in *controlplane.ClusterGroupMembers, out *ClusterGroupMembers, s conversion.Scope) error {
+	return autoConvert_controlplane_ClusterGroupMembers_To_v1beta1_ClusterGroupMembers(in, out, s)
+}
+
 func autoConvert_v1beta1_Endpoint_To_controlplane_Endpoint(in *Endpoint, out *controlplane.Endpoint, s conversion.Scope) error {
 	out.IP = *(*controlplane.IPAddress)(unsafe.Pointer(&in.IP))
 	out.Ports = *(*[]controlplane.NamedPort)(unsafe.Pointer(&in.Ports))
@@ -479,6 +533,7 @@ func autoConvert_v1beta1_GroupMemberPod_To_controlplane_GroupMemberPod(in *Group
 	out.Pod = (*controlplane.PodReference)(unsafe.Pointer(in.Pod))
 	out.IP = *(*controlplane.IPAddress)(unsafe.Pointer(&in.IP))
 	out.Ports = *(*[]controlplane.NamedPort)(unsafe.Pointer(&in.Ports))
+	out.IPs = *(*[]controlplane.IPAddress)(unsafe.Pointer(&in.IPs))
 	return nil
 }
 
@@ -491,6 +546,7 @@ func autoConvert_controlplane_GroupMemberPod_To_v1beta1_GroupMemberPod(in *contr
 	out.Pod = (*PodReference)(unsafe.Pointer(in.Pod))
 	out.IP = *(*IPAddress)(unsafe.Pointer(&in.IP))
 	out.Ports = *(*[]NamedPort)(unsafe.Pointer(&in.Ports))
+	out.IPs = *(*[]IPAddress)(unsafe.Pointer(&in.IPs))
 	return nil
 }
 
@@ -623,9 +679,36 @@ func Convert_controlplane_NetworkPolicyList_To_v1beta1_NetworkPolicyList(in *con
 	return autoConvert_controlplane_NetworkPolicyList_To_v1beta1_NetworkPolicyList(in, out, s)
 }
 
+func autoConvert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(in *NetworkPolicyNodeStatus, out *controlplane.NetworkPolicyNodeStatus, s conversion.Scope) error {
+	out.NodeName = in.NodeName
+	out.Generation = in.Generation
+	out.RealizationFailure = in.RealizationFailure
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus is an autogenerated conversion function.
+func Convert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(in *NetworkPolicyNodeStatus, out *controlplane.NetworkPolicyNodeStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(in, out, s)
+}
+
+func autoConvert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(in *controlplane.NetworkPolicyNodeStatus, out *NetworkPolicyNodeStatus, s conversion.Scope) error {
+	out.NodeName = in.NodeName
+	out.Generation = in.Generation
+	out.RealizationFailure = in.RealizationFailure
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus is an autogenerated conversion function.
+func Convert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(in *controlplane.NetworkPolicyNodeStatus, out *NetworkPolicyNodeStatus, s conversion.Scope) error {
+	return autoConvert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(in, out, s)
+}
+
 func autoConvert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(in *NetworkPolicyPeer, out *controlplane.NetworkPolicyPeer, s conversion.Scope) error {
 	out.AddressGroups = *(*[]string)(unsafe.Pointer(&in.AddressGroups))
 	out.IPBlocks = *(*[]controlplane.IPBlock)(unsafe.Pointer(&in.IPBlocks))
+	out.FQDNs = *(*[]string)(unsafe.Pointer(&in.FQDNs))
 	return nil
 }
 
@@ -637,6 +720,7 @@ func Convert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(in *Net
 func autoConvert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in *controlplane.NetworkPolicyPeer, out *NetworkPolicyPeer, s conversion.Scope) error {
 	out.AddressGroups = *(*[]string)(unsafe.Pointer(&in.AddressGroups))
 	out.IPBlocks = *(*[]IPBlock)(unsafe.Pointer(&in.IPBlocks))
+	out.FQDNs = *(*[]string)(unsafe.Pointer(&in.FQDNs))
 	return nil
 }
 
@@ -682,6 +766,8 @@ func autoConvert_v1beta1_NetworkPolicyRule_To_controlplane_NetworkPolicyRule(in
 	out.Services = *(*[]controlplane.Service)(unsafe.Pointer(&in.Services))
 	out.Priority = in.Priority
 	out.Action = (*v1alpha1.RuleAction)(unsafe.Pointer(in.Action))
+	out.EnableLogging = in.EnableLogging
+	out.AppliedToGroups = *(*[]string)(unsafe.Pointer(&in.AppliedToGroups))
 	return nil
 }
 
@@ -701,6 +787,8 @@ func autoConvert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in
 	out.Services = *(*[]Service)(unsafe.Pointer(&in.Services))
 	out.Priority = in.Priority
 	out.Action = (*v1alpha1.RuleAction)(unsafe.Pointer(in.Action))
+	out.EnableLogging = in.EnableLogging
+	out.AppliedToGroups = *(*[]string)(unsafe.Pointer(&in.AppliedToGroups))
 	return nil
 }
 
@@ -735,6 +823,34 @@ func Convert_controlplane_NetworkPolicyStats_To_v1beta1_NetworkPolicyStats(in *c
 	return autoConvert_controlplane_NetworkPolicyStats_To_v1beta1_NetworkPolicyStats(in, out, s)
 }
 
+func autoConvert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(in *NetworkPolicyStatus, out *controlplane.NetworkPolicyStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_NetworkPolicyReference_To_controlplane_NetworkPolicyReference(&in.NetworkPolicy, &out.NetworkPolicy, s); err != nil {
+		return err
+	}
+	out.Nodes = *(*[]controlplane.NetworkPolicyNodeStatus)(unsafe.Pointer(&in.Nodes))
+	return nil
+}
+
+// Convert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus is an autogenerated conversion function.
+func Convert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(in *NetworkPolicyStatus, out *controlplane.NetworkPolicyStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(in, out, s)
+}
+
+func autoConvert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(in *controlplane.NetworkPolicyStatus, out *NetworkPolicyStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_controlplane_NetworkPolicyReference_To_v1beta1_NetworkPolicyReference(&in.NetworkPolicy, &out.NetworkPolicy, s); err != nil {
+		return err
+	}
+	out.Nodes = *(*[]NetworkPolicyNodeStatus)(unsafe.Pointer(&in.Nodes))
+	return nil
+}
+
+// Convert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus is an autogenerated conversion function.
+func Convert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(in *controlplane.NetworkPolicyStatus, out *NetworkPolicyStatus, s conversion.Scope) error {
+	return autoConvert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(in, out, s)
+}
+
 func autoConvert_v1beta1_NodeStatsSummary_To_controlplane_NodeStatsSummary(in *NodeStatsSummary, out *controlplane.NodeStatsSummary, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	out.NetworkPolicies = *(*[]controlplane.NetworkPolicyStats)(unsafe.Pointer(&in.NetworkPolicies))
@@ -786,6 +902,9 @@ func Convert_controlplane_PodReference_To_v1beta1_PodReference(in *controlplane.
 func autoConvert_v1beta1_Service_To_controlplane_Service(in *Service, out *controlplane.Service, s conversion.Scope) error {
 	out.Protocol = (*controlplane.Protocol)(unsafe.Pointer(in.Protocol))
 	out.Port = (*intstr.IntOrString)(unsafe.Pointer(in.Port))
+	out.EndPort = (*int32)(unsafe.Pointer(in.EndPort))
+	out.ICMPType = (*int32)(unsafe.Pointer(in.ICMPType))
+	out.ICMPCode = (*int32)(unsafe.Pointer(in.ICMPCode))
 	return nil
 }
 
@@ -797,6 +916,9 @@ func Convert_v1beta1_Service_To_controlplane_Service(in *Service, out *controlpl
 func autoConvert_controlplane_Service_To_v1beta1_Service(in *controlplane.Service, out *Service, s conversion.Scope) error {
 	out.Protocol = (*Protocol)(unsafe.Pointer(in.Protocol))
 	out.Port = (*intstr.IntOrString)(unsafe.Pointer(in.Port))
+	out.EndPort = (*int32)(unsafe.Pointer(in.EndPort))
+	out.ICMPType = (*int32)(unsafe.Pointer(in.ICMPType))
+	out.ICMPCode = (*int32)(unsafe.Pointer(in.ICMPCode))
 	return nil
 }
 
@@ -0,0 +1,212 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Egress defines which egress (SNAT) IP the traffic from the selected Pods to
+// the external network should use.
+type Egress struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of Egress.
+	Spec EgressSpec `json:"spec"`
+}
+
+// EgressSpec defines the desired state for Egress.
+type EgressSpec struct {
+	// AppliedTo selects Pods to which the Egress will be applied.
+	AppliedTo AppliedTo `json:"appliedTo"`
+	// EgressIP specifies the SNAT IP address for the selected workloads. The
+	// IP must be configured on one of the Nodes of the cluster, which is
+	// responsible for performing SNAT for the selected traffic.
+	EgressIP string `json:"egressIP"`
+}
+
+// AppliedTo selects the entities to which a policy is applied.
+type AppliedTo struct {
+	// Select Pods matched by this selector. If set with NamespaceSelector,
+	// Pods are matched from Namespaces matched by the NamespaceSelector;
+	// otherwise, Pods are matched from all Namespaces.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// Select all Pods from Namespaces matched by this selector. If set with
+	// PodSelector, Pods are matched from Namespaces matched by the
+	// NamespaceSelector.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EgressList is a list of Egress objects.
+type EgressList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Egress `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterGroup is a named set of workloads or IP addresses which can be
+// referenced in Antrea ClusterNetworkPolicies.
+type ClusterGroup struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Desired state of the group.
+	Spec GroupSpec `json:"spec"`
+}
+
+// GroupSpec defines the members of a ClusterGroup. Exactly one of the
+// selectors, IPBlocks and ServiceReference must be set.
+type GroupSpec struct {
+	// Select Pods matched by this selector. If set with NamespaceSelector,
+	// Pods are matched from Namespaces matched by the NamespaceSelector;
+	// otherwise, Pods are matched from all Namespaces.
+	// Cannot be set with any other selector except NamespaceSelector.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// Select all Pods from Namespaces matched by this selector. If set with
+	// PodSelector, Pods are matched from Namespaces matched by the
+	// NamespaceSelector.
+	// Cannot be set with any other selector except PodSelector or
+	// ExternalEntitySelector.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Select ExternalEntities matched by this selector. If set with
+	// NamespaceSelector, ExternalEntities are matched from Namespaces matched
+	// by the NamespaceSelector; otherwise, ExternalEntities are matched from
+	// all Namespaces.
+	// Cannot be set with any other selector except NamespaceSelector.
+	// +optional
+	ExternalEntitySelector *metav1.LabelSelector `json:"externalEntitySelector,omitempty"`
+	// IPBlocks describes the IPAddresses/IPBlocks that are members of this
+	// group. A group with IPBlocks can only be used in the To/From fields of
+	// a policy rule.
+	// Cannot be set with any selector or ServiceReference.
+	// +optional
+	IPBlocks []secv1alpha1.IPBlock `json:"ipBlocks,omitempty"`
+	// ServiceReference selects the Pods backing the referred Service, i.e.
+	// the Pods matched by the selector of the Service.
+	// Cannot be set with any selector or IPBlocks.
+	// +optional
+	ServiceReference *ServiceReference `json:"serviceReference,omitempty"`
+}
+
+// ServiceReference represents a reference to a Service.
+type ServiceReference struct {
+	// Name of the Service.
+	Name string `json:"name"`
+	// Namespace of the Service.
+	Namespace string `json:"namespace"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterGroupList is a list of ClusterGroup objects.
+type ClusterGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterGroup `json:"items"`
+}
+
+// ServiceExternalIPPoolAnnotationKey is the key of the Service annotation that
+// specifies the ExternalIPPool from which the IP of a LoadBalancer Service is
+// allocated.
+const ServiceExternalIPPoolAnnotationKey = "service.antrea.tanzu.vmware.com/external-ip-pool"
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalIPPool defines one or multiple IP sets in the external network, from
+// which the IPs of LoadBalancer Services are allocated. An allocated IP is
+// announced by one of the Nodes selected by the pool.
+type ExternalIPPool struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the ExternalIPPool.
+	Spec ExternalIPPoolSpec `json:"spec"`
+
+	// The current status of the ExternalIPPool.
+	Status ExternalIPPoolStatus `json:"status"`
+}
+
+// ExternalIPPoolSpec defines the IP ranges of an ExternalIPPool and the Nodes
+// which can announce the IPs allocated from it.
+type ExternalIPPoolSpec struct {
+	// The IP ranges of this IP pool, e.g. 10.10.0.0/24, 10.10.10.2-10.10.10.20.
+	IPRanges []IPRange `json:"ipRanges"`
+	// The Nodes that the allocated IPs can be announced by. If empty, it
+	// means all Nodes.
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+}
+
+// IPRange is a set of contiguous IP addresses, represented by a CIDR or a pair
+// of start and end IPs. Exactly one of CIDR and the pair must be set.
+type IPRange struct {
+	// The CIDR of this range, e.g. 10.10.0.0/24.
+	CIDR string `json:"cidr,omitempty"`
+	// The start IP of this range, e.g. 10.10.10.2.
+	Start string `json:"start,omitempty"`
+	// The end IP of this range, included in the range, e.g. 10.10.10.20.
+	End string `json:"end,omitempty"`
+}
+
+// ExternalIPPoolStatus is the current status of an ExternalIPPool.
+type ExternalIPPoolStatus struct {
+	Usage ExternalIPPoolUsage `json:"usage,omitempty"`
+}
+
+// ExternalIPPoolUsage is the usage of the IPs of an ExternalIPPool.
+type ExternalIPPoolUsage struct {
+	// Total number of IPs.
+	Total int `json:"total"`
+	// Number of allocated IPs.
+	Used int `json:"used"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalIPPoolList is a list of ExternalIPPool objects.
+type ExternalIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ExternalIPPool `json:"items"`
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GatewayNodeLabelKey is the label key of the Nodes which can be selected
+	// as the gateway of the cluster for the multicluster traffic. The value
+	// of the label must be "true".
+	GatewayNodeLabelKey = "multicluster.antrea.tanzu.vmware.com/gateway"
+	// ServiceImportLabelKey is the label key of the Services and Endpoints
+	// derived from ServiceImports in the importing clusters. The value of
+	// the label is the name of the ServiceImport.
+	ServiceImportLabelKey = "multicluster.antrea.tanzu.vmware.com/service-import"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceExport declares that the Service with the same Namespace and name
+// should be exported to the other clusters of the ClusterSet.
+type ServiceExport struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Most recently observed status of the ServiceExport.
+	Status ServiceExportStatus `json:"status,omitempty"`
+}
+
+type ServiceExportConditionType string
+
+const (
+	// ServiceExportExported means that the endpoints of the Service have
+	// been exported to the leader cluster.
+	ServiceExportExported ServiceExportConditionType = "Exported"
+)
+
+// ServiceExportCondition describes the state of a ServiceExport at a point in
+// time.
+type ServiceExportCondition struct {
+	Type   ServiceExportConditionType `json:"type"`
+	Status corev1.ConditionStatus     `json:"status"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ServiceExportStatus contains the current status of a ServiceExport.
+type ServiceExportStatus struct {
+	// +optional
+	Conditions []ServiceExportCondition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceExportList is a list of ServiceExport objects.
+type ServiceExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ServiceExport `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceImport collects the endpoints of an exported Service from all the
+// exporting clusters. ServiceImports are maintained by the member clusters in
+// the leader cluster, and are named "<Service Namespace>.<Service name>".
+type ServiceImport struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the imported Service.
+	Spec ServiceImportSpec `json:"spec"`
+}
+
+// ServiceImportSpec defines the ports and the endpoints of an imported Service.
+type ServiceImportSpec struct {
+	// Ports of the Service, as exported by the last updating cluster.
+	Ports []ServicePort `json:"ports,omitempty"`
+	// Clusters lists the endpoints of the Service in each exporting cluster.
+	Clusters []ClusterEndpoints `json:"clusters,omitempty"`
+}
+
+// ServicePort describes a port of an imported Service.
+type ServicePort struct {
+	// The name of the port, which must match the name of the EndpointPorts.
+	// +optional
+	Name     string          `json:"name,omitempty"`
+	Protocol corev1.Protocol `json:"protocol"`
+	Port     int32           `json:"port"`
+}
+
+// ClusterEndpoints contains the endpoints of an exported Service in a cluster.
+type ClusterEndpoints struct {
+	// ClusterID is the ID of the exporting cluster.
+	ClusterID string `json:"clusterID"`
+	// Subsets are the ready endpoints of the Service in the cluster.
+	Subsets []corev1.EndpointSubset `json:"subsets,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceImportList is a list of ServiceImport objects.
+type ServiceImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ServiceImport `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterInfo describes how the Pods of a member cluster can be reached from
+// the other clusters. Each member cluster publishes its ClusterInfo, named
+// after the cluster ID, to the leader cluster, and the ClusterInfos of the
+// remote clusters are copied to the Antrea Namespace of each member cluster.
+type ClusterInfo struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterInfoSpec `json:"spec"`
+}
+
+// ClusterInfoSpec defines the gateway and the Pod CIDRs of a cluster.
+type ClusterInfoSpec struct {
+	// ClusterID is the ID of the cluster.
+	ClusterID string `json:"clusterID"`
+	// GatewayIP is the transport IP of the gateway Node of the cluster,
+	// which the tunnels from the other clusters are connected to. It's empty
+	// if no gateway Node is available.
+	// +optional
+	GatewayIP string `json:"gatewayIP,omitempty"`
+	// PodCIDRs are the Pod CIDRs of all the Nodes of the cluster.
+	// +optional
+	PodCIDRs []string `json:"podCIDRs,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterInfoList is a list of ClusterInfo objects.
+type ClusterInfoList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterInfo `json:"items"`
+}
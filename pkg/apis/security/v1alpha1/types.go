@@ -21,7 +21,6 @@ import (
 )
 
 // +genclient
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type NetworkPolicy struct {
@@ -31,6 +30,8 @@ type NetworkPolicy struct {
 
 	// Specification of the desired behavior of NetworkPolicy.
 	Spec NetworkPolicySpec `json:"spec"`
+	// Most recently observed status of the NetworkPolicy.
+	Status NetworkPolicyStatus `json:"status"`
 }
 
 // NetworkPolicySpec defines the desired state for NetworkPolicy.
@@ -77,6 +78,9 @@ type Rule struct {
 	// destinations.
 	// +optional
 	To []NetworkPolicyPeer `json:"to"`
+	// EnableLogging is used to indicate if agent should generate logs
+	// when rules are matched. Should be default to false.
+	EnableLogging bool `json:"enableLogging"`
 }
 
 // NetworkPolicyPeer describes the grouping selector of workloads.
@@ -105,6 +109,55 @@ type NetworkPolicyPeer struct {
 	// NamespaceSelector.
 	// Cannot be set with any other selector except NamespaceSelector.
 	ExternalEntitySelector *metav1.LabelSelector `json:"externalEntitySelector,omitempty"`
+	// Group is the name of the ClusterGroup which can be set as an
+	// AppliedTo or within an Ingress or Egress rule in place of
+	// a stand-alone selector. A Group is only supported in Antrea
+	// ClusterNetworkPolicies.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// FQDN matches the IP addresses which the given fully qualified domain
+	// name resolves to. It can be an exact name like "www.example.com", or
+	// a wildcard name like "*.example.com" which matches all the names ending
+	// with ".example.com". FQDN is only supported in the To field of Egress
+	// rules of Antrea ClusterNetworkPolicies.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	FQDN string `json:"fqdn,omitempty"`
+	// Select all Pods which run with the given ServiceAccount as workloads
+	// in AppliedTo/To/From fields. The Namespace of the ServiceAccount can
+	// be omitted in an Antrea NetworkPolicy, in which case it defaults to
+	// the NetworkPolicy's Namespace, and it must be the NetworkPolicy's
+	// Namespace if set.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	ServiceAccount *NamespacedName `json:"serviceAccount,omitempty"`
+	// Select Pods or ExternalEntities from Namespaces relative to the
+	// workloads selected by AppliedTo, in To/From fields of Antrea
+	// ClusterNetworkPolicy rules. If set with PodSelector or
+	// ExternalEntitySelector, the workloads are matched from these
+	// Namespaces only.
+	// Cannot be set with NamespaceSelector, IPBlock, Group, FQDN or
+	// ServiceAccount.
+	// +optional
+	Namespaces *PeerNamespaces `json:"namespaces,omitempty"`
+}
+
+// NamespaceMatchType describes how the Namespaces of a NetworkPolicyPeer are
+// matched.
+type NamespaceMatchType string
+
+const (
+	// NamespaceMatchSelf matches the Namespace of the workloads selected by
+	// AppliedTo, i.e. the rule only matches traffic within each Namespace.
+	NamespaceMatchSelf NamespaceMatchType = "Self"
+)
+
+// PeerNamespaces describes the Namespaces matched by a NetworkPolicyPeer.
+type PeerNamespaces struct {
+	// Match selects the Namespaces relative to the workloads selected by
+	// AppliedTo. Only "Self" is supported for now.
+	Match NamespaceMatchType `json:"match,omitempty"`
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24") that is allowed
@@ -115,20 +168,52 @@ type IPBlock struct {
 	CIDR string `json:"cidr"`
 }
 
+// NamespacedName refers to a Namespace scoped resource.
+type NamespacedName struct {
+	// Name of the resource.
+	Name string `json:"name"`
+	// Namespace of the resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // NetworkPolicyPort describes the port and protocol to match in a rule.
 type NetworkPolicyPort struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match.
+	// The protocol (TCP, UDP, SCTP, ICMP or ICMPv6) which traffic must match.
 	// If not specified, this field defaults to TCP.
 	// +optional
 	Protocol *v1.Protocol `json:"protocol"`
 	// The port on the given protocol. This can either be a numerical
 	// or named port on a Pod. If this field is not provided, this
 	// matches all port names and numbers.
-	// TODO: extend it to include Port Range.
 	// +optional
 	Port *intstr.IntOrString `json:"port"`
+	// EndPort defines the end of the port range, inclusive. It can only be
+	// specified when a numerical `port` is specified, and must be greater
+	// than or equal to `port`.
+	// +optional
+	EndPort *int32 `json:"endPort,omitempty"`
+	// ICMPType is the type of the ICMP or ICMPv6 messages which traffic
+	// must match. It can only be specified when the protocol is ICMP or
+	// ICMPv6. If not specified, this matches all ICMP types.
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty"`
+	// ICMPCode is the code of the ICMP or ICMPv6 messages which traffic
+	// must match. It can only be specified along with `icmpType`. If not
+	// specified, this matches all ICMP codes of the given type.
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty"`
 }
 
+const (
+	// ProtocolICMP is the ICMP protocol, which can be used in
+	// NetworkPolicyPort in addition to the protocols defined by v1.Protocol.
+	ProtocolICMP v1.Protocol = "ICMP"
+	// ProtocolICMPv6 is the ICMPv6 protocol, which can be used in
+	// NetworkPolicyPort in addition to the protocols defined by v1.Protocol.
+	ProtocolICMPv6 v1.Protocol = "ICMPv6"
+)
+
 // RuleAction describes the action to be applied on traffic matching a rule.
 type RuleAction string
 
@@ -137,8 +222,45 @@ const (
 	RuleActionAllow RuleAction = "Allow"
 	// RuleActionDrop describes that rule matching traffic must be dropped.
 	RuleActionDrop RuleAction = "Drop"
+	// RuleActionReject describes that rule matching traffic must be rejected,
+	// i.e. dropped with a TCP RST or an ICMP unreachable response sent back to
+	// the source.
+	RuleActionReject RuleAction = "Reject"
 )
 
+// NetworkPolicyPhase defines the phase in which a NetworkPolicy is.
+type NetworkPolicyPhase string
+
+// These are the valid values for NetworkPolicyPhase.
+const (
+	// NetworkPolicyPending means the NetworkPolicy has been accepted by the
+	// system, but it doesn't span any Node yet, i.e. its appliedTo selects
+	// no Pods.
+	NetworkPolicyPending NetworkPolicyPhase = "Pending"
+	// NetworkPolicyRealizing means the NetworkPolicy has been accepted by the
+	// system, but it has not been realized on all the Nodes it spans yet.
+	NetworkPolicyRealizing NetworkPolicyPhase = "Realizing"
+	// NetworkPolicyRealized means the NetworkPolicy has been enforced on all
+	// the Nodes it spans.
+	NetworkPolicyRealized NetworkPolicyPhase = "Realized"
+	// NetworkPolicyFailed means the NetworkPolicy has failed to be realized on
+	// at least one of the Nodes it spans.
+	NetworkPolicyFailed NetworkPolicyPhase = "Failed"
+)
+
+// NetworkPolicyStatus represents information about the status of a NetworkPolicy.
+type NetworkPolicyStatus struct {
+	// The phase of a NetworkPolicy is a simple, high-level summary of the
+	// NetworkPolicy's status.
+	Phase NetworkPolicyPhase `json:"phase"`
+	// The generation observed by Antrea.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// The number of Nodes that have realized the NetworkPolicy.
+	CurrentNodesRealized int32 `json:"currentNodesRealized"`
+	// The total number of Nodes that should realize the NetworkPolicy.
+	DesiredNodesRealized int32 `json:"desiredNodesRealized"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type NetworkPolicyList struct {
@@ -151,7 +273,6 @@ type NetworkPolicyList struct {
 
 // +genclient
 // +genclient:nonNamespaced
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type ClusterNetworkPolicy struct {
@@ -161,6 +282,8 @@ type ClusterNetworkPolicy struct {
 
 	// Specification of the desired behavior of ClusterNetworkPolicy.
 	Spec ClusterNetworkPolicySpec `json:"spec"`
+	// Most recently observed status of the ClusterNetworkPolicy.
+	Status NetworkPolicyStatus `json:"status"`
 }
 
 // ClusterNetworkPolicySpec defines the desired state for ClusterNetworkPolicy.
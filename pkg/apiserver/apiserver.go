@@ -40,9 +40,11 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/endpoint"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/loglevel"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/webhook"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/controlplane/networkpolicystatus"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/controlplane/nodestatssummary"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/networkpolicy/addressgroup"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/networkpolicy/appliedtogroup"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/networkpolicy/clustergroupmember"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/networkpolicy/networkpolicy"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/stats/antreaclusternetworkpolicystats"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/stats/antreanetworkpolicystats"
@@ -85,6 +87,7 @@ type ExtraConfig struct {
 	networkPolicyController *controllernetworkpolicy.NetworkPolicyController
 	caCertController        *certificate.CACertController
 	statsAggregator         *stats.Aggregator
+	npStatusController      *controllernetworkpolicy.StatusController
 }
 
 // Config defines the config for Antrea apiserver.
@@ -121,7 +124,8 @@ func NewConfig(
 	statsAggregator *stats.Aggregator,
 	controllerQuerier querier.ControllerQuerier,
 	endpointQuerier controllernetworkpolicy.EndpointQuerier,
-	npController *controllernetworkpolicy.NetworkPolicyController) *Config {
+	npController *controllernetworkpolicy.NetworkPolicyController,
+	npStatusController *controllernetworkpolicy.StatusController) *Config {
 	return &Config{
 		genericConfig: genericConfig,
 		extraConfig: ExtraConfig{
@@ -133,6 +137,7 @@ func NewConfig(
 			controllerQuerier:       controllerQuerier,
 			endpointQuerier:         endpointQuerier,
 			networkPolicyController: npController,
+			npStatusController:      npStatusController,
 		},
 	}
 }
@@ -151,6 +156,10 @@ func installAPIGroup(s *APIServer, c completedConfig) error {
 	cpStorage["appliedtogroups"] = appliedToGroupStorage
 	cpStorage["networkpolicies"] = networkPolicyStorage
 	cpStorage["nodestatssummaries"] = nodestatssummary.NewREST(c.extraConfig.statsAggregator)
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		cpStorage["clustergroupmembers"] = clustergroupmember.NewREST(c.extraConfig.networkPolicyController)
+		cpStorage["networkpolicystatuses"] = networkpolicystatus.NewREST(c.extraConfig.npStatusController)
+	}
 	cpGroup.VersionedResourcesStorageMap["v1beta1"] = cpStorage
 
 	// TODO: networkingGroup is the legacy group of controlplane NetworkPolicy APIs. To allow live upgrades from up to
@@ -235,6 +244,7 @@ func installHandlers(c *ExtraConfig, s *genericapiserver.GenericAPIServer) {
 		s.Handler.NonGoRestfulMux.HandleFunc("/validate/tier", webhook.HandleValidationNetworkPolicy(v))
 		s.Handler.NonGoRestfulMux.HandleFunc("/validate/acnp", webhook.HandleValidationNetworkPolicy(v))
 		s.Handler.NonGoRestfulMux.HandleFunc("/validate/anp", webhook.HandleValidationNetworkPolicy(v))
+		s.Handler.NonGoRestfulMux.HandleFunc("/validate/acg", webhook.HandleValidationNetworkPolicy(v))
 		// Install a post start hook to initialize Tiers on start-up
 		s.AddPostStartHook("initialize-tiers", func(context genericapiserver.PostStartHookContext) error {
 			go c.networkPolicyController.InitializeTiers()
@@ -0,0 +1,69 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustergroupmember
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+)
+
+// groupMembershipQuerier is the interface required by the handler.
+type groupMembershipQuerier interface {
+	GetClusterGroupMembers(name string) (controlplane.GroupMemberSet, []controlplane.IPBlock, error)
+}
+
+// REST implements rest.Storage for ClusterGroupMembers.
+type REST struct {
+	querier groupMembershipQuerier
+}
+
+var (
+	_ rest.Scoper = &REST{}
+	_ rest.Getter = &REST{}
+)
+
+// NewREST returns a REST object that will work against API services.
+func NewREST(querier groupMembershipQuerier) *REST {
+	return &REST{querier}
+}
+
+func (r *REST) New() runtime.Object {
+	return &controlplane.ClusterGroupMembers{}
+}
+
+func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	groupMembers, ipBlocks, err := r.querier.GetClusterGroupMembers(name)
+	if err != nil {
+		return nil, err
+	}
+	memberList := &controlplane.ClusterGroupMembers{}
+	memberList.Name = name
+	for _, member := range groupMembers {
+		memberList.EffectiveMembers = append(memberList.EffectiveMembers, *member)
+	}
+	for _, ipBlock := range ipBlocks {
+		memberList.EffectiveIPBlocks = append(memberList.EffectiveIPBlocks, ipBlock.CIDR)
+	}
+	return memberList, nil
+}
+
+func (r *REST) NamespaceScoped() bool {
+	return false
+}
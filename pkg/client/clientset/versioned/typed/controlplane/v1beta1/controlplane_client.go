@@ -26,7 +26,9 @@ type ControlplaneV1beta1Interface interface {
 	RESTClient() rest.Interface
 	AddressGroupsGetter
 	AppliedToGroupsGetter
+	ClusterGroupMembersesGetter
 	NetworkPoliciesGetter
+	NetworkPolicyStatusesGetter
 	NodeStatsSummariesGetter
 }
 
@@ -43,10 +45,18 @@ func (c *ControlplaneV1beta1Client) AppliedToGroups() AppliedToGroupInterface {
 	return newAppliedToGroups(c)
 }
 
+func (c *ControlplaneV1beta1Client) ClusterGroupMemberses() ClusterGroupMembersInterface {
+	return newClusterGroupMemberses(c)
+}
+
 func (c *ControlplaneV1beta1Client) NetworkPolicies(namespace string) NetworkPolicyInterface {
 	return newNetworkPolicies(c, namespace)
 }
 
+func (c *ControlplaneV1beta1Client) NetworkPolicyStatuses() NetworkPolicyStatusInterface {
+	return newNetworkPolicyStatuses(c)
+}
+
 func (c *ControlplaneV1beta1Client) NodeStatsSummaries() NodeStatsSummaryInterface {
 	return newNodeStatsSummaries(c)
 }
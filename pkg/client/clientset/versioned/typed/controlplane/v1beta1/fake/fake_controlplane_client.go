@@ -34,10 +34,18 @@ func (c *FakeControlplaneV1beta1) AppliedToGroups() v1beta1.AppliedToGroupInterf
 	return &FakeAppliedToGroups{c}
 }
 
+func (c *FakeControlplaneV1beta1) ClusterGroupMemberses() v1beta1.ClusterGroupMembersInterface {
+	return &FakeClusterGroupMemberses{c}
+}
+
 func (c *FakeControlplaneV1beta1) NetworkPolicies(namespace string) v1beta1.NetworkPolicyInterface {
 	return &FakeNetworkPolicies{c, namespace}
 }
 
+func (c *FakeControlplaneV1beta1) NetworkPolicyStatuses() v1beta1.NetworkPolicyStatusInterface {
+	return &FakeNetworkPolicyStatuses{c}
+}
+
 func (c *FakeControlplaneV1beta1) NodeStatsSummaries() v1beta1.NodeStatsSummaryInterface {
 	return &FakeNodeStatsSummaries{c}
 }
@@ -20,6 +20,10 @@ type AddressGroupExpansion interface{}
 
 type AppliedToGroupExpansion interface{}
 
+type ClusterGroupMembersExpansion interface{}
+
 type NetworkPolicyExpansion interface{}
 
+type NetworkPolicyStatusExpansion interface{}
+
 type NodeStatsSummaryExpansion interface{}
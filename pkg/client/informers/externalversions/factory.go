@@ -24,6 +24,7 @@ import (
 	versioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
 	core "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core"
 	internalinterfaces "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/internalinterfaces"
+	multicluster "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/multicluster"
 	ops "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/ops"
 	security "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/security"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -173,6 +174,7 @@ type SharedInformerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 
 	Core() core.Interface
+	Multicluster() multicluster.Interface
 	Ops() ops.Interface
 	Security() security.Interface
 }
@@ -181,6 +183,10 @@ func (f *sharedInformerFactory) Core() core.Interface {
 	return core.New(f, f.namespace, f.tweakListOptions)
 }
 
+func (f *sharedInformerFactory) Multicluster() multicluster.Interface {
+	return multicluster.New(f, f.namespace, f.tweakListOptions)
+}
+
 func (f *sharedInformerFactory) Ops() ops.Interface {
 	return ops.New(f, f.namespace, f.tweakListOptions)
 }
@@ -20,6 +20,8 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	v1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	multiclusterv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
 	opsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/ops/v1alpha1"
 	securityv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -56,6 +58,22 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1alpha1.SchemeGroupVersion.WithResource("externalentities"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().ExternalEntities().Informer()}, nil
 
+		// Group=core.antrea.tanzu.vmware.com, Version=v1alpha2
+	case v1alpha2.SchemeGroupVersion.WithResource("clustergroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha2().ClusterGroups().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("egresses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha2().Egresses().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("externalippools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha2().ExternalIPPools().Informer()}, nil
+
+		// Group=multicluster.antrea.tanzu.vmware.com, Version=v1alpha1
+	case multiclusterv1alpha1.SchemeGroupVersion.WithResource("clusterinfos"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Multicluster().V1alpha1().ClusterInfos().Informer()}, nil
+	case multiclusterv1alpha1.SchemeGroupVersion.WithResource("serviceexports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Multicluster().V1alpha1().ServiceExports().Informer()}, nil
+	case multiclusterv1alpha1.SchemeGroupVersion.WithResource("serviceimports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Multicluster().V1alpha1().ServiceImports().Informer()}, nil
+
 		// Group=ops.antrea.tanzu.vmware.com, Version=v1alpha1
 	case opsv1alpha1.SchemeGroupVersion.WithResource("traceflows"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Ops().V1alpha1().Traceflows().Informer()}, nil
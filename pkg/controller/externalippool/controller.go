@@ -0,0 +1,540 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalippool
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	corev1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	coreinformersv1alpha2 "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha2"
+	corelistersv1alpha2 "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha2"
+)
+
+const (
+	controllerName = "ExternalIPPoolController"
+	// Set resyncPeriod to 0 to disable resyncing.
+	resyncPeriod time.Duration = 0
+	// How long to wait before retrying the processing of a Service or an
+	// ExternalIPPool.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+)
+
+// allocation is an IP allocated to a Service from an ExternalIPPool.
+type allocation struct {
+	poolName string
+	ip       net.IP
+}
+
+// Controller allocates IPs from ExternalIPPools to the LoadBalancer Services
+// which request them with the ServiceExternalIPPoolAnnotationKey annotation,
+// and sets the allocated IPs as the ingress IPs of the Services. It also keeps
+// the usage in the status of the ExternalIPPools up to date.
+type Controller struct {
+	client                     kubernetes.Interface
+	crdClient                  versioned.Interface
+	serviceLister              corelisters.ServiceLister
+	serviceListerSynced        cache.InformerSynced
+	externalIPPoolLister       corelistersv1alpha2.ExternalIPPoolLister
+	externalIPPoolListerSynced cache.InformerSynced
+	serviceQueue               workqueue.RateLimitingInterface
+	externalIPPoolQueue        workqueue.RateLimitingInterface
+
+	// mutex protects ipAllocators and serviceAllocations.
+	mutex sync.Mutex
+	// ipAllocators maps the names of the ExternalIPPools to their IP
+	// allocators.
+	ipAllocators map[string]*ipAllocator
+	// serviceAllocations maps the keys of the Services to the IPs allocated
+	// to them.
+	serviceAllocations map[string]allocation
+}
+
+// NewExternalIPPoolController creates a new Controller which will process
+// Service and ExternalIPPool events.
+func NewExternalIPPoolController(
+	client kubernetes.Interface,
+	crdClient versioned.Interface,
+	serviceInformer coreinformers.ServiceInformer,
+	externalIPPoolInformer coreinformersv1alpha2.ExternalIPPoolInformer) *Controller {
+	c := &Controller{
+		client:                     client,
+		crdClient:                  crdClient,
+		serviceLister:              serviceInformer.Lister(),
+		serviceListerSynced:        serviceInformer.Informer().HasSynced,
+		externalIPPoolLister:       externalIPPoolInformer.Lister(),
+		externalIPPoolListerSynced: externalIPPoolInformer.Informer().HasSynced,
+		serviceQueue:               workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "externalIPPoolService"),
+		externalIPPoolQueue:        workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "externalIPPool"),
+		ipAllocators:               map[string]*ipAllocator{},
+		serviceAllocations:         map[string]allocation{},
+	}
+	serviceInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addService,
+			UpdateFunc: c.updateService,
+			DeleteFunc: c.deleteService,
+		},
+		resyncPeriod,
+	)
+	externalIPPoolInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addExternalIPPool,
+			UpdateFunc: c.updateExternalIPPool,
+			DeleteFunc: c.deleteExternalIPPool,
+		},
+		resyncPeriod,
+	)
+	return c
+}
+
+// requestsExternalIP returns whether the Service is annotated to request an IP
+// from an ExternalIPPool.
+func requestsExternalIP(svc *corev1.Service) bool {
+	_, exists := svc.Annotations[corev1alpha2.ServiceExternalIPPoolAnnotationKey]
+	return exists
+}
+
+// requestedPool returns the name of the ExternalIPPool from which an IP should
+// be allocated to the Service, or an empty string if the Service doesn't need
+// one.
+func requestedPool(svc *corev1.Service) string {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return ""
+	}
+	return svc.Annotations[corev1alpha2.ServiceExternalIPPoolAnnotationKey]
+}
+
+func (c *Controller) enqueueService(svc *corev1.Service) {
+	key, err := cache.MetaNamespaceKeyFunc(svc)
+	if err != nil {
+		klog.Errorf("Failed to get key of Service %s/%s: %v", svc.Namespace, svc.Name, err)
+		return
+	}
+	c.serviceQueue.Add(key)
+}
+
+func (c *Controller) addService(obj interface{}) {
+	svc := obj.(*corev1.Service)
+	if !requestsExternalIP(svc) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s ADD event", svc.Namespace, svc.Name)
+	c.enqueueService(svc)
+}
+
+func (c *Controller) updateService(old, cur interface{}) {
+	oldSvc := old.(*corev1.Service)
+	curSvc := cur.(*corev1.Service)
+	// The Service is processed if the annotation is removed as well, to
+	// release the allocated IP.
+	if !requestsExternalIP(oldSvc) && !requestsExternalIP(curSvc) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s UPDATE event", curSvc.Namespace, curSvc.Name)
+	c.enqueueService(curSvc)
+}
+
+func (c *Controller) deleteService(old interface{}) {
+	svc, ok := old.(*corev1.Service)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Service, invalid type: %v", old)
+			return
+		}
+		svc, ok = tombstone.Obj.(*corev1.Service)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Service, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	if !requestsExternalIP(svc) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s DELETE event", svc.Namespace, svc.Name)
+	c.enqueueService(svc)
+}
+
+func (c *Controller) addExternalIPPool(obj interface{}) {
+	pool := obj.(*corev1alpha2.ExternalIPPool)
+	klog.V(2).Infof("Processing ExternalIPPool %s ADD event", pool.Name)
+	c.externalIPPoolQueue.Add(pool.Name)
+}
+
+func (c *Controller) updateExternalIPPool(old, cur interface{}) {
+	oldPool := old.(*corev1alpha2.ExternalIPPool)
+	curPool := cur.(*corev1alpha2.ExternalIPPool)
+	// Ignore the updates of the status, which is maintained by this
+	// controller.
+	if apiequality.Semantic.DeepEqual(oldPool.Spec, curPool.Spec) {
+		return
+	}
+	klog.V(2).Infof("Processing ExternalIPPool %s UPDATE event", curPool.Name)
+	c.externalIPPoolQueue.Add(curPool.Name)
+}
+
+func (c *Controller) deleteExternalIPPool(old interface{}) {
+	pool, ok := old.(*corev1alpha2.ExternalIPPool)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting ExternalIPPool, invalid type: %v", old)
+			return
+		}
+		pool, ok = tombstone.Obj.(*corev1alpha2.ExternalIPPool)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting ExternalIPPool, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing ExternalIPPool %s DELETE event", pool.Name)
+	c.externalIPPoolQueue.Add(pool.Name)
+}
+
+// Run will create a worker for each of the Service queue and the
+// ExternalIPPool queue, after restoring the allocated IPs from the ingress IPs
+// of the existing Services.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.serviceQueue.ShutDown()
+	defer c.externalIPPoolQueue.ShutDown()
+
+	klog.Infof("Starting %s", controllerName)
+	defer klog.Infof("Shutting down %s", controllerName)
+
+	klog.Infof("Waiting for caches to sync for %s", controllerName)
+	if !cache.WaitForCacheSync(stopCh, c.serviceListerSynced, c.externalIPPoolListerSynced) {
+		klog.Errorf("Unable to sync caches for %s", controllerName)
+		return
+	}
+	klog.Infof("Caches are synced for %s", controllerName)
+
+	if err := c.restoreAllocations(); err != nil {
+		klog.Errorf("Failed to restore the allocated IPs of ExternalIPPools: %v", err)
+		return
+	}
+
+	go wait.Until(c.serviceWorker, time.Second, stopCh)
+	go wait.Until(c.externalIPPoolWorker, time.Second, stopCh)
+	<-stopCh
+}
+
+// restoreAllocations builds the IP allocators of the existing ExternalIPPools,
+// and marks the ingress IPs of the existing Services which are in the
+// ExternalIPPools requested by them as allocated, so that the IPs of the
+// Services are kept across restarts.
+func (c *Controller) restoreAllocations() error {
+	pools, err := c.externalIPPoolLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	services, err := c.serviceLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, pool := range pools {
+		allocator, err := newIPAllocator(pool.Spec.IPRanges)
+		if err != nil {
+			klog.Errorf("Invalid IP ranges of ExternalIPPool %s: %v", pool.Name, err)
+			continue
+		}
+		c.ipAllocators[pool.Name] = allocator
+	}
+	for _, svc := range services {
+		poolName := requestedPool(svc)
+		allocator, exists := c.ipAllocators[poolName]
+		if !exists {
+			continue
+		}
+		key, _ := cache.MetaNamespaceKeyFunc(svc)
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			ip := net.ParseIP(ingress.IP)
+			if ip == nil || allocator.allocate(ip) != nil {
+				continue
+			}
+			c.serviceAllocations[key] = allocation{poolName: poolName, ip: ip}
+			break
+		}
+	}
+	return nil
+}
+
+func (c *Controller) serviceWorker() {
+	for c.processNextWorkItem(c.serviceQueue, c.syncService) {
+	}
+}
+
+func (c *Controller) externalIPPoolWorker() {
+	for c.processNextWorkItem(c.externalIPPoolQueue, c.syncExternalIPPool) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(queue workqueue.RateLimitingInterface, syncFunc func(string) error) bool {
+	obj, quit := queue.Get()
+	if quit {
+		return false
+	}
+	defer queue.Done(obj)
+
+	// We expect strings (Service key or ExternalIPPool name) to come off the
+	// workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd
+		// go into a loop of attempting to process a work item that is invalid.
+		queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := syncFunc(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again until
+		// another change happens.
+		queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		queue.AddRateLimited(key)
+		klog.Errorf("Error syncing %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+func (c *Controller) syncService(key string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing Service %s. (%v)", key, time.Since(startTime))
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	svc, err := c.serviceLister.Services(namespace).Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		c.mutex.Lock()
+		c.releaseServiceIP(key)
+		c.mutex.Unlock()
+		return nil
+	}
+
+	c.mutex.Lock()
+	oldIP := c.serviceAllocations[key].ip
+	ip, err := c.allocateServiceIP(key, svc)
+	// The ingress IPs of the Services which request IPs from ExternalIPPools
+	// are owned by this controller.
+	var ingress []corev1.LoadBalancerIngress
+	if ip != nil {
+		ingress = []corev1.LoadBalancerIngress{{IP: ip.String()}}
+	} else if !requestsExternalIP(svc) {
+		// The Service doesn't request an IP anymore, only remove the
+		// IPs which were allocated to it.
+		for _, i := range svc.Status.LoadBalancer.Ingress {
+			if ingressIP := net.ParseIP(i.IP); !ingressIP.Equal(oldIP) && !c.isExternalIP(ingressIP) {
+				ingress = append(ingress, i)
+			}
+		}
+	}
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(svc.Status.LoadBalancer.Ingress, ingress) {
+		return nil
+	}
+	svcCopy := svc.DeepCopy()
+	svcCopy.Status.LoadBalancer.Ingress = ingress
+	if _, err := c.client.CoreV1().Services(namespace).UpdateStatus(context.TODO(), svcCopy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.Infof("Updated ingress IPs of Service %s to %v", key, ingress)
+	return nil
+}
+
+// allocateServiceIP returns the IP allocated to the Service from the
+// ExternalIPPool it requests, allocating one if it doesn't have it yet. The IP
+// allocated from a different ExternalIPPool is released. It returns nil if the
+// Service doesn't request an IP or the IP can't be allocated. The caller must
+// hold the mutex.
+func (c *Controller) allocateServiceIP(key string, svc *corev1.Service) (net.IP, error) {
+	poolName := requestedPool(svc)
+	if cur, exists := c.serviceAllocations[key]; exists {
+		if cur.poolName == poolName {
+			return cur.ip, nil
+		}
+		c.releaseServiceIP(key)
+	}
+	if poolName == "" {
+		return nil, nil
+	}
+	allocator, exists := c.ipAllocators[poolName]
+	if !exists {
+		// The Service will be processed again when the ExternalIPPool
+		// is created.
+		klog.Warningf("ExternalIPPool %s requested by Service %s is not found or invalid", poolName, key)
+		return nil, nil
+	}
+	var ip net.IP
+	if svc.Spec.LoadBalancerIP != "" {
+		ip = net.ParseIP(svc.Spec.LoadBalancerIP)
+		if ip == nil || !allocator.contains(ip) {
+			// Retrying doesn't help until the Service or the
+			// ExternalIPPool is updated.
+			klog.Errorf("LoadBalancerIP %s of Service %s is not in ExternalIPPool %s", svc.Spec.LoadBalancerIP, key, poolName)
+			return nil, nil
+		}
+		// The IP might be released by another Service later.
+		if err := allocator.allocate(ip); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if ip, err = allocator.allocateNext(); err != nil {
+			return nil, err
+		}
+	}
+	c.serviceAllocations[key] = allocation{poolName: poolName, ip: ip}
+	c.externalIPPoolQueue.Add(poolName)
+	klog.Infof("Allocated IP %s from ExternalIPPool %s to Service %s", ip, poolName, key)
+	return ip, nil
+}
+
+// releaseServiceIP releases the IP allocated to the Service if any. The caller
+// must hold the mutex.
+func (c *Controller) releaseServiceIP(key string) {
+	cur, exists := c.serviceAllocations[key]
+	if !exists {
+		return
+	}
+	if allocator, exists := c.ipAllocators[cur.poolName]; exists {
+		allocator.release(cur.ip)
+	}
+	delete(c.serviceAllocations, key)
+	c.externalIPPoolQueue.Add(cur.poolName)
+	klog.Infof("Released IP %s of ExternalIPPool %s from Service %s", cur.ip, cur.poolName, key)
+}
+
+// isExternalIP returns whether the IP is in any ExternalIPPool. The caller must
+// hold the mutex.
+func (c *Controller) isExternalIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, allocator := range c.ipAllocators {
+		if allocator.contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) syncExternalIPPool(name string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing ExternalIPPool %s. (%v)", name, time.Since(startTime))
+	}()
+
+	pool, err := c.externalIPPoolLister.Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		pool = nil
+	}
+
+	c.mutex.Lock()
+	var allocator *ipAllocator
+	if pool != nil {
+		allocator, err = newIPAllocator(pool.Spec.IPRanges)
+		if err != nil {
+			// Retrying doesn't help, the ExternalIPPool is handled as
+			// if it doesn't exist.
+			klog.Errorf("Invalid IP ranges of ExternalIPPool %s: %v", name, err)
+			allocator = nil
+		}
+	}
+	oldAllocator, exists := c.ipAllocators[name]
+	if allocator != nil && exists && oldAllocator.hasSameRanges(allocator) {
+		allocator = oldAllocator
+	} else {
+		// Keep the allocated IPs which are still in the IP ranges, and
+		// re-allocate IPs to the other Services.
+		for key, cur := range c.serviceAllocations {
+			if cur.poolName != name {
+				continue
+			}
+			if allocator != nil && allocator.allocate(cur.ip) == nil {
+				continue
+			}
+			delete(c.serviceAllocations, key)
+			c.serviceQueue.Add(key)
+		}
+		if allocator == nil {
+			delete(c.ipAllocators, name)
+		} else {
+			c.ipAllocators[name] = allocator
+			// The Services which failed to get IPs might get them
+			// now.
+			c.enqueueServicesForPool(name)
+		}
+	}
+	var usage corev1alpha2.ExternalIPPoolUsage
+	if allocator != nil {
+		usage = corev1alpha2.ExternalIPPoolUsage{Total: allocator.total(), Used: allocator.used()}
+	}
+	c.mutex.Unlock()
+
+	if pool == nil || pool.Status.Usage == usage {
+		return nil
+	}
+	poolCopy := pool.DeepCopy()
+	poolCopy.Status.Usage = usage
+	_, err = c.crdClient.CoreV1alpha2().ExternalIPPools().UpdateStatus(context.TODO(), poolCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// enqueueServicesForPool adds the Services which request IPs from the
+// ExternalIPPool to the Service queue.
+func (c *Controller) enqueueServicesForPool(name string) {
+	services, err := c.serviceLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Services: %v", err)
+		return
+	}
+	for _, svc := range services {
+		if requestedPool(svc) == name {
+			c.enqueueService(svc)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalippool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	corev1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+type externalIPPoolController struct {
+	*Controller
+	client             kubernetes.Interface
+	crdClient          versioned.Interface
+	informerFactory    informers.SharedInformerFactory
+	crdInformerFactory crdinformers.SharedInformerFactory
+}
+
+func newController(objects []runtime.Object, crdObjects []runtime.Object) *externalIPPoolController {
+	client := fake.NewSimpleClientset(objects...)
+	crdClient := fakeversioned.NewSimpleClientset(crdObjects...)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	controller := NewExternalIPPoolController(client, crdClient,
+		informerFactory.Core().V1().Services(),
+		crdInformerFactory.Core().V1alpha2().ExternalIPPools())
+	return &externalIPPoolController{
+		controller,
+		client,
+		crdClient,
+		informerFactory,
+		crdInformerFactory,
+	}
+}
+
+func newExternalIPPool(name string, ipRanges ...corev1alpha2.IPRange) *corev1alpha2.ExternalIPPool {
+	return &corev1alpha2.ExternalIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1alpha2.ExternalIPPoolSpec{IPRanges: ipRanges},
+	}
+}
+
+func newService(name, poolName, loadBalancerIP string, ingressIPs ...string) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        name,
+			Annotations: map[string]string{corev1alpha2.ServiceExternalIPPoolAnnotationKey: poolName},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:           corev1.ServiceTypeLoadBalancer,
+			LoadBalancerIP: loadBalancerIP,
+		},
+	}
+	for _, ip := range ingressIPs {
+		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+	}
+	return svc
+}
+
+func (c *externalIPPoolController) waitForServiceIngressIPs(t *testing.T, name string, expectedIPs ...string) {
+	var ingressIPs []string
+	err := wait.PollImmediate(50*time.Millisecond, 2*time.Second, func() (bool, error) {
+		svc, err := c.client.CoreV1().Services("default").Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		ingressIPs = nil
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			ingressIPs = append(ingressIPs, ingress.IP)
+		}
+		return assert.ObjectsAreEqual(expectedIPs, ingressIPs), nil
+	})
+	assert.NoError(t, err, "Ingress IPs of Service %s are %v, expected %v", name, ingressIPs, expectedIPs)
+}
+
+func (c *externalIPPoolController) waitForPoolUsage(t *testing.T, name string, expectedUsage corev1alpha2.ExternalIPPoolUsage) {
+	var usage corev1alpha2.ExternalIPPoolUsage
+	err := wait.PollImmediate(50*time.Millisecond, 2*time.Second, func() (bool, error) {
+		pool, err := c.crdClient.CoreV1alpha2().ExternalIPPools().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		usage = pool.Status.Usage
+		return usage == expectedUsage, nil
+	})
+	assert.NoError(t, err, "Usage of ExternalIPPool %s is %v, expected %v", name, usage, expectedUsage)
+}
+
+func TestAllocateServiceIPs(t *testing.T) {
+	pool := newExternalIPPool("pool1", corev1alpha2.IPRange{Start: "10.10.10.2", End: "10.10.10.4"})
+	// svc1 keeps its IP which was allocated before the restart.
+	svc1 := newService("svc1", "pool1", "", "10.10.10.3")
+	svc2 := newService("svc2", "pool1", "")
+	svc3 := newService("svc3", "pool1", "10.10.10.4")
+	c := newController([]runtime.Object{svc1, svc2, svc3}, []runtime.Object{pool})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.informerFactory.Start(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	go c.Run(stopCh)
+
+	c.waitForServiceIngressIPs(t, "svc1", "10.10.10.3")
+	c.waitForServiceIngressIPs(t, "svc2", "10.10.10.2")
+	c.waitForServiceIngressIPs(t, "svc3", "10.10.10.4")
+	c.waitForPoolUsage(t, "pool1", corev1alpha2.ExternalIPPoolUsage{Total: 3, Used: 3})
+
+	// The IP is released when the Service is changed to another type.
+	svc2.Spec.Type = corev1.ServiceTypeClusterIP
+	svc2.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.10.10.2"}}
+	_, err := c.client.CoreV1().Services("default").Update(context.TODO(), svc2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	c.waitForServiceIngressIPs(t, "svc2")
+	c.waitForPoolUsage(t, "pool1", corev1alpha2.ExternalIPPoolUsage{Total: 3, Used: 2})
+
+	// The Services whose IPs are not in the new IP ranges get new IPs.
+	pool.Spec.IPRanges = []corev1alpha2.IPRange{{CIDR: "10.10.10.4/30"}}
+	_, err = c.crdClient.CoreV1alpha2().ExternalIPPools().Update(context.TODO(), pool, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	c.waitForServiceIngressIPs(t, "svc1", "10.10.10.5")
+	c.waitForServiceIngressIPs(t, "svc3", "10.10.10.4")
+	c.waitForPoolUsage(t, "pool1", corev1alpha2.ExternalIPPoolUsage{Total: 4, Used: 2})
+
+	// The IPs are released when the ExternalIPPool is deleted.
+	err = c.crdClient.CoreV1alpha2().ExternalIPPools().Delete(context.TODO(), "pool1", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	c.waitForServiceIngressIPs(t, "svc1")
+	c.waitForServiceIngressIPs(t, "svc3")
+}
+
+func TestAllocateServiceIPsExhausted(t *testing.T) {
+	pool := newExternalIPPool("pool1", corev1alpha2.IPRange{CIDR: "10.10.10.2/32"})
+	svc1 := newService("svc1", "pool1", "")
+	c := newController([]runtime.Object{svc1}, []runtime.Object{pool})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.informerFactory.Start(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	go c.Run(stopCh)
+
+	c.waitForServiceIngressIPs(t, "svc1", "10.10.10.2")
+	_, err := c.client.CoreV1().Services("default").Create(context.TODO(), newService("svc2", "pool1", ""), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = c.client.CoreV1().Services("default").Create(context.TODO(), newService("svc3", "pool2", ""), metav1.CreateOptions{})
+	require.NoError(t, err)
+	// No IP is available for svc2, and pool2 doesn't exist.
+	time.Sleep(200 * time.Millisecond)
+	c.waitForServiceIngressIPs(t, "svc2")
+	c.waitForServiceIngressIPs(t, "svc3")
+
+	// svc3 gets an IP once pool2 is created.
+	_, err = c.crdClient.CoreV1alpha2().ExternalIPPools().Create(context.TODO(), newExternalIPPool("pool2", corev1alpha2.IPRange{CIDR: "10.10.20.0/24"}), metav1.CreateOptions{})
+	require.NoError(t, err)
+	c.waitForServiceIngressIPs(t, "svc3", "10.10.20.0")
+	c.waitForPoolUsage(t, "pool2", corev1alpha2.ExternalIPPoolUsage{Total: 256, Used: 1})
+}
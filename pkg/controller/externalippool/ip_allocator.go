@@ -0,0 +1,134 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalippool
+
+import (
+	"fmt"
+	"net"
+
+	corev1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	iputil "github.com/vmware-tanzu/antrea/pkg/util/ip"
+)
+
+// ipRange is a range of contiguous IPv4 addresses, with both ends included.
+type ipRange struct {
+	first uint32
+	last  uint32
+}
+
+// ipAllocator allocates IPv4 addresses from the IP ranges of an
+// ExternalIPPool. It's not thread-safe.
+type ipAllocator struct {
+	ranges    []ipRange
+	allocated map[uint32]bool
+}
+
+func newIPAllocator(ipRanges []corev1alpha2.IPRange) (*ipAllocator, error) {
+	a := &ipAllocator{allocated: map[uint32]bool{}}
+	for _, r := range ipRanges {
+		first, last, err := iputil.ParseIPv4Range(r.CIDR, r.Start, r.End)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range a.ranges {
+			if first <= existing.last && last >= existing.first {
+				return nil, fmt.Errorf("IP range %s-%s overlaps with IP range %s-%s",
+					iputil.Uint32ToIPv4(first), iputil.Uint32ToIPv4(last),
+					iputil.Uint32ToIPv4(existing.first), iputil.Uint32ToIPv4(existing.last))
+			}
+		}
+		a.ranges = append(a.ranges, ipRange{first: first, last: last})
+	}
+	return a, nil
+}
+
+// contains returns whether the IP is in the IP ranges of the allocator.
+func (a *ipAllocator) contains(ip net.IP) bool {
+	if ip.To4() == nil {
+		return false
+	}
+	i := iputil.IPv4ToUint32(ip)
+	for _, r := range a.ranges {
+		if i >= r.first && i <= r.last {
+			return true
+		}
+	}
+	return false
+}
+
+// allocate allocates the specified IP.
+func (a *ipAllocator) allocate(ip net.IP) error {
+	if !a.contains(ip) {
+		return fmt.Errorf("IP %s is not in the IP ranges", ip)
+	}
+	i := iputil.IPv4ToUint32(ip)
+	if a.allocated[i] {
+		return fmt.Errorf("IP %s is already allocated", ip)
+	}
+	a.allocated[i] = true
+	return nil
+}
+
+// allocateNext allocates the first available IP.
+func (a *ipAllocator) allocateNext() (net.IP, error) {
+	for _, r := range a.ranges {
+		for i := r.first; ; i++ {
+			if !a.allocated[i] {
+				a.allocated[i] = true
+				return iputil.Uint32ToIPv4(i), nil
+			}
+			if i == r.last {
+				break
+			}
+		}
+	}
+	return nil, fmt.Errorf("no available IP")
+}
+
+// release releases the specified IP.
+func (a *ipAllocator) release(ip net.IP) {
+	if ip.To4() == nil {
+		return
+	}
+	delete(a.allocated, iputil.IPv4ToUint32(ip))
+}
+
+// total returns the number of IPs in the IP ranges.
+func (a *ipAllocator) total() int {
+	total := 0
+	for _, r := range a.ranges {
+		total += int(r.last-r.first) + 1
+	}
+	return total
+}
+
+// used returns the number of allocated IPs.
+func (a *ipAllocator) used() int {
+	return len(a.allocated)
+}
+
+// hasSameRanges returns whether the IP ranges of the two allocators are the
+// same.
+func (a *ipAllocator) hasSameRanges(b *ipAllocator) bool {
+	if len(a.ranges) != len(b.ranges) {
+		return false
+	}
+	for i := range a.ranges {
+		if a.ranges[i] != b.ranges[i] {
+			return false
+		}
+	}
+	return true
+}
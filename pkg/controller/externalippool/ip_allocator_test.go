@@ -0,0 +1,65 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalippool
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1alpha2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+)
+
+func TestIPAllocator(t *testing.T) {
+	allocator, err := newIPAllocator([]corev1alpha2.IPRange{
+		{CIDR: "10.10.10.0/31"},
+		{Start: "10.10.20.5", End: "10.10.20.5"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, allocator.total())
+	assert.True(t, allocator.contains(net.ParseIP("10.10.20.5")))
+	assert.False(t, allocator.contains(net.ParseIP("10.10.10.2")))
+
+	assert.NoError(t, allocator.allocate(net.ParseIP("10.10.10.1")))
+	assert.Error(t, allocator.allocate(net.ParseIP("10.10.10.1")))
+	assert.Error(t, allocator.allocate(net.ParseIP("10.10.10.2")))
+	ip, err := allocator.allocateNext()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.10.10.0", ip.String())
+	ip, err = allocator.allocateNext()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.10.20.5", ip.String())
+	_, err = allocator.allocateNext()
+	assert.Error(t, err)
+	assert.Equal(t, 3, allocator.used())
+
+	allocator.release(net.ParseIP("10.10.10.1"))
+	assert.Equal(t, 2, allocator.used())
+	ip, err = allocator.allocateNext()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.10.10.1", ip.String())
+}
+
+func TestIPAllocatorInvalidRanges(t *testing.T) {
+	_, err := newIPAllocator([]corev1alpha2.IPRange{
+		{CIDR: "10.10.10.0/24"},
+		{Start: "10.10.10.250", End: "10.10.11.5"},
+	})
+	assert.Error(t, err)
+	_, err = newIPAllocator([]corev1alpha2.IPRange{{Start: "10.10.10.2"}})
+	assert.Error(t, err)
+}
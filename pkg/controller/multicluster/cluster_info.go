@@ -0,0 +1,155 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/multicluster"
+)
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// localClusterInfoSpec computes the ClusterInfoSpec of this cluster from the
+// Nodes. Only IPv4 Pod CIDRs are supported.
+func (c *Controller) localClusterInfoSpec() (*mcv1alpha1.ClusterInfoSpec, error) {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	spec := &mcv1alpha1.ClusterInfoSpec{ClusterID: c.clusterID}
+	if gatewayNode := multicluster.SelectGatewayNode(nodes); gatewayNode != nil {
+		spec.GatewayIP = multicluster.GetGatewayIP(gatewayNode).String()
+	}
+	for _, node := range nodes {
+		podCIDRs := node.Spec.PodCIDRs
+		if len(podCIDRs) == 0 && node.Spec.PodCIDR != "" {
+			podCIDRs = []string{node.Spec.PodCIDR}
+		}
+		for _, podCIDR := range podCIDRs {
+			if _, ipNet, err := net.ParseCIDR(podCIDR); err == nil && ipNet.IP.To4() != nil {
+				spec.PodCIDRs = append(spec.PodCIDRs, ipNet.String())
+			}
+		}
+	}
+	sort.Strings(spec.PodCIDRs)
+	return spec, nil
+}
+
+func (c *Controller) syncClusterInfo(clusterID string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing ClusterInfo %s. (%v)", clusterID, time.Since(startTime))
+	}()
+
+	if clusterID == c.clusterID {
+		spec, err := c.localClusterInfoSpec()
+		if err != nil {
+			return err
+		}
+		return c.publishLocalClusterInfo(spec)
+	}
+
+	leaderInfo, err := c.leaderClusterInfoLister.ClusterInfos(c.leaderNamespace).Get(clusterID)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		leaderInfo = nil
+	}
+	info, err := c.clusterInfoLister.ClusterInfos(c.localNamespace).Get(clusterID)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		info = nil
+	}
+	clusterInfos := c.crdClient.MulticlusterV1alpha1().ClusterInfos(c.localNamespace)
+	switch {
+	case leaderInfo == nil && info == nil:
+		return nil
+	case leaderInfo == nil:
+		if err := clusterInfos.Delete(context.TODO(), clusterID, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.Infof("Deleted ClusterInfo of remote cluster %s", clusterID)
+	case info == nil:
+		info = &mcv1alpha1.ClusterInfo{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterID, Namespace: c.localNamespace},
+			Spec:       leaderInfo.Spec,
+		}
+		if _, err := clusterInfos.Create(context.TODO(), info, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("Created ClusterInfo of remote cluster %s", clusterID)
+	case !apiequality.Semantic.DeepEqual(info.Spec, leaderInfo.Spec):
+		infoCopy := info.DeepCopy()
+		infoCopy.Spec = leaderInfo.Spec
+		if _, err := clusterInfos.Update(context.TODO(), infoCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("Updated ClusterInfo of remote cluster %s", clusterID)
+	}
+	return nil
+}
+
+// publishLocalClusterInfo creates or updates the ClusterInfo of this cluster
+// in the leader cluster.
+func (c *Controller) publishLocalClusterInfo(spec *mcv1alpha1.ClusterInfoSpec) error {
+	clusterInfos := c.leaderCRDClient.MulticlusterV1alpha1().ClusterInfos(c.leaderNamespace)
+	info, err := c.leaderClusterInfoLister.ClusterInfos(c.leaderNamespace).Get(c.clusterID)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		info = &mcv1alpha1.ClusterInfo{
+			ObjectMeta: metav1.ObjectMeta{Name: c.clusterID, Namespace: c.leaderNamespace},
+			Spec:       *spec,
+		}
+		if _, err := clusterInfos.Create(context.TODO(), info, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("Published ClusterInfo %+v to the leader cluster", *spec)
+		return nil
+	}
+	if apiequality.Semantic.DeepEqual(&info.Spec, spec) {
+		return nil
+	}
+	infoCopy := info.DeepCopy()
+	infoCopy.Spec = *spec
+	if _, err := clusterInfos.Update(context.TODO(), infoCopy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.Infof("Published ClusterInfo %+v to the leader cluster", *spec)
+	return nil
+}
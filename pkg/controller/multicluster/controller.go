@@ -0,0 +1,453 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	mcinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/multicluster/v1alpha1"
+	mclisters "github.com/vmware-tanzu/antrea/pkg/client/listers/multicluster/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+const (
+	controllerName = "MulticlusterController"
+	// Set resyncPeriod to 0 to disable resyncing.
+	resyncPeriod time.Duration = 0
+	// How long to wait before retrying the processing of an item.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+)
+
+// Controller implements multicluster Services for a member cluster of a
+// ClusterSet. The ClusterSet shares a leader cluster, which may be one of the
+// members, and the Controller of each member:
+//   - collects the endpoints of the Services exported with ServiceExports, to
+//     the ServiceImports in the leader cluster.
+//   - creates a derived Service and its Endpoints for each ServiceImport in the
+//     leader cluster, so that AntreaProxy load balances the traffic to the
+//     derived Service across the endpoints of all the exporting clusters.
+//   - publishes the gateway Node and the Pod CIDRs of the cluster with its
+//     ClusterInfo in the leader cluster, and copies the ClusterInfos of the
+//     other clusters to the local Namespace, from which the Antrea Agents set up
+//     the tunnels and the routes to the remote clusters.
+type Controller struct {
+	clusterID       string
+	localNamespace  string
+	leaderNamespace string
+
+	client          kubernetes.Interface
+	crdClient       versioned.Interface
+	leaderCRDClient versioned.Interface
+
+	nodeLister                corelisters.NodeLister
+	nodeListerSynced          cache.InformerSynced
+	serviceLister             corelisters.ServiceLister
+	serviceListerSynced       cache.InformerSynced
+	endpointsLister           corelisters.EndpointsLister
+	endpointsListerSynced     cache.InformerSynced
+	serviceExportLister       mclisters.ServiceExportLister
+	serviceExportListerSynced cache.InformerSynced
+	clusterInfoLister         mclisters.ClusterInfoLister
+	clusterInfoListerSynced   cache.InformerSynced
+
+	leaderServiceImportLister       mclisters.ServiceImportLister
+	leaderServiceImportListerSynced cache.InformerSynced
+	leaderClusterInfoLister         mclisters.ClusterInfoLister
+	leaderClusterInfoListerSynced   cache.InformerSynced
+
+	// serviceExportQueue contains the keys of the exported Services.
+	serviceExportQueue workqueue.RateLimitingInterface
+	// serviceImportQueue contains the names of the ServiceImports.
+	serviceImportQueue workqueue.RateLimitingInterface
+	// clusterInfoQueue contains the IDs of the clusters.
+	clusterInfoQueue workqueue.RateLimitingInterface
+}
+
+// NewMulticlusterController creates a new Controller for the member cluster
+// identified by clusterID. The copies of the remote ClusterInfos are kept in
+// localNamespace, and leaderCRDClient must access the leader cluster, in which
+// the ServiceImports and the ClusterInfos are kept in leaderNamespace. The
+// leader informers must be limited to leaderNamespace.
+func NewMulticlusterController(
+	clusterID string,
+	localNamespace string,
+	leaderNamespace string,
+	client kubernetes.Interface,
+	crdClient versioned.Interface,
+	leaderCRDClient versioned.Interface,
+	nodeInformer coreinformers.NodeInformer,
+	serviceInformer coreinformers.ServiceInformer,
+	endpointsInformer coreinformers.EndpointsInformer,
+	serviceExportInformer mcinformers.ServiceExportInformer,
+	clusterInfoInformer mcinformers.ClusterInfoInformer,
+	leaderServiceImportInformer mcinformers.ServiceImportInformer,
+	leaderClusterInfoInformer mcinformers.ClusterInfoInformer) *Controller {
+	c := &Controller{
+		clusterID:                       clusterID,
+		localNamespace:                  localNamespace,
+		leaderNamespace:                 leaderNamespace,
+		client:                          client,
+		crdClient:                       crdClient,
+		leaderCRDClient:                 leaderCRDClient,
+		nodeLister:                      nodeInformer.Lister(),
+		nodeListerSynced:                nodeInformer.Informer().HasSynced,
+		serviceLister:                   serviceInformer.Lister(),
+		serviceListerSynced:             serviceInformer.Informer().HasSynced,
+		endpointsLister:                 endpointsInformer.Lister(),
+		endpointsListerSynced:           endpointsInformer.Informer().HasSynced,
+		serviceExportLister:             serviceExportInformer.Lister(),
+		serviceExportListerSynced:       serviceExportInformer.Informer().HasSynced,
+		clusterInfoLister:               clusterInfoInformer.Lister(),
+		clusterInfoListerSynced:         clusterInfoInformer.Informer().HasSynced,
+		leaderServiceImportLister:       leaderServiceImportInformer.Lister(),
+		leaderServiceImportListerSynced: leaderServiceImportInformer.Informer().HasSynced,
+		leaderClusterInfoLister:         leaderClusterInfoInformer.Lister(),
+		leaderClusterInfoListerSynced:   leaderClusterInfoInformer.Informer().HasSynced,
+		serviceExportQueue:              workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "serviceExport"),
+		serviceImportQueue:              workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "serviceImport"),
+		clusterInfoQueue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "clusterInfo"),
+	}
+	nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addNode,
+			UpdateFunc: c.updateNode,
+			DeleteFunc: c.deleteNode,
+		},
+		resyncPeriod,
+	)
+	serviceInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addServiceOrEndpoints,
+			UpdateFunc: c.updateServiceOrEndpoints,
+			DeleteFunc: c.deleteServiceOrEndpoints,
+		},
+		resyncPeriod,
+	)
+	endpointsInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addServiceOrEndpoints,
+			UpdateFunc: c.updateServiceOrEndpoints,
+			DeleteFunc: c.deleteServiceOrEndpoints,
+		},
+		resyncPeriod,
+	)
+	serviceExportInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			// The updates of ServiceExports only change their status,
+			// which is maintained by this controller.
+			AddFunc:    c.addServiceExport,
+			DeleteFunc: c.deleteServiceExport,
+		},
+		resyncPeriod,
+	)
+	clusterInfoInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				info, ok := getClusterInfo(obj)
+				return ok && info.Namespace == localNamespace
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.enqueueClusterInfo,
+				UpdateFunc: func(_, cur interface{}) { c.enqueueClusterInfo(cur) },
+				DeleteFunc: c.enqueueClusterInfo,
+			},
+		},
+		resyncPeriod,
+	)
+	leaderServiceImportInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addServiceImport,
+			UpdateFunc: c.updateServiceImport,
+			DeleteFunc: c.deleteServiceImport,
+		},
+		resyncPeriod,
+	)
+	leaderClusterInfoInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueClusterInfo,
+			UpdateFunc: func(_, cur interface{}) { c.enqueueClusterInfo(cur) },
+			DeleteFunc: c.enqueueClusterInfo,
+		},
+		resyncPeriod,
+	)
+	return c
+}
+
+func (c *Controller) addNode(obj interface{}) {
+	node := obj.(*corev1.Node)
+	klog.V(2).Infof("Processing Node %s ADD event", node.Name)
+	c.clusterInfoQueue.Add(c.clusterID)
+}
+
+func (c *Controller) updateNode(old, cur interface{}) {
+	oldNode := old.(*corev1.Node)
+	curNode := cur.(*corev1.Node)
+	// Only the labels, the Pod CIDRs, the addresses and the conditions of
+	// the Nodes affect the ClusterInfo of the cluster. The heartbeat times of
+	// the conditions are ignored.
+	if apiequality.Semantic.DeepEqual(oldNode.Labels, curNode.Labels) &&
+		apiequality.Semantic.DeepEqual(oldNode.Spec.PodCIDRs, curNode.Spec.PodCIDRs) &&
+		oldNode.Spec.PodCIDR == curNode.Spec.PodCIDR &&
+		apiequality.Semantic.DeepEqual(oldNode.Status.Addresses, curNode.Status.Addresses) &&
+		isNodeReady(oldNode) == isNodeReady(curNode) {
+		return
+	}
+	klog.V(2).Infof("Processing Node %s UPDATE event", curNode.Name)
+	c.clusterInfoQueue.Add(c.clusterID)
+}
+
+func (c *Controller) deleteNode(old interface{}) {
+	node, ok := old.(*corev1.Node)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Node, invalid type: %v", old)
+			return
+		}
+		node, ok = tombstone.Obj.(*corev1.Node)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Node, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing Node %s DELETE event", node.Name)
+	c.clusterInfoQueue.Add(c.clusterID)
+}
+
+// enqueueServiceOrEndpoints enqueues the exported Service and the
+// ServiceImport related to the Service or the Endpoints.
+func (c *Controller) enqueueServiceOrEndpoints(obj metav1.Object) {
+	namespace, name := obj.GetNamespace(), obj.GetName()
+	if _, err := c.serviceExportLister.ServiceExports(namespace).Get(name); err == nil {
+		c.serviceExportQueue.Add(k8s.NamespacedName(namespace, name))
+	}
+	// The derived Services and Endpoints are reverted if they are changed
+	// or deleted by others.
+	if importName, ok := obj.GetLabels()[mcv1alpha1.ServiceImportLabelKey]; ok {
+		c.serviceImportQueue.Add(importName)
+	}
+}
+
+func (c *Controller) addServiceOrEndpoints(obj interface{}) {
+	c.enqueueServiceOrEndpoints(obj.(metav1.Object))
+}
+
+func (c *Controller) updateServiceOrEndpoints(_, cur interface{}) {
+	c.enqueueServiceOrEndpoints(cur.(metav1.Object))
+}
+
+func (c *Controller) deleteServiceOrEndpoints(old interface{}) {
+	obj, ok := old.(metav1.Object)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Service or Endpoints, invalid type: %v", old)
+			return
+		}
+		obj, ok = tombstone.Obj.(metav1.Object)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Service or Endpoints, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	c.enqueueServiceOrEndpoints(obj)
+}
+
+func (c *Controller) addServiceExport(obj interface{}) {
+	export := obj.(*mcv1alpha1.ServiceExport)
+	klog.V(2).Infof("Processing ServiceExport %s/%s ADD event", export.Namespace, export.Name)
+	c.serviceExportQueue.Add(k8s.NamespacedName(export.Namespace, export.Name))
+}
+
+func (c *Controller) deleteServiceExport(old interface{}) {
+	export, ok := old.(*mcv1alpha1.ServiceExport)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting ServiceExport, invalid type: %v", old)
+			return
+		}
+		export, ok = tombstone.Obj.(*mcv1alpha1.ServiceExport)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting ServiceExport, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing ServiceExport %s/%s DELETE event", export.Namespace, export.Name)
+	c.serviceExportQueue.Add(k8s.NamespacedName(export.Namespace, export.Name))
+}
+
+func (c *Controller) addServiceImport(obj interface{}) {
+	imp := obj.(*mcv1alpha1.ServiceImport)
+	klog.V(2).Infof("Processing ServiceImport %s ADD event", imp.Name)
+	c.serviceImportQueue.Add(imp.Name)
+}
+
+func (c *Controller) updateServiceImport(old, cur interface{}) {
+	oldImport := old.(*mcv1alpha1.ServiceImport)
+	curImport := cur.(*mcv1alpha1.ServiceImport)
+	if apiequality.Semantic.DeepEqual(oldImport.Spec, curImport.Spec) {
+		return
+	}
+	klog.V(2).Infof("Processing ServiceImport %s UPDATE event", curImport.Name)
+	c.serviceImportQueue.Add(curImport.Name)
+}
+
+func (c *Controller) deleteServiceImport(old interface{}) {
+	imp, ok := old.(*mcv1alpha1.ServiceImport)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting ServiceImport, invalid type: %v", old)
+			return
+		}
+		imp, ok = tombstone.Obj.(*mcv1alpha1.ServiceImport)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting ServiceImport, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	klog.V(2).Infof("Processing ServiceImport %s DELETE event", imp.Name)
+	c.serviceImportQueue.Add(imp.Name)
+	// The ServiceImport is re-created if the Service is still exported by
+	// this cluster.
+	if namespace, name, ok := parseServiceImportName(imp.Name); ok {
+		c.serviceExportQueue.Add(k8s.NamespacedName(namespace, name))
+	}
+}
+
+func getClusterInfo(obj interface{}) (*mcv1alpha1.ClusterInfo, bool) {
+	info, ok := obj.(*mcv1alpha1.ClusterInfo)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return nil, false
+		}
+		info, ok = tombstone.Obj.(*mcv1alpha1.ClusterInfo)
+		if !ok {
+			return nil, false
+		}
+	}
+	return info, true
+}
+
+func (c *Controller) enqueueClusterInfo(obj interface{}) {
+	info, ok := getClusterInfo(obj)
+	if !ok {
+		klog.Errorf("Error decoding ClusterInfo, invalid type: %v", obj)
+		return
+	}
+	klog.V(2).Infof("Processing ClusterInfo %s/%s event", info.Namespace, info.Name)
+	c.clusterInfoQueue.Add(info.Name)
+}
+
+// Run will create a worker for each of the queues, after enqueuing the
+// derived Services and the copies of the ClusterInfos which exist before, so
+// that the stale ones are deleted.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.serviceExportQueue.ShutDown()
+	defer c.serviceImportQueue.ShutDown()
+	defer c.clusterInfoQueue.ShutDown()
+
+	klog.Infof("Starting %s", controllerName)
+	defer klog.Infof("Shutting down %s", controllerName)
+
+	klog.Infof("Waiting for caches to sync for %s", controllerName)
+	if !cache.WaitForCacheSync(stopCh, c.nodeListerSynced, c.serviceListerSynced, c.endpointsListerSynced,
+		c.serviceExportListerSynced, c.clusterInfoListerSynced,
+		c.leaderServiceImportListerSynced, c.leaderClusterInfoListerSynced) {
+		klog.Errorf("Unable to sync caches for %s", controllerName)
+		return
+	}
+	klog.Infof("Caches are synced for %s", controllerName)
+
+	c.clusterInfoQueue.Add(c.clusterID)
+	services, err := c.serviceLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Services: %v", err)
+	}
+	for _, svc := range services {
+		c.enqueueServiceOrEndpoints(svc)
+	}
+	infos, err := c.clusterInfoLister.ClusterInfos(c.localNamespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing ClusterInfos: %v", err)
+	}
+	for _, info := range infos {
+		c.clusterInfoQueue.Add(info.Name)
+	}
+
+	go wait.Until(c.serviceExportWorker, time.Second, stopCh)
+	go wait.Until(c.serviceImportWorker, time.Second, stopCh)
+	go wait.Until(c.clusterInfoWorker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (c *Controller) serviceExportWorker() {
+	for c.processNextWorkItem(c.serviceExportQueue, c.syncServiceExport) {
+	}
+}
+
+func (c *Controller) serviceImportWorker() {
+	for c.processNextWorkItem(c.serviceImportQueue, c.syncServiceImport) {
+	}
+}
+
+func (c *Controller) clusterInfoWorker() {
+	for c.processNextWorkItem(c.clusterInfoQueue, c.syncClusterInfo) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(queue workqueue.RateLimitingInterface, syncFunc func(string) error) bool {
+	obj, quit := queue.Get()
+	if quit {
+		return false
+	}
+	defer queue.Done(obj)
+
+	// We expect strings (Service key, ServiceImport name or cluster ID) to
+	// come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd
+		// go into a loop of attempting to process a work item that is invalid.
+		queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := syncFunc(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again until
+		// another change happens.
+		queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		queue.AddRateLimited(key)
+		klog.Errorf("Error syncing %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
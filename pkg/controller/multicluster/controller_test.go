@@ -0,0 +1,339 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+const (
+	testLocalNamespace  = "kube-system"
+	testLeaderNamespace = "antrea-multicluster"
+)
+
+// memberCluster is a member cluster with its own clients and controller.
+type memberCluster struct {
+	*Controller
+	client                   kubernetes.Interface
+	crdClient                versioned.Interface
+	informerFactory          informers.SharedInformerFactory
+	crdInformerFactory       crdinformers.SharedInformerFactory
+	leaderCRDInformerFactory crdinformers.SharedInformerFactory
+}
+
+// newMemberCluster creates a member cluster whose leader cluster is accessed
+// with leaderCRDClient. It's the leader itself if leaderCRDClient is nil.
+func newMemberCluster(clusterID string, leaderCRDClient versioned.Interface, objects ...runtime.Object) *memberCluster {
+	client := fake.NewSimpleClientset(objects...)
+	crdClient := fakeversioned.NewSimpleClientset()
+	if leaderCRDClient == nil {
+		leaderCRDClient = crdClient
+	}
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	leaderCRDInformerFactory := crdinformers.NewSharedInformerFactoryWithOptions(leaderCRDClient, 0, crdinformers.WithNamespace(testLeaderNamespace))
+	controller := NewMulticlusterController(clusterID, testLocalNamespace, testLeaderNamespace,
+		client, crdClient, leaderCRDClient,
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Core().V1().Services(),
+		informerFactory.Core().V1().Endpoints(),
+		crdInformerFactory.Multicluster().V1alpha1().ServiceExports(),
+		crdInformerFactory.Multicluster().V1alpha1().ClusterInfos(),
+		leaderCRDInformerFactory.Multicluster().V1alpha1().ServiceImports(),
+		leaderCRDInformerFactory.Multicluster().V1alpha1().ClusterInfos())
+	return &memberCluster{
+		controller,
+		client,
+		crdClient,
+		informerFactory,
+		crdInformerFactory,
+		leaderCRDInformerFactory,
+	}
+}
+
+func (c *memberCluster) start(stopCh <-chan struct{}) {
+	c.informerFactory.Start(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	c.leaderCRDInformerFactory.Start(stopCh)
+	go c.Run(stopCh)
+}
+
+func newGatewayNode(name, ip, podCIDR string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{mcv1alpha1.GatewayNodeLabelKey: "true"},
+		},
+		Spec: corev1.NodeSpec{PodCIDR: podCIDR},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func newExportedService(namespace, name string, endpointIPs ...string) (*corev1.Service, *corev1.Endpoints) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(8080)}},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Subsets: []corev1.EndpointSubset{{
+			Ports: []corev1.EndpointPort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080}},
+		}},
+	}
+	for _, ip := range endpointIPs {
+		endpoints.Subsets[0].Addresses = append(endpoints.Subsets[0].Addresses, corev1.EndpointAddress{
+			IP:       ip,
+			NodeName: &name,
+		})
+	}
+	return svc, endpoints
+}
+
+func endpointIPs(endpoints *corev1.Endpoints) []string {
+	var ips []string
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			ips = append(ips, address.IP)
+		}
+	}
+	return ips
+}
+
+func (c *memberCluster) waitForImportedEndpoints(t *testing.T, namespace, name string, expectedIPs ...string) {
+	var ips []string
+	err := wait.PollImmediate(50*time.Millisecond, 2*time.Second, func() (bool, error) {
+		svc, err := c.client.CoreV1().Services(namespace).Get(context.TODO(), derivedServiceName(name), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return expectedIPs == nil, nil
+			}
+			return false, err
+		}
+		if !servicePortsEqual(svc.Spec.Ports, []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}}) {
+			return false, nil
+		}
+		endpoints, err := c.client.CoreV1().Endpoints(namespace).Get(context.TODO(), derivedServiceName(name), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		ips = endpointIPs(endpoints)
+		for _, subset := range endpoints.Subsets {
+			for _, address := range subset.Addresses {
+				// The addresses must not refer to the Nodes of the
+				// exporting clusters.
+				if address.NodeName != nil {
+					return false, nil
+				}
+			}
+		}
+		return assert.ObjectsAreEqual(expectedIPs, ips), nil
+	})
+	assert.NoError(t, err, "Imported endpoints of Service %s/%s are %v, expected %v", namespace, name, ips, expectedIPs)
+}
+
+func (c *memberCluster) waitForRemoteClusterInfo(t *testing.T, clusterID string, expectedSpec *mcv1alpha1.ClusterInfoSpec) {
+	var spec *mcv1alpha1.ClusterInfoSpec
+	err := wait.PollImmediate(50*time.Millisecond, 2*time.Second, func() (bool, error) {
+		info, err := c.crdClient.MulticlusterV1alpha1().ClusterInfos(testLocalNamespace).Get(context.TODO(), clusterID, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				spec = nil
+				return expectedSpec == nil, nil
+			}
+			return false, err
+		}
+		spec = &info.Spec
+		return assert.ObjectsAreEqual(expectedSpec, spec), nil
+	})
+	assert.NoError(t, err, "ClusterInfo of cluster %s is %v, expected %v", clusterID, spec, expectedSpec)
+}
+
+func TestExportAndImportService(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	clusterA := newMemberCluster("cluster-a", nil, newGatewayNode("node-a", "192.168.1.1", "10.10.0.0/24"))
+	svcB, endpointsB := newExportedService("ns1", "svc1", "10.20.0.5", "10.20.0.6")
+	clusterB := newMemberCluster("cluster-b", clusterA.crdClient, newGatewayNode("node-b", "192.168.2.1", "10.20.0.0/24"), svcB, endpointsB)
+	clusterA.start(stopCh)
+	clusterB.start(stopCh)
+
+	// The clusters learn the gateways and the Pod CIDRs of each other.
+	clusterA.waitForRemoteClusterInfo(t, "cluster-b", &mcv1alpha1.ClusterInfoSpec{
+		ClusterID: "cluster-b",
+		GatewayIP: "192.168.2.1",
+		PodCIDRs:  []string{"10.20.0.0/24"},
+	})
+	clusterB.waitForRemoteClusterInfo(t, "cluster-a", &mcv1alpha1.ClusterInfoSpec{
+		ClusterID: "cluster-a",
+		GatewayIP: "192.168.1.1",
+		PodCIDRs:  []string{"10.10.0.0/24"},
+	})
+	// A cluster doesn't copy its own ClusterInfo.
+	clusterA.waitForRemoteClusterInfo(t, "cluster-a", nil)
+
+	export := &mcv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc1"}}
+	_, err := clusterB.crdClient.MulticlusterV1alpha1().ServiceExports("ns1").Create(context.TODO(), export, metav1.CreateOptions{})
+	require.NoError(t, err)
+	clusterA.waitForImportedEndpoints(t, "ns1", "svc1", "10.20.0.5", "10.20.0.6")
+	clusterB.waitForImportedEndpoints(t, "ns1", "svc1", "10.20.0.5", "10.20.0.6")
+	err = wait.PollImmediate(50*time.Millisecond, 2*time.Second, func() (bool, error) {
+		export, err := clusterB.crdClient.MulticlusterV1alpha1().ServiceExports("ns1").Get(context.TODO(), "svc1", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		conditions := export.Status.Conditions
+		return len(conditions) == 1 && conditions[0].Status == corev1.ConditionTrue, nil
+	})
+	assert.NoError(t, err, "ServiceExport is not marked as exported")
+
+	// The endpoints of the same Service in cluster A are merged.
+	svcA, endpointsA := newExportedService("ns1", "svc1", "10.10.0.5")
+	_, err = clusterA.client.CoreV1().Services("ns1").Create(context.TODO(), svcA, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = clusterA.client.CoreV1().Endpoints("ns1").Create(context.TODO(), endpointsA, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = clusterA.crdClient.MulticlusterV1alpha1().ServiceExports("ns1").Create(context.TODO(), export, metav1.CreateOptions{})
+	require.NoError(t, err)
+	clusterB.waitForImportedEndpoints(t, "ns1", "svc1", "10.10.0.5", "10.20.0.5", "10.20.0.6")
+
+	// The update of the endpoints in cluster B is propagated.
+	endpointsB.Subsets[0].Addresses = endpointsB.Subsets[0].Addresses[:1]
+	_, err = clusterB.client.CoreV1().Endpoints("ns1").Update(context.TODO(), endpointsB, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	clusterA.waitForImportedEndpoints(t, "ns1", "svc1", "10.10.0.5", "10.20.0.5")
+
+	// The derived Service is deleted after all clusters stop exporting it.
+	err = clusterB.crdClient.MulticlusterV1alpha1().ServiceExports("ns1").Delete(context.TODO(), "svc1", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	clusterA.waitForImportedEndpoints(t, "ns1", "svc1", "10.10.0.5")
+	err = clusterA.client.CoreV1().Services("ns1").Delete(context.TODO(), "svc1", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	clusterA.waitForImportedEndpoints(t, "ns1", "svc1")
+	clusterB.waitForImportedEndpoints(t, "ns1", "svc1")
+	_, err = clusterA.crdClient.MulticlusterV1alpha1().ServiceImports(testLeaderNamespace).Get(context.TODO(), serviceImportName("ns1", "svc1"), metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "ServiceImport should be deleted")
+}
+
+func TestImportServiceWithTargetPort(t *testing.T) {
+	imp := &mcv1alpha1.ServiceImport{
+		Spec: mcv1alpha1.ServiceImportSpec{
+			Ports: []mcv1alpha1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}},
+			Clusters: []mcv1alpha1.ClusterEndpoints{
+				{
+					ClusterID: "cluster-a",
+					Subsets: []corev1.EndpointSubset{{
+						Addresses: []corev1.EndpointAddress{{IP: "10.10.0.5"}},
+						Ports:     []corev1.EndpointPort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080}},
+					}},
+				},
+				{
+					ClusterID: "cluster-b",
+					Subsets: []corev1.EndpointSubset{{
+						Addresses: []corev1.EndpointAddress{{IP: "10.20.0.5"}},
+						Ports:     []corev1.EndpointPort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 9090}},
+					}},
+				},
+			},
+		},
+	}
+	// The derived Service exposes the Service port, and the traffic is sent to
+	// the target port of each exporting cluster through the Endpoints.
+	assert.Equal(t, []corev1.ServicePort{{
+		Name:       "http",
+		Protocol:   corev1.ProtocolTCP,
+		Port:       80,
+		TargetPort: intstr.FromInt(80),
+	}}, importedPorts(imp))
+	assert.Equal(t, []corev1.EndpointSubset{
+		{
+			Addresses: []corev1.EndpointAddress{{IP: "10.10.0.5"}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080}},
+		},
+		{
+			Addresses: []corev1.EndpointAddress{{IP: "10.20.0.5"}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 9090}},
+		},
+	}, importedSubsets(imp))
+
+	// The exported ports don't include the target port.
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(8080)}},
+		},
+	}
+	assert.Equal(t, imp.Spec.Ports, exportedPorts(svc))
+}
+
+func TestUpdateClusterInfo(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	node1 := newGatewayNode("node1", "192.168.1.2", "10.10.1.0/24")
+	node2 := newGatewayNode("node2", "192.168.1.1", "10.10.0.0/24")
+	clusterA := newMemberCluster("cluster-a", nil, node1, node2)
+	clusterB := newMemberCluster("cluster-b", clusterA.crdClient)
+	clusterA.start(stopCh)
+	clusterB.start(stopCh)
+
+	// The first eligible Node ordered by name is the gateway.
+	clusterB.waitForRemoteClusterInfo(t, "cluster-a", &mcv1alpha1.ClusterInfoSpec{
+		ClusterID: "cluster-a",
+		GatewayIP: "192.168.1.2",
+		PodCIDRs:  []string{"10.10.0.0/24", "10.10.1.0/24"},
+	})
+
+	node1.Status.Conditions[0].Status = corev1.ConditionFalse
+	_, err := clusterA.client.CoreV1().Nodes().Update(context.TODO(), node1, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	clusterB.waitForRemoteClusterInfo(t, "cluster-a", &mcv1alpha1.ClusterInfoSpec{
+		ClusterID: "cluster-a",
+		GatewayIP: "192.168.1.1",
+		PodCIDRs:  []string{"10.10.0.0/24", "10.10.1.0/24"},
+	})
+
+	// Cluster B re-publishes its ClusterInfo if it's deleted from the leader
+	// cluster.
+	err = clusterA.crdClient.MulticlusterV1alpha1().ClusterInfos(testLeaderNamespace).Delete(context.TODO(), "cluster-b", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	clusterA.waitForRemoteClusterInfo(t, "cluster-b", &mcv1alpha1.ClusterInfoSpec{ClusterID: "cluster-b"})
+}
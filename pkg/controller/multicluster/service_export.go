@@ -0,0 +1,218 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+)
+
+// serviceImportName returns the name of the ServiceImport of a Service in the
+// leader cluster. Namespace names can't contain ".", so the name is unique.
+func serviceImportName(namespace, name string) string {
+	return fmt.Sprintf("%s.%s", namespace, name)
+}
+
+// parseServiceImportName returns the Namespace and the name of the Service of
+// a ServiceImport.
+func parseServiceImportName(importName string) (string, string, bool) {
+	parts := strings.SplitN(importName, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// exportedSubsets returns the ready endpoints of the Service to export. Only
+// the IPs of the addresses are kept, as the Nodes and the Pods they refer to
+// don't exist in the importing clusters.
+func exportedSubsets(endpoints *corev1.Endpoints) []corev1.EndpointSubset {
+	var subsets []corev1.EndpointSubset
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) == 0 {
+			continue
+		}
+		exported := corev1.EndpointSubset{
+			Addresses: make([]corev1.EndpointAddress, 0, len(subset.Addresses)),
+			Ports:     subset.Ports,
+		}
+		for _, address := range subset.Addresses {
+			exported.Addresses = append(exported.Addresses, corev1.EndpointAddress{IP: address.IP})
+		}
+		subsets = append(subsets, exported)
+	}
+	return subsets
+}
+
+func exportedPorts(svc *corev1.Service) []mcv1alpha1.ServicePort {
+	ports := make([]mcv1alpha1.ServicePort, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		ports = append(ports, mcv1alpha1.ServicePort{Name: port.Name, Protocol: port.Protocol, Port: port.Port})
+	}
+	return ports
+}
+
+func (c *Controller) syncServiceExport(key string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing ServiceExport %s. (%v)", key, time.Since(startTime))
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	export, err := c.serviceExportLister.ServiceExports(namespace).Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// The Service is not exported anymore.
+		return c.updateLeaderServiceImport(namespace, name, nil, nil)
+	}
+
+	svc, err := c.serviceLister.Services(namespace).Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := c.updateLeaderServiceImport(namespace, name, nil, nil); err != nil {
+			return err
+		}
+		return c.updateServiceExportCondition(export, corev1.ConditionFalse, "ServiceNotFound",
+			fmt.Sprintf("Service %s is not found", key))
+	}
+	var subsets []corev1.EndpointSubset
+	endpoints, err := c.endpointsLister.Endpoints(namespace).Get(name)
+	if err == nil {
+		subsets = exportedSubsets(endpoints)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+	clusterEndpoints := &mcv1alpha1.ClusterEndpoints{ClusterID: c.clusterID, Subsets: subsets}
+	if err := c.updateLeaderServiceImport(namespace, name, exportedPorts(svc), clusterEndpoints); err != nil {
+		return err
+	}
+	return c.updateServiceExportCondition(export, corev1.ConditionTrue, "Exported", "")
+}
+
+// updateLeaderServiceImport sets the endpoints of this cluster in the
+// ServiceImport of the Service in the leader cluster, or removes them if
+// clusterEndpoints is nil. The ServiceImport is created for the first
+// exporting cluster, and deleted after the last one removes its endpoints.
+func (c *Controller) updateLeaderServiceImport(namespace, name string, ports []mcv1alpha1.ServicePort, clusterEndpoints *mcv1alpha1.ClusterEndpoints) error {
+	importName := serviceImportName(namespace, name)
+	serviceImports := c.leaderCRDClient.MulticlusterV1alpha1().ServiceImports(c.leaderNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		imp, err := serviceImports.Get(context.TODO(), importName, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			if clusterEndpoints == nil {
+				return nil
+			}
+			imp = &mcv1alpha1.ServiceImport{
+				ObjectMeta: metav1.ObjectMeta{Name: importName, Namespace: c.leaderNamespace},
+				Spec: mcv1alpha1.ServiceImportSpec{
+					Ports:    ports,
+					Clusters: []mcv1alpha1.ClusterEndpoints{*clusterEndpoints},
+				},
+			}
+			if _, err := serviceImports.Create(context.TODO(), imp, metav1.CreateOptions{}); err != nil {
+				// A conflict is returned if another cluster has
+				// created it meanwhile.
+				if apierrors.IsAlreadyExists(err) {
+					return apierrors.NewConflict(mcv1alpha1.Resource("serviceimports"), importName, err)
+				}
+				return err
+			}
+			klog.Infof("Created ServiceImport %s in the leader cluster", importName)
+			return nil
+		}
+
+		spec := imp.Spec.DeepCopy()
+		clusters := make([]mcv1alpha1.ClusterEndpoints, 0, len(spec.Clusters)+1)
+		for _, cluster := range spec.Clusters {
+			if cluster.ClusterID != c.clusterID {
+				clusters = append(clusters, cluster)
+			}
+		}
+		if clusterEndpoints != nil {
+			clusters = append(clusters, *clusterEndpoints)
+			spec.Ports = ports
+		}
+		sort.Slice(clusters, func(i, j int) bool {
+			return clusters[i].ClusterID < clusters[j].ClusterID
+		})
+		spec.Clusters = clusters
+
+		if len(clusters) == 0 {
+			// The deletion fails with a conflict if another cluster has
+			// updated the ServiceImport meanwhile.
+			err := serviceImports.Delete(context.TODO(), importName, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{ResourceVersion: &imp.ResourceVersion},
+			})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			klog.Infof("Deleted ServiceImport %s in the leader cluster", importName)
+			return nil
+		}
+		if apiequality.Semantic.DeepEqual(&imp.Spec, spec) {
+			return nil
+		}
+		imp.Spec = *spec
+		if _, err := serviceImports.Update(context.TODO(), imp, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.V(2).Infof("Updated ServiceImport %s in the leader cluster", importName)
+		return nil
+	})
+}
+
+// updateServiceExportCondition sets the Exported condition of the
+// ServiceExport if it's changed.
+func (c *Controller) updateServiceExportCondition(export *mcv1alpha1.ServiceExport, status corev1.ConditionStatus, reason, message string) error {
+	for _, condition := range export.Status.Conditions {
+		if condition.Type == mcv1alpha1.ServiceExportExported &&
+			condition.Status == status && condition.Reason == reason && condition.Message == message {
+			return nil
+		}
+	}
+	exportCopy := export.DeepCopy()
+	exportCopy.Status.Conditions = []mcv1alpha1.ServiceExportCondition{{
+		Type:               mcv1alpha1.ServiceExportExported,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}}
+	_, err := c.crdClient.MulticlusterV1alpha1().ServiceExports(export.Namespace).UpdateStatus(context.TODO(), exportCopy, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+)
+
+// derivedServiceNamePrefix is the prefix of the names of the Services derived
+// from ServiceImports. An imported Service is reachable with the name
+// "antrea-mc-<Service name>" in the Namespace of the exported Service.
+const derivedServiceNamePrefix = "antrea-mc-"
+
+func derivedServiceName(name string) string {
+	return derivedServiceNamePrefix + name
+}
+
+// importedSubsets returns the endpoints of the Service from all the exporting
+// clusters.
+func importedSubsets(imp *mcv1alpha1.ServiceImport) []corev1.EndpointSubset {
+	var subsets []corev1.EndpointSubset
+	for _, cluster := range imp.Spec.Clusters {
+		subsets = append(subsets, cluster.Subsets...)
+	}
+	return subsets
+}
+
+// importedPorts returns the ports of the Service derived from the ServiceImport.
+// The derived Service has no selector, and its Endpoints, which carry the target
+// ports resolved by the exporting clusters, are the ones of all the exporting
+// clusters. The remote endpoints are thus added to the Service groups of
+// AntreaProxy through the Endpoints of the derived Service, like the endpoints
+// of any other Service, without any change to AntreaProxy.
+func importedPorts(imp *mcv1alpha1.ServiceImport) []corev1.ServicePort {
+	ports := make([]corev1.ServicePort, 0, len(imp.Spec.Ports))
+	for _, port := range imp.Spec.Ports {
+		ports = append(ports, corev1.ServicePort{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Port:     port.Port,
+			// The target ports are resolved by the exporting clusters, and
+			// they are the ports of the imported Endpoints. The TargetPort
+			// is not used as the Service has no selector.
+			TargetPort: intstr.FromInt(int(port.Port)),
+		})
+	}
+	return ports
+}
+
+// servicePortsEqual returns whether the names, the protocols and the ports of
+// the ServicePorts are the same, ignoring the fields set by the API server.
+func servicePortsEqual(a, b []corev1.ServicePort) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Protocol != b[i].Protocol || a[i].Port != b[i].Port {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Controller) syncServiceImport(importName string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing ServiceImport %s. (%v)", importName, time.Since(startTime))
+	}()
+
+	namespace, name, ok := parseServiceImportName(importName)
+	if !ok {
+		// Retrying doesn't help.
+		klog.Errorf("Invalid ServiceImport name %s", importName)
+		return nil
+	}
+	svcName := derivedServiceName(name)
+	if errs := validation.IsDNS1035Label(svcName); len(errs) > 0 {
+		klog.Errorf("Can't import ServiceImport %s as Service %s/%s: %v", importName, namespace, svcName, errs)
+		return nil
+	}
+	imp, err := c.leaderServiceImportLister.ServiceImports(c.leaderNamespace).Get(importName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return c.deleteDerivedService(importName, namespace, svcName)
+	}
+
+	labels := map[string]string{mcv1alpha1.ServiceImportLabelKey: importName}
+	ports := importedPorts(imp)
+	svc, err := c.serviceLister.Services(namespace).Get(svcName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// The Service has no selector, its Endpoints are maintained by
+		// this controller.
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: svcName, Namespace: namespace, Labels: labels},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeClusterIP,
+				Ports: ports,
+			},
+		}
+		if _, err := c.client.CoreV1().Services(namespace).Create(context.TODO(), svc, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("Created Service %s/%s for ServiceImport %s", namespace, svcName, importName)
+	} else if svc.Labels[mcv1alpha1.ServiceImportLabelKey] != importName {
+		// Retrying doesn't help until the Service is deleted.
+		klog.Errorf("Service %s/%s already exists and is not derived from ServiceImport %s", namespace, svcName, importName)
+		return nil
+	} else if !servicePortsEqual(svc.Spec.Ports, ports) {
+		svcCopy := svc.DeepCopy()
+		svcCopy.Spec.Ports = ports
+		if _, err := c.client.CoreV1().Services(namespace).Update(context.TODO(), svcCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("Updated ports of Service %s/%s for ServiceImport %s", namespace, svcName, importName)
+	}
+
+	subsets := importedSubsets(imp)
+	endpoints, err := c.endpointsLister.Endpoints(namespace).Get(svcName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		endpoints = &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: svcName, Namespace: namespace, Labels: labels},
+			Subsets:    subsets,
+		}
+		if _, err := c.client.CoreV1().Endpoints(namespace).Create(context.TODO(), endpoints, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		return nil
+	}
+	if apiequality.Semantic.DeepEqual(endpoints.Subsets, subsets) && endpoints.Labels[mcv1alpha1.ServiceImportLabelKey] == importName {
+		return nil
+	}
+	endpointsCopy := endpoints.DeepCopy()
+	endpointsCopy.Labels = labels
+	endpointsCopy.Subsets = subsets
+	if _, err := c.client.CoreV1().Endpoints(namespace).Update(context.TODO(), endpointsCopy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.V(2).Infof("Updated Endpoints %s/%s for ServiceImport %s", namespace, svcName, importName)
+	return nil
+}
+
+// deleteDerivedService deletes the Service derived from the ServiceImport and
+// its Endpoints if they exist.
+func (c *Controller) deleteDerivedService(importName, namespace, svcName string) error {
+	svc, err := c.serviceLister.Services(namespace).Get(svcName)
+	if err == nil && svc.Labels[mcv1alpha1.ServiceImportLabelKey] == importName {
+		if err := c.client.CoreV1().Services(namespace).Delete(context.TODO(), svcName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.Infof("Deleted Service %s/%s for ServiceImport %s", namespace, svcName, importName)
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	endpoints, err := c.endpointsLister.Endpoints(namespace).Get(svcName)
+	if err == nil && endpoints.Labels[mcv1alpha1.ServiceImportLabelKey] == importName {
+		if err := c.client.CoreV1().Endpoints(namespace).Delete(context.TODO(), svcName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
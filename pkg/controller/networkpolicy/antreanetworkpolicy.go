@@ -127,6 +127,11 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *secv1alpha1.Net
 	// Create AppliedToGroup for each AppliedTo present in
 	// AntreaNetworkPolicy spec.
 	for _, at := range np.Spec.AppliedTo {
+		if at.ServiceAccount != nil {
+			groupSelector := toGroupSelectorForServiceAccount(serviceAccountNamespace(at.ServiceAccount, np), at.ServiceAccount.Name)
+			appliedToGroupNames = append(appliedToGroupNames, n.createAppliedToGroupForSelector(groupSelector))
+			continue
+		}
 		appliedToGroupNames = append(appliedToGroupNames, n.createAppliedToGroup(
 			np.Namespace, at.PodSelector, at.NamespaceSelector, at.ExternalEntitySelector))
 	}
@@ -136,11 +141,12 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *secv1alpha1.Net
 		// Set default action to ALLOW to allow traffic.
 		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports)
 		rules = append(rules, controlplane.NetworkPolicyRule{
-			Direction: controlplane.DirectionIn,
-			From:      *n.toAntreaPeerForCRD(ingressRule.From, np, controlplane.DirectionIn, namedPortExists),
-			Services:  services,
-			Action:    ingressRule.Action,
-			Priority:  int32(idx),
+			Direction:     controlplane.DirectionIn,
+			From:          *n.toAntreaPeerForCRD(ingressRule.From, np, controlplane.DirectionIn, namedPortExists),
+			Services:      services,
+			Action:        ingressRule.Action,
+			Priority:      int32(idx),
+			EnableLogging: ingressRule.EnableLogging,
 		})
 	}
 	// Compute NetworkPolicyRule for Egress Rule.
@@ -148,11 +154,12 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *secv1alpha1.Net
 		// Set default action to ALLOW to allow traffic.
 		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports)
 		rules = append(rules, controlplane.NetworkPolicyRule{
-			Direction: controlplane.DirectionOut,
-			To:        *n.toAntreaPeerForCRD(egressRule.To, np, controlplane.DirectionOut, namedPortExists),
-			Services:  services,
-			Action:    egressRule.Action,
-			Priority:  int32(idx),
+			Direction:     controlplane.DirectionOut,
+			To:            *n.toAntreaPeerForCRD(egressRule.To, np, controlplane.DirectionOut, namedPortExists),
+			Services:      services,
+			Action:        egressRule.Action,
+			Priority:      int32(idx),
+			EnableLogging: egressRule.EnableLogging,
 		})
 	}
 	tierPriority := n.getTierPriority(np.Spec.Tier)
@@ -166,6 +173,7 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *secv1alpha1.Net
 		Name:            np.Name,
 		Namespace:       np.Namespace,
 		UID:             np.UID,
+		Generation:      np.Generation,
 		AppliedToGroups: appliedToGroupNames,
 		Rules:           rules,
 		Priority:        &np.Spec.Priority,
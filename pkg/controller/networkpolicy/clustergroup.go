@@ -0,0 +1,234 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	corev1a2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+// addClusterGroup receives ClusterGroup ADD events and reprocesses the
+// ClusterNetworkPolicies which refer to it.
+func (n *NetworkPolicyController) addClusterGroup(obj interface{}) {
+	defer n.heartbeat("addClusterGroup")
+	cg := obj.(*corev1a2.ClusterGroup)
+	klog.Infof("Processing ClusterGroup %s ADD event", cg.Name)
+	n.triggerCNPUpdates(cg.Name)
+}
+
+// updateClusterGroup receives ClusterGroup UPDATE events and reprocesses the
+// ClusterNetworkPolicies which refer to it if its spec has changed.
+func (n *NetworkPolicyController) updateClusterGroup(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateClusterGroup")
+	oldCG := oldObj.(*corev1a2.ClusterGroup)
+	curCG := curObj.(*corev1a2.ClusterGroup)
+	if reflect.DeepEqual(oldCG.Spec, curCG.Spec) {
+		return
+	}
+	klog.Infof("Processing ClusterGroup %s UPDATE event", curCG.Name)
+	n.triggerCNPUpdates(curCG.Name)
+}
+
+// deleteClusterGroup receives ClusterGroup DELETED events and reprocesses the
+// ClusterNetworkPolicies which refer to it.
+func (n *NetworkPolicyController) deleteClusterGroup(oldObj interface{}) {
+	cg, ok := oldObj.(*corev1a2.ClusterGroup)
+	if !ok {
+		tombstone, ok := oldObj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting ClusterGroup, invalid type: %v", oldObj)
+			return
+		}
+		cg, ok = tombstone.Obj.(*corev1a2.ClusterGroup)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting ClusterGroup, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	defer n.heartbeat("deleteClusterGroup")
+	klog.Infof("Processing ClusterGroup %s DELETE event", cg.Name)
+	n.triggerCNPUpdates(cg.Name)
+}
+
+// addService receives Service ADD events and reprocesses the
+// ClusterNetworkPolicies which refer to the Service through ClusterGroups.
+func (n *NetworkPolicyController) addService(obj interface{}) {
+	defer n.heartbeat("addService")
+	svc := obj.(*v1.Service)
+	klog.V(2).Infof("Processing Service %s/%s ADD event", svc.Namespace, svc.Name)
+	n.triggerClusterGroupUpdatesForService(svc)
+}
+
+// updateService receives Service UPDATE events and reprocesses the
+// ClusterNetworkPolicies which refer to the Service through ClusterGroups if
+// the selector of the Service has changed.
+func (n *NetworkPolicyController) updateService(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateService")
+	oldSvc := oldObj.(*v1.Service)
+	curSvc := curObj.(*v1.Service)
+	if reflect.DeepEqual(oldSvc.Spec.Selector, curSvc.Spec.Selector) {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s UPDATE event", curSvc.Namespace, curSvc.Name)
+	n.triggerClusterGroupUpdatesForService(curSvc)
+}
+
+// deleteService receives Service DELETED events and reprocesses the
+// ClusterNetworkPolicies which refer to the Service through ClusterGroups.
+func (n *NetworkPolicyController) deleteService(oldObj interface{}) {
+	svc, ok := oldObj.(*v1.Service)
+	if !ok {
+		tombstone, ok := oldObj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Service, invalid type: %v", oldObj)
+			return
+		}
+		svc, ok = tombstone.Obj.(*v1.Service)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Service, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	defer n.heartbeat("deleteService")
+	klog.V(2).Infof("Processing Service %s/%s DELETE event", svc.Namespace, svc.Name)
+	n.triggerClusterGroupUpdatesForService(svc)
+}
+
+// triggerClusterGroupUpdatesForService reprocesses the ClusterNetworkPolicies
+// referring to ClusterGroups which refer to the given Service.
+func (n *NetworkPolicyController) triggerClusterGroupUpdatesForService(svc *v1.Service) {
+	cgs, err := n.cgInformer.Informer().GetIndexer().ByIndex(ServiceIndex, k8s.NamespacedName(svc.Namespace, svc.Name))
+	if err != nil {
+		klog.Errorf("Error retrieving ClusterGroups corresponding to Service %s/%s: %v", svc.Namespace, svc.Name, err)
+		return
+	}
+	for _, obj := range cgs {
+		cg := obj.(*corev1a2.ClusterGroup)
+		n.triggerCNPUpdates(cg.Name)
+	}
+}
+
+// triggerCNPUpdates reprocesses all the ClusterNetworkPolicies which refer to
+// the given ClusterGroup, so that their AppliedToGroups and AddressGroups
+// reflect the current state of the ClusterGroup.
+func (n *NetworkPolicyController) triggerCNPUpdates(cgName string) {
+	cnps, err := n.cnpInformer.Informer().GetIndexer().ByIndex(ClusterGroupIndex, cgName)
+	if err != nil {
+		klog.Errorf("Error retrieving ClusterNetworkPolicies corresponding to ClusterGroup %s: %v", cgName, err)
+		return
+	}
+	for _, obj := range cnps {
+		cnp := obj.(*secv1alpha1.ClusterNetworkPolicy)
+		n.reprocessCNP(cnp)
+	}
+}
+
+// getClusterGroupNames returns the names of all the ClusterGroups referred in
+// the AppliedTo and rules of the given ClusterNetworkPolicy.
+func getClusterGroupNames(cnp *secv1alpha1.ClusterNetworkPolicy) sets.String {
+	names := sets.NewString()
+	addPeers := func(peers []secv1alpha1.NetworkPolicyPeer) {
+		for _, peer := range peers {
+			if peer.Group != "" {
+				names.Insert(peer.Group)
+			}
+		}
+	}
+	addPeers(cnp.Spec.AppliedTo)
+	for _, rule := range cnp.Spec.Ingress {
+		addPeers(rule.From)
+	}
+	for _, rule := range cnp.Spec.Egress {
+		addPeers(rule.To)
+	}
+	return names
+}
+
+// processClusterGroup resolves the ClusterGroup with the given name to a
+// GroupSelector and a list of IPBlocks. The GroupSelector is nil if the
+// ClusterGroup does not exist or does not select any workload, e.g. when it
+// is defined by IPBlocks or refers to a Service without selector.
+func (n *NetworkPolicyController) processClusterGroup(name string) (*antreatypes.GroupSelector, []controlplane.IPBlock) {
+	cg, err := n.cgLister.Get(name)
+	if err != nil {
+		klog.V(2).Infof("ClusterGroup %s not found: %v", name, err)
+		return nil, nil
+	}
+	if len(cg.Spec.IPBlocks) > 0 {
+		var ipBlocks []controlplane.IPBlock
+		for i := range cg.Spec.IPBlocks {
+			ipBlock, err := toAntreaIPBlockForCRD(&cg.Spec.IPBlocks[i])
+			if err != nil {
+				klog.Errorf("Failure processing ClusterGroup %s IPBlock %v: %v", cg.Name, cg.Spec.IPBlocks[i], err)
+				continue
+			}
+			ipBlocks = append(ipBlocks, *ipBlock)
+		}
+		return nil, ipBlocks
+	}
+	if svcRef := cg.Spec.ServiceReference; svcRef != nil {
+		svc, err := n.serviceLister.Services(svcRef.Namespace).Get(svcRef.Name)
+		if err != nil {
+			klog.V(2).Infof("Service %s/%s referred by ClusterGroup %s not found: %v", svcRef.Namespace, svcRef.Name, cg.Name, err)
+			return nil, nil
+		}
+		if len(svc.Spec.Selector) == 0 {
+			// A Service without selector doesn't select any Pod.
+			return nil, nil
+		}
+		return toGroupSelector(svc.Namespace, &metav1.LabelSelector{MatchLabels: svc.Spec.Selector}, nil, nil), nil
+	}
+	if cg.Spec.PodSelector == nil && cg.Spec.NamespaceSelector == nil && cg.Spec.ExternalEntitySelector == nil {
+		return nil, nil
+	}
+	// A ClusterGroup is cluster scoped, hence the selectors select workloads
+	// from all Namespaces unless NamespaceSelector is set.
+	return toGroupSelector("", cg.Spec.PodSelector, cg.Spec.NamespaceSelector, cg.Spec.ExternalEntitySelector), nil
+}
+
+// GetClusterGroupMembers returns the Pods and ExternalEntities currently
+// selected by the ClusterGroup with the given name, along with its IPBlocks.
+func (n *NetworkPolicyController) GetClusterGroupMembers(name string) (controlplane.GroupMemberSet, []controlplane.IPBlock, error) {
+	if _, err := n.cgLister.Get(name); err != nil {
+		return nil, nil, err
+	}
+	members := controlplane.GroupMemberSet{}
+	groupSelector, ipBlocks := n.processClusterGroup(name)
+	if groupSelector != nil {
+		pods, externalEntities := n.processSelector(*groupSelector)
+		for _, pod := range pods {
+			if pod.Status.PodIP == "" {
+				// Pods without IP address are not effective members.
+				continue
+			}
+			members.Insert(podToMemberPod(pod, true, true).ToGroupMember())
+		}
+		for _, entity := range externalEntities {
+			members.Insert(externalEntityToGroupMember(entity))
+		}
+	}
+	return members, ipBlocks, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	corev1a2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+func TestProcessClusterGroup(t *testing.T) {
+	selectorA := metav1.LabelSelector{MatchLabels: map[string]string{"foo1": "bar1"}}
+	selectorB := metav1.LabelSelector{MatchLabels: map[string]string{"foo2": "bar2"}}
+	svcA := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svcA", Namespace: "nsA"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"foo1": "bar1"}},
+	}
+	svcB := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svcB", Namespace: "nsA"},
+	}
+	ipNet, _ := cidrStrToIPNet("10.0.0.0/24")
+	tests := []struct {
+		name             string
+		inputGroup       *corev1a2.ClusterGroup
+		expectedSelector *antreatypes.GroupSelector
+		expectedIPBlocks []controlplane.IPBlock
+	}{
+		{
+			name: "cg-with-selectors",
+			inputGroup: &corev1a2.ClusterGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+				Spec: corev1a2.GroupSpec{
+					PodSelector:       &selectorA,
+					NamespaceSelector: &selectorB,
+				},
+			},
+			expectedSelector: toGroupSelector("", &selectorA, &selectorB, nil),
+		},
+		{
+			name: "cg-with-ipblocks",
+			inputGroup: &corev1a2.ClusterGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+				Spec: corev1a2.GroupSpec{
+					IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}},
+				},
+			},
+			expectedIPBlocks: []controlplane.IPBlock{
+				{
+					CIDR:   *ipNet,
+					Except: []controlplane.IPNet{},
+				},
+			},
+		},
+		{
+			name: "cg-with-service-reference",
+			inputGroup: &corev1a2.ClusterGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+				Spec: corev1a2.GroupSpec{
+					ServiceReference: &corev1a2.ServiceReference{Name: "svcA", Namespace: "nsA"},
+				},
+			},
+			expectedSelector: toGroupSelector("nsA", &selectorA, nil, nil),
+		},
+		{
+			name: "cg-with-service-without-selector",
+			inputGroup: &corev1a2.ClusterGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+				Spec: corev1a2.GroupSpec{
+					ServiceReference: &corev1a2.ServiceReference{Name: "svcB", Namespace: "nsA"},
+				},
+			},
+		},
+		{
+			name: "cg-with-missing-service",
+			inputGroup: &corev1a2.ClusterGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+				Spec: corev1a2.GroupSpec{
+					ServiceReference: &corev1a2.ServiceReference{Name: "svcC", Namespace: "nsA"},
+				},
+			},
+		},
+		{
+			name: "missing-cg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newController()
+			c.serviceStore.Add(svcA)
+			c.serviceStore.Add(svcB)
+			if tt.inputGroup != nil {
+				c.cgStore.Add(tt.inputGroup)
+			}
+			actualSelector, actualIPBlocks := c.processClusterGroup("cgA")
+			assert.Equal(t, tt.expectedSelector, actualSelector)
+			assert.Equal(t, tt.expectedIPBlocks, actualIPBlocks)
+		})
+	}
+}
+
+func TestProcessClusterNetworkPolicyWithClusterGroup(t *testing.T) {
+	p10 := float64(10)
+	allowAction := secv1alpha1.RuleActionAllow
+	selectorA := metav1.LabelSelector{MatchLabels: map[string]string{"foo1": "bar1"}}
+	selectorB := metav1.LabelSelector{MatchLabels: map[string]string{"foo2": "bar2"}}
+	cgA := &corev1a2.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+		Spec:       corev1a2.GroupSpec{PodSelector: &selectorA},
+	}
+	cgB := &corev1a2.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cgB"},
+		Spec:       corev1a2.GroupSpec{IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}}},
+	}
+	cgC := &corev1a2.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cgC"},
+		Spec:       corev1a2.GroupSpec{NamespaceSelector: &selectorB},
+	}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{
+				{Group: "cgA"},
+			},
+			Priority: p10,
+			Ingress: []secv1alpha1.Rule{
+				{
+					From: []secv1alpha1.NetworkPolicyPeer{
+						{Group: "cgB"},
+						{Group: "cgC"},
+					},
+					Action: &allowAction,
+				},
+			},
+			Egress: []secv1alpha1.Rule{
+				{
+					To: []secv1alpha1.NetworkPolicyPeer{
+						{Group: "cgD"},
+					},
+					Action: &allowAction,
+				},
+			},
+		},
+	}
+	_, c := newController()
+	c.cgStore.Add(cgA)
+	c.cgStore.Add(cgB)
+	c.cgStore.Add(cgC)
+	ipNet, _ := cidrStrToIPNet("10.0.0.0/24")
+	expectedPolicy := &antreatypes.NetworkPolicy{
+		UID:       "uidA",
+		Name:      "cnpA",
+		Namespace: "",
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type: controlplane.AntreaClusterNetworkPolicy,
+			Name: "cnpA",
+			UID:  "uidA",
+		},
+		Priority:     &p10,
+		TierPriority: &defaultTierPriority,
+		Rules: []controlplane.NetworkPolicyRule{
+			{
+				Direction: controlplane.DirectionIn,
+				From: controlplane.NetworkPolicyPeer{
+					AddressGroups: []string{getNormalizedUID(toGroupSelector("", nil, &selectorB, nil).NormalizedName)},
+					IPBlocks:      []controlplane.IPBlock{{CIDR: *ipNet, Except: []controlplane.IPNet{}}},
+				},
+				Priority: 0,
+				Action:   &allowAction,
+			},
+			{
+				// cgD doesn't exist, hence the rule doesn't match any peer.
+				Direction: controlplane.DirectionOut,
+				To:        controlplane.NetworkPolicyPeer{},
+				Priority:  0,
+				Action:    &allowAction,
+			},
+		},
+		AppliedToGroups: []string{getNormalizedUID(toGroupSelector("", &selectorA, nil, nil).NormalizedName)},
+	}
+	assert.Equal(t, expectedPolicy, c.processClusterNetworkPolicy(cnp))
+	assert.Equal(t, 1, len(c.addressGroupStore.List()))
+	assert.Equal(t, 1, len(c.appliedToGroupStore.List()))
+}
+
+func TestGetClusterGroupMembers(t *testing.T) {
+	selectorA := metav1.LabelSelector{MatchLabels: map[string]string{"foo1": "bar1"}}
+	podA := getPod("podA", "nsA", "node1", "1.1.1.1", false)
+	podA.Labels = map[string]string{"foo1": "bar1"}
+	podB := getPod("podB", "nsA", "node1", "1.1.1.2", false)
+	podB.Labels = map[string]string{"foo1": "bar1"}
+	podB.Status.PodIP = ""
+	podC := getPod("podC", "nsB", "node1", "1.1.1.3", false)
+	_, c := newController()
+	c.podStore.Add(podA)
+	c.podStore.Add(podB)
+	c.podStore.Add(podC)
+	c.cgStore.Add(&corev1a2.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cgA"},
+		Spec:       corev1a2.GroupSpec{PodSelector: &selectorA},
+	})
+	c.cgStore.Add(&corev1a2.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cgB"},
+		Spec:       corev1a2.GroupSpec{IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}}},
+	})
+
+	members, ipBlocks, err := c.GetClusterGroupMembers("cgA")
+	require.NoError(t, err)
+	assert.Empty(t, ipBlocks)
+	expectedMembers := controlplane.NewGroupMemberSet(podToMemberPod(podA, true, true).ToGroupMember())
+	assert.True(t, expectedMembers.Equal(members))
+
+	members, ipBlocks, err = c.GetClusterGroupMembers("cgB")
+	require.NoError(t, err)
+	assert.Empty(t, members)
+	ipNet, _ := cidrStrToIPNet("10.0.0.0/24")
+	assert.Equal(t, []controlplane.IPBlock{{CIDR: *ipNet, Except: []controlplane.IPNet{}}}, ipBlocks)
+
+	_, _, err = c.GetClusterGroupMembers("cgC")
+	assert.Error(t, err)
+}
@@ -15,6 +15,11 @@
 package networkpolicy
 
 import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
 
@@ -116,6 +121,46 @@ func (n *NetworkPolicyController) deleteCNP(old interface{}) {
 	n.deleteDereferencedAddressGroups(oldInternalNP)
 }
 
+// reprocessCNP recomputes the internal NetworkPolicy of the given
+// ClusterNetworkPolicy when a ClusterGroup referred by it, or the Namespaces
+// its per-Namespace rules are expanded to, have changed.
+func (n *NetworkPolicyController) reprocessCNP(cnp *secv1alpha1.ClusterNetworkPolicy) {
+	key, _ := keyFunc(cnp)
+	if _, found, _ := n.internalNetworkPolicyStore.Get(key); !found {
+		// The ClusterNetworkPolicy ADD event has not been processed yet, it
+		// will be processed with the latest ClusterGroups and Namespaces then.
+		return
+	}
+	curInternalNP := n.processClusterNetworkPolicy(cnp)
+	n.internalNetworkPolicyMutex.Lock()
+	oldInternalNPObj, found, _ := n.internalNetworkPolicyStore.Get(key)
+	if !found {
+		// The ClusterNetworkPolicy has been deleted in the meantime.
+		n.internalNetworkPolicyMutex.Unlock()
+		return
+	}
+	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
+	// Must preserve old internal NetworkPolicy Span.
+	curInternalNP.SpanMeta = oldInternalNP.SpanMeta
+	n.internalNetworkPolicyStore.Update(curInternalNP)
+	n.internalNetworkPolicyMutex.Unlock()
+	klog.V(2).Infof("Reprocessed internal NetworkPolicy %s", curInternalNP.Name)
+	// Enqueue addressGroup keys to update their Node span.
+	for _, rule := range curInternalNP.Rules {
+		for _, addrGroupName := range rule.From.AddressGroups {
+			n.enqueueAddressGroup(addrGroupName)
+		}
+		for _, addrGroupName := range rule.To.AddressGroups {
+			n.enqueueAddressGroup(addrGroupName)
+		}
+	}
+	n.enqueueInternalNetworkPolicy(key)
+	for _, atg := range oldInternalNP.AppliedToGroups {
+		n.deleteDereferencedAppliedToGroup(atg)
+	}
+	n.deleteDereferencedAddressGroups(oldInternalNP)
+}
+
 // processClusterNetworkPolicy creates an internal NetworkPolicy instance
 // corresponding to the secv1alpha1.ClusterNetworkPolicy object. This method
 // does not commit the internal NetworkPolicy in store, instead returns an
@@ -123,36 +168,100 @@ func (n *NetworkPolicyController) deleteCNP(old interface{}) {
 // in case of ADD event or modified and store the updated instance, in case
 // of an UPDATE event.
 func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *secv1alpha1.ClusterNetworkPolicy) *antreatypes.NetworkPolicy {
-	appliedToGroupNames := make([]string, 0, len(cnp.Spec.AppliedTo))
-	// Create AppliedToGroup for each AppliedTo present in
+	appliedToSelectors := make([]*antreatypes.GroupSelector, 0, len(cnp.Spec.AppliedTo))
+	// Compute the GroupSelector of each AppliedTo present in
 	// ClusterNetworkPolicy spec.
 	for _, at := range cnp.Spec.AppliedTo {
-		appliedToGroupNames = append(appliedToGroupNames, n.createAppliedToGroup("", at.PodSelector, at.NamespaceSelector, at.ExternalEntitySelector))
+		if at.Group != "" {
+			// IPBlocks of the ClusterGroup are ignored as they cannot be
+			// used in AppliedTo.
+			groupSelector, _ := n.processClusterGroup(at.Group)
+			if groupSelector != nil {
+				appliedToSelectors = append(appliedToSelectors, groupSelector)
+			}
+			continue
+		}
+		if at.ServiceAccount != nil {
+			appliedToSelectors = append(appliedToSelectors, toGroupSelectorForServiceAccount(serviceAccountNamespace(at.ServiceAccount, cnp), at.ServiceAccount.Name))
+			continue
+		}
+		appliedToSelectors = append(appliedToSelectors, toGroupSelector("", at.PodSelector, at.NamespaceSelector, at.ExternalEntitySelector))
+	}
+	// Create AppliedToGroup for each AppliedTo present in
+	// ClusterNetworkPolicy spec.
+	appliedToGroupNames := n.createAppliedToGroupsForSelectors(appliedToSelectors)
+	// allAppliedToGroupNames includes the AppliedToGroups of the rules which
+	// are expanded per Namespace, so that the span of the internal
+	// NetworkPolicy and the references to the AppliedToGroups are complete.
+	allAppliedToGroupNames := sets.NewString(appliedToGroupNames...)
+	perNamespace := hasPerNamespaceRule(cnp)
+	var namespaces []string
+	var appliedToSelectorsByNamespace map[string][]*antreatypes.GroupSelector
+	if perNamespace {
+		appliedToSelectorsByNamespace = n.getAppliedToSelectorsByNamespace(appliedToSelectors)
+		for ns := range appliedToSelectorsByNamespace {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
 	}
 	rules := make([]controlplane.NetworkPolicyRule, 0, len(cnp.Spec.Ingress)+len(cnp.Spec.Egress))
-	// Compute NetworkPolicyRule for Egress Rule.
+	// addRule appends the given rule after computing its peer. A rule whose
+	// peers refer to the Namespace of the workloads it applies to is expanded
+	// into one rule per Namespace, each of which only applies to the workloads
+	// in that Namespace.
+	addRule := func(rule controlplane.NetworkPolicyRule, peers []secv1alpha1.NetworkPolicyPeer, namedPortExists bool) {
+		setPeer := func(rule *controlplane.NetworkPolicyRule, peer *controlplane.NetworkPolicyPeer) {
+			if rule.Direction == controlplane.DirectionIn {
+				rule.From = *peer
+			} else {
+				rule.To = *peer
+			}
+		}
+		if !hasSelfNamespacePeer(peers) {
+			setPeer(&rule, n.toAntreaPeerForCRD(peers, cnp, rule.Direction, namedPortExists))
+			if perNamespace {
+				// The AppliedToGroups of the internal NetworkPolicy include
+				// the ones of the per-Namespace rules, hence they must be
+				// set explicitly for this rule.
+				rule.AppliedToGroups = appliedToGroupNames
+			}
+			rules = append(rules, rule)
+			return
+		}
+		for _, ns := range namespaces {
+			nsRule := rule
+			nsRule.AppliedToGroups = n.createAppliedToGroupsForSelectors(appliedToSelectorsByNamespace[ns])
+			allAppliedToGroupNames.Insert(nsRule.AppliedToGroups...)
+			setPeer(&nsRule, n.toAntreaPeerForNamespace(peers, ns, cnp, rule.Direction, namedPortExists))
+			rules = append(rules, nsRule)
+		}
+	}
+	// Compute NetworkPolicyRule for Ingress Rule.
 	for idx, ingressRule := range cnp.Spec.Ingress {
 		// Set default action to ALLOW to allow traffic.
 		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports)
-		rules = append(rules, controlplane.NetworkPolicyRule{
-			Direction: controlplane.DirectionIn,
-			From:      *n.toAntreaPeerForCRD(ingressRule.From, cnp, controlplane.DirectionIn, namedPortExists),
-			Services:  services,
-			Action:    ingressRule.Action,
-			Priority:  int32(idx),
-		})
+		addRule(controlplane.NetworkPolicyRule{
+			Direction:     controlplane.DirectionIn,
+			Services:      services,
+			Action:        ingressRule.Action,
+			Priority:      int32(idx),
+			EnableLogging: ingressRule.EnableLogging,
+		}, ingressRule.From, namedPortExists)
 	}
 	// Compute NetworkPolicyRule for Egress Rule.
 	for idx, egressRule := range cnp.Spec.Egress {
 		// Set default action to ALLOW to allow traffic.
 		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports)
-		rules = append(rules, controlplane.NetworkPolicyRule{
-			Direction: controlplane.DirectionOut,
-			To:        *n.toAntreaPeerForCRD(egressRule.To, cnp, controlplane.DirectionOut, namedPortExists),
-			Services:  services,
-			Action:    egressRule.Action,
-			Priority:  int32(idx),
-		})
+		addRule(controlplane.NetworkPolicyRule{
+			Direction:     controlplane.DirectionOut,
+			Services:      services,
+			Action:        egressRule.Action,
+			Priority:      int32(idx),
+			EnableLogging: egressRule.EnableLogging,
+		}, egressRule.To, namedPortExists)
+	}
+	if perNamespace {
+		appliedToGroupNames = allAppliedToGroupNames.List()
 	}
 	tierPriority := n.getTierPriority(cnp.Spec.Tier)
 	internalNetworkPolicy := &antreatypes.NetworkPolicy{
@@ -164,6 +273,7 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *secv1alpha1.C
 			UID:  cnp.UID,
 		},
 		UID:             cnp.UID,
+		Generation:      cnp.Generation,
 		AppliedToGroups: appliedToGroupNames,
 		Rules:           rules,
 		Priority:        &cnp.Spec.Priority,
@@ -171,3 +281,102 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *secv1alpha1.C
 	}
 	return internalNetworkPolicy
 }
+
+// createAppliedToGroupsForSelectors creates an AppliedToGroup for each of the
+// given GroupSelectors and returns their names.
+func (n *NetworkPolicyController) createAppliedToGroupsForSelectors(groupSelectors []*antreatypes.GroupSelector) []string {
+	appliedToGroupNames := make([]string, 0, len(groupSelectors))
+	for _, groupSelector := range groupSelectors {
+		appliedToGroupNames = append(appliedToGroupNames, n.createAppliedToGroupForSelector(groupSelector))
+	}
+	return appliedToGroupNames
+}
+
+// getAppliedToSelectorsByNamespace splits the given AppliedTo GroupSelectors
+// by Namespace. For each Namespace which may contain the selected workloads, it
+// returns the GroupSelectors which only select the workloads in that Namespace.
+func (n *NetworkPolicyController) getAppliedToSelectorsByNamespace(groupSelectors []*antreatypes.GroupSelector) map[string][]*antreatypes.GroupSelector {
+	selectorsByNamespace := map[string][]*antreatypes.GroupSelector{}
+	for _, groupSelector := range groupSelectors {
+		if groupSelector.Namespace != "" {
+			selectorsByNamespace[groupSelector.Namespace] = append(selectorsByNamespace[groupSelector.Namespace], groupSelector)
+			continue
+		}
+		nsSelector := groupSelector.NamespaceSelector
+		if nsSelector == nil {
+			nsSelector = labels.Everything()
+		}
+		namespaces, _ := n.namespaceLister.List(nsSelector)
+		for _, ns := range namespaces {
+			nsGroupSelector := &antreatypes.GroupSelector{
+				Namespace:              ns.Name,
+				PodSelector:            groupSelector.PodSelector,
+				ExternalEntitySelector: groupSelector.ExternalEntitySelector,
+			}
+			if nsGroupSelector.PodSelector == nil && nsGroupSelector.ExternalEntitySelector == nil {
+				// A NamespaceSelector alone selects all the Pods in the
+				// Namespaces.
+				nsGroupSelector.PodSelector = labels.Everything()
+			}
+			nsGroupSelector.NormalizedName = generateNormalizedName(ns.Name, nsGroupSelector.PodSelector, nil, nsGroupSelector.ExternalEntitySelector)
+			selectorsByNamespace[ns.Name] = append(selectorsByNamespace[ns.Name], nsGroupSelector)
+		}
+	}
+	return selectorsByNamespace
+}
+
+// toAntreaPeerForNamespace converts the peers of a rule which is expanded for
+// the given Namespace to an Antrea NetworkPolicyPeer. The peers matching the
+// Namespace of the workloads the rule applies to select the workloads in the
+// given Namespace.
+func (n *NetworkPolicyController) toAntreaPeerForNamespace(peers []secv1alpha1.NetworkPolicyPeer, namespace string,
+	cnp *secv1alpha1.ClusterNetworkPolicy, dir controlplane.Direction, namedPortExists bool) *controlplane.NetworkPolicyPeer {
+	var addressGroups []string
+	var otherPeers []secv1alpha1.NetworkPolicyPeer
+	for _, peer := range peers {
+		if peer.Namespaces == nil {
+			otherPeers = append(otherPeers, peer)
+			continue
+		}
+		podSelector := peer.PodSelector
+		if podSelector == nil && peer.ExternalEntitySelector == nil {
+			podSelector = &metav1.LabelSelector{}
+		}
+		addressGroups = append(addressGroups, n.createAddressGroupForSelector(toGroupSelector(namespace, podSelector, nil, peer.ExternalEntitySelector)))
+	}
+	antreaPeer := &controlplane.NetworkPolicyPeer{}
+	// An empty list of peers matches all addresses, hence the other peers are
+	// only converted when there is any.
+	if len(otherPeers) > 0 {
+		antreaPeer = n.toAntreaPeerForCRD(otherPeers, cnp, dir, namedPortExists)
+	}
+	antreaPeer.AddressGroups = append(addressGroups, antreaPeer.AddressGroups...)
+	return antreaPeer
+}
+
+// hasSelfNamespacePeer returns true if any of the given peers matches the
+// Namespace of the workloads the rule applies to.
+func hasSelfNamespacePeer(peers []secv1alpha1.NetworkPolicyPeer) bool {
+	for _, peer := range peers {
+		if peer.Namespaces != nil && peer.Namespaces.Match == secv1alpha1.NamespaceMatchSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPerNamespaceRule returns true if any rule of the given
+// ClusterNetworkPolicy must be expanded per Namespace.
+func hasPerNamespaceRule(cnp *secv1alpha1.ClusterNetworkPolicy) bool {
+	for _, rule := range cnp.Spec.Ingress {
+		if hasSelfNamespacePeer(rule.From) {
+			return true
+		}
+	}
+	for _, rule := range cnp.Spec.Egress {
+		if hasSelfNamespacePeer(rule.To) {
+			return true
+		}
+	}
+	return false
+}
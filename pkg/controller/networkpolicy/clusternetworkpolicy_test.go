@@ -18,8 +18,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
@@ -324,6 +326,87 @@ func TestProcessClusterNetworkPolicy(t *testing.T) {
 	}
 }
 
+func TestProcessClusterNetworkPolicyWithSelfNamespacePeer(t *testing.T) {
+	p10 := float64(10)
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	selectorIsolated := metav1.LabelSelector{MatchLabels: map[string]string{"isolated": "true"}}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{
+				{NamespaceSelector: &selectorIsolated},
+			},
+			Priority: p10,
+			Ingress: []secv1alpha1.Rule{
+				{
+					From: []secv1alpha1.NetworkPolicyPeer{
+						{Namespaces: &secv1alpha1.PeerNamespaces{Match: secv1alpha1.NamespaceMatchSelf}},
+					},
+					Action: &allowAction,
+				},
+				{
+					From: []secv1alpha1.NetworkPolicyPeer{
+						{NamespaceSelector: &metav1.LabelSelector{}},
+					},
+					Action: &dropAction,
+				},
+			},
+		},
+	}
+	_, c := newController()
+	c.namespaceStore.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"isolated": "true"}}})
+	c.namespaceStore.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", Labels: map[string]string{"isolated": "true"}}})
+	c.namespaceStore.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns3"}})
+
+	// The AppliedToGroup and the AddressGroup of each Namespace share the
+	// same selector.
+	ns1GroupUID := getNormalizedUID(toGroupSelector("ns1", &metav1.LabelSelector{}, nil, nil).NormalizedName)
+	ns2GroupUID := getNormalizedUID(toGroupSelector("ns2", &metav1.LabelSelector{}, nil, nil).NormalizedName)
+	policyAppliedToGroupUID := getNormalizedUID(toGroupSelector("", nil, &selectorIsolated, nil).NormalizedName)
+	expectedPolicy := &antreatypes.NetworkPolicy{
+		UID:       "uidA",
+		Name:      "cnpA",
+		Namespace: "",
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type: controlplane.AntreaClusterNetworkPolicy,
+			Name: "cnpA",
+			UID:  "uidA",
+		},
+		Priority:     &p10,
+		TierPriority: &defaultTierPriority,
+		Rules: []controlplane.NetworkPolicyRule{
+			{
+				Direction:       controlplane.DirectionIn,
+				From:            controlplane.NetworkPolicyPeer{AddressGroups: []string{ns1GroupUID}},
+				Priority:        0,
+				Action:          &allowAction,
+				AppliedToGroups: []string{ns1GroupUID},
+			},
+			{
+				Direction:       controlplane.DirectionIn,
+				From:            controlplane.NetworkPolicyPeer{AddressGroups: []string{ns2GroupUID}},
+				Priority:        0,
+				Action:          &allowAction,
+				AppliedToGroups: []string{ns2GroupUID},
+			},
+			{
+				Direction: controlplane.DirectionIn,
+				From: controlplane.NetworkPolicyPeer{
+					AddressGroups: []string{getNormalizedUID(toGroupSelector("", nil, &metav1.LabelSelector{}, nil).NormalizedName)},
+				},
+				Priority:        1,
+				Action:          &dropAction,
+				AppliedToGroups: []string{policyAppliedToGroupUID},
+			},
+		},
+		AppliedToGroups: sets.NewString(ns1GroupUID, ns2GroupUID, policyAppliedToGroupUID).List(),
+	}
+	assert.Equal(t, expectedPolicy, c.processClusterNetworkPolicy(cnp))
+	assert.Equal(t, 3, len(c.addressGroupStore.List()))
+	assert.Equal(t, 3, len(c.appliedToGroupStore.List()))
+}
+
 func TestAddCNP(t *testing.T) {
 	p10 := float64(10)
 	emergencyTierPriority := int32(1)
@@ -48,6 +48,9 @@ func toAntreaServicesForCRD(npPorts []secv1alpha1.NetworkPolicyPort) ([]controlp
 		antreaService := controlplane.Service{
 			Protocol: toAntreaProtocol(npPort.Protocol),
 			Port:     npPort.Port,
+			EndPort:  npPort.EndPort,
+			ICMPType: npPort.ICMPType,
+			ICMPCode: npPort.ICMPCode,
 		}
 		antreaServices = append(antreaServices, antreaService)
 	}
@@ -90,22 +93,37 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []secv1alpha1.Network
 		return &podsPeer
 	}
 	var ipBlocks []controlplane.IPBlock
+	var fqdns []string
 	for _, peer := range peers {
-		// A secv1alpha1.NetworkPolicyPeer will either have an IPBlock or a
-		// podSelector and/or namespaceSelector set.
-		if peer.IPBlock != nil {
+		// A secv1alpha1.NetworkPolicyPeer will either have an IPBlock, a
+		// Group, an FQDN, a ServiceAccount or a podSelector and/or
+		// namespaceSelector set.
+		if peer.Group != "" {
+			groupSelector, cgIPBlocks := n.processClusterGroup(peer.Group)
+			if groupSelector != nil {
+				addressGroups = append(addressGroups, n.createAddressGroupForSelector(groupSelector))
+			}
+			ipBlocks = append(ipBlocks, cgIPBlocks...)
+		} else if peer.IPBlock != nil {
 			ipBlock, err := toAntreaIPBlockForCRD(peer.IPBlock)
 			if err != nil {
 				klog.Errorf("Failure processing Antrea NetworkPolicy %s/%s IPBlock %v: %v", np.GetNamespace(), np.GetName(), peer.IPBlock, err)
 				continue
 			}
 			ipBlocks = append(ipBlocks, *ipBlock)
+		} else if peer.FQDN != "" {
+			// FQDNs are resolved to IPs by the agents, which learn them
+			// from the DNS responses received by the Pods.
+			fqdns = append(fqdns, strings.ToLower(peer.FQDN))
+		} else if peer.ServiceAccount != nil {
+			groupSelector := toGroupSelectorForServiceAccount(serviceAccountNamespace(peer.ServiceAccount, np), peer.ServiceAccount.Name)
+			addressGroups = append(addressGroups, n.createAddressGroupForSelector(groupSelector))
 		} else {
 			normalizedUID := n.createAddressGroupForCRD(peer, np)
 			addressGroups = append(addressGroups, normalizedUID)
 		}
 	}
-	return &controlplane.NetworkPolicyPeer{AddressGroups: addressGroups, IPBlocks: ipBlocks}
+	return &controlplane.NetworkPolicyPeer{AddressGroups: addressGroups, IPBlocks: ipBlocks, FQDNs: fqdns}
 }
 
 // createAddressGroupForCRD creates an AddressGroup object corresponding to a
@@ -114,6 +132,22 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []secv1alpha1.Network
 // PodAddresses as the affected Pods are calculated during sync process.
 func (n *NetworkPolicyController) createAddressGroupForCRD(peer secv1alpha1.NetworkPolicyPeer, np metav1.Object) string {
 	groupSelector := toGroupSelector(np.GetNamespace(), peer.PodSelector, peer.NamespaceSelector, peer.ExternalEntitySelector)
+	return n.createAddressGroupForSelector(groupSelector)
+}
+
+// serviceAccountNamespace returns the Namespace of the ServiceAccount referred
+// in an Antrea NetworkPolicy or ClusterNetworkPolicy, which defaults to the
+// Namespace of the policy.
+func serviceAccountNamespace(sa *secv1alpha1.NamespacedName, np metav1.Object) string {
+	if sa.Namespace != "" {
+		return sa.Namespace
+	}
+	return np.GetNamespace()
+}
+
+// createAddressGroupForSelector creates an AddressGroup object for the given
+// GroupSelector if it is not created already.
+func (n *NetworkPolicyController) createAddressGroupForSelector(groupSelector *antreatypes.GroupSelector) string {
 	normalizedUID := getNormalizedUID(groupSelector.NormalizedName)
 	// Get or create an AddressGroup for the generated UID.
 	_, found, _ := n.addressGroupStore.Get(normalizedUID)
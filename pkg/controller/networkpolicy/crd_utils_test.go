@@ -28,6 +28,8 @@ import (
 )
 
 func TestToAntreaServicesForCRD(t *testing.T) {
+	k8sProtocolICMP := secv1alpha1.ProtocolICMP
+	int32For0, int32For8, int32For1000 := int32(0), int32(8), int32(1000)
 	tables := []struct {
 		ports              []secv1alpha1.NetworkPolicyPort
 		expServices        []controlplane.Service
@@ -63,6 +65,33 @@ func TestToAntreaServicesForCRD(t *testing.T) {
 			},
 			expNamedPortExists: true,
 		},
+		{
+			ports: []secv1alpha1.NetworkPolicyPort{
+				{
+					Protocol: &k8sProtocolTCP,
+					Port:     &int80,
+					EndPort:  &int32For1000,
+				},
+				{
+					Protocol: &k8sProtocolICMP,
+					ICMPType: &int32For8,
+					ICMPCode: &int32For0,
+				},
+			},
+			expServices: []controlplane.Service{
+				{
+					Protocol: toAntreaProtocol(&k8sProtocolTCP),
+					Port:     &int80,
+					EndPort:  &int32For1000,
+				},
+				{
+					Protocol: toAntreaProtocol(&k8sProtocolICMP),
+					ICMPType: &int32For8,
+					ICMPCode: &int32For0,
+				},
+			},
+			expNamedPortExists: false,
+		},
 	}
 	for _, table := range tables {
 		services, namedPortExist := toAntreaServicesForCRD(table.ports)
@@ -210,6 +239,21 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 			},
 			direction: controlplane.DirectionOut,
 		},
+		{
+			name: "fqdn-peer-egress",
+			inPeers: []secv1alpha1.NetworkPolicyPeer{
+				{
+					FQDN: "WWW.Example.com",
+				},
+				{
+					FQDN: "*.example.org",
+				},
+			},
+			outPeer: controlplane.NetworkPolicyPeer{
+				FQDNs: []string{"www.example.com", "*.example.org"},
+			},
+			direction: controlplane.DirectionOut,
+		},
 		{
 			name:      "empty-peer-ingress",
 			inPeers:   []secv1alpha1.NetworkPolicyPeer{},
@@ -240,6 +284,9 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 			if len(tt.outPeer.IPBlocks) != len((*actualPeer).IPBlocks) {
 				t.Errorf("Unexpected number of IPBlocks in Antrea Peer conversion. Expected %v, got %v", len(tt.outPeer.IPBlocks), len((*actualPeer).IPBlocks))
 			}
+			if !reflect.DeepEqual(tt.outPeer.FQDNs, (*actualPeer).FQDNs) {
+				t.Errorf("Unexpected FQDNs in Antrea Peer conversion. Expected %v, got %v", tt.outPeer.FQDNs, (*actualPeer).FQDNs)
+			}
 			for i := 0; i < len(tt.outPeer.IPBlocks); i++ {
 				if !compareIPBlocks(&(tt.outPeer.IPBlocks[i]), &((*actualPeer).IPBlocks[i])) {
 					t.Errorf("Unexpected IPBlocks in Antrea Peer conversion. Expected %v, got %v", tt.outPeer.IPBlocks[i], (*actualPeer).IPBlocks[i])
@@ -31,6 +31,7 @@ import (
 	uuid "github.com/satori/go.uuid"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -48,17 +49,21 @@ import (
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
 	"github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	corev1a2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/storage"
 	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
 	corev1a1informers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha1"
+	corev1a2informers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha2"
 	secinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/security/v1alpha1"
 	corev1a1listers "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha1"
+	corev1a2listers "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha2"
 	seclisters "github.com/vmware-tanzu/antrea/pkg/client/listers/security/v1alpha1"
 	"github.com/vmware-tanzu/antrea/pkg/controller/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
 	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
 	"github.com/vmware-tanzu/antrea/pkg/features"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
 )
 
 const (
@@ -77,6 +82,16 @@ const (
 	TierIndex = "tier"
 	// PriorityIndex is used to index Tiers by their priorities.
 	PriorityIndex = "priority"
+	// ClusterGroupIndex is used to index ClusterNetworkPolicies by the names
+	// of the ClusterGroups they refer to.
+	ClusterGroupIndex = "clustergroup"
+	// PerNamespaceRuleIndex is used to index ClusterNetworkPolicies which
+	// have rules expanded per Namespace.
+	PerNamespaceRuleIndex = "perNamespaceRule"
+	// hasPerNamespaceRuleValue is the only indexed value of PerNamespaceRuleIndex.
+	hasPerNamespaceRuleValue = "true"
+	// ServiceIndex is used to index ClusterGroups by the Services they refer to.
+	ServiceIndex = "service"
 )
 
 var (
@@ -105,6 +120,12 @@ var (
 	defaultAction = secv1alpha1.RuleActionAllow
 )
 
+// perNamespaceCNPKey is the key of a ClusterNetworkPolicy with per-Namespace
+// rules in the internal NetworkPolicy queue. Processing it recomputes the
+// internal NetworkPolicy of the ClusterNetworkPolicy after the set of
+// Namespaces has changed.
+type perNamespaceCNPKey string
+
 // NetworkPolicyController is responsible for synchronizing the Namespaces and Pods
 // affected by a Network Policy.
 type NetworkPolicyController struct {
@@ -162,6 +183,20 @@ type NetworkPolicyController struct {
 	// tierListerSynced is a function which returns true if the Tiers shared informer has been synced at least once.
 	tierListerSynced cache.InformerSynced
 
+	cgInformer corev1a2informers.ClusterGroupInformer
+	// cgLister is able to list/get ClusterGroups and is populated by the shared informer passed to
+	// NewNetworkPolicyController.
+	cgLister corev1a2listers.ClusterGroupLister
+	// cgListerSynced is a function which returns true if the ClusterGroup shared informer has been synced at least once.
+	cgListerSynced cache.InformerSynced
+
+	serviceInformer coreinformers.ServiceInformer
+	// serviceLister is able to list/get Services and is populated by the shared informer passed to
+	// NewNetworkPolicyController.
+	serviceLister corelisters.ServiceLister
+	// serviceListerSynced is a function which returns true if the Service shared informer has been synced at least once.
+	serviceListerSynced cache.InformerSynced
+
 	// addressGroupStore is the storage where the populated Address Groups are stored.
 	addressGroupStore storage.Interface
 	// appliedToGroupStore is the storage where the populated AppliedTo Groups are stored.
@@ -198,11 +233,13 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 	crdClient versioned.Interface,
 	podInformer coreinformers.PodInformer,
 	namespaceInformer coreinformers.NamespaceInformer,
+	serviceInformer coreinformers.ServiceInformer,
 	externalEntityInformer corev1a1informers.ExternalEntityInformer,
 	networkPolicyInformer networkinginformers.NetworkPolicyInformer,
 	cnpInformer secinformers.ClusterNetworkPolicyInformer,
 	anpInformer secinformers.NetworkPolicyInformer,
 	tierInformer secinformers.TierInformer,
+	cgInformer corev1a2informers.ClusterGroupInformer,
 	addressGroupStore storage.Interface,
 	appliedToGroupStore storage.Interface,
 	internalNetworkPolicyStore storage.Interface) *NetworkPolicyController {
@@ -266,6 +303,12 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 		n.tierInformer = tierInformer
 		n.tierLister = tierInformer.Lister()
 		n.tierListerSynced = tierInformer.Informer().HasSynced
+		n.cgInformer = cgInformer
+		n.cgLister = cgInformer.Lister()
+		n.cgListerSynced = cgInformer.Informer().HasSynced
+		n.serviceInformer = serviceInformer
+		n.serviceLister = serviceInformer.Lister()
+		n.serviceListerSynced = serviceInformer.Informer().HasSynced
 		tierInformer.Informer().AddIndexers(
 			cache.Indexers{
 				PriorityIndex: func(obj interface{}) ([]string, error) {
@@ -286,6 +329,20 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 					}
 					return []string{cnp.Spec.Tier}, nil
 				},
+				ClusterGroupIndex: func(obj interface{}) ([]string, error) {
+					cnp, ok := obj.(*secv1alpha1.ClusterNetworkPolicy)
+					if !ok {
+						return []string{}, nil
+					}
+					return getClusterGroupNames(cnp).List(), nil
+				},
+				PerNamespaceRuleIndex: func(obj interface{}) ([]string, error) {
+					cnp, ok := obj.(*secv1alpha1.ClusterNetworkPolicy)
+					if !ok || !hasPerNamespaceRule(cnp) {
+						return []string{}, nil
+					}
+					return []string{hasPerNamespaceRuleValue}, nil
+				},
 			},
 		)
 		cnpInformer.Informer().AddEventHandlerWithResyncPeriod(
@@ -323,6 +380,33 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 			},
 			resyncPeriod,
 		)
+		cgInformer.Informer().AddIndexers(
+			cache.Indexers{
+				ServiceIndex: func(obj interface{}) ([]string, error) {
+					cg, ok := obj.(*corev1a2.ClusterGroup)
+					if !ok || cg.Spec.ServiceReference == nil {
+						return []string{}, nil
+					}
+					return []string{k8s.NamespacedName(cg.Spec.ServiceReference.Namespace, cg.Spec.ServiceReference.Name)}, nil
+				},
+			},
+		)
+		cgInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addClusterGroup,
+				UpdateFunc: n.updateClusterGroup,
+				DeleteFunc: n.deleteClusterGroup,
+			},
+			resyncPeriod,
+		)
+		serviceInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addService,
+				UpdateFunc: n.updateService,
+				DeleteFunc: n.deleteService,
+			},
+			resyncPeriod,
+		)
 	}
 	return n
 }
@@ -381,6 +465,17 @@ func toGroupSelector(namespace string, podSelector, nsSelector, extEntitySelecto
 	return &groupSelector
 }
 
+// toGroupSelectorForServiceAccount converts the Namespace and name of a
+// ServiceAccount to a networkpolicy.GroupSelector object which selects the Pods
+// running with the ServiceAccount.
+func toGroupSelectorForServiceAccount(namespace, name string) *antreatypes.GroupSelector {
+	return &antreatypes.GroupSelector{
+		NormalizedName:     fmt.Sprintf("namespace=%s And serviceAccount=%s", namespace, name),
+		Namespace:          namespace,
+		ServiceAccountName: name,
+	}
+}
+
 // getNormalizedUID generates a unique UUID based on a given string.
 // For example, it can be used to generate keys using normalized selectors
 // unique within the Namespace by adding the constant UID.
@@ -412,6 +507,12 @@ func generateNormalizedName(namespace string, podSelector, nsSelector, eeSelecto
 // createAppliedToGroup creates an AppliedToGroup object in store if it is not created already.
 func (n *NetworkPolicyController) createAppliedToGroup(npNsName string, pSel, nSel, eSel *metav1.LabelSelector) string {
 	groupSelector := toGroupSelector(npNsName, pSel, nSel, eSel)
+	return n.createAppliedToGroupForSelector(groupSelector)
+}
+
+// createAppliedToGroupForSelector creates an AppliedToGroup object for the
+// given GroupSelector in store if it is not created already.
+func (n *NetworkPolicyController) createAppliedToGroupForSelector(groupSelector *antreatypes.GroupSelector) string {
 	appliedToGroupUID := getNormalizedUID(groupSelector.NormalizedName)
 	// Get or create a AppliedToGroup for the generated UID.
 	// Ignoring returned error (here and elsewhere in this file) as with the
@@ -435,7 +536,15 @@ func (n *NetworkPolicyController) createAppliedToGroup(npNsName string, pSel, nS
 // labelsMatchGroupSelector matches an ExternalEntity or Pod's labels to the
 // GroupSelector object and returns true, if and only if the labels
 // match any of the selector criteria present in the GroupSelector.
+// A GroupSelector with a ServiceAccountName matches Pods by their
+// ServiceAccount instead of labels.
 func (n *NetworkPolicyController) labelsMatchGroupSelector(obj metav1.Object, ns *v1.Namespace, sel *antreatypes.GroupSelector) bool {
+	if sel.ServiceAccountName != "" {
+		// Only Pods run with a ServiceAccount, and they must be in the
+		// ServiceAccount's Namespace.
+		pod, ok := obj.(*v1.Pod)
+		return ok && pod.Namespace == sel.Namespace && pod.Spec.ServiceAccountName == sel.ServiceAccountName
+	}
 	objSelector := sel.PodSelector
 	if _, ok := obj.(*v1alpha1.ExternalEntity); ok {
 		objSelector = sel.ExternalEntitySelector
@@ -829,7 +938,7 @@ func (n *NetworkPolicyController) deleteNetworkPolicy(old interface{}) {
 }
 
 // addPod retrieves all AddressGroups and AppliedToGroups which match the Pod's
-// labels and enqueues the groups key for further processing.
+// labels or ServiceAccount and enqueues the groups key for further processing.
 func (n *NetworkPolicyController) addPod(obj interface{}) {
 	defer n.heartbeat("addPod")
 	pod := obj.(*v1.Pod)
@@ -856,7 +965,8 @@ func (n *NetworkPolicyController) updatePod(oldObj, curObj interface{}) {
 	curPod := curObj.(*v1.Pod)
 	klog.V(2).Infof("Processing Pod %s/%s UPDATE event, labels: %v", curPod.Namespace, curPod.Name, curPod.Labels)
 	// No need to trigger processing of groups if there is no change in the
-	// Pod labels or Pods Node or Pods IP.
+	// Pod labels or Pods Node or Pods IP. The Pod's ServiceAccount doesn't
+	// need to be compared as it cannot be updated.
 	labelsEqual := labels.Equals(labels.Set(oldPod.Labels), labels.Set(curPod.Labels))
 	if labelsEqual && oldPod.Spec.NodeName == curPod.Spec.NodeName && oldPod.Status.PodIP == curPod.Status.PodIP {
 		klog.V(4).Infof("No change in Pod %s/%s. Skipping NetworkPolicy evaluation.", curPod.Namespace, curPod.Name)
@@ -898,7 +1008,7 @@ func (n *NetworkPolicyController) updatePod(oldObj, curObj interface{}) {
 }
 
 // deletePod retrieves all AddressGroups and AppliedToGroups which match the Pod's
-// labels and enqueues the groups key for further processing.
+// labels or ServiceAccount and enqueues the groups key for further processing.
 func (n *NetworkPolicyController) deletePod(old interface{}) {
 	pod, ok := old.(*v1.Pod)
 	if !ok {
@@ -1044,6 +1154,7 @@ func (n *NetworkPolicyController) addNamespace(obj interface{}) {
 	for group := range addressGroupKeys {
 		n.enqueueAddressGroup(group)
 	}
+	n.triggerPerNamespaceCNPUpdates()
 }
 
 // updateNamespace retrieves all AddressGroups which match the current and old
@@ -1069,6 +1180,7 @@ func (n *NetworkPolicyController) updateNamespace(oldObj, curObj interface{}) {
 	for group := range addressGroupKeys {
 		n.enqueueAddressGroup(group)
 	}
+	n.triggerPerNamespaceCNPUpdates()
 }
 
 // deleteNamespace retrieves all AddressGroups which match the Namespace's
@@ -1096,6 +1208,43 @@ func (n *NetworkPolicyController) deleteNamespace(old interface{}) {
 	for group := range addressGroupKeys {
 		n.enqueueAddressGroup(group)
 	}
+	n.triggerPerNamespaceCNPUpdates()
+}
+
+// triggerPerNamespaceCNPUpdates reprocesses all the ClusterNetworkPolicies
+// which have rules expanded per Namespace, so that they reflect the current
+// set of Namespaces.
+func (n *NetworkPolicyController) triggerPerNamespaceCNPUpdates() {
+	if n.cnpInformer == nil {
+		return
+	}
+	cnps, err := n.cnpInformer.Informer().GetIndexer().ByIndex(PerNamespaceRuleIndex, hasPerNamespaceRuleValue)
+	if err != nil {
+		klog.Errorf("Error retrieving ClusterNetworkPolicies with per-Namespace rules: %v", err)
+		return
+	}
+	for _, obj := range cnps {
+		key, _ := keyFunc(obj)
+		klog.V(4).Infof("Adding ClusterNetworkPolicy %s with per-Namespace rules to internal NetworkPolicy queue", key)
+		n.internalNetworkPolicyQueue.Add(perNamespaceCNPKey(key))
+	}
+	metrics.LengthInternalNetworkPolicyQueue.Set(float64(n.internalNetworkPolicyQueue.Len()))
+}
+
+// syncPerNamespaceCNP recomputes the internal NetworkPolicy of the
+// ClusterNetworkPolicy with per-Namespace rules for the current set of
+// Namespaces.
+func (n *NetworkPolicyController) syncPerNamespaceCNP(key string) error {
+	cnp, err := n.cnpLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The ClusterNetworkPolicy has been deleted in the meantime.
+			return nil
+		}
+		return err
+	}
+	n.reprocessCNP(cnp)
+	return nil
 }
 
 func (n *NetworkPolicyController) enqueueAppliedToGroup(key string) {
@@ -1189,6 +1338,10 @@ func (n *NetworkPolicyController) Run(stopCh <-chan struct{}) {
 			klog.Error("Unable to sync ANP caches for NetworkPolicy controller")
 			return
 		}
+		if !cache.WaitForCacheSync(stopCh, n.cgListerSynced, n.serviceListerSynced) {
+			klog.Error("Unable to sync ClusterGroup caches for NetworkPolicy controller")
+			return
+		}
 	}
 	klog.Info("Caches are synced for NetworkPolicy controller")
 
@@ -1241,7 +1394,13 @@ func (n *NetworkPolicyController) processNextInternalNetworkPolicyWorkItem() boo
 	// on the workqueue and attempted again after a back-off period.
 	defer n.internalNetworkPolicyQueue.Done(key)
 
-	err := n.syncInternalNetworkPolicy(key.(string))
+	var err error
+	switch k := key.(type) {
+	case perNamespaceCNPKey:
+		err = n.syncPerNamespaceCNP(string(k))
+	case string:
+		err = n.syncInternalNetworkPolicy(k)
+	}
 	if err != nil {
 		// Put the item back on the workqueue to handle any transient errors.
 		n.internalNetworkPolicyQueue.AddRateLimited(key)
@@ -1389,6 +1548,12 @@ func podToMemberPod(pod *v1.Pod, includeIP, includePodRef bool) *controlplane.Gr
 
 	if includeIP {
 		memberPod.IP = ipStrToIPAddress(pod.Status.PodIP)
+		// Status.PodIPs has both the IPv4 and the IPv6 address of a dual-stack Pod.
+		if len(pod.Status.PodIPs) > 1 {
+			for _, podIP := range pod.Status.PodIPs {
+				memberPod.IPs = append(memberPod.IPs, ipStrToIPAddress(podIP.IP))
+			}
+		}
 	}
 
 	if includePodRef {
@@ -1429,7 +1594,16 @@ func externalEntityToGroupMember(ee *v1alpha1.ExternalEntity) *controlplane.Grou
 func (n *NetworkPolicyController) processSelector(groupSelector antreatypes.GroupSelector) ([]*v1.Pod, []*v1alpha1.ExternalEntity) {
 	var pods []*v1.Pod
 	var externalEntities []*v1alpha1.ExternalEntity
-	if groupSelector.Namespace != "" {
+	if groupSelector.ServiceAccountName != "" {
+		// Pods running with the ServiceAccount must be selected from the
+		// ServiceAccount's Namespace.
+		nsPods, _ := n.podLister.Pods(groupSelector.Namespace).List(labels.Everything())
+		for _, pod := range nsPods {
+			if pod.Spec.ServiceAccountName == groupSelector.ServiceAccountName {
+				pods = append(pods, pod)
+			}
+		}
+	} else if groupSelector.Namespace != "" {
 		// Namespace presence indicates Pods and ExternalEnitities must be selected from the same Namespace.
 		if groupSelector.PodSelector != nil {
 			pods, _ = n.podLister.Pods(groupSelector.Namespace).List(groupSelector.PodSelector)
@@ -70,6 +70,8 @@ type networkPolicyController struct {
 	networkPolicyStore         cache.Store
 	cnpStore                   cache.Store
 	tierStore                  cache.Store
+	cgStore                    cache.Store
+	serviceStore               cache.Store
 	appliedToGroupStore        storage.Interface
 	addressGroupStore          storage.Interface
 	internalNetworkPolicyStore storage.Interface
@@ -90,11 +92,13 @@ func newController(objects ...runtime.Object) (*fake.Clientset, *networkPolicyCo
 		crdClient,
 		informerFactory.Core().V1().Pods(),
 		informerFactory.Core().V1().Namespaces(),
+		informerFactory.Core().V1().Services(),
 		crdInformerFactory.Core().V1alpha1().ExternalEntities(),
 		informerFactory.Networking().V1().NetworkPolicies(),
 		crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(),
 		crdInformerFactory.Security().V1alpha1().NetworkPolicies(),
 		crdInformerFactory.Security().V1alpha1().Tiers(),
+		crdInformerFactory.Core().V1alpha2().ClusterGroups(),
 		addressGroupStore,
 		appliedToGroupStore,
 		internalNetworkPolicyStore)
@@ -104,6 +108,10 @@ func newController(objects ...runtime.Object) (*fake.Clientset, *networkPolicyCo
 	npController.cnpListerSynced = alwaysReady
 	npController.tierLister = crdInformerFactory.Security().V1alpha1().Tiers().Lister()
 	npController.tierListerSynced = alwaysReady
+	npController.cgLister = crdInformerFactory.Core().V1alpha2().ClusterGroups().Lister()
+	npController.cgListerSynced = alwaysReady
+	npController.serviceLister = informerFactory.Core().V1().Services().Lister()
+	npController.serviceListerSynced = alwaysReady
 	return client, &networkPolicyController{
 		npController,
 		informerFactory.Core().V1().Pods().Informer().GetStore(),
@@ -112,6 +120,8 @@ func newController(objects ...runtime.Object) (*fake.Clientset, *networkPolicyCo
 		informerFactory.Networking().V1().NetworkPolicies().Informer().GetStore(),
 		crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies().Informer().GetStore(),
 		crdInformerFactory.Security().V1alpha1().Tiers().Informer().GetStore(),
+		crdInformerFactory.Core().V1alpha2().ClusterGroups().Informer().GetStore(),
+		informerFactory.Core().V1().Services().Informer().GetStore(),
 		appliedToGroupStore,
 		addressGroupStore,
 		internalNetworkPolicyStore,
@@ -1834,11 +1844,20 @@ func TestFilterAddressGroupsForPodOrExternalEntity(t *testing.T) {
 		Name:     "AddrGrp4",
 		Selector: *toGroupSelector("", &selectorSpec, &selectorSpec, nil),
 	}
+	addrGrp5 := &antreatypes.AddressGroup{
+		UID:      "uid5",
+		Name:     "AddrGrp5",
+		Selector: *toGroupSelectorForServiceAccount("ns1", "sa1"),
+	}
 
 	pod1 := getPod("pod1", "ns1", "node1", "1.1.1.1", false)
 	pod1.Labels = map[string]string{"purpose": "test-select"}
 	pod2 := getPod("pod2", "ns1", "node1", "1.1.1.2", false)
 	pod3 := getPod("pod3", "ns2", "node1", "1.1.1.3", false)
+	pod4 := getPod("pod4", "ns1", "node1", "1.1.1.4", false)
+	pod4.Spec.ServiceAccountName = "sa1"
+	pod5 := getPod("pod5", "ns2", "node1", "1.1.1.5", false)
+	pod5.Spec.ServiceAccountName = "sa1"
 	ee1 := &v1alpha1.ExternalEntity{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "ee1",
@@ -1873,6 +1892,16 @@ func TestFilterAddressGroupsForPodOrExternalEntity(t *testing.T) {
 			pod3,
 			sets.String{},
 		},
+		{
+			"pod-unmatch-selector-match-ns-match-sa",
+			pod4,
+			sets.NewString("AddrGrp3", "AddrGrp5"),
+		},
+		{
+			"pod-unmatch-selector-unmatch-ns-match-sa-name",
+			pod5,
+			sets.String{},
+		},
 		{
 			"externalEntity-match-selector-match-ns",
 			ee1,
@@ -1889,6 +1918,7 @@ func TestFilterAddressGroupsForPodOrExternalEntity(t *testing.T) {
 	npc.addressGroupStore.Create(addrGrp2)
 	npc.addressGroupStore.Create(addrGrp3)
 	npc.addressGroupStore.Create(addrGrp4)
+	npc.addressGroupStore.Create(addrGrp5)
 	npc.namespaceStore.Add(ns1)
 	npc.namespaceStore.Add(ns2)
 
@@ -1938,11 +1968,20 @@ func TestFilterAppliedToGroupsForPodOrExternalEntity(t *testing.T) {
 		Name:     "ATGrp4",
 		Selector: *toGroupSelector("", &selectorSpec, &selectorSpec, nil),
 	}
+	atGrp5 := &antreatypes.AppliedToGroup{
+		UID:      "uid5",
+		Name:     "ATGrp5",
+		Selector: *toGroupSelectorForServiceAccount("ns1", "sa1"),
+	}
 
 	pod1 := getPod("pod1", "ns1", "node1", "1.1.1.1", false)
 	pod1.Labels = map[string]string{"purpose": "test-select"}
 	pod2 := getPod("pod2", "ns1", "node1", "1.1.1.2", false)
 	pod3 := getPod("pod3", "ns2", "node1", "1.1.1.3", false)
+	pod4 := getPod("pod4", "ns1", "node1", "1.1.1.4", false)
+	pod4.Spec.ServiceAccountName = "sa1"
+	pod5 := getPod("pod5", "ns2", "node1", "1.1.1.5", false)
+	pod5.Spec.ServiceAccountName = "sa1"
 	ee1 := &v1alpha1.ExternalEntity{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "ee1",
@@ -1977,6 +2016,16 @@ func TestFilterAppliedToGroupsForPodOrExternalEntity(t *testing.T) {
 			pod3,
 			sets.String{},
 		},
+		{
+			"pod-unmatch-selector-match-ns-match-sa",
+			pod4,
+			sets.NewString("ATGrp3", "ATGrp5"),
+		},
+		{
+			"pod-unmatch-selector-unmatch-ns-match-sa-name",
+			pod5,
+			sets.String{},
+		},
 		{
 			"externalEntity-match-selector-match-ns",
 			ee1,
@@ -1993,6 +2042,7 @@ func TestFilterAppliedToGroupsForPodOrExternalEntity(t *testing.T) {
 	npc.appliedToGroupStore.Create(atGrp2)
 	npc.appliedToGroupStore.Create(atGrp3)
 	npc.appliedToGroupStore.Create(atGrp4)
+	npc.appliedToGroupStore.Create(atGrp5)
 	npc.namespaceStore.Add(ns1)
 	npc.namespaceStore.Add(ns2)
 
@@ -2004,6 +2054,23 @@ func TestFilterAppliedToGroupsForPodOrExternalEntity(t *testing.T) {
 	}
 }
 
+func TestProcessSelectorForServiceAccount(t *testing.T) {
+	pod1 := getPod("pod1", "ns1", "node1", "1.1.1.1", false)
+	pod1.Spec.ServiceAccountName = "sa1"
+	pod2 := getPod("pod2", "ns1", "node1", "1.1.1.2", false)
+	pod2.Spec.ServiceAccountName = "sa2"
+	pod3 := getPod("pod3", "ns2", "node1", "1.1.1.3", false)
+	pod3.Spec.ServiceAccountName = "sa1"
+	_, npc := newController()
+	npc.podStore.Add(pod1)
+	npc.podStore.Add(pod2)
+	npc.podStore.Add(pod3)
+
+	pods, externalEntities := npc.processSelector(*toGroupSelectorForServiceAccount("ns1", "sa1"))
+	assert.Equal(t, []*corev1.Pod{pod1}, pods)
+	assert.Empty(t, externalEntities)
+}
+
 func TestToGroupSelector(t *testing.T) {
 	pSelector := metav1.LabelSelector{}
 	pLabelSelector, _ := metav1.LabelSelectorAsSelector(&pSelector)
@@ -0,0 +1,314 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/storage"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	secinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/security/v1alpha1"
+	seclisters "github.com/vmware-tanzu/antrea/pkg/client/listers/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+const (
+	statusControllerName = "NetworkPolicyStatusController"
+	// Default number of workers processing a NetworkPolicy status change.
+	statusControllerWorkers = 2
+)
+
+// networkPolicyControl is the interface used by StatusController to update the status of Antrea-native policies.
+type networkPolicyControl interface {
+	UpdateAntreaNetworkPolicyStatus(namespace, name string, uid types.UID, status *secv1alpha1.NetworkPolicyStatus) error
+	UpdateAntreaClusterNetworkPolicyStatus(name string, uid types.UID, status *secv1alpha1.NetworkPolicyStatus) error
+}
+
+// StatusController is responsible for aggregating the realization status of Antrea ClusterNetworkPolicies and Antrea
+// NetworkPolicies reported by antrea-agents, and for updating the status of the CRDs accordingly. It implements the
+// pkg/apiserver/registry/controlplane/networkpolicystatus.statusController interface.
+type StatusController struct {
+	npControlInterface networkPolicyControl
+
+	// queue maintains the keys of the internal NetworkPolicies whose status need to be synced.
+	queue workqueue.RateLimitingInterface
+
+	// internalNetworkPolicyStore is the storage where the populated internal NetworkPolicies are stored.
+	internalNetworkPolicyStore storage.Interface
+
+	// statuses is a nested map that keeps the realization statuses reported by antrea-agents.
+	// The outer map's keys are the keys of the internal NetworkPolicies.
+	// The inner map's keys are the Node names. The inner map's values are statuses reported by each Node.
+	statuses     map[string]map[string]*controlplane.NetworkPolicyNodeStatus
+	statusesLock sync.RWMutex
+
+	// cnpListerSynced is a function which returns true if the ClusterNetworkPolicies shared informer has been synced at least once.
+	cnpListerSynced cache.InformerSynced
+	// anpListerSynced is a function which returns true if the Antrea NetworkPolicies shared informer has been synced at least once.
+	anpListerSynced cache.InformerSynced
+}
+
+// NewStatusController returns a new *StatusController.
+func NewStatusController(antreaClient versioned.Interface, internalNetworkPolicyStore storage.Interface, cnpInformer secinformers.ClusterNetworkPolicyInformer, anpInformer secinformers.NetworkPolicyInformer) *StatusController {
+	return &StatusController{
+		npControlInterface: &networkPolicyControlImpl{
+			antreaClient: antreaClient,
+			cnpLister:    cnpInformer.Lister(),
+			anpLister:    anpInformer.Lister(),
+		},
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkPolicyStatus"),
+		internalNetworkPolicyStore: internalNetworkPolicyStore,
+		statuses:                   map[string]map[string]*controlplane.NetworkPolicyNodeStatus{},
+		cnpListerSynced:            cnpInformer.Informer().HasSynced,
+		anpListerSynced:            anpInformer.Informer().HasSynced,
+	}
+}
+
+// UpdateStatus is called when a realization status of a NetworkPolicy is reported by an antrea-agent.
+func (c *StatusController) UpdateStatus(status *controlplane.NetworkPolicyStatus) error {
+	ref := status.NetworkPolicy
+	if ref.Type != controlplane.AntreaClusterNetworkPolicy && ref.Type != controlplane.AntreaNetworkPolicy {
+		return fmt.Errorf("status of %s is not supported", ref.Type)
+	}
+	key := k8s.NamespacedName(ref.Namespace, ref.Name)
+	func() {
+		c.statusesLock.Lock()
+		defer c.statusesLock.Unlock()
+		nodeStatuses, exists := c.statuses[key]
+		if !exists {
+			nodeStatuses = map[string]*controlplane.NetworkPolicyNodeStatus{}
+			c.statuses[key] = nodeStatuses
+		}
+		for i := range status.Nodes {
+			nodeStatuses[status.Nodes[i].NodeName] = &status.Nodes[i]
+		}
+	}()
+	c.queue.Add(key)
+	return nil
+}
+
+func (c *StatusController) getNodeStatuses(key string) []*controlplane.NetworkPolicyNodeStatus {
+	c.statusesLock.RLock()
+	defer c.statusesLock.RUnlock()
+	nodeStatuses := make([]*controlplane.NetworkPolicyNodeStatus, 0, len(c.statuses[key]))
+	for _, status := range c.statuses[key] {
+		nodeStatuses = append(nodeStatuses, status)
+	}
+	return nodeStatuses
+}
+
+// pruneNodeStatuses removes the statuses reported by Nodes that the NetworkPolicy no longer spans. If the
+// NetworkPolicy doesn't exist anymore, all its statuses are removed.
+func (c *StatusController) pruneNodeStatuses(key string, internalNP *antreatypes.NetworkPolicy) {
+	c.statusesLock.Lock()
+	defer c.statusesLock.Unlock()
+	if internalNP == nil {
+		delete(c.statuses, key)
+		return
+	}
+	for nodeName := range c.statuses[key] {
+		if !internalNP.NodeNames.Has(nodeName) {
+			delete(c.statuses[key], nodeName)
+		}
+	}
+}
+
+// Run begins watching the internal NetworkPolicy store and syncing the status of Antrea-native policies.
+func (c *StatusController) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", statusControllerName)
+	defer klog.Infof("Shutting down %s", statusControllerName)
+
+	if !cache.WaitForNamedCacheSync(statusControllerName, stopCh, c.cnpListerSynced, c.anpListerSynced) {
+		return
+	}
+
+	go wait.Until(c.watchInternalNetworkPolicies(stopCh), time.Second, stopCh)
+
+	for i := 0; i < statusControllerWorkers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+// watchInternalNetworkPolicies returns a function that watches the internal NetworkPolicy store and enqueues the
+// internal NetworkPolicies whose desired state have changed, until the watch is terminated.
+func (c *StatusController) watchInternalNetworkPolicies(stopCh <-chan struct{}) func() {
+	return func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w, err := c.internalNetworkPolicyStore.Watch(ctx, "", labels.Everything(), fields.Everything())
+		if err != nil {
+			klog.Errorf("Failed to watch internal NetworkPolicies: %v", err)
+			return
+		}
+		defer w.Stop()
+		for {
+			select {
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+				policy, ok := event.Object.(*controlplane.NetworkPolicy)
+				if !ok {
+					continue
+				}
+				c.queue.Add(k8s.NamespacedName(policy.Namespace, policy.Name))
+			case <-stopCh:
+				return
+			}
+		}
+	}
+}
+
+func (c *StatusController) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *StatusController) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.syncHandler(key.(string))
+	if err != nil {
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Failed to sync NetworkPolicy status %s: %v", key, err)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// syncHandler calculates the NetworkPolicy status based on the desired state of the internal NetworkPolicy and the
+// statuses reported by antrea-agents, and updates the status of the original Antrea-native policy.
+func (c *StatusController) syncHandler(key string) error {
+	obj, found, _ := c.internalNetworkPolicyStore.Get(key)
+	if !found {
+		c.pruneNodeStatuses(key, nil)
+		return nil
+	}
+	internalNP := obj.(*antreatypes.NetworkPolicy)
+	// It must be a K8s NetworkPolicy, which has no status to update.
+	if internalNP.SourceRef == nil || internalNP.SourceRef.Type == controlplane.K8sNetworkPolicy {
+		return nil
+	}
+	c.pruneNodeStatuses(key, internalNP)
+
+	desiredNodes := len(internalNP.NodeNames)
+	currentNodes := 0
+	hasFailure := false
+	for _, nodeStatus := range c.getNodeStatuses(key) {
+		// The status is stale as the Node hasn't realized the latest generation.
+		if nodeStatus.Generation != internalNP.Generation {
+			continue
+		}
+		if nodeStatus.RealizationFailure {
+			hasFailure = true
+			continue
+		}
+		currentNodes++
+	}
+
+	phase := secv1alpha1.NetworkPolicyRealizing
+	if hasFailure {
+		phase = secv1alpha1.NetworkPolicyFailed
+	} else if desiredNodes == 0 {
+		// The policy isn't realized anywhere as it doesn't span any Node.
+		phase = secv1alpha1.NetworkPolicyPending
+	} else if currentNodes == desiredNodes {
+		phase = secv1alpha1.NetworkPolicyRealized
+	}
+	status := &secv1alpha1.NetworkPolicyStatus{
+		Phase:                phase,
+		ObservedGeneration:   internalNP.Generation,
+		CurrentNodesRealized: int32(currentNodes),
+		DesiredNodesRealized: int32(desiredNodes),
+	}
+
+	ref := internalNP.SourceRef
+	if ref.Type == controlplane.AntreaClusterNetworkPolicy {
+		return c.npControlInterface.UpdateAntreaClusterNetworkPolicyStatus(ref.Name, ref.UID, status)
+	}
+	return c.npControlInterface.UpdateAntreaNetworkPolicyStatus(ref.Namespace, ref.Name, ref.UID, status)
+}
+
+// networkPolicyControlImpl implements networkPolicyControl by updating the status of Antrea-native policies via
+// antrea clientset.
+type networkPolicyControlImpl struct {
+	antreaClient versioned.Interface
+	cnpLister    seclisters.ClusterNetworkPolicyLister
+	anpLister    seclisters.NetworkPolicyLister
+}
+
+func (c *networkPolicyControlImpl) UpdateAntreaNetworkPolicyStatus(namespace, name string, uid types.UID, status *secv1alpha1.NetworkPolicyStatus) error {
+	anp, err := c.anpLister.NetworkPolicies(namespace).Get(name)
+	if err != nil {
+		klog.Infof("Didn't find the original Antrea NetworkPolicy %s/%s, skip updating status", namespace, name)
+		return nil
+	}
+	// It's a new NetworkPolicy with the same name, skip updating status.
+	if anp.UID != uid {
+		return nil
+	}
+	if reflect.DeepEqual(anp.Status, *status) {
+		return nil
+	}
+	toUpdate := anp.DeepCopy()
+	toUpdate.Status = *status
+	klog.V(2).Infof("Updating Antrea NetworkPolicy %s/%s status to %#v", namespace, name, *status)
+	_, err = c.antreaClient.SecurityV1alpha1().NetworkPolicies(namespace).UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *networkPolicyControlImpl) UpdateAntreaClusterNetworkPolicyStatus(name string, uid types.UID, status *secv1alpha1.NetworkPolicyStatus) error {
+	cnp, err := c.cnpLister.Get(name)
+	if err != nil {
+		klog.Infof("Didn't find the original Antrea ClusterNetworkPolicy %s, skip updating status", name)
+		return nil
+	}
+	// It's a new ClusterNetworkPolicy with the same name, skip updating status.
+	if cnp.UID != uid {
+		return nil
+	}
+	if reflect.DeepEqual(cnp.Status, *status) {
+		return nil
+	}
+	toUpdate := cnp.DeepCopy()
+	toUpdate.Status = *status
+	klog.V(2).Infof("Updating Antrea ClusterNetworkPolicy %s status to %#v", name, *status)
+	_, err = c.antreaClient.SecurityV1alpha1().ClusterNetworkPolicies().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+type fakeNetworkPolicyControl struct {
+	anpStatuses map[string]*secv1alpha1.NetworkPolicyStatus
+	cnpStatuses map[string]*secv1alpha1.NetworkPolicyStatus
+}
+
+func (c *fakeNetworkPolicyControl) UpdateAntreaNetworkPolicyStatus(namespace, name string, uid types.UID, status *secv1alpha1.NetworkPolicyStatus) error {
+	c.anpStatuses[namespace+"/"+name] = status
+	return nil
+}
+
+func (c *fakeNetworkPolicyControl) UpdateAntreaClusterNetworkPolicyStatus(name string, uid types.UID, status *secv1alpha1.NetworkPolicyStatus) error {
+	c.cnpStatuses[name] = status
+	return nil
+}
+
+func newTestStatusController() (*StatusController, *fakeNetworkPolicyControl) {
+	crdClient := fakeversioned.NewSimpleClientset()
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	statusController := NewStatusController(crdClient, store.NewNetworkPolicyStore(),
+		crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(),
+		crdInformerFactory.Security().V1alpha1().NetworkPolicies())
+	npControl := &fakeNetworkPolicyControl{
+		anpStatuses: map[string]*secv1alpha1.NetworkPolicyStatus{},
+		cnpStatuses: map[string]*secv1alpha1.NetworkPolicyStatus{},
+	}
+	statusController.npControlInterface = npControl
+	return statusController, npControl
+}
+
+func newInternalNetworkPolicy(ref *controlplane.NetworkPolicyReference, generation int64, nodeNames ...string) *antreatypes.NetworkPolicy {
+	return &antreatypes.NetworkPolicy{
+		SpanMeta:   antreatypes.SpanMeta{NodeNames: sets.NewString(nodeNames...)},
+		UID:        ref.UID,
+		Name:       ref.Name,
+		Namespace:  ref.Namespace,
+		Generation: generation,
+		SourceRef:  ref,
+	}
+}
+
+func newNetworkPolicyStatus(ref *controlplane.NetworkPolicyReference, nodeName string, generation int64, failure bool) *controlplane.NetworkPolicyStatus {
+	return &controlplane.NetworkPolicyStatus{
+		NetworkPolicy: *ref,
+		Nodes: []controlplane.NetworkPolicyNodeStatus{
+			{NodeName: nodeName, Generation: generation, RealizationFailure: failure},
+		},
+	}
+}
+
+func TestStatusControllerSync(t *testing.T) {
+	cnpRef := &controlplane.NetworkPolicyReference{Type: controlplane.AntreaClusterNetworkPolicy, Name: "cnpA", UID: "uidA"}
+	anpRef := &controlplane.NetworkPolicyReference{Type: controlplane.AntreaNetworkPolicy, Namespace: "ns1", Name: "anpB", UID: "uidB"}
+	tests := []struct {
+		name              string
+		policy            *antreatypes.NetworkPolicy
+		reportedStatuses  []*controlplane.NetworkPolicyStatus
+		expectedCNPStatus *secv1alpha1.NetworkPolicyStatus
+		expectedANPStatus *secv1alpha1.NetworkPolicyStatus
+	}{
+		{
+			name:   "no status reported",
+			policy: newInternalNetworkPolicy(cnpRef, 1, "node1", "node2"),
+			expectedCNPStatus: &secv1alpha1.NetworkPolicyStatus{
+				Phase:                secv1alpha1.NetworkPolicyRealizing,
+				ObservedGeneration:   1,
+				CurrentNodesRealized: 0,
+				DesiredNodesRealized: 2,
+			},
+		},
+		{
+			name:   "no Node spanned",
+			policy: newInternalNetworkPolicy(cnpRef, 1),
+			expectedCNPStatus: &secv1alpha1.NetworkPolicyStatus{
+				Phase:                secv1alpha1.NetworkPolicyPending,
+				ObservedGeneration:   1,
+				CurrentNodesRealized: 0,
+				DesiredNodesRealized: 0,
+			},
+		},
+		{
+			name:   "partially realized",
+			policy: newInternalNetworkPolicy(cnpRef, 2, "node1", "node2"),
+			reportedStatuses: []*controlplane.NetworkPolicyStatus{
+				newNetworkPolicyStatus(cnpRef, "node1", 2, false),
+				newNetworkPolicyStatus(cnpRef, "node2", 1, false),
+			},
+			expectedCNPStatus: &secv1alpha1.NetworkPolicyStatus{
+				Phase:                secv1alpha1.NetworkPolicyRealizing,
+				ObservedGeneration:   2,
+				CurrentNodesRealized: 1,
+				DesiredNodesRealized: 2,
+			},
+		},
+		{
+			name:   "fully realized",
+			policy: newInternalNetworkPolicy(anpRef, 2, "node1", "node2"),
+			reportedStatuses: []*controlplane.NetworkPolicyStatus{
+				newNetworkPolicyStatus(anpRef, "node1", 2, false),
+				newNetworkPolicyStatus(anpRef, "node2", 2, false),
+				newNetworkPolicyStatus(anpRef, "node3", 2, false),
+			},
+			expectedANPStatus: &secv1alpha1.NetworkPolicyStatus{
+				Phase:                secv1alpha1.NetworkPolicyRealized,
+				ObservedGeneration:   2,
+				CurrentNodesRealized: 2,
+				DesiredNodesRealized: 2,
+			},
+		},
+		{
+			name:   "failed on one Node",
+			policy: newInternalNetworkPolicy(anpRef, 3, "node1", "node2"),
+			reportedStatuses: []*controlplane.NetworkPolicyStatus{
+				newNetworkPolicyStatus(anpRef, "node1", 3, false),
+				newNetworkPolicyStatus(anpRef, "node2", 3, true),
+			},
+			expectedANPStatus: &secv1alpha1.NetworkPolicyStatus{
+				Phase:                secv1alpha1.NetworkPolicyFailed,
+				ObservedGeneration:   3,
+				CurrentNodesRealized: 1,
+				DesiredNodesRealized: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, npControl := newTestStatusController()
+			c.internalNetworkPolicyStore.Create(tt.policy)
+			for _, status := range tt.reportedStatuses {
+				require.NoError(t, c.UpdateStatus(status))
+			}
+			key := tt.policy.Namespace + "/" + tt.policy.Name
+			if tt.policy.Namespace == "" {
+				key = tt.policy.Name
+			}
+			require.NoError(t, c.syncHandler(key))
+			assert.Equal(t, tt.expectedCNPStatus, npControl.cnpStatuses[cnpRef.Name])
+			assert.Equal(t, tt.expectedANPStatus, npControl.anpStatuses[anpRef.Namespace+"/"+anpRef.Name])
+			// Statuses of the Nodes that are not in the span must be pruned.
+			for _, nodeStatus := range c.getNodeStatuses(key) {
+				assert.True(t, tt.policy.NodeNames.Has(nodeStatus.NodeName))
+			}
+		})
+	}
+}
+
+func TestStatusControllerDeletePolicy(t *testing.T) {
+	c, npControl := newTestStatusController()
+	ref := &controlplane.NetworkPolicyReference{Type: controlplane.AntreaClusterNetworkPolicy, Name: "cnpA", UID: "uidA"}
+	require.NoError(t, c.UpdateStatus(newNetworkPolicyStatus(ref, "node1", 1, false)))
+	assert.Len(t, c.getNodeStatuses(ref.Name), 1)
+	// The internal NetworkPolicy doesn't exist, the statuses should be removed.
+	require.NoError(t, c.syncHandler(ref.Name))
+	assert.Empty(t, c.getNodeStatuses(ref.Name))
+	assert.Empty(t, npControl.cnpStatuses)
+}
+
+func TestStatusControllerIgnoreK8sNetworkPolicy(t *testing.T) {
+	c, _ := newTestStatusController()
+	ref := &controlplane.NetworkPolicyReference{Type: controlplane.K8sNetworkPolicy, Namespace: "ns1", Name: "npA", UID: "uidA"}
+	assert.Error(t, c.UpdateStatus(newNetworkPolicyStatus(ref, "node1", 1, false)))
+}
+
+func TestNetworkPolicyControlUpdateStatus(t *testing.T) {
+	cnp := &secv1alpha1.ClusterNetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"}}
+	crdClient := fakeversioned.NewSimpleClientset(cnp)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	cnpInformer := crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
+	cnpInformer.Informer().GetStore().Add(cnp)
+	npControl := &networkPolicyControlImpl{
+		antreaClient: crdClient,
+		cnpLister:    cnpInformer.Lister(),
+		anpLister:    crdInformerFactory.Security().V1alpha1().NetworkPolicies().Lister(),
+	}
+	status := &secv1alpha1.NetworkPolicyStatus{
+		Phase:                secv1alpha1.NetworkPolicyRealized,
+		ObservedGeneration:   1,
+		CurrentNodesRealized: 1,
+		DesiredNodesRealized: 1,
+	}
+	// The status shouldn't be updated if the UID doesn't match.
+	require.NoError(t, npControl.UpdateAntreaClusterNetworkPolicyStatus("cnpA", "uidB", status))
+	actual, err := crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Get(context.TODO(), "cnpA", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secv1alpha1.NetworkPolicyStatus{}, actual.Status)
+
+	require.NoError(t, npControl.UpdateAntreaClusterNetworkPolicyStatus("cnpA", "uidA", status))
+	actual, err = crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Get(context.TODO(), "cnpA", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, *status, actual.Status)
+
+	// Updating status of a non-existing policy should be skipped.
+	require.NoError(t, npControl.UpdateAntreaNetworkPolicyStatus("ns1", "anpA", "uidC", status))
+}
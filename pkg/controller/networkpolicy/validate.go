@@ -17,13 +17,19 @@ package networkpolicy
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"regexp"
 	"strconv"
+	"strings"
 
 	admv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
 
+	corev1a2 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha2"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 )
 
@@ -35,6 +41,9 @@ var (
 	// reservedTierNames stores the set of Tier names which cannot be deleted
 	// since they are created by Antrea.
 	reservedTierNames = sets.NewString("application", "platform", "networkops", "securityops", "emergency")
+	// fqdnSelectorRegex matches the lower case domain names which may have
+	// wildcards "*" in their labels, e.g. "www.example.com" or "*.example.com".
+	fqdnSelectorRegex = regexp.MustCompile(`^[-a-z0-9*]+(\.[-a-z0-9*]+)*\.?$`)
 )
 
 type NetworkPolicyValidator struct {
@@ -48,7 +57,7 @@ func NewNetworkPolicyValidator(networkPolicyController *NetworkPolicyController)
 	}
 }
 
-// Validate function validates a Tier or Antrea Policy object
+// Validate function validates a Tier, ClusterGroup or Antrea Policy object
 func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.AdmissionResponse {
 	var result *metav1.Status
 	var msg string
@@ -89,6 +98,12 @@ func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.Admi
 			}
 		}
 		msg, allowed = v.validateAntreaPolicy(op, curCNP.Spec.Tier)
+		if allowed {
+			msg, allowed = validateAntreaPolicyPeers(curCNP.Spec.AppliedTo, curCNP.Spec.Ingress, curCNP.Spec.Egress, true, "")
+		}
+		if allowed {
+			msg, allowed = validateAntreaPolicyPorts(curCNP.Spec.Ingress, curCNP.Spec.Egress)
+		}
 	case "NetworkPolicy":
 		klog.V(2).Info("Validating Antrea NetworkPolicy CRD")
 		var curANP, oldANP secv1alpha1.NetworkPolicy
@@ -105,6 +120,28 @@ func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.Admi
 			}
 		}
 		msg, allowed = v.validateAntreaPolicy(op, curANP.Spec.Tier)
+		if allowed {
+			msg, allowed = validateAntreaPolicyPeers(curANP.Spec.AppliedTo, curANP.Spec.Ingress, curANP.Spec.Egress, false, curANP.Namespace)
+		}
+		if allowed {
+			msg, allowed = validateAntreaPolicyPorts(curANP.Spec.Ingress, curANP.Spec.Egress)
+		}
+	case "ClusterGroup":
+		klog.V(2).Info("Validating ClusterGroup CRD")
+		var curCG, oldCG corev1a2.ClusterGroup
+		if curRaw != nil {
+			if err := json.Unmarshal(curRaw, &curCG); err != nil {
+				klog.Errorf("Error de-serializing current ClusterGroup")
+				return GetAdmissionResponseForErr(err)
+			}
+		}
+		if oldRaw != nil {
+			if err := json.Unmarshal(oldRaw, &oldCG); err != nil {
+				klog.Errorf("Error de-serializing old ClusterGroup")
+				return GetAdmissionResponseForErr(err)
+			}
+		}
+		msg, allowed = v.validateClusterGroup(&curCG, &oldCG, op)
 	}
 	if msg != "" {
 		result = &metav1.Status{
@@ -139,6 +176,179 @@ func (v *NetworkPolicyValidator) validateAntreaPolicy(op admv1.Operation, tier s
 	return reason, allowed
 }
 
+// validateAntreaPolicyPeers validates the AppliedTo and rule peers of an
+// Antrea NetworkPolicy or ClusterNetworkPolicy. A peer which refers to a
+// ClusterGroup, an FQDN or a ServiceAccount cannot set any other field.
+// ClusterGroups can only be referred by Antrea ClusterNetworkPolicies, and
+// FQDNs can only be used in the To field of Egress rules of Antrea
+// ClusterNetworkPolicies. An Antrea NetworkPolicy can only refer to the
+// ServiceAccounts in its own Namespace. Namespaces can only be matched in the
+// rules of Antrea ClusterNetworkPolicies.
+func validateAntreaPolicyPeers(appliedTo []secv1alpha1.NetworkPolicyPeer, ingress, egress []secv1alpha1.Rule, isACNP bool, namespace string) (string, bool) {
+	var peers, egressPeers []secv1alpha1.NetworkPolicyPeer
+	peers = append(peers, appliedTo...)
+	for _, rule := range ingress {
+		peers = append(peers, rule.From...)
+	}
+	for _, rule := range egress {
+		egressPeers = append(egressPeers, rule.To...)
+	}
+	for _, peer := range peers {
+		if peer.FQDN != "" {
+			return fmt.Sprintf("fqdn %s can only be set in the to field of egress rules", peer.FQDN), false
+		}
+	}
+	for _, peer := range appliedTo {
+		if peer.Namespaces != nil {
+			return "namespaces can only be set in the peers of rules", false
+		}
+	}
+	for _, peer := range append(peers, egressPeers...) {
+		if peer.Group != "" {
+			if !isACNP {
+				return fmt.Sprintf("group %s cannot be referred by an Antrea NetworkPolicy", peer.Group), false
+			}
+			if peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil || peer.IPBlock != nil || peer.FQDN != "" {
+				return fmt.Sprintf("group %s cannot be set with other peers", peer.Group), false
+			}
+		}
+		if peer.FQDN != "" {
+			if !isACNP {
+				return fmt.Sprintf("fqdn %s cannot be used in an Antrea NetworkPolicy", peer.FQDN), false
+			}
+			if peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil || peer.IPBlock != nil {
+				return fmt.Sprintf("fqdn %s cannot be set with other peers", peer.FQDN), false
+			}
+			if !fqdnSelectorRegex.MatchString(strings.ToLower(peer.FQDN)) {
+				return fmt.Sprintf("fqdn %s is not a valid domain name or wildcard domain name", peer.FQDN), false
+			}
+		}
+		if sa := peer.ServiceAccount; sa != nil {
+			if peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil || peer.IPBlock != nil || peer.Group != "" || peer.FQDN != "" {
+				return fmt.Sprintf("serviceAccount %s/%s cannot be set with other peers", sa.Namespace, sa.Name), false
+			}
+			if sa.Name == "" {
+				return "serviceAccount must have a name", false
+			}
+			if isACNP && sa.Namespace == "" {
+				return fmt.Sprintf("serviceAccount %s must have a namespace in an Antrea ClusterNetworkPolicy", sa.Name), false
+			}
+			if !isACNP && sa.Namespace != "" && sa.Namespace != namespace {
+				return fmt.Sprintf("serviceAccount %s/%s must be in the namespace of the Antrea NetworkPolicy", sa.Namespace, sa.Name), false
+			}
+		}
+		if ns := peer.Namespaces; ns != nil {
+			if !isACNP {
+				return "namespaces cannot be used in an Antrea NetworkPolicy", false
+			}
+			if peer.NamespaceSelector != nil || peer.IPBlock != nil || peer.Group != "" || peer.FQDN != "" || peer.ServiceAccount != nil {
+				return "namespaces can only be set with podSelector or externalEntitySelector", false
+			}
+			if ns.Match != secv1alpha1.NamespaceMatchSelf {
+				return fmt.Sprintf("namespaces match %q is not supported", ns.Match), false
+			}
+		}
+	}
+	return "", true
+}
+
+// validateAntreaPolicyPorts validates the ports of the rules of an Antrea
+// NetworkPolicy or ClusterNetworkPolicy. An endPort can only be set along with
+// a numerical port of TCP, UDP or SCTP, and must not be smaller than the port.
+// ICMP type and code can only be set for ICMP and ICMPv6, which don't have
+// ports, and the code can only be set along with the type.
+func validateAntreaPolicyPorts(ingress, egress []secv1alpha1.Rule) (string, bool) {
+	var ports []secv1alpha1.NetworkPolicyPort
+	for _, rule := range ingress {
+		ports = append(ports, rule.Ports...)
+	}
+	for _, rule := range egress {
+		ports = append(ports, rule.Ports...)
+	}
+	for _, port := range ports {
+		protocol := v1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		isICMP := protocol == secv1alpha1.ProtocolICMP || protocol == secv1alpha1.ProtocolICMPv6
+		if isICMP {
+			if port.Port != nil || port.EndPort != nil {
+				return fmt.Sprintf("port and endPort cannot be set for protocol %s", protocol), false
+			}
+		} else if port.ICMPType != nil || port.ICMPCode != nil {
+			return fmt.Sprintf("icmpType and icmpCode cannot be set for protocol %s", protocol), false
+		}
+		if port.EndPort != nil {
+			if port.Port == nil || port.Port.Type != intstr.Int {
+				return "endPort can only be set along with a numerical port", false
+			}
+			if *port.EndPort < port.Port.IntVal || *port.EndPort > 65535 {
+				return fmt.Sprintf("endPort %d must be between port %d and 65535", *port.EndPort, port.Port.IntVal), false
+			}
+		}
+		if port.ICMPCode != nil && port.ICMPType == nil {
+			return "icmpCode can only be set along with icmpType", false
+		}
+		if port.ICMPType != nil && (*port.ICMPType < 0 || *port.ICMPType > 255) {
+			return fmt.Sprintf("icmpType %d must be between 0 and 255", *port.ICMPType), false
+		}
+		if port.ICMPCode != nil && (*port.ICMPCode < 0 || *port.ICMPCode > 255) {
+			return fmt.Sprintf("icmpCode %d must be between 0 and 255", *port.ICMPCode), false
+		}
+	}
+	return "", true
+}
+
+// validateClusterGroup validates the admission of a ClusterGroup resource.
+func (v *NetworkPolicyValidator) validateClusterGroup(curCG, oldCG *corev1a2.ClusterGroup, op admv1.Operation) (string, bool) {
+	switch op {
+	case admv1.Create, admv1.Update:
+		klog.V(2).Infof("Validating %s request for ClusterGroup", op)
+		return validateClusterGroupSpec(&curCG.Spec)
+	case admv1.Delete:
+		klog.V(2).Info("Validating DELETE request for ClusterGroup")
+		// ClusterGroup referred by existing ACNPs cannot be deleted.
+		cnps, err := v.networkPolicyController.cnpInformer.Informer().GetIndexer().ByIndex(ClusterGroupIndex, oldCG.Name)
+		if err != nil {
+			return fmt.Sprintf("error retrieving the Antrea ClusterNetworkPolicies referencing clustergroup %s: %v", oldCG.Name, err), false
+		}
+		if len(cnps) > 0 {
+			return fmt.Sprintf("clustergroup %s is referenced by %d Antrea ClusterNetworkPolicies", oldCG.Name, len(cnps)), false
+		}
+	}
+	return "", true
+}
+
+// validateClusterGroupSpec ensures that exactly one of the selectors, IPBlocks
+// and ServiceReference is set in the ClusterGroup spec.
+func validateClusterGroupSpec(spec *corev1a2.GroupSpec) (string, bool) {
+	setFields := 0
+	if spec.PodSelector != nil || spec.NamespaceSelector != nil || spec.ExternalEntitySelector != nil {
+		setFields++
+	}
+	if len(spec.IPBlocks) > 0 {
+		setFields++
+	}
+	if spec.ServiceReference != nil {
+		setFields++
+	}
+	if setFields != 1 {
+		return "exactly one of podSelector and/or namespaceSelector, externalEntitySelector, ipBlocks or serviceReference must be set", false
+	}
+	if spec.PodSelector != nil && spec.ExternalEntitySelector != nil {
+		return "podSelector and externalEntitySelector cannot be set at the same time", false
+	}
+	for _, ipBlock := range spec.IPBlocks {
+		if _, _, err := net.ParseCIDR(ipBlock.CIDR); err != nil {
+			return fmt.Sprintf("invalid ipBlock CIDR %s: %v", ipBlock.CIDR, err), false
+		}
+	}
+	if svcRef := spec.ServiceReference; svcRef != nil && (svcRef.Name == "" || svcRef.Namespace == "") {
+		return "both name and namespace must be set in serviceReference", false
+	}
+	return "", true
+}
+
 // validateTier validates the admission of a Tier resource
 func (v *NetworkPolicyValidator) validateTier(curTier, oldTier *secv1alpha1.Tier, op admv1.Operation) (string, bool) {
 	allowed := true
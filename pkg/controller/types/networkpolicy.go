@@ -39,7 +39,8 @@ func (meta *SpanMeta) Has(nodeName string) bool {
 
 // GroupSelector describes how to select Pods.
 type GroupSelector struct {
-	// The normalized name is calculated from Namespace, PodSelector, ExternalEntitySelector and NamespaceSelector.
+	// The normalized name is calculated from Namespace, PodSelector, ExternalEntitySelector, NamespaceSelector and
+	// ServiceAccountName.
 	// If multiple policies have same selectors, they should share this group by comparing NormalizedName.
 	// It's also used to generate Name and UUID of group.
 	NormalizedName string
@@ -57,6 +58,9 @@ type GroupSelector struct {
 	// If Namespace and NamespaceSelector both are unset, it selects the ExternalEntities in all the Namespaces.
 	// TODO: Add validation in API to not allow externalEntitySelector and podSelector in the same group.
 	ExternalEntitySelector labels.Selector
+	// This is the name of a ServiceAccount. If it is set, Namespace must be set to the ServiceAccount's Namespace and
+	// no other selector can be set. It selects the Pods which run with the ServiceAccount.
+	ServiceAccountName string
 }
 
 // AppliedToGroup describes a set of Pods to apply Network Policies to.
@@ -101,6 +105,8 @@ type NetworkPolicy struct {
 	UID types.UID
 	// Name of the internal Network Policy.
 	Name string
+	// Generation of the original Network Policy that the internal Network Policy is created for.
+	Generation int64
 	// Namespace of the original K8s Network Policy.
 	// An empty value indicates that the Network Policy is Cluster scoped.
 	Namespace string
@@ -39,6 +39,30 @@ const (
 	// Service traffic.
 	AntreaProxy featuregate.Feature = "AntreaProxy"
 
+	// alpha: v0.11
+	// Enable NodePort Service support in AntreaProxy, so that kube-proxy is not
+	// required anymore. It requires AntreaProxy to be enabled.
+	AntreaProxyNodePort featuregate.Feature = "AntreaProxyNodePort"
+
+	// alpha: v0.11
+	// Enable AntreaProxy to load-balance the traffic sent to ClusterIP Services
+	// from the host network of Nodes, by routing the Service CIDR to OVS via the
+	// host gateway. It requires AntreaProxy to be enabled.
+	AntreaProxyHostNetwork featuregate.Feature = "AntreaProxyHostNetwork"
+
+	// alpha: v0.11
+	// Enable AntreaProxy to load-balance the traffic sent to the ingress IPs of
+	// LoadBalancer Services from outside the cluster in Direct Server Return
+	// mode, if it's selected with the Service annotation. It requires
+	// AntreaProxy to be enabled and the Geneve tunnel.
+	AntreaProxyLoadBalancerDSR featuregate.Feature = "AntreaProxyLoadBalancerDSR"
+
+	// alpha: v0.11
+	// Enable AntreaProxy to track Service Endpoints with EndpointSlices instead of
+	// Endpoints, and to select Endpoints according to the topologyKeys of Services.
+	// It requires AntreaProxy to be enabled.
+	EndpointSlice featuregate.Feature = "EndpointSlice"
+
 	// alpha: v0.8
 	// Allows to trace path from a generated packet.
 	Traceflow featuregate.Feature = "Traceflow"
@@ -50,6 +74,22 @@ const (
 	// alpha: v0.10
 	// Enable collecting and exposing NetworkPolicy statistics.
 	NetworkPolicyStats featuregate.Feature = "NetworkPolicyStats"
+
+	// alpha: v0.11
+	// Enable SNAT of the traffic from the selected Pods to the external network
+	// with the Egress IPs specified in Egress CRDs.
+	Egress featuregate.Feature = "Egress"
+
+	// alpha: v0.11
+	// Enable allocating IPs from ExternalIPPools to LoadBalancer Services, and
+	// announcing the allocated IPs from the selected Nodes.
+	ServiceExternalIP featuregate.Feature = "ServiceExternalIP"
+
+	// alpha: v0.11
+	// Enable exporting Services to and importing Services from the other
+	// clusters of a multicluster set, and routing the traffic to the Pods of the
+	// other clusters through the gateway Nodes.
+	Multicluster featuregate.Feature = "Multicluster"
 )
 
 var (
@@ -64,11 +104,18 @@ var (
 	// To add a new feature, define a key for it above and add it here. The features will be
 	// available throughout Antrea binaries.
 	defaultAntreaFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-		AntreaPolicy:       {Default: false, PreRelease: featuregate.Alpha},
-		AntreaProxy:        {Default: false, PreRelease: featuregate.Alpha},
-		Traceflow:          {Default: false, PreRelease: featuregate.Alpha},
-		FlowExporter:       {Default: false, PreRelease: featuregate.Alpha},
-		NetworkPolicyStats: {Default: false, PreRelease: featuregate.Alpha},
+		AntreaPolicy:               {Default: false, PreRelease: featuregate.Alpha},
+		AntreaProxy:                {Default: false, PreRelease: featuregate.Alpha},
+		AntreaProxyNodePort:        {Default: false, PreRelease: featuregate.Alpha},
+		AntreaProxyHostNetwork:     {Default: false, PreRelease: featuregate.Alpha},
+		AntreaProxyLoadBalancerDSR: {Default: false, PreRelease: featuregate.Alpha},
+		EndpointSlice:              {Default: false, PreRelease: featuregate.Alpha},
+		Traceflow:                  {Default: false, PreRelease: featuregate.Alpha},
+		FlowExporter:               {Default: false, PreRelease: featuregate.Alpha},
+		NetworkPolicyStats:         {Default: false, PreRelease: featuregate.Alpha},
+		Egress:                     {Default: false, PreRelease: featuregate.Alpha},
+		ServiceExternalIP:          {Default: false, PreRelease: featuregate.Alpha},
+		Multicluster:               {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
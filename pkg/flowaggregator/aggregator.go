@@ -0,0 +1,426 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sync"
+	"time"
+
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix"
+	"github.com/vmware-tanzu/antrea/pkg/util/env"
+)
+
+// A flow is removed if it has not been updated for inactiveFlowTimeoutFactor
+// export intervals, as the Flow Exporters keep exporting the records of the
+// connections until they are gone from conntrack.
+const inactiveFlowTimeoutFactor = 3
+
+// aggregatedFlow is the flow correlated from the records of the same
+// connection exported by the source Node and the destination Node.
+type aggregatedFlow struct {
+	record *FlowRecord
+	// firstSeen is when the first record of the flow was received.
+	firstSeen time.Time
+	// lastUpdated is when the last record of the flow was received.
+	lastUpdated time.Time
+	// updated is whether a record has been received since the flow was last
+	// exported.
+	updated bool
+	// The counters of the flow when it was last exported, used to compute the
+	// delta counts.
+	prevPackets        uint64
+	prevBytes          uint64
+	prevReversePackets uint64
+	prevReverseBytes   uint64
+}
+
+// isCorrelated returns whether the flow has the information from both the
+// source Node and the destination Node. It's true for intra-Node flows with a
+// single record.
+func (f *aggregatedFlow) isCorrelated() bool {
+	return f.record.hasSourceInfo() && f.record.hasDestinationInfo()
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func deltaCount(count, prevCount uint64) uint64 {
+	if count < prevCount {
+		klog.Warningf("Count %d is not expected to be less than previous count %d", count, prevCount)
+		return 0
+	}
+	return count - prevCount
+}
+
+// merge merges a record of the connection into the flow.
+func (f *aggregatedFlow) merge(record *FlowRecord) {
+	agg := f.record
+	if !record.FlowStartTime.IsZero() && record.FlowStartTime.Before(agg.FlowStartTime) {
+		agg.FlowStartTime = record.FlowStartTime
+	}
+	if record.FlowEndTime.After(agg.FlowEndTime) {
+		agg.FlowEndTime = record.FlowEndTime
+	}
+	// A connection denied on either Node is reported as denied, even if the
+	// other Node sees it as an active connection.
+	if agg.FlowEndReason != exporter.DeniedReason {
+		agg.FlowEndReason = record.FlowEndReason
+	}
+	// Both Nodes report the counters of the same connection, which may be
+	// polled at different times. The greater ones are more recent.
+	agg.Packets = maxUint64(agg.Packets, record.Packets)
+	agg.Bytes = maxUint64(agg.Bytes, record.Bytes)
+	agg.ReversePackets = maxUint64(agg.ReversePackets, record.ReversePackets)
+	agg.ReverseBytes = maxUint64(agg.ReverseBytes, record.ReverseBytes)
+	if record.hasSourceInfo() {
+		agg.SourcePodName = record.SourcePodName
+		agg.SourcePodNamespace = record.SourcePodNamespace
+		agg.SourceNodeName = record.SourceNodeName
+	}
+	if record.hasDestinationInfo() {
+		agg.DestinationPodName = record.DestinationPodName
+		agg.DestinationPodNamespace = record.DestinationPodNamespace
+		agg.DestinationNodeName = record.DestinationNodeName
+	}
+	// Only the source Node knows the Service of the connection.
+	if record.DestinationServicePortName != "" {
+		agg.DestinationClusterIP = record.DestinationClusterIP
+		agg.DestinationServicePortName = record.DestinationServicePortName
+	}
+	// The ingress rules are enforced on the destination Node and the egress
+	// rules on the source Node, so each record may only have one of them.
+	if record.IngressNetworkPolicyName != "" {
+		agg.IngressNetworkPolicyName = record.IngressNetworkPolicyName
+		agg.IngressNetworkPolicyNamespace = record.IngressNetworkPolicyNamespace
+	}
+	if record.EgressNetworkPolicyName != "" {
+		agg.EgressNetworkPolicyName = record.EgressNetworkPolicyName
+		agg.EgressNetworkPolicyNamespace = record.EgressNetworkPolicyNamespace
+	}
+}
+
+type flowAggregator struct {
+	collectingProcess         *collectingProcess
+	externalFlowCollectorAddr net.Addr
+	exportInterval            time.Duration
+
+	flowsLock sync.Mutex
+	// flows is a map from the 5-tuple of the connection to the flow.
+	flows map[FlowKey]*aggregatedFlow
+
+	process        ipfix.IPFIXExportingProcess
+	elementsListv4 []*ipfixentities.InfoElement
+	elementsListv6 []*ipfixentities.InfoElement
+	templateIDv4   uint16
+	templateIDv6   uint16
+	registry       ipfix.IPFIXRegistry
+}
+
+// NewFlowAggregator returns a flow aggregator that collects the flow records
+// sent by the Flow Exporters on collectorAddr, and exports the aggregated flow
+// records to the external flow collector every exportInterval.
+func NewFlowAggregator(collectorAddr net.Addr, externalFlowCollectorAddr net.Addr, exportInterval time.Duration) *flowAggregator {
+	registry := ipfix.NewIPFIXRegistry()
+	registry.LoadRegistry()
+	fa := &flowAggregator{
+		externalFlowCollectorAddr: externalFlowCollectorAddr,
+		exportInterval:            exportInterval,
+		flows:                     make(map[FlowKey]*aggregatedFlow),
+		registry:                  registry,
+	}
+	fa.collectingProcess = newCollectingProcess(collectorAddr, fa.addRecord)
+	return fa
+}
+
+// Run starts the collecting process and exports the aggregated flow records
+// periodically until stopCh is closed. An error is returned if the collecting
+// process fails.
+func (fa *flowAggregator) Run(stopCh <-chan struct{}) error {
+	if err := fa.collectingProcess.listen(); err != nil {
+		return err
+	}
+	serveErrCh := make(chan error, 1)
+	go func() {
+		serveErrCh <- fa.collectingProcess.serve(stopCh)
+	}()
+
+	ticker := time.NewTicker(fa.exportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			if fa.process != nil {
+				fa.process.CloseConnToCollector()
+			}
+			return nil
+		case err := <-serveErrCh:
+			if err == nil {
+				// stopCh is closed.
+				continue
+			}
+			if fa.process != nil {
+				fa.process.CloseConnToCollector()
+			}
+			return err
+		case <-ticker.C:
+			if err := fa.export(); err != nil {
+				klog.Errorf("Error when exporting aggregated flow records: %v", err)
+				// Reset the connection to the external flow collector and
+				// retry in the next export cycle.
+				if fa.process != nil {
+					fa.process.CloseConnToCollector()
+					fa.process = nil
+				}
+			}
+		}
+	}
+}
+
+// addRecord correlates a flow record received from a Flow Exporter with the
+// flow of the same connection.
+func (fa *flowAggregator) addRecord(record *FlowRecord) {
+	if record.SourceAddress == nil || record.DestinationAddress == nil {
+		klog.V(4).Infof("Ignoring flow record without IP addresses")
+		return
+	}
+	key := record.Key()
+	now := time.Now()
+	fa.flowsLock.Lock()
+	defer fa.flowsLock.Unlock()
+	flow, exists := fa.flows[key]
+	if !exists {
+		fa.flows[key] = &aggregatedFlow{record: record, firstSeen: now, lastUpdated: now, updated: true}
+		return
+	}
+	flow.merge(record)
+	flow.lastUpdated = now
+	flow.updated = true
+}
+
+// export exports the updated flows. A flow which is not correlated is exported
+// only after the record of the other Node has been waited for one export
+// interval, as it may never come if the other end is not a Pod.
+func (fa *flowAggregator) export() error {
+	if fa.process == nil {
+		if err := fa.initExportingProcess(); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	fa.flowsLock.Lock()
+	defer fa.flowsLock.Unlock()
+	for key, flow := range fa.flows {
+		if flow.updated && (flow.isCorrelated() || now.Sub(flow.firstSeen) >= fa.exportInterval) {
+			isIPv6 := flow.record.SourceAddress.To4() == nil
+			if err := fa.sendDataRecord(ipfix.NewIPFIXDataRecord(fa.templateID(isIPv6)), flow, isIPv6); err != nil {
+				return err
+			}
+			flow.prevPackets = flow.record.Packets
+			flow.prevBytes = flow.record.Bytes
+			flow.prevReversePackets = flow.record.ReversePackets
+			flow.prevReverseBytes = flow.record.ReverseBytes
+			flow.updated = false
+		} else if !flow.updated && now.Sub(flow.lastUpdated) >= inactiveFlowTimeoutFactor*fa.exportInterval {
+			klog.V(4).Infof("Removing inactive flow %s", key)
+			delete(fa.flows, key)
+		}
+	}
+	klog.V(2).Infof("Successfully exported aggregated IPFIX flow records")
+	return nil
+}
+
+func genObservationID() uint32 {
+	h := fnv.New32()
+	h.Write([]byte(env.GetPodName()))
+	return h.Sum32()
+}
+
+func (fa *flowAggregator) initExportingProcess() error {
+	var expProcess ipfix.IPFIXExportingProcess
+	var err error
+	if fa.externalFlowCollectorAddr.Network() == "tcp" {
+		// TCP transport do not need any tempRefTimeout, so sending 0.
+		expProcess, err = ipfix.NewIPFIXExportingProcess(fa.externalFlowCollectorAddr, genObservationID(), 0)
+	} else {
+		// For UDP transport, hardcoding tempRefTimeout value as 1800s.
+		expProcess, err = ipfix.NewIPFIXExportingProcess(fa.externalFlowCollectorAddr, genObservationID(), 1800)
+	}
+	if err != nil {
+		return err
+	}
+	fa.process = expProcess
+	fa.templateIDv4 = expProcess.NewTemplateID()
+	fa.templateIDv6 = expProcess.NewTemplateID()
+
+	for _, isIPv6 := range []bool{false, true} {
+		templateRec := ipfix.NewIPFIXTemplateRecord(uint16(len(exporter.IANAInfoElements)+len(exporter.IANAReverseInfoElements)+len(exporter.AntreaInfoElements)), fa.templateID(isIPv6))
+		sentBytes, err := fa.sendTemplateRecord(templateRec, isIPv6)
+		if err != nil {
+			return err
+		}
+		klog.V(2).Infof("Initialized exporting process and sent %d bytes size of template record (IPv6: %t)", sentBytes, isIPv6)
+	}
+	return nil
+}
+
+func (fa *flowAggregator) templateID(isIPv6 bool) uint16 {
+	if isIPv6 {
+		return fa.templateIDv6
+	}
+	return fa.templateIDv4
+}
+
+// sendTemplateRecord sends the IPv4 or IPv6 template of the Flow Exporters,
+// so that the external flow collector can process the aggregated flow records
+// the same way as the flow records of the Flow Exporters.
+func (fa *flowAggregator) sendTemplateRecord(templateRec ipfix.IPFIXRecord, isIPv6 bool) (int, error) {
+	if _, err := templateRec.PrepareRecord(); err != nil {
+		return 0, fmt.Errorf("error when writing template header: %v", err)
+	}
+	addElements := func(names []string, enterpriseID uint32) error {
+		for _, ie := range names {
+			element, err := fa.registry.GetInfoElement(ie, enterpriseID)
+			if err != nil {
+				return fmt.Errorf("%s not present. returned error: %v", ie, err)
+			}
+			if _, err := templateRec.AddInfoElement(element, nil); err != nil {
+				return fmt.Errorf("error when adding %s to template: %v", element.Name, err)
+			}
+		}
+		return nil
+	}
+	ianaInfoElements, antreaInfoElements := exporter.IANAInfoElements, exporter.AntreaInfoElements
+	if isIPv6 {
+		ianaInfoElements, antreaInfoElements = exporter.IANAInfoElementsIPv6, exporter.AntreaInfoElementsIPv6
+	}
+	if err := addElements(ianaInfoElements, ipfixregistry.IANAEnterpriseID); err != nil {
+		return 0, err
+	}
+	if err := addElements(exporter.IANAReverseInfoElements, ipfixregistry.ReverseEnterpriseID); err != nil {
+		return 0, err
+	}
+	if err := addElements(antreaInfoElements, ipfixregistry.AntreaEnterpriseID); err != nil {
+		return 0, err
+	}
+
+	sentBytes, err := fa.process.AddRecordAndSendMsg(ipfixentities.Template, templateRec.GetRecord())
+	if err != nil {
+		return 0, fmt.Errorf("error in IPFIX exporting process when sending template record: %v", err)
+	}
+	if isIPv6 {
+		fa.elementsListv6 = templateRec.GetTemplateElements()
+	} else {
+		fa.elementsListv4 = templateRec.GetTemplateElements()
+	}
+	return sentBytes, nil
+}
+
+func (fa *flowAggregator) sendDataRecord(dataRec ipfix.IPFIXRecord, flow *aggregatedFlow, isIPv6 bool) error {
+	record := flow.record
+	elementsList := fa.elementsListv4
+	if isIPv6 {
+		elementsList = fa.elementsListv6
+	}
+	for _, ie := range elementsList {
+		var err error
+		switch ieName := ie.Name; ieName {
+		case "flowStartSeconds":
+			_, err = dataRec.AddInfoElement(ie, record.FlowStartTime.Unix())
+		case "flowEndSeconds":
+			_, err = dataRec.AddInfoElement(ie, record.FlowEndTime.Unix())
+		case "flowEndReason":
+			_, err = dataRec.AddInfoElement(ie, record.FlowEndReason)
+		case "sourceIPv4Address", "sourceIPv6Address":
+			_, err = dataRec.AddInfoElement(ie, record.SourceAddress)
+		case "destinationIPv4Address", "destinationIPv6Address":
+			_, err = dataRec.AddInfoElement(ie, record.DestinationAddress)
+		case "sourceTransportPort":
+			_, err = dataRec.AddInfoElement(ie, record.SourcePort)
+		case "destinationTransportPort":
+			_, err = dataRec.AddInfoElement(ie, record.DestinationPort)
+		case "protocolIdentifier":
+			_, err = dataRec.AddInfoElement(ie, record.Protocol)
+		case "packetTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.Packets)
+		case "octetTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.Bytes)
+		case "packetDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.Packets, flow.prevPackets))
+		case "octetDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.Bytes, flow.prevBytes))
+		case "reverse_PacketTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.ReversePackets)
+		case "reverse_OctetTotalCount":
+			_, err = dataRec.AddInfoElement(ie, record.ReverseBytes)
+		case "reverse_PacketDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.ReversePackets, flow.prevReversePackets))
+		case "reverse_OctetDeltaCount":
+			_, err = dataRec.AddInfoElement(ie, deltaCount(record.ReverseBytes, flow.prevReverseBytes))
+		case "sourcePodNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.SourcePodNamespace)
+		case "sourcePodName":
+			_, err = dataRec.AddInfoElement(ie, record.SourcePodName)
+		case "sourceNodeName":
+			_, err = dataRec.AddInfoElement(ie, record.SourceNodeName)
+		case "destinationPodNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.DestinationPodNamespace)
+		case "destinationPodName":
+			_, err = dataRec.AddInfoElement(ie, record.DestinationPodName)
+		case "destinationNodeName":
+			_, err = dataRec.AddInfoElement(ie, record.DestinationNodeName)
+		case "destinationClusterIP", "destinationClusterIPv6":
+			if record.DestinationClusterIP != nil {
+				_, err = dataRec.AddInfoElement(ie, record.DestinationClusterIP)
+			} else if isIPv6 {
+				// Sending dummy IP as the Flow Exporters do.
+				_, err = dataRec.AddInfoElement(ie, net.IPv6zero)
+			} else {
+				_, err = dataRec.AddInfoElement(ie, net.IP{0, 0, 0, 0})
+			}
+		case "destinationServicePortName":
+			_, err = dataRec.AddInfoElement(ie, record.DestinationServicePortName)
+		case "ingressNetworkPolicyName":
+			_, err = dataRec.AddInfoElement(ie, record.IngressNetworkPolicyName)
+		case "ingressNetworkPolicyNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.IngressNetworkPolicyNamespace)
+		case "egressNetworkPolicyName":
+			_, err = dataRec.AddInfoElement(ie, record.EgressNetworkPolicyName)
+		case "egressNetworkPolicyNamespace":
+			_, err = dataRec.AddInfoElement(ie, record.EgressNetworkPolicyNamespace)
+		}
+		if err != nil {
+			return fmt.Errorf("error while adding info element: %s to data record: %v", ie.Name, err)
+		}
+	}
+
+	sentBytes, err := fa.process.AddRecordAndSendMsg(ipfixentities.Data, dataRec.GetRecord())
+	if err != nil {
+		return fmt.Errorf("error in IPFIX exporting process when sending data record: %v", err)
+	}
+	klog.V(4).Infof("Aggregated flow record created and sent. Bytes sent: %d", sentBytes)
+	return nil
+}
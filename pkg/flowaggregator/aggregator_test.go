@@ -0,0 +1,218 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
+	ipfixtest "github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix/testing"
+)
+
+func TestAggregateFlowRecords(t *testing.T) {
+	fa := NewFlowAggregator(nil, nil, time.Minute)
+	sourceRecord := newSourceRecord()
+	fa.addRecord(sourceRecord)
+	flow := fa.flows[sourceRecord.Key()]
+	require.NotNil(t, flow)
+	assert.False(t, flow.isCorrelated())
+
+	fa.addRecord(newDestinationRecord())
+	require.Len(t, fa.flows, 1)
+	assert.True(t, flow.isCorrelated())
+	expectedRecord := &FlowRecord{
+		FlowStartTime:                 time.Unix(1600000000, 0),
+		FlowEndTime:                   time.Unix(1600000062, 0),
+		FlowEndReason:                 exporter.ActiveTimeoutReason,
+		SourceAddress:                 net.ParseIP("10.10.0.1").To4(),
+		DestinationAddress:            net.ParseIP("10.10.1.2").To4(),
+		SourcePort:                    35402,
+		DestinationPort:               80,
+		Protocol:                      6,
+		Packets:                       102,
+		Bytes:                         10200,
+		ReversePackets:                80,
+		ReverseBytes:                  200000,
+		SourcePodName:                 "client",
+		SourcePodNamespace:            "default",
+		SourceNodeName:                "node1",
+		DestinationPodName:            "web",
+		DestinationPodNamespace:       "default",
+		DestinationNodeName:           "node2",
+		DestinationClusterIP:          net.ParseIP("10.96.0.10").To4(),
+		DestinationServicePortName:    "default/web:http",
+		IngressNetworkPolicyName:      "web",
+		IngressNetworkPolicyNamespace: "default",
+		EgressNetworkPolicyName:       "allow-web",
+		EgressNetworkPolicyNamespace:  "default",
+	}
+	assert.Equal(t, expectedRecord, flow.record)
+
+	// A record of another connection is not merged.
+	otherRecord := newSourceRecord()
+	otherRecord.SourcePort = 35403
+	fa.addRecord(otherRecord)
+	assert.Len(t, fa.flows, 2)
+
+	// A connection denied on the destination Node stays denied.
+	deniedRecord := newDestinationRecord()
+	deniedRecord.SourcePort = 35403
+	deniedRecord.FlowEndReason = exporter.DeniedReason
+	fa.addRecord(deniedRecord)
+	otherFlow := fa.flows[otherRecord.Key()]
+	assert.Equal(t, exporter.DeniedReason, otherFlow.record.FlowEndReason)
+	fa.addRecord(otherRecord)
+	assert.Equal(t, exporter.DeniedReason, otherFlow.record.FlowEndReason)
+}
+
+func TestSendDataRecordDeltaCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIPFIXExpProc := ipfixtest.NewMockIPFIXExportingProcess(ctrl)
+	mockDataRec := ipfixtest.NewMockIPFIXRecord(ctrl)
+	fa := NewFlowAggregator(nil, nil, time.Minute)
+	fa.process = mockIPFIXExpProc
+	for _, ie := range exporter.IANAInfoElements {
+		element, _ := ipfixregistry.GetInfoElement(ie, ipfixregistry.IANAEnterpriseID)
+		fa.elementsListv4 = append(fa.elementsListv4, element)
+	}
+	for _, ie := range exporter.IANAReverseInfoElements {
+		element, _ := ipfixregistry.GetInfoElement(ie, ipfixregistry.ReverseEnterpriseID)
+		fa.elementsListv4 = append(fa.elementsListv4, element)
+	}
+
+	values := map[string]interface{}{}
+	mockDataRec.EXPECT().AddInfoElement(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(element *ipfixentities.InfoElement, val interface{}) (uint16, error) {
+			values[element.Name] = val
+			return 0, nil
+		}).AnyTimes()
+	mockDataRec.EXPECT().GetRecord().Return(nil)
+	mockIPFIXExpProc.EXPECT().AddRecordAndSendMsg(ipfixentities.Data, nil).Return(0, nil)
+
+	flow := &aggregatedFlow{
+		record:             newDestinationRecord(),
+		prevPackets:        100,
+		prevBytes:          10000,
+		prevReversePackets: 70,
+		prevReverseBytes:   150000,
+	}
+	require.NoError(t, fa.sendDataRecord(mockDataRec, flow, false))
+	assert.Equal(t, uint64(102), values["packetTotalCount"])
+	assert.Equal(t, uint64(2), values["packetDeltaCount"])
+	assert.Equal(t, uint64(200), values["octetDeltaCount"])
+	assert.Equal(t, uint64(10), values["reverse_PacketDeltaCount"])
+	assert.Equal(t, uint64(50000), values["reverse_OctetDeltaCount"])
+}
+
+// TestFlowAggregator sends the records of the source Node and the destination
+// Node to the flow aggregator, and validates the aggregated records received
+// by a local collector.
+func TestFlowAggregator(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	collector, records := newTestCollector(t, "tcp", stopCh)
+	fa := NewFlowAggregator(newLocalAddr(t, "udp"), collector.localAddr(), time.Minute)
+	require.NoError(t, fa.collectingProcess.listen())
+	go fa.collectingProcess.serve(stopCh)
+	defer func() {
+		if fa.process != nil {
+			fa.process.CloseConnToCollector()
+		}
+	}()
+	sourceExporter := newTestExporter(t, fa.collectingProcess.localAddr())
+	defer sourceExporter.process.CloseConnToCollector()
+	destinationExporter := newTestExporter(t, fa.collectingProcess.localAddr())
+	defer destinationExporter.process.CloseConnToCollector()
+
+	waitForFlow := func(key FlowKey, condition func(flow *aggregatedFlow) bool) {
+		err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			fa.flowsLock.Lock()
+			defer fa.flowsLock.Unlock()
+			flow, exists := fa.flows[key]
+			return exists && condition(flow), nil
+		})
+		require.NoError(t, err)
+	}
+
+	sourceRecord := newSourceRecord()
+	key := sourceRecord.Key()
+	sendRecord(t, sourceExporter, sourceRecord)
+	waitForFlow(key, func(flow *aggregatedFlow) bool { return true })
+	// The flow is not exported until the record of the destination Node is
+	// received.
+	require.NoError(t, fa.export())
+	destinationRecord := newDestinationRecord()
+	sendRecord(t, destinationExporter, destinationRecord)
+	waitForFlow(key, (*aggregatedFlow).isCorrelated)
+	require.NoError(t, fa.export())
+	record := receiveRecord(t, records)
+	assert.Equal(t, "client", record.SourcePodName)
+	assert.Equal(t, "node1", record.SourceNodeName)
+	assert.Equal(t, "web", record.DestinationPodName)
+	assert.Equal(t, "node2", record.DestinationNodeName)
+	assert.Equal(t, "default/web:http", record.DestinationServicePortName)
+	assert.Equal(t, "web", record.IngressNetworkPolicyName)
+	assert.Equal(t, "allow-web", record.EgressNetworkPolicyName)
+	assert.Equal(t, uint64(102), record.Packets)
+
+	// The flow is exported again only when it's updated.
+	require.NoError(t, fa.export())
+	destinationRecord.Packets = 110
+	sendRecord(t, destinationExporter, destinationRecord)
+	waitForFlow(key, func(flow *aggregatedFlow) bool { return flow.record.Packets == 110 })
+	require.NoError(t, fa.export())
+	record = receiveRecord(t, records)
+	assert.Equal(t, uint64(110), record.Packets)
+	assert.Equal(t, "client", record.SourcePodName)
+
+	// A flow to a destination out of the cluster is never correlated, it's
+	// exported after it's been waited for one export interval.
+	externalRecord := newSourceRecord()
+	externalRecord.DestinationAddress = net.ParseIP("8.8.8.8").To4()
+	externalRecord.DestinationClusterIP = nil
+	externalRecord.DestinationServicePortName = ""
+	sendRecord(t, sourceExporter, externalRecord)
+	waitForFlow(externalRecord.Key(), func(flow *aggregatedFlow) bool { return true })
+	fa.flowsLock.Lock()
+	fa.flows[externalRecord.Key()].firstSeen = time.Now().Add(-time.Minute)
+	fa.flowsLock.Unlock()
+	require.NoError(t, fa.export())
+	assert.Equal(t, externalRecord, receiveRecord(t, records))
+
+	// Inactive flows are removed.
+	fa.flowsLock.Lock()
+	for _, flow := range fa.flows {
+		flow.lastUpdated = time.Now().Add(-inactiveFlowTimeoutFactor * time.Minute)
+	}
+	fa.flowsLock.Unlock()
+	require.NoError(t, fa.export())
+	assert.Empty(t, fa.flows)
+	select {
+	case record := <-records:
+		t.Errorf("Unexpected record %v", record)
+	default:
+	}
+}
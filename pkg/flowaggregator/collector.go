@@ -0,0 +1,469 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	"github.com/vmware/go-ipfix/pkg/util"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix"
+)
+
+const (
+	ipfixVersion        = 10
+	messageHeaderLength = 16
+	setHeaderLength     = 4
+	// The length field of the IPFIX message header is 16 bits.
+	maxMessageLength = 65535
+	// The set IDs of data sets are greater than or equal to 256.
+	minDataSetID = 256
+	// minRetryDelay and maxRetryDelay bound the delay before accepting a
+	// connection or receiving a message again after a temporary error.
+	minRetryDelay = 5 * time.Millisecond
+	maxRetryDelay = time.Second
+)
+
+// recordHandler is called for every data record decoded by the collecting
+// process.
+type recordHandler func(record *FlowRecord)
+
+// collectingProcess receives the IPFIX messages sent by the Flow Exporters over
+// TCP or UDP, and decodes the data records with the templates received from
+// each observation domain. The collecting process of go-ipfix is not used as it
+// doesn't hand the decoded records over to its consumer.
+type collectingProcess struct {
+	address net.Addr
+	handler recordHandler
+	// tcpListener or udpConn is set when the collecting process starts to
+	// listen, depending on the transport protocol of address.
+	tcpListener net.Listener
+	udpConn     *net.UDPConn
+
+	templatesLock sync.RWMutex
+	// templates is a map from observation domain ID to template ID to the
+	// elements of the template.
+	templates map[uint32]map[uint16][]*ipfixentities.InfoElement
+}
+
+func newCollectingProcess(address net.Addr, handler recordHandler) *collectingProcess {
+	return &collectingProcess{
+		address:   address,
+		handler:   handler,
+		templates: make(map[uint32]map[uint16][]*ipfixentities.InfoElement),
+	}
+}
+
+// listen starts listening on the address of the collecting process.
+func (cp *collectingProcess) listen() error {
+	var err error
+	if cp.address.Network() == "udp" {
+		cp.udpConn, err = net.ListenUDP("udp", cp.address.(*net.UDPAddr))
+	} else {
+		cp.tcpListener, err = net.Listen("tcp", cp.address.String())
+	}
+	if err != nil {
+		return fmt.Errorf("error when listening on %s: %v", cp.address, err)
+	}
+	klog.Infof("Started %s collecting process on %s", cp.address.Network(), cp.localAddr())
+	return nil
+}
+
+// localAddr returns the address the collecting process listens on, which has
+// the actual port when the port of address is 0.
+func (cp *collectingProcess) localAddr() net.Addr {
+	if cp.udpConn != nil {
+		return cp.udpConn.LocalAddr()
+	}
+	return cp.tcpListener.Addr()
+}
+
+// serve receives IPFIX messages until stopCh is closed. listen must be called
+// first. Temporary errors, e.g. when running out of file descriptors, are
+// retried with a growing delay. An error is returned if the collecting process
+// can't receive messages anymore, and nil is returned after stopCh is closed.
+func (cp *collectingProcess) serve(stopCh <-chan struct{}) error {
+	if cp.udpConn != nil {
+		go func() {
+			<-stopCh
+			cp.udpConn.Close()
+		}()
+		return cp.serveUDP(stopCh)
+	}
+	go func() {
+		<-stopCh
+		cp.tcpListener.Close()
+	}()
+	var retryDelay time.Duration
+	for {
+		conn, err := cp.tcpListener.Accept()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return nil
+			default:
+			}
+			if !isTemporaryError(err) {
+				return fmt.Errorf("error when accepting connection in collecting process: %v", err)
+			}
+			retryDelay = nextRetryDelay(retryDelay)
+			klog.Errorf("Error when accepting connection in collecting process, retrying in %v: %v", retryDelay, err)
+			if !sleepUntilStopped(retryDelay, stopCh) {
+				return nil
+			}
+			continue
+		}
+		retryDelay = 0
+		klog.V(2).Infof("Accepted connection from Flow Exporter %s", conn.RemoteAddr())
+		go cp.handleTCPConn(conn, stopCh)
+	}
+}
+
+func (cp *collectingProcess) serveUDP(stopCh <-chan struct{}) error {
+	buffer := make([]byte, maxMessageLength)
+	var retryDelay time.Duration
+	for {
+		size, address, err := cp.udpConn.ReadFromUDP(buffer)
+		if err != nil {
+			select {
+			case <-stopCh:
+				return nil
+			default:
+			}
+			if !isTemporaryError(err) {
+				return fmt.Errorf("error when receiving IPFIX message in collecting process: %v", err)
+			}
+			retryDelay = nextRetryDelay(retryDelay)
+			klog.Errorf("Error when receiving IPFIX message in collecting process, retrying in %v: %v", retryDelay, err)
+			if !sleepUntilStopped(retryDelay, stopCh) {
+				return nil
+			}
+			continue
+		}
+		retryDelay = 0
+		// Every UDP datagram carries exactly one IPFIX message.
+		if err := cp.decodeMessage(buffer[:size]); err != nil {
+			klog.Errorf("Error when decoding IPFIX message from %s: %v", address, err)
+		}
+	}
+}
+
+// isTemporaryError returns whether the network error is temporary, i.e. the
+// operation may succeed if retried.
+func isTemporaryError(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Temporary()
+}
+
+// nextRetryDelay doubles the retry delay, within minRetryDelay and
+// maxRetryDelay.
+func nextRetryDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay < minRetryDelay {
+		return minRetryDelay
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// sleepUntilStopped waits for the delay, and returns false if stopCh is closed
+// in the meantime.
+func sleepUntilStopped(delay time.Duration, stopCh <-chan struct{}) bool {
+	select {
+	case <-stopCh:
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+func (cp *collectingProcess) handleTCPConn(conn net.Conn, stopCh <-chan struct{}) {
+	done := make(chan struct{})
+	defer close(done)
+	defer conn.Close()
+	go func() {
+		select {
+		case <-stopCh:
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		header := make([]byte, messageHeaderLength)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			if err != io.EOF {
+				klog.Errorf("Error when receiving IPFIX message from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		length := int(binary.BigEndian.Uint16(header[2:4]))
+		if length < messageHeaderLength {
+			// The messages can't be delimited anymore.
+			klog.Errorf("Invalid IPFIX message length %d from %s, closing the connection", length, conn.RemoteAddr())
+			return
+		}
+		message := make([]byte, length)
+		copy(message, header)
+		if _, err := io.ReadFull(conn, message[messageHeaderLength:]); err != nil {
+			klog.Errorf("Error when receiving IPFIX message from %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+		if err := cp.decodeMessage(message); err != nil {
+			klog.Errorf("Error when decoding IPFIX message from %s: %v", conn.RemoteAddr(), err)
+		}
+	}
+}
+
+// decodeMessage decodes all the sets of an IPFIX message. The templates of
+// template sets are saved, and the records of data sets are passed to the
+// handler of the collecting process.
+func (cp *collectingProcess) decodeMessage(message []byte) error {
+	buffer := bytes.NewBuffer(message)
+	var version, length uint16
+	var exportTime, seqNumber, obsDomainID uint32
+	if err := util.Decode(buffer, &version, &length, &exportTime, &seqNumber, &obsDomainID); err != nil {
+		return err
+	}
+	if version != ipfixVersion {
+		return fmt.Errorf("IPFIX version %d is not supported", version)
+	}
+	for buffer.Len() >= setHeaderLength {
+		var setID, setLength uint16
+		if err := util.Decode(buffer, &setID, &setLength); err != nil {
+			return err
+		}
+		if int(setLength) < setHeaderLength || int(setLength)-setHeaderLength > buffer.Len() {
+			return fmt.Errorf("invalid length %d of set %d", setLength, setID)
+		}
+		setBuffer := bytes.NewBuffer(buffer.Next(int(setLength) - setHeaderLength))
+		if setID == ipfixentities.TemplateSetID {
+			if err := cp.decodeTemplateSet(setBuffer, obsDomainID); err != nil {
+				return err
+			}
+		} else if setID >= minDataSetID {
+			if err := cp.decodeDataSet(setBuffer, obsDomainID, setID); err != nil {
+				return err
+			}
+		} else {
+			klog.V(4).Infof("Ignoring set %d which is not supported", setID)
+		}
+	}
+	return nil
+}
+
+func (cp *collectingProcess) decodeTemplateSet(buffer *bytes.Buffer, obsDomainID uint32) error {
+	for buffer.Len() >= 4 {
+		var templateID, fieldCount uint16
+		if err := util.Decode(buffer, &templateID, &fieldCount); err != nil {
+			return err
+		}
+		// A template record with no fields withdraws the template.
+		if fieldCount == 0 {
+			cp.deleteTemplate(obsDomainID, templateID)
+			continue
+		}
+		elements := make([]*ipfixentities.InfoElement, 0, fieldCount)
+		for i := 0; i < int(fieldCount); i++ {
+			var elementID, elementLength uint16
+			if err := util.Decode(buffer, &elementID, &elementLength); err != nil {
+				return err
+			}
+			enterpriseID := ipfixregistry.IANAEnterpriseID
+			// The enterprise bit indicates an enterprise-specific element,
+			// which is followed by the enterprise number.
+			if elementID&0x8000 != 0 {
+				elementID = elementID ^ 0x8000
+				if err := util.Decode(buffer, &enterpriseID); err != nil {
+					return err
+				}
+			}
+			element, err := ipfix.GetInfoElementFromID(elementID, enterpriseID)
+			if err != nil {
+				return fmt.Errorf("error when decoding template %d: %v", templateID, err)
+			}
+			elements = append(elements, element)
+		}
+		cp.addTemplate(obsDomainID, templateID, elements)
+	}
+	return nil
+}
+
+func (cp *collectingProcess) decodeDataSet(buffer *bytes.Buffer, obsDomainID uint32, templateID uint16) error {
+	elements, exists := cp.getTemplate(obsDomainID, templateID)
+	if !exists {
+		return fmt.Errorf("template %d of observation domain %d does not exist", templateID, obsDomainID)
+	}
+	// The remaining bytes are padding if they can't make a record.
+	minRecordLength := 0
+	for _, element := range elements {
+		if element.Len == ipfixentities.VariableLength {
+			minRecordLength++
+		} else {
+			minRecordLength += int(element.Len)
+		}
+	}
+	for buffer.Len() > 0 && buffer.Len() >= minRecordLength {
+		dataSet := ipfixentities.NewDataSet()
+		for _, element := range elements {
+			length := int(element.Len)
+			if element.Len == ipfixentities.VariableLength {
+				var err error
+				if length, err = decodeVariableLength(buffer); err != nil {
+					return err
+				}
+			}
+			value := buffer.Next(length)
+			if len(value) != length {
+				return fmt.Errorf("data record of template %d is truncated", templateID)
+			}
+			if err := dataSet.AddInfoElement(element, bytes.NewBuffer(value)); err != nil {
+				return err
+			}
+		}
+		record := &FlowRecord{}
+		for _, element := range elements {
+			record.setField(element.Name, dataSet[element.EnterpriseId][element.ElementId])
+		}
+		cp.handler(record)
+	}
+	return nil
+}
+
+// decodeVariableLength decodes the length of a variable-length element, see
+// https://tools.ietf.org/html/rfc7011#section-7.
+func decodeVariableLength(buffer *bytes.Buffer) (int, error) {
+	var length uint8
+	if err := util.Decode(buffer, &length); err != nil {
+		return 0, err
+	}
+	if length < 255 {
+		return int(length), nil
+	}
+	var longLength uint16
+	if err := util.Decode(buffer, &longLength); err != nil {
+		return 0, err
+	}
+	return int(longLength), nil
+}
+
+func (cp *collectingProcess) addTemplate(obsDomainID uint32, templateID uint16, elements []*ipfixentities.InfoElement) {
+	cp.templatesLock.Lock()
+	defer cp.templatesLock.Unlock()
+	if _, exists := cp.templates[obsDomainID]; !exists {
+		cp.templates[obsDomainID] = make(map[uint16][]*ipfixentities.InfoElement)
+	}
+	cp.templates[obsDomainID][templateID] = elements
+}
+
+func (cp *collectingProcess) deleteTemplate(obsDomainID uint32, templateID uint16) {
+	cp.templatesLock.Lock()
+	defer cp.templatesLock.Unlock()
+	delete(cp.templates[obsDomainID], templateID)
+}
+
+func (cp *collectingProcess) getTemplate(obsDomainID uint32, templateID uint16) ([]*ipfixentities.InfoElement, bool) {
+	cp.templatesLock.RLock()
+	defer cp.templatesLock.RUnlock()
+	elements, exists := cp.templates[obsDomainID][templateID]
+	return elements, exists
+}
+
+// setField sets the field of the record corresponding to the information
+// element. The elements which are not part of the template of the Flow
+// Exporter are ignored.
+func (r *FlowRecord) setField(name string, value interface{}) {
+	switch v := value.(type) {
+	case uint8:
+		switch name {
+		case "protocolIdentifier":
+			r.Protocol = v
+		case "flowEndReason":
+			r.FlowEndReason = v
+		}
+	case uint16:
+		switch name {
+		case "sourceTransportPort":
+			r.SourcePort = v
+		case "destinationTransportPort":
+			r.DestinationPort = v
+		}
+	case uint64:
+		switch name {
+		case "flowStartSeconds":
+			r.FlowStartTime = time.Unix(int64(v), 0)
+		case "flowEndSeconds":
+			r.FlowEndTime = time.Unix(int64(v), 0)
+		case "packetTotalCount":
+			r.Packets = v
+		case "octetTotalCount":
+			r.Bytes = v
+		case "reverse_PacketTotalCount":
+			r.ReversePackets = v
+		case "reverse_OctetTotalCount":
+			r.ReverseBytes = v
+		}
+	case []byte:
+		ip := net.IP(append([]byte(nil), v...))
+		switch name {
+		case "sourceIPv4Address", "sourceIPv6Address":
+			r.SourceAddress = ip
+		case "destinationIPv4Address", "destinationIPv6Address":
+			r.DestinationAddress = ip
+		case "destinationClusterIP", "destinationClusterIPv6":
+			// The Flow Exporter sends 0.0.0.0 or :: if the destination is
+			// not a Service.
+			if !ip.IsUnspecified() {
+				r.DestinationClusterIP = ip
+			}
+		}
+	case string:
+		switch name {
+		case "sourcePodName":
+			r.SourcePodName = v
+		case "sourcePodNamespace":
+			r.SourcePodNamespace = v
+		case "sourceNodeName":
+			r.SourceNodeName = v
+		case "destinationPodName":
+			r.DestinationPodName = v
+		case "destinationPodNamespace":
+			r.DestinationPodNamespace = v
+		case "destinationNodeName":
+			r.DestinationNodeName = v
+		case "destinationServicePortName":
+			r.DestinationServicePortName = v
+		case "ingressNetworkPolicyName":
+			r.IngressNetworkPolicyName = v
+		case "ingressNetworkPolicyNamespace":
+			r.IngressNetworkPolicyNamespace = v
+		case "egressNetworkPolicyName":
+			r.EgressNetworkPolicyName = v
+		case "egressNetworkPolicyNamespace":
+			r.EgressNetworkPolicyNamespace = v
+		}
+	}
+}
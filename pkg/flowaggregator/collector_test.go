@@ -0,0 +1,245 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/ipfix"
+)
+
+func newLocalAddr(t *testing.T, network string) net.Addr {
+	var addr net.Addr
+	var err error
+	if network == "udp" {
+		addr, err = net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	} else {
+		addr, err = net.ResolveTCPAddr("tcp", "127.0.0.1:0")
+	}
+	require.NoError(t, err)
+	return addr
+}
+
+// newTestCollector starts a collecting process on a local address, which
+// passes the received records to the returned channel.
+func newTestCollector(t *testing.T, network string, stopCh <-chan struct{}) (*collectingProcess, <-chan *FlowRecord) {
+	ipfixregistry.LoadRegistry()
+	records := make(chan *FlowRecord, 10)
+	cp := newCollectingProcess(newLocalAddr(t, network), func(record *FlowRecord) {
+		records <- record
+	})
+	require.NoError(t, cp.listen())
+	go cp.serve(stopCh)
+	return cp, records
+}
+
+// newTestExporter returns a flowAggregator connected to the collector, which
+// is used to send records with the same template as the Flow Exporters. The
+// registry is not reloaded as it's read by the running collecting processes.
+func newTestExporter(t *testing.T, collector net.Addr) *flowAggregator {
+	exp := &flowAggregator{
+		externalFlowCollectorAddr: collector,
+		exportInterval:            time.Minute,
+		registry:                  ipfix.NewIPFIXRegistry(),
+	}
+	require.NoError(t, exp.initExportingProcess())
+	return exp
+}
+
+func sendRecord(t *testing.T, exp *flowAggregator, record *FlowRecord) {
+	isIPv6 := record.SourceAddress.To4() == nil
+	require.NoError(t, exp.sendDataRecord(ipfix.NewIPFIXDataRecord(exp.templateID(isIPv6)), &aggregatedFlow{record: record}, isIPv6))
+}
+
+func receiveRecord(t *testing.T, records <-chan *FlowRecord) *FlowRecord {
+	select {
+	case record := <-records:
+		return record
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout when waiting for flow record")
+	}
+	return nil
+}
+
+func newSourceRecord() *FlowRecord {
+	return &FlowRecord{
+		FlowStartTime:                time.Unix(1600000000, 0),
+		FlowEndTime:                  time.Unix(1600000060, 0),
+		FlowEndReason:                exporter.ActiveTimeoutReason,
+		SourceAddress:                net.ParseIP("10.10.0.1").To4(),
+		DestinationAddress:           net.ParseIP("10.10.1.2").To4(),
+		SourcePort:                   35402,
+		DestinationPort:              80,
+		Protocol:                     6,
+		Packets:                      100,
+		Bytes:                        10000,
+		ReversePackets:               80,
+		ReverseBytes:                 200000,
+		SourcePodName:                "client",
+		SourcePodNamespace:           "default",
+		SourceNodeName:               "node1",
+		DestinationClusterIP:         net.ParseIP("10.96.0.10").To4(),
+		DestinationServicePortName:   "default/web:http",
+		EgressNetworkPolicyName:      "allow-web",
+		EgressNetworkPolicyNamespace: "default",
+	}
+}
+
+func newDestinationRecord() *FlowRecord {
+	return &FlowRecord{
+		FlowStartTime:                 time.Unix(1600000001, 0),
+		FlowEndTime:                   time.Unix(1600000062, 0),
+		FlowEndReason:                 exporter.ActiveTimeoutReason,
+		SourceAddress:                 net.ParseIP("10.10.0.1").To4(),
+		DestinationAddress:            net.ParseIP("10.10.1.2").To4(),
+		SourcePort:                    35402,
+		DestinationPort:               80,
+		Protocol:                      6,
+		Packets:                       102,
+		Bytes:                         10200,
+		ReversePackets:                80,
+		ReverseBytes:                  200000,
+		DestinationPodName:            "web",
+		DestinationPodNamespace:       "default",
+		DestinationNodeName:           "node2",
+		IngressNetworkPolicyName:      "web",
+		IngressNetworkPolicyNamespace: "default",
+	}
+}
+
+func TestCollectingProcess(t *testing.T) {
+	for _, network := range []string{"tcp", "udp"} {
+		t.Run(network, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			cp, records := newTestCollector(t, network, stopCh)
+			exp := newTestExporter(t, cp.localAddr())
+			defer exp.process.CloseConnToCollector()
+
+			sourceRecord := newSourceRecord()
+			sendRecord(t, exp, sourceRecord)
+			assert.Equal(t, sourceRecord, receiveRecord(t, records))
+			// The dummy ClusterIP is not decoded.
+			destinationRecord := newDestinationRecord()
+			sendRecord(t, exp, destinationRecord)
+			assert.Equal(t, destinationRecord, receiveRecord(t, records))
+
+			sourceRecordIPv6 := newSourceRecord()
+			sourceRecordIPv6.SourceAddress = net.ParseIP("fd00:10:10::1")
+			sourceRecordIPv6.DestinationAddress = net.ParseIP("fd00:10:10:1::2")
+			sourceRecordIPv6.DestinationClusterIP = net.ParseIP("fd00:10:96::10")
+			sendRecord(t, exp, sourceRecordIPv6)
+			assert.Equal(t, sourceRecordIPv6, receiveRecord(t, records))
+			destinationRecordIPv6 := newDestinationRecord()
+			destinationRecordIPv6.SourceAddress = net.ParseIP("fd00:10:10::1")
+			destinationRecordIPv6.DestinationAddress = net.ParseIP("fd00:10:10:1::2")
+			sendRecord(t, exp, destinationRecordIPv6)
+			assert.Equal(t, destinationRecordIPv6, receiveRecord(t, records))
+		})
+	}
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary error" }
+func (temporaryError) Timeout() bool   { return false }
+func (temporaryError) Temporary() bool { return true }
+
+// flakyListener fails to accept connections with temporary errors for the
+// given number of times before accepting them normally.
+type flakyListener struct {
+	net.Listener
+	failures int
+}
+
+func (l *flakyListener) Accept() (net.Conn, error) {
+	if l.failures > 0 {
+		l.failures--
+		return nil, temporaryError{}
+	}
+	return l.Listener.Accept()
+}
+
+func TestCollectingProcessAcceptErrors(t *testing.T) {
+	ipfixregistry.LoadRegistry()
+	records := make(chan *FlowRecord, 10)
+	cp := newCollectingProcess(newLocalAddr(t, "tcp"), func(record *FlowRecord) {
+		records <- record
+	})
+	require.NoError(t, cp.listen())
+	listener := cp.tcpListener
+	cp.tcpListener = &flakyListener{Listener: listener, failures: 3}
+	stopCh := make(chan struct{})
+	serveErrCh := make(chan error, 1)
+	go func() {
+		serveErrCh <- cp.serve(stopCh)
+	}()
+
+	// The connection is accepted after the temporary errors.
+	exp := newTestExporter(t, cp.localAddr())
+	sourceRecord := newSourceRecord()
+	sendRecord(t, exp, sourceRecord)
+	assert.Equal(t, sourceRecord, receiveRecord(t, records))
+	exp.process.CloseConnToCollector()
+
+	// An error is returned when the listener fails permanently.
+	listener.Close()
+	select {
+	case err := <-serveErrCh:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout when waiting for the collecting process to fail")
+	}
+	close(stopCh)
+}
+
+func TestDecodeMessageErrors(t *testing.T) {
+	cp := newCollectingProcess(nil, func(record *FlowRecord) {
+		t.Errorf("Unexpected record %v", record)
+	})
+	tests := []struct {
+		name    string
+		message []byte
+	}{
+		{
+			name:    "truncated header",
+			message: []byte{0, 10, 0, 16},
+		},
+		{
+			name:    "invalid version",
+			message: []byte{0, 9, 0, 16, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+		},
+		{
+			name:    "invalid set length",
+			message: []byte{0, 10, 0, 24, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 12, 0, 0, 0, 0},
+		},
+		{
+			name:    "unknown template",
+			message: []byte{0, 10, 0, 24, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 0, 0, 8, 0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, cp.decodeMessage(tt.message))
+		})
+	}
+}
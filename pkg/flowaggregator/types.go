@@ -0,0 +1,87 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// FlowKey is the 5-tuple of a connection. The Flow Exporters of the source
+// Node and the destination Node of an inter-Node connection report the same
+// FlowKey, as the destination is the Endpoint after Service DNAT on both sides.
+type FlowKey struct {
+	SourceAddress      string
+	DestinationAddress string
+	Protocol           uint8
+	SourcePort         uint16
+	DestinationPort    uint16
+}
+
+func (k FlowKey) String() string {
+	return fmt.Sprintf("%s:%d->%s:%d/%d", k.SourceAddress, k.SourcePort, k.DestinationAddress, k.DestinationPort, k.Protocol)
+}
+
+// FlowRecord is a flow record received from a Flow Exporter. The delta
+// counts are not kept as they are computed over the aggregated flow.
+type FlowRecord struct {
+	FlowStartTime                 time.Time
+	FlowEndTime                   time.Time
+	FlowEndReason                 uint8
+	SourceAddress                 net.IP
+	DestinationAddress            net.IP
+	SourcePort                    uint16
+	DestinationPort               uint16
+	Protocol                      uint8
+	Packets                       uint64
+	Bytes                         uint64
+	ReversePackets                uint64
+	ReverseBytes                  uint64
+	SourcePodName                 string
+	SourcePodNamespace            string
+	SourceNodeName                string
+	DestinationPodName            string
+	DestinationPodNamespace       string
+	DestinationNodeName           string
+	DestinationClusterIP          net.IP
+	DestinationServicePortName    string
+	IngressNetworkPolicyName      string
+	IngressNetworkPolicyNamespace string
+	EgressNetworkPolicyName       string
+	EgressNetworkPolicyNamespace  string
+}
+
+func (r *FlowRecord) Key() FlowKey {
+	return FlowKey{
+		SourceAddress:      r.SourceAddress.String(),
+		DestinationAddress: r.DestinationAddress.String(),
+		Protocol:           r.Protocol,
+		SourcePort:         r.SourcePort,
+		DestinationPort:    r.DestinationPort,
+	}
+}
+
+// hasSourceInfo returns whether the record is exported by the Node of the
+// source Pod, which fills the source Pod and Node fields only for local Pods.
+func (r *FlowRecord) hasSourceInfo() bool {
+	return r.SourceNodeName != ""
+}
+
+// hasDestinationInfo returns whether the record is exported by the Node of the
+// destination Pod.
+func (r *FlowRecord) hasDestinationInfo() bool {
+	return r.DestinationNodeName != ""
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"net"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+)
+
+// GatewayNodeSelector selects the Nodes which can be the gateway of the cluster.
+var GatewayNodeSelector = labels.SelectorFromSet(labels.Set{mcv1alpha1.GatewayNodeLabelKey: "true"})
+
+// SelectGatewayNode selects the gateway Node of the cluster from the provided
+// Nodes, and returns nil if no Node can be the gateway. The Nodes which have
+// the gateway label, are Ready and have an IPv4 address are eligible, and the
+// first one ordered by name is selected, so that the Antrea Controller and all
+// the Antrea Agents of the cluster agree on the same Node.
+func SelectGatewayNode(nodes []*corev1.Node) *corev1.Node {
+	var eligibleNodes []*corev1.Node
+	for _, node := range nodes {
+		if !GatewayNodeSelector.Matches(labels.Set(node.Labels)) || !isNodeReady(node) || GetGatewayIP(node) == nil {
+			continue
+		}
+		eligibleNodes = append(eligibleNodes, node)
+	}
+	if len(eligibleNodes) == 0 {
+		return nil
+	}
+	sort.Slice(eligibleNodes, func(i, j int) bool {
+		return eligibleNodes[i].Name < eligibleNodes[j].Name
+	})
+	return eligibleNodes[0]
+}
+
+// GetGatewayIP returns the IP of the gateway Node which the tunnels from the
+// other clusters are connected to. The IPv4 NodeInternalIP is preferred over
+// the IPv4 NodeExternalIP. It returns nil if the Node has no IPv4 address.
+func GetGatewayIP(node *corev1.Node) net.IP {
+	for _, addrType := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP} {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type != addrType {
+				continue
+			}
+			if ip := net.ParseIP(addr.Address); ip != nil && ip.To4() != nil {
+				return ip
+			}
+		}
+	}
+	return nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multicluster
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/multicluster/v1alpha1"
+)
+
+func newNode(name string, gateway, ready bool, addresses ...corev1.NodeAddress) *corev1.Node {
+	labels := map[string]string{}
+	if gateway {
+		labels[mcv1alpha1.GatewayNodeLabelKey] = "true"
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Addresses:  addresses,
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func internalIP(ip string) corev1.NodeAddress {
+	return corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: ip}
+}
+
+func externalIP(ip string) corev1.NodeAddress {
+	return corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: ip}
+}
+
+func TestSelectGatewayNode(t *testing.T) {
+	tests := []struct {
+		name         string
+		nodes        []*corev1.Node
+		expectedNode string
+	}{
+		{
+			name: "no gateway label",
+			nodes: []*corev1.Node{
+				newNode("node1", false, true, internalIP("192.168.1.1")),
+			},
+		},
+		{
+			name: "not ready",
+			nodes: []*corev1.Node{
+				newNode("node1", true, false, internalIP("192.168.1.1")),
+			},
+		},
+		{
+			name: "no IPv4 address",
+			nodes: []*corev1.Node{
+				newNode("node1", true, true, internalIP("fd00::1")),
+			},
+		},
+		{
+			name: "first by name",
+			nodes: []*corev1.Node{
+				newNode("node3", true, true, internalIP("192.168.1.3")),
+				newNode("node2", true, true, internalIP("192.168.1.2")),
+				newNode("node1", true, false, internalIP("192.168.1.1")),
+			},
+			expectedNode: "node2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := SelectGatewayNode(tt.nodes)
+			if tt.expectedNode == "" {
+				assert.Nil(t, node)
+			} else if assert.NotNil(t, node) {
+				assert.Equal(t, tt.expectedNode, node.Name)
+			}
+		})
+	}
+}
+
+func TestGetGatewayIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		addresses  []corev1.NodeAddress
+		expectedIP net.IP
+	}{
+		{
+			name:       "internal IP preferred",
+			addresses:  []corev1.NodeAddress{externalIP("10.10.0.1"), internalIP("192.168.1.1")},
+			expectedIP: net.ParseIP("192.168.1.1"),
+		},
+		{
+			name:       "external IP",
+			addresses:  []corev1.NodeAddress{internalIP("fd00::1"), externalIP("10.10.0.1")},
+			expectedIP: net.ParseIP("10.10.0.1"),
+		},
+		{
+			name:      "no IPv4 address",
+			addresses: []corev1.NodeAddress{internalIP("fd00::1")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedIP, GetGatewayIP(newNode("node1", true, true, tt.addresses...)))
+		})
+	}
+}
@@ -24,23 +24,36 @@ import (
 type Protocol string
 type TableIDType uint8
 type GroupIDType uint32
+type MeterIDType uint32
 
 type MissActionType uint32
 type Range [2]uint32
 type OFOperation int
 
+// MultipathFields are the fields hashed by the multipath action.
+type MultipathFields uint16
+
+// MultipathAlgorithm is the algorithm used by the multipath action to map the
+// hash value to a link.
+type MultipathAlgorithm uint16
+
 const (
 	LastTableID TableIDType = 0xff
 	TableIDAll              = LastTableID
 )
 
 const (
-	ProtocolIP   Protocol = "ip"
-	ProtocolARP  Protocol = "arp"
-	ProtocolTCP  Protocol = "tcp"
-	ProtocolUDP  Protocol = "udp"
-	ProtocolSCTP Protocol = "sctp"
-	ProtocolICMP Protocol = "icmp"
+	ProtocolIP     Protocol = "ip"
+	ProtocolIPv6   Protocol = "ipv6"
+	ProtocolARP    Protocol = "arp"
+	ProtocolTCP    Protocol = "tcp"
+	ProtocolTCPv6  Protocol = "tcp6"
+	ProtocolUDP    Protocol = "udp"
+	ProtocolUDPv6  Protocol = "udp6"
+	ProtocolSCTP   Protocol = "sctp"
+	ProtocolSCTPv6 Protocol = "sctp6"
+	ProtocolICMP   Protocol = "icmp"
+	ProtocolICMPv6 Protocol = "icmp6"
 )
 
 const (
@@ -62,6 +75,9 @@ const (
 	NxmFieldARPOp       = "NXM_OF_ARP_OP"
 	NxmFieldReg         = "NXM_NX_REG"
 	NxmFieldTunMetadata = "NXM_NX_TUN_METADATA"
+	NxmFieldPktMark     = "NXM_NX_PKT_MARK"
+	NxmFieldTunIPv4Dst  = "NXM_NX_TUN_IPV4_DST"
+	NxmFieldIPDst       = "NXM_OF_IP_DST"
 )
 
 const (
@@ -70,6 +86,22 @@ const (
 	DeleteMessage
 )
 
+const (
+	// MultipathFieldsSymmetricL3L4UDP hashes the IP addresses and the TCP, UDP
+	// or SCTP ports, regardless of the direction of the packet.
+	MultipathFieldsSymmetricL3L4UDP MultipathFields = 3
+	// MultipathFieldsNwSrc hashes the source IP address.
+	MultipathFieldsNwSrc MultipathFields = 4
+)
+
+const (
+	// MultipathAlgorithmModuloN maps the hash value to the link hash % n_links.
+	MultipathAlgorithmModuloN MultipathAlgorithm = 0
+	// MultipathAlgorithmHRW uses the Highest Random Weight hashing, which
+	// minimizes the disruption when links are added.
+	MultipathAlgorithmHRW MultipathAlgorithm = 2
+)
+
 // Bridge defines operations on an openflow bridge.
 type Bridge interface {
 	CreateTable(id, next TableIDType, missAction MissActionType) Table
@@ -108,6 +140,13 @@ type Bridge interface {
 	SendPacketOut(packetOut *ofctrl.PacketOut) error
 	// BuildPacketOut returns a new PacketOutBuilder.
 	BuildPacketOut() PacketOutBuilder
+	// ResubmitPacketIn sends the packet of the provided PacketIn back to the OVS Bridge with a packetOut message. The
+	// packet is resubmitted to the provided table from the in_port of the PacketIn, with the registers of the PacketIn
+	// restored, so that its processing continues from the table.
+	ResubmitPacketIn(pktIn *ofctrl.PacketIn, tableID TableIDType) error
+	// AddMeter adds a meter which drops the packets exceeding the rate in packets per second. burst is the maximum
+	// number of packets which can exceed the rate, 0 means the default value of OVS is used.
+	AddMeter(id MeterIDType, rate, burst uint32) error
 }
 
 // TableStatus represents the status of a specific flow table. The status is useful for debugging.
@@ -142,9 +181,6 @@ type OFEntry interface {
 	// Modify / Delete methods can be called on this object. This method
 	// should be called if a reconnection event happened.
 	Reset()
-	// GetBundleMessage returns ofctrl.OpenFlowModMessage which can be used in Bundle messages. operation specifies what
-	// operation is expected to be taken on the OFEntry.
-	GetBundleMessage(operation OFOperation) (ofctrl.OpenFlowModMessage, error)
 }
 
 type Flow interface {
@@ -188,7 +224,9 @@ type Action interface {
 	Learn(id TableIDType, priority uint16, idleTimeout, hardTimeout uint16, cookieID uint64) LearnAction
 	GotoTable(table TableIDType) FlowBuilder
 	SendToController(reason uint8) FlowBuilder
+	SendToControllerWithFullPacket(reason uint8) FlowBuilder
 	Note(notes string) FlowBuilder
+	Meter(id MeterIDType) FlowBuilder
 }
 
 type FlowBuilder interface {
@@ -209,6 +247,16 @@ type FlowBuilder interface {
 	MatchARPTpa(ip net.IP) FlowBuilder
 	MatchARPOp(op uint16) FlowBuilder
 	MatchIPDscp(dscp uint8) FlowBuilder
+	// MatchICMPType matches the type of ICMP packets.
+	MatchICMPType(icmpType uint8) FlowBuilder
+	// MatchICMPCode matches the code of ICMP packets.
+	MatchICMPCode(icmpCode uint8) FlowBuilder
+	// MatchICMPv6Type matches the type of ICMPv6 packets.
+	MatchICMPv6Type(icmp6Type uint8) FlowBuilder
+	// MatchICMPv6Code matches the code of ICMPv6 packets.
+	MatchICMPv6Code(icmp6Code uint8) FlowBuilder
+	// MatchNDTarget matches the target address of IPv6 Neighbor Discovery packets.
+	MatchNDTarget(target net.IP) FlowBuilder
 	MatchCTStateNew(isSet bool) FlowBuilder
 	MatchCTStateRel(isSet bool) FlowBuilder
 	MatchCTStateRpl(isSet bool) FlowBuilder
@@ -219,8 +267,16 @@ type FlowBuilder interface {
 	MatchCTLabelRange(high, low uint64, bitRange Range) FlowBuilder
 	MatchConjID(value uint32) FlowBuilder
 	MatchTCPDstPort(port uint16) FlowBuilder
+	MatchTCPSrcPort(port uint16) FlowBuilder
 	MatchUDPDstPort(port uint16) FlowBuilder
+	MatchUDPSrcPort(port uint16) FlowBuilder
 	MatchSCTPDstPort(port uint16) FlowBuilder
+	// MatchTCPDstPortWithMask matches the TCP destination port with a bitwise mask.
+	MatchTCPDstPortWithMask(port, mask uint16) FlowBuilder
+	// MatchUDPDstPortWithMask matches the UDP destination port with a bitwise mask.
+	MatchUDPDstPortWithMask(port, mask uint16) FlowBuilder
+	// MatchSCTPDstPortWithMask matches the SCTP destination port with a bitwise mask.
+	MatchSCTPDstPortWithMask(port, mask uint16) FlowBuilder
 	MatchTunMetadata(index int, data uint32) FlowBuilder
 	// MatchCTSrcIP matches the source IPv4 address of the connection tracker original direction tuple.
 	MatchCTSrcIP(ip net.IP) FlowBuilder
@@ -269,6 +325,9 @@ type BucketBuilder interface {
 	Weight(val uint16) BucketBuilder
 	LoadReg(regID int, data uint32) BucketBuilder
 	LoadRegRange(regID int, data uint32, rng Range) BucketBuilder
+	// Multipath hashes the fields and stores the link chosen from [0, nLinks)
+	// by the algorithm in the target register at the specified range.
+	Multipath(fields MultipathFields, algorithm MultipathAlgorithm, nLinks uint16, regID int, rng Range) BucketBuilder
 	ResubmitToTable(tableID TableIDType) BucketBuilder
 	Done() Group
 }
@@ -302,12 +361,15 @@ type PacketOutBuilder interface {
 	SetTCPSrcPort(port uint16) PacketOutBuilder
 	SetTCPDstPort(port uint16) PacketOutBuilder
 	SetTCPFlags(flags uint8) PacketOutBuilder
+	SetTCPSeqNum(seqNum uint32) PacketOutBuilder
+	SetTCPAckNum(ackNum uint32) PacketOutBuilder
 	SetUDPSrcPort(port uint16) PacketOutBuilder
 	SetUDPDstPort(port uint16) PacketOutBuilder
 	SetICMPType(icmpType uint8) PacketOutBuilder
 	SetICMPCode(icmpCode uint8) PacketOutBuilder
 	SetICMPID(id uint16) PacketOutBuilder
 	SetICMPSequence(seq uint16) PacketOutBuilder
+	SetICMPData(data []byte) PacketOutBuilder
 	SetInport(inPort uint32) PacketOutBuilder
 	SetOutport(outport uint32) PacketOutBuilder
 	AddLoadAction(name string, data uint64, rng Range) PacketOutBuilder
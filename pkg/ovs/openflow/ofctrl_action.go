@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
-	"strings"
 
 	"github.com/contiv/libOpenflow/openflow13"
 	"github.com/contiv/ofnet/ofctrl"
@@ -326,6 +325,43 @@ func (a *ofFlowAction) SendToController(reason uint8) FlowBuilder {
 	return a.builder
 }
 
+// SendToControllerWithFullPacket is an action to send the whole packet to the
+// controller. SendToController only sends the first 128 bytes of the packet,
+// which is not enough if the controller needs to parse the payload.
+func (a *ofFlowAction) SendToControllerWithFullPacket(reason uint8) FlowBuilder {
+	controllerAct := &nxControllerFullPacket{
+		controllerID: a.builder.ofFlow.Table.Switch.GetControllerID(),
+		reason:       reason,
+	}
+	a.builder.ApplyAction(controllerAct)
+	return a.builder
+}
+
+// Meter is an action to apply the meter to the packets. The packets exceeding the rate of the meter are dropped, and
+// the other actions of the flow are not applied to them.
+func (a *ofFlowAction) Meter(id MeterIDType) FlowBuilder {
+	a.builder.ofFlow.meterID = &id
+	return a.builder
+}
+
+// nxControllerFullPacket is the same as ofctrl.NXController except that it
+// doesn't limit the length of the packet sent to the controller.
+type nxControllerFullPacket struct {
+	controllerID uint16
+	reason       uint8
+}
+
+func (a *nxControllerFullPacket) GetActionMessage() openflow13.Action {
+	action := openflow13.NewNXActionController(a.controllerID)
+	action.MaxLen = openflow13.OFPCML_NO_BUFFER
+	action.Reason = a.reason
+	return action
+}
+
+func (a *nxControllerFullPacket) GetActionType() string {
+	return ofctrl.ActTypeController
+}
+
 //  Learn is an action which adds or modifies a flow in an OpenFlow table.
 func (a *ofFlowAction) Learn(id TableIDType, priority uint16, idleTimeout, hardTimeout uint16, cookieID uint64) LearnAction {
 	la := &ofLearnAction{
@@ -357,18 +393,28 @@ func (a *ofLearnAction) MatchEthernetProtocolIP() LearnAction {
 }
 
 // MatchTransportDst specifies that the transport layer destination field
-// {tcp|udp}_dst in the learned flow must match the same field of the packet
-// currently being processed. It only accepts ProtocolTCP or ProtocolUDP,
-// otherwise this does nothing.
+// {tcp|udp|sctp}_dst in the learned flow must match the same field of the packet
+// currently being processed, and that the learned flow must match the IP protocol.
+// It only accepts ProtocolTCP, ProtocolUDP or ProtocolSCTP, otherwise this does
+// nothing.
 func (a *ofLearnAction) MatchTransportDst(protocol Protocol) LearnAction {
-	if protocol != ProtocolTCP && protocol != ProtocolUDP && protocol != ProtocolSCTP {
+	var ipProtocol uint8
+	var fieldName string
+	switch protocol {
+	case ProtocolTCP:
+		ipProtocol, fieldName = ofctrl.IP_PROTO_TCP, "NXM_OF_TCP_DST"
+	case ProtocolUDP:
+		ipProtocol, fieldName = ofctrl.IP_PROTO_UDP, "NXM_OF_UDP_DST"
+	case ProtocolSCTP:
+		// There is no NXM field for SCTP ports, the OXM field is used.
+		ipProtocol, fieldName = ofctrl.IP_PROTO_SCTP, "OXM_OF_SCTP_DST"
+	default:
 		return a
 	}
 	a.MatchEthernetProtocolIP()
 	ipTypeVal := make([]byte, 2)
-	ipTypeVal[1] = byte(ofctrl.IP_PROTO_TCP)
+	ipTypeVal[1] = ipProtocol
 	a.nxLearn.AddMatch(&ofctrl.LearnField{Name: "NXM_OF_IP_PROTO"}, 1*8, nil, ipTypeVal)
-	fieldName := fmt.Sprintf("NXM_OF_%s_DST", strings.ToUpper(string(protocol)))
 	a.nxLearn.AddMatch(&ofctrl.LearnField{Name: fieldName}, 2*8, &ofctrl.LearnField{Name: fieldName}, nil)
 	return a
 }
@@ -385,7 +431,7 @@ func (a *ofLearnAction) MatchLearnedUDPDstPort() LearnAction {
 	return a.MatchTransportDst(ProtocolUDP)
 }
 
-// MatchLearnedUDPDstPort specifies that the sctp_dst field in the learned flow
+// MatchLearnedSCTPDstPort specifies that the sctp_dst field in the learned flow
 // must match the sctp_dst of the packet currently being processed.
 func (a *ofLearnAction) MatchLearnedSCTPDstPort() LearnAction {
 	return a.MatchTransportDst(ProtocolSCTP)
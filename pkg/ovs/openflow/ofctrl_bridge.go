@@ -16,6 +16,8 @@ import (
 
 const (
 	ofTableExistsError = "Table already exists"
+	// nxmRegCount is the number of the 32-bit registers NXM_NX_REG0 to NXM_NX_REG15 supported by OVS.
+	nxmRegCount = 16
 )
 
 // ofTable implements openflow.Table.
@@ -86,7 +88,9 @@ func (t *ofTable) BuildFlow(priority uint16) FlowBuilder {
 	fb := new(ofFlowBuilder)
 	fb.table = t
 	// Set ofctl.Table to Flow, otherwise the flow can't find OFSwitch to install.
-	fb.Flow = &ofctrl.Flow{Table: t.Table, Match: ofctrl.FlowMatch{Priority: priority}}
+	// Set an empty element as the NextElem of the Flow, so that ofctrl.Flow generates the FlowMod message without any
+	// instructions. The instructions are added by ofFlow.
+	fb.Flow = &ofctrl.Flow{Table: t.Table, Match: ofctrl.FlowMatch{Priority: priority}, NextElem: ofctrl.NewEmptyElem()}
 	return fb
 }
 
@@ -206,7 +210,7 @@ func (b *OFBridge) DumpTableStatus() []TableStatus {
 
 // PacketRcvd is a callback when a packetIn is received on ofctrl.OFSwitch.
 func (b *OFBridge) PacketRcvd(sw *ofctrl.OFSwitch, packet *ofctrl.PacketIn) {
-	klog.Infof("Received packet: %+v", packet)
+	klog.V(2).Infof("Received packet: %+v", packet)
 	reason := packet.Reason
 	ch, found := b.pktConsumers.Load(reason)
 	if found {
@@ -353,11 +357,11 @@ func (b *OFBridge) AddFlowsInBundle(addflows []Flow, modFlows []Flow, delFlows [
 			// the BundleAdd message. An absence of error does not mean that all Openflow entries are added into the
 			// bundle by the switch. The number of entries successfully added to the bundle by the switch will be
 			// returned by function "Complete".
-			flowMod, err := ofFlow.Flow.GetBundleMessage(operation)
+			flowMod, err := ofFlow.getFlowMod(operation)
 			if err != nil {
 				return err
 			}
-			if err := tx.AddMessage(flowMod); err != nil {
+			if err := tx.AddFlow(flowMod); err != nil {
 				// Close the bundle and abort it if there is error when adding the FlowMod message.
 				_, err := tx.Complete()
 				if err == nil {
@@ -458,15 +462,26 @@ func (b *OFBridge) AddOFEntriesInBundle(addEntries []OFEntry, modEntries []OFEnt
 			return nil
 		}
 		for _, e := range entrySet {
-			msg, err := e.entry.GetBundleMessage(e.operation)
-			if err != nil {
-				return err
+			var add func() error
+			switch entry := e.entry.(type) {
+			case *ofFlow:
+				flowMod, err := entry.getFlowMod(getFlowModCommand(e.operation))
+				if err != nil {
+					return err
+				}
+				add = func() error { return tx.AddFlow(flowMod) }
+			case *ofGroup:
+				msg, err := entry.GetBundleMessage(e.operation)
+				if err != nil {
+					return err
+				}
+				add = func() error { return tx.AddMessage(msg) }
 			}
-			// "AddMessage" operation is async, the function only returns error which occur when constructing and sending
-			// the BundleAdd message. An absence of error does not mean that all OpenFlow entries are added into the
-			// bundle by the switch. The number of entries successfully added to the bundle by the switch will be
-			// returned by function "Complete".
-			if err := tx.AddMessage(msg); err != nil {
+			// "AddFlow" and "AddMessage" operations are async, the functions only return error which occur when
+			// constructing and sending the BundleAdd message. An absence of error does not mean that all OpenFlow
+			// entries are added into the bundle by the switch. The number of entries successfully added to the bundle
+			// by the switch will be returned by function "Complete".
+			if err := add(); err != nil {
 				// Close the bundle and abort it if there is error when adding the FlowMod message.
 				_, err := tx.Complete()
 				if err == nil {
@@ -545,6 +560,51 @@ func (b *OFBridge) BuildPacketOut() PacketOutBuilder {
 	}
 }
 
+// ResubmitPacketIn sends the packet of the PacketIn back to the OFSwitch, with the actions to load the registers of the
+// PacketIn and resubmit the packet to the provided table. The registers are set to zero when a packet is received by
+// OVS, so only the non-zero registers included in the PacketIn need to be restored.
+func (b *OFBridge) ResubmitPacketIn(pktIn *ofctrl.PacketIn, tableID TableIDType) error {
+	matches := pktIn.GetMatches()
+	inPortMatch := matches.GetMatchByName("OXM_OF_IN_PORT")
+	if inPortMatch == nil {
+		return errors.New("in_port of PacketIn not found")
+	}
+	inPort, ok := inPortMatch.GetValue().(uint32)
+	if !ok {
+		return errors.New("in_port of PacketIn cannot be got")
+	}
+	packetOut := openflow13.NewPacketOut()
+	packetOut.InPort = inPort
+	regRange := Range{0, 31}
+	for i := 0; i < nxmRegCount; i++ {
+		regName := fmt.Sprintf("%s%d", NxmFieldReg, i)
+		regMatch := matches.GetMatchByName(regName)
+		if regMatch == nil {
+			continue
+		}
+		regValue, ok := regMatch.GetValue().(*ofctrl.NXRegister)
+		if !ok {
+			return fmt.Errorf("%s of PacketIn cannot be got", regName)
+		}
+		loadAction, err := ofctrl.NewNXLoadAction(regName, uint64(regValue.Data), regRange.ToNXRange())
+		if err != nil {
+			return err
+		}
+		packetOut.AddAction(loadAction.GetActionMessage())
+	}
+	table := uint8(tableID)
+	packetOut.AddAction(ofctrl.NewResubmit(nil, &table).GetActionMessage())
+	packetOut.Data = &pktIn.Data
+	return b.ofSwitch.Send(packetOut)
+}
+
+// AddMeter adds a meter with a drop band to the OFSwitch. The MeterMod message is handled by OVS asynchronously, and
+// OVS replies with an error if the meter exists already, e.g. after the OFSwitch is reconnected, in which case the
+// existing meter is kept unchanged.
+func (b *OFBridge) AddMeter(id MeterIDType, rate, burst uint32) error {
+	return b.ofSwitch.Send(newMeterMod(meterModCommandAdd, id, rate, burst))
+}
+
 // MaxRetry is a callback from OFController. It sets the max retry count that OFController attempts to connect to OFSwitch.
 func (b *OFBridge) MaxRetry() int {
 	return b.maxRetrySec
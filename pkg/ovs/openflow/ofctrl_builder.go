@@ -240,18 +240,28 @@ func (b *ofFlowBuilder) MatchInPort(inPort uint32) FlowBuilder {
 	return b
 }
 
-// MatchDstIP adds match condition for matching destination IP address.
+// MatchDstIP adds match condition for matching destination IP address. Both
+// IPv4 and IPv6 addresses are supported.
 func (b *ofFlowBuilder) MatchDstIP(ip net.IP) FlowBuilder {
-	b.matchers = append(b.matchers, fmt.Sprintf("nw_dst=%s", ip.String()))
+	if ip.To4() == nil {
+		b.matchers = append(b.matchers, fmt.Sprintf("ipv6_dst=%s", ip.String()))
+	} else {
+		b.matchers = append(b.matchers, fmt.Sprintf("nw_dst=%s", ip.String()))
+	}
 	b.Match.IpDa = &ip
 	return b
 }
 
-// MatchDstIPNet adds match condition for matching destination IP CIDR.
+// MatchDstIPNet adds match condition for matching destination IP CIDR. Both
+// IPv4 and IPv6 CIDRs are supported.
 func (b *ofFlowBuilder) MatchDstIPNet(ipnet net.IPNet) FlowBuilder {
-	b.matchers = append(b.matchers, fmt.Sprintf("nw_dst=%s", ipnet.String()))
+	if ipnet.IP.To4() == nil {
+		b.matchers = append(b.matchers, fmt.Sprintf("ipv6_dst=%s", ipnet.String()))
+	} else {
+		b.matchers = append(b.matchers, fmt.Sprintf("nw_dst=%s", ipnet.String()))
+	}
 	b.Match.IpDa = &ipnet.IP
-	b.Match.IpDaMask = maskToIPv4(ipnet.Mask)
+	b.Match.IpDaMask = maskToIP(ipnet.Mask)
 	return b
 }
 
@@ -260,18 +270,37 @@ func maskToIPv4(mask net.IPMask) *net.IP {
 	return &ip
 }
 
-// MatchSrcIP adds match condition for matching source IP address.
+// maskToIP returns the IP form of the provided IPv4 or IPv6 mask.
+func maskToIP(mask net.IPMask) *net.IP {
+	if len(mask) == net.IPv6len {
+		ip := net.IP(mask)
+		return &ip
+	}
+	return maskToIPv4(mask)
+}
+
+// MatchSrcIP adds match condition for matching source IP address. Both IPv4
+// and IPv6 addresses are supported.
 func (b *ofFlowBuilder) MatchSrcIP(ip net.IP) FlowBuilder {
-	b.matchers = append(b.matchers, fmt.Sprintf("nw_src=%s", ip.String()))
+	if ip.To4() == nil {
+		b.matchers = append(b.matchers, fmt.Sprintf("ipv6_src=%s", ip.String()))
+	} else {
+		b.matchers = append(b.matchers, fmt.Sprintf("nw_src=%s", ip.String()))
+	}
 	b.Match.IpSa = &ip
 	return b
 }
 
-// MatchSrcIPNet adds match condition for matching source IP CIDR.
+// MatchSrcIPNet adds match condition for matching source IP CIDR. Both IPv4
+// and IPv6 CIDRs are supported.
 func (b *ofFlowBuilder) MatchSrcIPNet(ipnet net.IPNet) FlowBuilder {
-	b.matchers = append(b.matchers, fmt.Sprintf("nw_src=%s", ipnet.String()))
+	if ipnet.IP.To4() == nil {
+		b.matchers = append(b.matchers, fmt.Sprintf("ipv6_src=%s", ipnet.String()))
+	} else {
+		b.matchers = append(b.matchers, fmt.Sprintf("nw_src=%s", ipnet.String()))
+	}
 	b.Match.IpSa = &ipnet.IP
-	b.Match.IpSaMask = maskToIPv4(ipnet.Mask)
+	b.Match.IpSaMask = maskToIP(ipnet.Mask)
 	return b
 }
 
@@ -333,6 +362,62 @@ func (b *ofFlowBuilder) MatchIPDscp(dscp uint8) FlowBuilder {
 	return b
 }
 
+// MatchICMPv6Type adds match condition for matching the type of ICMPv6 packets.
+func (b *ofFlowBuilder) MatchICMPv6Type(icmp6Type uint8) FlowBuilder {
+	b.MatchProtocol(ProtocolICMPv6)
+	b.matchers = append(b.matchers, fmt.Sprintf("icmp_type=%d", icmp6Type))
+	b.Match.Icmp6Type = &icmp6Type
+	return b
+}
+
+// MatchICMPv6Code adds match condition for matching the code of ICMPv6 packets.
+func (b *ofFlowBuilder) MatchICMPv6Code(icmp6Code uint8) FlowBuilder {
+	b.MatchProtocol(ProtocolICMPv6)
+	b.matchers = append(b.matchers, fmt.Sprintf("icmp_code=%d", icmp6Code))
+	b.Match.Icmp6Code = &icmp6Code
+	return b
+}
+
+// MatchICMPType adds match condition for matching the type of ICMP packets.
+// ofctrl.FlowMatch doesn't support the ICMP type, so the match field is added
+// to the FlowMod message when the Flow is sent.
+func (b *ofFlowBuilder) MatchICMPType(icmpType uint8) FlowBuilder {
+	b.MatchProtocol(ProtocolICMP)
+	b.matchers = append(b.matchers, fmt.Sprintf("icmp_type=%d", icmpType))
+	field := openflow13.MatchField{
+		Class:  openflow13.OXM_CLASS_OPENFLOW_BASIC,
+		Field:  openflow13.OXM_FIELD_ICMPV4_TYPE,
+		Length: 1,
+		Value:  &openflow13.IcmpTypeField{Type: icmpType},
+	}
+	b.extraMatchFields = append(b.extraMatchFields, field)
+	return b
+}
+
+// MatchICMPCode adds match condition for matching the code of ICMP packets.
+// ofctrl.FlowMatch doesn't support the ICMP code, so the match field is added
+// to the FlowMod message when the Flow is sent.
+func (b *ofFlowBuilder) MatchICMPCode(icmpCode uint8) FlowBuilder {
+	b.MatchProtocol(ProtocolICMP)
+	b.matchers = append(b.matchers, fmt.Sprintf("icmp_code=%d", icmpCode))
+	field := openflow13.MatchField{
+		Class:  openflow13.OXM_CLASS_OPENFLOW_BASIC,
+		Field:  openflow13.OXM_FIELD_ICMPV4_CODE,
+		Length: 1,
+		Value:  &openflow13.IcmpCodeField{Code: icmpCode},
+	}
+	b.extraMatchFields = append(b.extraMatchFields, field)
+	return b
+}
+
+// MatchNDTarget adds match condition for matching the target address of IPv6
+// Neighbor Discovery packets.
+func (b *ofFlowBuilder) MatchNDTarget(target net.IP) FlowBuilder {
+	b.matchers = append(b.matchers, fmt.Sprintf("nd_target=%s", target.String()))
+	b.Match.NdTarget = &target
+	return b
+}
+
 // MatchConjID adds match condition for matching conj_id.
 func (b *ofFlowBuilder) MatchConjID(value uint32) FlowBuilder {
 	b.matchers = append(b.matchers, fmt.Sprintf("conj_id=%d", value))
@@ -364,14 +449,37 @@ func (b *ofFlowBuilder) MatchProtocol(protocol Protocol) FlowBuilder {
 	case ProtocolICMP:
 		b.Match.Ethertype = 0x0800
 		b.Match.IpProto = 1
+	case ProtocolIPv6:
+		b.Match.Ethertype = 0x86dd
+	case ProtocolTCPv6:
+		b.Match.Ethertype = 0x86dd
+		b.Match.IpProto = 6
+	case ProtocolUDPv6:
+		b.Match.Ethertype = 0x86dd
+		b.Match.IpProto = 17
+	case ProtocolSCTPv6:
+		b.Match.Ethertype = 0x86dd
+		b.Match.IpProto = 132
+	case ProtocolICMPv6:
+		b.Match.Ethertype = 0x86dd
+		b.Match.IpProto = 58
 	}
 	b.protocol = protocol
 	return b
 }
 
+// isIPv6 returns whether the flow has been set to match IPv6 packets.
+func (b *ofFlowBuilder) isIPv6() bool {
+	return b.Match.Ethertype == 0x86dd
+}
+
 // MatchTCPDstPort adds match condition for matching TCP destination port.
 func (b *ofFlowBuilder) MatchTCPDstPort(port uint16) FlowBuilder {
-	b.MatchProtocol(ProtocolTCP)
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolTCPv6)
+	} else {
+		b.MatchProtocol(ProtocolTCP)
+	}
 	b.Match.TcpDstPort = port
 	// According to ovs-ofctl(8) man page, "tp_dst" is deprecated and "tcp_dst",
 	// "udp_dst", "sctp_dst" should be used for the destination port of TCP, UDP,
@@ -383,22 +491,101 @@ func (b *ofFlowBuilder) MatchTCPDstPort(port uint16) FlowBuilder {
 	return b
 }
 
+// MatchTCPSrcPort adds match condition for matching TCP source port.
+func (b *ofFlowBuilder) MatchTCPSrcPort(port uint16) FlowBuilder {
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolTCPv6)
+	} else {
+		b.MatchProtocol(ProtocolTCP)
+	}
+	b.Match.TcpSrcPort = port
+	b.matchers = append(b.matchers, fmt.Sprintf("tp_src=%d", port))
+	return b
+}
+
 // MatchUDPDstPort adds match condition for matching UDP destination port.
 func (b *ofFlowBuilder) MatchUDPDstPort(port uint16) FlowBuilder {
-	b.MatchProtocol(ProtocolUDP)
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolUDPv6)
+	} else {
+		b.MatchProtocol(ProtocolUDP)
+	}
 	b.Match.UdpDstPort = port
 	b.matchers = append(b.matchers, fmt.Sprintf("tp_dst=%d", port))
 	return b
 }
 
+// MatchUDPSrcPort adds match condition for matching UDP source port.
+func (b *ofFlowBuilder) MatchUDPSrcPort(port uint16) FlowBuilder {
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolUDPv6)
+	} else {
+		b.MatchProtocol(ProtocolUDP)
+	}
+	b.Match.UdpSrcPort = port
+	b.matchers = append(b.matchers, fmt.Sprintf("tp_src=%d", port))
+	return b
+}
+
 // MatchSCTPDstPort adds match condition for matching SCTP destination port.
 func (b *ofFlowBuilder) MatchSCTPDstPort(port uint16) FlowBuilder {
-	b.MatchProtocol(ProtocolSCTP)
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolSCTPv6)
+	} else {
+		b.MatchProtocol(ProtocolSCTP)
+	}
 	b.Match.SctpDstPort = port
 	b.matchers = append(b.matchers, fmt.Sprintf("tp_dst=%d", port))
 	return b
 }
 
+// MatchTCPDstPortWithMask adds match condition for matching TCP destination port with a bitwise mask, which can be used
+// to match a range of ports with a single flow.
+func (b *ofFlowBuilder) MatchTCPDstPortWithMask(port, mask uint16) FlowBuilder {
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolTCPv6)
+	} else {
+		b.MatchProtocol(ProtocolTCP)
+	}
+	b.matchDstPortWithMask(openflow13.NewTcpDstField, port, mask)
+	return b
+}
+
+// MatchUDPDstPortWithMask adds match condition for matching UDP destination port with a bitwise mask, which can be used
+// to match a range of ports with a single flow.
+func (b *ofFlowBuilder) MatchUDPDstPortWithMask(port, mask uint16) FlowBuilder {
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolUDPv6)
+	} else {
+		b.MatchProtocol(ProtocolUDP)
+	}
+	b.matchDstPortWithMask(openflow13.NewUdpDstField, port, mask)
+	return b
+}
+
+// MatchSCTPDstPortWithMask adds match condition for matching SCTP destination port with a bitwise mask, which can be
+// used to match a range of ports with a single flow.
+func (b *ofFlowBuilder) MatchSCTPDstPortWithMask(port, mask uint16) FlowBuilder {
+	if b.isIPv6() {
+		b.MatchProtocol(ProtocolSCTPv6)
+	} else {
+		b.MatchProtocol(ProtocolSCTP)
+	}
+	b.matchDstPortWithMask(openflow13.NewSctpDstField, port, mask)
+	return b
+}
+
+// matchDstPortWithMask adds the masked transport layer destination port field generated by newField. ofctrl.FlowMatch
+// doesn't support masked ports, so the match field is added to the FlowMod message when the Flow is sent.
+func (b *ofFlowBuilder) matchDstPortWithMask(newField func(port uint16) *openflow13.MatchField, port, mask uint16) {
+	field := newField(port)
+	field.HasMask = true
+	field.Mask = newField(mask).Value
+	field.Length += uint8(field.Mask.Len())
+	b.extraMatchFields = append(b.extraMatchFields, *field)
+	b.matchers = append(b.matchers, fmt.Sprintf("tp_dst=0x%x/0x%x", port, mask))
+}
+
 // MatchCTSrcIP matches the source IPv4 address of the connection tracker original direction tuple. This match requires
 // a match to valid connection tracking state as a prerequisite, and valid connection tracking state matches include
 // "+new", "+est", "+rel" and "+trk-inv".
@@ -456,14 +643,16 @@ func (b *ofFlowBuilder) MatchCTDstPort(port uint16) FlowBuilder {
 // "+new", "+est", "+rel" and "+trk-inv".
 func (b *ofFlowBuilder) MatchCTProtocol(proto Protocol) FlowBuilder {
 	switch proto {
-	case ProtocolTCP:
+	case ProtocolTCP, ProtocolTCPv6:
 		b.Match.CtIpProto = 6
-	case ProtocolUDP:
+	case ProtocolUDP, ProtocolUDPv6:
 		b.Match.CtIpProto = 17
-	case ProtocolSCTP:
+	case ProtocolSCTP, ProtocolSCTPv6:
 		b.Match.CtIpProto = 132
 	case ProtocolICMP:
 		b.Match.CtIpProto = 1
+	case ProtocolICMPv6:
+		b.Match.CtIpProto = 58
 	}
 	b.matchers = append(b.matchers, fmt.Sprintf("ct_nw_proto=%d", b.Match.CtIpProto))
 	return b
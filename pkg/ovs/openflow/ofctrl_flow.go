@@ -31,6 +31,33 @@ type ofFlow struct {
 	// ctStates is a temporary variable to maintain openflow13.CTStates. When FlowBuilder.Done is called, it is used to
 	// set the CtStates field in ofctrl.Flow.Match.
 	ctStates *openflow13.CTStates
+	// extraMatchFields are the match fields which are not supported by ofctrl.FlowMatch, e.g. the masked transport
+	// layer ports and the type and code of ICMP packets. They are appended to the match of the FlowMod messages
+	// generated by ofctrl.Flow.
+	extraMatchFields []openflow13.MatchField
+	// meterID, appliedActions and gotoTable are used to generate the instructions of the FlowMod messages. They are
+	// maintained in ofFlow instead of ofctrl.Flow, because ofctrl.Flow doesn't expose the FlowMod messages it generates
+	// with them, and ofFlow needs to add the extra match fields to the messages.
+	meterID        *MeterIDType
+	appliedActions []ofctrl.OFAction
+	gotoTable      *uint8
+}
+
+// ApplyAction adds an action to the apply-actions instruction of the Flow.
+func (f *ofFlow) ApplyAction(action ofctrl.OFAction) {
+	f.appliedActions = append(f.appliedActions, action)
+}
+
+// Goto sets the goto-table instruction of the Flow.
+func (f *ofFlow) Goto(tableID uint8) {
+	f.gotoTable = &tableID
+}
+
+// Drop removes all the instructions of the Flow, so that the matched packets are dropped.
+func (f *ofFlow) Drop() {
+	f.meterID = nil
+	f.appliedActions = nil
+	f.gotoTable = nil
 }
 
 // Reset updates the ofFlow.Flow.Table field with ofFlow.table.Table.
@@ -42,7 +69,7 @@ func (f *ofFlow) Reset() {
 }
 
 func (f *ofFlow) Add() error {
-	err := f.Flow.Send(openflow13.FC_ADD)
+	err := f.send(openflow13.FC_ADD)
 	if err != nil {
 		return err
 	}
@@ -51,7 +78,7 @@ func (f *ofFlow) Add() error {
 }
 
 func (f *ofFlow) Modify() error {
-	err := f.Flow.Send(openflow13.FC_MODIFY_STRICT)
+	err := f.send(openflow13.FC_MODIFY_STRICT)
 	if err != nil {
 		return err
 	}
@@ -61,7 +88,7 @@ func (f *ofFlow) Modify() error {
 
 func (f *ofFlow) Delete() error {
 	f.Flow.UpdateInstallStatus(true)
-	err := f.Flow.Send(openflow13.FC_DELETE_STRICT)
+	err := f.send(openflow13.FC_DELETE_STRICT)
 	if err != nil {
 		return err
 	}
@@ -69,6 +96,47 @@ func (f *ofFlow) Delete() error {
 	return nil
 }
 
+// send sends the FlowMod message of the Flow generated for the provided command to the OFSwitch.
+func (f *ofFlow) send(command int) error {
+	flowMod, err := f.getFlowMod(command)
+	if err != nil {
+		return err
+	}
+	return f.Flow.Table.Switch.Send(flowMod)
+}
+
+// getFlowMod returns the FlowMod message of the Flow generated for the provided command. ofctrl.Flow generates the
+// message with the header and the match, as its NextElem is an empty element without actions. The extra match fields
+// and the instructions are added to the message afterwards.
+func (f *ofFlow) getFlowMod(command int) (*openflow13.FlowMod, error) {
+	flowMod, err := f.Flow.GenerateFlowModMessage(command)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range f.extraMatchFields {
+		flowMod.Match.AddField(field)
+	}
+	if command == openflow13.FC_DELETE || command == openflow13.FC_DELETE_STRICT {
+		return flowMod, nil
+	}
+	if f.meterID != nil {
+		flowMod.AddInstruction(newMeterInstruction(*f.meterID))
+	}
+	if len(f.appliedActions) > 0 {
+		instruction := openflow13.NewInstrApplyActions()
+		for _, action := range f.appliedActions {
+			if err := instruction.AddAction(action.GetActionMessage(), false); err != nil {
+				return nil, err
+			}
+		}
+		flowMod.AddInstruction(instruction)
+	}
+	if f.gotoTable != nil {
+		flowMod.AddInstruction(openflow13.NewInstrGotoTable(*f.gotoTable))
+	}
+	return flowMod, nil
+}
+
 func (f *ofFlow) Type() EntryType {
 	return FlowEntry
 }
@@ -93,21 +161,16 @@ func (f *ofFlow) FlowPriority() uint16 {
 	return f.Match.Priority
 }
 
-func (f *ofFlow) GetBundleMessage(entryOper OFOperation) (ofctrl.OpenFlowModMessage, error) {
-	var operation int
+// getFlowModCommand returns the FlowMod command used for the provided OFOperation.
+func getFlowModCommand(entryOper OFOperation) int {
 	switch entryOper {
-	case AddMessage:
-		operation = openflow13.FC_ADD
 	case ModifyMessage:
-		operation = openflow13.FC_MODIFY_STRICT
+		return openflow13.FC_MODIFY_STRICT
 	case DeleteMessage:
-		operation = openflow13.FC_DELETE_STRICT
+		return openflow13.FC_DELETE_STRICT
+	default:
+		return openflow13.FC_ADD
 	}
-	message, err := f.Flow.GetBundleMessage(operation)
-	if err != nil {
-		return nil, err
-	}
-	return message, nil
 }
 
 // CopyToBuilder returns a new FlowBuilder that copies the table, protocols,
@@ -121,9 +184,7 @@ func (f *ofFlow) CopyToBuilder(priority uint16, copyActions bool) FlowBuilder {
 		CookieID:   f.Flow.CookieID,
 		CookieMask: f.Flow.CookieMask,
 		Match:      f.Flow.Match,
-	}
-	if copyActions {
-		f.Flow.CopyActionsToNewFlow(flow)
+		NextElem:   ofctrl.NewEmptyElem(),
 	}
 	if priority > 0 {
 		flow.Match.Priority = priority
@@ -133,6 +194,13 @@ func (f *ofFlow) CopyToBuilder(priority uint16, copyActions bool) FlowBuilder {
 		Flow:     flow,
 		matchers: f.matchers,
 		protocol: f.protocol,
+		// The extra match fields are copied as they are not included in ofctrl.Flow.Match.
+		extraMatchFields: f.extraMatchFields,
+	}
+	if copyActions {
+		newFlow.appliedActions = append([]ofctrl.OFAction(nil), f.appliedActions...)
+		newFlow.gotoTable = f.gotoTable
+		newFlow.meterID = f.meterID
 	}
 	return &ofFlowBuilder{newFlow}
 }
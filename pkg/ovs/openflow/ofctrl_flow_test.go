@@ -3,7 +3,10 @@ package openflow
 import (
 	"testing"
 
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/ofnet/ofctrl"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCopyToBuilder(t *testing.T) {
@@ -29,3 +32,80 @@ func TestCopyToBuilder(t *testing.T) {
 	newFlow2 := oriFlow.CopyToBuilder(newPriority, false)
 	assert.Equal(t, newPriority, newFlow2.Done().(*ofFlow).Match.Priority)
 }
+
+func TestExtraMatchFields(t *testing.T) {
+	table := &ofTable{
+		id:    0,
+		next:  1,
+		Table: &ofctrl.Table{TableId: 0},
+	}
+	flow := table.BuildFlow(uint16(100)).
+		MatchTCPDstPortWithMask(0x400, 0xfc00).
+		Action().GotoTable(1).
+		Done()
+	assert.Equal(t, "table=0,tcp,tp_dst=0x400/0xfc00", flow.MatchString())
+	flowMod, err := flow.(*ofFlow).getFlowMod(openflow13.FC_ADD)
+	require.NoError(t, err)
+	field := flowMod.Match.Fields[len(flowMod.Match.Fields)-1]
+	assert.Equal(t, uint8(openflow13.OXM_FIELD_TCP_DST), field.Field)
+	assert.True(t, field.HasMask)
+	assert.Equal(t, uint8(4), field.Length)
+	require.Len(t, flowMod.Instructions, 1)
+	assert.Equal(t, openflow13.NewInstrGotoTable(1), flowMod.Instructions[0])
+	_, err = flowMod.MarshalBinary()
+	require.NoError(t, err)
+
+	// The extra match fields must be kept by CopyToBuilder and not be appended twice.
+	newFlow := flow.CopyToBuilder(0, true).Done()
+	newFlowMod, err := newFlow.(*ofFlow).getFlowMod(openflow13.FC_ADD)
+	require.NoError(t, err)
+	assert.Equal(t, flowMod.Match, newFlowMod.Match)
+	assert.Equal(t, flowMod.Instructions, newFlowMod.Instructions)
+
+	icmpFlow := table.BuildFlow(uint16(100)).
+		MatchICMPType(8).MatchICMPCode(0).
+		Action().GotoTable(1).
+		Done()
+	assert.Equal(t, "table=0,icmp,icmp_type=8,icmp_code=0", icmpFlow.MatchString())
+	icmpFlowMod, err := icmpFlow.(*ofFlow).getFlowMod(openflow13.FC_ADD)
+	require.NoError(t, err)
+	fields := icmpFlowMod.Match.Fields
+	require.True(t, len(fields) >= 2)
+	assert.Equal(t, &openflow13.IcmpTypeField{Type: 8}, fields[len(fields)-2].Value)
+	assert.Equal(t, &openflow13.IcmpCodeField{Code: 0}, fields[len(fields)-1].Value)
+}
+
+func TestMeter(t *testing.T) {
+	table := &ofTable{
+		id:    0,
+		next:  1,
+		Table: &ofctrl.Table{TableId: 0},
+	}
+	flow := table.BuildFlow(uint16(100)).
+		MatchProtocol(ProtocolIP).
+		Action().Meter(1).
+		Action().GotoTable(1).
+		Done()
+	flowMod, err := flow.(*ofFlow).getFlowMod(openflow13.FC_ADD)
+	require.NoError(t, err)
+	require.Len(t, flowMod.Instructions, 2)
+	assert.Equal(t, newMeterInstruction(1), flowMod.Instructions[0])
+	data, err := flowMod.Instructions[0].MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 6, 0, 8, 0, 0, 0, 1}, data)
+	_, err = flowMod.MarshalBinary()
+	require.NoError(t, err)
+
+	meterMod := newMeterMod(meterModCommandAdd, 1, 100, 200)
+	meterMod.Xid = 0
+	data, err = meterMod.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		// OpenFlow header with type OFPT_METER_MOD and length 32.
+		4, 29, 0, 32, 0, 0, 0, 0,
+		// Command OFPMC_ADD, flags OFPMF_PKTPS|OFPMF_BURST|OFPMF_STATS and meter ID 1.
+		0, 0, 0, 0xe, 0, 0, 0, 1,
+		// Drop band with rate 100 and burst size 200.
+		0, 1, 0, 16, 0, 0, 0, 100, 0, 0, 0, 200, 0, 0, 0, 0,
+	}, data)
+}
@@ -15,6 +15,8 @@
 package openflow
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/contiv/libOpenflow/openflow13"
@@ -94,6 +96,15 @@ func (b *bucketBuilder) LoadRegRange(regID int, data uint32, rng Range) BucketBu
 	return b
 }
 
+// Multipath is an action to hash the fields and store the link chosen by the algorithm to the target register at
+// specified range.
+func (b *bucketBuilder) Multipath(fields MultipathFields, algorithm MultipathAlgorithm, nLinks uint16, regID int, rng Range) BucketBuilder {
+	reg := fmt.Sprintf("%s%d", NxmFieldReg, regID)
+	regField, _ := openflow13.FindFieldHeaderByName(reg, false)
+	b.bucket.AddAction(newNXActionMultipath(fields, algorithm, nLinks, rng.ToNXRange().ToOfsBits(), regField))
+	return b
+}
+
 // ResubmitToTable is an action to resubmit packet to the specified table when the bucket is selected.
 func (b *bucketBuilder) ResubmitToTable(tableID TableIDType) BucketBuilder {
 	b.bucket.AddAction(openflow13.NewNXActionResubmitTableAction(openflow13.OFPP_IN_PORT, uint8(tableID)))
@@ -110,3 +121,83 @@ func (b *bucketBuilder) Done() Group {
 	b.group.ofctrl.AddBuckets(b.bucket)
 	return b.group
 }
+
+// nxActionMultipath is the NX action "multipath", which is not provided by
+// libOpenflow. Its layout is the struct nx_action_multipath of Open vSwitch.
+type nxActionMultipath struct {
+	*openflow13.NXActionHeader
+	Fields    uint16
+	Basis     uint16
+	Algorithm uint16
+	MaxLink   uint16
+	Arg       uint32
+	OfsNbits  uint16
+	Dst       *openflow13.MatchField
+}
+
+const nxActionMultipathLength = 32
+
+func newNXActionMultipath(fields MultipathFields, algorithm MultipathAlgorithm, nLinks uint16, ofsNbits uint16, dst *openflow13.MatchField) *nxActionMultipath {
+	a := &nxActionMultipath{
+		NXActionHeader: openflow13.NewNxActionHeader(openflow13.NXAST_MULTIPATH),
+		Fields:         uint16(fields),
+		Algorithm:      uint16(algorithm),
+		MaxLink:        nLinks - 1,
+		OfsNbits:       ofsNbits,
+		Dst:            dst,
+	}
+	a.Length = nxActionMultipathLength
+	return a
+}
+
+func (a *nxActionMultipath) Len() uint16 {
+	return a.Length
+}
+
+func (a *nxActionMultipath) MarshalBinary() ([]byte, error) {
+	data := make([]byte, int(a.Len()))
+	b, err := a.NXActionHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	n := copy(data, b)
+	binary.BigEndian.PutUint16(data[n:], a.Fields)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.Basis)
+	n += 4 // Basis and padding.
+	binary.BigEndian.PutUint16(data[n:], a.Algorithm)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.MaxLink)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.Arg)
+	n += 6 // Arg and padding.
+	binary.BigEndian.PutUint16(data[n:], a.OfsNbits)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.Dst.MarshalHeader())
+	return data, nil
+}
+
+func (a *nxActionMultipath) UnmarshalBinary(data []byte) error {
+	if len(data) < nxActionMultipathLength {
+		return errors.New("the []byte is too short to unmarshal a full nxActionMultipath message")
+	}
+	a.NXActionHeader = new(openflow13.NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	n := int(openflow13.NxActionHeaderLength)
+	a.Fields = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Basis = binary.BigEndian.Uint16(data[n:])
+	n += 4
+	a.Algorithm = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.MaxLink = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Arg = binary.BigEndian.Uint32(data[n:])
+	n += 6
+	a.OfsNbits = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Dst = new(openflow13.MatchField)
+	return a.Dst.UnmarshalHeader(data[n : n+4])
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"testing"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNXActionMultipath(t *testing.T) {
+	regField, err := openflow13.FindFieldHeaderByName("NXM_NX_REG4", false)
+	require.NoError(t, err)
+	rng := Range{0, 15}
+	action := newNXActionMultipath(MultipathFieldsNwSrc, MultipathAlgorithmHRW, 3, rng.ToNXRange().ToOfsBits(), regField)
+	data, err := action.MarshalBinary()
+	require.NoError(t, err)
+	// multipath(nw_src,0,hrw,3,0,reg4[0..15])
+	assert.Equal(t, []byte{
+		0xff, 0xff, 0x00, 0x20, 0x00, 0x00, 0x23, 0x20, 0x00, 0x0a, // header
+		0x00, 0x04, 0x00, 0x00, 0x00, 0x00, // fields, basis, pad
+		0x00, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, // algorithm, max_link, arg
+		0x00, 0x00, 0x00, 0x0f, 0x00, 0x01, 0x08, 0x04, // pad, ofs_nbits, dst
+	}, data)
+
+	newAction := new(nxActionMultipath)
+	require.NoError(t, newAction.UnmarshalBinary(data))
+	assert.Equal(t, action.Fields, newAction.Fields)
+	assert.Equal(t, action.Algorithm, newAction.Algorithm)
+	assert.Equal(t, action.MaxLink, newAction.MaxLink)
+	assert.Equal(t, action.OfsNbits, newAction.OfsNbits)
+	assert.Equal(t, action.Dst.MarshalHeader(), newAction.Dst.MarshalHeader())
+}
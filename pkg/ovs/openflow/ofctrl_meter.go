@@ -0,0 +1,138 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/contiv/libOpenflow/common"
+	"github.com/contiv/libOpenflow/openflow13"
+)
+
+const (
+	meterModCommandAdd = 0
+
+	meterFlagPktps = 0x2
+	meterFlagBurst = 0x4
+	meterFlagStats = 0x8
+
+	meterBandTypeDrop = 1
+
+	meterModHeaderLen = 16
+	meterBandDropLen  = 16
+	meterInstrLen     = 8
+)
+
+// meterMod is the OpenFlow MeterMod message, which is not provided by libOpenflow. Only the meters with drop bands in
+// packets per second are supported.
+type meterMod struct {
+	common.Header
+	command uint16
+	flags   uint16
+	meterID uint32
+	bands   []meterBandDrop
+}
+
+// meterBandDrop is a meter band which drops the packets exceeding the rate.
+type meterBandDrop struct {
+	rate  uint32
+	burst uint32
+}
+
+func newMeterMod(command uint16, id MeterIDType, rate, burst uint32) *meterMod {
+	m := &meterMod{
+		Header:  openflow13.NewOfp13Header(),
+		command: command,
+		flags:   meterFlagPktps | meterFlagStats,
+		meterID: uint32(id),
+		bands:   []meterBandDrop{{rate: rate, burst: burst}},
+	}
+	if burst > 0 {
+		m.flags |= meterFlagBurst
+	}
+	m.Header.Type = openflow13.Type_MeterMod
+	m.Header.Length = m.Len()
+	return m
+}
+
+func (m *meterMod) Len() uint16 {
+	return meterModHeaderLen + uint16(len(m.bands))*meterBandDropLen
+}
+
+func (m *meterMod) MarshalBinary() ([]byte, error) {
+	m.Header.Length = m.Len()
+	data := make([]byte, m.Len())
+	header, err := m.Header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	n := copy(data, header)
+	binary.BigEndian.PutUint16(data[n:], m.command)
+	binary.BigEndian.PutUint16(data[n+2:], m.flags)
+	binary.BigEndian.PutUint32(data[n+4:], m.meterID)
+	n += 8
+	for _, band := range m.bands {
+		binary.BigEndian.PutUint16(data[n:], meterBandTypeDrop)
+		binary.BigEndian.PutUint16(data[n+2:], meterBandDropLen)
+		binary.BigEndian.PutUint32(data[n+4:], band.rate)
+		binary.BigEndian.PutUint32(data[n+8:], band.burst)
+		n += meterBandDropLen
+	}
+	return data, nil
+}
+
+func (m *meterMod) UnmarshalBinary(data []byte) error {
+	return errors.New("unmarshalling MeterMod message is not supported")
+}
+
+// meterInstruction is the OpenFlow instruction to apply a meter. openflow13.InstrMeter is not used as it doesn't
+// marshal the meter ID.
+type meterInstruction struct {
+	openflow13.InstrMeter
+}
+
+func newMeterInstruction(id MeterIDType) *meterInstruction {
+	instr := new(meterInstruction)
+	instr.Type = openflow13.InstrType_METER
+	instr.Length = meterInstrLen
+	instr.MeterId = uint32(id)
+	return instr
+}
+
+func (instr *meterInstruction) Len() uint16 {
+	return meterInstrLen
+}
+
+func (instr *meterInstruction) MarshalBinary() ([]byte, error) {
+	data, err := instr.InstrHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	meterID := make([]byte, 4)
+	binary.BigEndian.PutUint32(meterID, instr.MeterId)
+	return append(data, meterID...), nil
+}
+
+func (instr *meterInstruction) UnmarshalBinary(data []byte) error {
+	if len(data) < meterInstrLen {
+		return errors.New("wrong size to unmarshal a meter instruction")
+	}
+	if err := instr.InstrHeader.UnmarshalBinary(data[:4]); err != nil {
+		return err
+	}
+	instr.MeterId = binary.BigEndian.Uint32(data[4:meterInstrLen])
+	return nil
+}
@@ -64,6 +64,20 @@ func (mr *MockBridgeMockRecorder) AddFlowsInBundle(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFlowsInBundle", reflect.TypeOf((*MockBridge)(nil).AddFlowsInBundle), arg0, arg1, arg2)
 }
 
+// AddMeter mocks base method
+func (m *MockBridge) AddMeter(arg0 openflow.MeterIDType, arg1, arg2 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMeter", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMeter indicates an expected call of AddMeter
+func (mr *MockBridgeMockRecorder) AddMeter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMeter", reflect.TypeOf((*MockBridge)(nil).AddMeter), arg0, arg1, arg2)
+}
+
 // AddOFEntriesInBundle mocks base method
 func (m *MockBridge) AddOFEntriesInBundle(arg0, arg1, arg2 []openflow.OFEntry) error {
 	m.ctrl.T.Helper()
@@ -247,6 +261,20 @@ func (mr *MockBridgeMockRecorder) IsConnected() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConnected", reflect.TypeOf((*MockBridge)(nil).IsConnected))
 }
 
+// ResubmitPacketIn mocks base method
+func (m *MockBridge) ResubmitPacketIn(arg0 *ofctrl.PacketIn, arg1 openflow.TableIDType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResubmitPacketIn", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResubmitPacketIn indicates an expected call of ResubmitPacketIn
+func (mr *MockBridgeMockRecorder) ResubmitPacketIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResubmitPacketIn", reflect.TypeOf((*MockBridge)(nil).ResubmitPacketIn), arg0, arg1)
+}
+
 // SendPacketOut mocks base method
 func (m *MockBridge) SendPacketOut(arg0 *ofctrl.PacketOut) error {
 	m.ctrl.T.Helper()
@@ -447,21 +475,6 @@ func (mr *MockFlowMockRecorder) FlowPriority() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlowPriority", reflect.TypeOf((*MockFlow)(nil).FlowPriority))
 }
 
-// GetBundleMessage mocks base method
-func (m *MockFlow) GetBundleMessage(arg0 openflow.OFOperation) (ofctrl.OpenFlowModMessage, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBundleMessage", arg0)
-	ret0, _ := ret[0].(ofctrl.OpenFlowModMessage)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetBundleMessage indicates an expected call of GetBundleMessage
-func (mr *MockFlowMockRecorder) GetBundleMessage(arg0 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleMessage", reflect.TypeOf((*MockFlow)(nil).GetBundleMessage), arg0)
-}
-
 // KeyString mocks base method
 func (m *MockFlow) KeyString() string {
 	m.ctrl.T.Helper()
@@ -693,6 +706,20 @@ func (mr *MockActionMockRecorder) LoadRegRange(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadRegRange", reflect.TypeOf((*MockAction)(nil).LoadRegRange), arg0, arg1, arg2)
 }
 
+// Meter mocks base method
+func (m *MockAction) Meter(arg0 openflow.MeterIDType) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Meter", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// Meter indicates an expected call of Meter
+func (mr *MockActionMockRecorder) Meter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Meter", reflect.TypeOf((*MockAction)(nil).Meter), arg0)
+}
+
 // Move mocks base method
 func (m *MockAction) Move(arg0, arg1 string) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -847,6 +874,20 @@ func (mr *MockActionMockRecorder) SendToController(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToController", reflect.TypeOf((*MockAction)(nil).SendToController), arg0)
 }
 
+// SendToControllerWithFullPacket mocks base method
+func (m *MockAction) SendToControllerWithFullPacket(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendToControllerWithFullPacket", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// SendToControllerWithFullPacket indicates an expected call of SendToControllerWithFullPacket
+func (mr *MockActionMockRecorder) SendToControllerWithFullPacket(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToControllerWithFullPacket", reflect.TypeOf((*MockAction)(nil).SendToControllerWithFullPacket), arg0)
+}
+
 // SetARPSha mocks base method
 func (m *MockAction) SetARPSha(arg0 net.HardwareAddr) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1495,6 +1536,62 @@ func (mr *MockFlowBuilderMockRecorder) MatchDstMAC(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchDstMAC", reflect.TypeOf((*MockFlowBuilder)(nil).MatchDstMAC), arg0)
 }
 
+// MatchICMPCode mocks base method
+func (m *MockFlowBuilder) MatchICMPCode(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchICMPCode", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchICMPCode indicates an expected call of MatchICMPCode
+func (mr *MockFlowBuilderMockRecorder) MatchICMPCode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchICMPCode", reflect.TypeOf((*MockFlowBuilder)(nil).MatchICMPCode), arg0)
+}
+
+// MatchICMPType mocks base method
+func (m *MockFlowBuilder) MatchICMPType(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchICMPType", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchICMPType indicates an expected call of MatchICMPType
+func (mr *MockFlowBuilderMockRecorder) MatchICMPType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchICMPType", reflect.TypeOf((*MockFlowBuilder)(nil).MatchICMPType), arg0)
+}
+
+// MatchICMPv6Code mocks base method
+func (m *MockFlowBuilder) MatchICMPv6Code(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchICMPv6Code", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchICMPv6Code indicates an expected call of MatchICMPv6Code
+func (mr *MockFlowBuilderMockRecorder) MatchICMPv6Code(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchICMPv6Code", reflect.TypeOf((*MockFlowBuilder)(nil).MatchICMPv6Code), arg0)
+}
+
+// MatchICMPv6Type mocks base method
+func (m *MockFlowBuilder) MatchICMPv6Type(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchICMPv6Type", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchICMPv6Type indicates an expected call of MatchICMPv6Type
+func (mr *MockFlowBuilderMockRecorder) MatchICMPv6Type(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchICMPv6Type", reflect.TypeOf((*MockFlowBuilder)(nil).MatchICMPv6Type), arg0)
+}
+
 // MatchIPDscp mocks base method
 func (m *MockFlowBuilder) MatchIPDscp(arg0 byte) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1523,6 +1620,20 @@ func (mr *MockFlowBuilderMockRecorder) MatchInPort(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchInPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchInPort), arg0)
 }
 
+// MatchNDTarget mocks base method
+func (m *MockFlowBuilder) MatchNDTarget(arg0 net.IP) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchNDTarget", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchNDTarget indicates an expected call of MatchNDTarget
+func (mr *MockFlowBuilderMockRecorder) MatchNDTarget(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchNDTarget", reflect.TypeOf((*MockFlowBuilder)(nil).MatchNDTarget), arg0)
+}
+
 // MatchPriority mocks base method
 func (m *MockFlowBuilder) MatchPriority(arg0 uint16) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1593,6 +1704,20 @@ func (mr *MockFlowBuilderMockRecorder) MatchSCTPDstPort(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchSCTPDstPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchSCTPDstPort), arg0)
 }
 
+// MatchSCTPDstPortWithMask mocks base method
+func (m *MockFlowBuilder) MatchSCTPDstPortWithMask(arg0, arg1 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchSCTPDstPortWithMask", arg0, arg1)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchSCTPDstPortWithMask indicates an expected call of MatchSCTPDstPortWithMask
+func (mr *MockFlowBuilderMockRecorder) MatchSCTPDstPortWithMask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchSCTPDstPortWithMask", reflect.TypeOf((*MockFlowBuilder)(nil).MatchSCTPDstPortWithMask), arg0, arg1)
+}
+
 // MatchSrcIP mocks base method
 func (m *MockFlowBuilder) MatchSrcIP(arg0 net.IP) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1649,6 +1774,34 @@ func (mr *MockFlowBuilderMockRecorder) MatchTCPDstPort(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTCPDstPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchTCPDstPort), arg0)
 }
 
+// MatchTCPDstPortWithMask mocks base method
+func (m *MockFlowBuilder) MatchTCPDstPortWithMask(arg0, arg1 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchTCPDstPortWithMask", arg0, arg1)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchTCPDstPortWithMask indicates an expected call of MatchTCPDstPortWithMask
+func (mr *MockFlowBuilderMockRecorder) MatchTCPDstPortWithMask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTCPDstPortWithMask", reflect.TypeOf((*MockFlowBuilder)(nil).MatchTCPDstPortWithMask), arg0, arg1)
+}
+
+// MatchTCPSrcPort mocks base method
+func (m *MockFlowBuilder) MatchTCPSrcPort(arg0 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchTCPSrcPort", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchTCPSrcPort indicates an expected call of MatchTCPSrcPort
+func (mr *MockFlowBuilderMockRecorder) MatchTCPSrcPort(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTCPSrcPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchTCPSrcPort), arg0)
+}
+
 // MatchTunMetadata mocks base method
 func (m *MockFlowBuilder) MatchTunMetadata(arg0 int, arg1 uint32) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1677,6 +1830,34 @@ func (mr *MockFlowBuilderMockRecorder) MatchUDPDstPort(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchUDPDstPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchUDPDstPort), arg0)
 }
 
+// MatchUDPDstPortWithMask mocks base method
+func (m *MockFlowBuilder) MatchUDPDstPortWithMask(arg0, arg1 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchUDPDstPortWithMask", arg0, arg1)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchUDPDstPortWithMask indicates an expected call of MatchUDPDstPortWithMask
+func (mr *MockFlowBuilderMockRecorder) MatchUDPDstPortWithMask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchUDPDstPortWithMask", reflect.TypeOf((*MockFlowBuilder)(nil).MatchUDPDstPortWithMask), arg0, arg1)
+}
+
+// MatchUDPSrcPort mocks base method
+func (m *MockFlowBuilder) MatchUDPSrcPort(arg0 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchUDPSrcPort", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchUDPSrcPort indicates an expected call of MatchUDPSrcPort
+func (mr *MockFlowBuilderMockRecorder) MatchUDPSrcPort(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchUDPSrcPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchUDPSrcPort), arg0)
+}
+
 // SetHardTimeout mocks base method
 func (m *MockFlowBuilder) SetHardTimeout(arg0 uint16) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
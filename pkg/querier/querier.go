@@ -36,6 +36,7 @@ type AgentNetworkPolicyInfoQuerier interface {
 	GetAppliedToGroups() []cpv1beta1.AppliedToGroup
 	GetNetworkPolicy(npName, npNamespace string) *cpv1beta1.NetworkPolicy
 	GetAppliedNetworkPolicies(pod, namespace string) []cpv1beta1.NetworkPolicy
+	GetNetworkPolicyByRuleFlowID(ruleFlowID uint32) *cpv1beta1.NetworkPolicyReference
 }
 
 type ControllerNetworkPolicyInfoQuerier interface {
@@ -16,6 +16,7 @@ package ip
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"sort"
@@ -146,3 +147,72 @@ func NetIPNetToIPNet(ipNet *net.IPNet) *v1beta1.IPNet {
 	prefix, _ := ipNet.Mask.Size()
 	return &v1beta1.IPNet{IP: v1beta1.IPAddress(ipNet.IP), PrefixLength: int32(prefix)}
 }
+
+// GetIPv4Addr returns the first IPv4 address in the provided IPs, or nil if there is none.
+func GetIPv4Addr(ips []net.IP) net.IP {
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip
+		}
+	}
+	return nil
+}
+
+// GetIPv6Addr returns the first IPv6 address in the provided IPs, or nil if there is none.
+func GetIPv6Addr(ips []net.IP) net.IP {
+	for _, ip := range ips {
+		if ip.To4() == nil && ip.To16() != nil {
+			return ip
+		}
+	}
+	return nil
+}
+
+// IsIPv6IPNet returns true if the provided IPNet is an IPv6 subnet.
+func IsIPv6IPNet(ipNet *net.IPNet) bool {
+	return ipNet.IP.To4() == nil
+}
+
+// ParseIPv4Range parses a range of IPv4 addresses represented by either a CIDR
+// or a pair of start and end IPs, and returns the first and the last IPs of the
+// range as integers.
+func ParseIPv4Range(cidr, start, end string) (uint32, uint32, error) {
+	if cidr != "" {
+		if start != "" || end != "" {
+			return 0, 0, fmt.Errorf("CIDR and start/end IPs can't be set together")
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid CIDR %s: %v", cidr, err)
+		}
+		if ipNet.IP.To4() == nil {
+			return 0, 0, fmt.Errorf("CIDR %s is not IPv4", cidr)
+		}
+		first := IPv4ToUint32(ipNet.IP)
+		return first, first | ^binary.BigEndian.Uint32(ipNet.Mask), nil
+	}
+	startIP, endIP := net.ParseIP(start), net.ParseIP(end)
+	if startIP == nil || startIP.To4() == nil {
+		return 0, 0, fmt.Errorf("invalid IPv4 start IP %q", start)
+	}
+	if endIP == nil || endIP.To4() == nil {
+		return 0, 0, fmt.Errorf("invalid IPv4 end IP %q", end)
+	}
+	first, last := IPv4ToUint32(startIP), IPv4ToUint32(endIP)
+	if first > last {
+		return 0, 0, fmt.Errorf("start IP %s is greater than end IP %s", start, end)
+	}
+	return first, last, nil
+}
+
+// IPv4ToUint32 converts an IPv4 address to an integer.
+func IPv4ToUint32(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+// Uint32ToIPv4 converts an integer to an IPv4 address.
+func Uint32ToIPv4(i uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, i)
+	return ip
+}
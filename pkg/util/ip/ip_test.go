@@ -108,3 +108,46 @@ func TestMergeCIDRs(t *testing.T) {
 	ipNetList4 = mergeCIDRs(ipNetList4)
 	assert.ElementsMatch(t, correctList4, ipNetList4)
 }
+
+func TestGetIPAddrByFamily(t *testing.T) {
+	ipv4 := net.ParseIP("10.10.0.2")
+	ipv6 := net.ParseIP("fd00:10:10::2")
+	assert.Equal(t, ipv4, GetIPv4Addr([]net.IP{ipv6, ipv4}))
+	assert.Equal(t, ipv6, GetIPv6Addr([]net.IP{ipv4, ipv6}))
+	assert.Nil(t, GetIPv4Addr([]net.IP{ipv6}))
+	assert.Nil(t, GetIPv6Addr([]net.IP{ipv4}))
+	assert.Nil(t, GetIPv6Addr(nil))
+}
+
+func TestParseIPv4Range(t *testing.T) {
+	tests := []struct {
+		name          string
+		cidr          string
+		start         string
+		end           string
+		expectedFirst string
+		expectedLast  string
+		expectedErr   bool
+	}{
+		{"cidr", "10.10.0.0/30", "", "", "10.10.0.0", "10.10.0.3", false},
+		{"unmasked cidr", "10.10.0.5/30", "", "", "10.10.0.4", "10.10.0.7", false},
+		{"start-end", "", "10.10.0.2", "10.10.0.20", "10.10.0.2", "10.10.0.20", false},
+		{"single ip", "", "10.10.0.2", "10.10.0.2", "10.10.0.2", "10.10.0.2", false},
+		{"cidr and start-end", "10.10.0.0/30", "10.10.0.2", "10.10.0.20", "", "", true},
+		{"reversed start-end", "", "10.10.0.20", "10.10.0.2", "", "", true},
+		{"ipv6 cidr", "fd00:10:10::/120", "", "", "", "", true},
+		{"missing end", "", "10.10.0.2", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, err := ParseIPv4Range(tt.cidr, tt.start, tt.end)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFirst, Uint32ToIPv4(first).String())
+			assert.Equal(t, tt.expectedLast, Uint32ToIPv4(last).String())
+		})
+	}
+}
@@ -95,7 +95,7 @@ func (data *TestData) testDeletePod(t *testing.T, podName string, nodeName strin
 		t.Fatalf("Expected 1 pod interface, got %d", len(podInterfaces))
 	}
 	ifName := podInterfaces[0].InterfaceName
-	podIP := podInterfaces[0].IP
+	podIP := podInterfaces[0].IPs[0]
 	t.Logf("Host interface name for Pod is '%s'", ifName)
 
 	doesInterfaceExist := func() bool {
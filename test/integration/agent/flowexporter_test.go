@@ -89,7 +89,7 @@ func prepareInterfaceConfigs(contID, podName, podNS, ifName string, ip *net.IP)
 	}
 	iface := &interfacestore.InterfaceConfig{
 		InterfaceName:            ifName,
-		IP:                       *ip,
+		IPs:                      []net.IP{*ip},
 		ContainerInterfaceConfig: podConfig,
 	}
 	return iface
@@ -126,7 +126,7 @@ func TestConnectionStoreAndFlowRecords(t *testing.T) {
 	connDumperMock := connectionstest.NewMockConnTrackDumper(ctrl)
 	ifStoreMock := interfacestoretest.NewMockInterfaceStore(ctrl)
 	// TODO: Enhance the integration test by testing service.
-	connStore := connections.NewConnectionStore(connDumperMock, ifStoreMock, nil, nil, testPollInterval)
+	connStore := connections.NewConnectionStore(connDumperMock, ifStoreMock, nil, nil, nil, testPollInterval)
 	// Expect calls for connStore.poll and other callees
 	connDumperMock.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return(testConns, 0, nil)
 	connDumperMock.EXPECT().GetMaxConnections().Return(0, nil)
@@ -149,11 +149,9 @@ func TestConnectionStoreAndFlowRecords(t *testing.T) {
 		if i == 0 {
 			expConn.SourcePodName = testIfConfigs[i].PodName
 			expConn.SourcePodNamespace = testIfConfigs[i].PodNamespace
-			expConn.DoExport = true
 		} else {
 			expConn.DestinationPodName = testIfConfigs[i].PodName
 			expConn.DestinationPodNamespace = testIfConfigs[i].PodNamespace
-			expConn.DoExport = false
 		}
 		actualConn, found := connStore.GetConnByKey(*testConnKeys[i])
 		assert.Equal(t, found, true, "testConn should be present in connection store")
@@ -15,6 +15,7 @@
 package agent
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
 	"strings"
@@ -29,6 +30,7 @@ import (
 	config1 "github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
 	ofClient "github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow/cookie"
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
@@ -36,6 +38,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
 	ofTestUtils "github.com/vmware-tanzu/antrea/test/integration/ovs"
+	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
 var (
@@ -90,7 +93,7 @@ func TestConnectivityFlows(t *testing.T) {
 	// Initialize ovs metrics (Prometheus) to test them
 	metrics.InitializeOVSMetrics()
 
-	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false)
+	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false, false, false, false)
 	err := ofTestUtils.PrepareOVSBridge(br)
 	require.Nil(t, err, fmt.Sprintf("Failed to prepare OVS bridge: %v", err))
 	defer func() {
@@ -117,7 +120,7 @@ func TestConnectivityFlows(t *testing.T) {
 }
 
 func TestReplayFlowsConnectivityFlows(t *testing.T) {
-	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false)
+	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false, false, false, false)
 	err := ofTestUtils.PrepareOVSBridge(br)
 	require.Nil(t, err, fmt.Sprintf("Failed to prepare OVS bridge: %v", err))
 
@@ -144,7 +147,7 @@ func TestReplayFlowsConnectivityFlows(t *testing.T) {
 }
 
 func TestReplayFlowsNetworkPolicyFlows(t *testing.T) {
-	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false)
+	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false, false, false, false)
 	err := ofTestUtils.PrepareOVSBridge(br)
 	require.Nil(t, err, fmt.Sprintf("Failed to prepare OVS bridge: %v", err))
 
@@ -195,6 +198,167 @@ func TestReplayFlowsNetworkPolicyFlows(t *testing.T) {
 	testReplayFlows(t)
 }
 
+type svcConfig struct {
+	ip       net.IP
+	port     uint16
+	protocol ofconfig.Protocol
+}
+
+func TestProxyServiceFlows(t *testing.T) {
+	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false, false, false, false)
+	err := ofTestUtils.PrepareOVSBridge(br)
+	require.Nil(t, err, fmt.Sprintf("Failed to prepare OVS bridge: %v", err))
+
+	_, err = c.Initialize(roundInfo, &config1.NodeConfig{}, config1.TrafficEncapModeEncap, config1.HostGatewayOFPort)
+	require.Nil(t, err, "Failed to initialize OFClient")
+
+	defer func() {
+		err = c.Disconnect()
+		assert.Nil(t, err, fmt.Sprintf("Error while disconnecting from OVS bridge: %v", err))
+		err = ofTestUtils.DeleteOVSBridge(br)
+		assert.Nil(t, err, fmt.Sprintf("Error while deleting OVS bridge: %v", err))
+	}()
+
+	endpoints := []k8sproxy.Endpoint{
+		&k8sproxy.BaseEndpointInfo{Endpoint: "10.180.0.1:8000", Ready: true},
+		&k8sproxy.BaseEndpointInfo{Endpoint: "10.180.1.1:8000", Ready: true},
+	}
+	stickyMaxAgeSeconds := uint16(30)
+	services := []svcConfig{
+		{ip: net.ParseIP("10.20.30.41"), port: uint16(8000), protocol: ofconfig.ProtocolTCP},
+		{ip: net.ParseIP("10.20.30.42"), port: uint16(8000), protocol: ofconfig.ProtocolUDP},
+		{ip: net.ParseIP("10.20.30.43"), port: uint16(8000), protocol: ofconfig.ProtocolSCTP},
+	}
+	for i, svc := range services {
+		groupID := ofconfig.GroupIDType(i + 1)
+		expTableFlows, expGroupBuckets := expectedProxyServiceGroupAndFlows(groupID, svc, endpoints, stickyMaxAgeSeconds)
+		installServiceFlows(t, groupID, svc, endpoints, stickyMaxAgeSeconds)
+		ofTestUtils.CheckGroupExists(t, ovsCtlClient, groupID, "select", expGroupBuckets, true)
+		for _, tableFlow := range expTableFlows {
+			ofTestUtils.CheckFlowExists(t, ovsCtlClient, tableFlow.tableID, true, tableFlow.flows)
+		}
+
+		uninstallServiceFlowsFunc(t, groupID, svc, endpoints)
+		ofTestUtils.CheckGroupExists(t, ovsCtlClient, groupID, "select", expGroupBuckets, false)
+		for _, tableFlow := range expTableFlows {
+			ofTestUtils.CheckFlowExists(t, ovsCtlClient, tableFlow.tableID, false, tableFlow.flows)
+		}
+	}
+}
+
+// TestDNSInterceptFlows checks that the DNS responses received by local Pods are
+// sent to the controller, and that the reinjected ones, which are resubmitted
+// to conntrackTable with the registers of the PacketIn, are delivered to the
+// Pods.
+func TestDNSInterceptFlows(t *testing.T) {
+	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false, false, false, false)
+	err := ofTestUtils.PrepareOVSBridge(br)
+	require.Nil(t, err, fmt.Sprintf("Failed to prepare OVS bridge: %v", err))
+	defer func() {
+		err = c.Disconnect()
+		assert.Nil(t, err, fmt.Sprintf("Error while disconnecting from OVS bridge: %v", err))
+		err = ofTestUtils.DeleteOVSBridge(br)
+		assert.Nil(t, err, fmt.Sprintf("Error while deleting OVS bridge: %v", err))
+	}()
+
+	config := prepareConfiguration()
+	_, podCIDR, _ := net.ParseCIDR("192.168.1.0/24")
+	_, err = c.Initialize(roundInfo, &config1.NodeConfig{PodCIDR: podCIDR}, config1.TrafficEncapModeEncap, config1.HostGatewayOFPort)
+	require.Nil(t, err, "Failed to initialize OpenFlow client")
+	testInstallGatewayFlows(t, config)
+	testInstallPodFlows(t, config)
+	require.Nil(t, c.InstallDNSInterceptFlows(), "Failed to install DNS intercept flows")
+
+	ofTestUtils.CheckFlowExists(t, ovsCtlClient, uint8(30), true, []*ofTestUtils.ExpectFlow{
+		{MatchStr: "priority=64991,udp,reg0=0/0x4000000,tp_src=53", ActStr: "ct(table=31,zone=65520)"},
+		{MatchStr: "priority=64991,tcp,reg0=0/0x4000000,tp_src=53", ActStr: "ct(table=31,zone=65520)"},
+	})
+	ofTestUtils.CheckFlowExists(t, ovsCtlClient, uint8(31), true, []*ofTestUtils.ExpectFlow{
+		{MatchStr: "priority=64990,udp,reg0=0/0x4000000,tp_src=53", ActStr: "load:0x1->NXM_NX_REG0[26],ct(table=31,zone=65520,nat)"},
+		{MatchStr: "priority=64990,tcp,reg0=0/0x4000000,tp_src=53", ActStr: "load:0x1->NXM_NX_REG0[26],ct(table=31,zone=65520,nat)"},
+	})
+
+	pod := config.localPods[0]
+	dnsResponse := fmt.Sprintf("in_port=%d,udp,dl_src=%s,dl_dst=%s,nw_src=172.16.0.10,nw_dst=%s,tp_src=53,tp_dst=34567",
+		config.localGateway.ofPort, config.localGateway.mac, pod.mac, pod.ip)
+	trace := func(flow string) string {
+		out, execErr := ovsCtlClient.RunAppctlCmd("ofproto/trace", true, fmt.Sprintf("'%s'", flow), "--ct-next", "'trk,est,rpl'")
+		require.Nil(t, execErr, "Failed to trace DNS response")
+		return string(out)
+	}
+	// The DNS response is sent to the controller.
+	out := trace(dnsResponse)
+	assert.Contains(t, out, "controller", "DNS response is not sent to the controller")
+	assert.NotContains(t, out, fmt.Sprintf("output port is %d", pod.ofPort), "DNS response is not held")
+	// The reinjected DNS response carries dnsResponseMark, and is delivered
+	// to the Pod after being looked up in conntrack with NAT.
+	out = trace(dnsResponse + ",reg0=0x4000000/0x4000000")
+	assert.Contains(t, out, "ct(table=31,zone=65520,nat)", "Reinjected DNS response is not looked up in conntrack with NAT")
+	assert.Contains(t, out, fmt.Sprintf("output port is %d", pod.ofPort), "Reinjected DNS response is not delivered")
+
+	require.Nil(t, c.UninstallDNSInterceptFlows(), "Failed to uninstall DNS intercept flows")
+}
+
+func installServiceFlows(t *testing.T, groupID ofconfig.GroupIDType, svc svcConfig, endpoints []k8sproxy.Endpoint, stickyMaxAgeSeconds uint16) {
+	err := c.InstallEndpointFlows(svc.protocol, endpoints)
+	assert.NoError(t, err, "Failed to install Endpoint flows")
+	err = c.InstallServiceGroup(groupID, stickyMaxAgeSeconds != 0, types.LBAlgorithmRandom, nil, endpoints)
+	assert.NoError(t, err, "Failed to install Service group")
+	err = c.InstallServiceFlows(groupID, svc.ip, svc.port, svc.protocol, stickyMaxAgeSeconds)
+	assert.NoError(t, err, "Failed to install Service flows")
+}
+
+func uninstallServiceFlowsFunc(t *testing.T, groupID ofconfig.GroupIDType, svc svcConfig, endpoints []k8sproxy.Endpoint) {
+	err := c.UninstallServiceFlows(svc.ip, svc.port, svc.protocol)
+	assert.NoError(t, err, "Failed to uninstall Service flows")
+	err = c.UninstallServiceGroup(groupID)
+	assert.NoError(t, err, "Failed to uninstall Service group")
+	for _, ep := range endpoints {
+		err := c.UninstallEndpointFlows(svc.protocol, ep)
+		assert.NoError(t, err, "Failed to uninstall Endpoint flows")
+	}
+}
+
+// expectedProxyServiceGroupAndFlows returns the expected flows and group buckets of a Service with session
+// affinity. The learned flows must match the IP protocol and the destination port field of the Service protocol.
+func expectedProxyServiceGroupAndFlows(groupID ofconfig.GroupIDType, svc svcConfig, endpoints []k8sproxy.Endpoint, stickyAge uint16) ([]expectTableFlows, []string) {
+	nwProto := 6
+	learnProtoField := "NXM_OF_TCP_DST[]"
+	if svc.protocol == ofconfig.ProtocolUDP {
+		nwProto = 17
+		learnProtoField = "NXM_OF_UDP_DST[]"
+	} else if svc.protocol == ofconfig.ProtocolSCTP {
+		nwProto = 132
+		learnProtoField = "OXM_OF_SCTP_DST[]"
+	}
+	cookieID := cookie.NewAllocator(roundInfo.RoundNum).RequestWithObjectID(cookie.Service, uint32(groupID)).Raw()
+	svcFlows := expectTableFlows{tableID: 41, flows: []*ofTestUtils.ExpectFlow{
+		{
+			MatchStr: fmt.Sprintf("priority=200,%s,reg4=0x10000/0x70000,nw_dst=%s,tp_dst=%d", svc.protocol, svc.ip, svc.port),
+			ActStr:   fmt.Sprintf("group:%d", groupID),
+		},
+		{
+			MatchStr: fmt.Sprintf("priority=190,%s,reg4=0x30000/0x70000,nw_dst=%s,tp_dst=%d", svc.protocol, svc.ip, svc.port),
+			ActStr: fmt.Sprintf("learn(table=40,hard_timeout=%d,priority=200,delete_learned,cookie=0x%x,eth_type=0x800,nw_proto=%d,%s,NXM_OF_IP_DST[],NXM_OF_IP_SRC[],"+
+				"load:NXM_NX_REG3[]->NXM_NX_REG3[],load:NXM_NX_REG4[0..15]->NXM_NX_REG4[0..15],load:0x2->NXM_NX_REG4[16..18],load:0x1->NXM_NX_REG0[19]),"+
+				"load:0x2->NXM_NX_REG4[16..18],goto_table:42", stickyAge, cookieID, nwProto, learnProtoField),
+		},
+	}}
+	epDNATFlows := expectTableFlows{tableID: 42, flows: []*ofTestUtils.ExpectFlow{}}
+	var groupBuckets []string
+	for _, ep := range endpoints {
+		epIP := binary.BigEndian.Uint32(net.ParseIP(ep.IP()).To4())
+		epPort, _ := ep.Port()
+		groupBuckets = append(groupBuckets, fmt.Sprintf("weight:100,actions=load:0x%x->NXM_NX_REG3[],load:0x%x->NXM_NX_REG4[0..15],load:0x3->NXM_NX_REG4[16..18],load:0x1->NXM_NX_REG0[19],resubmit(,41)", epIP, epPort))
+		unionVal := (0b010 << 16) + uint32(epPort)
+		epDNATFlows.flows = append(epDNATFlows.flows, &ofTestUtils.ExpectFlow{
+			MatchStr: fmt.Sprintf("priority=200,%s,reg3=0x%x,reg4=0x%x/0x7ffff", svc.protocol, epIP, unionVal),
+			ActStr:   fmt.Sprintf("ct(commit,table=50,zone=65520,nat(dst=%s:%d),exec(load:0x21->NXM_NX_CT_MARK[])", ep.IP(), epPort),
+		})
+	}
+	return []expectTableFlows{svcFlows, epDNATFlows}, groupBuckets
+}
+
 func testExternalFlows(t *testing.T, config *testConfig) {
 	nodeIP := net.ParseIP("10.10.10.1")
 	_, localSubnet, _ := net.ParseCIDR("172.16.1.0/24")
@@ -263,7 +427,10 @@ func testInstallServiceFlows(t *testing.T, config *testConfig) {
 
 func testInstallNodeFlows(t *testing.T, config *testConfig) {
 	for _, node := range config.peers {
-		err := c.InstallNodeFlows(node.name, config.localGateway.mac, node.subnet, node.gateway, node.nodeAddress, config.tunnelOFPort, 0)
+		peerConfig := map[*net.IPNet]net.IP{
+			&node.subnet: node.gateway,
+		}
+		err := c.InstallNodeFlows(node.name, config.localGateway.mac, peerConfig, node.nodeAddress, config.tunnelOFPort, 0)
 		if err != nil {
 			t.Fatalf("Failed to install Openflow entries for node connectivity: %v", err)
 		}
@@ -287,7 +454,7 @@ func testUninstallNodeFlows(t *testing.T, config *testConfig) {
 
 func testInstallPodFlows(t *testing.T, config *testConfig) {
 	for _, pod := range config.localPods {
-		err := c.InstallPodFlows(pod.name, pod.ip, pod.mac, config.localGateway.mac, pod.ofPort)
+		err := c.InstallPodFlows(pod.name, []net.IP{pod.ip}, pod.mac, config.localGateway.mac, pod.ofPort)
 		if err != nil {
 			t.Fatalf("Failed to install Openflow entries for pod: %v", err)
 		}
@@ -313,7 +480,7 @@ func TestNetworkPolicyFlows(t *testing.T) {
 	// Initialize ovs metrics (Prometheus) to test them
 	metrics.InitializeOVSMetrics()
 
-	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false)
+	c = ofClient.NewClient(br, bridgeMgmtAddr, true, false, false, false, false)
 	err := ofTestUtils.PrepareOVSBridge(br)
 	require.Nil(t, err, fmt.Sprintf("Failed to prepare OVS bridge %s", br))
 
@@ -574,7 +741,7 @@ func checkOVSFlowMetrics(t *testing.T, client ofClient.Client) {
 }
 
 func testInstallGatewayFlows(t *testing.T, config *testConfig) {
-	err := c.InstallGatewayFlows(config.localGateway.ip, config.localGateway.mac, config.localGateway.ofPort)
+	err := c.InstallGatewayFlows([]net.IP{config.localGateway.ip}, config.localGateway.mac, config.localGateway.ofPort)
 	if err != nil {
 		t.Fatalf("Failed to install Openflow entries for gateway: %v", err)
 	}
@@ -23,15 +23,18 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/containernetworking/plugins/pkg/ip"
 	"github.com/stretchr/testify/assert"
 	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/route"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/ipset"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 func ExecOutputTrim(cmd string) (string, error) {
@@ -101,7 +104,7 @@ func TestInitialize(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Logf("Running Initialize test with mode %s node config %s", tc.mode, nodeConfig)
-		routeClient, err := route.NewClient(serviceCIDR, tc.mode)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, false)
 		if err != nil {
 			t.Error(err)
 		}
@@ -148,6 +151,111 @@ func TestInitialize(t *testing.T) {
 	}
 }
 
+func TestInitializeProxyHostNetwork(t *testing.T) {
+	if _, incontainer := os.LookupEnv("INCONTAINER"); !incontainer {
+		// test changes file system, routing table. Run in contain only
+		t.Skipf("Skip test runs only in container")
+	}
+
+	link := createDummyGW(t)
+	defer netlink.LinkDel(link)
+	// The gateway IP is used as the preferred source IP of the route to the Service CIDR.
+	if err := netlink.AddrAdd(link, &netlink.Addr{IPNet: &net.IPNet{IP: gwIP, Mask: podCIDR.Mask}}); err != nil {
+		t.Error(err)
+	}
+
+	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeEncap, false, true, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if err := routeClient.Initialize(nodeConfig); err != nil {
+		t.Error(err)
+	}
+
+	// verify the routes and the neighbor of the virtual Service IP
+	expRoutes := fmt.Sprintf("%s via %s dev %s src %s", serviceCIDR, config.VirtualServiceIP, gwName, gwIP)
+	output, err := ExecOutputTrim(fmt.Sprintf("ip route show %s", serviceCIDR))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join(strings.Fields(expRoutes), ""), output)
+	expRoutes = fmt.Sprintf("%s dev %s scope link", config.VirtualServiceIP, gwName)
+	output, err = ExecOutputTrim(fmt.Sprintf("ip route show %s", config.VirtualServiceIP))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join(strings.Fields(expRoutes), ""), output)
+	output, err = ExecOutputTrim(fmt.Sprintf("ip neigh show %s dev %s", config.VirtualServiceIP, gwName))
+	assert.NoError(t, err)
+	assert.Contains(t, output, "aa:bb:cc:dd:ee:ffPERMANENT")
+
+	// verify the masquerade rule of the ClusterIP traffic from the host network
+	// #nosec G204: ignore in test code
+	actualData, err := exec.Command("bash", "-c", "iptables-save -t nat | grep -i antrea-postrouting").Output()
+	assert.NoError(t, err, "error executing iptables-save")
+	assert.Contains(t, string(actualData), fmt.Sprintf(`-A ANTREA-POSTROUTING -d %s -o antrea-gw0 -m comment --comment "Antrea: masquerade host network to ClusterIP packets" -j MASQUERADE`, serviceCIDR))
+
+	// verify the route to the Service CIDR is not removed by Reconcile
+	assert.NoError(t, routeClient.Reconcile([]string{podCIDR.String()}))
+	output, err = ExecOutputTrim(fmt.Sprintf("ip route show %s", serviceCIDR))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, output)
+}
+
+func TestNodePortIPsSync(t *testing.T) {
+	if _, incontainer := os.LookupEnv("INCONTAINER"); !incontainer {
+		// test changes file system, routing table. Run in contain only
+		t.Skipf("Skip test runs only in container")
+	}
+
+	gwLink := createDummyGW(t)
+	defer netlink.LinkDel(gwLink)
+	nodePortLink := &netlink.Dummy{}
+	nodePortLink.Name = "antrea-test-np"
+	if err := netlink.LinkAdd(nodePortLink); err != nil {
+		t.Fatal(err)
+	}
+	defer netlink.LinkDel(nodePortLink)
+
+	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeEncap, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := routeClient.Initialize(nodeConfig); err != nil {
+		t.Fatal(err)
+	}
+	if err := routeClient.AddNodePort(30001, binding.ProtocolTCP, false); err != nil {
+		t.Fatal(err)
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go routeClient.Run(stopCh)
+
+	nodePortIPSetHasEntry := func(entry string) (bool, error) {
+		entries, err := ipset.ListEntries("ANTREA-NODEPORT-IP")
+		if err != nil {
+			return false, err
+		}
+		for _, e := range entries {
+			if e == entry {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	entry := "192.168.77.10,tcp:30001"
+	addr := &netlink.Addr{IPNet: &net.IPNet{IP: net.ParseIP("192.168.77.10"), Mask: net.CIDRMask(24, 32)}}
+
+	// The NodePort should be added on the new address.
+	assert.NoError(t, netlink.AddrAdd(nodePortLink, addr))
+	assert.NoError(t, wait.PollImmediate(100*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return nodePortIPSetHasEntry(entry)
+	}), "NodePort was not added on the new address")
+
+	// The NodePort should be deleted on the deleted address.
+	assert.NoError(t, netlink.AddrDel(nodePortLink, addr))
+	assert.NoError(t, wait.PollImmediate(100*time.Millisecond, 5*time.Second, func() (bool, error) {
+		exists, err := nodePortIPSetHasEntry(entry)
+		return !exists, err
+	}), "NodePort was not deleted on the deleted address")
+}
+
 func TestAddAndDeleteRoutes(t *testing.T) {
 	if _, incontainer := os.LookupEnv("INCONTAINER"); !incontainer {
 		// test changes file system, routing table. Run in contain only
@@ -174,7 +282,7 @@ func TestAddAndDeleteRoutes(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Logf("Running test with mode %s peer cidr %s peer ip %s node config %s", tc.mode, tc.peerCIDR, tc.peerIP, nodeConfig)
-		routeClient, err := route.NewClient(serviceCIDR, tc.mode)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, false)
 		if err != nil {
 			t.Error(err)
 		}
@@ -278,7 +386,7 @@ func TestReconcile(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Logf("Running test with mode %s added routes %v desired routes %v", tc.mode, tc.addedRoutes, tc.desiredPeerCIDRs)
-		routeClient, err := route.NewClient(serviceCIDR, tc.mode)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, false)
 		if err != nil {
 			t.Error(err)
 		}
@@ -326,7 +434,7 @@ func TestRouteTablePolicyOnly(t *testing.T) {
 	gwLink := createDummyGW(t)
 	defer netlink.LinkDel(gwLink)
 
-	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeNetworkPolicyOnly)
+	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeNetworkPolicyOnly, false, false, false)
 	if err != nil {
 		t.Error(err)
 	}
@@ -36,6 +36,7 @@ Modifies:
 - Remove functions: "newBaseEndpointInfo", "makeEndpointFunc",
   "NewEndpointChangeTracker", "detectStaleConnections"
 - Remove structs: "EndpointChangeTracker", "EndpointsMap"
+- Add field "Ready" and function "IsReady" to struct "BaseEndpointInfo"
 */
 package proxy
 
@@ -52,6 +53,8 @@ type BaseEndpointInfo struct {
 	// IsLocal indicates whether the endpoint is running in same host as kube-proxy.
 	IsLocal  bool
 	Topology map[string]string
+	// Ready indicates whether the endpoint is ready to serve new connections.
+	Ready bool
 }
 
 var _ Endpoint = &BaseEndpointInfo{}
@@ -71,6 +74,11 @@ func (info *BaseEndpointInfo) GetTopology() map[string]string {
 	return info.Topology
 }
 
+// IsReady is part of proxy.Endpoint interface.
+func (info *BaseEndpointInfo) IsReady() bool {
+	return info.Ready
+}
+
 // IP returns just the IP part of the endpoint, it's a part of proxy.Endpoint interface.
 func (info *BaseEndpointInfo) IP() string {
 	return utilproxy.IPPart(info.Endpoint)
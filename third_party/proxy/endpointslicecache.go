@@ -0,0 +1,268 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+/*
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+Modifies:
+- Remove import "k8s.io/kubernetes/pkg/proxy/util"
+- Replace type "EndpointsMap" with "map[ServicePortName]map[string]Endpoint", which
+  is the type of EndpointsMap used by Antrea
+- Add "MakeEndpointFunc" to replace "makeEndpointFunc" of the removed
+  "endpoints.go" content
+- Cache the Ready condition of the endpoints instead of skipping the ones
+  which are not ready
+- Add "isIPv6Mode" to "NewEndpointSliceCache" and skip the EndpointSlices
+  whose address type is not supported or doesn't match the IP family in
+  "Update"
+*/
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// supportedEndpointSliceAddressTypes are the address types of the
+// EndpointSlices which can be handled by the proxier. The EndpointSlices of
+// FQDN address type are skipped.
+var supportedEndpointSliceAddressTypes = sets.NewString(
+	string(discovery.AddressTypeIPv4),
+	string(discovery.AddressTypeIPv6),
+)
+
+// MakeEndpointFunc abstracts the creation of an Endpoint by the proxier from
+// the BaseEndpointInfo.
+type MakeEndpointFunc func(info *BaseEndpointInfo) Endpoint
+
+// EndpointSliceCache is used as a cache of EndpointSlice information.
+type EndpointSliceCache struct {
+	// sliceByServiceMap is the basis of this cache. It contains endpoint slice
+	// info grouped by service name and endpoint slice name. The first key
+	// represents a namespaced service name while the second key represents
+	// an endpoint slice name. Since endpoints can move between slices, we
+	// require slice specific caching to prevent endpoints being removed from
+	// the cache when they may have just moved to a different slice.
+	sliceByServiceMap map[types.NamespacedName]map[string]*endpointSliceInfo
+	makeEndpointInfo  MakeEndpointFunc
+	hostname          string
+	// isIPv6Mode indicates if the cache is under IPv6/IPv4 mode. Nil means
+	// not applicable.
+	isIPv6Mode *bool
+}
+
+// endpointSliceInfo contains just the attributes kube-proxy cares about.
+// Used for caching. Intentionally small to limit memory util.
+type endpointSliceInfo struct {
+	Ports     []discovery.EndpointPort
+	Endpoints []*endpointInfo
+}
+
+// endpointInfo contains just the attributes kube-proxy cares about.
+// Used for caching. Intentionally small to limit memory util.
+// Addresses and Topology are copied from EndpointSlice Endpoints.
+type endpointInfo struct {
+	Addresses []string
+	Topology  map[string]string
+	Ready     bool
+}
+
+// spToEndpointMap stores groups Endpoint objects by ServicePortName and
+// EndpointSlice name.
+type spToEndpointMap map[ServicePortName]map[string]Endpoint
+
+// NewEndpointSliceCache initializes an EndpointSliceCache.
+func NewEndpointSliceCache(hostname string, isIPv6Mode *bool, makeEndpointInfo MakeEndpointFunc) *EndpointSliceCache {
+	if makeEndpointInfo == nil {
+		makeEndpointInfo = standardEndpointInfo
+	}
+	return &EndpointSliceCache{
+		sliceByServiceMap: map[types.NamespacedName]map[string]*endpointSliceInfo{},
+		hostname:          hostname,
+		isIPv6Mode:        isIPv6Mode,
+		makeEndpointInfo:  makeEndpointInfo,
+	}
+}
+
+// standardEndpointInfo is the default makeEndpointFunc.
+func standardEndpointInfo(ep *BaseEndpointInfo) Endpoint {
+	return ep
+}
+
+// Update a slice in the cache.
+func (cache *EndpointSliceCache) Update(endpointSlice *discovery.EndpointSlice) {
+	if !supportedEndpointSliceAddressTypes.Has(string(endpointSlice.AddressType)) {
+		klog.V(4).Infof("EndpointSlice address type not supported: %s", endpointSlice.AddressType)
+		return
+	}
+	if cache.isIPv6Mode != nil && (endpointSlice.AddressType == discovery.AddressTypeIPv6) != *cache.isIPv6Mode {
+		klog.V(4).Infof("EndpointSlice address type %s doesn't match the IP family of the proxier", endpointSlice.AddressType)
+		return
+	}
+	serviceKey, sliceKey, err := endpointSliceCacheKeys(endpointSlice)
+	if err != nil {
+		klog.Warningf("Error getting endpoint slice cache keys: %v", err)
+		return
+	}
+
+	esInfo := &endpointSliceInfo{
+		Ports:     endpointSlice.Ports,
+		Endpoints: []*endpointInfo{},
+	}
+	for _, endpoint := range endpointSlice.Endpoints {
+		esInfo.Endpoints = append(esInfo.Endpoints, &endpointInfo{
+			Addresses: endpoint.Addresses,
+			Topology:  endpoint.Topology,
+			// A nil Ready condition indicates an unknown state, which should be
+			// interpreted as ready.
+			Ready: endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready,
+		})
+	}
+	if _, exists := cache.sliceByServiceMap[serviceKey]; !exists {
+		cache.sliceByServiceMap[serviceKey] = map[string]*endpointSliceInfo{}
+	}
+	cache.sliceByServiceMap[serviceKey][sliceKey] = esInfo
+}
+
+// Delete a slice from the cache.
+func (cache *EndpointSliceCache) Delete(endpointSlice *discovery.EndpointSlice) {
+	serviceKey, sliceKey, err := endpointSliceCacheKeys(endpointSlice)
+	if err != nil {
+		klog.Warningf("Error getting endpoint slice cache keys: %v", err)
+		return
+	}
+	delete(cache.sliceByServiceMap[serviceKey], sliceKey)
+	if len(cache.sliceByServiceMap[serviceKey]) == 0 {
+		delete(cache.sliceByServiceMap, serviceKey)
+	}
+}
+
+// EndpointsMap computes an EndpointsMap for a given service.
+func (cache *EndpointSliceCache) EndpointsMap(serviceNN types.NamespacedName) map[ServicePortName]map[string]Endpoint {
+	endpointInfoBySP := cache.endpointInfoByServicePort(serviceNN)
+	if len(endpointInfoBySP) == 0 {
+		return nil
+	}
+	return endpointInfoBySP
+}
+
+// endpointInfoByServicePort groups endpoint info by service port name and address.
+func (cache *EndpointSliceCache) endpointInfoByServicePort(serviceNN types.NamespacedName) spToEndpointMap {
+	endpointInfoBySP := spToEndpointMap{}
+	sliceInfoByName, ok := cache.sliceByServiceMap[serviceNN]
+
+	if !ok {
+		return endpointInfoBySP
+	}
+
+	for _, sliceInfo := range sliceInfoByName {
+		for _, port := range sliceInfo.Ports {
+			if port.Name == nil {
+				klog.Warningf("ignoring port with nil name %v", port)
+				continue
+			}
+			// TODO: handle nil ports to mean "all"
+			if port.Port == nil || *port.Port == int32(0) {
+				klog.Warningf("ignoring invalid endpoint port %s", *port.Name)
+				continue
+			}
+
+			svcPortName := ServicePortName{
+				NamespacedName: serviceNN,
+				Port:           *port.Name,
+				Protocol:       *port.Protocol,
+			}
+
+			endpointInfoBySP[svcPortName] = cache.addEndpointsByIP(serviceNN, int(*port.Port), endpointInfoBySP[svcPortName], sliceInfo.Endpoints)
+		}
+	}
+
+	return endpointInfoBySP
+}
+
+// addEndpointsByIP adds endpointInfo for each IP.
+func (cache *EndpointSliceCache) addEndpointsByIP(serviceNN types.NamespacedName, portNum int, endpointsByIP map[string]Endpoint, endpoints []*endpointInfo) map[string]Endpoint {
+	if endpointsByIP == nil {
+		endpointsByIP = map[string]Endpoint{}
+	}
+
+	// iterate through endpoints to add them to endpointsByIP.
+	for _, endpoint := range endpoints {
+		if len(endpoint.Addresses) == 0 {
+			klog.Warningf("ignoring invalid endpoint port %s with empty addresses", endpoint)
+			continue
+		}
+
+		isLocal := cache.isLocal(endpoint.Topology[v1.LabelHostname])
+		endpointInfo := cache.makeEndpointInfo(&BaseEndpointInfo{
+			Endpoint: net.JoinHostPort(endpoint.Addresses[0], strconv.Itoa(portNum)),
+			IsLocal:  isLocal,
+			Topology: endpoint.Topology,
+			Ready:    endpoint.Ready,
+		})
+
+		// This logic ensures we're deduping potential overlapping endpoints
+		// isLocal should not vary between matching IPs, but if it does, we
+		// favor a true value here if it exists.
+		if _, exists := endpointsByIP[endpointInfo.String()]; !exists || isLocal {
+			endpointsByIP[endpointInfo.String()] = endpointInfo
+		}
+	}
+
+	return endpointsByIP
+}
+
+func (cache *EndpointSliceCache) isLocal(hostname string) bool {
+	return len(cache.hostname) > 0 && hostname == cache.hostname
+}
+
+// endpointSliceCacheKeys returns cache keys used for a given EndpointSlice.
+func endpointSliceCacheKeys(endpointSlice *discovery.EndpointSlice) (types.NamespacedName, string, error) {
+	var err error
+	serviceName, ok := endpointSlice.Labels[discovery.LabelServiceName]
+	if !ok || serviceName == "" {
+		err = fmt.Errorf("No %s label set on endpoint slice: %s", discovery.LabelServiceName, endpointSlice.Name)
+	} else if endpointSlice.Namespace == "" || endpointSlice.Name == "" {
+		err = fmt.Errorf("Expected EndpointSlice name and namespace to be set: %v", endpointSlice)
+	}
+	return types.NamespacedName{Namespace: endpointSlice.Namespace, Name: serviceName}, endpointSlice.Name, err
+}
+
+// EndpointSliceServiceKey returns the namespaced name of the Service which the
+// EndpointSlice belongs to.
+func EndpointSliceServiceKey(endpointSlice *discovery.EndpointSlice) (types.NamespacedName, error) {
+	serviceKey, _, err := endpointSliceCacheKeys(endpointSlice)
+	return serviceKey, err
+}
@@ -31,6 +31,7 @@ limitations under the License.
 Modifies:
 - Remove interface "Provider"
 - Remove import "k8s.io/kubernetes/pkg/proxy/config"
+- Add function "IsReady" to interface "Endpoint"
 */
 
 package proxy
@@ -95,6 +96,9 @@ type Endpoint interface {
 	GetIsLocal() bool
 	// GetTopology returns the topology information of the endpoint.
 	GetTopology() map[string]string
+	// IsReady returns true if the endpoint is ready to serve new connections.
+	// The endpoints which are not ready may still serve the existing connections.
+	IsReady() bool
 	// IP returns IP part of the endpoint.
 	IP() string
 	// Port returns the Port part of the endpoint.